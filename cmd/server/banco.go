@@ -0,0 +1,95 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/cmd/server/banco.go
+/// Responsabilidade: Único ponto de abertura dos pools de banco (primário e réplica de leitura) a partir de backend/internal/config; usado por todos os subcomandos via carregarAmbiente (cli.go).
+/// Dependências principais: database/sql, driver Postgres (via model.DriverSQL), backend/internal/config, backend/internal/logging.
+/// Pontos de atenção:
+/// - Nenhum outro arquivo abre conexões: credenciais vêm só de DATABASE_URL/DATABASE_URL_RO (nada fixo no código).
+/// - statement_timeout vai como parâmetro de sessão no DSN (DB_STATEMENT_TIMEOUT); migrations usam só MIGRATE_TIMEOUT.
+/// - Primário fora do ar encerra o processo; réplica fora do ar só gera aviso.
+/// - O pool é o do database/sql sobre pgx/v5/stdlib (não pgxpool): handlers e repositórios dependem de *sql.DB.
+///   Limites em configurarPool (DB_MAX_*, DB_CONN_*), iguais para primário e réplica.
+*/
+
+package main
+
+import (
+	"database/sql"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/internal/config"
+	"backend/internal/logging"
+	"backend/internal/model"
+)
+
+/// ============ Banco de Dados ============
+
+// conectarBanco inicializa conexão com Postgres a partir de cfg (DATABASE_URL, DB_*).
+// Efeitos colaterais: abre pool, faz ping de verificação e configura pool.
+// Falhas: logging.Fatal em erros críticos (encerra o processo).
+func conectarBanco(cfg *config.Config) *sql.DB {
+	db, err := sql.Open(model.DriverSQL, comStatementTimeout(cfg.DatabaseURL, cfg.DB.StatementTimeout))
+	if err != nil {
+		logging.Fatal("abrir conexão com o banco", "erro", err)
+	}
+	configurarPool(db, cfg.DB)
+	if err = db.Ping(); err != nil {
+		logging.Fatal("não foi possível conectar ao banco", "erro", err)
+	}
+	slog.Info("conectado ao banco de dados")
+	return db
+}
+
+// configurarPool aplica os limites do pool. Conexões ociosas são fechadas após
+// ConnMaxIdleTime, então vale MaxIdleConns alto: no pico elas ficam, fora dele somem.
+func configurarPool(db *sql.DB, c config.DB) {
+	db.SetMaxOpenConns(c.MaxOpenConns)
+	db.SetMaxIdleConns(c.MaxIdleConns)
+	db.SetConnMaxLifetime(c.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(c.ConnMaxIdleTime)
+}
+
+// comStatementTimeout acrescenta statement_timeout (ms) como parâmetro de sessão da
+// conexão (o pgx repassa parâmetros desconhecidos ao Postgres na abertura), salvo
+// se o DSN já definir um. Vale para URL (postgres://...) e para o formato chave=valor.
+func comStatementTimeout(dsn string, d time.Duration) string {
+	if d <= 0 || strings.Contains(dsn, "statement_timeout") {
+		return dsn
+	}
+	ms := strconv.FormatInt(d.Milliseconds(), 10)
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		u, err := url.Parse(dsn)
+		if err != nil {
+			return dsn // sql.Open/Ping acusam o DSN inválido
+		}
+		q := u.Query()
+		q.Set("statement_timeout", ms)
+		u.RawQuery = q.Encode()
+		return u.String()
+	}
+	return dsn + " statement_timeout=" + ms
+}
+
+// conectarReplica abre o pool da réplica de leitura (DATABASE_URL_RO, mesmos DB_*).
+// Sem a variável devolve nil. Réplica fora do ar na subida só gera aviso: as leituras
+// começam no primário e voltam à réplica quando ela responder (model.Replica).
+func conectarReplica(cfg *config.Config) *sql.DB {
+	if cfg.DatabaseURLRO == "" {
+		return nil
+	}
+	ro, err := sql.Open(model.DriverSQL, comStatementTimeout(cfg.DatabaseURLRO, cfg.DB.StatementTimeout))
+	if err != nil {
+		logging.Fatal("abrir conexão com a réplica", "erro", err)
+	}
+	configurarPool(ro, cfg.DB)
+	if err := ro.Ping(); err != nil {
+		slog.Warn("réplica de leitura indisponível na subida", "erro", err)
+	} else {
+		slog.Info("conectado à réplica de leitura")
+	}
+	return ro
+}
@@ -0,0 +1,433 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/cmd/server/cli.go
+/// Responsabilidade: CLI do binário (serve, migrate, seed, createuser, cifrar-pii, config) sobre o mesmo carregamento de configuração (.env + backend/internal/config, banco, criptografia de PII).
+/// Dependências principais: flag, log/slog, database/sql (Postgres), bcrypt, pacotes locais (config, cripto, logging, migrations, model).
+/// Pontos de atenção:
+/// - Sem subcomando, o binário roda `serve` (compatível com `go run ./cmd/server`).
+/// - Todos os subcomandos, exceto `migrate`, aplicam as migrations pendentes antes (respeitando MIGRATE_ON_START=false).
+/// - `createuser --admin` cria também a organização com o usuário como dono/admin; sem --senha, uma senha aleatória é gerada e impressa uma única vez.
+/// - `createuser --suporte` marca a conta como equipe de suporte (rotas /api/admin); a API não concede esse papel.
+/// - `seed --demo` é idempotente: se a conta demo já existir, nada é alterado.
+/// - `seed --estudantes N` acrescenta N estudantes fictícios (model.SemearEstudantes) à conta de --email (padrão: a conta demo, que precisa existir); pode ser combinado com --demo.
+*/
+
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/mail"
+	"os"
+	"strings"
+	"time"
+
+	"backend/internal/config"
+	"backend/internal/cripto"
+	"backend/internal/logging"
+	"backend/internal/migrations"
+	"backend/internal/model"
+
+	"github.com/joho/godotenv"
+	"golang.org/x/crypto/bcrypt"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// ambiente reúne as dependências compartilhadas pelos subcomandos.
+type ambiente struct {
+	cfg *config.Config
+	db  *sql.DB
+	pii *cripto.Cifrador
+}
+
+// comando é um subcomando da CLI.
+type comando struct {
+	uso        string
+	executar   func(amb *ambiente, args []string)
+	migrations bool // aplica migrations pendentes antes de executar
+}
+
+/// ============ Configurações & Constantes ============
+
+// conta criada por `seed --demo`
+const (
+	emailDemo = "demo@tecmise.local"
+	senhaDemo = "demo12345"
+)
+
+var comandos = map[string]comando{
+	"serve":      {uso: "sobe a API HTTP (padrão)", executar: servir, migrations: true},
+	"migrate":    {uso: "aplica as migrations pendentes; `migrate status` lista a situação", executar: executarMigrate},
+	"seed":       {uso: "--demo: cria a conta " + emailDemo + " com anos e estudantes de exemplo; --estudantes N [--email E]: gera N estudantes fictícios", executar: executarSeed, migrations: true},
+	"createuser": {uso: "--email E [--nome N] [--senha S] [--admin [--org NOME]] [--suporte]: cria um usuário", executar: executarCreateUser, migrations: true},
+	"cifrar-pii": {uso: "cifra/re-cifra CPF e telefone de estudantes e responsáveis existentes", executar: executarCifrarPII, migrations: true},
+}
+
+// comandos que não precisam de banco nem de configuração válida
+var comandosSemAmbiente = map[string]func(args []string){
+	"config": executarConfig,
+}
+
+/// ============ Funções Públicas ============
+
+// executarComando despacha args (sem o nome do binário) para o subcomando.
+func executarComando(args []string) {
+	nome := "serve"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		nome, args = args[0], args[1:]
+	}
+	if nome == "help" || nome == "-h" || nome == "--help" {
+		imprimirAjuda()
+		return
+	}
+	// Logger provisório até a configuração ser lida (LOG_LEVEL/LOG_FORMAT).
+	_ = logging.Configurar(os.Stderr, "info", "json")
+	if f, ok := comandosSemAmbiente[nome]; ok {
+		carregarDotenv()
+		f(args)
+		return
+	}
+	cmd, ok := comandos[nome]
+	if !ok {
+		imprimirAjuda()
+		logging.Fatal("comando desconhecido", "comando", nome)
+	}
+
+	amb := carregarAmbiente()
+	defer func() { _ = amb.db.Close() }()
+
+	// Na subida, aplica migrations pendentes (desligue com MIGRATE_ON_START=false
+	// quando o deploy rodar `migrate` separadamente).
+	if cmd.migrations && amb.cfg.Migrate.OnStart {
+		aplicarMigrations(amb)
+	}
+	cmd.executar(amb, args)
+}
+
+/// ============ Funções Internas (helpers) ============
+
+// carregarDotenv lê o .env (quando existir) para o ambiente do processo.
+func carregarDotenv() {
+	if err := godotenv.Load(".env"); err != nil {
+		slog.Debug("(.env) não encontrado; seguindo com variáveis do ambiente")
+	}
+}
+
+// carregarAmbiente lê o .env, valida a configuração (falha com o relatório
+// completo), conecta ao banco e configura a criptografia de PII.
+func carregarAmbiente() *ambiente {
+	carregarDotenv()
+	cfg, err := config.Carregar()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := logging.Configurar(os.Stderr, cfg.Log.Nivel, cfg.Log.Formato); err != nil {
+		logging.Fatal("configurar logs", "erro", err)
+	}
+	model.ConfigurarEmail(cfg.GmailCanonico)
+	model.ConfigurarDominiosBloqueados(cfg.BloquearDescartaveis, cfg.DominiosBloqueados)
+	db := conectarBanco(cfg)
+
+	pii, err := cripto.NewFromConfig(cfg.PII)
+	if err != nil {
+		logging.Fatal("configurar criptografia de dados pessoais", "erro", err)
+	}
+	if !pii.Ativo() {
+		slog.Warn("PII_KEY não definida; CPF/telefone serão gravados sem criptografia")
+	}
+	return &ambiente{cfg: cfg, db: db, pii: pii}
+}
+
+// imprimirAjuda lista os subcomandos disponíveis.
+func imprimirAjuda() {
+	fmt.Fprintln(os.Stderr, "uso: backend [comando] [opções]")
+	for _, nome := range []string{"serve", "migrate", "seed", "createuser", "cifrar-pii"} {
+		fmt.Fprintf(os.Stderr, "  %-11s %s\n", nome, comandos[nome].uso)
+	}
+	fmt.Fprintf(os.Stderr, "  %-11s %s\n", "config", "documenta as variáveis de ambiente e valida a configuração atual")
+}
+
+// executarConfig imprime a documentação das variáveis e os valores efetivos;
+// sai com erro (e o relatório de problemas) se a configuração for inválida.
+func executarConfig(_ []string) {
+	fmt.Println("Variáveis de ambiente (nome, padrão, descrição):")
+	fmt.Println()
+	fmt.Print(config.Documentacao())
+	fmt.Println()
+	fmt.Println("Valores efetivos:")
+	fmt.Println()
+	fmt.Print(config.Resumo())
+	if _, err := config.Carregar(); err != nil {
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Println()
+	fmt.Println("configuração válida")
+}
+
+// aplicarMigrations executa as migrations pendentes; falha encerra o processo.
+func aplicarMigrations(amb *ambiente) {
+	ctx, cancel := context.WithTimeout(context.Background(), amb.cfg.Migrate.Timeout)
+	defer cancel()
+	feitas, err := migrations.Aplicar(ctx, amb.db)
+	for _, m := range feitas {
+		slog.Info("migration aplicada", "migration", m.Nome)
+	}
+	if err != nil {
+		logging.Fatal("aplicar migrations", "erro", err)
+	}
+}
+
+// executarMigrate trata o subcomando `migrate`: sem argumentos aplica as
+// pendentes; com "status" lista cada migration e se já foi aplicada.
+func executarMigrate(amb *ambiente, args []string) {
+	if len(args) == 0 {
+		aplicarMigrations(amb)
+		slog.Info("migrate: banco atualizado")
+		return
+	}
+	if args[0] != "status" {
+		logging.Fatal("migrate: argumento desconhecido (use: migrate [status])", "argumento", args[0])
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	lista, err := migrations.Situacao(ctx, amb.db)
+	if err != nil {
+		logging.Fatal("migrate status", "erro", err)
+	}
+	for _, m := range lista {
+		estado := "pendente"
+		if m.Aplicada {
+			estado = "aplicada"
+		}
+		fmt.Printf("%04d  %-9s %s\n", m.Versao, estado, m.Nome)
+	}
+}
+
+// executarCifrarPII cifra/re-cifra CPF e telefone dos registros existentes.
+func executarCifrarPII(amb *ambiente, _ []string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+	repo := model.NewEstudanteRepo(amb.db, amb.pii)
+	n, err := repo.CifrarExistentes(ctx)
+	if err != nil {
+		logging.Fatal("cifrar-pii", "erro", err, "atualizados", n)
+	}
+	nr, err := repo.CifrarResponsaveisExistentes(ctx)
+	if err != nil {
+		logging.Fatal("cifrar-pii: responsáveis", "erro", err, "estudantes", n, "responsaveis", nr)
+	}
+	slog.Info("cifrar-pii: concluído", "estudantes", n, "responsaveis", nr)
+}
+
+// executarCreateUser cria um usuário com senha (e, com --admin, a organização dele).
+func executarCreateUser(amb *ambiente, args []string) {
+	fs := flag.NewFlagSet("createuser", flag.ExitOnError)
+	email := fs.String("email", "", "e-mail de login (obrigatório)")
+	nome := fs.String("nome", "", "nome exibido (padrão: parte local do e-mail)")
+	senha := fs.String("senha", "", "senha (mínimo 8 caracteres); vazio gera uma aleatória")
+	admin := fs.Bool("admin", false, "cria uma organização com o usuário como dono/admin")
+	org := fs.String("org", "", "nome da organização criada com --admin (padrão: nome do usuário)")
+	suporte := fs.Bool("suporte", false, "conta da equipe de suporte (acesso a /api/admin)")
+	_ = fs.Parse(args)
+
+	*email = model.NormalizarEmail(*email)
+	if _, err := mail.ParseAddress(*email); err != nil {
+		logging.Fatal("createuser: --email inválido ou ausente")
+	}
+	if strings.TrimSpace(*nome) == "" {
+		*nome, _, _ = strings.Cut(*email, "@")
+	}
+	gerada := false
+	if *senha == "" {
+		*senha, gerada = senhaAleatoria(), true
+	}
+	if len(*senha) < 8 || strings.Contains(*senha, " ") {
+		logging.Fatal("createuser: senha muito curta (mínimo 8 caracteres e sem espaços)")
+	}
+	if *admin && strings.TrimSpace(*org) == "" {
+		*org = *nome
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	var uid int
+	err := model.ComTransacao(ctx, amb.db, func(tx *sql.Tx) error {
+		var err error
+		if uid, err = criarUsuario(ctx, tx, *nome, *email, *senha); err != nil {
+			return err
+		}
+		if *suporte {
+			if _, err := tx.ExecContext(ctx, `UPDATE usuarios SET suporte=TRUE WHERE id=$1`, uid); err != nil {
+				return fmt.Errorf("marcar suporte: %w", err)
+			}
+		}
+		if *admin {
+			return criarOrganizacao(ctx, tx, uid, strings.TrimSpace(*org))
+		}
+		return nil
+	})
+	if err != nil {
+		logging.Fatal("createuser", "erro", err)
+	}
+
+	slog.Info("createuser: usuário criado", "usuario_id", uid, "email", *email, "suporte", *suporte)
+	if *admin {
+		slog.Info("createuser: organização criada", "organizacao", *org, "admin", *email)
+	}
+	if gerada {
+		fmt.Printf("senha gerada: %s\n", *senha)
+	}
+}
+
+// executarSeed popula o banco com dados de demonstração (--demo) e/ou estudantes
+// fictícios em quantidade (--estudantes N).
+func executarSeed(amb *ambiente, args []string) {
+	fs := flag.NewFlagSet("seed", flag.ExitOnError)
+	demo := fs.Bool("demo", false, "cria a conta demo com anos e estudantes de exemplo")
+	estudantes := fs.Int("estudantes", 0, fmt.Sprintf("gera N estudantes fictícios (1 a %d)", model.MaxEstudantesSeed))
+	email := fs.String("email", emailDemo, "conta que recebe os estudantes de --estudantes")
+	_ = fs.Parse(args)
+	if !*demo && *estudantes == 0 {
+		logging.Fatal("seed: informe o conjunto de dados (ex.: seed --demo ou seed --estudantes 500)")
+	}
+	if *demo {
+		seedDemo(amb)
+	}
+	if *estudantes != 0 {
+		seedEstudantes(amb, model.NormalizarEmail(*email), *estudantes)
+	}
+}
+
+// seedEstudantes gera n estudantes fictícios no tenant da conta email.
+func seedEstudantes(amb *ambiente, email string, n int) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	acesso, err := model.ResolverAcesso(ctx, amb.db, email)
+	if errors.Is(err, sql.ErrNoRows) {
+		logging.Fatal("seed: conta não encontrada (crie com seed --demo ou createuser)", "email", email)
+	}
+	if err != nil {
+		logging.Fatal("seed", "erro", err)
+	}
+	criados, err := model.SemearEstudantes(ctx, amb.db, model.NewEstudanteRepo(amb.db, amb.pii), acesso.TenantID, n)
+	if err != nil {
+		logging.Fatal("seed: gerar estudantes", "erro", err, "criados", criados)
+	}
+	slog.Info("seed: estudantes fictícios criados", "email", email, "solicitados", n, "criados", criados)
+}
+
+// seedDemo cria a conta demo (idempotente: se já existir, nada muda).
+func seedDemo(amb *ambiente) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	var existe bool
+	if err := amb.db.QueryRowContext(ctx,
+		`SELECT EXISTS(SELECT 1 FROM usuarios WHERE email = $1)`, emailDemo,
+	).Scan(&existe); err != nil {
+		logging.Fatal("seed", "erro", err)
+	}
+	if existe {
+		slog.Info("seed: conta demo já existe; nada a fazer", "email", emailDemo)
+		return
+	}
+
+	var uid int
+	anos := map[string]int{}
+	err := model.ComTransacao(ctx, amb.db, func(tx *sql.Tx) error {
+		var err error
+		if uid, err = criarUsuario(ctx, tx, "Conta Demo", emailDemo, senhaDemo); err != nil {
+			return err
+		}
+		for i, nome := range []string{"1º Ano", "2º Ano", "3º Ano"} {
+			var id int
+			if err := tx.QueryRowContext(ctx,
+				`INSERT INTO anos (nome, usuario_id, ordem) VALUES ($1, $2, $3) RETURNING id`,
+				nome, uid, i,
+			).Scan(&id); err != nil {
+				return fmt.Errorf("criar ano %q: %w", nome, err)
+			}
+			anos[nome] = id
+		}
+		return nil
+	})
+	if err != nil {
+		logging.Fatal("seed", "erro", err)
+	}
+
+	// Estudantes passam pelo repositório para respeitar a criptografia de CPF/telefone.
+	repo := model.NewEstudanteRepo(amb.db, amb.pii)
+	estudantes := []model.EstudanteCreateRequest{
+		{Nome: "Ana Souza", CPF: "52998224725", Email: "ana.souza@demo.tecmise.local", DataNascimento: "2012-03-14", Telefone: "11987650001", AnoID: anos["1º Ano"]},
+		{Nome: "Bruno Lima", CPF: "16899535009", Email: "bruno.lima@demo.tecmise.local", DataNascimento: "2012-07-02", Telefone: "11987650002", AnoID: anos["1º Ano"]},
+		{Nome: "Carla Mendes", CPF: "39053344705", Email: "carla.mendes@demo.tecmise.local", DataNascimento: "2011-01-23", Telefone: "11987650003", AnoID: anos["2º Ano"]},
+		{Nome: "Diego Rocha", CPF: "11144477735", Email: "diego.rocha@demo.tecmise.local", DataNascimento: "2011-09-30", Telefone: "11987650004", AnoID: anos["2º Ano"]},
+		{Nome: "Eduarda Alves", CPF: "74682489070", Email: "eduarda.alves@demo.tecmise.local", DataNascimento: "2010-05-11", Telefone: "11987650005", AnoID: anos["3º Ano"]},
+	}
+	for _, e := range estudantes {
+		if _, err := repo.Criar(ctx, uid, e); err != nil {
+			logging.Fatal("seed: criar estudante", "estudante", e.Nome, "erro", err)
+		}
+	}
+	slog.Info("seed: conta demo criada", "email", emailDemo, "senha", senhaDemo, "anos", len(anos), "estudantes", len(estudantes))
+}
+
+// criarUsuario insere um usuário com senha (bcrypt) e devolve o id.
+func criarUsuario(ctx context.Context, tx *sql.Tx, nome, email, senha string) (int, error) {
+	var existe bool
+	if err := tx.QueryRowContext(ctx,
+		`SELECT EXISTS(SELECT 1 FROM usuarios WHERE email=$1)`, email,
+	).Scan(&existe); err != nil {
+		return 0, err
+	}
+	if existe {
+		return 0, errors.New("e-mail já cadastrado")
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(senha), bcrypt.DefaultCost)
+	if err != nil {
+		return 0, err
+	}
+	var id int
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO usuarios (nome, email, senha_hash) VALUES ($1, $2, $3) RETURNING id`,
+		nome, email, string(hash),
+	).Scan(&id)
+	return id, err
+}
+
+// criarOrganizacao cria a organização de uid com ele como admin.
+func criarOrganizacao(ctx context.Context, tx *sql.Tx, uid int, nome string) error {
+	var orgID int
+	if err := tx.QueryRowContext(ctx,
+		`INSERT INTO organizacoes (nome, dono_id) VALUES ($1, $2) RETURNING id`, nome, uid,
+	).Scan(&orgID); err != nil {
+		return fmt.Errorf("criar organização: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO organizacao_membros (organizacao_id, usuario_id, papel) VALUES ($1, $2, $3)`,
+		orgID, uid, model.PapelAdmin,
+	); err != nil {
+		return fmt.Errorf("vincular admin: %w", err)
+	}
+	return nil
+}
+
+// senhaAleatoria gera uma senha de 16 caracteres (base64 URL).
+func senhaAleatoria() string {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		logging.Fatal("gerar senha", "erro", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
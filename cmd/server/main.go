@@ -0,0 +1,554 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/cmd/server/main.go
+/// Responsabilidade: Ponto de entrada do backend HTTP (Go), configuração de infraestrutura (middlewares, CORS, rotas) e graceful shutdown. Subcomandos de operação ficam em cli.go; a abertura dos pools de banco, em banco.go.
+/// Dependências principais: net/http, database/sql (Postgres), pacotes locais (antivirus, captcha, config, cripto, handler, jobs, middleware, migrations, model, notificador, router, storage).
+/// Pontos de atenção:
+/// - Configuração: toda variável de ambiente é lida e validada em backend/internal/config (carregada em cli.go); nada aqui chama os.Getenv.
+/// - CORS: middleware.Cors(cfg.CORS); padrão permite "Content-Type, Authorization, X-User-Email, X-Impersonation-Token, Idempotency-Key, If-Match" (CORS_ALLOW_HEADERS).
+/// - Desligamento (SIGINT/SIGTERM), tudo dentro de HTTP_SHUTDOWN_TIMEOUT: para de aceitar requisições e espera as em curso, cancela os jobs periódicos, recusa novas exportações e espera exportações, jobs e e-mails em segundo plano; o DB fecha por último (defer em cli.go).
+/// - Logs estruturados (slog) com request_id: middleware.RequestID é o primeiro da cadeia; recoverMiddleware registra valor e stack do panic.
+/// - Rotas usam padrões do Go 1.22 via backend/internal/router ("GET /api/usuario/sessoes"); método não registrado responde 405.
+/// - Middlewares declarados uma vez por grupo (router.Group: estaticos → base → rotasJSON → api → dados → idempotente); a ordem dos registros define os middlewares do 405/OPTIONS de cada caminho.
+/// - Prazo total por requisição (HTTP_REQUEST_TIMEOUT_*): middleware.PrazoRequisicao no grupo base responde 504 JSON e libera a conexão; rotas longas e de streaming ficam no mapa passado a ele.
+/// - Idioma dos erros: middleware.Idioma envolve o roteador (Accept-Language → Content-Language), lido por apierr.Escrever.
+/// - Segurança de cabeçalhos: X-Frame-Options=DENY; X-XSS-Protection=0; CSP, HSTS, Referrer-Policy e Permissions-Policy configuráveis (SECURITY_*, padrões para API sem HTML).
+*/
+
+// main.go — ponto de entrada (resumo para foco no ajuste do repo do Google)
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"backend/internal/antivirus"
+	"backend/internal/apierr"
+	"backend/internal/cache"
+	"backend/internal/captcha"
+	"backend/internal/classroom"
+	"backend/internal/config"
+	"backend/internal/cripto"
+	"backend/internal/handler"
+	"backend/internal/jobs"
+	"backend/internal/logging"
+	"backend/internal/middleware"
+	"backend/internal/model" // << usa o repo no package model
+	"backend/internal/notificador"
+	"backend/internal/router"
+	"backend/internal/storage"
+)
+
+/// ============ Middlewares ============
+
+// securityHeadersMiddleware adiciona cabeçalhos de segurança a todas as respostas.
+//   - X-Content-Type-Options: nosniff
+//   - X-Frame-Options: DENY
+//   - X-XSS-Protection: 0 (desabilita filtro legado)
+//   - Content-Security-Policy, Referrer-Policy e Permissions-Policy de cfg (SECURITY_*)
+//   - Strict-Transport-Security só em HTTPS: TLS no próprio servidor ou
+//     X-Forwarded-Proto=https vindo do proxy (navegadores ignoram HSTS em HTTP)
+//
+// Páginas HTML (Swagger UI, link público de turma) trocam a CSP pela própria.
+func securityHeadersMiddleware(cfg config.Seguranca) router.Middleware {
+	hsts := ""
+	if cfg.HSTSMaxAge > 0 {
+		hsts = "max-age=" + strconv.FormatInt(int64(cfg.HSTSMaxAge.Seconds()), 10)
+		if cfg.HSTSSubdominios {
+			hsts += "; includeSubDomains"
+		}
+		if cfg.HSTSPreload {
+			hsts += "; preload"
+		}
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h := w.Header()
+			h.Set("X-Content-Type-Options", "nosniff")
+			h.Set("X-Frame-Options", "DENY")
+			h.Set("X-XSS-Protection", "0")
+			for _, c := range [][2]string{
+				{"Content-Security-Policy", cfg.CSP},
+				{"Referrer-Policy", cfg.ReferrerPolicy},
+				{"Permissions-Policy", cfg.PermissionsPolicy},
+			} {
+				if c[1] != "" {
+					h.Set(c[0], c[1])
+				}
+			}
+			if hsts != "" && (r.TLS != nil || strings.EqualFold(r.Header.Get("X-Forwarded-Proto"), "https")) {
+				h.Set("Strict-Transport-Security", hsts)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// recoverMiddleware captura panics e responde 500 com log de erro.
+// Observação: registra valor e stack trace do panic com o request_id da requisição.
+func recoverMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				if rec == http.ErrAbortHandler { // resposta já iniciada: só derruba a conexão
+					panic(rec)
+				}
+				logging.De(r.Context()).Error("panic", "valor", fmt.Sprint(rec), "stack", string(debug.Stack()))
+				apierr.Escrever(w, http.StatusInternalServerError, apierr.ErroInterno, "Erro interno", nil)
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+/// ============ Rotas & Handlers ============
+
+// registrarRotas mapeia endpoints no router com middlewares padrão.
+// Parâmetros:
+//   - rt: *router.Router alvo (padrões "MÉTODO /caminho/{param}" do Go 1.22)
+//   - cfg: configuração carregada (CORS, Google, APP_URL)
+//   - db: *sql.DB para injeção nos handlers
+//   - replica: leituras pesadas (listagem de estudantes, relatórios) na réplica, se houver
+//   - st: backend de armazenamento de uploads (local/S3)
+//   - ch: cache de consultas frequentes (memória/Redis)
+//   - pii: cifrador de CPF/telefone e das credenciais de SMS/WhatsApp (nil = sem criptografia)
+//   - wh: fila de webhooks (eventos de estudantes/anos)
+//   - nt: envio de e-mails (boas-vindas, convites, comunicados aos responsáveis)
+//
+// Rotas principais: /register, /login, /login/google, /api/*, uploads (/api/uploads, /api/perfil/foto, /api/estudantes/import-fotos, /uploads), /api/meus-dados/export, /api/graphql, /api/relatorios, /api/filtros, /api/integracoes/classroom, /api/carteirinhas, /api/anos/{id}/roster.pdf, /api/comunicados, /api/avisos, /api/estudantes/{id}/consentimentos, /compartilhado/anos, /api/lixeira, /api/webhooks, /api/notificacoes, /api/atividades, /api/usuario/logins, /api/usuario/sessoes, /api/usuario/preferencias, /api/usuario/onboarding, /api/usuario/definir-senha, /api/usuario/vincular-google, /api/admin (suporte: usuários, impersonate), /api/dev/seed (fora de produção), /api/openapi.json, /api/docs, /healthz, /livez, /readyz, fallback 404.
+// Parâmetros de caminho ({id}, {docID}...) são lidos nos handlers com r.PathValue.
+func registrarRotas(rt *router.Router, cfg *config.Config, db *sql.DB, replica *model.Replica, st storage.Storage, ch cache.Cache, pii *cripto.Cifrador, wh *jobs.Webhooks, nt *notificador.Notificador, exportacoes *jobs.Exportacoes) {
+	// Usuário do X-User-Email resolvido uma vez por requisição (cache e-mail → acesso)
+	acessos := model.NovoCacheAcesso(ch, cfg.Cache.TTLAcesso)
+	// Grupos de rotas: cada camada de middlewares é declarada uma vez (router.Group).
+	// estaticos: só request ID, IP do cliente, recover e cabeçalhos de segurança (arquivos /uploads/...)
+	estaticos := rt.Group("", middleware.RequestID, middleware.IPCliente(cfg.HTTP.ConfiarProxy), recoverMiddleware, securityHeadersMiddleware(cfg.Seguranca))
+	// Prazo total por requisição (504): leitura/escrita pelo método; as rotas abaixo usam o
+	// prazo longo, e a listagem em streaming de estudantes fica com o prazo próprio do handler.
+	longo := cfg.HTTP.PrazoLongo
+	prazos := middleware.PrazoRequisicao(cfg.HTTP.PrazoLeitura, cfg.HTTP.PrazoEscrita, map[string]time.Duration{
+		"GET /api/estudantes":                      0,
+		"GET /api/estudantes/export":               longo,
+		"GET /api/estudantes/duplicados":           longo,
+		"POST /api/relatorios":                     longo,
+		"GET /api/graphql":                         longo,
+		"POST /api/graphql":                        longo,
+		"POST /api/integracoes/classroom/cursos":   longo,
+		"POST /api/integracoes/classroom/previa":   longo,
+		"POST /api/integracoes/classroom/importar": longo,
+		"POST /api/uploads":                        longo,
+		"POST /api/perfil/foto":                    longo,
+		"POST /api/estudantes/{id}/documentos":     longo,
+		"POST /api/estudantes/import-fotos":        longo,
+		"GET /api/anos/{id}/roster.pdf":            longo,
+		"POST /api/comunicados":                    longo,
+		"POST /api/avisos":                         longo,
+		"POST /api/dev/seed":                       longo,
+	})
+	// base: + CORS, prazo da requisição e usuário do X-User-Email (uploads multipart e links públicos)
+	base := estaticos.With(middleware.Cors(cfg.CORS), prazos, middleware.Autenticacao(db, acessos, cfg.ExigirSessao))
+	// Rotas JSON: corpo limitado a HTTP_MAX_BODY_BYTES e Content-Type application/json (415)
+	rotasJSON := base.With(middleware.CorpoJSON(cfg.HTTP.MaxBodyBytes))
+	api := rotasJSON.Group("/api")
+	// Rotas de dados: além do padrão, bloqueia escrita para papel "leitor" da organização
+	// (e invalida o cache dos dados do tenant a cada escrita bem-sucedida)
+	dados := api.With(middleware.ExigirEscritaMiddleware(db), middleware.InvalidarCacheDados(ch))
+	// POSTs de criação que aceitam Idempotency-Key (retentativas não duplicam registros)
+	idempotente := dados.With(middleware.Idempotencia(db))
+	// Uploads multipart: sem CorpoJSON (os handlers aplicam limites próprios); todos gravam no
+	// storage do tenant, então passam pelo bloqueio de escrita do papel "leitor"
+	uploads := base.Group("/api")
+	uploadsDados := uploads.With(middleware.ExigirEscritaMiddleware(db), middleware.InvalidarCacheDados(ch))
+	validarEmail := func(h http.HandlerFunc) http.Handler { return middleware.ValidarEstudanteEmailMiddleware(h) }
+
+	// Repositórios com prepared statements nas consultas quentes (login, listar/criar
+	// estudante): SQL inválido ou migration pendente derruba a inicialização aqui.
+	userRepo := model.NewUserRepo(db)
+	estudanteRepo := model.NewEstudanteRepo(db, pii) // CPF/telefone cifrados em repouso
+	ctxPrep, cancelPrep := context.WithTimeout(context.Background(), cfg.Migrate.Timeout)
+	defer cancelPrep()
+	for _, p := range []interface{ Preparar(context.Context) error }{userRepo, estudanteRepo} {
+		if err := p.Preparar(ctxPrep); err != nil {
+			logging.Fatal("preparar consultas", "erro", err)
+		}
+	}
+	// Listagens de estudantes (lista, filtros, export, duplicados, GraphQL) na réplica
+	estudanteRepo.UsarReplica(ctxPrep, replica)
+
+	// Antivírus dos uploads (fotos e documentos) com ANTIVIRUS_DRIVER ligado
+	av := antivirus.Novo(cfg.Antivirus)
+	slog.Info("antivírus dos uploads", "driver", av.Driver())
+
+	// Auth tradicional (captcha no cadastro com CAPTCHA_DRIVER ligado)
+	cv := captcha.Novo(cfg.Captcha)
+	slog.Info("captcha do cadastro", "driver", cv.Driver())
+	rotasJSON.Handle("POST /register", handler.RegisterHandler(db, nt, cfg.AppURL, cv))
+	rotasJSON.Handle("POST /login", handler.LoginHandler(userRepo, cfg.LoginLegado))
+
+	// Google Login
+	googleH := handler.NewAuthGoogleHandler(userRepo, cfg.GoogleClientID, nt, cfg.AppURL, cfg.LoginLegado)
+	rotasJSON.HandleFunc("POST /login/google", googleH.LoginGoogle)
+
+	// Perfil / Usuário
+	perfil := handler.PerfilHandler(db)
+	api.Handle("GET /perfil", perfil)
+	api.Handle("PUT /perfil", handler.AtualizarPerfilHandler(db))
+	uploadsDados.Handle("POST /perfil/foto", handler.FotoPerfilHandler(db, st, av)) // multipart: sem CorpoJSON
+	// legado: mesmo perfil; ?email só do próprio usuário (outras contas: GET /api/admin/usuario)
+	api.Handle("GET /usuario", perfil)
+	api.Handle("PUT /usuario/{id}/tutorial", handler.MarcarTutorialVistoHandler(db))
+	api.Handle("GET /usuario/logins", handler.HistoricoLoginsHandler(db))
+	sessoes := handler.SessoesHandler(db)
+	api.Handle("GET /usuario/sessoes", sessoes)
+	api.Handle("DELETE /usuario/sessoes/{id}", sessoes)
+	preferencias := handler.PreferenciasHandler(db)
+	api.Handle("GET /usuario/preferencias", preferencias)
+	api.Handle("PUT /usuario/preferencias", preferencias)
+	api.Handle("GET /usuario/onboarding", handler.OnboardingHandler(db))
+	api.Handle("PUT /usuario/onboarding/etapas/{etapa}", handler.ConcluirEtapaHandler(db))
+	// Vinculação de logins: senha numa conta Google, Google numa conta com senha
+	api.Handle("POST /usuario/definir-senha", handler.DefinirSenhaHandler(db, cfg.GoogleClientID))
+	api.Handle("POST /usuario/vincular-google", handler.VincularGoogleHandler(db, userRepo, cfg.GoogleClientID))
+
+	// Organização (multiusuário por escola)
+	api.Handle("GET /organizacao", handler.OrganizacaoHandler(db, acessos))
+	api.Handle("POST /organizacao", handler.OrganizacaoHandler(db, acessos))
+	api.Handle("POST /organizacao/membros", handler.OrganizacaoMembrosHandler(db, acessos))
+	api.Handle("PUT /organizacao/membros/{usuarioID}", handler.OrganizacaoMembrosHandler(db, acessos))
+	api.Handle("DELETE /organizacao/membros/{usuarioID}", handler.OrganizacaoMembrosHandler(db, acessos))
+	convites := handler.ConvitesHandler(db, nt, cfg.AppURL)
+	api.Handle("GET /organizacao/convites", convites)
+	api.Handle("POST /organizacao/convites", convites)
+	api.Handle("POST /organizacao/convites/aceitar", handler.AceitarConviteHandler(db, acessos))
+	api.Handle("DELETE /organizacao/convites/{id}", handler.RevogarConviteHandler(db))
+
+	// Gestão de contas pela equipe de suporte (usuarios.suporte)
+	admin := api.Group("/admin", middleware.ExigirSuporte)
+	admin.Handle("GET /usuarios", handler.AdminUsuariosHandler(db))
+	admin.Handle("GET /usuario", handler.AdminBuscarUsuarioHandler(db))
+	admin.Handle("PUT /usuarios/{id}/bloquear", handler.BloquearUsuarioHandler(db, acessos))
+	admin.Handle("DELETE /usuarios/{id}", handler.ExcluirUsuarioHandler(db, acessos))
+	admin.Handle("POST /impersonate", handler.PersonificarHandler(db))
+
+	// Portabilidade de dados (LGPD): exportação assíncrona em ZIP
+	api.Handle("GET /meus-dados/export", handler.ExportarMeusDadosHandler(db, exportacoes))
+	api.Handle("GET /meus-dados/export/{id}", handler.ExportarMeusDadosHandler(db, exportacoes))
+
+	// Validações
+	dados.Handle("GET /estudantes/check-cpf", handler.VerificarCpfHandler(db, estudanteRepo))
+	dados.Handle("GET /estudantes/check-email", handler.VerificarEmailHandler(db))
+	dados.Handle("GET /estudantes/duplicados", handler.DuplicadosEstudantesHandler(db, estudanteRepo))
+	dados.Handle("POST /estudantes/merge", handler.MesclarEstudantesHandler(db, estudanteRepo))
+	dados.Handle("GET /estudantes/aniversariantes", handler.AniversariantesHandler(db))
+
+	// Exportação em formatos externos (Educacenso, vCard)
+	dados.Handle("GET /estudantes/export", handler.ExportarEstudantesHandler(db, estudanteRepo))
+
+	// Relatórios (somente leitura: POST pelo corpo da spec, liberado ao papel leitor)
+	api.Handle("POST /relatorios", handler.RelatorioHandler(db, replica))
+
+	// Filtros salvos (pessoais: gravação liberada ao papel leitor)
+	filtros := handler.FiltrosHandler(db)
+	api.Handle("GET /filtros", filtros)
+	api.Handle("POST /filtros", filtros)
+	api.Handle("PUT /filtros/{id}", filtros)
+	api.Handle("DELETE /filtros/{id}", filtros)
+	dados.Handle("GET /filtros/{id}/resultados", handler.ResultadosFiltroHandler(db, estudanteRepo))
+
+	// Integração Google Classroom (prévia somente leitura; importar grava estudantes)
+	classroomCl := classroom.Novo(cfg.GoogleClientID)
+	api.Handle("POST /integracoes/classroom/cursos", handler.ClassroomCursosHandler(db, classroomCl))
+	api.Handle("POST /integracoes/classroom/previa", handler.ClassroomPreviaHandler(db, estudanteRepo, classroomCl))
+	dados.Handle("POST /integracoes/classroom/importar", handler.ClassroomImportarHandler(db, estudanteRepo, classroomCl, wh, nt))
+
+	// Estudantes
+	dados.Handle("GET /estudantes", handler.ListarEstudantesHandler(db, estudanteRepo))
+	idempotente.Handle("POST /estudantes", validarEmail(handler.CriarEstudanteHandler(db, estudanteRepo, wh)))
+	dados.Handle("GET /estudantes/{id}", handler.BuscarEstudanteHandler(db, estudanteRepo))
+	dados.Handle("PUT /estudantes/{id}", validarEmail(handler.EditarEstudanteHandler(db, estudanteRepo)))
+	dados.Handle("DELETE /estudantes/{id}", handler.RemoverEstudanteHandler(db, estudanteRepo, wh))
+	uploadsDados.Handle("POST /estudantes/import-fotos", handler.ImportarFotosHandler(db, estudanteRepo, st, av)) // ZIP multipart
+
+	// GraphQL (escrita checada por operação: mutation exige papel com escrita)
+	graphqlH := handler.GraphQLHandler(db, estudanteRepo, wh)
+	api.Handle("GET /graphql", graphqlH)
+	api.Handle("POST /graphql", graphqlH, middleware.InvalidarCacheDados(ch))
+
+	// Sub-recursos de estudante
+	documentos := handler.DocumentosEstudanteHandler(db, st, av)
+	dados.Handle("GET /estudantes/{id}/documentos", documentos)
+	uploadsDados.Handle("POST /estudantes/{id}/documentos", documentos)
+	dados.Handle("GET /estudantes/{id}/documentos/{docID}", documentos)
+	dados.Handle("DELETE /estudantes/{id}/documentos/{docID}", documentos)
+	dados.Handle("GET /estudantes/{id}/presencas", handler.PresencasEstudanteHandler(db, false))
+	dados.Handle("GET /estudantes/{id}/presencas/resumo", handler.PresencasEstudanteHandler(db, true))
+	dados.Handle("GET /estudantes/{id}/boletim", handler.BoletimHandler(db))
+	dados.Handle("GET /estudantes/{id}/carteirinha", handler.CarteirinhaHandler(db, estudanteRepo, st, cfg.Carteirinha.Key))
+	dados.Handle("GET /carteirinhas/verificar", handler.VerificarCarteirinhaHandler(db, estudanteRepo, cfg.Carteirinha.Key))
+	responsaveis := handler.ResponsaveisEstudanteHandler(db, estudanteRepo)
+	dados.Handle("GET /estudantes/{id}/responsaveis", responsaveis)
+	dados.Handle("POST /estudantes/{id}/responsaveis", responsaveis)
+	dados.Handle("PUT /estudantes/{id}/responsaveis/{rid}", responsaveis)
+	dados.Handle("DELETE /estudantes/{id}/responsaveis/{rid}", responsaveis)
+	dados.Handle("GET /estudantes/{id}/status", handler.StatusEstudanteHandler(db))
+	dados.Handle("PUT /estudantes/{id}/status", handler.StatusEstudanteHandler(db))
+	dados.Handle("POST /estudantes/{id}/transferir", handler.TransferirEstudanteHandler(db))
+	dados.Handle("GET /estudantes/{id}/matriculas", handler.MatriculasEstudanteHandler(db))
+	// Consentimentos (LGPD): e-mail/SMS/WhatsApp por responsável e uso de foto
+	consentimentos := handler.ConsentimentosEstudanteHandler(db)
+	dados.Handle("GET /estudantes/{id}/consentimentos", consentimentos)
+	dados.Handle("POST /estudantes/{id}/consentimentos", consentimentos)
+	dados.Handle("DELETE /estudantes/{id}/consentimentos/{cid}", consentimentos)
+
+	// Comunicados por e-mail aos responsáveis (mala direta)
+	comunicados := handler.ComunicadosHandler(db, estudanteRepo, nt)
+	dados.Handle("GET /comunicados", comunicados)
+	idempotente.Handle("POST /comunicados", comunicados)
+	dados.Handle("GET /comunicados/{id}", handler.ComunicadoHandler(db))
+	// Avisos por SMS/WhatsApp (registrados como comunicados); provedor da conta (admin)
+	idempotente.Handle("POST /avisos", handler.AvisosHandler(db, estudanteRepo, pii))
+	provedorMensagem := handler.ProvedorMensagemHandler(db, pii)
+	api.Handle("GET /avisos/provedor", provedorMensagem)
+	api.Handle("PUT /avisos/provedor", provedorMensagem)
+	api.Handle("DELETE /avisos/provedor", provedorMensagem)
+
+	// Avaliações e notas
+	dados.Handle("GET /avaliacoes", handler.AvaliacoesHandler(db))
+	dados.Handle("POST /avaliacoes", handler.AvaliacoesHandler(db))
+	dados.Handle("DELETE /avaliacoes/{id}", handler.RemoverAvaliacaoHandler(db))
+	dados.Handle("GET /avaliacoes/{id}/notas", handler.NotasAvaliacaoHandler(db))
+	dados.Handle("PUT /avaliacoes/{id}/notas", handler.NotasAvaliacaoHandler(db))
+
+	// Períodos letivos
+	dados.Handle("GET /periodos", handler.PeriodosHandler(db))
+	dados.Handle("POST /periodos", handler.PeriodosHandler(db))
+	dados.Handle("PUT /periodos/{id}/fechar", handler.FecharPeriodoHandler(db, true))
+	dados.Handle("PUT /periodos/{id}/abrir", handler.FecharPeriodoHandler(db, false))
+	dados.Handle("POST /periodos/{id}/clonar", handler.ClonarPeriodoHandler(db))
+
+	// Turmas (frequência/chamada; {id} = anos.id)
+	dados.Handle("POST /turmas/{id}/chamada", handler.ChamadaTurmaHandler(db))
+	dados.Handle("GET /turmas/{id}/presencas/resumo", handler.ResumoPresencasTurmaHandler(db))
+
+	// Anos
+	dados.Handle("GET /anos", handler.ListarAnosHandler(db, ch, cfg.Cache.TTLAnos))
+	idempotente.Handle("POST /anos", handler.CriarAnoHandler(db))
+	dados.Handle("PUT /anos/reorder", handler.ReordenarAnosHandler(db))
+	dados.Handle("POST /anos/bulk", handler.CriarAnosEmLoteHandler(db))
+	dados.Handle("DELETE /anos/{id}", handler.RemoverAnoHandler(db, wh))
+	dados.Handle("POST /anos/{id}/promover", handler.PromoverAnoHandler(db))
+	dados.Handle("PUT /anos/{id}/arquivar", handler.ArquivarAnoHandler(db))
+	dados.Handle("POST /anos/{id}/share", handler.CompartilharAnoHandler(db, cfg.Compartilhar.Key))
+	dados.Handle("GET /anos/{id}/roster.pdf", handler.ListaTurmaPDFHandler(db, estudanteRepo, st)) // lista impressa por turma
+
+	// Lista pública de um ano (link assinado e com validade; sem autenticação)
+	base.Handle("GET /compartilhado/anos/{id}", handler.TurmaCompartilhadaHandler(db, cfg.Compartilhar.Key))
+
+	// Lixeira (estudantes/anos excluídos): restaurar ou excluir definitivamente (admin)
+	dados.Handle("GET /lixeira", handler.ListarLixeiraHandler(db))
+	dados.Handle("POST /lixeira/{tipo}/{id}/restaurar", handler.RestaurarLixeiraHandler(db))
+	dados.Handle("DELETE /lixeira/{tipo}/{id}", handler.ExpurgarLixeiraHandler(db))
+
+	// Webhooks (admin): cadastro e log de entregas
+	webhooks := handler.WebhooksHandler(db)
+	api.Handle("GET /webhooks", webhooks)
+	api.Handle("POST /webhooks", webhooks)
+	api.Handle("DELETE /webhooks/{id}", webhooks)
+	api.Handle("GET /webhooks/{id}/entregas", handler.EntregasWebhookHandler(db))
+
+	// Notificações in-app (sino): cada conta vê as próprias
+	api.Handle("GET /notificacoes", handler.ListarNotificacoesHandler(db))
+	api.Handle("PUT /notificacoes/{id}/lida", handler.MarcarNotificacaoLidaHandler(db))
+
+	// Feed de atividades (log de auditoria dos dados do tenant)
+	api.Handle("GET /atividades", handler.AtividadesHandler(db))
+
+	// Uploads (gravação e leitura via storage.Storage)
+	uploadsDados.Handle("POST /uploads", handler.UploadHandler(db, st, av))
+	api.Handle("GET /uploads/assinar", handler.AssinarUploadHandler(db, st))
+	estaticos.Handle("GET /uploads/{key...}", handler.ServirUploadsHandler(db, st))
+
+	// Dados fictícios (APP_ENV=development): em produção a rota não é registrada
+	if !cfg.Producao() {
+		dados.Handle("POST /dev/seed", handler.SeedHandler(db, estudanteRepo))
+	}
+
+	// Contrato da API: OpenAPI 3 + Swagger UI
+	api.Handle("GET /openapi.json", handler.OpenAPIHandler())
+	api.Handle("GET /docs", handler.DocsHandler())
+
+	// health: /livez e /readyz para probes; /healthz mantido por compatibilidade
+	rt.Handle("GET /livez", handler.LivezHandler())
+	rt.Handle("GET /readyz", handler.ReadyzHandler(db, st, ch))
+	rt.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok"))
+	})
+	rotasJSON.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		apierr.Escrever(w, http.StatusNotFound, apierr.EndpointNaoEncontrado, "Endpoint não encontrado", nil)
+	})
+}
+
+/// ============ Inicialização/Bootstrap ============
+
+// main inicializa configuração via .env, conecta no banco, registra rotas e inicia HTTP server.
+// Implementa graceful shutdown em SIGINT/SIGTERM com timeout configurável via HTTP_SHUTDOWN_TIMEOUT.
+// Logs básicos informam porta e eventos de desligamento.
+func main() {
+	executarComando(os.Args[1:])
+}
+
+// servir sobe a API HTTP (subcomando padrão `serve`) e bloqueia até SIGINT/SIGTERM.
+func servir(amb *ambiente, _ []string) {
+	cfg, db, pii := amb.cfg, amb.db, amb.pii
+
+	st, err := storage.New(cfg.Storage)
+	if err != nil {
+		logging.Fatal("configurar armazenamento de uploads", "erro", err)
+	}
+
+	ch, err := cache.New(cfg.Cache)
+	if err != nil {
+		logging.Fatal("configurar cache", "erro", err)
+	}
+	slog.Info("cache configurado", "driver", cfg.Cache.Driver)
+
+	wh := jobs.NovosWebhooks(db, cfg.Webhooks.Interval, cfg.Webhooks.Timeout)
+
+	nt, err := notificador.Novo(cfg.Email)
+	if err != nil {
+		logging.Fatal("configurar envio de e-mails", "erro", err)
+	}
+	slog.Info("envio de e-mails configurado", "driver", nt.Driver())
+
+	exportacoes := jobs.NovasExportacoes(db, st, pii)
+
+	ro := conectarReplica(cfg)
+	if ro != nil {
+		defer func() { _ = ro.Close() }()
+	}
+	replica := model.NovaReplica(db, ro)
+
+	handler.ConfigurarTimeouts(cfg.DB.TimeoutLeitura, cfg.DB.TimeoutEscrita, cfg.DB.TimeoutRelatorio)
+	handler.ConfigurarValidacaoGoogle(cfg.Google.TimeoutToken, cfg.Google.FalhasDisjuntor, cfg.Google.PausaDisjuntor)
+	handler.ConfigurarSessoes(cfg.SessaoTTL)
+	if cfg.ExigirSessao {
+		slog.Info("AUTH_REQUIRE_SESSION ativo: X-User-Email sem sessão não autentica")
+	} else {
+		slog.Warn("AUTH_REQUIRE_SESSION desligado: X-User-Email sozinho autentica, e encerrar uma sessão não desconecta o dispositivo")
+	}
+	rt := router.New()
+	registrarRotas(rt, cfg, db, replica, st, ch, pii, wh, nt, exportacoes)
+	if faltando := handler.RotasSemDocumentacao(rt.Padroes()); len(faltando) > 0 {
+		slog.Warn("rotas sem documentação no OpenAPI (handler/openapi_rotas.go)", "rotas", faltando)
+	}
+	var raiz http.Handler = rt
+	if cfg.HTTP.ValidarContrato {
+		raiz = handler.ValidarContrato(rt)
+		slog.Warn("OPENAPI_VALIDATE_RESPONSES ativo: respostas conferidas contra o OpenAPI (custo extra por requisição)")
+	}
+	// X-HTTP-Method-Override precisa trocar o método antes do roteamento
+	raiz = middleware.SobreporMetodo(cfg.HTTP.SobreporMetodo)(raiz)
+	if cfg.HTTP.SobreporMetodo {
+		slog.Info("HTTP_METHOD_OVERRIDE ativo: POST com X-HTTP-Method-Override vira PUT/PATCH/DELETE")
+	}
+	// idioma das mensagens de erro (Accept-Language), inclusive nos 404/405 do roteador
+	raiz = middleware.Idioma(raiz)
+
+	// Jobs em segundo plano (cancelados no desligamento)
+	bgCtx, stopBG := context.WithCancel(context.Background())
+	defer stopBG()
+	gc := &jobs.UploadsGC{
+		DB:       db,
+		Storage:  st,
+		Interval: cfg.UploadsGC.Interval,
+		Grace:    cfg.UploadsGC.Grace,
+		DryRun:   cfg.UploadsGC.DryRun,
+	}
+	var trabalhadores jobs.Trabalhadores
+	trabalhadores.Iniciar(bgCtx, gc.Run)
+	trabalhadores.Iniciar(bgCtx, wh.Run)
+	aniversarios := &jobs.Aniversarios{
+		DB:          db,
+		Notificador: nt,
+		AppURL:      cfg.AppURL,
+		Interval:    cfg.Aniversarios.Interval,
+	}
+	trabalhadores.Iniciar(bgCtx, aniversarios.Run)
+
+	port := cfg.Porta
+	server := &http.Server{
+		Addr: ":" + port, Handler: raiz,
+		ReadTimeout:       cfg.HTTP.ReadTimeout,
+		ReadHeaderTimeout: cfg.HTTP.ReadHeaderTimeout,
+		WriteTimeout:      cfg.HTTP.WriteTimeout,
+		IdleTimeout:       cfg.HTTP.IdleTimeout,
+	}
+	var redirect *http.Server
+	if cfg.TLS.Ativo() {
+		redirect = configurarTLS(cfg, server)
+		slog.Info("servidor HTTPS iniciado", "endereco", "https://localhost:"+port)
+	} else {
+		slog.Info("servidor HTTP iniciado", "endereco", "http://localhost:"+port)
+	}
+	if redirect != nil {
+		go func() {
+			slog.Info("redirecionamento HTTP→HTTPS iniciado", "porta", cfg.TLS.RedirectPorta)
+			if err := redirect.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logging.Fatal("iniciar redirecionamento HTTP", "erro", err)
+			}
+		}()
+	}
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	encerrado := make(chan struct{})
+	go func() {
+		defer close(encerrado)
+		<-quit
+		slog.Info("desligando o servidor")
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.HTTP.ShutdownTimeout)
+		defer cancel()
+
+		// 1) para de aceitar requisições (e, com isso, novos jobs) e espera as em curso
+		if redirect != nil {
+			_ = redirect.Shutdown(ctx)
+		}
+		if err := server.Shutdown(ctx); err != nil {
+			slog.Error("desligar servidor", "erro", err)
+		}
+		// 2) jobs periódicos não iniciam nova varredura; exportações novas são recusadas
+		stopBG()
+		// 3) espera o trabalho em andamento
+		if err := exportacoes.Encerrar(ctx); err != nil {
+			slog.Warn("desligamento: exportações ainda em andamento", "erro", err)
+		}
+		if err := trabalhadores.Aguardar(ctx); err != nil {
+			slog.Warn("desligamento: jobs ainda em andamento", "erro", err)
+		}
+		if err := nt.Aguardar(ctx); err != nil {
+			slog.Warn("desligamento: e-mails ainda não enviados", "erro", err)
+		}
+		slog.Info("servidor desligado")
+	}()
+	if cfg.TLS.Ativo() {
+		err = server.ListenAndServeTLS(cfg.TLS.CertFile, cfg.TLS.KeyFile)
+	} else {
+		err = server.ListenAndServe()
+	}
+	if err != nil && err != http.ErrServerClosed {
+		logging.Fatal("iniciar servidor", "erro", err)
+	}
+	<-encerrado
+}
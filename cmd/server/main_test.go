@@ -0,0 +1,100 @@
+package main
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"backend/internal/cache"
+	"backend/internal/config"
+	"backend/internal/cripto"
+	"backend/internal/handler"
+	"backend/internal/jobs"
+	"backend/internal/model"
+	"backend/internal/notificador"
+	"backend/internal/router"
+	"backend/internal/storage"
+)
+
+// bancoSemServidor é um driver database/sql que aceita Prepare (os repositórios
+// preparam consultas ao registrar as rotas) e falha em qualquer execução.
+type bancoSemServidor struct{}
+
+type conexaoSemServidor struct{}
+
+type consultaSemServidor struct{}
+
+var errSemServidor = errors.New("teste: sem servidor de banco")
+
+func (bancoSemServidor) Open(string) (driver.Conn, error) { return conexaoSemServidor{}, nil }
+
+func (conexaoSemServidor) Prepare(string) (driver.Stmt, error) { return consultaSemServidor{}, nil }
+func (conexaoSemServidor) Close() error                        { return nil }
+func (conexaoSemServidor) Begin() (driver.Tx, error)           { return nil, errSemServidor }
+
+func (consultaSemServidor) Close() error                               { return nil }
+func (consultaSemServidor) NumInput() int                              { return -1 }
+func (consultaSemServidor) Exec([]driver.Value) (driver.Result, error) { return nil, errSemServidor }
+func (consultaSemServidor) Query([]driver.Value) (driver.Rows, error)  { return nil, errSemServidor }
+
+func init() { sql.Register("sem-servidor", bancoSemServidor{}) }
+
+// rotasDeTeste monta o roteador completo com a configuração padrão; o banco é
+// falso (bancoSemServidor): nenhuma rota é chamada aqui.
+func rotasDeTeste(t *testing.T) *router.Router {
+	t.Helper()
+	t.Setenv("DATABASE_URL", "postgres://teste@localhost:1/teste?sslmode=disable")
+	t.Setenv("UPLOADS_DIR", t.TempDir())
+	t.Setenv("EMAIL_DRIVER", "log")
+	cfg, err := config.Carregar()
+	if err != nil {
+		t.Fatalf("config: %v", err)
+	}
+	db, err := sql.Open("sem-servidor", "")
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	st, err := storage.New(cfg.Storage)
+	if err != nil {
+		t.Fatalf("storage: %v", err)
+	}
+	ch, err := cache.New(cfg.Cache)
+	if err != nil {
+		t.Fatalf("cache: %v", err)
+	}
+	nt, err := notificador.Novo(cfg.Email)
+	if err != nil {
+		t.Fatalf("notificador: %v", err)
+	}
+	pii, err := cripto.NewFromConfig(cfg.PII)
+	if err != nil {
+		t.Fatalf("cripto: %v", err)
+	}
+
+	rt := router.New()
+	registrarRotas(rt, cfg, db, model.NovaReplica(db, nil), st, ch, pii,
+		jobs.NovosWebhooks(db, cfg.Webhooks.Interval, cfg.Webhooks.Timeout), nt,
+		jobs.NovasExportacoes(db, st, pii))
+	return rt
+}
+
+// Caminhos equivalentes (ex.: "GET /estudantes/{id}" e "PUT /estudantes/{estudanteID}")
+// ou padrões inválidos só aparecem como panic no boot.
+func TestRegistrarRotasSemConflito(t *testing.T) {
+	defer func() {
+		if r := recover(); r != nil {
+			t.Fatalf("registrarRotas entrou em panic: %v", r)
+		}
+	}()
+	rotasDeTeste(t)
+}
+
+func TestRotasDocumentadas(t *testing.T) {
+	rt := rotasDeTeste(t)
+	if faltando := handler.RotasSemDocumentacao(rt.Padroes()); len(faltando) > 0 {
+		t.Errorf("rotas sem documentação em handler/openapi_rotas.go: %v", faltando)
+	}
+}
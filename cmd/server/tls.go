@@ -0,0 +1,91 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/cmd/server/tls.go
+/// Responsabilidade: Terminação TLS no próprio servidor (certificado em arquivo ou Let's Encrypt via autocert) e servidor HTTP auxiliar que redireciona para HTTPS.
+/// Dependências principais: crypto/tls, net, net/http, golang.org/x/crypto/acme/autocert, backend/internal/config.
+/// Pontos de atenção:
+/// - HTTP/2 é negociado automaticamente (ALPN) pelo net/http quando o servidor fala TLS; nada a configurar.
+/// - Autocert resolve o desafio ACME por TLS-ALPN-01 na PORT e por HTTP-01 na TLS_REDIRECT_PORT; em produção use PORT=443 e TLS_REDIRECT_PORT=80.
+/// - TLS_AUTOCERT_CACHE_DIR guarda a chave da conta e os certificados: persista o diretório entre deploys (limites de emissão do Let's Encrypt).
+/// - Atrás de proxy reverso que já termina TLS, deixe as variáveis TLS_* vazias.
+*/
+
+package main
+
+import (
+	"crypto/tls"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+
+	"golang.org/x/crypto/acme/autocert"
+
+	"backend/internal/config"
+)
+
+/// ============ Inicialização/Bootstrap ============
+
+// configurarTLS prepara server para HTTPS conforme cfg.TLS e devolve o servidor HTTP
+// de redirecionamento (nil quando TLS_REDIRECT_PORT=0). Só chamar com cfg.TLS.Ativo().
+func configurarTLS(cfg *config.Config, server *http.Server) *http.Server {
+	redirecionar := redirecionarHTTPS(cfg.Porta)
+	var desafio http.Handler = redirecionar
+
+	if len(cfg.TLS.AutocertDomains) > 0 {
+		m := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(cfg.TLS.AutocertDomains...),
+			Cache:      autocert.DirCache(cfg.TLS.AutocertCacheDir),
+			Email:      cfg.TLS.AutocertEmail,
+		}
+		server.TLSConfig = m.TLSConfig()
+		desafio = m.HTTPHandler(redirecionar)
+		slog.Info("TLS automático (Let's Encrypt) habilitado", "dominios", cfg.TLS.AutocertDomains, "cache", cfg.TLS.AutocertCacheDir)
+	} else {
+		server.TLSConfig = &tls.Config{}
+	}
+	server.TLSConfig.MinVersion = tls.VersionTLS12
+
+	if cfg.TLS.RedirectPorta == "0" {
+		return nil
+	}
+	return &http.Server{
+		Addr:              ":" + cfg.TLS.RedirectPorta,
+		Handler:           desafio,
+		ReadHeaderTimeout: cfg.HTTP.ReadHeaderTimeout,
+		ReadTimeout:       cfg.HTTP.ReadTimeout,
+		WriteTimeout:      cfg.HTTP.WriteTimeout,
+		IdleTimeout:       cfg.HTTP.IdleTimeout,
+	}
+}
+
+/// ============ Funções Internas (helpers) ============
+
+// redirecionarHTTPS responde 301 (GET/HEAD) ou 308 (demais métodos, preserva corpo)
+// para o mesmo host e caminho em https, na porta HTTPS quando ela não for a 443.
+func redirecionarHTTPS(portaHTTPS string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		} else {
+			host = strings.Trim(host, "[]") // IPv6 sem porta
+		}
+		if host == "" {
+			http.Error(w, "host ausente", http.StatusBadRequest)
+			return
+		}
+		if portaHTTPS != "443" {
+			host = net.JoinHostPort(host, portaHTTPS)
+		} else if strings.Contains(host, ":") {
+			host = "[" + host + "]"
+		}
+
+		status := http.StatusMovedPermanently
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			status = http.StatusPermanentRedirect
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), status)
+	})
+}
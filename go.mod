@@ -25,8 +25,11 @@ require (
 	// 📚 Dependências diretas
 	// =============================
 
-	// Driver PostgreSQL para Go
-	github.com/lib/pq v1.10.9
+	// Driver PostgreSQL para Go (pgx v5, usado via database/sql)
+	github.com/jackc/pgx/v5 v5.7.1
+
+	// Geração de QR Code (carteirinha do estudante)
+	github.com/skip2/go-qrcode v0.0.0-20200617195104-da1b6568686e
 
 	// Pacote oficial com utilitários de criptografia
 	// (usado para hashing de senhas com bcrypt, etc.)
@@ -40,6 +43,8 @@ require (
 // - Podem ser atualizadas/removidas via `go mod tidy`
 require github.com/joho/godotenv v1.5.1
 
+require google.golang.org/api v0.250.0
+
 require (
 	cloud.google.com/go/auth v0.16.5 // indirect
 	cloud.google.com/go/auth/oauth2adapt v0.2.8 // indirect
@@ -50,6 +55,9 @@ require (
 	github.com/google/s2a-go v0.1.9 // indirect
 	github.com/googleapis/enterprise-certificate-proxy v0.3.6 // indirect
 	github.com/googleapis/gax-go/v2 v2.15.0 // indirect
+	github.com/jackc/pgpassfile v1.0.0 // indirect
+	github.com/jackc/pgservicefile v0.0.0-20240606120523-5a60cdf6a761 // indirect
+	github.com/jackc/puddle/v2 v2.2.2 // indirect
 	go.opentelemetry.io/auto/sdk v1.1.0 // indirect
 	go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp v0.61.0 // indirect
 	go.opentelemetry.io/otel v1.37.0 // indirect
@@ -57,9 +65,9 @@ require (
 	go.opentelemetry.io/otel/trace v1.37.0 // indirect
 	golang.org/x/net v0.44.0 // indirect
 	golang.org/x/oauth2 v0.31.0 // indirect
+	golang.org/x/sync v0.17.0 // indirect
 	golang.org/x/sys v0.36.0 // indirect
 	golang.org/x/text v0.29.0 // indirect
-	google.golang.org/api v0.250.0 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20250908214217-97024824d090 // indirect
 	google.golang.org/grpc v1.75.1 // indirect
 	google.golang.org/protobuf v1.36.9 // indirect
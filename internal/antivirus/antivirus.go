@@ -0,0 +1,134 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/antivirus/antivirus.go
+/// Responsabilidade: Verificação antivírus dos arquivos enviados (fotos e documentos) antes de gravar no storage, via clamd (ClamAV) por TCP.
+/// Dependências principais: context, encoding/binary, net, backend/internal/config.
+/// Pontos de atenção:
+/// - Protocolo INSTREAM do clamd: "zINSTREAM\0", blocos [tamanho uint32 big-endian][dados], bloco de tamanho 0 no fim;
+///   a resposta é "stream: OK", "stream: <assinatura> FOUND" ou "... ERROR".
+/// - ANTIVIRUS_DRIVER=off (padrão) desliga: Verificar aceita tudo.
+/// - clamd fora do ar ou com erro é falha distinta de ErrInfectado: o handler responde 503 (ou aceita o arquivo com
+///   ANTIVIRUS_FAIL_OPEN=true), em vez de culpar o arquivo.
+/// - O tamanho máximo aceito pelo clamd (StreamMaxLength, 25 MiB por padrão) precisa cobrir os limites de upload da API.
+*/
+
+package antivirus
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"backend/internal/config"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// Verificador envia arquivos ao antivírus configurado.
+type Verificador struct {
+	driver      string // off | clamd
+	endereco    string
+	timeout     time.Duration
+	falhaAberta bool
+}
+
+/// ============ Configurações & Constantes ============
+
+// tamanho de cada bloco enviado ao clamd
+const tamanhoBloco = 64 << 10
+
+var ErrInfectado = errors.New("arquivo recusado pelo antivírus")
+
+/// ============ Inicialização/Bootstrap ============
+
+// Novo cria o verificador de cfg (ANTIVIRUS_*; já validado por config.Carregar).
+func Novo(cfg config.Antivirus) *Verificador {
+	return &Verificador{
+		driver:      cfg.Driver,
+		endereco:    cfg.Endereco,
+		timeout:     cfg.Timeout,
+		falhaAberta: cfg.FalhaAberta,
+	}
+}
+
+/// ============ Funções Públicas ============
+
+// Driver informa o antivírus em uso ("off" quando desligado), para logs de inicialização.
+func (v *Verificador) Driver() string { return v.driver }
+
+// Ativo indica se Verificar consulta o antivírus.
+func (v *Verificador) Ativo() bool { return v.driver != "off" }
+
+// FalhaAberta indica se arquivos devem ser aceitos quando o antivírus falhar (ANTIVIRUS_FAIL_OPEN).
+func (v *Verificador) FalhaAberta() bool { return v.falhaAberta }
+
+// Verificar envia data ao antivírus. ErrInfectado (com a assinatura na mensagem)
+// quando o arquivo tem ameaça; outros erros são falhas do antivírus.
+func (v *Verificador) Verificar(ctx context.Context, data []byte) error {
+	if !v.Ativo() {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, v.timeout)
+	defer cancel()
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", v.endereco)
+	if err != nil {
+		return fmt.Errorf("clamd: %w", err)
+	}
+	defer conn.Close()
+	if prazo, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(prazo)
+	}
+
+	w := bufio.NewWriter(conn)
+	if _, err := w.WriteString("zINSTREAM\x00"); err != nil {
+		return fmt.Errorf("clamd: %w", err)
+	}
+	var tam [4]byte
+	for resto := data; len(resto) > 0; {
+		n := min(len(resto), tamanhoBloco)
+		binary.BigEndian.PutUint32(tam[:], uint32(n))
+		if _, err := w.Write(tam[:]); err != nil {
+			return fmt.Errorf("clamd: %w", err)
+		}
+		if _, err := w.Write(resto[:n]); err != nil {
+			return fmt.Errorf("clamd: %w", err)
+		}
+		resto = resto[n:]
+	}
+	binary.BigEndian.PutUint32(tam[:], 0)
+	if _, err := w.Write(tam[:]); err != nil {
+		return fmt.Errorf("clamd: %w", err)
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("clamd: %w", err)
+	}
+
+	resposta, err := bufio.NewReader(conn).ReadBytes(0)
+	if err != nil && len(resposta) == 0 {
+		return fmt.Errorf("clamd: %w", err)
+	}
+	return interpretar(string(bytes.TrimRight(resposta, "\x00\n")))
+}
+
+/// ============ Funções Internas (helpers) ============
+
+// interpretar lê a resposta do INSTREAM ("stream: OK", "stream: X FOUND", "... ERROR").
+func interpretar(resposta string) error {
+	r := strings.TrimSpace(strings.TrimPrefix(resposta, "stream:"))
+	switch {
+	case r == "OK":
+		return nil
+	case strings.HasSuffix(r, " FOUND"):
+		return fmt.Errorf("%w (%s)", ErrInfectado, strings.TrimSuffix(r, " FOUND"))
+	default:
+		return fmt.Errorf("clamd: resposta inesperada: %q", resposta)
+	}
+}
@@ -0,0 +1,190 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/apierr/apierr.go
+/// Responsabilidade: Envelope único de erro da API ({code, message, details, request_id}) e catálogo de códigos legíveis por máquina.
+/// Dependências principais: net/http, encoding/json, backend/internal/i18n, backend/internal/logging.
+/// Pontos de atenção:
+/// - Usado por handlers, middlewares, router e main: nenhuma resposta de erro deve sair em texto simples.
+/// - request_id vem do cabeçalho de resposta definido por middleware.RequestID (ausente fora da cadeia).
+/// - Códigos são contrato com o frontend: renomear um código é breaking change; mensagens podem mudar.
+/// - message e details (Traduzivel) saem no idioma do Content-Language da resposta (middleware.Idioma): pt-BR ou en (mensagens.go).
+/// - Erros 5xx nunca devem carregar err.Error() em message/details (o detalhe vai para o log).
+*/
+
+package apierr
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"backend/internal/i18n"
+	"backend/internal/logging"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// Erro é o corpo JSON de toda resposta de erro.
+type Erro struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Details   any    `json:"details,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// Traduzivel é implementado por details com textos para o usuário
+// (ex.: model.ErrosValidacao), traduzidos por Escrever.
+type Traduzivel interface {
+	Traduzir(idioma string) any
+}
+
+/// ============ Configurações & Constantes ============
+
+// Códigos genéricos (derivados do status HTTP quando não há código específico).
+const (
+	RequisicaoInvalida  = "REQUISICAO_INVALIDA"
+	NaoAutenticado      = "NAO_AUTENTICADO"
+	SemPermissao        = "SEM_PERMISSAO"
+	NaoEncontrado       = "NAO_ENCONTRADO"
+	MetodoNaoPermitido  = "METODO_NAO_PERMITIDO"
+	Conflito            = "CONFLITO"
+	PayloadMuitoGrande  = "PAYLOAD_MUITO_GRANDE"
+	TipoNaoSuportado    = "TIPO_NAO_SUPORTADO"
+	MuitasRequisicoes   = "MUITAS_REQUISICOES"
+	ErroInterno         = "ERRO_INTERNO"
+	FalhaServicoExterno = "FALHA_SERVICO_EXTERNO"
+	Indisponivel        = "INDISPONIVEL"
+	TempoEsgotado       = "TEMPO_ESGOTADO"
+)
+
+// Códigos de entrada inválida.
+const (
+	JSONInvalido = "JSON_INVALIDO"
+	IDInvalido   = "ID_INVALIDO"
+	Validacao    = "VALIDACAO"
+)
+
+// Códigos de domínio.
+const (
+	CredenciaisInvalidas         = "CREDENCIAIS_INVALIDAS"
+	EmailJaCadastrado            = "EMAIL_JA_CADASTRADO"
+	CaptchaInvalido              = "CAPTCHA_INVALIDO"
+	UsuarioNaoEncontrado         = "USUARIO_NAO_ENCONTRADO"
+	ContaBloqueada               = "CONTA_BLOQUEADA"
+	PropriaConta                 = "PROPRIA_CONTA"
+	DonoComMembros               = "DONO_COM_MEMBROS"
+	PersonificacaoInvalida       = "PERSONIFICACAO_INVALIDA"
+	PersonificacaoNaoPermitida   = "PERSONIFICACAO_NAO_PERMITIDA"
+	SessaoInvalida               = "SESSAO_INVALIDA"
+	SessaoNaoEncontrada          = "SESSAO_NAO_ENCONTRADA"
+	SessaoObrigatoria            = "SESSAO_OBRIGATORIA"
+	SenhaJaDefinida              = "SENHA_JA_DEFINIDA"
+	ContaSemSenha                = "CONTA_SEM_SENHA"
+	GoogleContaDiferente         = "GOOGLE_CONTA_DIFERENTE"
+	GoogleJaVinculado            = "GOOGLE_JA_VINCULADO"
+	UsuarioSemOrganizacao        = "USUARIO_SEM_ORGANIZACAO"
+	UsuarioJaPertenceOrg         = "USUARIO_JA_PERTENCE_ORGANIZACAO"
+	MembroNaoEncontrado          = "MEMBRO_NAO_ENCONTRADO"
+	PapelInvalido                = "PAPEL_INVALIDO"
+	ConviteInvalido              = "CONVITE_INVALIDO"
+	ConviteOutroEmail            = "CONVITE_OUTRO_EMAIL"
+	EstudanteNaoEncontrado       = "ESTUDANTE_NAO_ENCONTRADO"
+	EstudanteCPFDuplicado        = "ESTUDANTE_CPF_DUPLICADO"
+	EstudanteEmailDuplicado      = "ESTUDANTE_EMAIL_DUPLICADO"
+	EstudanteStatusInvalido      = "ESTUDANTE_STATUS_INVALIDO"
+	TransicaoStatus              = "TRANSICAO_STATUS_INVALIDA"
+	TransferenciaMesmaTurma      = "TRANSFERENCIA_MESMA_TURMA"
+	AnoNaoEncontrado             = "ANO_NAO_ENCONTRADO"
+	AnoNomeDuplicado             = "ANO_NOME_DUPLICADO"
+	AnoComEstudantes             = "ANO_COM_ESTUDANTES"
+	PeriodoNaoEncontrado         = "PERIODO_NAO_ENCONTRADO"
+	PeriodoNomeDuplicado         = "PERIODO_NOME_DUPLICADO"
+	PeriodoFechado               = "PERIODO_FECHADO"
+	ResponsavelNaoEncontrado     = "RESPONSAVEL_NAO_ENCONTRADO"
+	AvaliacaoNaoEncontrada       = "AVALIACAO_NAO_ENCONTRADA"
+	DocumentoNaoEncontrado       = "DOCUMENTO_NAO_ENCONTRADO"
+	ExportacaoNaoEncontrada      = "EXPORTACAO_NAO_ENCONTRADA"
+	WebhookNaoEncontrado         = "WEBHOOK_NAO_ENCONTRADO"
+	ComunicadoNaoEncontrado      = "COMUNICADO_NAO_ENCONTRADO"
+	ConsentimentoNaoEncontrado   = "CONSENTIMENTO_NAO_ENCONTRADO"
+	ConsentimentoAtivo           = "CONSENTIMENTO_ATIVO"
+	NotificacaoNaoEncontrada     = "NOTIFICACAO_NAO_ENCONTRADA"
+	FiltroNaoEncontrado          = "FILTRO_NAO_ENCONTRADO"
+	FiltroNomeDuplicado          = "FILTRO_NOME_DUPLICADO"
+	ItemLixeiraNaoEncontrado     = "ITEM_LIXEIRA_NAO_ENCONTRADO"
+	LixeiraAnoExcluido           = "LIXEIRA_ANO_EXCLUIDO"
+	ConfirmacaoInvalida          = "CONFIRMACAO_INVALIDA"
+	RegistroDuplicado            = "REGISTRO_DUPLICADO"
+	ArquivoInvalido              = "ARQUIVO_INVALIDO"
+	ArquivoInfectado             = "ARQUIVO_INFECTADO"
+	GoogleTokenInvalido          = "GOOGLE_TOKEN_INVALIDO"
+	GoogleNaoConfigurado         = "GOOGLE_NAO_CONFIGURADO"
+	GoogleIndisponivel           = "GOOGLE_INDISPONIVEL"
+	GoogleEscopoInsuficiente     = "GOOGLE_ESCOPO_INSUFICIENTE"
+	ClassroomCursoNaoEncontrado  = "CLASSROOM_CURSO_NAO_ENCONTRADO"
+	EducacensoPendencias         = "EDUCACENSO_PENDENCIAS"
+	LinkInvalido                 = "LINK_INVALIDO"
+	PrecondicaoObrigatoria       = "PRECONDICAO_OBRIGATORIA"
+	VersaoDivergente             = "VERSAO_DIVERGENTE"
+	IdempotenciaChaveInvalida    = "IDEMPOTENCIA_CHAVE_INVALIDA"
+	IdempotenciaChaveReutilizada = "IDEMPOTENCIA_CHAVE_REUTILIZADA"
+	IdempotenciaEmAndamento      = "IDEMPOTENCIA_EM_ANDAMENTO"
+	EndpointNaoEncontrado        = "ENDPOINT_NAO_ENCONTRADO"
+)
+
+/// ============ Funções Públicas ============
+
+// Escrever responde o envelope de erro com status, código, mensagem e detalhes opcionais,
+// no idioma negociado para a resposta (i18n.DaResposta).
+func Escrever(w http.ResponseWriter, status int, code, msg string, details any) {
+	if code == "" {
+		code = CodigoPadrao(status)
+	}
+	idioma := i18n.DaResposta(w)
+	if t, ok := details.(Traduzivel); ok {
+		details = t.Traduzir(idioma)
+	}
+	body := Erro{
+		Code:      code,
+		Message:   Mensagem(idioma, code, msg),
+		Details:   details,
+		RequestID: w.Header().Get(logging.HeaderRequestID),
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Del("Content-Length")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// CodigoPadrao devolve o código genérico de um status HTTP.
+func CodigoPadrao(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return RequisicaoInvalida
+	case http.StatusUnauthorized:
+		return NaoAutenticado
+	case http.StatusForbidden:
+		return SemPermissao
+	case http.StatusNotFound:
+		return NaoEncontrado
+	case http.StatusMethodNotAllowed:
+		return MetodoNaoPermitido
+	case http.StatusConflict:
+		return Conflito
+	case http.StatusRequestEntityTooLarge:
+		return PayloadMuitoGrande
+	case http.StatusUnsupportedMediaType:
+		return TipoNaoSuportado
+	case http.StatusTooManyRequests:
+		return MuitasRequisicoes
+	case http.StatusBadGateway:
+		return FalhaServicoExterno
+	case http.StatusServiceUnavailable:
+		return Indisponivel
+	case http.StatusGatewayTimeout:
+		return TempoEsgotado
+	}
+	if status >= http.StatusInternalServerError {
+		return ErroInterno
+	}
+	return RequisicaoInvalida
+}
@@ -0,0 +1,120 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/apierr/mensagens.go
+/// Responsabilidade: Mensagens de cada código de erro em pt-BR e en, usadas por Escrever conforme o idioma negociado.
+/// Dependências principais: backend/internal/i18n.
+/// Pontos de atenção:
+/// - Todo código novo em apierr.go precisa de uma entrada aqui; sem ela, a resposta em en sai com a mensagem em português.
+/// - Em pt-BR a mensagem passada pelo chamador tem prioridade (é mais específica); o texto pt-BR daqui só cobre mensagem vazia.
+/// - As mensagens en são genéricas por código: detalhes variáveis (nomes, ids) continuam em details.
+*/
+
+package apierr
+
+import "backend/internal/i18n"
+
+/// ============ Configurações & Constantes ============
+
+// mensagens traduz cada código (ver Mensagem).
+var mensagens = map[string]i18n.Texto{
+	// genéricos
+	RequisicaoInvalida:  {PtBR: "Requisição inválida", EN: "Invalid request"},
+	NaoAutenticado:      {PtBR: "Usuário não autenticado", EN: "Not authenticated"},
+	SemPermissao:        {PtBR: "Acesso negado", EN: "Access denied"},
+	NaoEncontrado:       {PtBR: "Recurso não encontrado", EN: "Resource not found"},
+	MetodoNaoPermitido:  {PtBR: "Método não permitido", EN: "Method not allowed"},
+	Conflito:            {PtBR: "Conflito com o estado atual do recurso", EN: "Conflict with the current state of the resource"},
+	PayloadMuitoGrande:  {PtBR: "Corpo da requisição muito grande", EN: "Request body too large"},
+	TipoNaoSuportado:    {PtBR: "Tipo de conteúdo não suportado", EN: "Unsupported content type"},
+	MuitasRequisicoes:   {PtBR: "Muitas requisições; tente novamente mais tarde", EN: "Too many requests; try again later"},
+	ErroInterno:         {PtBR: "Erro interno do servidor", EN: "Internal server error"},
+	FalhaServicoExterno: {PtBR: "Falha em um serviço externo", EN: "An external service failed"},
+	Indisponivel:        {PtBR: "Serviço indisponível no momento", EN: "Service temporarily unavailable"},
+	TempoEsgotado:       {PtBR: "A requisição excedeu o tempo limite", EN: "The request timed out"},
+
+	// entrada inválida
+	JSONInvalido: {PtBR: "JSON inválido", EN: "Invalid JSON"},
+	IDInvalido:   {PtBR: "ID inválido", EN: "Invalid ID"},
+	Validacao:    {PtBR: "Dados inválidos", EN: "Invalid data"},
+
+	// domínio
+	CredenciaisInvalidas:         {PtBR: "Credenciais inválidas", EN: "Invalid credentials"},
+	EmailJaCadastrado:            {PtBR: "E-mail já cadastrado", EN: "E-mail already registered"},
+	CaptchaInvalido:              {PtBR: "Verificação de captcha falhou", EN: "Captcha verification failed"},
+	UsuarioNaoEncontrado:         {PtBR: "Usuário não encontrado", EN: "User not found"},
+	ContaBloqueada:               {PtBR: "Conta bloqueada", EN: "Account locked"},
+	PropriaConta:                 {PtBR: "Operação não permitida na própria conta", EN: "Operation not allowed on your own account"},
+	DonoComMembros:               {PtBR: "O dono da organização ainda tem membros", EN: "The organization owner still has members"},
+	PersonificacaoInvalida:       {PtBR: "Personificação inválida", EN: "Invalid impersonation"},
+	PersonificacaoNaoPermitida:   {PtBR: "Personificação não permitida", EN: "Impersonation not allowed"},
+	SessaoInvalida:               {PtBR: "Sessão inválida ou expirada", EN: "Invalid or expired session"},
+	SessaoNaoEncontrada:          {PtBR: "Sessão não encontrada", EN: "Session not found"},
+	SessaoObrigatoria:            {PtBR: "Esta rota exige a sessão do login", EN: "This route requires a login session"},
+	SenhaJaDefinida:              {PtBR: "A conta já tem senha", EN: "The account already has a password"},
+	ContaSemSenha:                {PtBR: "A conta não tem senha", EN: "The account has no password"},
+	GoogleContaDiferente:         {PtBR: "A conta Google é de outro e-mail", EN: "The Google account belongs to a different e-mail"},
+	GoogleJaVinculado:            {PtBR: "Conta Google já vinculada", EN: "Google account already linked"},
+	UsuarioSemOrganizacao:        {PtBR: "Usuário sem organização", EN: "User has no organization"},
+	UsuarioJaPertenceOrg:         {PtBR: "Usuário já pertence a uma organização", EN: "User already belongs to an organization"},
+	MembroNaoEncontrado:          {PtBR: "Membro não encontrado", EN: "Member not found"},
+	PapelInvalido:                {PtBR: "Papel inválido", EN: "Invalid role"},
+	ConviteInvalido:              {PtBR: "Convite inválido ou expirado", EN: "Invalid or expired invitation"},
+	ConviteOutroEmail:            {PtBR: "O convite é para outro e-mail", EN: "The invitation is for a different e-mail"},
+	EstudanteNaoEncontrado:       {PtBR: "Estudante não encontrado", EN: "Student not found"},
+	EstudanteCPFDuplicado:        {PtBR: "CPF já cadastrado", EN: "CPF already registered"},
+	EstudanteEmailDuplicado:      {PtBR: "E-mail já cadastrado para outro estudante", EN: "E-mail already registered for another student"},
+	EstudanteStatusInvalido:      {PtBR: "Status de estudante inválido", EN: "Invalid student status"},
+	TransicaoStatus:              {PtBR: "Transição de status não permitida", EN: "Status transition not allowed"},
+	TransferenciaMesmaTurma:      {PtBR: "O estudante já está nesta turma", EN: "The student is already in this class"},
+	AnoNaoEncontrado:             {PtBR: "Ano/Turma não encontrado", EN: "Year/class not found"},
+	AnoNomeDuplicado:             {PtBR: "Já existe um ano/turma com esse nome", EN: "A year/class with this name already exists"},
+	AnoComEstudantes:             {PtBR: "O ano/turma ainda tem estudantes", EN: "The year/class still has students"},
+	PeriodoNaoEncontrado:         {PtBR: "Período letivo não encontrado", EN: "Academic term not found"},
+	PeriodoNomeDuplicado:         {PtBR: "Já existe um período letivo com esse nome", EN: "An academic term with this name already exists"},
+	PeriodoFechado:               {PtBR: "Período letivo fechado", EN: "Academic term is closed"},
+	ResponsavelNaoEncontrado:     {PtBR: "Responsável não encontrado", EN: "Guardian not found"},
+	AvaliacaoNaoEncontrada:       {PtBR: "Avaliação não encontrada", EN: "Assessment not found"},
+	DocumentoNaoEncontrado:       {PtBR: "Documento não encontrado", EN: "Document not found"},
+	ExportacaoNaoEncontrada:      {PtBR: "Exportação não encontrada", EN: "Export not found"},
+	WebhookNaoEncontrado:         {PtBR: "Webhook não encontrado", EN: "Webhook not found"},
+	ComunicadoNaoEncontrado:      {PtBR: "Comunicado não encontrado", EN: "Announcement not found"},
+	ConsentimentoNaoEncontrado:   {PtBR: "Consentimento não encontrado ou já revogado", EN: "Consent not found or already revoked"},
+	ConsentimentoAtivo:           {PtBR: "Já existe um consentimento ativo para essa finalidade", EN: "There is already an active consent for this purpose"},
+	NotificacaoNaoEncontrada:     {PtBR: "Notificação não encontrada", EN: "Notification not found"},
+	FiltroNaoEncontrado:          {PtBR: "Filtro não encontrado", EN: "Filter not found"},
+	FiltroNomeDuplicado:          {PtBR: "Já existe um filtro com esse nome", EN: "A filter with this name already exists"},
+	ItemLixeiraNaoEncontrado:     {PtBR: "Item não encontrado na lixeira", EN: "Item not found in the trash"},
+	LixeiraAnoExcluido:           {PtBR: "O ano/turma do estudante está na lixeira", EN: "The student's year/class is in the trash"},
+	ConfirmacaoInvalida:          {PtBR: "Confirmação inválida", EN: "Invalid confirmation"},
+	RegistroDuplicado:            {PtBR: "Registro duplicado", EN: "Duplicate record"},
+	ArquivoInvalido:              {PtBR: "Arquivo inválido", EN: "Invalid file"},
+	ArquivoInfectado:             {PtBR: "Arquivo recusado pela verificação antivírus", EN: "File rejected by the antivirus scan"},
+	GoogleTokenInvalido:          {PtBR: "Token do Google inválido", EN: "Invalid Google token"},
+	GoogleNaoConfigurado:         {PtBR: "Login com Google não configurado", EN: "Google sign-in is not configured"},
+	GoogleIndisponivel:           {PtBR: "Login com Google indisponível no momento", EN: "Google sign-in is temporarily unavailable"},
+	GoogleEscopoInsuficiente:     {PtBR: "Permissões do Google insuficientes", EN: "Insufficient Google permissions"},
+	ClassroomCursoNaoEncontrado:  {PtBR: "Curso do Classroom não encontrado", EN: "Classroom course not found"},
+	EducacensoPendencias:         {PtBR: "Há estudantes com campos obrigatórios do Educacenso pendentes", EN: "Some students are missing fields required by Educacenso"},
+	LinkInvalido:                 {PtBR: "Link inválido ou expirado", EN: "Invalid or expired link"},
+	PrecondicaoObrigatoria:       {PtBR: "Cabeçalho If-Match obrigatório", EN: "If-Match header is required"},
+	VersaoDivergente:             {PtBR: "O registro foi alterado por outra requisição", EN: "The record was changed by another request"},
+	IdempotenciaChaveInvalida:    {PtBR: "Idempotency-Key inválida", EN: "Invalid Idempotency-Key"},
+	IdempotenciaChaveReutilizada: {PtBR: "Idempotency-Key reutilizada com outro corpo", EN: "Idempotency-Key reused with a different body"},
+	IdempotenciaEmAndamento:      {PtBR: "Requisição com esta Idempotency-Key ainda em andamento", EN: "A request with this Idempotency-Key is still in progress"},
+	EndpointNaoEncontrado:        {PtBR: "Endpoint não encontrado", EN: "Endpoint not found"},
+}
+
+/// ============ Funções Públicas ============
+
+// Mensagem devolve a mensagem de code no idioma: em pt-BR, msg (ou o texto do
+// catálogo se msg for vazia); nos demais, a tradução do código (msg se não houver).
+func Mensagem(idioma, code, msg string) string {
+	t, ok := mensagens[code]
+	switch {
+	case !ok:
+		return msg
+	case idioma == i18n.PtBR && msg != "":
+		return msg
+	}
+	return t.Em(idioma)
+}
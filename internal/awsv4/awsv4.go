@@ -0,0 +1,207 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/awsv4/awsv4.go
+/// Responsabilidade: Assinatura AWS Signature Version 4 (cabeçalho Authorization e URLs pré-assinadas) para serviços compatíveis com S3.
+/// Dependências principais: crypto/hmac, crypto/sha256, net/http, net/url.
+/// Pontos de atenção:
+/// - Implementação mínima, suficiente para S3/MinIO; não cobre chunked signing (aws-chunked).
+/// - O relógio local é usado no carimbo X-Amz-Date; servidores rejeitam diferenças > 15 min.
+/// - O path da URL deve chegar já "escapado" por segmento (PathEscape); aqui ele é re-codificado no padrão AWS.
+*/
+
+package awsv4
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// Credentials reúne os dados necessários para assinar uma requisição.
+type Credentials struct {
+	AccessKey string // AWS_ACCESS_KEY_ID (ou equivalente do provedor)
+	SecretKey string // AWS_SECRET_ACCESS_KEY
+	Region    string // ex.: "us-east-1"
+	Service   string // ex.: "s3"
+}
+
+/// ============ Configurações & Constantes ============
+
+const (
+	algorithm = "AWS4-HMAC-SHA256"
+	amzDate   = "20060102T150405Z"
+	shortDate = "20060102"
+
+	// UnsignedPayload dispensa o hash do corpo (aceito pelo S3 sobre HTTPS).
+	UnsignedPayload = "UNSIGNED-PAYLOAD"
+)
+
+/// ============ Funções Públicas ============
+
+// HashHex retorna o SHA-256 em hexadecimal (usado em x-amz-content-sha256).
+func HashHex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// SignRequest assina a requisição in-place adicionando X-Amz-Date,
+// X-Amz-Content-Sha256 e Authorization.
+// Parâmetros:
+//   - req: requisição já montada (Host/URL definitivos)
+//   - payloadHash: HashHex(corpo) ou UnsignedPayload
+//   - c: credenciais/região/serviço
+//   - t: instante da assinatura (normalmente time.Now())
+func SignRequest(req *http.Request, payloadHash string, c Credentials, t time.Time) {
+	t = t.UTC()
+	req.Header.Set("X-Amz-Date", t.Format(amzDate))
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	host := req.URL.Host
+	names := []string{"host"}
+	values := map[string]string{"host": host}
+	for k, v := range req.Header {
+		lk := strings.ToLower(k)
+		if lk == "content-type" || strings.HasPrefix(lk, "x-amz-") {
+			names = append(names, lk)
+			values[lk] = strings.TrimSpace(strings.Join(v, ","))
+		}
+	}
+	sort.Strings(names)
+
+	var canonHeaders strings.Builder
+	for _, n := range names {
+		canonHeaders.WriteString(n + ":" + values[n] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonical := strings.Join([]string{
+		req.Method,
+		canonicalURI(req.URL),
+		canonicalQuery(req.URL.Query()),
+		canonHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := scopeOf(c, t)
+	sig := signature(c, t, stringToSign(t, scope, canonical))
+	req.Header.Set("Authorization",
+		algorithm+" Credential="+c.AccessKey+"/"+scope+
+			", SignedHeaders="+signedHeaders+", Signature="+sig)
+}
+
+// PresignURL gera uma URL pré-assinada (query string) válida por expires.
+// Apenas o cabeçalho "host" é assinado; o corpo não é considerado.
+func PresignURL(method string, u *url.URL, c Credentials, t time.Time, expires time.Duration) string {
+	t = t.UTC()
+	scope := scopeOf(c, t)
+
+	q := u.Query()
+	q.Set("X-Amz-Algorithm", algorithm)
+	q.Set("X-Amz-Credential", c.AccessKey+"/"+scope)
+	q.Set("X-Amz-Date", t.Format(amzDate))
+	q.Set("X-Amz-Expires", strconv.Itoa(int(expires.Seconds())))
+	q.Set("X-Amz-SignedHeaders", "host")
+
+	canonical := strings.Join([]string{
+		method,
+		canonicalURI(u),
+		canonicalQuery(q),
+		"host:" + u.Host + "\n",
+		"host",
+		UnsignedPayload,
+	}, "\n")
+
+	sig := signature(c, t, stringToSign(t, scope, canonical))
+
+	out := *u
+	out.RawQuery = canonicalQuery(q) + "&X-Amz-Signature=" + sig
+	return out.String()
+}
+
+/// ============ Funções Internas (helpers) ============
+
+// scopeOf monta o escopo da credencial: data/região/serviço/aws4_request.
+func scopeOf(c Credentials, t time.Time) string {
+	return t.Format(shortDate) + "/" + c.Region + "/" + c.Service + "/aws4_request"
+}
+
+// stringToSign compõe o texto final assinado a partir da requisição canônica.
+func stringToSign(t time.Time, scope, canonical string) string {
+	return algorithm + "\n" + t.Format(amzDate) + "\n" + scope + "\n" + HashHex([]byte(canonical))
+}
+
+// signature deriva a chave de assinatura e retorna o HMAC final em hexadecimal.
+func signature(c Credentials, t time.Time, toSign string) string {
+	k := hmacSHA256([]byte("AWS4"+c.SecretKey), t.Format(shortDate))
+	k = hmacSHA256(k, c.Region)
+	k = hmacSHA256(k, c.Service)
+	k = hmacSHA256(k, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(k, toSign))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	m := hmac.New(sha256.New, key)
+	m.Write([]byte(data))
+	return m.Sum(nil)
+}
+
+// canonicalURI re-codifica cada segmento do path no padrão AWS (RFC 3986).
+func canonicalURI(u *url.URL) string {
+	p := u.EscapedPath()
+	if p == "" {
+		return "/"
+	}
+	segs := strings.Split(p, "/")
+	for i, s := range segs {
+		if dec, err := url.PathUnescape(s); err == nil {
+			s = dec
+		}
+		segs[i] = escape(s)
+	}
+	return strings.Join(segs, "/")
+}
+
+// canonicalQuery ordena e codifica os parâmetros da query string.
+func canonicalQuery(q url.Values) string {
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		vals := append([]string(nil), q[k]...)
+		sort.Strings(vals)
+		for _, v := range vals {
+			parts = append(parts, escape(k)+"="+escape(v))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// escape aplica URI-encoding estrito (mantém apenas A-Z a-z 0-9 - _ . ~).
+func escape(s string) string {
+	const hexDigits = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteByte('%')
+		b.WriteByte(hexDigits[c>>4])
+		b.WriteByte(hexDigits[c&15])
+	}
+	return b.String()
+}
@@ -0,0 +1,100 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/cache/cache.go
+/// Responsabilidade: Abstração de cache chave → bytes com TTL e backends intercambiáveis (memória LRU por processo ou Redis compartilhado).
+/// Dependências principais: context, encoding/json, time, backend/internal/config, backend/internal/logging; implementações em memoria.go e redis.go.
+/// Pontos de atenção:
+/// - O backend é escolhido por CACHE_DRIVER ("memoria" padrão, ou "redis" com REDIS_URL), lido em config.Carregar.
+/// - Cache é otimização: ObterJSON/GuardarJSON registram falhas (Redis fora do ar) e seguem como "miss"; nunca derrubam a requisição.
+/// - Com "memoria" e várias instâncias, cada uma tem o próprio cache: invalidações só valem localmente e o TTL limita a defasagem.
+*/
+
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"backend/internal/config"
+	"backend/internal/logging"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// Cache define o contrato mínimo de um cache com expiração.
+type Cache interface {
+	// Get devolve o valor da chave; ok=false quando não existe ou expirou.
+	Get(ctx context.Context, chave string) (valor []byte, ok bool, err error)
+	// Set grava (ou sobrescreve) a chave, válida por ttl.
+	Set(ctx context.Context, chave string, valor []byte, ttl time.Duration) error
+	// Delete remove as chaves; chave inexistente não é erro.
+	Delete(ctx context.Context, chaves ...string) error
+}
+
+// Checker é implementado pelos backends que sabem verificar a própria saúde (usado por /readyz).
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+/// ============ Inicialização/Bootstrap ============
+
+// New instancia o backend escolhido em cfg.Driver ("memoria" ou "redis").
+// Os valores vêm de config.Carregar (CACHE_*, REDIS_URL).
+func New(cfg config.Cache) (Cache, error) {
+	switch cfg.Driver {
+	case "memoria":
+		return NewMemoria(cfg.MaxItens), nil
+	case "redis":
+		return NewRedis(cfg.RedisURL)
+	default:
+		return nil, fmt.Errorf("CACHE_DRIVER desconhecido: %q", cfg.Driver)
+	}
+}
+
+/// ============ Funções Públicas ============
+
+// ObterJSON lê a chave e decodifica em v. false = miss (inclusive erro do backend, que é registrado).
+func ObterJSON(ctx context.Context, c Cache, chave string, v any) bool {
+	if c == nil {
+		return false
+	}
+	b, ok, err := c.Get(ctx, chave)
+	if err != nil {
+		logging.De(ctx).Warn("cache: leitura", "chave", chave, "erro", err)
+		return false
+	}
+	if !ok {
+		return false
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		logging.De(ctx).Warn("cache: valor ilegível", "chave", chave, "erro", err)
+		return false
+	}
+	return true
+}
+
+// GuardarJSON codifica v e grava na chave; falhas são só registradas.
+func GuardarJSON(ctx context.Context, c Cache, chave string, v any, ttl time.Duration) {
+	if c == nil || ttl <= 0 {
+		return
+	}
+	b, err := json.Marshal(v)
+	if err == nil {
+		err = c.Set(ctx, chave, b, ttl)
+	}
+	if err != nil {
+		logging.De(ctx).Warn("cache: gravação", "chave", chave, "erro", err)
+	}
+}
+
+// Invalidar remove as chaves; falhas são só registradas (o TTL cobre o restante).
+func Invalidar(ctx context.Context, c Cache, chaves ...string) {
+	if c == nil || len(chaves) == 0 {
+		return
+	}
+	if err := c.Delete(ctx, chaves...); err != nil {
+		logging.De(ctx).Warn("cache: invalidação", "chaves", chaves, "erro", err)
+	}
+}
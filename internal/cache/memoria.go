@@ -0,0 +1,105 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/cache/memoria.go
+/// Responsabilidade: Backend de cache em memória do processo, LRU com limite de itens e expiração por item.
+/// Dependências principais: container/list, sync, time.
+/// Pontos de atenção:
+/// - Ao passar de maxItens, descarta o item usado há mais tempo; itens expirados saem na leitura.
+/// - Os bytes gravados são copiados (o chamador pode reutilizar o slice).
+*/
+
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// Memoria é um cache LRU local, seguro para uso concorrente.
+type Memoria struct {
+	max   int
+	mu    sync.Mutex
+	ordem *list.List               // frente = usado mais recentemente
+	itens map[string]*list.Element // chave → elemento de ordem (valor *itemMemoria)
+}
+
+type itemMemoria struct {
+	chave  string
+	valor  []byte
+	expira time.Time
+}
+
+/// ============ Configurações & Constantes ============
+
+// MaxItensPadrao é o limite usado quando NewMemoria recebe max <= 0.
+const MaxItensPadrao = 10000
+
+/// ============ Inicialização/Bootstrap ============
+
+// NewMemoria cria o cache com até max itens.
+func NewMemoria(max int) *Memoria {
+	if max <= 0 {
+		max = MaxItensPadrao
+	}
+	return &Memoria{max: max, ordem: list.New(), itens: make(map[string]*list.Element)}
+}
+
+/// ============ Funções Públicas ============
+
+// Get devolve o valor se existir e não tiver expirado (e o marca como usado).
+func (m *Memoria) Get(_ context.Context, chave string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	el, ok := m.itens[chave]
+	if !ok {
+		return nil, false, nil
+	}
+	it := el.Value.(*itemMemoria)
+	if !time.Now().Before(it.expira) {
+		m.remover(el)
+		return nil, false, nil
+	}
+	m.ordem.MoveToFront(el)
+	return it.valor, true, nil
+}
+
+// Set grava a chave com validade ttl, descartando o item menos usado se passar do limite.
+func (m *Memoria) Set(_ context.Context, chave string, valor []byte, ttl time.Duration) error {
+	it := &itemMemoria{chave: chave, valor: append([]byte(nil), valor...), expira: time.Now().Add(ttl)}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if el, ok := m.itens[chave]; ok {
+		el.Value = it
+		m.ordem.MoveToFront(el)
+		return nil
+	}
+	m.itens[chave] = m.ordem.PushFront(it)
+	for m.ordem.Len() > m.max {
+		m.remover(m.ordem.Back())
+	}
+	return nil
+}
+
+// Delete remove as chaves.
+func (m *Memoria) Delete(_ context.Context, chaves ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, chave := range chaves {
+		if el, ok := m.itens[chave]; ok {
+			m.remover(el)
+		}
+	}
+	return nil
+}
+
+/// ============ Funções Internas (helpers) ============
+
+// remover tira o elemento da lista e do mapa (chamado com mu travado).
+func (m *Memoria) remover(el *list.Element) {
+	m.ordem.Remove(el)
+	delete(m.itens, el.Value.(*itemMemoria).chave)
+}
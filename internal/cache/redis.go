@@ -0,0 +1,267 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/cache/redis.go
+/// Responsabilidade: Backend de cache em Redis (compartilhado entre instâncias), com cliente RESP mínimo e pool de conexões.
+/// Dependências principais: bufio, crypto/tls, net, net/url, strconv, sync.
+/// Pontos de atenção:
+/// - Sem biblioteca externa: implementa só os comandos usados (AUTH, SELECT, GET, SET PX, DEL, PING).
+/// - REDIS_URL: redis://[usuario:senha@]host:porta[/db] (rediss:// usa TLS).
+/// - Conexão com erro de rede/protocolo é descartada; as demais voltam ao pool (até maxOciosas).
+/// - O prazo de cada comando é o do context (ou redisTimeout, o que vier antes).
+*/
+
+package cache
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// Redis guarda os valores num servidor Redis.
+type Redis struct {
+	endereco string
+	tls      *tls.Config // nil = sem TLS
+	usuario  string
+	senha    string
+	banco    int
+	ociosas  chan *conexaoRedis
+	discar   func(ctx context.Context) (net.Conn, error) // abre a conexão (TCP ou TLS); trocado nos testes
+}
+
+type conexaoRedis struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+// erroRedis é uma resposta de erro do servidor (-ERR ...): a conexão continua utilizável.
+type erroRedis string
+
+func (e erroRedis) Error() string { return "redis: " + string(e) }
+
+/// ============ Configurações & Constantes ============
+
+const (
+	redisTimeout    = 2 * time.Second
+	redisMaxOciosas = 8
+)
+
+var errProtocoloRedis = errors.New("redis: resposta inesperada")
+
+/// ============ Inicialização/Bootstrap ============
+
+// NewRedis interpreta a REDIS_URL; a conexão é aberta sob demanda.
+func NewRedis(rawURL string) (*Redis, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "redis" && u.Scheme != "rediss") || u.Host == "" {
+		return nil, fmt.Errorf("REDIS_URL inválida (use redis://[usuario:senha@]host:porta[/db])")
+	}
+	r := &Redis{endereco: u.Host, ociosas: make(chan *conexaoRedis, redisMaxOciosas)}
+	if u.Port() == "" {
+		r.endereco = net.JoinHostPort(u.Hostname(), "6379")
+	}
+	if u.Scheme == "rediss" {
+		r.tls = &tls.Config{ServerName: u.Hostname(), MinVersion: tls.VersionTLS12}
+	}
+	if u.User != nil {
+		r.usuario = u.User.Username()
+		r.senha, _ = u.User.Password()
+		if r.senha == "" { // redis://senha@host
+			r.usuario, r.senha = "", r.usuario
+		}
+	}
+	if db := strings.Trim(u.Path, "/"); db != "" {
+		if r.banco, err = strconv.Atoi(db); err != nil || r.banco < 0 {
+			return nil, fmt.Errorf("REDIS_URL: banco inválido %q", db)
+		}
+	}
+	r.discar = func(ctx context.Context) (net.Conn, error) {
+		if r.tls != nil {
+			return (&tls.Dialer{Config: r.tls}).DialContext(ctx, "tcp", r.endereco)
+		}
+		return (&net.Dialer{}).DialContext(ctx, "tcp", r.endereco)
+	}
+	return r, nil
+}
+
+/// ============ Funções Públicas ============
+
+// Get executa GET.
+func (r *Redis) Get(ctx context.Context, chave string) ([]byte, bool, error) {
+	v, err := r.comando(ctx, "GET", chave)
+	if err != nil {
+		return nil, false, err
+	}
+	if v == nil {
+		return nil, false, nil
+	}
+	b, ok := v.([]byte)
+	if !ok {
+		return nil, false, errProtocoloRedis
+	}
+	return b, true, nil
+}
+
+// Set executa SET chave valor PX ttl.
+func (r *Redis) Set(ctx context.Context, chave string, valor []byte, ttl time.Duration) error {
+	ms := ttl.Milliseconds()
+	if ms <= 0 {
+		ms = 1
+	}
+	_, err := r.comando(ctx, "SET", chave, string(valor), "PX", strconv.FormatInt(ms, 10))
+	return err
+}
+
+// Delete executa DEL.
+func (r *Redis) Delete(ctx context.Context, chaves ...string) error {
+	if len(chaves) == 0 {
+		return nil
+	}
+	_, err := r.comando(ctx, append([]string{"DEL"}, chaves...)...)
+	return err
+}
+
+// Check executa PING (usado por /readyz).
+func (r *Redis) Check(ctx context.Context) error {
+	_, err := r.comando(ctx, "PING")
+	return err
+}
+
+/// ============ Funções Internas (helpers) ============
+
+// comando envia args e lê a resposta, devolvendo a conexão ao pool quando ela segue íntegra.
+func (r *Redis) comando(ctx context.Context, args ...string) (any, error) {
+	c, err := r.conexao(ctx)
+	if err != nil {
+		return nil, err
+	}
+	prazo := time.Now().Add(redisTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(prazo) {
+		prazo = d
+	}
+	_ = c.SetDeadline(prazo)
+
+	v, err := c.executar(args...)
+	var er erroRedis
+	if err != nil && !errors.As(err, &er) {
+		_ = c.Close()
+		return nil, err
+	}
+	select {
+	case r.ociosas <- c:
+	default:
+		_ = c.Close()
+	}
+	return v, err
+}
+
+// conexao pega uma conexão ociosa ou abre outra (com AUTH/SELECT).
+func (r *Redis) conexao(ctx context.Context) (*conexaoRedis, error) {
+	select {
+	case c := <-r.ociosas:
+		return c, nil
+	default:
+	}
+	ctx, cancel := context.WithTimeout(ctx, redisTimeout)
+	defer cancel()
+	nc, err := r.discar(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c := &conexaoRedis{Conn: nc, r: bufio.NewReader(nc)}
+	_ = c.SetDeadline(time.Now().Add(redisTimeout))
+
+	var iniciais [][]string
+	switch {
+	case r.senha != "" && r.usuario != "":
+		iniciais = append(iniciais, []string{"AUTH", r.usuario, r.senha})
+	case r.senha != "":
+		iniciais = append(iniciais, []string{"AUTH", r.senha})
+	}
+	if r.banco != 0 {
+		iniciais = append(iniciais, []string{"SELECT", strconv.Itoa(r.banco)})
+	}
+	for _, args := range iniciais {
+		if _, err := c.executar(args...); err != nil {
+			_ = c.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// executar escreve o comando no formato RESP (array de bulk strings) e lê uma resposta.
+func (c *conexaoRedis) executar(args ...string) (any, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, a := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(a), a)
+	}
+	if _, err := io.WriteString(c.Conn, b.String()); err != nil {
+		return nil, err
+	}
+	return c.ler()
+}
+
+// ler interpreta uma resposta RESP2: string simples, erro, inteiro, bulk (nil se -1) ou array.
+func (c *conexaoRedis) ler() (any, error) {
+	linha, err := c.r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(linha) < 3 || !strings.HasSuffix(linha, "\r\n") {
+		return nil, errProtocoloRedis
+	}
+	tipo, corpo := linha[0], linha[1:len(linha)-2]
+	switch tipo {
+	case '+':
+		return corpo, nil
+	case '-':
+		return nil, erroRedis(corpo)
+	case ':':
+		return strconv.ParseInt(corpo, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(corpo)
+		if err != nil || n < -1 {
+			return nil, errProtocoloRedis
+		}
+		if n == -1 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(c.r, buf); err != nil {
+			return nil, err
+		}
+		if buf[n] != '\r' || buf[n+1] != '\n' {
+			return nil, errProtocoloRedis
+		}
+		return buf[:n], nil
+	case '*':
+		n, err := strconv.Atoi(corpo)
+		if err != nil || n < -1 {
+			return nil, errProtocoloRedis
+		}
+		if n == -1 {
+			return nil, nil
+		}
+		itens := make([]any, n)
+		for i := range itens {
+			if itens[i], err = c.ler(); err != nil {
+				return nil, err
+			}
+		}
+		return itens, nil
+	default:
+		return nil, errProtocoloRedis
+	}
+}
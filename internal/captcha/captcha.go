@@ -0,0 +1,123 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/captcha/captcha.go
+/// Responsabilidade: Verificação no servidor do token de captcha (hCaptcha ou reCAPTCHA) enviado pelo frontend nas rotas públicas de cadastro.
+/// Dependências principais: context, encoding/json, net/http, net/url, backend/internal/config.
+/// Pontos de atenção:
+/// - Os dois provedores usam o mesmo protocolo "siteverify" (POST form secret/response/remoteip → {"success": bool}); só o endpoint muda.
+/// - CAPTCHA_DRIVER=off (padrão) ou CAPTCHA_SKIP=true (só em development) desligam a verificação: Verificar aceita qualquer token.
+/// - Falha do provedor (rede, 5xx, JSON inválido) é erro distinto de ErrRecusado: o handler responde 502 em vez de culpar o usuário.
+/// - Tokens são de uso único e expiram em ~2 min no provedor; o frontend gera um novo a cada tentativa.
+*/
+
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"backend/internal/config"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// Verificador confere tokens de captcha no provedor configurado.
+type Verificador struct {
+	driver   string // off | hcaptcha | recaptcha
+	segredo  string
+	endpoint string
+	client   *http.Client
+}
+
+// respostaSiteverify é o corpo devolvido pelos dois provedores.
+type respostaSiteverify struct {
+	Success    bool     `json:"success"`
+	ErrorCodes []string `json:"error-codes"`
+}
+
+/// ============ Configurações & Constantes ============
+
+// endpoints de verificação por driver
+var endpoints = map[string]string{
+	"hcaptcha":  "https://api.hcaptcha.com/siteverify",
+	"recaptcha": "https://www.google.com/recaptcha/api/siteverify",
+}
+
+// timeout da chamada ao provedor
+const verificacaoTimeout = 10 * time.Second
+
+var (
+	ErrTokenAusente = errors.New("captcha não informado")
+	ErrRecusado     = errors.New("captcha inválido ou expirado")
+)
+
+/// ============ Inicialização/Bootstrap ============
+
+// Novo cria o verificador de cfg (CAPTCHA_*; já validado por config.Carregar).
+// Com CAPTCHA_SKIP, o verificador fica desligado mesmo com driver configurado.
+func Novo(cfg config.Captcha) *Verificador {
+	driver := cfg.Driver
+	if cfg.Pular {
+		driver = "off"
+	}
+	return &Verificador{
+		driver:   driver,
+		segredo:  cfg.Segredo,
+		endpoint: endpoints[driver],
+		client:   &http.Client{Timeout: verificacaoTimeout},
+	}
+}
+
+/// ============ Funções Públicas ============
+
+// Driver informa o provedor em uso ("off" quando desligado), para logs de inicialização.
+func (v *Verificador) Driver() string { return v.driver }
+
+// Ativo indica se Verificar consulta o provedor.
+func (v *Verificador) Ativo() bool { return v.driver != "off" }
+
+// Verificar confere token (e o IP do cliente, se informado) no provedor.
+// ErrTokenAusente ou ErrRecusado quando o captcha não vale; outros erros são falhas do provedor.
+func (v *Verificador) Verificar(ctx context.Context, token, ip string) error {
+	if !v.Ativo() {
+		return nil
+	}
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return ErrTokenAusente
+	}
+
+	form := url.Values{"secret": {v.segredo}, "response": {token}}
+	if ip != "" {
+		form.Set("remoteip", ip)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", v.driver, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: status %d", v.driver, resp.StatusCode)
+	}
+
+	var r respostaSiteverify
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&r); err != nil {
+		return fmt.Errorf("%s: resposta inválida: %w", v.driver, err)
+	}
+	if !r.Success {
+		return fmt.Errorf("%w (%s)", ErrRecusado, strings.Join(r.ErrorCodes, ", "))
+	}
+	return nil
+}
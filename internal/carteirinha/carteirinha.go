@@ -0,0 +1,135 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/carteirinha/carteirinha.go
+/// Responsabilidade: Geração da carteirinha do estudante (PDF no tamanho de cartão CR80) com foto, nome, turma e QR code; e do QR code avulso em PNG.
+/// Dependências principais: github.com/skip2/go-qrcode, image, backend/internal/pdf.
+/// Pontos de atenção:
+/// - Uma página do tamanho do cartão, escrita com o pacote pdf (fontes padrão Helvetica): caracteres fora do Latin-1 viram "?".
+/// - O QR code é desenhado em vetores (retângulos), nítido em qualquer impressora; a foto entra como JPEG (DCTDecode) recortada em 3:4.
+/// - Nomes longos quebram em até duas linhas pela largura real da Helvetica-Bold; o restante é truncado com "...".
+*/
+
+package carteirinha
+
+import (
+	"fmt"
+	"image"
+	"time"
+
+	"backend/internal/pdf"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// Dados é o conteúdo impresso na carteirinha.
+type Dados struct {
+	Escola    string      // organização (ou nome da conta)
+	Nome      string      // nome do estudante
+	Turma     string      // nome do ano/turma
+	Matricula int         // id do estudante
+	EmitidaEm time.Time   // data impressa
+	Foto      image.Image // nil = quadro "sem foto"
+	Codigo    string      // conteúdo assinado do QR code
+}
+
+/// ============ Configurações & Constantes ============
+
+// Dimensões em pontos (1/72"): cartão CR80, 85,6 × 54 mm.
+const (
+	larguraPt = 242.65
+	alturaPt  = 153.07
+
+	margemPt   = 10.0
+	cabecalhoH = 30.0
+	fotoX      = margemPt
+	fotoY      = 20.0
+	fotoW      = 60.0
+	fotoH      = 80.0
+	textoX     = fotoX + fotoW + 8
+	qrLado     = 66.0
+
+	fotoMaxW = 240 // pixels da foto embutida (3:4)
+	fotoMaxH = 320
+)
+
+// cor do cabeçalho (RGB 0–1)
+const corCabecalho = "0.11 0.30 0.60"
+
+/// ============ Funções Públicas ============
+
+// PNG devolve só o QR code (para modelos de carteirinha próprios da escola).
+func PNG(codigo string, tamanho int) ([]byte, error) {
+	return qrcode.Encode(codigo, qrcode.Medium, tamanho)
+}
+
+// PDF monta a carteirinha em uma página do tamanho do cartão.
+func PDF(d Dados) ([]byte, error) {
+	qr, err := qrcode.New(d.Codigo, qrcode.Medium)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := pdf.Novo(larguraPt, alturaPt)
+	c := doc.NovaPagina()
+	// cabeçalho
+	c.Comando("%s rg 0 %.2f %.2f %.2f re f", corCabecalho, alturaPt-cabecalhoH, larguraPt, cabecalhoH)
+	c.Texto(pdf.FonteNegrito, 10, margemPt, alturaPt-15, "1 g", pdf.Truncar(d.Escola, 10, larguraPt-2*margemPt))
+	c.Texto(pdf.FonteRegular, 7, margemPt, alturaPt-25, "1 g", "Carteirinha de estudante")
+
+	// foto (ou moldura vazia)
+	if d.Foto != nil {
+		foto, err := pdf.FotoJPEG(d.Foto, fotoMaxW, fotoMaxH)
+		if err != nil {
+			return nil, err
+		}
+		c.Imagem(doc.ImagemJPEG(foto, fotoMaxW, fotoMaxH), fotoX, fotoY, fotoW, fotoH)
+	} else {
+		c.Comando("0.6 G 0.5 w %.2f %.2f %.2f %.2f re S", fotoX, fotoY, fotoW, fotoH)
+		c.Texto(pdf.FonteRegular, 7, fotoX+16, fotoY+fotoH/2-2, "0.5 g", "sem foto")
+	}
+
+	// nome (até duas linhas), turma, matrícula e emissão
+	y := fotoY + fotoH - 8
+	for _, linha := range pdf.QuebrarLinhas(d.Nome, 9, larguraPt-margemPt-textoX, 2) {
+		c.Texto(pdf.FonteNegrito, 9, textoX, y, "0 g", linha)
+		y -= 11
+	}
+	larguraColuna := larguraPt - margemPt - qrLado - textoX - 4
+	c.Texto(pdf.FonteRegular, 8, textoX, fotoY+34, "0 g", pdf.Truncar("Turma: "+d.Turma, 8, larguraColuna))
+	c.Texto(pdf.FonteRegular, 8, textoX, fotoY+23, "0 g", fmt.Sprintf("Matrícula nº %d", d.Matricula))
+	c.Texto(pdf.FonteRegular, 6, textoX, fotoY+12, "0.35 g", "Emitida em "+d.EmitidaEm.Format("02/01/2006"))
+	c.Texto(pdf.FonteRegular, 6, textoX, fotoY+3, "0.35 g", "Verifique pelo QR code")
+
+	// QR code (módulos escuros agrupados em faixas horizontais)
+	desenharQR(c, qr.Bitmap(), larguraPt-margemPt-qrLado, margemPt-4, qrLado)
+
+	return doc.Bytes(), nil
+}
+
+/// ============ Funções Internas (helpers) ============
+
+// desenharQR pinta os módulos escuros do bitmap dentro de um quadrado de lado pt.
+func desenharQR(c *pdf.Pagina, bm [][]bool, x, y, lado float64) {
+	n := len(bm)
+	if n == 0 {
+		return
+	}
+	m := lado / float64(n)
+	c.Comando("0 g")
+	for lin, fileira := range bm {
+		for col := 0; col < n; {
+			if !fileira[col] {
+				col++
+				continue
+			}
+			ini := col
+			for col < n && fileira[col] {
+				col++
+			}
+			c.Comando("%.3f %.3f %.3f %.3f re", x+float64(ini)*m, y+float64(n-1-lin)*m, float64(col-ini)*m, m)
+		}
+	}
+	c.Comando("f")
+}
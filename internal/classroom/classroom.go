@@ -0,0 +1,225 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/classroom/classroom.go
+/// Responsabilidade: Cliente mínimo da API REST do Google Classroom (turmas do professor e lista de alunos) a partir de um access token OAuth obtido pelo front-end.
+/// Dependências principais: net/http, encoding/json, net/url.
+/// Pontos de atenção:
+/// - O consentimento acontece no navegador (Google Identity Services, token client); o backend só recebe o access token e não o guarda.
+/// - Escopos necessários: classroom.courses.readonly, classroom.rosters.readonly e classroom.profile.emails (sem este, o e-mail vem vazio).
+/// - VerificarToken confere no tokeninfo que o token foi emitido para GOOGLE_CLIENT_ID (evita aceitar token de outro app).
+/// - Listagens são paginadas (pageToken) e limitadas a classroomMaxPaginas para não prender a requisição.
+*/
+
+package classroom
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// Cliente chama a API do Classroom em nome do usuário dono do access token.
+type Cliente struct {
+	ClientID     string // GOOGLE_CLIENT_ID (aud/azp esperado no token)
+	APIURL       string // base da API (padrão classroomAPIURL)
+	TokenInfoURL string // endpoint tokeninfo (padrão tokenInfoURL)
+	HTTP         *http.Client
+}
+
+// Curso é uma turma do Classroom em que o usuário leciona.
+type Curso struct {
+	ID    string `json:"id"`
+	Nome  string `json:"nome"`
+	Secao string `json:"secao,omitempty"`
+	Sala  string `json:"sala,omitempty"`
+}
+
+// Aluno é um estudante matriculado no curso.
+type Aluno struct {
+	GoogleID string `json:"google_id"`
+	Nome     string `json:"nome"`
+	Email    string `json:"email"` // vazio sem o escopo classroom.profile.emails
+}
+
+/// ============ Configurações & Constantes ============
+
+const (
+	classroomAPIURL = "https://classroom.googleapis.com/v1"
+	tokenInfoURL    = "https://oauth2.googleapis.com/tokeninfo"
+
+	// escopo mínimo para ler a lista de alunos
+	escopoRoster = "https://www.googleapis.com/auth/classroom.rosters.readonly"
+
+	classroomTimeout     = 15 * time.Second
+	classroomPageSize    = 100
+	classroomMaxPaginas  = 20 // até 2000 itens por listagem
+	classroomMaxResposta = 4 << 20
+)
+
+var (
+	ErrTokenInvalido      = errors.New("access token do Google inválido, expirado ou emitido para outro aplicativo")
+	ErrEscopoInsuficiente = errors.New("access token sem o escopo classroom.rosters.readonly")
+	ErrCursoNaoEncontrado = errors.New("curso não encontrado no Google Classroom (ou sem acesso)")
+	ErrRespostaInesperada = errors.New("resposta inesperada do Google Classroom")
+	ErrClientIDAusente    = errors.New("GOOGLE_CLIENT_ID não configurado")
+	ErrMuitosItens        = errors.New("listagem do Google Classroom com mais de 2000 itens")
+)
+
+/// ============ Inicialização/Bootstrap ============
+
+// Novo cria o cliente com os endpoints públicos do Google.
+func Novo(clientID string) *Cliente {
+	return &Cliente{
+		ClientID:     strings.TrimSpace(clientID),
+		APIURL:       classroomAPIURL,
+		TokenInfoURL: tokenInfoURL,
+		HTTP:         &http.Client{Timeout: classroomTimeout},
+	}
+}
+
+/// ============ Funções Públicas ============
+
+// VerificarToken confere no tokeninfo que o token é válido, foi emitido para
+// ClientID e tem o escopo de leitura da lista de alunos.
+func (c *Cliente) VerificarToken(ctx context.Context, token string) error {
+	if c.ClientID == "" {
+		return ErrClientIDAusente
+	}
+	var info struct {
+		Aud   string `json:"aud"`
+		Azp   string `json:"azp"`
+		Scope string `json:"scope"`
+	}
+	u := c.TokenInfoURL + "?" + url.Values{"access_token": {token}}.Encode()
+	if err := c.getJSON(ctx, u, "", &info); err != nil {
+		if errors.Is(err, ErrRespostaInesperada) {
+			return ErrTokenInvalido // tokeninfo responde 400 para token inválido/expirado
+		}
+		return err
+	}
+	if info.Aud != c.ClientID && info.Azp != c.ClientID {
+		return ErrTokenInvalido
+	}
+	for _, s := range strings.Fields(info.Scope) {
+		if s == escopoRoster {
+			return nil
+		}
+	}
+	return ErrEscopoInsuficiente
+}
+
+// Cursos lista os cursos ativos em que o dono do token é professor.
+func (c *Cliente) Cursos(ctx context.Context, token string) ([]Curso, error) {
+	out := []Curso{}
+	err := c.paginar(ctx, token, "/courses", url.Values{"teacherId": {"me"}, "courseStates": {"ACTIVE"}}, func(corpo json.RawMessage) (string, error) {
+		var pagina struct {
+			Courses []struct {
+				ID      string `json:"id"`
+				Name    string `json:"name"`
+				Section string `json:"section"`
+				Room    string `json:"room"`
+			} `json:"courses"`
+			NextPageToken string `json:"nextPageToken"`
+		}
+		if err := json.Unmarshal(corpo, &pagina); err != nil {
+			return "", ErrRespostaInesperada
+		}
+		for _, cr := range pagina.Courses {
+			out = append(out, Curso{ID: cr.ID, Nome: cr.Name, Secao: cr.Section, Sala: cr.Room})
+		}
+		return pagina.NextPageToken, nil
+	})
+	return out, err
+}
+
+// Alunos lista os alunos do curso (nome completo e e-mail).
+func (c *Cliente) Alunos(ctx context.Context, token, cursoID string) ([]Aluno, error) {
+	out := []Aluno{}
+	caminho := "/courses/" + url.PathEscape(cursoID) + "/students"
+	err := c.paginar(ctx, token, caminho, url.Values{}, func(corpo json.RawMessage) (string, error) {
+		var pagina struct {
+			Students []struct {
+				UserID  string `json:"userId"`
+				Profile struct {
+					Name struct {
+						FullName string `json:"fullName"`
+					} `json:"name"`
+					EmailAddress string `json:"emailAddress"`
+				} `json:"profile"`
+			} `json:"students"`
+			NextPageToken string `json:"nextPageToken"`
+		}
+		if err := json.Unmarshal(corpo, &pagina); err != nil {
+			return "", ErrRespostaInesperada
+		}
+		for _, s := range pagina.Students {
+			out = append(out, Aluno{
+				GoogleID: s.UserID,
+				Nome:     strings.TrimSpace(s.Profile.Name.FullName),
+				Email:    strings.ToLower(strings.TrimSpace(s.Profile.EmailAddress)),
+			})
+		}
+		return pagina.NextPageToken, nil
+	})
+	return out, err
+}
+
+/// ============ Funções Internas (helpers) ============
+
+// paginar percorre as páginas de uma listagem; ler devolve o nextPageToken.
+func (c *Cliente) paginar(ctx context.Context, token, caminho string, q url.Values, ler func(json.RawMessage) (string, error)) error {
+	q.Set("pageSize", fmt.Sprint(classroomPageSize))
+	for i := 0; i < classroomMaxPaginas; i++ {
+		var corpo json.RawMessage
+		if err := c.getJSON(ctx, c.APIURL+caminho+"?"+q.Encode(), token, &corpo); err != nil {
+			return err
+		}
+		prox, err := ler(corpo)
+		if err != nil || prox == "" {
+			return err
+		}
+		q.Set("pageToken", prox)
+	}
+	return ErrMuitosItens
+}
+
+// getJSON faz o GET (com Bearer quando token != "") e decodifica a resposta 200.
+func (c *Cliente) getJSON(ctx context.Context, u, token string, destino any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return fmt.Errorf("classroom: %w", err)
+	}
+	defer resp.Body.Close()
+	corpo, err := io.ReadAll(io.LimitReader(resp.Body, classroomMaxResposta))
+	if err != nil {
+		return fmt.Errorf("classroom: %w", err)
+	}
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		return ErrTokenInvalido
+	case token != "" && (resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusNotFound):
+		return ErrCursoNaoEncontrado // 403 = curso de outro professor
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("%w (status %d)", ErrRespostaInesperada, resp.StatusCode)
+	}
+	if err := json.Unmarshal(corpo, destino); err != nil {
+		return ErrRespostaInesperada
+	}
+	return nil
+}
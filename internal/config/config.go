@@ -0,0 +1,882 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/config/config.go
+/// Responsabilidade: Configuração tipada do backend: lê, valida e documenta todas as variáveis de ambiente num único lugar.
+/// Dependências principais: os, strconv, time, encoding/base64.
+/// Pontos de atenção:
+/// - A tabela Variaveis é a fonte única de nomes, padrões e descrições (`backend config` imprime a documentação).
+/// - Carregar não para no primeiro erro: junta todos os problemas num *ErroConfig para o processo falhar com um relatório completo.
+/// - Pacote folha: não importa pacotes locais (storage, cripto, middleware e handler dependem dele, não o contrário).
+/// - Valores secretos (Secreta=true) nunca aparecem em Resumo.
+*/
+
+package config
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// Config agrupa toda a configuração lida do ambiente.
+type Config struct {
+	Ambiente       string // APP_ENV (production | development)
+	Porta          string // PORT
+	DatabaseURL    string // DATABASE_URL
+	DatabaseURLRO  string // DATABASE_URL_RO (réplica de leitura; vazio = só o primário)
+	AppURL         string // APP_URL (sem "/" final)
+	GoogleClientID string // GOOGLE_CLIENT_ID
+	GmailCanonico  bool   // EMAIL_GMAIL_CANONICAL (ver model.NormalizarEmail)
+	LoginLegado    bool   // AUTH_LEGACY_RESPONSE (formato antigo da resposta de /login e /login/google)
+
+	ExigirSessao bool          // AUTH_REQUIRE_SESSION (X-User-Email sem sessão deixa de autenticar)
+	SessaoTTL    time.Duration // AUTH_SESSION_TTL (validade das sessões emitidas no login)
+
+	BloquearDescartaveis bool     // EMAIL_BLOCK_DISPOSABLE (ver model.ValidarDominioEmail)
+	DominiosBloqueados   []string // EMAIL_BLOCKED_DOMAINS
+
+	Log          Log
+	DB           DB
+	HTTP         HTTP
+	TLS          TLS
+	CORS         CORS
+	Seguranca    Seguranca
+	Migrate      Migrate
+	Storage      Storage
+	Cache        Cache
+	UploadsGC    UploadsGC
+	Webhooks     Webhooks
+	Aniversarios Aniversarios
+	Email        Email
+	PII          PII
+	Carteirinha  Carteirinha
+	Compartilhar Compartilhar
+	Captcha      Captcha
+	Google       Google
+	Antivirus    Antivirus
+}
+
+// Producao indica APP_ENV=production (rotas de desenvolvimento desligadas).
+func (c *Config) Producao() bool { return c.Ambiente == "production" }
+
+// Log configura o logging estruturado (slog).
+type Log struct {
+	Nivel   string // debug | info | warn | error
+	Formato string // json | text
+}
+
+// DB configura o pool de conexões e os prazos das consultas.
+type DB struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration // ociosa há mais tempo que isso é fechada (0 = nunca)
+
+	TimeoutLeitura   time.Duration // GET/HEAD nos handlers
+	TimeoutEscrita   time.Duration // demais métodos
+	TimeoutRelatorio time.Duration // relatórios, exportações e GraphQL
+	StatementTimeout time.Duration // statement_timeout da sessão no Postgres; 0 = sem limite
+}
+
+// HTTP configura os timeouts do servidor e o limite de corpo das rotas JSON.
+type HTTP struct {
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	ShutdownTimeout   time.Duration
+	MaxBodyBytes      int64 // corpo máximo das rotas JSON (uploads têm limite próprio)
+	ValidarContrato   bool  // confere as respostas contra o OpenAPI (testes/desenvolvimento)
+	ConfiarProxy      bool  // IP do cliente pelo X-Forwarded-For (API atrás de proxy reverso)
+	SobreporMetodo    bool  // aceita X-HTTP-Method-Override em POST (proxies que bloqueiam PUT/DELETE)
+
+	// Prazo total por requisição (504 ao vencer); 0 = sem prazo
+	PrazoLeitura time.Duration // GET/HEAD
+	PrazoEscrita time.Duration // demais métodos
+	PrazoLongo   time.Duration // importações, relatórios, GraphQL, uploads e exportações
+}
+
+// TLS configura a terminação TLS no próprio servidor (sem proxy reverso na frente).
+// Sem certificado nem domínios de autocert, o servidor fala HTTP puro.
+type TLS struct {
+	CertFile         string
+	KeyFile          string
+	AutocertDomains  []string // Let's Encrypt (ACME); exclusivo com CertFile/KeyFile
+	AutocertCacheDir string
+	AutocertEmail    string
+	RedirectPorta    string // porta HTTP que redireciona para HTTPS; "0" = desligado
+}
+
+// Ativo indica se o servidor deve servir HTTPS.
+func (t TLS) Ativo() bool { return t.CertFile != "" || len(t.AutocertDomains) > 0 }
+
+// Google configura a validação dos ID Tokens do login Google (chaves públicas buscadas na rede).
+type Google struct {
+	TimeoutToken    time.Duration // prazo da validação, separado dos prazos de banco
+	FalhasDisjuntor int           // falhas seguidas do Google que abrem o disjuntor
+	PausaDisjuntor  time.Duration // tempo aberto antes de tentar de novo
+}
+
+// CORS configura o middleware de CORS.
+type CORS struct {
+	AllowOrigins     []string // "*" ou lista de origens
+	AllowMethods     string
+	AllowHeaders     string
+	ExposeHeaders    string // cabeçalhos de resposta legíveis pelo JS (ETag, X-Request-ID...)
+	MaxAge           int    // segundos
+	AllowCredentials bool
+}
+
+// Seguranca configura os cabeçalhos de segurança de todas as respostas
+// (securityHeadersMiddleware em cmd/server/main.go). Texto vazio = cabeçalho não enviado.
+type Seguranca struct {
+	CSP               string        // Content-Security-Policy
+	HSTSMaxAge        time.Duration // Strict-Transport-Security (só em HTTPS); 0 = não envia
+	HSTSSubdominios   bool          // includeSubDomains
+	HSTSPreload       bool          // preload (exige includeSubDomains e 1 ano ou mais)
+	ReferrerPolicy    string
+	PermissionsPolicy string
+}
+
+// Migrate controla a aplicação automática das migrations.
+type Migrate struct {
+	OnStart bool
+	Timeout time.Duration
+}
+
+// Storage escolhe e configura o backend de uploads.
+type Storage struct {
+	Driver            string // local | s3
+	UploadsDir        string
+	UploadsPublicPath string
+	SigningKey        string
+	S3                S3
+}
+
+// S3 configura o backend S3-compatível.
+type S3 struct {
+	Endpoint       string
+	Region         string
+	Bucket         string
+	AccessKey      string
+	SecretKey      string
+	ForcePathStyle bool
+}
+
+// Cache escolhe o backend de cache e as validades das consultas mais frequentes.
+type Cache struct {
+	Driver    string // memoria | redis
+	RedisURL  string
+	MaxItens  int           // limite do backend memoria
+	TTLAcesso time.Duration // e-mail → usuário/tenant/papel
+	TTLAnos   time.Duration // GET /api/anos (com as contagens do dashboard)
+}
+
+// UploadsGC configura a limpeza periódica de uploads órfãos.
+type UploadsGC struct {
+	Interval time.Duration // 0 = desligado
+	Grace    time.Duration
+	DryRun   bool
+}
+
+// Webhooks configura a entrega da fila de webhooks.
+type Webhooks struct {
+	Interval time.Duration // 0 = entrega desligada (eventos continuam enfileirados)
+	Timeout  time.Duration // por requisição ao receptor
+}
+
+// Aniversarios configura o job de aniversários (notificação do dia e resumo semanal por e-mail).
+type Aniversarios struct {
+	Interval time.Duration // 0 = desligado
+}
+
+// Email escolhe e configura o provedor de e-mails (backend/internal/notificador).
+type Email struct {
+	Driver   string // log | smtp | sendgrid | ses ("auto" resolvido em Carregar)
+	From     string // remetente
+	SMTP     SMTP
+	SendGrid SendGrid
+	SES      SES
+}
+
+// SMTP configura o driver smtp.
+type SMTP struct {
+	Host string
+	Port string
+	User string
+	Pass string
+}
+
+// SendGrid configura o driver sendgrid (API v3).
+type SendGrid struct {
+	APIKey string
+}
+
+// SES configura o driver ses (Amazon SES v2, assinatura SigV4).
+type SES struct {
+	Region    string
+	AccessKey string
+	SecretKey string
+	Endpoint  string // vazio = https://email.{região}.amazonaws.com
+}
+
+// PII guarda as chaves (já decodificadas) da criptografia de CPF/telefone.
+type PII struct {
+	Key      []byte            // nil = sem criptografia
+	KeyID    string            // id da chave atual
+	OldKeys  map[string][]byte // id → chave antiga (somente leitura)
+	IndexKey []byte            // segredo do índice cego (opcional)
+}
+
+// Carteirinha configura a assinatura do QR code das carteirinhas de estudante.
+type Carteirinha struct {
+	Key []byte // segredo HMAC (nil = carteirinhas desligadas)
+}
+
+// Compartilhar configura a assinatura dos links públicos de listas de turma.
+type Compartilhar struct {
+	Key []byte // segredo HMAC (nil = compartilhamento desligado)
+}
+
+// Captcha configura a verificação anti-robô do cadastro (backend/internal/captcha).
+type Captcha struct {
+	Driver  string // off | hcaptcha | recaptcha
+	Segredo string // chave secreta do provedor (verificação no servidor)
+	Pular   bool   // CAPTCHA_SKIP: aceita sem verificar (só em development)
+}
+
+// Antivirus configura a verificação dos uploads (backend/internal/antivirus).
+type Antivirus struct {
+	Driver      string        // off | clamd
+	Endereco    string        // host:porta do clamd (TCP)
+	Timeout     time.Duration // prazo de cada verificação
+	FalhaAberta bool          // antivírus fora do ar: aceita o arquivo (padrão: recusa com 503)
+}
+
+// Variavel documenta uma variável de ambiente suportada.
+type Variavel struct {
+	Nome        string
+	Padrao      string
+	Descricao   string
+	Obrigatoria bool
+	Secreta     bool
+}
+
+// ErroConfig reúne todos os problemas encontrados ao carregar a configuração.
+type ErroConfig struct {
+	Problemas []string
+}
+
+/// ============ Configurações & Constantes ============
+
+// Variaveis lista todas as variáveis de ambiente lidas pelo backend.
+var Variaveis = []Variavel{
+	{Nome: "DATABASE_URL", Descricao: "string de conexão do Postgres", Obrigatoria: true, Secreta: true},
+	{Nome: "DATABASE_URL_RO", Descricao: "réplica de leitura para listagens e relatórios; vazio = tudo no primário", Secreta: true},
+	{Nome: "APP_ENV", Padrao: "production", Descricao: `"production" ou "development" (libera POST /api/dev/seed)`},
+	{Nome: "PORT", Padrao: "8080", Descricao: "porta HTTP"},
+	{Nome: "APP_URL", Padrao: "http://localhost:3000", Descricao: "URL pública do frontend (links enviados por e-mail)"},
+	{Nome: "EMAIL_GMAIL_CANONICAL", Padrao: "false", Descricao: "compara contas Gmail sem pontos/+tag na parte local (só em bases novas)"},
+	{Nome: "EMAIL_BLOCK_DISPOSABLE", Padrao: "true", Descricao: "recusa cadastros com e-mail de domínio descartável (lista embutida)"},
+	{Nome: "EMAIL_BLOCKED_DOMAINS", Descricao: "domínios extras recusados no cadastro (vírgula; subdomínios também)"},
+	{Nome: "AUTH_LEGACY_RESPONSE", Padrao: "false", Descricao: "true devolve o formato antigo em /login e /login/google (sem tutorial_visto/fotoUrl); só durante a transição do frontend"},
+	{Nome: "AUTH_REQUIRE_SESSION", Padrao: "false", Descricao: "true exige o token de sessão do login (Authorization: Bearer); X-User-Email sozinho deixa de autenticar. Desligado, encerrar uma sessão não desconecta o dispositivo"},
+	{Nome: "AUTH_SESSION_TTL", Padrao: "720h", Descricao: "validade das sessões emitidas no login (GET /api/usuario/sessoes)"},
+	{Nome: "GOOGLE_CLIENT_ID", Descricao: "Client ID OAuth do Google (login GIS); vazio desativa /login/google"},
+	{Nome: "GOOGLE_TOKEN_TIMEOUT", Padrao: "5s", Descricao: "prazo para validar o ID Token (inclui buscar as chaves públicas do Google)"},
+	{Nome: "GOOGLE_BREAKER_FAILURES", Padrao: "5", Descricao: "falhas seguidas ao buscar as chaves do Google que abrem o disjuntor (503 imediato)"},
+	{Nome: "GOOGLE_BREAKER_COOLDOWN", Padrao: "30s", Descricao: "tempo com o disjuntor aberto antes de tentar o Google de novo"},
+
+	{Nome: "LOG_LEVEL", Padrao: "info", Descricao: "nível mínimo de log: debug, info, warn ou error"},
+	{Nome: "LOG_FORMAT", Padrao: "json", Descricao: `formato dos logs: "json" ou "text"`},
+
+	{Nome: "DB_MAX_OPEN_CONNS", Padrao: "20", Descricao: "máximo de conexões abertas por pool (primário e réplica, por instância)"},
+	{Nome: "DB_MAX_IDLE_CONNS", Padrao: "10", Descricao: "máximo de conexões ociosas"},
+	{Nome: "DB_CONN_MAX_LIFETIME", Padrao: "30m", Descricao: "tempo de vida de uma conexão"},
+	{Nome: "DB_CONN_MAX_IDLE_TIME", Padrao: "5m", Descricao: "conexão ociosa por mais tempo é fechada (libera o Postgres fora do pico); 0 = nunca"},
+	{Nome: "DB_TIMEOUT_READ", Padrao: "5s", Descricao: "prazo das consultas de leitura (GET) nos handlers"},
+	{Nome: "DB_TIMEOUT_WRITE", Padrao: "5s", Descricao: "prazo das operações de escrita nos handlers"},
+	{Nome: "DB_TIMEOUT_REPORT", Padrao: "30s", Descricao: "prazo de relatórios, exportações e consultas GraphQL"},
+	{Nome: "DB_STATEMENT_TIMEOUT", Padrao: "2m", Descricao: "statement_timeout da sessão no Postgres (rede de segurança); 0 = sem limite"},
+
+	{Nome: "HTTP_READ_TIMEOUT", Padrao: "10s", Descricao: "timeout de leitura da requisição"},
+	{Nome: "HTTP_READ_HEADER_TIMEOUT", Padrao: "5s", Descricao: "timeout de leitura dos cabeçalhos"},
+	{Nome: "HTTP_WRITE_TIMEOUT", Padrao: "15s", Descricao: "timeout de escrita da resposta"},
+	{Nome: "HTTP_IDLE_TIMEOUT", Padrao: "60s", Descricao: "timeout de conexões keep-alive ociosas"},
+	{Nome: "HTTP_SHUTDOWN_TIMEOUT", Padrao: "10s", Descricao: "espera máxima no desligamento gracioso"},
+	{Nome: "HTTP_MAX_BODY_BYTES", Padrao: "1048576", Descricao: "tamanho máximo (bytes) do corpo JSON; acima disso 413"},
+	{Nome: "HTTP_TRUST_PROXY", Padrao: "false", Descricao: "true atrás de proxy reverso: IP do cliente (histórico de logins) vem do X-Forwarded-For"},
+	{Nome: "HTTP_REQUEST_TIMEOUT_READ", Padrao: "10s", Descricao: "prazo total de requisições GET/HEAD (504 ao vencer); 0 = sem prazo"},
+	{Nome: "HTTP_REQUEST_TIMEOUT_WRITE", Padrao: "15s", Descricao: "prazo total de requisições de escrita (POST/PUT/PATCH/DELETE); 0 = sem prazo"},
+	{Nome: "HTTP_REQUEST_TIMEOUT_LONG", Padrao: "60s", Descricao: "prazo total de importações, relatórios, GraphQL, uploads e exportações; 0 = sem prazo"},
+	{Nome: "HTTP_METHOD_OVERRIDE", Padrao: "false", Descricao: "true aceita X-HTTP-Method-Override (PUT, PATCH, DELETE) em POST, para proxies que bloqueiam esses métodos"},
+	{Nome: "OPENAPI_VALIDATE_RESPONSES", Padrao: "false", Descricao: "loga respostas fora do esquema OpenAPI (testes de contrato; proibido em produção)"},
+
+	{Nome: "TLS_CERT_FILE", Descricao: "certificado PEM (cadeia completa); com TLS_KEY_FILE, serve HTTPS/HTTP2 na PORT"},
+	{Nome: "TLS_KEY_FILE", Descricao: "chave privada PEM do certificado"},
+	{Nome: "TLS_AUTOCERT_DOMAINS", Descricao: "domínios (vírgula) com certificado automático Let's Encrypt; vazio = desligado"},
+	{Nome: "TLS_AUTOCERT_CACHE_DIR", Padrao: "./certs", Descricao: "diretório onde o autocert guarda conta e certificados"},
+	{Nome: "TLS_AUTOCERT_EMAIL", Descricao: "e-mail de contato da conta ACME (avisos de expiração)"},
+	{Nome: "TLS_REDIRECT_PORT", Padrao: "80", Descricao: "porta HTTP que redireciona para HTTPS (e atende o desafio ACME); 0 = desligado"},
+
+	{Nome: "CORS_ALLOW_ORIGINS", Padrao: "*", Descricao: `origens permitidas ("*" ou lista separada por vírgula; aceita "https://*.dominio")`},
+	{Nome: "CORS_ALLOW_METHODS", Padrao: "GET, POST, PUT, DELETE, OPTIONS", Descricao: "métodos permitidos"},
+	{Nome: "CORS_ALLOW_HEADERS", Padrao: "Content-Type, Authorization, X-User-Email, X-Impersonation-Token, Idempotency-Key, If-Match", Descricao: "cabeçalhos permitidos"},
+	{Nome: "CORS_EXPOSE_HEADERS", Padrao: "ETag, X-Request-ID, Idempotent-Replayed", Descricao: "cabeçalhos de resposta expostos ao frontend"},
+	{Nome: "CORS_MAX_AGE", Padrao: "86400", Descricao: "cache do preflight (segundos)"},
+	{Nome: "CORS_ALLOW_CREDENTIALS", Padrao: "false", Descricao: "envia Access-Control-Allow-Credentials (exige origens explícitas)"},
+
+	{Nome: "SECURITY_CSP", Padrao: "default-src 'none'; frame-ancestors 'none'; base-uri 'none'; form-action 'none'", Descricao: `Content-Security-Policy (API sem HTML; /api/docs e o link público de turma definem a própria); "off" = não envia`},
+	{Nome: "SECURITY_HSTS_MAX_AGE", Padrao: "8760h", Descricao: "max-age do Strict-Transport-Security, enviado só em HTTPS (TLS próprio ou X-Forwarded-Proto); 0 = não envia"},
+	{Nome: "SECURITY_HSTS_INCLUDE_SUBDOMAINS", Padrao: "false", Descricao: "acrescenta includeSubDomains ao HSTS"},
+	{Nome: "SECURITY_HSTS_PRELOAD", Padrao: "false", Descricao: "acrescenta preload ao HSTS (exige includeSubDomains e max-age de 1 ano ou mais)"},
+	{Nome: "SECURITY_REFERRER_POLICY", Padrao: "no-referrer", Descricao: `Referrer-Policy; "off" = não envia`},
+	{Nome: "SECURITY_PERMISSIONS_POLICY", Padrao: "accelerometer=(), camera=(), geolocation=(), gyroscope=(), microphone=(), payment=(), usb=()", Descricao: `Permissions-Policy; "off" = não envia`},
+
+	{Nome: "MIGRATE_ON_START", Padrao: "true", Descricao: "aplica migrations pendentes ao iniciar"},
+	{Nome: "MIGRATE_TIMEOUT", Padrao: "5m", Descricao: "tempo máximo para aplicar as migrations"},
+
+	{Nome: "STORAGE_DRIVER", Padrao: "local", Descricao: `backend de uploads: "local" ou "s3"`},
+	{Nome: "UPLOADS_DIR", Padrao: "./uploads", Descricao: "diretório do backend local"},
+	{Nome: "UPLOADS_PUBLIC_PATH", Padrao: "/uploads/", Descricao: "prefixo público das URLs do backend local"},
+	{Nome: "UPLOADS_SIGNING_KEY", Descricao: "segredo HMAC das URLs assinadas do backend local (vazio = aleatório por processo)", Secreta: true},
+	{Nome: "S3_ENDPOINT", Descricao: "endpoint S3-compatível (vazio = AWS na região)"},
+	{Nome: "S3_REGION", Padrao: "us-east-1", Descricao: "região S3"},
+	{Nome: "S3_BUCKET", Descricao: "bucket (obrigatório com STORAGE_DRIVER=s3)"},
+	{Nome: "S3_ACCESS_KEY", Descricao: "access key (obrigatória com STORAGE_DRIVER=s3)", Secreta: true},
+	{Nome: "S3_SECRET_KEY", Descricao: "secret key (obrigatória com STORAGE_DRIVER=s3)", Secreta: true},
+	{Nome: "S3_FORCE_PATH_STYLE", Padrao: "false", Descricao: "usa URLs path-style (MinIO)"},
+
+	{Nome: "CACHE_DRIVER", Padrao: "memoria", Descricao: `cache de consultas: "memoria" (por processo) ou "redis"`},
+	{Nome: "REDIS_URL", Descricao: "redis://[usuario:senha@]host:porta[/db] (obrigatória com CACHE_DRIVER=redis)", Secreta: true},
+	{Nome: "CACHE_MAX_ITENS", Padrao: "10000", Descricao: "máximo de itens do cache em memória (LRU)"},
+	{Nome: "CACHE_TTL_ACESSO", Padrao: "1m", Descricao: "validade da resolução X-User-Email → usuário (0 = sem cache)"},
+	{Nome: "CACHE_TTL_ANOS", Padrao: "30s", Descricao: "validade da lista de anos com contagens (0 = sem cache)"},
+
+	{Nome: "UPLOADS_GC_INTERVAL", Padrao: "0", Descricao: "intervalo da limpeza de uploads órfãos (0 = desligada)"},
+	{Nome: "UPLOADS_GC_GRACE", Padrao: "24h", Descricao: "idade mínima de um upload para ser removido"},
+	{Nome: "UPLOADS_GC_DRY_RUN", Padrao: "false", Descricao: "apenas registra o que seria removido"},
+
+	{Nome: "WEBHOOKS_INTERVAL", Padrao: "10s", Descricao: "intervalo de varredura da fila de webhooks (0 = entrega desligada)"},
+	{Nome: "WEBHOOKS_TIMEOUT", Padrao: "10s", Descricao: "timeout de cada entrega de webhook"},
+
+	{Nome: "ANIVERSARIOS_INTERVAL", Padrao: "1h", Descricao: "intervalo do job de aniversários (notificação do dia e resumo semanal; 0 = desligado)"},
+
+	{Nome: "EMAIL_DRIVER", Padrao: "auto", Descricao: `envio de e-mails: "log", "smtp", "sendgrid", "ses" ou "auto" (smtp com SMTP_HOST, senão log)`},
+	{Nome: "EMAIL_FROM", Descricao: "remetente dos e-mails (vazio = SMTP_FROM)"},
+	{Nome: "SMTP_HOST", Descricao: "servidor SMTP (driver smtp)"},
+	{Nome: "SMTP_PORT", Padrao: "587", Descricao: "porta SMTP"},
+	{Nome: "SMTP_USER", Descricao: "usuário SMTP"},
+	{Nome: "SMTP_PASS", Descricao: "senha SMTP", Secreta: true},
+	{Nome: "SMTP_FROM", Padrao: "no-reply@tecmise.local", Descricao: "remetente (legado; prefira EMAIL_FROM)"},
+	{Nome: "SENDGRID_API_KEY", Descricao: "API key do SendGrid (driver sendgrid)", Secreta: true},
+	{Nome: "SES_REGION", Padrao: "us-east-1", Descricao: "região do Amazon SES (driver ses)"},
+	{Nome: "SES_ACCESS_KEY", Descricao: "access key do SES", Secreta: true},
+	{Nome: "SES_SECRET_KEY", Descricao: "secret key do SES", Secreta: true},
+	{Nome: "SES_ENDPOINT", Descricao: "endpoint do SES (vazio = AWS na região)"},
+
+	{Nome: "PII_KEY", Descricao: "chave AES-256 de CPF/telefone (base64, 32 bytes); vazio = sem criptografia", Secreta: true},
+	{Nome: "PII_KEY_ID", Padrao: "1", Descricao: "id da chave atual"},
+	{Nome: "PII_OLD_KEYS", Descricao: `chaves antigas "id:base64,..." (somente leitura)`, Secreta: true},
+	{Nome: "PII_INDEX_KEY", Descricao: "segredo do índice cego de CPF (base64, >= 16 bytes)", Secreta: true},
+	{Nome: "CARTEIRINHA_KEY", Descricao: "segredo HMAC do QR code das carteirinhas (base64, >= 16 bytes); vazio = desligado", Secreta: true},
+	{Nome: "COMPARTILHAR_KEY", Descricao: "segredo HMAC dos links públicos de turmas (base64, >= 16 bytes); vazio = desligado", Secreta: true},
+
+	{Nome: "CAPTCHA_DRIVER", Padrao: "off", Descricao: `captcha no cadastro (POST /register): "off", "hcaptcha" ou "recaptcha"`},
+	{Nome: "CAPTCHA_SECRET", Descricao: "chave secreta do provedor de captcha (obrigatória com CAPTCHA_DRIVER ligado)", Secreta: true},
+	{Nome: "CAPTCHA_SKIP", Padrao: "false", Descricao: "aceita o cadastro sem verificar o captcha (só com APP_ENV=development)"},
+
+	{Nome: "ANTIVIRUS_DRIVER", Padrao: "off", Descricao: `verificação antivírus de fotos e documentos enviados: "off" ou "clamd"`},
+	{Nome: "CLAMD_ADDR", Padrao: "localhost:3310", Descricao: "endereço TCP (host:porta) do clamd"},
+	{Nome: "ANTIVIRUS_TIMEOUT", Padrao: "10s", Descricao: "prazo de cada verificação antivírus"},
+	{Nome: "ANTIVIRUS_FAIL_OPEN", Padrao: "false", Descricao: "true aceita o arquivo quando o antivírus falhar (padrão: recusa com 503)"},
+}
+
+/// ============ Inicialização/Bootstrap ============
+
+// Carregar lê e valida o ambiente. Em erro devolve *ErroConfig com todos os problemas.
+func Carregar() (*Config, error) {
+	l := newLeitor()
+	c := &Config{
+		Ambiente:       strings.ToLower(l.str("APP_ENV")),
+		Porta:          l.str("PORT"),
+		DatabaseURL:    l.str("DATABASE_URL"),
+		DatabaseURLRO:  l.str("DATABASE_URL_RO"),
+		AppURL:         strings.TrimRight(l.str("APP_URL"), "/"),
+		GoogleClientID: l.str("GOOGLE_CLIENT_ID"),
+		GmailCanonico:  l.booleano("EMAIL_GMAIL_CANONICAL"),
+		LoginLegado:    l.booleano("AUTH_LEGACY_RESPONSE"),
+		ExigirSessao:   l.booleano("AUTH_REQUIRE_SESSION"),
+		SessaoTTL:      l.duracao("AUTH_SESSION_TTL"),
+
+		BloquearDescartaveis: l.booleano("EMAIL_BLOCK_DISPOSABLE"),
+		DominiosBloqueados:   splitCSV(l.str("EMAIL_BLOCKED_DOMAINS")),
+		Log: Log{
+			Nivel:   strings.ToLower(l.str("LOG_LEVEL")),
+			Formato: strings.ToLower(l.str("LOG_FORMAT")),
+		},
+		DB: DB{
+			MaxOpenConns:    l.intPositivo("DB_MAX_OPEN_CONNS"),
+			MaxIdleConns:    l.intPositivo("DB_MAX_IDLE_CONNS"),
+			ConnMaxLifetime: l.duracao("DB_CONN_MAX_LIFETIME"),
+			ConnMaxIdleTime: l.duracao("DB_CONN_MAX_IDLE_TIME"),
+
+			TimeoutLeitura:   l.duracao("DB_TIMEOUT_READ"),
+			TimeoutEscrita:   l.duracao("DB_TIMEOUT_WRITE"),
+			TimeoutRelatorio: l.duracao("DB_TIMEOUT_REPORT"),
+			StatementTimeout: l.duracao("DB_STATEMENT_TIMEOUT"),
+		},
+		HTTP: HTTP{
+			ReadTimeout:       l.duracao("HTTP_READ_TIMEOUT"),
+			ReadHeaderTimeout: l.duracao("HTTP_READ_HEADER_TIMEOUT"),
+			WriteTimeout:      l.duracao("HTTP_WRITE_TIMEOUT"),
+			IdleTimeout:       l.duracao("HTTP_IDLE_TIMEOUT"),
+			ShutdownTimeout:   l.duracao("HTTP_SHUTDOWN_TIMEOUT"),
+			MaxBodyBytes:      int64(l.intPositivo("HTTP_MAX_BODY_BYTES")),
+			ValidarContrato:   l.booleano("OPENAPI_VALIDATE_RESPONSES"),
+			ConfiarProxy:      l.booleano("HTTP_TRUST_PROXY"),
+			SobreporMetodo:    l.booleano("HTTP_METHOD_OVERRIDE"),
+			PrazoLeitura:      l.duracao("HTTP_REQUEST_TIMEOUT_READ"),
+			PrazoEscrita:      l.duracao("HTTP_REQUEST_TIMEOUT_WRITE"),
+			PrazoLongo:        l.duracao("HTTP_REQUEST_TIMEOUT_LONG"),
+		},
+		TLS: TLS{
+			CertFile:         l.str("TLS_CERT_FILE"),
+			KeyFile:          l.str("TLS_KEY_FILE"),
+			AutocertDomains:  splitCSV(l.str("TLS_AUTOCERT_DOMAINS")),
+			AutocertCacheDir: l.str("TLS_AUTOCERT_CACHE_DIR"),
+			AutocertEmail:    l.str("TLS_AUTOCERT_EMAIL"),
+			RedirectPorta:    l.str("TLS_REDIRECT_PORT"),
+		},
+		CORS: CORS{
+			AllowOrigins:     splitCSV(l.str("CORS_ALLOW_ORIGINS")),
+			AllowMethods:     l.str("CORS_ALLOW_METHODS"),
+			AllowHeaders:     l.str("CORS_ALLOW_HEADERS"),
+			ExposeHeaders:    l.str("CORS_EXPOSE_HEADERS"),
+			MaxAge:           l.intPositivo("CORS_MAX_AGE"),
+			AllowCredentials: l.booleano("CORS_ALLOW_CREDENTIALS"),
+		},
+		Seguranca: Seguranca{
+			CSP:               l.desligavel("SECURITY_CSP"),
+			HSTSMaxAge:        l.duracao("SECURITY_HSTS_MAX_AGE"),
+			HSTSSubdominios:   l.booleano("SECURITY_HSTS_INCLUDE_SUBDOMAINS"),
+			HSTSPreload:       l.booleano("SECURITY_HSTS_PRELOAD"),
+			ReferrerPolicy:    l.desligavel("SECURITY_REFERRER_POLICY"),
+			PermissionsPolicy: l.desligavel("SECURITY_PERMISSIONS_POLICY"),
+		},
+		Migrate: Migrate{
+			OnStart: l.booleano("MIGRATE_ON_START"),
+			Timeout: l.duracao("MIGRATE_TIMEOUT"),
+		},
+		Storage: Storage{
+			Driver:            strings.ToLower(l.str("STORAGE_DRIVER")),
+			UploadsDir:        l.str("UPLOADS_DIR"),
+			UploadsPublicPath: l.str("UPLOADS_PUBLIC_PATH"),
+			SigningKey:        l.str("UPLOADS_SIGNING_KEY"),
+			S3: S3{
+				Endpoint:       l.str("S3_ENDPOINT"),
+				Region:         l.str("S3_REGION"),
+				Bucket:         l.str("S3_BUCKET"),
+				AccessKey:      l.str("S3_ACCESS_KEY"),
+				SecretKey:      l.str("S3_SECRET_KEY"),
+				ForcePathStyle: l.booleano("S3_FORCE_PATH_STYLE"),
+			},
+		},
+		Cache: Cache{
+			Driver:    strings.ToLower(l.str("CACHE_DRIVER")),
+			RedisURL:  l.str("REDIS_URL"),
+			MaxItens:  l.intPositivo("CACHE_MAX_ITENS"),
+			TTLAcesso: l.duracao("CACHE_TTL_ACESSO"),
+			TTLAnos:   l.duracao("CACHE_TTL_ANOS"),
+		},
+		UploadsGC: UploadsGC{
+			Interval: l.duracao("UPLOADS_GC_INTERVAL"),
+			Grace:    l.duracao("UPLOADS_GC_GRACE"),
+			DryRun:   l.booleano("UPLOADS_GC_DRY_RUN"),
+		},
+		Webhooks: Webhooks{
+			Interval: l.duracao("WEBHOOKS_INTERVAL"),
+			Timeout:  l.duracao("WEBHOOKS_TIMEOUT"),
+		},
+		Aniversarios: Aniversarios{Interval: l.duracao("ANIVERSARIOS_INTERVAL")},
+		Email: Email{
+			Driver: strings.ToLower(l.str("EMAIL_DRIVER")),
+			From:   l.str("EMAIL_FROM"),
+			SMTP: SMTP{
+				Host: l.str("SMTP_HOST"),
+				Port: l.str("SMTP_PORT"),
+				User: l.str("SMTP_USER"),
+				Pass: l.str("SMTP_PASS"),
+			},
+			SendGrid: SendGrid{APIKey: l.str("SENDGRID_API_KEY")},
+			SES: SES{
+				Region:    l.str("SES_REGION"),
+				AccessKey: l.str("SES_ACCESS_KEY"),
+				SecretKey: l.str("SES_SECRET_KEY"),
+				Endpoint:  l.str("SES_ENDPOINT"),
+			},
+		},
+		PII: PII{
+			Key:      l.base64("PII_KEY", 32, 32),
+			KeyID:    l.str("PII_KEY_ID"),
+			OldKeys:  l.chavesAntigas("PII_OLD_KEYS"),
+			IndexKey: l.base64("PII_INDEX_KEY", 16, 0),
+		},
+		Carteirinha:  Carteirinha{Key: l.base64("CARTEIRINHA_KEY", 16, 0)},
+		Compartilhar: Compartilhar{Key: l.base64("COMPARTILHAR_KEY", 16, 0)},
+		Captcha: Captcha{
+			Driver:  strings.ToLower(l.str("CAPTCHA_DRIVER")),
+			Segredo: l.str("CAPTCHA_SECRET"),
+			Pular:   l.booleano("CAPTCHA_SKIP"),
+		},
+		Google: Google{
+			TimeoutToken:    l.duracao("GOOGLE_TOKEN_TIMEOUT"),
+			FalhasDisjuntor: l.intPositivo("GOOGLE_BREAKER_FAILURES"),
+			PausaDisjuntor:  l.duracao("GOOGLE_BREAKER_COOLDOWN"),
+		},
+		Antivirus: Antivirus{
+			Driver:      strings.ToLower(l.str("ANTIVIRUS_DRIVER")),
+			Endereco:    l.str("CLAMD_ADDR"),
+			Timeout:     l.duracao("ANTIVIRUS_TIMEOUT"),
+			FalhaAberta: l.booleano("ANTIVIRUS_FAIL_OPEN"),
+		},
+	}
+	// Com o override ligado, o pré-flight CORS precisa aceitar o cabeçalho.
+	if c.HTTP.SobreporMetodo && !strings.Contains(strings.ToLower(c.CORS.AllowHeaders), "x-http-method-override") {
+		c.CORS.AllowHeaders += ", X-HTTP-Method-Override"
+	}
+	c.validar(l)
+	if len(l.problemas) > 0 {
+		return nil, &ErroConfig{Problemas: l.problemas}
+	}
+	return c, nil
+}
+
+/// ============ Funções Públicas ============
+
+// Error lista os problemas, um por linha.
+func (e *ErroConfig) Error() string {
+	return "configuração inválida:\n  - " + strings.Join(e.Problemas, "\n  - ")
+}
+
+// Documentacao devolve a tabela de variáveis em texto (nome, padrão, descrição).
+func Documentacao() string {
+	var b strings.Builder
+	for _, v := range Variaveis {
+		padrao := v.Padrao
+		switch {
+		case v.Obrigatoria:
+			padrao = "(obrigatória)"
+		case padrao == "":
+			padrao = "-"
+		}
+		fmt.Fprintf(&b, "%-26s %-34s %s\n", v.Nome, padrao, v.Descricao)
+	}
+	return b.String()
+}
+
+// Resumo lista os valores efetivos do ambiente, ocultando os secretos.
+func Resumo() string {
+	var b strings.Builder
+	for _, v := range Variaveis {
+		valor := strings.TrimSpace(os.Getenv(v.Nome))
+		switch {
+		case valor == "" && v.Padrao == "":
+			valor = "-"
+		case valor == "":
+			valor = v.Padrao + " (padrão)"
+		case v.Secreta:
+			valor = "******"
+		}
+		fmt.Fprintf(&b, "%-26s %s\n", v.Nome, valor)
+	}
+	return b.String()
+}
+
+/// ============ Funções Internas (helpers) ============
+
+// validar aplica as regras que envolvem mais de uma variável.
+func (c *Config) validar(l *leitor) {
+	if c.Ambiente != "production" && c.Ambiente != "development" {
+		l.problema(`APP_ENV inválido: %q ("production" ou "development")`, c.Ambiente)
+	}
+	if c.HTTP.ValidarContrato && c.Producao() {
+		l.problema("OPENAPI_VALIDATE_RESPONSES=true exige APP_ENV=development")
+	}
+	switch c.Log.Nivel {
+	case "debug", "info", "warn", "error":
+	default:
+		l.problema("LOG_LEVEL inválido: %q (debug, info, warn, error)", c.Log.Nivel)
+	}
+	if c.Log.Formato != "json" && c.Log.Formato != "text" {
+		l.problema(`LOG_FORMAT inválido: %q ("json" ou "text")`, c.Log.Formato)
+	}
+	for _, v := range []struct {
+		nome string
+		d    time.Duration
+	}{{"DB_TIMEOUT_READ", c.DB.TimeoutLeitura}, {"DB_TIMEOUT_WRITE", c.DB.TimeoutEscrita}, {"DB_TIMEOUT_REPORT", c.DB.TimeoutRelatorio}, {"GOOGLE_TOKEN_TIMEOUT", c.Google.TimeoutToken}, {"AUTH_SESSION_TTL", c.SessaoTTL}} {
+		if v.d == 0 {
+			l.problema("%s deve ser maior que zero", v.nome)
+		}
+	}
+	// o prazo da requisição inclui o do banco: menor que ele, o 504 esconderia o erro da consulta
+	for _, v := range []struct {
+		req, db      string
+		prazo, banco time.Duration
+	}{
+		{"HTTP_REQUEST_TIMEOUT_READ", "DB_TIMEOUT_READ", c.HTTP.PrazoLeitura, c.DB.TimeoutLeitura},
+		{"HTTP_REQUEST_TIMEOUT_WRITE", "DB_TIMEOUT_WRITE", c.HTTP.PrazoEscrita, c.DB.TimeoutEscrita},
+		{"HTTP_REQUEST_TIMEOUT_LONG", "DB_TIMEOUT_REPORT", c.HTTP.PrazoLongo, c.DB.TimeoutRelatorio},
+	} {
+		if v.prazo > 0 && v.prazo < v.banco {
+			l.problema("%s (%s) menor que %s (%s)", v.req, v.prazo, v.db, v.banco)
+		}
+	}
+	if c.ExigirSessao && c.LoginLegado {
+		l.problema("AUTH_REQUIRE_SESSION=true exige AUTH_LEGACY_RESPONSE=false (o formato antigo do login não traz o token da sessão)")
+	}
+	if c.DB.MaxOpenConns > 0 && c.DB.MaxIdleConns > c.DB.MaxOpenConns {
+		l.problema("DB_MAX_IDLE_CONNS (%d) maior que DB_MAX_OPEN_CONNS (%d)", c.DB.MaxIdleConns, c.DB.MaxOpenConns)
+	}
+	if c.AppURL != "" {
+		if u, err := url.Parse(c.AppURL); err != nil || u.Scheme == "" || u.Host == "" {
+			l.problema("APP_URL inválida: %q", c.AppURL)
+		}
+	}
+	if c.CORS.AllowCredentials && len(c.CORS.AllowOrigins) > 0 && c.CORS.AllowOrigins[0] == "*" {
+		l.problema(`CORS_ALLOW_CREDENTIALS=true exige CORS_ALLOW_ORIGINS explícito (não "*")`)
+	}
+	for _, o := range c.CORS.AllowOrigins {
+		if o == "*" || !strings.Contains(o, "*") {
+			continue
+		}
+		if esquema, dominio, ok := strings.Cut(o, "://*."); !ok || esquema == "" || dominio == "" || strings.ContainsAny(dominio, "*/") {
+			l.problema(`CORS_ALLOW_ORIGINS: curinga inválido %q (use "https://*.dominio.com.br")`, o)
+		}
+	}
+	if c.Seguranca.HSTSPreload && (!c.Seguranca.HSTSSubdominios || c.Seguranca.HSTSMaxAge < 365*24*time.Hour) {
+		l.problema("SECURITY_HSTS_PRELOAD=true exige SECURITY_HSTS_INCLUDE_SUBDOMAINS=true e SECURITY_HSTS_MAX_AGE de 8760h ou mais")
+	}
+	for _, v := range [][2]string{
+		{"SECURITY_CSP", c.Seguranca.CSP},
+		{"SECURITY_REFERRER_POLICY", c.Seguranca.ReferrerPolicy},
+		{"SECURITY_PERMISSIONS_POLICY", c.Seguranca.PermissionsPolicy},
+	} {
+		if strings.ContainsAny(v[1], "\r\n") {
+			l.problema("%s não pode ter quebras de linha", v[0])
+		}
+	}
+	switch c.Storage.Driver {
+	case "local":
+	case "s3":
+		for _, v := range [][2]string{
+			{"S3_BUCKET", c.Storage.S3.Bucket},
+			{"S3_ACCESS_KEY", c.Storage.S3.AccessKey},
+			{"S3_SECRET_KEY", c.Storage.S3.SecretKey},
+		} {
+			if v[1] == "" {
+				l.problema("%s é obrigatória com STORAGE_DRIVER=s3", v[0])
+			}
+		}
+	default:
+		l.problema(`STORAGE_DRIVER desconhecido: %q (use "local" ou "s3")`, c.Storage.Driver)
+	}
+	if (c.TLS.CertFile == "") != (c.TLS.KeyFile == "") {
+		l.problema("TLS_CERT_FILE e TLS_KEY_FILE devem ser definidas juntas")
+	}
+	if c.TLS.CertFile != "" && len(c.TLS.AutocertDomains) > 0 {
+		l.problema("TLS_AUTOCERT_DOMAINS não pode ser usada junto com TLS_CERT_FILE/TLS_KEY_FILE")
+	}
+	if c.TLS.RedirectPorta != "0" {
+		if n, err := strconv.Atoi(c.TLS.RedirectPorta); err != nil || n <= 0 || n > 65535 {
+			l.problema("TLS_REDIRECT_PORT inválida: %q", c.TLS.RedirectPorta)
+		} else if c.TLS.Ativo() && c.TLS.RedirectPorta == c.Porta {
+			l.problema("TLS_REDIRECT_PORT (%s) igual a PORT", c.TLS.RedirectPorta)
+		}
+	}
+	switch c.Cache.Driver {
+	case "memoria":
+	case "redis":
+		if c.Cache.RedisURL == "" {
+			l.problema("REDIS_URL é obrigatória com CACHE_DRIVER=redis")
+		}
+	default:
+		l.problema(`CACHE_DRIVER desconhecido: %q (use "memoria" ou "redis")`, c.Cache.Driver)
+	}
+	if c.Webhooks.Timeout == 0 {
+		l.problema("WEBHOOKS_TIMEOUT deve ser maior que zero")
+	}
+	if c.Email.From == "" {
+		c.Email.From = l.str("SMTP_FROM")
+	}
+	if c.Email.Driver == "auto" {
+		c.Email.Driver = "log"
+		if c.Email.SMTP.Host != "" {
+			c.Email.Driver = "smtp"
+		}
+	}
+	switch c.Email.Driver {
+	case "log":
+	case "smtp":
+		if c.Email.SMTP.Host == "" {
+			l.problema("SMTP_HOST é obrigatória com EMAIL_DRIVER=smtp")
+		}
+	case "sendgrid":
+		if c.Email.SendGrid.APIKey == "" {
+			l.problema("SENDGRID_API_KEY é obrigatória com EMAIL_DRIVER=sendgrid")
+		}
+	case "ses":
+		if c.Email.SES.AccessKey == "" || c.Email.SES.SecretKey == "" {
+			l.problema("SES_ACCESS_KEY e SES_SECRET_KEY são obrigatórias com EMAIL_DRIVER=ses")
+		}
+	default:
+		l.problema(`EMAIL_DRIVER desconhecido: %q (use "auto", "log", "smtp", "sendgrid" ou "ses")`, c.Email.Driver)
+	}
+	switch c.Captcha.Driver {
+	case "off":
+	case "hcaptcha", "recaptcha":
+		if c.Captcha.Segredo == "" {
+			l.problema("CAPTCHA_SECRET é obrigatória com CAPTCHA_DRIVER=%s", c.Captcha.Driver)
+		}
+	default:
+		l.problema(`CAPTCHA_DRIVER desconhecido: %q (use "off", "hcaptcha" ou "recaptcha")`, c.Captcha.Driver)
+	}
+	if c.Captcha.Pular && c.Producao() {
+		l.problema("CAPTCHA_SKIP=true exige APP_ENV=development")
+	}
+	switch c.Antivirus.Driver {
+	case "off":
+	case "clamd":
+		if _, _, err := net.SplitHostPort(c.Antivirus.Endereco); err != nil {
+			l.problema("CLAMD_ADDR inválido: %q (use host:porta)", c.Antivirus.Endereco)
+		}
+		if c.Antivirus.Timeout == 0 {
+			l.problema("ANTIVIRUS_TIMEOUT deve ser maior que zero")
+		}
+	default:
+		l.problema(`ANTIVIRUS_DRIVER desconhecido: %q (use "off" ou "clamd")`, c.Antivirus.Driver)
+	}
+	if c.PII.Key == nil && (len(c.PII.OldKeys) > 0 || c.PII.IndexKey != nil) {
+		l.problema("PII_OLD_KEYS/PII_INDEX_KEY definidas sem PII_KEY")
+	}
+}
+
+// leitor lê variáveis da tabela acumulando problemas.
+type leitor struct {
+	vars      map[string]Variavel
+	problemas []string
+}
+
+func newLeitor() *leitor {
+	l := &leitor{vars: make(map[string]Variavel, len(Variaveis))}
+	for _, v := range Variaveis {
+		l.vars[v.Nome] = v
+	}
+	return l
+}
+
+func (l *leitor) problema(format string, args ...any) {
+	l.problemas = append(l.problemas, fmt.Sprintf(format, args...))
+}
+
+// str devolve o valor (trim) ou o padrão da tabela; registra ausência de obrigatórias.
+func (l *leitor) str(nome string) string {
+	v, ok := l.vars[nome]
+	if !ok {
+		panic("config: variável não documentada " + nome)
+	}
+	if s := strings.TrimSpace(os.Getenv(nome)); s != "" {
+		return s
+	}
+	if v.Obrigatoria {
+		l.problema("%s não definida (%s)", nome, v.Descricao)
+	}
+	return v.Padrao
+}
+
+func (l *leitor) intPositivo(nome string) int {
+	s := l.str(nome)
+	n, err := strconv.Atoi(s)
+	if err != nil || n <= 0 {
+		l.problema("%s deve ser um inteiro positivo (recebido %q)", nome, s)
+	}
+	return n
+}
+
+func (l *leitor) duracao(nome string) time.Duration {
+	s := l.str(nome)
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		l.problema(`%s deve ser uma duração (ex.: "30s", "5m"; recebido %q)`, nome, s)
+	}
+	return d
+}
+
+// desligavel devolve o valor de str, ou "" quando ele for "off" (cabeçalho desligado).
+func (l *leitor) desligavel(nome string) string {
+	if s := l.str(nome); !strings.EqualFold(s, "off") {
+		return s
+	}
+	return ""
+}
+
+func (l *leitor) booleano(nome string) bool {
+	s := l.str(nome)
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		l.problema("%s deve ser true ou false (recebido %q)", nome, s)
+	}
+	return b
+}
+
+// base64 decodifica a variável; minimo/maximo em bytes (maximo 0 = sem limite). Vazia = nil.
+func (l *leitor) base64(nome string, minimo, maximo int) []byte {
+	s := l.str(nome)
+	if s == "" {
+		return nil
+	}
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil || len(b) < minimo || (maximo > 0 && len(b) > maximo) {
+		if maximo == minimo {
+			l.problema("%s deve ser base64 de %d bytes", nome, minimo)
+		} else {
+			l.problema("%s deve ser base64 de no mínimo %d bytes", nome, minimo)
+		}
+		return nil
+	}
+	return b
+}
+
+// chavesAntigas interpreta "id:base64,id:base64".
+func (l *leitor) chavesAntigas(nome string) map[string][]byte {
+	out := map[string][]byte{}
+	for _, par := range splitCSV(l.str(nome)) {
+		id, b64, ok := strings.Cut(par, ":")
+		id = strings.TrimSpace(id)
+		k, err := base64.StdEncoding.DecodeString(strings.TrimSpace(b64))
+		if !ok || id == "" || err != nil || len(k) != 32 {
+			l.problema(`%s: item %q inválido (formato "id:base64" de 32 bytes)`, nome, id)
+			continue
+		}
+		out[id] = k
+	}
+	return out
+}
+
+// splitCSV divide por vírgulas descartando itens vazios.
+func splitCSV(s string) []string {
+	var out []string
+	for _, p := range strings.Split(s, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
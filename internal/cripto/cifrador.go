@@ -0,0 +1,172 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/cripto/cifrador.go
+/// Responsabilidade: Criptografia de campos sensíveis (CPF, telefone) em repouso com AES-256-GCM e índice cego (HMAC) para buscas por igualdade.
+/// Dependências principais: crypto/aes, crypto/cipher, crypto/hmac, crypto/sha256, encoding/base64, backend/internal/config.
+/// Pontos de atenção:
+/// - Formato gravado: "enc:v1:{id_chave}:{base64(nonce|cifra)}"; valores sem o prefixo são tratados como legados (texto puro) e devolvidos como estão.
+/// - Rotação: a chave atual (PII_KEY/PII_KEY_ID) cifra; as antigas (PII_OLD_KEYS) só decifram. Rode "cifrar-pii" para regravar tudo com a atual.
+/// - O índice cego usa PII_INDEX_KEY (ou uma derivação da PII_KEY); ele NÃO pode mudar sem recalcular cpf_hash — defina PII_INDEX_KEY antes de rotacionar.
+/// - Sem PII_KEY, NewFromConfig devolve nil: métodos de um *Cifrador nil não cifram (modo desenvolvimento) e o índice é o próprio valor.
+/// - As chaves podem vir de um KMS/secret manager que injete as variáveis de ambiente; nada é lido do disco.
+*/
+
+package cripto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"backend/internal/config"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// Cifrador cifra/decifra valores com a chave atual e reconhece chaves antigas.
+type Cifrador struct {
+	atual       string
+	aeads       map[string]cipher.AEAD
+	chaveIndice []byte
+}
+
+/// ============ Configurações & Constantes ============
+
+// prefixo dos valores cifrados (versão do formato)
+const prefixo = "enc:v1:"
+
+// tamanho exigido das chaves (AES-256)
+const tamanhoChave = 32
+
+var (
+	ErrChaveInvalida      = errors.New("chave de criptografia inválida (esperado base64 de 32 bytes)")
+	ErrChaveDesconhecida  = errors.New("valor cifrado com chave desconhecida")
+	ErrValorCorrompido    = errors.New("valor cifrado corrompido")
+	ErrIDChaveObrigatorio = errors.New("id da chave é obrigatório e não pode conter ':'")
+)
+
+/// ============ Inicialização/Bootstrap ============
+
+// Novo cria um Cifrador. chaves mapeia id → chave (32 bytes) e deve conter atual.
+// chaveIndice vazia deriva o segredo do índice cego a partir da chave atual.
+func Novo(atual string, chaves map[string][]byte, chaveIndice []byte) (*Cifrador, error) {
+	c := &Cifrador{atual: atual, aeads: make(map[string]cipher.AEAD, len(chaves))}
+	for id, k := range chaves {
+		if id == "" || strings.Contains(id, ":") {
+			return nil, ErrIDChaveObrigatorio
+		}
+		if len(k) != tamanhoChave {
+			return nil, fmt.Errorf("chave %q: %w", id, ErrChaveInvalida)
+		}
+		block, err := aes.NewCipher(k)
+		if err != nil {
+			return nil, err
+		}
+		if c.aeads[id], err = cipher.NewGCM(block); err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := c.aeads[atual]; !ok {
+		return nil, fmt.Errorf("chave atual %q: %w", atual, ErrChaveInvalida)
+	}
+	if len(chaveIndice) == 0 {
+		m := hmac.New(sha256.New, chaves[atual])
+		m.Write([]byte("tecmise/pii/indice"))
+		chaveIndice = m.Sum(nil)
+	}
+	c.chaveIndice = chaveIndice
+	return c, nil
+}
+
+// NewFromConfig monta o Cifrador a partir da configuração. Devolve (nil, nil) sem PII_KEY.
+// As chaves já chegam decodificadas e validadas por config.Carregar.
+func NewFromConfig(c config.PII) (*Cifrador, error) {
+	if c.Key == nil {
+		return nil, nil
+	}
+	chaves := make(map[string][]byte, len(c.OldKeys)+1)
+	for id, k := range c.OldKeys {
+		chaves[id] = k
+	}
+	chaves[c.KeyID] = c.Key
+	return Novo(c.KeyID, chaves, c.IndexKey)
+}
+
+/// ============ Funções Públicas ============
+
+// Ativo informa se há chave configurada (c != nil).
+func (c *Cifrador) Ativo() bool { return c != nil }
+
+// Cifrar devolve v cifrado com a chave atual. Vazio continua vazio.
+func (c *Cifrador) Cifrar(v string) (string, error) {
+	if c == nil || v == "" {
+		return v, nil
+	}
+	aead := c.aeads[c.atual]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	ct := aead.Seal(nonce, nonce, []byte(v), nil)
+	return prefixo + c.atual + ":" + base64.RawStdEncoding.EncodeToString(ct), nil
+}
+
+// Decifrar devolve o texto original. Valores sem prefixo (legados) voltam inalterados.
+func (c *Cifrador) Decifrar(v string) (string, error) {
+	if !Cifrado(v) {
+		return v, nil
+	}
+	if c == nil {
+		return "", ErrChaveDesconhecida
+	}
+	id, b64, ok := strings.Cut(strings.TrimPrefix(v, prefixo), ":")
+	if !ok {
+		return "", ErrValorCorrompido
+	}
+	aead, ok := c.aeads[id]
+	if !ok {
+		return "", ErrChaveDesconhecida
+	}
+	raw, err := base64.RawStdEncoding.DecodeString(b64)
+	if err != nil || len(raw) < aead.NonceSize() {
+		return "", ErrValorCorrompido
+	}
+	pt, err := aead.Open(nil, raw[:aead.NonceSize()], raw[aead.NonceSize():], nil)
+	if err != nil {
+		return "", ErrValorCorrompido
+	}
+	return string(pt), nil
+}
+
+// Indice devolve o índice cego (HMAC-SHA256 hex) de v, para buscas por igualdade.
+// Sem chave configurada, devolve o próprio valor.
+func (c *Cifrador) Indice(v string) string {
+	if c == nil || v == "" {
+		return v
+	}
+	m := hmac.New(sha256.New, c.chaveIndice)
+	m.Write([]byte(v))
+	return hex.EncodeToString(m.Sum(nil))
+}
+
+// Atualizado informa se v já está no formato desejado: cifrado com a chave
+// atual (com Cifrador) ou em texto puro (sem Cifrador). Usado pela migração.
+func (c *Cifrador) Atualizado(v string) bool {
+	if v == "" {
+		return true
+	}
+	if c == nil {
+		return !Cifrado(v)
+	}
+	return strings.HasPrefix(v, prefixo+c.atual+":")
+}
+
+// Cifrado informa se v está no formato cifrado.
+func Cifrado(v string) bool { return strings.HasPrefix(v, prefixo) }
@@ -0,0 +1,169 @@
+package cripto
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+var (
+	chaveA = bytes.Repeat([]byte{0xA1}, tamanhoChave)
+	chaveB = bytes.Repeat([]byte{0xB2}, tamanhoChave)
+)
+
+func novoCifrador(t *testing.T, atual string, chaves map[string][]byte, indice []byte) *Cifrador {
+	t.Helper()
+	c, err := Novo(atual, chaves, indice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestCifrarIdaEVolta(t *testing.T) {
+	c := novoCifrador(t, "k1", map[string][]byte{"k1": chaveA}, nil)
+	for _, v := range []string{"123.456.789-09", "(11) 99999-0000", "çãé 🙂", strings.Repeat("x", 4096)} {
+		cif, err := c.Cifrar(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(cif, "enc:v1:k1:") || strings.Contains(cif, v) {
+			t.Errorf("Cifrar(%q) = %q", v, cif)
+		}
+		got, err := c.Decifrar(cif)
+		if err != nil || got != v {
+			t.Errorf("Decifrar(Cifrar(%q)) = %q, %v", v, got, err)
+		}
+	}
+	// nonce aleatório: o mesmo valor não se repete no banco
+	a, _ := c.Cifrar("123")
+	b, _ := c.Cifrar("123")
+	if a == b {
+		t.Error("dois Cifrar do mesmo valor deram o mesmo texto")
+	}
+	if v, _ := c.Cifrar(""); v != "" {
+		t.Errorf("Cifrar(\"\") = %q, esperado vazio", v)
+	}
+}
+
+func TestDecifrarRejeitaAdulteracao(t *testing.T) {
+	c := novoCifrador(t, "k1", map[string][]byte{"k1": chaveA, "k2": chaveB}, nil)
+	cif, err := c.Cifrar("123.456.789-09")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b64 := strings.TrimPrefix(cif, "enc:v1:k1:")
+	raw, _ := base64.RawStdEncoding.DecodeString(b64)
+
+	invertido := func(i int) string {
+		r := bytes.Clone(raw)
+		r[i] ^= 0x01
+		return "enc:v1:k1:" + base64.RawStdEncoding.EncodeToString(r)
+	}
+	casos := map[string]struct {
+		valor string
+		erro  error
+	}{
+		"bit do nonce":      {invertido(0), ErrValorCorrompido},
+		"bit da cifra":      {invertido(len(raw) / 2), ErrValorCorrompido},
+		"bit da tag":        {invertido(len(raw) - 1), ErrValorCorrompido},
+		"truncado":          {"enc:v1:k1:" + base64.RawStdEncoding.EncodeToString(raw[:len(raw)-1]), ErrValorCorrompido},
+		"menor que nonce":   {"enc:v1:k1:" + base64.RawStdEncoding.EncodeToString(raw[:4]), ErrValorCorrompido},
+		"base64 inválido":   {"enc:v1:k1:***", ErrValorCorrompido},
+		"sem id":            {"enc:v1:" + b64, ErrValorCorrompido},
+		"outra chave":       {"enc:v1:k2:" + b64, ErrValorCorrompido},
+		"chave inexistente": {"enc:v1:k9:" + b64, ErrChaveDesconhecida},
+	}
+	for nome, caso := range casos {
+		if got, err := c.Decifrar(caso.valor); !errors.Is(err, caso.erro) {
+			t.Errorf("%s: Decifrar = %q, %v; esperado %v", nome, got, err, caso.erro)
+		}
+	}
+}
+
+func TestRotacaoDeChave(t *testing.T) {
+	antigo := novoCifrador(t, "k1", map[string][]byte{"k1": chaveA}, nil)
+	cif, _ := antigo.Cifrar("11999990000")
+
+	novo := novoCifrador(t, "k2", map[string][]byte{"k1": chaveA, "k2": chaveB}, nil)
+	if got, err := novo.Decifrar(cif); err != nil || got != "11999990000" {
+		t.Fatalf("chave antiga não decifra após a rotação: %q, %v", got, err)
+	}
+	if novo.Atualizado(cif) {
+		t.Error("valor com a chave antiga marcado como atualizado")
+	}
+	recifrado, _ := novo.Cifrar("11999990000")
+	if !novo.Atualizado(recifrado) || !strings.HasPrefix(recifrado, "enc:v1:k2:") {
+		t.Errorf("Cifrar usou outra chave: %q", recifrado)
+	}
+}
+
+func TestValorLegadoESemChave(t *testing.T) {
+	c := novoCifrador(t, "k1", map[string][]byte{"k1": chaveA}, nil)
+	if got, err := c.Decifrar("123.456.789-09"); err != nil || got != "123.456.789-09" {
+		t.Errorf("legado = %q, %v", got, err)
+	}
+	if c.Atualizado("123.456.789-09") {
+		t.Error("texto puro marcado como atualizado com chave configurada")
+	}
+
+	var nulo *Cifrador
+	if v, _ := nulo.Cifrar("123"); v != "123" || nulo.Indice("123") != "123" || nulo.Ativo() {
+		t.Error("Cifrador nil deveria deixar os valores em texto puro")
+	}
+	cif, _ := c.Cifrar("123")
+	if _, err := nulo.Decifrar(cif); !errors.Is(err, ErrChaveDesconhecida) {
+		t.Errorf("Decifrar sem chave = %v, esperado %v", err, ErrChaveDesconhecida)
+	}
+}
+
+func TestIndiceCego(t *testing.T) {
+	c := novoCifrador(t, "k1", map[string][]byte{"k1": chaveA}, nil)
+	i1, i2 := c.Indice("12345678909"), c.Indice("12345678909")
+	if i1 != i2 || len(i1) != 64 || strings.Contains(i1, "12345678909") {
+		t.Fatalf("Indice não é estável/opaco: %q x %q", i1, i2)
+	}
+	if c.Indice("12345678900") == i1 {
+		t.Error("valores diferentes com o mesmo índice")
+	}
+	if c.Indice("") != "" {
+		t.Error("índice de vazio deveria ser vazio")
+	}
+
+	// derivado da chave atual: outra PII_KEY, outro índice
+	outra := novoCifrador(t, "k1", map[string][]byte{"k1": chaveB}, nil)
+	if outra.Indice("12345678909") == i1 {
+		t.Error("índice não depende da chave")
+	}
+
+	// com PII_INDEX_KEY, rotacionar a PII_KEY não muda o índice (cpf_hash continua válido)
+	idx := []byte("segredo-do-indice")
+	antes := novoCifrador(t, "k1", map[string][]byte{"k1": chaveA}, idx)
+	depois := novoCifrador(t, "k2", map[string][]byte{"k1": chaveA, "k2": chaveB}, idx)
+	if antes.Indice("12345678909") != depois.Indice("12345678909") {
+		t.Error("PII_INDEX_KEY fixa, mas o índice mudou com a rotação")
+	}
+	if antes.Indice("12345678909") == i1 {
+		t.Error("PII_INDEX_KEY ignorada")
+	}
+}
+
+func TestNovoValidaChaves(t *testing.T) {
+	casos := map[string]struct {
+		atual  string
+		chaves map[string][]byte
+		erro   error
+	}{
+		"chave curta":        {"k1", map[string][]byte{"k1": chaveA[:16]}, ErrChaveInvalida},
+		"atual ausente":      {"k2", map[string][]byte{"k1": chaveA}, ErrChaveInvalida},
+		"id com dois-pontos": {"k:1", map[string][]byte{"k:1": chaveA}, ErrIDChaveObrigatorio},
+		"id vazio":           {"", map[string][]byte{"": chaveA}, ErrIDChaveObrigatorio},
+	}
+	for nome, c := range casos {
+		if _, err := Novo(c.atual, c.chaves, nil); !errors.Is(err, c.erro) {
+			t.Errorf("%s: erro = %v, esperado %v", nome, err, c.erro)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/disjuntor/disjuntor.go
+/// Responsabilidade: Disjuntor (circuit breaker) para chamadas a serviços externos: depois de N falhas seguidas, recusa as chamadas por um tempo em vez de esperar o timeout de cada uma.
+/// Dependências principais: errors, sync, time.
+/// Pontos de atenção:
+/// - Estados: fechado (chamadas passam) → aberto (Permitir devolve ErrAberto durante a pausa) → meio-aberto (uma única
+///   chamada de sonda passa; sucesso fecha, falha reabre por mais uma pausa).
+/// - Quem chama registra o resultado com Registrar; uma sonda que nunca registra (cancelada) é liberada de novo após a pausa.
+/// - Em memória, por processo: cada réplica abre o próprio disjuntor.
+*/
+
+package disjuntor
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// Disjuntor conta falhas seguidas de um serviço externo. Seguro para uso concorrente.
+type Disjuntor struct {
+	limiar int
+	pausa  time.Duration
+
+	mu         sync.Mutex
+	falhas     int       // falhas seguidas
+	abertoAte  time.Time // fim da pausa (aberto enquanto falhas >= limiar)
+	sondaDesde time.Time // sonda do meio-aberto em andamento (zero = nenhuma)
+}
+
+/// ============ Configurações & Constantes ============
+
+var ErrAberto = errors.New("disjuntor aberto: serviço externo indisponível")
+
+/// ============ Inicialização/Bootstrap ============
+
+// Novo cria um disjuntor que abre após limiar falhas seguidas e fica aberto por pausa.
+func Novo(limiar int, pausa time.Duration) *Disjuntor {
+	return &Disjuntor{limiar: max(limiar, 1), pausa: pausa}
+}
+
+/// ============ Funções Públicas ============
+
+// Permitir devolve nil se a chamada pode seguir, ou ErrAberto com o tempo até a
+// próxima tentativa.
+func (d *Disjuntor) Permitir() (time.Duration, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.falhas < d.limiar {
+		return 0, nil
+	}
+	agora := time.Now()
+	if agora.Before(d.abertoAte) {
+		return d.abertoAte.Sub(agora), ErrAberto
+	}
+	// meio-aberto: uma sonda por vez
+	if !d.sondaDesde.IsZero() && agora.Sub(d.sondaDesde) < d.pausa {
+		return d.pausa - agora.Sub(d.sondaDesde), ErrAberto
+	}
+	d.sondaDesde = agora
+	return 0, nil
+}
+
+// Registrar informa o resultado de uma chamada liberada por Permitir.
+func (d *Disjuntor) Registrar(ok bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.sondaDesde = time.Time{}
+	if ok {
+		d.falhas = 0
+		return
+	}
+	d.falhas++
+	if d.falhas >= d.limiar {
+		d.abertoAte = time.Now().Add(d.pausa)
+	}
+}
+
+// Restante informa quanto falta para o disjuntor aceitar uma nova tentativa (0 = fechado ou meio-aberto).
+func (d *Disjuntor) Restante() time.Duration {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.falhas < d.limiar {
+		return 0
+	}
+	return max(time.Until(d.abertoAte), 0)
+}
@@ -0,0 +1,534 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/graphql/graphql.go
+/// Responsabilidade: Executor GraphQL mínimo (schema declarado em Go, resolvers por campo, respostas {data, errors}).
+/// Dependências principais: context, encoding/json, reflect, strings.
+/// Pontos de atenção:
+/// - Sem dependência externa: o schema é um mapa de Objeto → Campo montado por quem usa o pacote (ex.: handler/graphql_handler.go).
+/// - O documento inteiro é validado contra o schema ANTES de executar (campos, argumentos, fragmentos, subseleções):
+///   uma mutation nunca roda pela metade por causa de um erro de digitação na consulta.
+/// - Erro em um resolver vira null naquele campo + entrada em "errors" (com path); os demais campos seguem.
+/// - Não há tipos não-nulos nem introspecção (__schema/__type); __typename é suportado.
+/// - Campos da raiz de uma mutation executam em série, na ordem do documento.
+/// - Limites validados antes da execução (o schema tem ciclos, ex.: estudante → ano → estudantes):
+///   ProfundidadeMaxima níveis de campos e ComplexidadeMaxima campos no total, contando cada uso de
+///   fragmento por extenso. Passar de um deles é erro de validação: nada é executado.
+*/
+
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// Resolver calcula o valor de um campo a partir do objeto pai e dos argumentos já resolvidos
+// (variáveis substituídas e defaults aplicados).
+type Resolver func(ctx context.Context, pai any, args map[string]any) (any, error)
+
+// Campo declara um campo de um Objeto.
+type Campo struct {
+	Tipo     *Objeto        // nil = escalar (ou lista de escalares), sem subseleção
+	Args     map[string]any // argumentos aceitos → valor default (nil = sem default)
+	Resolver Resolver       // nil = lê o campo homônimo do pai (map ou tag json do struct)
+}
+
+// Objeto é um tipo composto do schema; valores resolvidos podem ser um item ou uma lista.
+type Objeto struct {
+	Nome   string
+	Campos map[string]*Campo
+}
+
+// Schema define os pontos de entrada das operações.
+type Schema struct {
+	Query    *Objeto
+	Mutation *Objeto
+}
+
+// Requisicao é o corpo padrão do GraphQL sobre HTTP.
+type Requisicao struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+// Resposta é o envelope {data, errors}.
+type Resposta struct {
+	Data   any     `json:"data,omitempty"`
+	Errors []*Erro `json:"errors,omitempty"`
+}
+
+// Erro segue o formato da especificação (message, locations, path, extensions).
+type Erro struct {
+	Message    string         `json:"message"`
+	Locations  []Local        `json:"locations,omitempty"`
+	Path       []any          `json:"path,omitempty"`
+	Extensions map[string]any `json:"extensions,omitempty"`
+}
+
+// Local é a posição (1-based) de um trecho do documento.
+type Local struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// Preparada é uma operação já analisada e validada, pronta para executar.
+type Preparada struct {
+	raiz *Objeto
+	doc  *documento
+	op   *operacao
+	vars map[string]any
+}
+
+// mapaOrdenado preserva a ordem da seleção na serialização JSON.
+type mapaOrdenado struct {
+	chaves  []string
+	valores map[string]any
+}
+
+// execucao carrega o estado de uma operação.
+type execucao struct {
+	doc   *documento
+	vars  map[string]any
+	erros []*Erro
+}
+
+/// ============ Configurações & Constantes ============
+
+const (
+	// ProfundidadeMaxima é o nível máximo de um campo (campos da raiz = 1).
+	ProfundidadeMaxima = 10
+	// ComplexidadeMaxima é o total de campos selecionados, com fragmentos expandidos.
+	ComplexidadeMaxima = 500
+)
+
+/// ============ Funções Públicas ============
+
+// NovoErro cria um erro de resolver com extensions.code (e details opcional).
+func NovoErro(mensagem, code string, details any) *Erro {
+	ext := map[string]any{"code": code}
+	if details != nil {
+		ext["details"] = details
+	}
+	return &Erro{Message: mensagem, Extensions: ext}
+}
+
+func (e *Erro) Error() string { return e.Message }
+
+// Preparar analisa a sintaxe, escolhe a operação e valida o documento contra o schema.
+// Erros aqui significam que nada foi executado.
+func (s *Schema) Preparar(req Requisicao) (*Preparada, []*Erro) {
+	doc, op, erros := s.preparar(req)
+	if len(erros) > 0 {
+		return nil, erros
+	}
+	vars := map[string]any{}
+	for k, v := range op.padroes {
+		vars[k] = v
+	}
+	for k, v := range req.Variables {
+		vars[k] = v
+	}
+	return &Preparada{raiz: s.raiz(op.tipo), doc: doc, op: op, vars: vars}, nil
+}
+
+// Executar prepara e executa a requisição.
+func (s *Schema) Executar(ctx context.Context, req Requisicao) Resposta {
+	p, erros := s.Preparar(req)
+	if len(erros) > 0 {
+		return Resposta{Errors: erros}
+	}
+	return p.Executar(ctx)
+}
+
+// Tipo devolve o tipo da operação escolhida: "query" ou "mutation".
+func (p *Preparada) Tipo() string { return p.op.tipo }
+
+// Executar roda a operação validada.
+func (p *Preparada) Executar(ctx context.Context) Resposta {
+	ex := &execucao{doc: p.doc, vars: p.vars}
+	dados := ex.executarSelecao(ctx, p.raiz, nil, p.op.selecoes, nil)
+	return Resposta{Data: dados, Errors: ex.erros}
+}
+
+// ArgInt lê um argumento inteiro (aceita float64 inteiro vindo de variáveis JSON).
+func ArgInt(args map[string]any, nome string) (int, bool) {
+	switch v := args[nome].(type) {
+	case int:
+		return v, true
+	case float64:
+		if v == float64(int(v)) {
+			return int(v), true
+		}
+	case json.Number:
+		n, err := v.Int64()
+		return int(n), err == nil
+	}
+	return 0, false
+}
+
+// ArgString lê um argumento texto (ou enum).
+func ArgString(args map[string]any, nome string) (string, bool) {
+	v, ok := args[nome].(string)
+	return v, ok
+}
+
+// ArgBool lê um argumento booleano.
+func ArgBool(args map[string]any, nome string) (bool, bool) {
+	v, ok := args[nome].(bool)
+	return v, ok
+}
+
+// Decodificar converte um argumento objeto (input) em struct, usando as tags json de destino.
+func Decodificar(valor any, destino any) error {
+	b, err := json.Marshal(valor)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	return dec.Decode(destino)
+}
+
+func (m *mapaOrdenado) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, k := range m.chaves {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		kb, _ := json.Marshal(k)
+		b.Write(kb)
+		b.WriteByte(':')
+		vb, err := json.Marshal(m.valores[k])
+		if err != nil {
+			return nil, err
+		}
+		b.Write(vb)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+/// ============ Funções Internas (helpers) ============
+
+// preparar analisa o documento, escolhe a operação e valida tudo contra o schema.
+func (s *Schema) preparar(req Requisicao) (*documento, *operacao, []*Erro) {
+	if strings.TrimSpace(req.Query) == "" {
+		return nil, nil, []*Erro{{Message: "Campo query obrigatório"}}
+	}
+	doc, err := analisar(req.Query)
+	if err != nil {
+		return nil, nil, []*Erro{err.(*Erro)}
+	}
+
+	var op *operacao
+	switch {
+	case req.OperationName != "":
+		for _, o := range doc.operacoes {
+			if o.nome == req.OperationName {
+				op = o
+			}
+		}
+		if op == nil {
+			return nil, nil, []*Erro{{Message: fmt.Sprintf("Operação %q não encontrada no documento", req.OperationName)}}
+		}
+	case len(doc.operacoes) == 1:
+		op = doc.operacoes[0]
+	default:
+		return nil, nil, []*Erro{{Message: "Documento com várias operações: informe operationName"}}
+	}
+
+	raiz := s.raiz(op.tipo)
+	if raiz == nil {
+		return nil, nil, []*Erro{{Message: "Operação " + op.tipo + " não suportada", Locations: []Local{op.local}}}
+	}
+	v := &validador{doc: doc, visitando: map[string]bool{}}
+	v.selecao(raiz, op.selecoes, 1)
+	if len(v.erros) > 0 {
+		return nil, nil, v.erros
+	}
+	return doc, op, nil
+}
+
+func (s *Schema) raiz(tipo string) *Objeto {
+	if tipo == "mutation" {
+		return s.Mutation
+	}
+	return s.Query
+}
+
+// validador percorre o documento conferindo campos, argumentos, fragmentos e os limites.
+type validador struct {
+	doc         *documento
+	visitando   map[string]bool // fragmentos na pilha (detecta ciclos)
+	erros       []*Erro
+	campos      int  // campos visitados até aqui (complexidade)
+	muitoFundo  bool // ProfundidadeMaxima já reportada
+	muitoCampos bool // ComplexidadeMaxima já reportada (interrompe a validação)
+}
+
+func (v *validador) falhar(l Local, formato string, args ...any) {
+	v.erros = append(v.erros, &Erro{Message: fmt.Sprintf(formato, args...), Locations: []Local{l}})
+}
+
+// selecao valida sels como campos de obj no nível informado.
+func (v *validador) selecao(obj *Objeto, sels []selecao, nivel int) {
+	for _, sel := range sels {
+		if v.muitoCampos {
+			return // fragmentos repetidos podem crescer exponencialmente: para de expandir
+		}
+		switch s := sel.(type) {
+		case *campoSel:
+			if v.campos++; v.campos > ComplexidadeMaxima {
+				v.muitoCampos = true
+				v.falhar(s.local, "Consulta excede o limite de %d campos", ComplexidadeMaxima)
+				return
+			}
+			if nivel > ProfundidadeMaxima {
+				if !v.muitoFundo {
+					v.muitoFundo = true
+					v.falhar(s.local, "Consulta excede a profundidade máxima de %d níveis", ProfundidadeMaxima)
+				}
+				continue
+			}
+			if s.nome == "__typename" {
+				continue
+			}
+			c, ok := obj.Campos[s.nome]
+			if !ok {
+				v.falhar(s.local, "Campo %q não existe em %s", s.nome, obj.Nome)
+				continue
+			}
+			for _, a := range s.args {
+				if _, ok := c.Args[a.nome]; !ok {
+					v.falhar(s.local, "Argumento %q não existe em %s.%s", a.nome, obj.Nome, s.nome)
+				}
+			}
+			switch {
+			case c.Tipo != nil && s.selecoes == nil:
+				v.falhar(s.local, "Campo %s.%s (%s) exige subseleção { ... }", obj.Nome, s.nome, c.Tipo.Nome)
+			case c.Tipo == nil && s.selecoes != nil:
+				v.falhar(s.local, "Campo %s.%s é escalar e não aceita subseleção", obj.Nome, s.nome)
+			case c.Tipo != nil:
+				v.selecao(c.Tipo, s.selecoes, nivel+1)
+			}
+		case *spreadSel:
+			f, ok := v.doc.fragmentos[s.nome]
+			if !ok {
+				v.falhar(s.local, "Fragmento %q não definido", s.nome)
+				continue
+			}
+			if v.visitando[s.nome] {
+				v.falhar(s.local, "Fragmento %q referencia a si mesmo", s.nome)
+				continue
+			}
+			v.visitando[s.nome] = true
+			v.selecao(obj, f.selecoes, nivel)
+			delete(v.visitando, s.nome)
+		case *inlineSel:
+			v.selecao(obj, s.selecoes, nivel)
+		}
+	}
+}
+
+// executarSelecao resolve os campos de obj sobre o valor pai.
+func (ex *execucao) executarSelecao(ctx context.Context, obj *Objeto, pai any, sels []selecao, caminho []any) *mapaOrdenado {
+	out := &mapaOrdenado{valores: map[string]any{}}
+	campos, ordem := ex.coletar(sels, map[string][]*campoSel{}, nil)
+	for _, chave := range ordem {
+		grupo := campos[chave]
+		sel := grupo[0]
+		out.chaves = append(out.chaves, chave)
+		if sel.nome == "__typename" {
+			out.valores[chave] = obj.Nome
+			continue
+		}
+		c := obj.Campos[sel.nome]
+		caminhoCampo := append(caminho[:len(caminho):len(caminho)], chave)
+
+		args := make(map[string]any, len(c.Args))
+		for nome, padrao := range c.Args {
+			if padrao != nil {
+				args[nome] = padrao
+			}
+		}
+		for _, a := range sel.args {
+			args[a.nome] = ex.valor(a.valor)
+		}
+
+		var (
+			valor any
+			err   error
+		)
+		if c.Resolver != nil {
+			valor, err = c.Resolver(ctx, pai, args)
+		} else {
+			valor = campoDoPai(pai, sel.nome)
+		}
+		if err != nil {
+			ex.erro(err, sel.local, caminhoCampo)
+			out.valores[chave] = nil
+			continue
+		}
+		if c.Tipo == nil {
+			out.valores[chave] = valor
+			continue
+		}
+		var subs []selecao
+		for _, s := range grupo {
+			subs = append(subs, s.selecoes...)
+		}
+		out.valores[chave] = ex.completar(ctx, c.Tipo, valor, subs, caminhoCampo)
+	}
+	return out
+}
+
+// completar aplica a subseleção a um objeto ou a cada item de uma lista.
+func (ex *execucao) completar(ctx context.Context, obj *Objeto, valor any, sels []selecao, caminho []any) any {
+	rv := reflect.ValueOf(valor)
+	for rv.IsValid() && (rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface) {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return nil
+	}
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		lista := make([]any, rv.Len())
+		for i := range lista {
+			item := append(caminho[:len(caminho):len(caminho)], i)
+			lista[i] = ex.completar(ctx, obj, rv.Index(i).Interface(), sels, item)
+		}
+		return lista
+	}
+	return ex.executarSelecao(ctx, obj, rv.Interface(), sels, caminho)
+}
+
+// coletar achata fragmentos e agrupa os campos pela chave de resposta (alias ou nome).
+func (ex *execucao) coletar(sels []selecao, campos map[string][]*campoSel, ordem []string) (map[string][]*campoSel, []string) {
+	for _, sel := range sels {
+		switch s := sel.(type) {
+		case *campoSel:
+			if !ex.incluir(s.diretivas) {
+				continue
+			}
+			chave := s.nome
+			if s.alias != "" {
+				chave = s.alias
+			}
+			if _, ok := campos[chave]; !ok {
+				ordem = append(ordem, chave)
+			}
+			campos[chave] = append(campos[chave], s)
+		case *spreadSel:
+			if ex.incluir(s.diretivas) {
+				campos, ordem = ex.coletar(ex.doc.fragmentos[s.nome].selecoes, campos, ordem)
+			}
+		case *inlineSel:
+			if ex.incluir(s.diretivas) {
+				campos, ordem = ex.coletar(s.selecoes, campos, ordem)
+			}
+		}
+	}
+	return campos, ordem
+}
+
+// incluir avalia @skip(if:) e @include(if:).
+func (ex *execucao) incluir(ds []diretiva) bool {
+	for _, d := range ds {
+		cond := false
+		for _, a := range d.args {
+			if a.nome == "if" {
+				cond, _ = ex.valor(a.valor).(bool)
+			}
+		}
+		if (d.nome == "skip" && cond) || (d.nome == "include" && !cond) {
+			return false
+		}
+	}
+	return true
+}
+
+// valor substitui referências a variáveis (recursivamente em listas/objetos).
+func (ex *execucao) valor(v any) any {
+	switch x := v.(type) {
+	case refVariavel:
+		return ex.vars[string(x)]
+	case []any:
+		out := make([]any, len(x))
+		for i := range x {
+			out[i] = ex.valor(x[i])
+		}
+		return out
+	case map[string]any:
+		out := make(map[string]any, len(x))
+		for k, vv := range x {
+			out[k] = ex.valor(vv)
+		}
+		return out
+	}
+	return v
+}
+
+func (ex *execucao) erro(err error, l Local, caminho []any) {
+	e, ok := err.(*Erro)
+	if !ok {
+		e = &Erro{Message: err.Error()}
+	}
+	cp := *e
+	cp.Locations = []Local{l}
+	cp.Path = caminho
+	ex.erros = append(ex.erros, &cp)
+}
+
+// campoDoPai lê nome de um map[string]any ou do campo com tag json correspondente.
+func campoDoPai(pai any, nome string) any {
+	if m, ok := pai.(map[string]any); ok {
+		return m[nome]
+	}
+	rv := reflect.ValueOf(pai)
+	for rv.IsValid() && rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() || rv.Kind() != reflect.Struct {
+		return nil
+	}
+	if f, ok := campoPorTag(rv, nome); ok {
+		return f.Interface()
+	}
+	return nil
+}
+
+// campoPorTag procura o campo exportado cuja tag json (ou nome) é nome, incluindo embutidos.
+func campoPorTag(rv reflect.Value, nome string) (reflect.Value, bool) {
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if f.Anonymous && tag == "" && f.Type.Kind() == reflect.Struct {
+			if v, ok := campoPorTag(rv.Field(i), nome); ok {
+				return v, true
+			}
+			continue
+		}
+		if !f.IsExported() || tag == "-" {
+			continue
+		}
+		if tag == nome || (tag == "" && f.Name == nome) {
+			return rv.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
@@ -0,0 +1,527 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/graphql/parser.go
+/// Responsabilidade: Analisador léxico/sintático de documentos GraphQL (operações, variáveis, fragmentos e diretivas).
+/// Dependências principais: strconv, strings, unicode/utf8.
+/// Pontos de atenção:
+/// - Cobre o subconjunto usado pela API: query/mutation, aliases, argumentos, variáveis com default,
+///   fragmentos nomeados e inline, @skip/@include. Subscriptions e definições de tipo (SDL) são rejeitadas.
+/// - Os tipos declarados nas variáveis são lidos e ignorados: a coerção acontece nos resolvers.
+/// - Valores literais viram valores Go (int, float64, string, bool, nil, []any, map[string]any);
+///   enums viram string e referências a variáveis ficam como refVariavel até a execução.
+/// - A descida é recursiva: seleções e valores aninhados além de aninhamentoMaximo são erro de sintaxe
+///   (um "{{{{..." gigante não estoura a pilha).
+*/
+
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// documento é o resultado da análise de uma requisição.
+type documento struct {
+	operacoes  []*operacao
+	fragmentos map[string]*fragmento
+}
+
+// operacao é uma query ou mutation.
+type operacao struct {
+	tipo     string // query | mutation
+	nome     string
+	padroes  map[string]any // valores default das variáveis declaradas
+	selecoes []selecao
+	local    Local
+}
+
+// fragmento nomeado (fragment X on Tipo { ... }).
+type fragmento struct {
+	nome     string
+	selecoes []selecao
+}
+
+// selecao é *campoSel, *spreadSel ou *inlineSel.
+type selecao interface{}
+
+type campoSel struct {
+	alias, nome string
+	args        []argumento
+	diretivas   []diretiva
+	selecoes    []selecao
+	local       Local
+}
+
+type spreadSel struct {
+	nome      string
+	diretivas []diretiva
+	local     Local
+}
+
+type inlineSel struct {
+	diretivas []diretiva
+	selecoes  []selecao
+}
+
+type argumento struct {
+	nome  string
+	valor any
+}
+
+type diretiva struct {
+	nome string
+	args []argumento
+}
+
+// refVariavel marca um $nome dentro de um valor literal.
+type refVariavel string
+
+// token léxico
+type token struct {
+	tipo  int
+	texto string
+	local Local
+}
+
+const (
+	tkFim = iota
+	tkPontuacao
+	tkNome
+	tkInt
+	tkFloat
+	tkString
+)
+
+// aninhamentoMaximo limita { } de seleções e [ ]/{ } de valores, somados.
+const aninhamentoMaximo = 64
+
+type analisador struct {
+	fonte string
+	pos   int
+	linha int
+	ini   int // posição do início da linha corrente
+	atual token
+	nivel int // aninhamento corrente (ver aninhamentoMaximo)
+}
+
+/// ============ Funções Internas (helpers) ============
+
+// analisar converte o texto da consulta em documento (erro com linha/coluna).
+func analisar(fonte string) (doc *documento, err error) {
+	p := &analisador{fonte: fonte, linha: 1}
+	defer func() {
+		if r := recover(); r != nil {
+			e, ok := r.(*Erro)
+			if !ok {
+				panic(r)
+			}
+			doc, err = nil, e
+		}
+	}()
+	p.avancar()
+	doc = &documento{fragmentos: map[string]*fragmento{}}
+	for p.atual.tipo != tkFim {
+		switch {
+		case p.eh(tkPontuacao, "{"):
+			doc.operacoes = append(doc.operacoes, &operacao{tipo: "query", local: p.atual.local, selecoes: p.selecoes()})
+		case p.eh(tkNome, "query"), p.eh(tkNome, "mutation"):
+			doc.operacoes = append(doc.operacoes, p.operacao())
+		case p.eh(tkNome, "fragment"):
+			f := p.fragmento()
+			if _, dup := doc.fragmentos[f.nome]; dup {
+				p.falhar("fragmento %q definido mais de uma vez", f.nome)
+			}
+			doc.fragmentos[f.nome] = f
+		case p.eh(tkNome, "subscription"):
+			p.falhar("subscriptions não são suportadas")
+		default:
+			p.falhar("esperado query, mutation ou fragment; encontrado %q", p.atual.texto)
+		}
+	}
+	if len(doc.operacoes) == 0 {
+		p.falhar("documento sem operação")
+	}
+	return doc, nil
+}
+
+func (p *analisador) operacao() *operacao {
+	op := &operacao{tipo: p.atual.texto, local: p.atual.local, padroes: map[string]any{}}
+	p.avancar()
+	if p.atual.tipo == tkNome {
+		op.nome = p.atual.texto
+		p.avancar()
+	}
+	if p.pular("(") {
+		for !p.pular(")") {
+			p.esperar("$")
+			nome := p.nome()
+			p.esperar(":")
+			p.tipoRef()
+			if p.pular("=") {
+				op.padroes[nome] = p.valor(true)
+			}
+			p.diretivas()
+		}
+	}
+	p.diretivas()
+	op.selecoes = p.selecoes()
+	return op
+}
+
+func (p *analisador) fragmento() *fragmento {
+	p.avancar()
+	f := &fragmento{nome: p.nome()}
+	if f.nome == "on" {
+		p.falhar("nome de fragmento inválido")
+	}
+	if !p.eh(tkNome, "on") {
+		p.falhar("esperado 'on' após o nome do fragmento")
+	}
+	p.avancar()
+	p.nome()
+	p.diretivas()
+	f.selecoes = p.selecoes()
+	return f
+}
+
+// tipoRef lê (e descarta) uma referência de tipo: Nome, [Tipo], com "!" opcional.
+func (p *analisador) tipoRef() {
+	if p.pular("[") {
+		p.tipoRef()
+		p.esperar("]")
+	} else {
+		p.nome()
+	}
+	p.pular("!")
+}
+
+func (p *analisador) selecoes() []selecao {
+	p.esperar("{")
+	p.entrar()
+	defer p.sair()
+	var out []selecao
+	for !p.pular("}") {
+		if p.eh(tkPontuacao, "...") {
+			local := p.atual.local
+			p.avancar()
+			if p.eh(tkNome, "on") || p.eh(tkPontuacao, "{") || p.eh(tkPontuacao, "@") {
+				if p.eh(tkNome, "on") {
+					p.avancar()
+					p.nome()
+				}
+				out = append(out, &inlineSel{diretivas: p.diretivas(), selecoes: p.selecoes()})
+				continue
+			}
+			out = append(out, &spreadSel{nome: p.nome(), diretivas: p.diretivas(), local: local})
+			continue
+		}
+		c := &campoSel{local: p.atual.local}
+		c.nome = p.nome()
+		if p.pular(":") {
+			c.alias, c.nome = c.nome, p.nome()
+		}
+		c.args = p.argumentos()
+		c.diretivas = p.diretivas()
+		if p.eh(tkPontuacao, "{") {
+			c.selecoes = p.selecoes()
+		}
+		out = append(out, c)
+	}
+	if len(out) == 0 {
+		p.falhar("conjunto de seleção vazio")
+	}
+	return out
+}
+
+func (p *analisador) argumentos() []argumento {
+	if !p.pular("(") {
+		return nil
+	}
+	var out []argumento
+	for !p.pular(")") {
+		nome := p.nome()
+		p.esperar(":")
+		out = append(out, argumento{nome: nome, valor: p.valor(false)})
+	}
+	return out
+}
+
+func (p *analisador) diretivas() []diretiva {
+	var out []diretiva
+	for p.pular("@") {
+		out = append(out, diretiva{nome: p.nome(), args: p.argumentos()})
+	}
+	return out
+}
+
+// valor lê um literal; constante=true proíbe variáveis (defaults).
+func (p *analisador) valor(constante bool) any {
+	t := p.atual
+	switch t.tipo {
+	case tkInt:
+		p.avancar()
+		n, err := strconv.Atoi(t.texto)
+		if err != nil {
+			p.falharEm(t.local, "inteiro fora do intervalo: %s", t.texto)
+		}
+		return n
+	case tkFloat:
+		p.avancar()
+		f, _ := strconv.ParseFloat(t.texto, 64)
+		return f
+	case tkString:
+		p.avancar()
+		return t.texto
+	case tkNome:
+		p.avancar()
+		switch t.texto {
+		case "true":
+			return true
+		case "false":
+			return false
+		case "null":
+			return nil
+		}
+		return t.texto // enum
+	}
+	switch {
+	case p.pular("$"):
+		if constante {
+			p.falharEm(t.local, "variável não permitida aqui")
+		}
+		return refVariavel(p.nome())
+	case p.pular("["):
+		p.entrar()
+		defer p.sair()
+		lista := []any{}
+		for !p.pular("]") {
+			lista = append(lista, p.valor(constante))
+		}
+		return lista
+	case p.pular("{"):
+		p.entrar()
+		defer p.sair()
+		obj := map[string]any{}
+		for !p.pular("}") {
+			nome := p.nome()
+			p.esperar(":")
+			obj[nome] = p.valor(constante)
+		}
+		return obj
+	}
+	p.falhar("valor inesperado: %q", t.texto)
+	return nil
+}
+
+// entrar/sair contam o aninhamento de { } e [ ] já consumidos.
+func (p *analisador) entrar() {
+	if p.nivel++; p.nivel > aninhamentoMaximo {
+		p.falhar("aninhamento acima de %d níveis", aninhamentoMaximo)
+	}
+}
+
+func (p *analisador) sair() { p.nivel-- }
+
+func (p *analisador) nome() string {
+	if p.atual.tipo != tkNome {
+		p.falhar("esperado nome; encontrado %q", p.atual.texto)
+	}
+	n := p.atual.texto
+	p.avancar()
+	return n
+}
+
+func (p *analisador) eh(tipo int, texto string) bool {
+	return p.atual.tipo == tipo && p.atual.texto == texto
+}
+
+// pular consome a pontuação s se ela for o token atual.
+func (p *analisador) pular(s string) bool {
+	if p.eh(tkPontuacao, s) {
+		p.avancar()
+		return true
+	}
+	if p.atual.tipo == tkFim && s != "" && strings.Contains(")]}", s) {
+		p.falhar("fim inesperado do documento (faltou %q)", s)
+	}
+	return false
+}
+
+func (p *analisador) esperar(s string) {
+	if !p.pular(s) {
+		p.falhar("esperado %q; encontrado %q", s, p.atual.texto)
+	}
+}
+
+func (p *analisador) falhar(formato string, args ...any) {
+	p.falharEm(p.atual.local, formato, args...)
+}
+
+func (p *analisador) falharEm(l Local, formato string, args ...any) {
+	panic(&Erro{Message: "Erro de sintaxe: " + fmt.Sprintf(formato, args...), Locations: []Local{l}})
+}
+
+// avancar lê o próximo token (ignora espaços, vírgulas e comentários).
+func (p *analisador) avancar() {
+	s := p.fonte
+	for p.pos < len(s) {
+		c := s[p.pos]
+		switch {
+		case c == '\n':
+			p.pos++
+			p.linha++
+			p.ini = p.pos
+		case c == ' ' || c == '\t' || c == '\r' || c == ',':
+			p.pos++
+		case c == '#':
+			for p.pos < len(s) && s[p.pos] != '\n' {
+				p.pos++
+			}
+		case strings.HasPrefix(s[p.pos:], "\uFEFF"):
+			p.pos += len("\uFEFF")
+		default:
+			p.lerToken()
+			return
+		}
+	}
+	p.atual = token{tipo: tkFim, local: p.local()}
+}
+
+func (p *analisador) local() Local {
+	return Local{Line: p.linha, Column: utf8.RuneCountInString(p.fonte[p.ini:p.pos]) + 1}
+}
+
+func (p *analisador) lerToken() {
+	s, ini, local := p.fonte, p.pos, p.local()
+	c := s[p.pos]
+	switch {
+	case strings.HasPrefix(s[p.pos:], "..."):
+		p.pos += 3
+		p.atual = token{tkPontuacao, "...", local}
+	case strings.ContainsRune("!$&()[]{}:=@|", rune(c)):
+		p.pos++
+		p.atual = token{tkPontuacao, string(c), local}
+	case c == '_' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z':
+		for p.pos < len(s) && (s[p.pos] == '_' || s[p.pos] >= 'A' && s[p.pos] <= 'Z' ||
+			s[p.pos] >= 'a' && s[p.pos] <= 'z' || s[p.pos] >= '0' && s[p.pos] <= '9') {
+			p.pos++
+		}
+		p.atual = token{tkNome, s[ini:p.pos], local}
+	case c == '-' || c >= '0' && c <= '9':
+		p.lerNumero(local)
+	case c == '"':
+		p.atual = token{tkString, p.lerString(), local}
+	default:
+		r, _ := utf8.DecodeRuneInString(s[p.pos:])
+		p.falharEm(local, "caractere inesperado %q", r)
+	}
+}
+
+func (p *analisador) lerNumero(local Local) {
+	s, ini := p.fonte, p.pos
+	digitos := func() int {
+		n := 0
+		for p.pos < len(s) && s[p.pos] >= '0' && s[p.pos] <= '9' {
+			p.pos++
+			n++
+		}
+		return n
+	}
+	if s[p.pos] == '-' {
+		p.pos++
+	}
+	if digitos() == 0 {
+		p.falharEm(local, "número inválido")
+	}
+	tipo := tkInt
+	if p.pos < len(s) && s[p.pos] == '.' {
+		p.pos++
+		tipo = tkFloat
+		if digitos() == 0 {
+			p.falharEm(local, "número inválido")
+		}
+	}
+	if p.pos < len(s) && (s[p.pos] == 'e' || s[p.pos] == 'E') {
+		p.pos++
+		tipo = tkFloat
+		if p.pos < len(s) && (s[p.pos] == '+' || s[p.pos] == '-') {
+			p.pos++
+		}
+		if digitos() == 0 {
+			p.falharEm(local, "número inválido")
+		}
+	}
+	p.atual = token{tipo, s[ini:p.pos], local}
+}
+
+// lerString lê "..." (com escapes) ou """...""" (bloco, sem escapes além de \""").
+func (p *analisador) lerString() string {
+	s := p.fonte
+	local := p.local()
+	if strings.HasPrefix(s[p.pos:], `"""`) {
+		p.pos += 3
+		fim := strings.Index(s[p.pos:], `"""`)
+		if fim < 0 {
+			p.falharEm(local, "string de bloco não terminada")
+		}
+		bloco := s[p.pos : p.pos+fim]
+		p.linha += strings.Count(bloco, "\n")
+		if i := strings.LastIndex(bloco, "\n"); i >= 0 {
+			p.ini = p.pos + i + 1
+		}
+		p.pos += fim + 3
+		return strings.TrimSpace(strings.ReplaceAll(bloco, `\"""`, `"""`))
+	}
+	p.pos++
+	var b strings.Builder
+	for {
+		if p.pos >= len(s) || s[p.pos] == '\n' {
+			p.falharEm(local, "string não terminada")
+		}
+		c := s[p.pos]
+		switch c {
+		case '"':
+			p.pos++
+			return b.String()
+		case '\\':
+			if p.pos+1 >= len(s) {
+				p.falharEm(local, "string não terminada")
+			}
+			esc := s[p.pos+1]
+			p.pos += 2
+			switch esc {
+			case '"', '\\', '/':
+				b.WriteByte(esc)
+			case 'b':
+				b.WriteByte('\b')
+			case 'f':
+				b.WriteByte('\f')
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case 'u':
+				if p.pos+4 > len(s) {
+					p.falharEm(local, "escape \\u inválido")
+				}
+				n, err := strconv.ParseUint(s[p.pos:p.pos+4], 16, 32)
+				if err != nil {
+					p.falharEm(local, "escape \\u inválido")
+				}
+				b.WriteRune(rune(n))
+				p.pos += 4
+			default:
+				p.falharEm(local, "escape inválido \\%c", esc)
+			}
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+}
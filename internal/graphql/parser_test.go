@@ -0,0 +1,223 @@
+package graphql
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAnalisarVariaveis(t *testing.T) {
+	doc, err := analisar(`
+		query Busca($id: Int! = 3, $filtro: EstudanteFiltro = {status: ATIVO, anos: [1, 2]}, $busca: [String!]) {
+			estudante(id: $id) { nome }
+			estudantes(filtro: {busca: $busca, status: "ativo"}) { total }
+		}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	op := doc.operacoes[0]
+	if op.tipo != "query" || op.nome != "Busca" {
+		t.Errorf("operação = %s %q", op.tipo, op.nome)
+	}
+	padroes := map[string]any{"id": 3, "filtro": map[string]any{"status": "ATIVO", "anos": []any{1, 2}}}
+	if !reflect.DeepEqual(op.padroes, padroes) {
+		t.Errorf("padroes = %#v, esperado %#v", op.padroes, padroes)
+	}
+	est := op.selecoes[0].(*campoSel)
+	if len(est.args) != 1 || est.args[0].valor != refVariavel("id") {
+		t.Errorf("args de estudante = %#v", est.args)
+	}
+	filtro := op.selecoes[1].(*campoSel).args[0].valor
+	if want := map[string]any{"busca": refVariavel("busca"), "status": "ativo"}; !reflect.DeepEqual(filtro, want) {
+		t.Errorf("filtro = %#v, esperado %#v", filtro, want)
+	}
+}
+
+func TestAnalisarSelecoesAninhadasEAliases(t *testing.T) {
+	doc, err := analisar(`{
+		primeiros: estudantes(limite: 2) {
+			itens { id nome ano { nome } contato: email }
+		}
+		...Anos @include(if: true)
+		... on Query { anos { id } }
+	}
+	fragment Anos on Query { anos(arquivados: false) { nome } }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sels := doc.operacoes[0].selecoes
+	if len(sels) != 3 {
+		t.Fatalf("seleções da raiz = %d, esperadas 3", len(sels))
+	}
+	est := sels[0].(*campoSel)
+	if est.alias != "primeiros" || est.nome != "estudantes" {
+		t.Errorf("alias/nome = %q/%q", est.alias, est.nome)
+	}
+	itens := est.selecoes[0].(*campoSel)
+	var nomes []string
+	for _, s := range itens.selecoes {
+		c := s.(*campoSel)
+		nomes = append(nomes, c.alias+":"+c.nome)
+	}
+	if want := []string{":id", ":nome", ":ano", "contato:email"}; !reflect.DeepEqual(nomes, want) {
+		t.Errorf("campos de itens = %v, esperados %v", nomes, want)
+	}
+	if ano := itens.selecoes[2].(*campoSel); len(ano.selecoes) != 1 {
+		t.Errorf("ano sem subseleção: %#v", ano)
+	}
+	if sp := sels[1].(*spreadSel); sp.nome != "Anos" || sp.diretivas[0].nome != "include" {
+		t.Errorf("spread = %#v", sp)
+	}
+	if _, ok := sels[2].(*inlineSel); !ok {
+		t.Errorf("esperado fragmento inline, veio %T", sels[2])
+	}
+	if _, ok := doc.fragmentos["Anos"]; !ok {
+		t.Error("fragmento Anos não registrado")
+	}
+}
+
+func TestAnalisarValoresLiterais(t *testing.T) {
+	doc, err := analisar(`{ f(a: -12, b: 1.5e2, c: "x\"é\n", d: """ bloco "aspas" """, e: null, g: false, h: ENUM, i: []) }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]any{}
+	for _, a := range doc.operacoes[0].selecoes[0].(*campoSel).args {
+		got[a.nome] = a.valor
+	}
+	want := map[string]any{"a": -12, "b": 150.0, "c": "x\"é\n", "d": `bloco "aspas"`, "e": nil, "g": false, "h": "ENUM", "i": []any{}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("valores = %#v, esperados %#v", got, want)
+	}
+}
+
+func TestAnalisarMalformado(t *testing.T) {
+	casos := []struct{ fonte, msg string }{
+		{`{ estudantes { nome }`, `faltou "}"`},
+		{`{ }`, "conjunto de seleção vazio"},
+		{`{ a(x: ) }`, "valor inesperado"},
+		{`{ a(x: "sem fim) }`, "string não terminada"},
+		{`{ a(x: 99999999999999999999) }`, "inteiro fora do intervalo"},
+		{`{ a(x: 1.) }`, "número inválido"},
+		{`{ a(x: "\q") }`, "escape inválido"},
+		{`{ a(x: "\u12") }`, "escape \\u inválido"},
+		{`query ($id: Int = $outro) { a }`, "variável não permitida"},
+		{`subscription { a }`, "subscriptions não são suportadas"},
+		{`fragment F on Q { a }`, "documento sem operação"},
+		{`fragment on on Q { a } { a }`, "nome de fragmento inválido"},
+		{`{ a } fragment F on Q { a } fragment F on Q { b }`, "definido mais de uma vez"},
+		{`{ a ~ }`, "caractere inesperado"},
+		{"{ a(x: [1, 2) }", "valor inesperado"},
+		{"", "documento sem operação"},
+	}
+	for _, c := range casos {
+		doc, err := analisar(c.fonte)
+		if err == nil {
+			t.Errorf("%q: aceito (%#v)", c.fonte, doc)
+			continue
+		}
+		e := err.(*Erro)
+		if !strings.Contains(e.Message, c.msg) || len(e.Locations) != 1 {
+			t.Errorf("%q: erro = %q %v, esperado com %q e posição", c.fonte, e.Message, e.Locations, c.msg)
+		}
+	}
+}
+
+// Qualquer prefixo de um documento válido devolve erro (ou documento), nunca panic.
+func TestAnalisarPrefixosSemPanic(t *testing.T) {
+	fonte := `query Q($id: Int! = 1, $l: [String] = ["a"]) @dir(x: {y: [1.5, "z", $nao]}) {
+		a: estudante(id: $id) { nome ... on Estudante { ano { nome } } ...F @skip(if: false) }
+	}
+	fragment F on Estudante { email # comentário
+		telefone }`
+	for i := range len(fonte) + 1 {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Fatalf("panic com %q: %v", fonte[:i], r)
+				}
+			}()
+			_, _ = analisar(fonte[:i])
+		}()
+	}
+}
+
+func TestAnalisarLocalDoErro(t *testing.T) {
+	_, err := analisar("{\n  estudantes {\n    nome ~\n  }\n}")
+	e := err.(*Erro)
+	if want := (Local{Line: 3, Column: 10}); len(e.Locations) != 1 || e.Locations[0] != want {
+		t.Errorf("locations = %v, esperado %v", e.Locations, want)
+	}
+}
+
+func TestAnalisarAninhamentoMaximo(t *testing.T) {
+	casos := map[string]string{
+		"seleções": strings.Repeat("{ a ", aninhamentoMaximo+1) + strings.Repeat("}", aninhamentoMaximo+1),
+		"listas":   "{ a(x: " + strings.Repeat("[", 100000) + ") }",
+		"objetos":  "{ a(x: " + strings.Repeat("{y: ", aninhamentoMaximo) + "1" + strings.Repeat("}", aninhamentoMaximo) + ") }",
+	}
+	for nome, fonte := range casos {
+		if _, err := analisar(fonte); err == nil || !strings.Contains(err.Error(), "aninhamento") {
+			t.Errorf("%s: erro = %v, esperado limite de aninhamento", nome, err)
+		}
+	}
+	no := strings.Repeat("{ a ", aninhamentoMaximo) + strings.Repeat("}", aninhamentoMaximo)
+	if _, err := analisar(no); err != nil {
+		t.Errorf("aninhamento no limite recusado: %v", err)
+	}
+}
+
+// schemaCiclico imita o ciclo Estudante → Ano → estudantes do handler.
+func schemaCiclico() *Schema {
+	estudante := &Objeto{Nome: "Estudante", Campos: map[string]*Campo{"nome": {}}}
+	ano := &Objeto{Nome: "Ano", Campos: map[string]*Campo{"nome": {}, "estudantes": {Tipo: estudante}}}
+	estudante.Campos["ano"] = &Campo{Tipo: ano}
+	return &Schema{Query: &Objeto{Nome: "Query", Campos: map[string]*Campo{"estudantes": {Tipo: estudante}}}}
+}
+
+func TestProfundidadeMaxima(t *testing.T) {
+	// estudantes(1) { ano(2) { estudantes(3) { ... nome(n) } } }
+	consulta := func(niveis int) string {
+		var b strings.Builder
+		for i := 1; i < niveis; i++ {
+			if i%2 == 1 {
+				b.WriteString("estudantes { ")
+			} else {
+				b.WriteString("ano { ")
+			}
+		}
+		b.WriteString("nome")
+		return "{ " + b.String() + strings.Repeat(" }", niveis)
+	}
+	s := schemaCiclico()
+	if _, erros := s.Preparar(Requisicao{Query: consulta(ProfundidadeMaxima)}); erros != nil {
+		t.Errorf("profundidade no limite recusada: %v", erros[0])
+	}
+	_, erros := s.Preparar(Requisicao{Query: consulta(ProfundidadeMaxima + 1)})
+	if len(erros) != 1 || !strings.Contains(erros[0].Message, "profundidade máxima") {
+		t.Fatalf("erros = %v, esperado só o de profundidade", erros)
+	}
+}
+
+func TestComplexidadeMaxima(t *testing.T) {
+	s := schemaCiclico()
+	// cada nível dobra os campos: F0 tem 2 campos, F1 usa F0 duas vezes... sem expandir tudo
+	var b strings.Builder
+	b.WriteString("{ estudantes { ...F30 } }\nfragment F0 on Estudante { nome a: nome }\n")
+	for i := 1; i <= 30; i++ {
+		fmt.Fprintf(&b, "fragment F%d on Estudante { ...F%d x: ano { nome } ...F%d }\n", i, i-1, i-1)
+	}
+	_, erros := s.Preparar(Requisicao{Query: b.String()})
+	if len(erros) != 1 || !strings.Contains(erros[0].Message, "limite de") {
+		t.Fatalf("erros = %v, esperado só o de complexidade", erros)
+	}
+
+	campos := strings.Repeat("nome ", ComplexidadeMaxima-1)
+	if _, erros := s.Preparar(Requisicao{Query: "{ estudantes { " + campos + "} }"}); erros != nil {
+		t.Errorf("consulta no limite recusada: %v", erros[0])
+	}
+	if _, erros := s.Preparar(Requisicao{Query: "{ estudantes { " + campos + "nome } }"}); len(erros) != 1 {
+		t.Errorf("consulta acima do limite aceita: %v", erros)
+	}
+}
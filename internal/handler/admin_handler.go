@@ -0,0 +1,289 @@
+// ============================================================================
+// 📄 handler/admin_handler.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - Gestão de contas pela equipe de suporte:
+//   * GET    /api/admin/usuarios                 → contas (?busca, ?limite=1–100, ?antes=<id>)
+//   * GET    /api/admin/usuario?email=...        → conta com o e-mail exato (substitui a
+//     busca de outras contas que GET /api/usuario permitia)
+//   * PUT    /api/admin/usuarios/{id}/bloquear   → bloqueia/desbloqueia ({"bloqueado": false} desbloqueia)
+//   * DELETE /api/admin/usuarios/{id}            → exclui a conta e os dados dela
+//   * POST   /api/admin/impersonate              → token curto para agir como a conta
+//     (X-Impersonation-Token; ver middleware.Autenticacao)
+//
+// 🔐 Autenticação/escopo
+// - Sessão do login (Authorization: Bearer) de uma conta com suporte=true
+//   (middleware.ExigirSuporte no grupo /api/admin); X-User-Email sozinho responde 401.
+//   Não há escopo por tenant: o suporte enxerga todas as contas.
+// - Bloqueio e exclusão são registrados no log (suporte_id, usuario_id) e
+//   invalidam o cache de acesso da conta afetada.
+// - Personificação: cada sessão fica em `personificacoes` (com motivo) e toda
+//   escrita feita com o token sai na auditoria com personificado_por
+//   (middleware.Autenticacao, além das atividades que os handlers registram).
+// ============================================================================
+
+package handler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"backend/internal/apierr"
+	"backend/internal/logging"
+	"backend/internal/model"
+)
+
+// limites da listagem de contas (mesmos do feed de atividades)
+const (
+	adminUsuariosLimitePadrao = 20
+	adminUsuariosLimiteMaximo = 100
+)
+
+// AdminUsuariosHandler trata GET /api/admin/usuarios
+//
+// Regras/erros:
+//   - 405 se método != GET.
+//   - 400 (VALIDACAO) para ?limite fora de 1..100 ou ?antes inválido.
+//   - 200 + {itens, proximo}; proximo = null na última página.
+func AdminUsuariosHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+
+		q := r.URL.Query()
+		limite := adminUsuariosLimitePadrao
+		if v := q.Get("limite"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 || n > adminUsuariosLimiteMaximo {
+				writeAPIError(w, http.StatusBadRequest, apierr.Validacao, "Parâmetro limite inválido (1 a 100)")
+				return
+			}
+			limite = n
+		}
+		var antes int
+		if v := q.Get("antes"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n <= 0 {
+				writeAPIError(w, http.StatusBadRequest, apierr.Validacao, "Parâmetro antes inválido")
+				return
+			}
+			antes = n
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		pagina, err := model.ListarUsuariosAdmin(ctx, db, q.Get("busca"), antes, limite)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao listar usuários")
+			return
+		}
+		writeJSON(w, http.StatusOK, pagina)
+	}
+}
+
+// AdminBuscarUsuarioHandler trata GET /api/admin/usuario?email=...
+//
+// Regras/erros:
+//   - 405 se método != GET; 400 (VALIDACAO) sem ?email.
+//   - 404 se nenhuma conta tiver o e-mail.
+//   - 200 + conta (mesmo formato dos itens de GET /api/admin/usuarios).
+func AdminBuscarUsuarioHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+		email := strings.TrimSpace(r.URL.Query().Get("email"))
+		if email == "" {
+			writeAPIError(w, http.StatusBadRequest, apierr.Validacao, "E-mail não informado")
+			return
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		u, err := model.BuscarUsuarioAdmin(ctx, db, email)
+		if errors.Is(err, model.ErrUsuarioNaoEncontrado) {
+			writeAPIError(w, http.StatusNotFound, apierr.UsuarioNaoEncontrado, "Usuário não encontrado")
+			return
+		}
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao buscar usuário")
+			return
+		}
+		writeJSON(w, http.StatusOK, u)
+	}
+}
+
+// BloquearUsuarioHandler trata PUT /api/admin/usuarios/{id}/bloquear
+//
+// Corpo opcional: {"bloqueado": true|false} (omitido = bloquear).
+//
+// Regras/erros:
+//   - 405 se método != PUT; 400 se id/JSON inválido.
+//   - 400 (PROPRIA_CONTA) ao tentar bloquear a própria conta.
+//   - 404 se a conta não existir.
+//   - 200 + conta atualizada; o acesso em cache da conta é descartado.
+func BloquearUsuarioHandler(db *sql.DB, acessos *model.CacheAcesso) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+		suporte, err := acessoFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+		id, ok := pathID(r, "id")
+		if !ok {
+			writeAPIError(w, http.StatusBadRequest, apierr.IDInvalido, "ID do usuário inválido")
+			return
+		}
+		var in model.BloqueioRequest
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil && err != io.EOF {
+			writeDecodeError(w, err)
+			return
+		}
+		bloquear := in.Bloqueado == nil || *in.Bloqueado
+		if bloquear && id == suporte.UsuarioID {
+			writeAPIError(w, http.StatusBadRequest, apierr.PropriaConta, model.ErrPropriaConta.Error())
+			return
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		u, err := model.BloquearUsuario(ctx, db, id, bloquear)
+		if errors.Is(err, model.ErrUsuarioNaoEncontrado) {
+			writeAPIError(w, http.StatusNotFound, apierr.UsuarioNaoEncontrado, err.Error())
+			return
+		}
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao atualizar usuário")
+			return
+		}
+		acessos.InvalidarEmail(ctx, u.Email)
+		logging.De(ctx).Info("admin: bloqueio de conta alterado",
+			"suporte_id", suporte.UsuarioID, "usuario_id", id, "bloqueado", bloquear)
+
+		writeJSON(w, http.StatusOK, u)
+	}
+}
+
+// ExcluirUsuarioHandler trata DELETE /api/admin/usuarios/{id}
+//
+// Regras/erros:
+//   - 405 se método != DELETE; 400 se id inválido.
+//   - 400 (PROPRIA_CONTA) ao tentar excluir a própria conta.
+//   - 404 se a conta não existir.
+//   - 409 (DONO_COM_MEMBROS) se a conta for dona de organização com outros membros.
+//   - 204 em sucesso; o acesso em cache da conta é descartado.
+func ExcluirUsuarioHandler(db *sql.DB, acessos *model.CacheAcesso) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+		suporte, err := acessoFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+		id, ok := pathID(r, "id")
+		if !ok {
+			writeAPIError(w, http.StatusBadRequest, apierr.IDInvalido, "ID do usuário inválido")
+			return
+		}
+		if id == suporte.UsuarioID {
+			writeAPIError(w, http.StatusBadRequest, apierr.PropriaConta, model.ErrPropriaConta.Error())
+			return
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		email, err := model.ExcluirUsuario(ctx, db, id)
+		switch {
+		case errors.Is(err, model.ErrUsuarioNaoEncontrado):
+			writeAPIError(w, http.StatusNotFound, apierr.UsuarioNaoEncontrado, err.Error())
+			return
+		case errors.Is(err, model.ErrDonoComMembros):
+			writeAPIError(w, http.StatusConflict, apierr.DonoComMembros, err.Error())
+			return
+		case err != nil:
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao excluir usuário")
+			return
+		}
+		acessos.InvalidarEmail(ctx, email)
+		logging.De(ctx).Info("admin: conta excluída", "suporte_id", suporte.UsuarioID, "usuario_id", id)
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// PersonificarHandler trata POST /api/admin/impersonate
+//
+// Corpo: {"usuario_id": 12, "motivo": "chamado #123"}.
+//
+// Regras/erros:
+//   - 405 se método != POST; 400 se JSON inválido ou sem usuario_id/motivo.
+//   - 404 se a conta não existir.
+//   - 403 (PERSONIFICACAO_NAO_PERMITIDA) para contas de suporte (inclusive a própria) ou bloqueadas.
+//   - 201 + {token, expira_em...}; o token vale model.PersonificacaoTTL e só aparece nesta resposta.
+func PersonificarHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+		suporte, err := acessoFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+		// além do ExigirSuporte do grupo: token de personificação só com a sessão do login
+		if suporte.SessaoID == 0 {
+			writeAPIError(w, http.StatusUnauthorized, apierr.SessaoObrigatoria, "Personificar exige a sessão do login (Authorization: Bearer)")
+			return
+		}
+		var in model.PersonificarRequest
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			writeDecodeError(w, err)
+			return
+		}
+		in.Sanitize()
+		if err := in.Validate(); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		p, err := model.CriarPersonificacao(ctx, db, suporte.UsuarioID, in)
+		switch {
+		case errors.Is(err, model.ErrUsuarioNaoEncontrado):
+			writeAPIError(w, http.StatusNotFound, apierr.UsuarioNaoEncontrado, err.Error())
+			return
+		case errors.Is(err, model.ErrPersonificarSuporte), errors.Is(err, model.ErrPersonificarBloqueado):
+			writeAPIError(w, http.StatusForbidden, apierr.PersonificacaoNaoPermitida, err.Error())
+			return
+		case err != nil:
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao iniciar personificação")
+			return
+		}
+		logging.De(ctx).Info("admin: personificação iniciada",
+			"suporte_id", suporte.UsuarioID, "usuario_id", p.UsuarioID, "personificacao_id", p.ID, "motivo", in.Motivo)
+
+		writeJSON(w, http.StatusCreated, p)
+	}
+}
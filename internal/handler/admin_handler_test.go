@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"database/sql/driver"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"backend/internal/middleware"
+	"backend/internal/model"
+)
+
+// Rotas /api/admin: Autenticacao + ExigirSuporte, como no grupo de cmd/server/main.go.
+func TestAdminExigeSessaoDoSuporte(t *testing.T) {
+	colunasAcesso := []string{"id", "dono_id", "organizacao_id", "papel", "suporte"}
+	casos := []struct {
+		nome      string
+		cabecalho map[string]string
+		suporte   bool
+		status    int
+		code      string
+	}{
+		{"X-User-Email do suporte sem sessão", map[string]string{"X-User-Email": "suporte@tecmise.com"}, true,
+			http.StatusUnauthorized, "SESSAO_OBRIGATORIA"},
+		{"sem cabeçalho", nil, true, http.StatusUnauthorized, "NAO_AUTENTICADO"},
+		{"sessão do suporte", map[string]string{"Authorization": "Bearer tok-suporte"}, true, http.StatusNoContent, ""},
+		{"sessão de conta comum", map[string]string{"Authorization": "Bearer tok-ana"}, false, http.StatusForbidden, "SEM_PERMISSAO"},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			db := bancoDeTeste(t,
+				resultadoRoteiro{trecho: "FROM sessoes s", colunas: []string{"id", "email", "ultimo_uso"},
+					linhas: [][]driver.Value{{int64(3), "suporte@tecmise.com", time.Now()}}},
+				resultadoRoteiro{trecho: "LEFT JOIN organizacao_membros", colunas: colunasAcesso,
+					linhas: [][]driver.Value{{int64(1), int64(1), int64(0), "admin", c.suporte}}},
+			)
+			chamado := false
+			rota := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				chamado = true
+				w.WriteHeader(http.StatusNoContent)
+			})
+			h := middleware.Autenticacao(db, nil, false)(middleware.ExigirSuporte(rota))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/admin/impersonate", nil)
+			for k, v := range c.cabecalho {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != c.status {
+				t.Fatalf("status = %d, esperado %d (corpo = %s)", rec.Code, c.status, rec.Body)
+			}
+			if c.code != "" && !strings.Contains(rec.Body.String(), `"code":"`+c.code+`"`) {
+				t.Errorf("corpo sem code %s: %s", c.code, rec.Body)
+			}
+			if chamado != (c.status == http.StatusNoContent) {
+				t.Errorf("handler chamado = %v com status %d", chamado, rec.Code)
+			}
+		})
+	}
+}
+
+// Sem a sessão do login o handler recusa mesmo fora do grupo /api/admin.
+func TestPersonificarExigeSessao(t *testing.T) {
+	db := bancoDeTeste(t)
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/impersonate", strings.NewReader(`{"usuario_id": 12, "motivo": "chamado #123"}`))
+	req = req.WithContext(middleware.ComAcesso(req.Context(), model.Acesso{UsuarioID: 1, TenantID: 1, Suporte: true}))
+	rec := httptest.NewRecorder()
+	PersonificarHandler(db).ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized || !strings.Contains(rec.Body.String(), "SESSAO_OBRIGATORIA") {
+		t.Fatalf("status = %d, corpo = %s", rec.Code, rec.Body)
+	}
+}
+
+// Toda escrita com X-Impersonation-Token vai para a auditoria, mesmo sem registrarAtividade no handler.
+func TestPersonificacaoAuditaEscritas(t *testing.T) {
+	for _, metodo := range []string{http.MethodGet, http.MethodPost, http.MethodDelete} {
+		t.Run(metodo, func(t *testing.T) {
+			db := bancoDeTeste(t,
+				resultadoRoteiro{trecho: "FROM personificacoes p", colunas: []string{"suporte_id", "email"},
+					linhas: [][]driver.Value{{int64(1), "ana@escola.com"}}},
+				resultadoRoteiro{trecho: "LEFT JOIN organizacao_membros", colunas: []string{"id", "dono_id", "organizacao_id", "papel", "suporte"},
+					linhas: [][]driver.Value{{int64(7), int64(7), int64(0), "admin", false}}},
+				resultadoRoteiro{trecho: "INSERT INTO auditoria", linhas: [][]driver.Value{{}}},
+			)
+			escritas := escritasDeTeste(t)
+			rota := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				a, _ := middleware.AcessoDe(r.Context())
+				if a.UsuarioID != 7 || a.PersonificadoPor != 1 {
+					t.Errorf("acesso = %+v", a)
+				}
+				w.WriteHeader(http.StatusAccepted)
+			})
+			req := httptest.NewRequest(metodo, "/api/estudantes/5/status", nil)
+			req.Header.Set(middleware.HeaderPersonificacao, "tok-personificacao")
+			rec := httptest.NewRecorder()
+			middleware.Autenticacao(db, nil, false)(rota).ServeHTTP(rec, req)
+			if rec.Code != http.StatusAccepted {
+				t.Fatalf("status = %d, corpo = %s", rec.Code, rec.Body)
+			}
+
+			if metodo == http.MethodGet {
+				if len(*escritas) != 0 {
+					t.Fatalf("leitura auditada: %+v", *escritas)
+				}
+				return
+			}
+			if len(*escritas) != 1 {
+				t.Fatalf("escritas = %+v, esperada 1 na auditoria", *escritas)
+			}
+			args := (*escritas)[0].args
+			// usuario_id, autor_id, acao, entidade, entidade_id, resumo, dados, personificado_por
+			if args[1] != 7 || args[2] != model.AcaoRequisicaoPersonificada || args[7] != 1 {
+				t.Errorf("auditoria com argumentos inesperados: %v", args)
+			}
+			if args[5] != metodo+" /api/estudantes/5/status" || !strings.Contains(args[6].(string), `"status":202`) {
+				t.Errorf("resumo/dados = %v / %v", args[5], args[6])
+			}
+		})
+	}
+}
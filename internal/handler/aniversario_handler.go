@@ -0,0 +1,90 @@
+// ============================================================================
+// 📄 handler/aniversario_handler.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - GET /api/estudantes/aniversariantes → próximos aniversários dos estudantes ativos.
+//   * sem parâmetros: de hoje até 30 dias à frente (?dias=1–366 muda a janela).
+//   * ?mes=1–12: aniversários do mês (deste ano, ou do próximo se o mês já passou);
+//     no mês corrente, dias_restantes negativo indica aniversário já passado.
+// - O resumo semanal por e-mail é feito pelo job jobs.Aniversarios.
+//
+// 🔐 Autenticação/escopo
+// - `X-User-Email` obrigatório; apenas estudantes do próprio usuário (tenant).
+// ============================================================================
+
+package handler
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"backend/internal/apierr"
+	"backend/internal/model"
+)
+
+// janelas da listagem de aniversariantes
+const (
+	aniversariosDiasPadrao = 30
+	aniversariosDiasMaximo = 366
+)
+
+// AniversariantesHandler lista os aniversariantes da janela pedida, pela data.
+//
+// Regras/erros:
+//   - 405 se método != GET; 401 se não resolver usuário.
+//   - 400 (VALIDACAO) se ?mes não estiver em 1..12, ?dias em 1..366 ou ambos forem enviados.
+func AniversariantesHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+
+		uid, err := usuarioIDFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+
+		q := r.URL.Query()
+		if q.Get("mes") != "" && q.Get("dias") != "" {
+			writeAPIError(w, http.StatusBadRequest, apierr.Validacao, "Use mes ou dias, não ambos")
+			return
+		}
+		agora := time.Now()
+		hoje := time.Date(agora.Year(), agora.Month(), agora.Day(), 0, 0, 0, 0, agora.Location())
+		de, dias := hoje, aniversariosDiasPadrao
+		if v := q.Get("mes"); v != "" {
+			mes, err := strconv.Atoi(v)
+			if err != nil || mes < 1 || mes > 12 {
+				writeAPIError(w, http.StatusBadRequest, apierr.Validacao, "Parâmetro mes inválido (1 a 12)")
+				return
+			}
+			ano := hoje.Year()
+			if time.Month(mes) < hoje.Month() {
+				ano++
+			}
+			de = time.Date(ano, time.Month(mes), 1, 0, 0, 0, 0, hoje.Location())
+			dias = de.AddDate(0, 1, -1).Day() // dias no mês
+		}
+		if v := q.Get("dias"); v != "" {
+			dias, err = strconv.Atoi(v)
+			if err != nil || dias < 1 || dias > aniversariosDiasMaximo {
+				writeAPIError(w, http.StatusBadRequest, apierr.Validacao, "Parâmetro dias inválido (1 a 366)")
+				return
+			}
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		out, err := model.ListarAniversariantes(ctx, db, uid, de, dias, hoje)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao buscar aniversariantes")
+			return
+		}
+		writeJSON(w, http.StatusOK, out)
+	}
+}
@@ -0,0 +1,698 @@
+// ============================================================================
+// 📄 handler/ano_handler.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - Endpoints REST para gerenciamento de "Ano/Turma" (tabela: anos)
+//   * Listar anos do usuário autenticado
+//   * Criar novo ano vinculado ao usuário (entra no fim da ordem)
+//   * Criar anos em lote a partir de template (POST /api/anos/bulk)
+//   * Reordenar anos (PUT /api/anos/reorder) e arquivar/desarquivar (PUT /api/anos/{id}/arquivar)
+//   * Remover ano do usuário (bloqueado se houver estudantes, salvo ?force=true
+//     para cascata ou ?move_to_ano_id=N para realocar os estudantes antes)
+//
+// 🔐 Autenticação
+// - Baseada no cabeçalho HTTP `X-User-Email` (email do usuário já autenticado).
+// - O helper `usuarioIDFromHeader` resolve o `usuario_id` a partir desse e-mail
+//   (resolvido uma vez por requisição pelo middleware Autenticacao, com cache).
+// - Todas as rotas retornam 401 quando o cabeçalho não existe ou não encontra usuário.
+//
+// 🧱 Regras de escopo/segurança
+// - Todas as queries incluem `usuario_id = $UID` para isolar os dados por dono.
+// - A remoção é transacional: trata os estudantes do ano (erro 409, cascata ou
+//   realocação) e, depois, apaga o ano.
+// - Retorna 404 quando o ano não pertencer ao usuário ou não existir.
+//
+// 📤 Formato das respostas
+// - JSON (`Content-Type: application/json; charset=utf-8`) para retornos com corpo.
+// - 204 (No Content) para deleção bem-sucedida.
+// - Erros com mensagens claras e status apropriados.
+// ============================================================================
+
+package handler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/internal/apierr"
+	"backend/internal/cache"
+	"backend/internal/jobs"
+	"backend/internal/logging"
+	"backend/internal/middleware"
+	"backend/internal/model"
+)
+
+// Ano representa um registro da tabela `anos`.
+type Ano struct {
+	ID                   int    `json:"id"`                    // identificador do ano/turma
+	Nome                 string `json:"nome"`                  // nome exibido (ex.: "8º A")
+	QuantidadeEstudantes int    `json:"quantidade_estudantes"` // estudantes vinculados (não excluídos)
+	Ordem                int    `json:"ordem"`                 // posição definida pelo usuário
+	Arquivado            bool   `json:"arquivado"`             // oculto da listagem padrão
+	PeriodoLetivoID      int    `json:"periodo_letivo_id,omitempty"`
+}
+
+// anosEmCache é uma variante (arquivados/período) de GET /api/anos guardada no cache,
+// com a marca usada no ETag.
+type anosEmCache struct {
+	Ultima          time.Time `json:"ultima"`
+	TotalAnos       int       `json:"total_anos"`
+	TotalEstudantes int       `json:"total_estudantes"`
+	Anos            []Ano     `json:"anos"`
+}
+
+// usuarioIDFromHeader resolve o id usado no escopo dos dados a partir do cabeçalho X-User-Email.
+//
+// Fluxo:
+//  1. Lê e normaliza o valor de "X-User-Email".
+//  2. Busca o usuário na tabela `usuarios` (e sua organização, se houver).
+//  3. Retorna (id, nil) quando encontra; caso contrário retorna erro.
+//
+// Observação: para membros de organização o id retornado é o do dono da
+// organização (tenant), pois é com ele que os registros são gravados.
+//
+// Retorna:
+//   - (0, sql.ErrNoRows) quando o header está vazio ou não encontra usuário.
+//   - Outros erros de banco quando a query falha.
+func usuarioIDFromHeader(db *sql.DB, r *http.Request) (int, error) {
+	a, err := acessoFromHeader(db, r)
+	return a.TenantID, err
+}
+
+// acessoFromHeader resolve usuário autenticado, tenant e papel a partir do X-User-Email.
+// Usa o que o middleware Autenticacao já pôs no context; só consulta o banco sem ele.
+func acessoFromHeader(db *sql.DB, r *http.Request) (model.Acesso, error) {
+	if a, ok := middleware.AcessoDe(r.Context()); ok {
+		return a, nil
+	}
+	email := model.NormalizarEmail(r.Header.Get("X-User-Email"))
+	if email == "" {
+		return model.Acesso{}, sql.ErrNoRows
+	}
+	ctx, cancel := contextoBanco(r)
+	defer cancel()
+
+	return model.ResolverAcesso(ctx, db, email)
+}
+
+// ListarAnosHandler trata GET /api/anos
+//
+// Regras/erros:
+//   - 401 se não conseguir resolver o usuário pelo header.
+//   - 500 se houver falha ao consultar/iterar o banco.
+//   - 200 + JSON com array de anos quando OK.
+//
+// Cada ano traz quantidade_estudantes calculada na mesma query (LEFT JOIN + COUNT),
+// evitando uma requisição por ano no dashboard.
+// Anos arquivados ficam de fora, exceto com ?arquivados=true. Ordena por ordem, id.
+// ?periodo_letivo_id=N restringe aos anos daquele período letivo.
+// Suporta GET condicional (ETag/Last-Modified → 304) para os polls do dashboard.
+// A lista (com a marca do ETag) fica em cache por ttl; escritas do tenant a invalidam
+// (middleware.InvalidarCacheDados). ttl 0 consulta sempre o banco.
+func ListarAnosHandler(db *sql.DB, c cache.Cache, ttl time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid, err := usuarioIDFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		incluirArquivados := r.URL.Query().Get("arquivados") == "true"
+		periodoID := 0
+		if v := r.URL.Query().Get("periodo_letivo_id"); v != "" {
+			if periodoID, err = strconv.Atoi(v); err != nil || periodoID <= 0 {
+				writeAPIError(w, http.StatusBadRequest, apierr.IDInvalido, "periodo_letivo_id inválido")
+				return
+			}
+		}
+
+		variante := fmt.Sprintf("%t:%d", incluirArquivados, periodoID)
+		var emCache map[string]anosEmCache
+		if ttl > 0 && cache.ObterJSON(ctx, c, model.ChaveCacheAnos(uid), &emCache) {
+			if v, ok := emCache[variante]; ok {
+				if responderCondicional(w, r, v.Ultima, uid, v.TotalAnos, v.TotalEstudantes) {
+					return
+				}
+				w.Header().Set("Content-Type", "application/json; charset=utf-8")
+				_ = json.NewEncoder(w).Encode(v.Anos)
+				return
+			}
+		}
+
+		// marca: anos e estudantes (quantidade_estudantes depende dos dois)
+		var (
+			totalAnos, totalEstudantes int
+			ultimaAno, ultimaEstudante time.Time
+		)
+		if err := db.QueryRowContext(ctx, `
+			SELECT (SELECT COUNT(*) FROM anos WHERE usuario_id = $1 AND excluido_em IS NULL),
+			       (SELECT COALESCE(MAX(atualizado_em), 'epoch') FROM anos WHERE usuario_id = $1),
+			       (SELECT COUNT(*) FROM estudantes WHERE usuario_id = $1 AND excluido_em IS NULL),
+			       (SELECT COALESCE(MAX(atualizado_em), 'epoch') FROM estudantes WHERE usuario_id = $1)
+		`, uid).Scan(&totalAnos, &ultimaAno, &totalEstudantes, &ultimaEstudante); err != nil {
+			logging.De(r.Context()).Error("marca de anos", "erro", err)
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao listar anos")
+			return
+		}
+		ultima := ultimaAno
+		if ultimaEstudante.After(ultima) {
+			ultima = ultimaEstudante
+		}
+		if responderCondicional(w, r, ultima, uid, totalAnos, totalEstudantes) {
+			return
+		}
+
+		rows, err := db.QueryContext(ctx, `
+			SELECT a.id, a.nome, COUNT(e.id), a.ordem, a.arquivado, COALESCE(a.periodo_letivo_id, 0)
+			  FROM anos a
+			  LEFT JOIN estudantes e
+			         ON e.ano_id = a.id AND e.usuario_id = a.usuario_id AND e.excluido_em IS NULL
+			 WHERE a.usuario_id = $1 AND a.excluido_em IS NULL AND ($2 OR NOT a.arquivado)
+			   AND ($3 = 0 OR a.periodo_letivo_id = $3)
+			 GROUP BY a.id, a.nome, a.ordem, a.arquivado, a.periodo_letivo_id
+			 ORDER BY a.ordem ASC, a.id ASC
+		`, uid, incluirArquivados, periodoID)
+		if err != nil {
+			logging.De(r.Context()).Error("listar anos", "erro", err)
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao listar anos")
+			return
+		}
+		defer rows.Close()
+
+		var anos []Ano
+		for rows.Next() {
+			var a Ano
+			if err := rows.Scan(&a.ID, &a.Nome, &a.QuantidadeEstudantes, &a.Ordem, &a.Arquivado, &a.PeriodoLetivoID); err != nil {
+				logging.De(r.Context()).Error("ler ano", "erro", err)
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao ler ano")
+				return
+			}
+			anos = append(anos, a)
+		}
+		if err := rows.Err(); err != nil {
+			logging.De(r.Context()).Error("iterar anos", "erro", err)
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao iterar anos")
+			return
+		}
+		if ttl > 0 {
+			if emCache == nil {
+				emCache = map[string]anosEmCache{}
+			}
+			emCache[variante] = anosEmCache{Ultima: ultima, TotalAnos: totalAnos, TotalEstudantes: totalEstudantes, Anos: anos}
+			cache.GuardarJSON(ctx, c, model.ChaveCacheAnos(uid), emCache, ttl)
+		}
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		_ = json.NewEncoder(w).Encode(anos)
+	}
+}
+
+// CriarAnoHandler trata POST /api/anos
+//
+// Corpo esperado (JSON):
+//
+//	{ "nome": "8º A", "periodo_letivo_id": 1 }   // periodo_letivo_id opcional
+//
+// Regras/erros:
+//   - 401 se não resolver usuário.
+//   - 400 se JSON inválido ou nome vazio.
+//   - 404 se o período letivo não pertencer ao usuário; 409 se estiver encerrado.
+//   - 409 se já existir ano com o mesmo nome (sem diferenciar maiúsculas) para o usuário.
+//   - 500 em erro de inserção.
+//   - 201 + JSON { id, nome } quando criado (registrado no feed de atividades).
+func CriarAnoHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		acesso, err := acessoFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+		uid := acesso.TenantID
+
+		var input struct {
+			Nome            string `json:"nome"`
+			PeriodoLetivoID int    `json:"periodo_letivo_id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			writeDecodeError(w, err)
+			return
+		}
+		input.Nome = strings.TrimSpace(input.Nome)
+		if input.Nome == "" {
+			writeAPIError(w, http.StatusBadRequest, apierr.Validacao, "Nome do ano obrigatório")
+			return
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		if input.PeriodoLetivoID != 0 {
+			existe, fechado, err := periodoDoUsuario(ctx, db, input.PeriodoLetivoID, uid)
+			if err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao verificar período letivo")
+				return
+			}
+			if !existe {
+				writeAPIError(w, http.StatusNotFound, apierr.PeriodoNaoEncontrado, "Período letivo não encontrado")
+				return
+			}
+			if fechado {
+				writeAPIError(w, http.StatusConflict, apierr.PeriodoFechado, model.ErrPeriodoFechado.Error())
+				return
+			}
+		}
+
+		var novoID, ordem int
+		err = db.QueryRowContext(ctx, `
+			INSERT INTO anos (nome, usuario_id, ordem, periodo_letivo_id)
+			VALUES ($1, $2, (SELECT COALESCE(MAX(ordem), 0) + 1 FROM anos WHERE usuario_id = $2), NULLIF($3, 0))
+			RETURNING id, ordem
+		`, input.Nome, uid, input.PeriodoLetivoID).Scan(&novoID, &ordem)
+		if status, code, msg, ok := mapPQError(err); ok {
+			writeAPIError(w, status, code, msg)
+			return
+		}
+		if err != nil {
+			logging.De(r.Context()).Error("criar ano", "erro", err)
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao criar ano")
+			return
+		}
+		registrarAtividade(ctx, db, acesso, model.AcaoAnoCriado, novoID, input.Nome, nil)
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"id":                novoID,
+			"nome":              input.Nome,
+			"ordem":             ordem,
+			"periodo_letivo_id": input.PeriodoLetivoID,
+		})
+	}
+}
+
+// RemoverAnoHandler trata DELETE /api/anos/{id}[?force=true|?move_to_ano_id=N]
+//
+// Regras/erros:
+//   - 405 se método != DELETE.
+//   - 401 se não resolver usuário.
+//   - 400 se id ausente ou inválido, ou move_to_ano_id inválido/igual ao próprio ano.
+//   - 404 se o ano (ou o ano de destino) não existir para esse usuário.
+//   - 409 + JSON { error, quantidade_estudantes } se houver estudantes vinculados
+//     e nenhuma das opções (force / move_to_ano_id) for informada.
+//   - 409 (CONFLITO) se a transação continuar em conflito com outra após as repetições.
+//   - 500 se falhar iniciar/execução/commit da transação.
+//   - 204 (No Content) quando removido com sucesso.
+//
+// O ano (e, com force=true, os estudantes vinculados) vai para a lixeira com o
+// mesmo excluido_em, para ser restaurado junto (ver lixeira_handler.go).
+// Após o commit publica ano.removed (e estudante.deleted para cada estudante
+// apagado com force=true) para os webhooks do usuário e registra a remoção no
+// feed de atividades.
+func RemoverAnoHandler(db *sql.DB, wh *jobs.Webhooks) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+
+		acesso, err := acessoFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+		uid := acesso.TenantID
+
+		// Extrai o id da rota e valida
+		id, ok := pathID(r, "id")
+		if !ok {
+			writeAPIError(w, http.StatusBadRequest, apierr.IDInvalido, "ID do ano/turma inválido")
+			return
+		}
+
+		force := r.URL.Query().Get("force") == "true"
+		moverPara := 0
+		if v := strings.TrimSpace(r.URL.Query().Get("move_to_ano_id")); v != "" {
+			moverPara, err = strconv.Atoi(v)
+			if err != nil || moverPara <= 0 || moverPara == id {
+				writeAPIError(w, http.StatusBadRequest, apierr.IDInvalido, "move_to_ano_id inválido")
+				return
+			}
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		var (
+			nome       string
+			vinculados int
+			removidos  []int // estudantes apagados em cascata (eventos de webhook)
+		)
+		err = model.ComTransacao(ctx, db, func(tx *sql.Tx) error {
+			removidos = nil
+
+			// 1) verifica o ano (nome vai para o feed) e conta os estudantes vinculados
+			err := tx.QueryRowContext(ctx,
+				`SELECT nome FROM anos WHERE id=$1 AND usuario_id=$2 AND excluido_em IS NULL`,
+				id, uid,
+			).Scan(&nome)
+			if err == sql.ErrNoRows {
+				return falhar(http.StatusNotFound, apierr.AnoNaoEncontrado, "Ano/Turma não encontrado")
+			}
+			if err != nil {
+				return err
+			}
+			if err := tx.QueryRowContext(ctx,
+				`SELECT COUNT(*) FROM estudantes WHERE ano_id=$1 AND usuario_id=$2 AND excluido_em IS NULL`,
+				id, uid,
+			).Scan(&vinculados); err != nil {
+				return err
+			}
+
+			// 2) trata os estudantes: realoca, apaga (force) ou bloqueia
+			switch {
+			case vinculados == 0:
+			case moverPara > 0:
+				var existe bool
+				if err := tx.QueryRowContext(ctx,
+					`SELECT EXISTS(SELECT 1 FROM anos WHERE id=$1 AND usuario_id=$2 AND excluido_em IS NULL)`,
+					moverPara, uid,
+				).Scan(&existe); err != nil {
+					return err
+				}
+				if !existe {
+					return falhar(http.StatusNotFound, apierr.AnoNaoEncontrado, "Ano/Turma de destino não encontrado")
+				}
+				if _, err := tx.ExecContext(ctx,
+					`UPDATE estudantes SET ano_id=$1 WHERE ano_id=$2 AND usuario_id=$3 AND excluido_em IS NULL`,
+					moverPara, id, uid,
+				); err != nil {
+					return err
+				}
+			case force:
+				rows, err := tx.QueryContext(ctx, `
+					UPDATE estudantes SET excluido_em=NOW(), excluido_por=NULLIF($3, 0)
+					 WHERE ano_id=$1 AND usuario_id=$2 AND excluido_em IS NULL
+					RETURNING id
+				`, id, uid, acesso.UsuarioID)
+				if err != nil {
+					return err
+				}
+				defer rows.Close()
+				for rows.Next() {
+					var estID int
+					if err := rows.Scan(&estID); err != nil {
+						return err
+					}
+					removidos = append(removidos, estID)
+				}
+				if err := rows.Err(); err != nil {
+					return err
+				}
+			default:
+				return &falhaTx{
+					status:  http.StatusConflict,
+					code:    apierr.AnoComEstudantes,
+					msg:     "Ano/Turma possui estudantes vinculados; use force=true ou move_to_ano_id",
+					details: map[string]int{"quantidade_estudantes": vinculados},
+				}
+			}
+
+			// 3) move o ano pertencente ao dono para a lixeira
+			res, err := tx.ExecContext(ctx, `
+				UPDATE anos SET excluido_em=NOW(), excluido_por=NULLIF($3, 0)
+				 WHERE id=$1 AND usuario_id=$2 AND excluido_em IS NULL
+			`, id, uid, acesso.UsuarioID)
+			if err != nil {
+				return err
+			}
+			// Se nenhuma linha foi afetada, o registro não existe/pertence ao usuário
+			if aff, _ := res.RowsAffected(); aff == 0 {
+				return falhar(http.StatusNotFound, apierr.AnoNaoEncontrado, "Ano/Turma não encontrado")
+			}
+			return nil
+		})
+		if err != nil {
+			escreverFalhaTx(w, err, "Erro ao remover ano/turma")
+			return
+		}
+
+		// 4) notifica webhooks: cada estudante apagado em cascata e o próprio ano
+		for _, estID := range removidos {
+			publicarEvento(ctx, wh, uid, model.EventoEstudanteRemovido, map[string]int{"id": estID})
+		}
+		evento := map[string]int{"id": id, "estudantes_removidos": len(removidos)}
+		if moverPara > 0 {
+			evento["estudantes_movidos"] = vinculados
+			evento["move_to_ano_id"] = moverPara
+		}
+		publicarEvento(ctx, wh, uid, model.EventoAnoRemovido, evento)
+		dados := map[string]any{"estudantes_removidos": len(removidos)}
+		if moverPara > 0 {
+			dados["estudantes_movidos"] = vinculados
+			dados["move_to_ano_id"] = moverPara
+		}
+		registrarAtividade(ctx, db, acesso, model.AcaoAnoRemovido, id, nome, dados)
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// ReordenarAnosHandler trata PUT /api/anos/reorder
+//
+// Corpo esperado (JSON):
+//
+//	{ "ids": [3, 1, 2] }
+//
+// Regras/erros:
+//   - 405 se método != PUT; 401 se não resolver usuário.
+//   - 400 se JSON inválido, lista vazia, ids repetidos ou de outro usuário.
+//   - 204 (No Content) quando reordenado; ordem = posição na lista (1..n).
+//   - Anos não enviados mantêm a ordem atual (ficam depois dos enviados se ordem maior).
+func ReordenarAnosHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+
+		uid, err := usuarioIDFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+
+		var input struct {
+			IDs []int `json:"ids"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			writeDecodeError(w, err)
+			return
+		}
+		if len(input.IDs) == 0 {
+			writeAPIError(w, http.StatusBadRequest, apierr.Validacao, "Lista de ids obrigatória")
+			return
+		}
+		vistos := make(map[int]bool, len(input.IDs))
+		for _, id := range input.IDs {
+			if id <= 0 || vistos[id] {
+				writeAPIError(w, http.StatusBadRequest, apierr.Validacao, "Lista de ids inválida (ids repetidos ou não positivos)")
+				return
+			}
+			vistos[id] = true
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao iniciar transação")
+			return
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		for i, id := range input.IDs {
+			res, err := tx.ExecContext(ctx,
+				`UPDATE anos SET ordem=$1 WHERE id=$2 AND usuario_id=$3 AND excluido_em IS NULL`,
+				i+1, id, uid,
+			)
+			if err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao reordenar anos")
+				return
+			}
+			if aff, _ := res.RowsAffected(); aff == 0 {
+				apierr.Escrever(w, http.StatusBadRequest, apierr.AnoNaoEncontrado, "Ano/Turma "+strconv.Itoa(id)+" não encontrado", map[string]int{"id": id})
+				return
+			}
+		}
+
+		if err := tx.Commit(); err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao confirmar reordenação")
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// ArquivarAnoHandler trata PUT /api/anos/{id}/arquivar
+//
+// Corpo opcional (JSON): { "arquivado": false } para desarquivar; sem corpo = arquivar.
+//
+// Regras/erros:
+//   - 405 se método != PUT; 401 se não resolver usuário.
+//   - 400 se id/JSON inválido; 404 se o ano não pertencer ao usuário.
+//   - 200 + JSON { id, arquivado } quando OK.
+func ArquivarAnoHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+
+		uid, err := usuarioIDFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+
+		id, ok := pathID(r, "id")
+		if !ok {
+			writeAPIError(w, http.StatusBadRequest, apierr.IDInvalido, "ID do ano/turma inválido")
+			return
+		}
+
+		input := struct {
+			Arquivado *bool `json:"arquivado"`
+		}{}
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil && err != io.EOF {
+			writeDecodeError(w, err)
+			return
+		}
+		arquivado := input.Arquivado == nil || *input.Arquivado
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		res, err := db.ExecContext(ctx,
+			`UPDATE anos SET arquivado=$1 WHERE id=$2 AND usuario_id=$3 AND excluido_em IS NULL`,
+			arquivado, id, uid,
+		)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao arquivar ano/turma")
+			return
+		}
+		if aff, _ := res.RowsAffected(); aff == 0 {
+			writeAPIError(w, http.StatusNotFound, apierr.AnoNaoEncontrado, "Ano/Turma não encontrado")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": id, "arquivado": arquivado})
+	}
+}
+
+// CriarAnosEmLoteHandler trata POST /api/anos/bulk
+//
+// Corpo esperado (JSON):
+//
+//	{ "serie": "1º ao 9º", "turmas": ["A", "B"] }
+//
+// Regras/erros:
+//   - 405 se método != POST; 401 se não resolver usuário.
+//   - 400 se JSON/template inválido (ver model.AnoTemplate).
+//   - 409 se algum nome gerado já existir para o usuário (nada é criado).
+//   - 201 + JSON [{ serie, anos: [{id, nome, ordem}] }] quando criado.
+func CriarAnosEmLoteHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+
+		acesso, err := acessoFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+		uid := acesso.TenantID
+
+		var input model.AnoTemplate
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			writeDecodeError(w, err)
+			return
+		}
+		input.Sanitize()
+		if err := input.Validate(); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+		series, err := input.Expandir()
+		if err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		type serieCriada struct {
+			Serie string `json:"serie"`
+			Anos  []Ano  `json:"anos"`
+		}
+		var out []serieCriada
+		err = model.ComTransacao(ctx, db, func(tx *sql.Tx) error {
+			out = make([]serieCriada, 0, len(series))
+			var ordem int
+			if err := tx.QueryRowContext(ctx,
+				`SELECT COALESCE(MAX(ordem), 0) FROM anos WHERE usuario_id=$1`, uid,
+			).Scan(&ordem); err != nil {
+				return err
+			}
+
+			for _, s := range series {
+				sc := serieCriada{Serie: s.Serie, Anos: []Ano{}}
+				for _, nome := range s.Nomes {
+					ordem++
+					a := Ano{Nome: nome, Ordem: ordem}
+					err := tx.QueryRowContext(ctx, `
+						INSERT INTO anos (nome, usuario_id, ordem)
+						VALUES ($1, $2, $3) RETURNING id
+					`, nome, uid, ordem).Scan(&a.ID)
+					if status, code, msg, ok := mapPQError(err); ok {
+						return &falhaTx{status: status, code: code, msg: msg, details: map[string]string{"nome": nome}}
+					}
+					if err != nil {
+						logging.De(r.Context()).Error("criar ano", "erro", err)
+						return err
+					}
+					sc.Anos = append(sc.Anos, a)
+				}
+				out = append(out, sc)
+			}
+			return nil
+		})
+		if err != nil {
+			escreverFalhaTx(w, err, "Erro ao criar anos")
+			return
+		}
+		for _, sc := range out {
+			for _, a := range sc.Anos {
+				registrarAtividade(ctx, db, acesso, model.AcaoAnoCriado, a.ID, a.Nome, nil)
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(out)
+	}
+}
@@ -0,0 +1,148 @@
+// ============================================================================
+// 📄 handler/atividade_handler.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - GET /api/atividades → feed de atividades recentes dos dados do usuário,
+//   derivado do log de auditoria ("Você criou o estudante X").
+//   * ?limite=1–100 (padrão 20); ?antes=<id> pagina (use "proximo" da resposta).
+//   * ?meus=true mostra só as ações da própria conta.
+// - registrarAtividade: usado pelos handlers de estudantes/anos após confirmar a operação.
+//
+// 🔐 Autenticação/escopo
+// - `X-User-Email` obrigatório; o feed cobre os dados do tenant (numa organização,
+//   inclui as ações dos demais membros, identificados pelo nome).
+// ============================================================================
+
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"backend/internal/apierr"
+	"backend/internal/logging"
+	"backend/internal/model"
+)
+
+// limites do feed de atividades
+const (
+	atividadesLimitePadrao = 20
+	atividadesLimiteMaximo = 100
+)
+
+// AtividadesHandler trata GET /api/atividades
+//
+// Regras/erros:
+//   - 405 se método != GET; 401 se não resolver usuário.
+//   - 400 (VALIDACAO) para ?limite fora de 1..100, ?antes inválido ou ?meus diferente de true/false.
+//   - 200 + {itens, proximo}; proximo = null na última página.
+func AtividadesHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+		acesso, err := acessoFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+
+		q := r.URL.Query()
+		limite := atividadesLimitePadrao
+		if v := q.Get("limite"); v != "" {
+			limite, err = strconv.Atoi(v)
+			if err != nil || limite < 1 || limite > atividadesLimiteMaximo {
+				writeAPIError(w, http.StatusBadRequest, apierr.Validacao, "Parâmetro limite inválido (1 a 100)")
+				return
+			}
+		}
+		var antes int64
+		if v := q.Get("antes"); v != "" {
+			antes, err = strconv.ParseInt(v, 10, 64)
+			if err != nil || antes <= 0 {
+				writeAPIError(w, http.StatusBadRequest, apierr.Validacao, "Parâmetro antes inválido")
+				return
+			}
+		}
+		meus := false
+		if v := q.Get("meus"); v != "" {
+			if meus, err = strconv.ParseBool(v); err != nil {
+				writeAPIError(w, http.StatusBadRequest, apierr.Validacao, "Parâmetro meus inválido (true ou false)")
+				return
+			}
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		// Busca um item a mais para saber se há próxima página.
+		rows, err := db.QueryContext(ctx, `
+			SELECT a.id, a.acao, a.entidade, a.entidade_id, a.resumo, a.dados, a.criado_em,
+			       COALESCE(a.autor_id, 0), COALESCE(u.nome, ''), a.personificado_por IS NOT NULL
+			  FROM auditoria a
+			  LEFT JOIN usuarios u ON u.id = a.autor_id
+			 WHERE a.usuario_id = $1
+			   AND ($2 = 0 OR a.id < $2)
+			   AND (NOT $3 OR a.autor_id = $4)
+			 ORDER BY a.id DESC
+			 LIMIT $5
+		`, acesso.TenantID, antes, meus, acesso.UsuarioID, limite+1)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao listar atividades")
+			return
+		}
+		defer rows.Close()
+
+		out := model.FeedAtividades{Itens: []model.Atividade{}}
+		for rows.Next() {
+			var (
+				a      model.Atividade
+				resumo string
+				dados  []byte
+			)
+			if err := rows.Scan(&a.ID, &a.Acao, &a.Entidade, &a.EntidadeID, &resumo, &dados, &a.CriadoEm,
+				&a.Autor.ID, &a.Autor.Nome, &a.Autor.Suporte); err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao ler atividades")
+				return
+			}
+			if err := json.Unmarshal(dados, &a.Dados); err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao ler atividades")
+				return
+			}
+			a.Autor.Voce = a.Autor.ID == acesso.UsuarioID
+			a.Descrever(resumo)
+			out.Itens = append(out.Itens, a)
+		}
+		if err := rows.Err(); err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao ler atividades")
+			return
+		}
+		if len(out.Itens) > limite {
+			out.Itens = out.Itens[:limite]
+			proximo := out.Itens[limite-1].ID
+			out.Proximo = &proximo
+		}
+		writeJSON(w, http.StatusOK, out)
+	}
+}
+
+// registrarAtividade grava a ação no log de auditoria. A operação principal já foi
+// confirmada, então uma falha aqui só é registrada em log.
+func registrarAtividade(ctx context.Context, db *sql.DB, acesso model.Acesso, acao string, entidadeID int, resumo string, dados map[string]any) {
+	err := model.RegistrarAtividade(ctx, db, model.NovaAtividade{
+		TenantID:         acesso.TenantID,
+		AutorID:          acesso.UsuarioID,
+		PersonificadoPor: acesso.PersonificadoPor,
+		Acao:             acao,
+		EntidadeID:       entidadeID,
+		Resumo:           resumo,
+		Dados:            dados,
+	})
+	if err != nil {
+		logging.De(ctx).Error("auditoria: falha ao registrar atividade", "acao", acao, "entidade_id", entidadeID, "erro", err)
+	}
+}
@@ -0,0 +1,223 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/handler/auth_google.go
+/// Responsabilidade: Endpoint de autenticação via Google Identity Services (GIS) utilizando validação de ID Token e upsert de usuário via repositório do pacote model.
+/// Dependências principais: google.golang.org/api/idtoken (via google_token.go), backend/internal/model (UserRepository), backend/internal/notificador, net/http.
+/// Pontos de atenção:
+/// - Requer a variável de ambiente GOOGLE_CLIENT_ID para validar o "aud" do token.
+/// - Validação com prazo próprio e disjuntor (google_token.go): Google fora do ar responde 503 GOOGLE_INDISPONIVEL, não 401.
+/// - Não verifica "email_verified" nas claims; considerar se necessário.
+/// - Erros retornados são genéricos por design (sem detalhes sensíveis); logs podem ser adicionados em camadas superiores.
+/// - Tamanho do body limitado por middleware.CorpoJSON (HTTP_MAX_BODY_BYTES). Content-Type exigido: application/json.
+/// - Reutiliza helpers writeJSON / writeJSONError (definidos no package) – este arquivo pressupõe sua existência no mesmo pacote.
+*/
+
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"backend/internal/apierr"
+	"backend/internal/model"
+	"backend/internal/notificador"
+)
+
+// 🔐 Login com Google (GIS) — usa o repositório do package model.
+// ⚠️ Não declaramos helpers writeJSON/writeJSONError aqui; reutilizamos os do package.
+
+/// ============ Tipos & Estruturas ============
+
+// erros de validarTokenGoogle (a mensagem vai na resposta 401)
+var (
+	errTokenGoogle       = errors.New("ID Token inválido para este CLIENT_ID")
+	errClaimsTokenGoogle = errors.New("Claims obrigatórias ausentes no token")
+)
+
+/**
+ * googleClaims são as claims do ID Token usadas pela API.
+ */
+type googleClaims struct {
+	Email string
+	Nome  string
+	Foto  string
+	Sub   string
+}
+
+/**
+ * AuthGoogleHandler encapsula dependências para o fluxo de login com Google.
+ * Campos:
+ *  - repo: implementação de model.UserRepository responsável por upsert de usuários.
+ *  - clientID: Client ID OAuth do Google (usado na validação do ID Token).
+ *  - nt/appURL: envio do e-mail de boas-vindas no primeiro login (conta criada pelo upsert).
+ *  - legado: AUTH_LEGACY_RESPONSE, responde só {id, nome, email} (formato anterior).
+ */
+type AuthGoogleHandler struct {
+	repo     model.UserRepository
+	clientID string
+	nt       *notificador.Notificador
+	appURL   string
+	legado   bool
+}
+
+/**
+ * NewAuthGoogleHandler cria uma instância do handler com o Client ID do Google (GOOGLE_CLIENT_ID, via config).
+ * A rota POST /login/google é registrada só em cmd/server/main.go (registrarRotas), com os middlewares do grupo.
+ * Exemplo:
+ *   h := handler.NewAuthGoogleHandler(model.NewUserRepo(db), cfg.GoogleClientID, nt, cfg.AppURL, cfg.LoginLegado)
+ */
+func NewAuthGoogleHandler(repo model.UserRepository, clientID string, nt *notificador.Notificador, appURL string, legado bool) *AuthGoogleHandler {
+	return &AuthGoogleHandler{
+		repo:     repo,
+		clientID: strings.TrimSpace(clientID),
+		nt:       nt,
+		appURL:   appURL,
+		legado:   legado,
+	}
+}
+
+// ===== DTOs =====
+
+/**
+ * googleLoginRequest representa o corpo aceito pelo endpoint,
+ * contemplando variações comuns que o GIS pode fornecer.
+ */
+type googleLoginRequest struct {
+	// Aceita as variações comuns do GIS
+	IDToken    string `json:"idToken"`
+	IDTokenAlt string `json:"id_token"`
+	Credential string `json:"credential"`
+}
+
+// ===== Handler =====
+
+/**
+ * LoginGoogle (POST /login/google)
+ *
+ * Fluxo:
+ *  1) Valida método HTTP (aceita apenas POST).
+ *  2) Garante presença de GOOGLE_CLIENT_ID.
+ *  3) Lê e parseia JSON do corpo (limite de middleware.CorpoJSON).
+ *  4) Extrai idToken de campos aceitos (idToken, id_token, credential).
+ *  5) Valida o ID Token com audience = GOOGLE_CLIENT_ID (GOOGLE_TOKEN_TIMEOUT; 503 se o Google estiver fora).
+ *  6) Extrai claims relevantes (email, name, picture, sub).
+ *  7) Upsert no repositório de usuários via model.UserRepository (conta nova → e-mail de boas-vindas em segundo plano)
+ *     e registro da tentativa no histórico de logins da conta.
+ *  8) Emite a sessão do dispositivo (tabela sessoes) e retorna 200 com model.RespostaLogin
+ *     (o mesmo de /login: usuário + token) em sucesso; erros com http.Status adequados.
+ *
+ * Efeitos colaterais:
+ *  - Prazos separados: GOOGLE_TOKEN_TIMEOUT na validação, DB_TIMEOUT_WRITE no upsert.
+ *  - Sem cookie: o token da sessão vai no corpo e o cliente o envia em Authorization: Bearer.
+ */
+func (h *AuthGoogleHandler) LoginGoogle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+		return
+	}
+	if h.clientID == "" {
+		writeAPIError(w, http.StatusInternalServerError, apierr.GoogleNaoConfigurado, "Servidor sem GOOGLE_CLIENT_ID configurado")
+		return
+	}
+
+	body, err := io.ReadAll(r.Body) // limitado por middleware.CorpoJSON
+	if err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	defer r.Body.Close()
+
+	var req googleLoginRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	// Aceita idToken em múltiplos campos (idToken, id_token, credential)
+	idToken := firstNonEmpty(req.IDToken, req.IDTokenAlt, req.Credential)
+	idToken = strings.TrimSpace(idToken)
+	if idToken == "" {
+		writeAPIError(w, http.StatusBadRequest, apierr.Validacao, "idToken é obrigatório")
+		return
+	}
+
+	// Valida o ID Token (audience = GOOGLE_CLIENT_ID) e extrai as claims
+	c, err := validarTokenGoogle(r.Context(), idToken, h.clientID)
+	if err != nil {
+		writeErroTokenGoogle(w, err)
+		return
+	}
+
+	// Upsert no repositório
+	ctx, cancel := contextoBanco(r)
+	defer cancel()
+	u, err := h.repo.UpsertFromGoogle(ctx, c.Nome, c.Email, c.Sub, c.Foto)
+	if err != nil || u == nil {
+		writeJSONError(w, http.StatusInternalServerError, "Falha ao autenticar com Google")
+		return
+	}
+	if u.Bloqueado {
+		registrarLogin(ctx, h.repo, r, u.ID, model.MetodoLoginGoogle, model.MotivoLoginContaBloqueada)
+		writeAPIError(w, http.StatusForbidden, apierr.ContaBloqueada, "Conta bloqueada. Entre em contato com o suporte.")
+		return
+	}
+	registrarLogin(ctx, h.repo, r, u.ID, model.MetodoLoginGoogle, "")
+	token, err := emitirSessao(ctx, h.repo, r, u.ID, model.MetodoLoginGoogle)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Falha ao autenticar com Google")
+		return
+	}
+	if u.Novo {
+		h.nt.EnviarEmSegundoPlano(r.Context(), u.Email, notificador.BoasVindas,
+			notificador.DadosBoasVindas{Nome: u.Nome, AppURL: h.appURL})
+	}
+
+	writeJSON(w, http.StatusOK, respostaLogin(u.Public(), token, h.legado, false))
+}
+
+// ===== helpers =====
+
+/**
+ * validarTokenGoogle valida o ID Token (audience = clientID) e extrai email, nome,
+ * foto e sub. errTokenGoogle se o token for inválido; errClaimsTokenGoogle sem email/sub;
+ * errGoogleIndisponivel se as chaves do Google não puderem ser obtidas (ver google_token.go).
+ * Nome vazio vira o e-mail. Também usado na vinculação de contas (vinculo_conta_handler.go).
+ */
+func validarTokenGoogle(ctx context.Context, token, clientID string) (googleClaims, error) {
+	payload, err := tokensGoogle.validar(ctx, token, clientID)
+	if errors.Is(err, errGoogleIndisponivel) {
+		return googleClaims{}, errGoogleIndisponivel
+	}
+	if err != nil {
+		return googleClaims{}, errTokenGoogle
+	}
+	var c googleClaims
+	c.Email, _ = payload.Claims["email"].(string)
+	c.Nome, _ = payload.Claims["name"].(string)
+	c.Foto, _ = payload.Claims["picture"].(string)
+	c.Sub, _ = payload.Claims["sub"].(string)
+	if c.Email == "" || c.Sub == "" {
+		return googleClaims{}, errClaimsTokenGoogle
+	}
+	if c.Nome == "" {
+		c.Nome = c.Email
+	}
+	return c, nil
+}
+
+/**
+ * firstNonEmpty retorna o primeiro valor não-vazio em uma lista de strings.
+ * Útil para aceitar múltiplos aliases do token no payload.
+ */
+func firstNonEmpty(vals ...string) string {
+	for _, v := range vals {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
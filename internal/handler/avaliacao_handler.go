@@ -0,0 +1,409 @@
+// ============================================================================
+// 📄 handler/avaliacao_handler.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - Avaliações por Ano/Turma e lançamento de notas (tabelas: avaliacoes, notas).
+//   * GET    /api/avaliacoes?ano_id=...        → lista avaliações (também ?periodo_letivo_id=...)
+//   * POST   /api/avaliacoes                   → cria avaliação
+//   * DELETE /api/avaliacoes/{id}              → remove (e suas notas)
+//   * GET    /api/avaliacoes/{id}/notas        → notas lançadas
+//   * PUT    /api/avaliacoes/{id}/notas        → lança/atualiza notas (upsert)
+//   * GET    /api/estudantes/{id}/boletim      → médias por disciplina e período (?periodo_letivo_id=...)
+//
+// 📅 Período letivo
+// - Anos de período letivo encerrado não aceitam novas avaliações nem notas (409).
+//
+// 🔐 Autenticação/escopo
+// - `X-User-Email` obrigatório; ano, avaliação e estudantes filtrados por usuario_id.
+//
+// 🧮 Boletim
+// - Média ponderada por peso, normalizada para 0–10 (valor/nota_maxima*10).
+// ============================================================================
+
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"backend/internal/apierr"
+	"backend/internal/model"
+)
+
+// AvaliacoesHandler trata GET/POST /api/avaliacoes.
+//
+// Regras/erros:
+//   - 401 se não resolver usuário.
+//   - 400 se JSON/filtro inválido; 404 se o ano não pertencer ao usuário.
+//   - 201 + avaliação criada / 200 + lista.
+func AvaliacoesHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid, err := usuarioIDFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		switch r.Method {
+		case http.MethodGet:
+			query := `
+				SELECT id, ano_id, disciplina, titulo, COALESCE(periodo,''), peso, nota_maxima,
+				       COALESCE(to_char(data, 'YYYY-MM-DD'), '')
+				  FROM avaliacoes
+				 WHERE usuario_id = $1`
+			args := []any{uid}
+			if v := r.URL.Query().Get("ano_id"); v != "" {
+				anoID, err := strconv.Atoi(v)
+				if err != nil || anoID <= 0 {
+					writeAPIError(w, http.StatusBadRequest, apierr.IDInvalido, "ano_id inválido")
+					return
+				}
+				args = append(args, anoID)
+				query += ` AND ano_id = $` + strconv.Itoa(len(args))
+			}
+			if v := r.URL.Query().Get("periodo_letivo_id"); v != "" {
+				periodoID, err := strconv.Atoi(v)
+				if err != nil || periodoID <= 0 {
+					writeAPIError(w, http.StatusBadRequest, apierr.IDInvalido, "periodo_letivo_id inválido")
+					return
+				}
+				args = append(args, periodoID)
+				query += ` AND ano_id IN (SELECT id FROM anos WHERE periodo_letivo_id = $` + strconv.Itoa(len(args)) + `)`
+			}
+			query += ` ORDER BY data NULLS LAST, id`
+
+			rows, err := db.QueryContext(ctx, query, args...)
+			if err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao listar avaliações")
+				return
+			}
+			defer rows.Close()
+
+			out := []model.Avaliacao{}
+			for rows.Next() {
+				var a model.Avaliacao
+				if err := rows.Scan(&a.ID, &a.AnoID, &a.Disciplina, &a.Titulo, &a.Periodo, &a.Peso, &a.NotaMaxima, &a.Data); err != nil {
+					writeJSONError(w, http.StatusInternalServerError, "Erro ao ler avaliação")
+					return
+				}
+				out = append(out, a)
+			}
+			if err := rows.Err(); err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao iterar avaliações")
+				return
+			}
+			writeJSON(w, http.StatusOK, out)
+
+		case http.MethodPost:
+			var in model.AvaliacaoCreateRequest
+			if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+				writeDecodeError(w, err)
+				return
+			}
+			in.Sanitize()
+			if err := in.Validate(); err != nil {
+				writeValidationError(w, err)
+				return
+			}
+			if ok, err := anoDoUsuario(ctx, db, in.AnoID, uid); err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao verificar ano/turma")
+				return
+			} else if !ok {
+				writeAPIError(w, http.StatusNotFound, apierr.AnoNaoEncontrado, "Ano/Turma não encontrado")
+				return
+			}
+			if fechado, err := anoEmPeriodoFechado(ctx, db, in.AnoID); err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao verificar período letivo")
+				return
+			} else if fechado {
+				writeAPIError(w, http.StatusConflict, apierr.PeriodoFechado, model.ErrPeriodoFechado.Error())
+				return
+			}
+
+			a := model.Avaliacao{
+				AnoID: in.AnoID, Disciplina: in.Disciplina, Titulo: in.Titulo,
+				Periodo: in.Periodo, Peso: in.Peso, NotaMaxima: in.NotaMaxima, Data: in.Data,
+			}
+			err := db.QueryRowContext(ctx, `
+				INSERT INTO avaliacoes (usuario_id, ano_id, disciplina, titulo, periodo, peso, nota_maxima, data)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8,'')::date)
+				RETURNING id
+			`, uid, a.AnoID, a.Disciplina, a.Titulo, a.Periodo, a.Peso, a.NotaMaxima, a.Data).Scan(&a.ID)
+			if err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao criar avaliação")
+				return
+			}
+			writeJSON(w, http.StatusCreated, a)
+
+		default:
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+		}
+	}
+}
+
+// avaliacaoDoUsuario lê {id} da rota e carrega ano e nota máxima da avaliação do usuário.
+// Em erro já responde (400/401/404/500) e devolve ok=false.
+func avaliacaoDoUsuario(ctx context.Context, w http.ResponseWriter, r *http.Request, db *sql.DB) (id, anoID, uid int, notaMaxima float64, ok bool) {
+	uid, err := usuarioIDFromHeader(db, r)
+	if err != nil {
+		writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+		return
+	}
+	id, valido := pathID(r, "id")
+	if !valido {
+		writeAPIError(w, http.StatusBadRequest, apierr.IDInvalido, "ID da avaliação inválido")
+		return
+	}
+
+	err = db.QueryRowContext(ctx,
+		`SELECT ano_id, nota_maxima FROM avaliacoes WHERE id=$1 AND usuario_id=$2`, id, uid,
+	).Scan(&anoID, &notaMaxima)
+	if err == sql.ErrNoRows {
+		writeAPIError(w, http.StatusNotFound, apierr.AvaliacaoNaoEncontrada, "Avaliação não encontrada")
+		return
+	}
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao buscar avaliação")
+		return
+	}
+	return id, anoID, uid, notaMaxima, true
+}
+
+// RemoverAvaliacaoHandler trata DELETE /api/avaliacoes/{id} (remove também as notas).
+//
+// Regras/erros:
+//   - 401 se não resolver usuário; 400 se id inválido.
+//   - 404 se a avaliação não pertencer ao usuário.
+func RemoverAvaliacaoHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		id, _, uid, _, ok := avaliacaoDoUsuario(ctx, w, r, db)
+		if !ok {
+			return
+		}
+		if _, err := db.ExecContext(ctx, `DELETE FROM avaliacoes WHERE id=$1 AND usuario_id=$2`, id, uid); err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao remover avaliação")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// NotasAvaliacaoHandler trata GET/PUT /api/avaliacoes/{id}/notas.
+//
+// Regras/erros:
+//   - 401 se não resolver usuário; 400 se id/JSON inválidos.
+//   - 404 se a avaliação não pertencer ao usuário.
+//   - 400 se algum estudante não for da turma da avaliação ou nota fora da escala.
+func NotasAvaliacaoHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodPut {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		id, anoID, uid, notaMaxima, ok := avaliacaoDoUsuario(ctx, w, r, db)
+		if !ok {
+			return
+		}
+		if r.Method == http.MethodPut {
+			lancarNotas(ctx, w, r, db, id, anoID, uid, notaMaxima)
+			return
+		}
+
+		rows, err := db.QueryContext(ctx, `
+			SELECT n.estudante_id, n.valor
+			  FROM notas n
+			 WHERE n.avaliacao_id=$1 AND n.usuario_id=$2
+			 ORDER BY n.estudante_id
+		`, id, uid)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao listar notas")
+			return
+		}
+		defer rows.Close()
+		out := []model.NotaItem{}
+		for rows.Next() {
+			var n model.NotaItem
+			if err := rows.Scan(&n.EstudanteID, &n.Valor); err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao ler nota")
+				return
+			}
+			out = append(out, n)
+		}
+		if err := rows.Err(); err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao iterar notas")
+			return
+		}
+		writeJSON(w, http.StatusOK, out)
+	}
+}
+
+// lancarNotas valida e grava (upsert) as notas em transação.
+func lancarNotas(ctx context.Context, w http.ResponseWriter, r *http.Request, db *sql.DB, avaliacaoID, anoID, uid int, notaMaxima float64) {
+	var in model.NotasRequest
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	if err := in.Validate(notaMaxima); err != nil {
+		writeValidationError(w, err)
+		return
+	}
+
+	if fechado, err := anoEmPeriodoFechado(ctx, db, anoID); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao verificar período letivo")
+		return
+	} else if fechado {
+		writeAPIError(w, http.StatusConflict, apierr.PeriodoFechado, model.ErrPeriodoFechado.Error())
+		return
+	}
+
+	ids := make([]int64, len(in.Notas))
+	for i, n := range in.Notas {
+		ids[i] = int64(n.EstudanteID)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao iniciar transação")
+		return
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var encontrados int
+	if err := tx.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM estudantes
+		 WHERE usuario_id=$1 AND ano_id=$2 AND id = ANY($3) AND excluido_em IS NULL
+	`, uid, anoID, model.Array(ids)).Scan(&encontrados); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao verificar estudantes")
+		return
+	}
+	if encontrados != len(ids) {
+		writeAPIError(w, http.StatusBadRequest, apierr.Validacao, "Há estudantes que não pertencem à turma da avaliação")
+		return
+	}
+
+	for _, n := range in.Notas {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO notas (avaliacao_id, estudante_id, usuario_id, valor)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (avaliacao_id, estudante_id) DO UPDATE SET valor=EXCLUDED.valor
+		`, avaliacaoID, n.EstudanteID, uid, n.Valor); err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao lançar nota")
+			return
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao confirmar notas")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]int{"lancadas": len(in.Notas)})
+}
+
+// BoletimHandler trata GET /api/estudantes/{id}/boletim.
+// Retorna { estudante_id, linhas: [...], media_geral } com médias ponderadas 0–10.
+func BoletimHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+
+		uid, err := usuarioIDFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+
+		estID, ok := pathID(r, "id")
+		if !ok {
+			writeAPIError(w, http.StatusBadRequest, apierr.IDInvalido, "ID do estudante inválido")
+			return
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		ok, err = estudanteDoUsuario(ctx, db, estID, uid)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao verificar estudante")
+			return
+		}
+		if !ok {
+			writeAPIError(w, http.StatusNotFound, apierr.EstudanteNaoEncontrado, "Estudante não encontrado")
+			return
+		}
+
+		periodoID := 0
+		if v := r.URL.Query().Get("periodo_letivo_id"); v != "" {
+			if periodoID, err = strconv.Atoi(v); err != nil || periodoID <= 0 {
+				writeAPIError(w, http.StatusBadRequest, apierr.IDInvalido, "periodo_letivo_id inválido")
+				return
+			}
+		}
+
+		rows, err := db.QueryContext(ctx, `
+			SELECT a.disciplina,
+			       COALESCE(a.periodo, ''),
+			       SUM(n.valor / a.nota_maxima * 10 * a.peso) / SUM(a.peso),
+			       COUNT(*),
+			       SUM(a.peso)
+			  FROM notas n
+			  JOIN avaliacoes a ON a.id = n.avaliacao_id
+			 WHERE n.estudante_id=$1 AND n.usuario_id=$2
+			   AND ($3 = 0 OR a.ano_id IN (SELECT id FROM anos WHERE periodo_letivo_id = $3))
+			 GROUP BY a.disciplina, COALESCE(a.periodo, '')
+			 ORDER BY a.disciplina, COALESCE(a.periodo, '')
+		`, estID, uid, periodoID)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao montar boletim")
+			return
+		}
+		defer rows.Close()
+
+		linhas := []model.BoletimLinha{}
+		var soma float64
+		for rows.Next() {
+			var l model.BoletimLinha
+			if err := rows.Scan(&l.Disciplina, &l.Periodo, &l.Media, &l.Avaliacoes, &l.PesoLancado); err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao ler boletim")
+				return
+			}
+			l.Media = float64(int(l.Media*100+0.5)) / 100
+			soma += l.Media
+			linhas = append(linhas, l)
+		}
+		if err := rows.Err(); err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao iterar boletim")
+			return
+		}
+
+		var mediaGeral float64
+		if len(linhas) > 0 {
+			mediaGeral = float64(int(soma/float64(len(linhas))*100+0.5)) / 100
+		}
+		writeJSON(w, http.StatusOK, map[string]any{
+			"estudante_id": estID,
+			"linhas":       linhas,
+			"media_geral":  mediaGeral,
+		})
+	}
+}
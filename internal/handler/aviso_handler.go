@@ -0,0 +1,211 @@
+// ============================================================================
+// 📄 handler/aviso_handler.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - Avisos curtos por SMS/WhatsApp aos responsáveis (mensageiro.Provedor):
+//   * POST   /api/avisos          → envia o texto (com os mesmos campos dos
+//     comunicados) ao telefone dos responsáveis dos estudantes/turmas
+//     selecionados; fica registrado como comunicado (canal sms/whatsapp)
+//   * GET    /api/avisos/provedor → provedor configurado (sem o token)
+//   * PUT    /api/avisos/provedor → grava credenciais Twilio/Zenvia da conta
+//   * DELETE /api/avisos/provedor → remove as credenciais
+// - Só recebe quem consentiu no canal (sms ou whatsapp); os demais ficam como
+//   "sem_consentimento". A situação por destinatário sai em GET /api/comunicados/{id}.
+//
+// 🔐 Autenticação/escopo
+// - `X-User-Email` obrigatório; credenciais e avisos são do dono dos dados (tenant).
+// - Apenas admin (ou usuário sem organização) gerencia o provedor: 403 para os demais.
+// - Credenciais só são gravadas cifradas (PII_KEY); sem ela, PUT responde 503.
+// ============================================================================
+
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"backend/internal/apierr"
+	"backend/internal/cripto"
+	"backend/internal/logging"
+	"backend/internal/mensageiro"
+	"backend/internal/model"
+)
+
+// tamanho do resumo do aviso no log de atividades
+const resumoAvisoMaximo = 60
+
+// AvisosHandler trata POST /api/avisos.
+//
+// Regras/erros:
+//   - 401 se não resolver usuário; 405 se não for POST; 400 para JSON inválido.
+//   - 422 (VALIDACAO) para canal/texto/seleção inválidos, campo desconhecido,
+//     nenhum estudante ativo selecionado ou destinatários demais.
+//   - 409 (CONFLITO) se a conta não tiver provedor configurado.
+//   - 201 com o comunicado (canal sms/whatsapp) e a situação de cada destinatário.
+func AvisosHandler(db *sql.DB, repo *model.EstudanteRepo, pii *cripto.Cifrador) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+		acesso, err := acessoFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+		var in model.AvisoRequest
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			writeDecodeError(w, err)
+			return
+		}
+		in.Sanitize()
+		if err := in.Validate(); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		ctx := r.Context()
+		cred, _, err := model.BuscarProvedorMensagem(ctx, db, pii, acesso.TenantID)
+		if errors.Is(err, model.ErrProvedorNaoConfigurado) {
+			writeAPIError(w, http.StatusConflict, apierr.Conflito, "Configure o provedor de SMS/WhatsApp em /api/avisos/provedor")
+			return
+		}
+		if err != nil {
+			logging.De(ctx).Error("avisos: falha ao ler credenciais do provedor", "erro", err)
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao ler provedor de SMS/WhatsApp")
+			return
+		}
+		provedor, err := mensageiro.Novo(cred)
+		if err != nil {
+			logging.De(ctx).Error("avisos: provedor inválido", "erro", err)
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao ler provedor de SMS/WhatsApp")
+			return
+		}
+
+		selecionados, responsaveis, ok := destinatariosSelecionados(w, r, db, repo, acesso, in.SelecaoComunicado)
+		if !ok {
+			return
+		}
+		escola, err := nomeEscola(ctx, db, acesso)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao buscar escola")
+			return
+		}
+
+		c := model.Comunicado{Canal: in.Canal, Corpo: in.Texto, AutorID: acesso.UsuarioID}
+		for _, rsp := range responsaveis {
+			d := model.DestinatarioComunicado{
+				ResponsavelID: rsp.ID,
+				EstudanteID:   rsp.EstudanteID,
+				Nome:          rsp.Nome,
+				Estudante:     selecionados[rsp.EstudanteID].Nome,
+				Telefone:      rsp.Telefone,
+				Status:        model.DestinatarioPendente,
+			}
+			if d.Telefone == "" {
+				d.Status = model.DestinatarioSemTelefone
+			}
+			c.Destinatarios = append(c.Destinatarios, d)
+		}
+		if err := destinatariosConsentidos(ctx, db, acesso.TenantID, &c); err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao verificar consentimentos")
+			return
+		}
+		c, err = model.CriarComunicado(ctx, db, acesso.TenantID, c)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao registrar aviso")
+			return
+		}
+
+		entregarComunicado(ctx, db, &c, selecionados, escola, func(ctx context.Context, d *model.DestinatarioComunicado, m model.MesclagemComunicado) error {
+			return provedor.Enviar(ctx, mensageiro.Mensagem{Canal: c.Canal, Para: d.Telefone, Texto: m.Aplicar(c.Corpo)})
+		})
+		resumo := []rune(c.Corpo)
+		if len(resumo) > resumoAvisoMaximo {
+			resumo = append(resumo[:resumoAvisoMaximo], '…')
+		}
+		registrarAtividade(context.WithoutCancel(ctx), db, acesso, model.AcaoComunicadoEnviado, c.ID, string(resumo),
+			map[string]any{"canal": c.Canal, "destinatarios": c.Total, "enviados": c.Enviados, "falhas": c.Falhas})
+		writeJSON(w, http.StatusCreated, c)
+	}
+}
+
+// ProvedorMensagemHandler despacha GET/PUT/DELETE /api/avisos/provedor.
+//
+// Regras/erros:
+//   - 401 se não resolver usuário; 403 se não for admin; 405 para outros métodos.
+//   - 404 (NAO_ENCONTRADO) no GET/DELETE sem provedor configurado.
+//   - 422 (VALIDACAO) para provedor/conta_sid/token/remetente inválidos.
+//   - 503 (INDISPONIVEL) no PUT sem PII_KEY (credenciais não são gravadas em texto puro).
+//   - 200 no GET/PUT (sem o token, só o final); 204 na remoção.
+func ProvedorMensagemHandler(db *sql.DB, pii *cripto.Cifrador) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		acesso, err := acessoFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+		if !acesso.Admin() {
+			writeAPIError(w, http.StatusForbidden, apierr.SemPermissao, "Apenas administradores configuram o provedor de SMS/WhatsApp")
+			return
+		}
+		uid := acesso.TenantID
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		switch r.Method {
+		case http.MethodGet:
+			_, out, err := model.BuscarProvedorMensagem(ctx, db, pii, uid)
+			if errors.Is(err, model.ErrProvedorNaoConfigurado) {
+				writeAPIError(w, http.StatusNotFound, apierr.NaoEncontrado, "Provedor de SMS/WhatsApp não configurado")
+				return
+			}
+			if err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao ler provedor de SMS/WhatsApp")
+				return
+			}
+			writeJSON(w, http.StatusOK, out)
+
+		case http.MethodPut:
+			var in model.ProvedorMensagemRequest
+			if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+				writeDecodeError(w, err)
+				return
+			}
+			in.Sanitize()
+			if err := in.Validate(); err != nil {
+				writeValidationError(w, err)
+				return
+			}
+			out, err := model.SalvarProvedorMensagem(ctx, db, pii, uid, in.Credenciais())
+			if errors.Is(err, model.ErrCifradorInativo) {
+				writeAPIError(w, http.StatusServiceUnavailable, apierr.Indisponivel, "Credenciais de SMS/WhatsApp exigem PII_KEY configurada")
+				return
+			}
+			if err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao gravar provedor de SMS/WhatsApp")
+				return
+			}
+			writeJSON(w, http.StatusOK, out)
+
+		case http.MethodDelete:
+			removido, err := model.RemoverProvedorMensagem(ctx, db, uid)
+			if err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao remover provedor de SMS/WhatsApp")
+				return
+			}
+			if !removido {
+				writeAPIError(w, http.StatusNotFound, apierr.NaoEncontrado, "Provedor de SMS/WhatsApp não configurado")
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+
+		default:
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+		}
+	}
+}
@@ -0,0 +1,252 @@
+// ============================================================================
+// 📄 handler/carteirinha_handler.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - Carteirinha do estudante com QR code assinado:
+//   * GET /api/estudantes/{id}/carteirinha           → PDF (cartão CR80) com foto, nome, turma e QR
+//   * GET /api/estudantes/{id}/carteirinha?formato=png → só o QR code (PNG)
+//   * GET /api/carteirinhas/verificar?codigo=...     → confere o conteúdo lido do QR
+//
+// 🔐 Autenticação/escopo
+// - `X-User-Email` obrigatório; a verificação só reconhece carteirinhas do
+//   próprio tenant (a de outra escola responde valido=false, motivo outra_conta).
+// - Sem CARTEIRINHA_KEY as rotas respondem 503.
+// ============================================================================
+
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"image"
+	_ "image/png" // fotos PNG (JPEG é registrado pelo pacote pdf)
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"backend/internal/apierr"
+	"backend/internal/carteirinha"
+	"backend/internal/logging"
+	"backend/internal/model"
+	"backend/internal/storage"
+)
+
+// tamanho (px) do PNG avulso do QR code
+const carteirinhaQRPNG = 512
+
+// CarteirinhaHandler gera a carteirinha do estudante.
+//
+// Regras/erros:
+//   - 405 se método != GET; 401 se não resolver usuário; 400 para id ou formato inválidos.
+//   - 404 se o estudante não for do usuário (ou estiver na lixeira); 503 sem CARTEIRINHA_KEY.
+//   - 200 + application/pdf (padrão) ou image/png (formato=png).
+//   - Foto só de /uploads (armazenamento próprio) e com consentimento de uso de foto (LGPD);
+//     sem consentimento, foto externa ou ilegível sai como "sem foto".
+func CarteirinhaHandler(db *sql.DB, repo *model.EstudanteRepo, st storage.Storage, chave []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+		acesso, err := acessoFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+		id, ok := pathID(r, "id")
+		if !ok {
+			writeAPIError(w, http.StatusBadRequest, apierr.IDInvalido, "ID do estudante inválido")
+			return
+		}
+		formato := r.URL.Query().Get("formato")
+		if formato != "" && formato != "pdf" && formato != "png" {
+			writeAPIError(w, http.StatusBadRequest, apierr.Validacao, "Parâmetro formato inválido (pdf ou png)")
+			return
+		}
+		if len(chave) == 0 {
+			writeAPIError(w, http.StatusServiceUnavailable, apierr.Indisponivel, "Carteirinhas desativadas (CARTEIRINHA_KEY não configurada)")
+			return
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		est, err := repo.Buscar(ctx, id, acesso.TenantID)
+		if err == sql.ErrNoRows {
+			writeAPIError(w, http.StatusNotFound, apierr.EstudanteNaoEncontrado, "Estudante não encontrado")
+			return
+		}
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao buscar estudante")
+			return
+		}
+		codigo := model.CodigoCarteirinha(chave, acesso.TenantID, est.ID)
+
+		if formato == "png" {
+			png, err := carteirinha.PNG(codigo, carteirinhaQRPNG)
+			if err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao gerar QR code")
+				return
+			}
+			w.Header().Set("Content-Type", "image/png")
+			w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="carteirinha-%d.png"`, est.ID))
+			_, _ = w.Write(png)
+			return
+		}
+
+		escola, turma, err := dadosCarteirinha(ctx, db, acesso, est.AnoID)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao buscar estudante")
+			return
+		}
+		fotos, err := model.ComConsentimento(ctx, db, acesso.TenantID, model.FinalidadeFoto, []int{est.ID})
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao verificar consentimentos")
+			return
+		}
+		var foto image.Image
+		if fotos[est.ID] {
+			foto = fotoArmazenada(ctx, st, est.FotoURL, false)
+		}
+		pdf, err := carteirinha.PDF(carteirinha.Dados{
+			Escola:    escola,
+			Nome:      est.Nome,
+			Turma:     turma,
+			Matricula: est.ID,
+			EmitidaEm: time.Now(),
+			Foto:      foto,
+			Codigo:    codigo,
+		})
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao gerar carteirinha")
+			return
+		}
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="carteirinha-%d.pdf"`, est.ID))
+		_, _ = w.Write(pdf)
+	}
+}
+
+// VerificarCarteirinhaHandler confere o conteúdo lido do QR code.
+//
+// Regras/erros:
+//   - 405 se método != GET; 401 se não resolver usuário; 503 sem CARTEIRINHA_KEY.
+//   - 200 sempre que o código for lido: valido=false com motivo (codigo_invalido |
+//     outra_conta | nao_encontrado | inativo); os dados do estudante só vão para o próprio tenant.
+func VerificarCarteirinhaHandler(db *sql.DB, repo *model.EstudanteRepo, chave []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+		acesso, err := acessoFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+		if len(chave) == 0 {
+			writeAPIError(w, http.StatusServiceUnavailable, apierr.Indisponivel, "Carteirinhas desativadas (CARTEIRINHA_KEY não configurada)")
+			return
+		}
+
+		tenantID, estudanteID, err := model.LerCodigoCarteirinha(chave, r.URL.Query().Get("codigo"))
+		if err != nil {
+			writeJSON(w, http.StatusOK, model.VerificacaoCarteirinha{Motivo: model.CarteirinhaCodigoInvalido})
+			return
+		}
+		if tenantID != acesso.TenantID {
+			writeJSON(w, http.StatusOK, model.VerificacaoCarteirinha{Motivo: model.CarteirinhaOutraConta})
+			return
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		est, err := repo.Buscar(ctx, estudanteID, tenantID)
+		if err == sql.ErrNoRows {
+			writeJSON(w, http.StatusOK, model.VerificacaoCarteirinha{Motivo: model.CarteirinhaNaoEncontrada})
+			return
+		}
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao buscar estudante")
+			return
+		}
+		_, turma, err := dadosCarteirinha(ctx, db, acesso, est.AnoID)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao buscar estudante")
+			return
+		}
+
+		out := model.VerificacaoCarteirinha{
+			Valido:    est.Status == model.StatusAtivo,
+			Estudante: &model.EstudanteCarteirinha{ID: est.ID, Nome: est.Nome, Turma: turma, Status: est.Status, FotoURL: est.FotoURL},
+		}
+		if !out.Valido {
+			out.Motivo = model.CarteirinhaInativa
+		}
+		writeJSON(w, http.StatusOK, out)
+	}
+}
+
+// ===== helpers =====
+
+// dadosCarteirinha busca o nome da escola (organização ou, sem ela, o dono dos dados) e da turma.
+func dadosCarteirinha(ctx context.Context, db *sql.DB, acesso model.Acesso, anoID int) (escola, turma string, err error) {
+	if escola, err = nomeEscola(ctx, db, acesso); err != nil {
+		return "", "", err
+	}
+	err = db.QueryRowContext(ctx,
+		`SELECT nome FROM anos WHERE id = $1 AND usuario_id = $2 AND excluido_em IS NULL`, anoID, acesso.TenantID,
+	).Scan(&turma)
+	if err == sql.ErrNoRows {
+		return escola, "", nil
+	}
+	return escola, turma, err
+}
+
+// nomeEscola é o nome da organização do usuário ou, sem organização, o nome da conta dona dos dados.
+func nomeEscola(ctx context.Context, db *sql.DB, acesso model.Acesso) (escola string, err error) {
+	if acesso.OrganizacaoID != 0 {
+		err = db.QueryRowContext(ctx, `SELECT nome FROM organizacoes WHERE id = $1`, acesso.OrganizacaoID).Scan(&escola)
+	} else {
+		err = db.QueryRowContext(ctx, `SELECT nome FROM usuarios WHERE id = $1`, acesso.TenantID).Scan(&escola)
+	}
+	return escola, err
+}
+
+// fotoArmazenada lê a foto do armazenamento próprio; nil se externa, ausente ou ilegível.
+// Com miniatura, tenta antes a miniatura quadrada (storage.ChaveMiniatura), bem menor.
+func fotoArmazenada(ctx context.Context, st storage.Storage, fotoURL string, miniatura bool) image.Image {
+	key, ok := strings.CutPrefix(fotoURL, uploadsPublicPath)
+	if !ok || key == "" {
+		return nil
+	}
+	if miniatura {
+		if img := lerImagemArmazenada(ctx, st, storage.ChaveMiniatura(key), false); img != nil {
+			return img
+		}
+	}
+	return lerImagemArmazenada(ctx, st, key, true)
+}
+
+// lerImagemArmazenada decodifica key; falhas só vão para o log quando registrar.
+func lerImagemArmazenada(ctx context.Context, st storage.Storage, key string, registrar bool) image.Image {
+	rc, err := st.Get(ctx, key)
+	if err != nil {
+		if registrar {
+			logging.De(ctx).Warn("foto indisponível", "key", key, "erro", err)
+		}
+		return nil
+	}
+	defer rc.Close()
+	img, _, err := image.Decode(io.LimitReader(rc, maxUploadSize))
+	if err != nil {
+		if registrar {
+			logging.De(ctx).Warn("foto ilegível", "key", key, "erro", err)
+		}
+		return nil
+	}
+	return img
+}
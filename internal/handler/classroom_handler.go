@@ -0,0 +1,348 @@
+// ============================================================================
+// 📄 handler/classroom_handler.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - Importação de alunos do Google Classroom (prévia + confirmação):
+//   * POST /api/integracoes/classroom/cursos    → cursos ativos do professor { access_token }
+//   * POST /api/integracoes/classroom/previa    → alunos do curso × estudantes existentes
+//   * POST /api/integracoes/classroom/importar  → cria os alunos escolhidos na prévia
+//
+// 🔐 Autenticação/escopo
+// - `X-User-Email` obrigatório; os estudantes entram no tenant da conta.
+// - O access token OAuth (consentimento feito no front-end) vai no corpo, é
+//   verificado no tokeninfo (aud = GOOGLE_CLIENT_ID) e nunca é armazenado.
+// ============================================================================
+
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"backend/internal/apierr"
+	"backend/internal/classroom"
+	"backend/internal/jobs"
+	"backend/internal/logging"
+	"backend/internal/model"
+	"backend/internal/notificador"
+)
+
+// prazo das rotas do Classroom (chamadas ao Google + inserções da confirmação)
+const classroomTimeout = 60 * time.Second
+
+// máximo de erros listados no e-mail/notificação de fim de importação
+const classroomMaxErrosResumo = 10
+
+// classroomRequest é o corpo de /cursos e /previa.
+type classroomRequest struct {
+	AccessToken string `json:"access_token"`
+	CursoID     string `json:"curso_id"`
+}
+
+// ClassroomCursosHandler lista os cursos ativos em que o dono do token leciona.
+//
+// Regras/erros:
+//   - 401 se não resolver usuário; 405 se não for POST; 400 para JSON malformado.
+//   - 422 (VALIDACAO) sem access_token; erros do Google conforme escreverErroClassroom.
+func ClassroomCursosHandler(db *sql.DB, cl *classroom.Cliente) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+		if _, err := acessoFromHeader(db, r); err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+		in, ok := lerClassroomRequest(w, r, false)
+		if !ok {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), classroomTimeout)
+		defer cancel()
+
+		if err := cl.VerificarToken(ctx, in.AccessToken); err != nil {
+			escreverErroClassroom(w, r, err)
+			return
+		}
+		cursos, err := cl.Cursos(ctx, in.AccessToken)
+		if err != nil {
+			escreverErroClassroom(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, cursos)
+	}
+}
+
+// ClassroomPreviaHandler compara os alunos do curso com os estudantes ativos do
+// tenant (e-mail igual = duplicado; nome parecido = possível duplicado). Nada é gravado.
+//
+// Regras/erros:
+//   - 401 se não resolver usuário; 405 se não for POST; 400 para JSON malformado.
+//   - 422 (VALIDACAO) sem access_token/curso_id; erros do Google conforme escreverErroClassroom.
+func ClassroomPreviaHandler(db *sql.DB, repo *model.EstudanteRepo, cl *classroom.Cliente) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+		acesso, err := acessoFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+		in, ok := lerClassroomRequest(w, r, true)
+		if !ok {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), classroomTimeout)
+		defer cancel()
+
+		if err := cl.VerificarToken(ctx, in.AccessToken); err != nil {
+			escreverErroClassroom(w, r, err)
+			return
+		}
+		alunos, err := cl.Alunos(ctx, in.AccessToken, in.CursoID)
+		if err != nil {
+			escreverErroClassroom(w, r, err)
+			return
+		}
+		existentes, err := repo.Listar(ctx, acesso.TenantID, nil)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao listar estudantes")
+			return
+		}
+
+		out := model.PreviaClassroom{CursoID: in.CursoID, Total: len(alunos), Itens: make([]model.ItemPreviaClassroom, 0, len(alunos))}
+		for _, a := range alunos {
+			it := model.CompararAlunoClassroom(a.Nome, a.Email, existentes)
+			it.GoogleID = a.GoogleID
+			switch it.Situacao {
+			case model.ClassroomDuplicado:
+				out.Duplicados++
+			case model.ClassroomPossivelDuplicado:
+				out.PossiveisDuplicado++
+			default:
+				out.Novos++
+			}
+			out.Itens = append(out.Itens, it)
+		}
+		writeJSON(w, http.StatusOK, out)
+	}
+}
+
+// ClassroomImportarHandler cria os alunos escolhidos na prévia. Nome e e-mail são
+// lidos de novo do Classroom (pelo google_id); CPF, data de nascimento e telefone
+// vêm do corpo. Cada aluno é independente: um inválido/duplicado não impede os demais.
+//
+// Regras/erros:
+//   - 401 se não resolver usuário; 405 se não for POST; 400 para JSON malformado.
+//   - 422 (VALIDACAO) para o envelope inválido (token, curso, lista de estudantes).
+//   - 200 com o resultado por aluno (criado | duplicado | invalido | fora_do_curso).
+//   - Ao final: notificação in-app e e-mail (em segundo plano) com o resumo.
+func ClassroomImportarHandler(db *sql.DB, repo *model.EstudanteRepo, cl *classroom.Cliente, wh *jobs.Webhooks, nt *notificador.Notificador) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+		acesso, err := acessoFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+		uid := acesso.TenantID
+
+		var in model.ImportarClassroomRequest
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			writeDecodeError(w, err)
+			return
+		}
+		in.Sanitize()
+		if err := in.Validate(); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), classroomTimeout)
+		defer cancel()
+
+		if err := cl.VerificarToken(ctx, in.AccessToken); err != nil {
+			escreverErroClassroom(w, r, err)
+			return
+		}
+		alunos, err := cl.Alunos(ctx, in.AccessToken, in.CursoID)
+		if err != nil {
+			escreverErroClassroom(w, r, err)
+			return
+		}
+		porID := make(map[string]classroom.Aluno, len(alunos))
+		for _, a := range alunos {
+			porID[a.GoogleID] = a
+		}
+		existentes, err := repo.ListarAtual(ctx, uid, nil)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao listar estudantes")
+			return
+		}
+
+		out := model.ResultadoImportacaoClassroom{Itens: make([]model.ResultadoItemClassroom, 0, len(in.Estudantes))}
+		var erros []string
+		for _, item := range in.Estudantes {
+			res := importarAlunoClassroom(ctx, repo, uid, in, item, porID, existentes)
+			if res.Situacao == model.ClassroomCriado {
+				out.Importados++
+				existentes = append(existentes, res.criado) // e-mail repetido na própria lista vira duplicado
+				publicarEvento(ctx, wh, uid, model.EventoEstudanteCriado, eventoEstudante(res.criado))
+				registrarAtividade(ctx, db, acesso, model.AcaoEstudanteCriado, res.EstudanteID, res.Nome,
+					map[string]any{"origem": "google_classroom", "curso_id": in.CursoID})
+			} else {
+				out.Ignorados++
+				erros = append(erros, resumoErroClassroom(res.ResultadoItemClassroom))
+			}
+			out.Itens = append(out.Itens, res.ResultadoItemClassroom)
+		}
+
+		notificarImportacaoClassroom(ctx, db, nt, acesso.UsuarioID, in.CursoID, out, erros)
+		writeJSON(w, http.StatusOK, out)
+	}
+}
+
+// ===== helpers =====
+
+// resultadoAlunoClassroom acompanha o estudante criado (para o evento de webhook).
+type resultadoAlunoClassroom struct {
+	model.ResultadoItemClassroom
+	criado model.Estudante
+}
+
+// lerClassroomRequest decodifica e valida o corpo de /cursos e /previa; false se já respondeu.
+func lerClassroomRequest(w http.ResponseWriter, r *http.Request, exigirCurso bool) (classroomRequest, bool) {
+	var in classroomRequest
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeDecodeError(w, err)
+		return in, false
+	}
+	in.AccessToken = strings.TrimSpace(in.AccessToken)
+	in.CursoID = strings.TrimSpace(in.CursoID)
+
+	var ev model.ErrosValidacao
+	if in.AccessToken == "" {
+		ev.Add("access_token", model.RegraObrigatorio, model.ErrClassroomToken)
+	}
+	if exigirCurso && in.CursoID == "" {
+		ev.Add("curso_id", model.RegraObrigatorio, model.ErrClassroomCurso)
+	}
+	if err := ev.Err(); err != nil {
+		writeValidationError(w, err)
+		return in, false
+	}
+	return in, true
+}
+
+// importarAlunoClassroom valida e cria um aluno escolhido na prévia.
+func importarAlunoClassroom(ctx context.Context, repo *model.EstudanteRepo, uid int, in model.ImportarClassroomRequest, item model.ImportarClassroomItem, porID map[string]classroom.Aluno, existentes []model.Estudante) resultadoAlunoClassroom {
+	res := resultadoAlunoClassroom{ResultadoItemClassroom: model.ResultadoItemClassroom{GoogleID: item.GoogleID}}
+	aluno, ok := porID[item.GoogleID]
+	if !ok {
+		res.Situacao, res.Mensagem = model.ClassroomForaDoCurso, "Aluno não está (mais) matriculado no curso."
+		return res
+	}
+	res.Nome = aluno.Nome
+
+	if cmp := model.CompararAlunoClassroom(aluno.Nome, aluno.Email, existentes); cmp.Situacao == model.ClassroomDuplicado {
+		res.Situacao, res.EstudanteID, res.Mensagem = model.ClassroomDuplicado, cmp.EstudanteID, "E-mail já cadastrado para este usuário."
+		return res
+	}
+
+	req := model.EstudanteCreateRequest{
+		Nome:           aluno.Nome,
+		CPF:            item.CPF,
+		Email:          aluno.Email,
+		DataNascimento: item.DataNascimento,
+		Telefone:       item.Telefone,
+		AnoID:          in.AnoID,
+		TurmaID:        in.TurmaID,
+	}
+	req.Sanitize()
+	if err := req.Validate(); err != nil {
+		res.Situacao, res.Mensagem = model.ClassroomInvalido, "Dados do aluno inválidos."
+		res.Erros, _ = model.ComoErrosValidacao(err)
+		return res
+	}
+
+	est, err := repo.Criar(ctx, uid, req)
+	if _, _, msg, ok := mapPQError(err); ok {
+		res.Situacao, res.Mensagem = model.ClassroomDuplicado, msg
+		return res
+	}
+	if err != nil {
+		logging.De(ctx).Error("classroom: falha ao criar estudante", "google_id", item.GoogleID, "erro", err)
+		res.Situacao, res.Mensagem = model.ClassroomInvalido, "Erro ao criar estudante."
+		return res
+	}
+	res.Situacao, res.EstudanteID, res.criado = model.ClassroomCriado, est.ID, est
+	return res
+}
+
+// resumoErroClassroom formata a linha do aluno ignorado no resumo da importação.
+func resumoErroClassroom(it model.ResultadoItemClassroom) string {
+	nome := it.Nome
+	if nome == "" {
+		nome = it.GoogleID
+	}
+	return fmt.Sprintf("%s: %s", nome, it.Mensagem)
+}
+
+// notificarImportacaoClassroom cria a notificação in-app e envia o e-mail de fim de importação.
+func notificarImportacaoClassroom(ctx context.Context, db *sql.DB, nt *notificador.Notificador, usuarioID int, cursoID string, out model.ResultadoImportacaoClassroom, erros []string) {
+	if len(erros) > classroomMaxErrosResumo {
+		erros = erros[:classroomMaxErrosResumo]
+	}
+	if _, err := model.CriarNotificacao(ctx, db, usuarioID, model.NovaNotificacao{
+		Tipo:     model.NotificacaoImportacaoConcluida,
+		Titulo:   "Importação do Google Classroom concluída",
+		Mensagem: fmt.Sprintf("%d estudante(s) importado(s), %d ignorado(s).", out.Importados, out.Ignorados),
+		Dados:    map[string]any{"origem": "google_classroom", "curso_id": cursoID, "importados": out.Importados, "ignorados": out.Ignorados},
+	}); err != nil {
+		logging.De(ctx).Error("classroom: falha ao criar notificação", "usuario_id", usuarioID, "erro", err)
+	}
+
+	var nome, email string
+	if err := db.QueryRowContext(ctx, `SELECT nome, email FROM usuarios WHERE id = $1`, usuarioID).Scan(&nome, &email); err != nil {
+		logging.De(ctx).Error("classroom: falha ao buscar destinatário", "usuario_id", usuarioID, "erro", err)
+		return
+	}
+	nt.EnviarEmSegundoPlano(ctx, email, notificador.ImportacaoConcluida, notificador.DadosImportacao{
+		Nome:       nome,
+		Origem:     "Google Classroom",
+		Importados: out.Importados,
+		Ignorados:  out.Ignorados,
+		Erros:      erros,
+	})
+}
+
+// escreverErroClassroom traduz os erros do cliente do Classroom para o envelope da API.
+func escreverErroClassroom(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, classroom.ErrClientIDAusente):
+		writeAPIError(w, http.StatusInternalServerError, apierr.GoogleNaoConfigurado, "Servidor sem GOOGLE_CLIENT_ID configurado")
+	case errors.Is(err, classroom.ErrTokenInvalido):
+		writeAPIError(w, http.StatusUnauthorized, apierr.GoogleTokenInvalido, err.Error())
+	case errors.Is(err, classroom.ErrEscopoInsuficiente):
+		writeAPIError(w, http.StatusForbidden, apierr.GoogleEscopoInsuficiente, err.Error())
+	case errors.Is(err, classroom.ErrCursoNaoEncontrado):
+		writeAPIError(w, http.StatusNotFound, apierr.ClassroomCursoNaoEncontrado, err.Error())
+	default:
+		logging.De(r.Context()).Error("classroom: falha ao consultar o Google", "erro", err)
+		writeAPIError(w, http.StatusBadGateway, apierr.FalhaServicoExterno, "Falha ao consultar o Google Classroom")
+	}
+}
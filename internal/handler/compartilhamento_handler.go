@@ -0,0 +1,219 @@
+// ============================================================================
+// 📄 handler/compartilhamento_handler.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - Links públicos (somente leitura) da lista de estudantes de um ano/turma:
+//   * POST /api/anos/{id}/share             → cria o link assinado { url, expira_em }
+//   * GET  /compartilhado/anos/{id}?exp&sig → lista pública (HTML no navegador, JSON com formato=json)
+//
+// 🔐 Autenticação/escopo
+// - Criar o link exige `X-User-Email` com escrita (papel leitor não compartilha).
+// - A rota pública não tem autenticação: vale enquanto exp/sig conferirem
+//   (COMPARTILHAR_KEY). Só nomes de estudantes ativos são expostos.
+// ============================================================================
+
+package handler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"backend/internal/apierr"
+	"backend/internal/model"
+)
+
+// página HTML da lista pública
+var turmaCompartilhadaHTML = template.Must(template.New("turma").Parse(`<!doctype html>
+<html lang="pt-BR">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>{{.Ano}} — lista de estudantes</title>
+<style>
+body{font-family:system-ui,sans-serif;max-width:640px;margin:2rem auto;padding:0 1rem;color:#222}
+table{border-collapse:collapse;width:100%}td,th{border-bottom:1px solid #ddd;padding:.4rem;text-align:left}
+small{color:#666}
+</style>
+</head>
+<body>
+<h1>{{.Ano}}</h1>
+<p>{{.Total}} estudante(s) ativo(s).</p>
+<table>
+<thead><tr><th>Nº</th><th>Nome</th></tr></thead>
+<tbody>{{range .Estudantes}}<tr><td>{{.Numero}}</td><td>{{.Nome}}</td></tr>{{end}}</tbody>
+</table>
+<p><small>Link somente leitura, válido até {{.ExpiraEm.Format "02/01/2006 15:04"}}.</small></p>
+</body>
+</html>
+`))
+
+// CSP da página pública: só o <style> embutido, sem scripts nem recursos externos.
+const cspTurmaCompartilhada = "default-src 'none'; style-src 'unsafe-inline'; frame-ancestors 'none'; base-uri 'none'; form-action 'none'"
+
+// CompartilharAnoHandler cria o link público da lista de estudantes do ano.
+//
+// Regras/erros:
+//   - 405 se método != POST; 401 se não resolver usuário; 400 para id/JSON inválidos.
+//   - 422 (VALIDACAO) se expira_em_horas estiver fora de 1..720 (padrão 72).
+//   - 404 se o ano não for do usuário (ou estiver na lixeira); 503 sem COMPARTILHAR_KEY.
+//   - 201 + { url, expira_em }.
+func CompartilharAnoHandler(db *sql.DB, chave []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+		acesso, err := acessoFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+		id, ok := pathID(r, "id")
+		if !ok {
+			writeAPIError(w, http.StatusBadRequest, apierr.IDInvalido, "ID do ano/turma inválido")
+			return
+		}
+
+		var in model.CompartilharAnoRequest
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil && err != io.EOF {
+			writeDecodeError(w, err)
+			return
+		}
+		in.Sanitize()
+		if err := in.Validate(); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+		if len(chave) == 0 {
+			writeAPIError(w, http.StatusServiceUnavailable, apierr.Indisponivel, "Compartilhamento desativado (COMPARTILHAR_KEY não configurada)")
+			return
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		var nome string
+		err = db.QueryRowContext(ctx,
+			`SELECT nome FROM anos WHERE id = $1 AND usuario_id = $2 AND excluido_em IS NULL`, id, acesso.TenantID,
+		).Scan(&nome)
+		if err == sql.ErrNoRows {
+			writeAPIError(w, http.StatusNotFound, apierr.AnoNaoEncontrado, "Ano/Turma não encontrado")
+			return
+		}
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao compartilhar ano/turma")
+			return
+		}
+
+		expira := time.Now().Add(time.Duration(in.ExpiraEmHoras) * time.Hour).Truncate(time.Second)
+		q := url.Values{
+			"exp": {fmt.Sprint(expira.Unix())},
+			"sig": {model.AssinarCompartilhamento(chave, id, acesso.TenantID, expira.Unix())},
+		}
+		out := model.LinkCompartilhamento{
+			URL:      fmt.Sprintf("%s/compartilhado/anos/%d?%s", urlBase(r), id, q.Encode()),
+			ExpiraEm: expira,
+		}
+		registrarAtividade(ctx, db, acesso, model.AcaoAnoCompartilhado, id, nome,
+			map[string]any{"expira_em": expira})
+
+		writeJSON(w, http.StatusCreated, out)
+	}
+}
+
+// TurmaCompartilhadaHandler responde a lista pública do ano se exp/sig conferirem.
+//
+// Regras/erros:
+//   - 405 se método != GET; 400 para id inválido; 503 sem COMPARTILHAR_KEY.
+//   - 403 (LINK_INVALIDO) para assinatura inválida, link expirado ou ano excluído.
+//   - 200 + HTML (navegador) ou JSON (formato=json, ou Accept sem text/html).
+func TurmaCompartilhadaHandler(db *sql.DB, chave []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+		id, ok := pathID(r, "id")
+		if !ok {
+			writeAPIError(w, http.StatusBadRequest, apierr.IDInvalido, "ID do ano/turma inválido")
+			return
+		}
+		if len(chave) == 0 {
+			writeAPIError(w, http.StatusServiceUnavailable, apierr.Indisponivel, "Compartilhamento desativado")
+			return
+		}
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("X-Robots-Tag", "noindex")
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		var out model.TurmaCompartilhada
+		var dono int
+		err := db.QueryRowContext(ctx,
+			`SELECT nome, usuario_id FROM anos WHERE id = $1 AND excluido_em IS NULL`, id,
+		).Scan(&out.Ano, &dono)
+		if err != nil && err != sql.ErrNoRows {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao carregar lista")
+			return
+		}
+		q := r.URL.Query()
+		expira, valido := model.ConferirCompartilhamento(chave, id, dono, q.Get("exp"), q.Get("sig"), time.Now())
+		// ano inexistente/excluído responde igual a link inválido (não revela quais ids existem)
+		if err == sql.ErrNoRows || !valido {
+			writeAPIError(w, http.StatusForbidden, apierr.LinkInvalido, "Link inválido ou expirado")
+			return
+		}
+		out.ExpiraEm = expira
+
+		rows, err := db.QueryContext(ctx, `
+			SELECT nome FROM estudantes
+			 WHERE ano_id = $1 AND usuario_id = $2 AND excluido_em IS NULL AND status = $3
+			 ORDER BY LOWER(nome), id`, id, dono, model.StatusAtivo)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao carregar lista")
+			return
+		}
+		defer rows.Close()
+		out.Estudantes = []model.EstudanteCompartilhado{}
+		for rows.Next() {
+			e := model.EstudanteCompartilhado{Numero: len(out.Estudantes) + 1}
+			if err := rows.Scan(&e.Nome); err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao carregar lista")
+				return
+			}
+			out.Estudantes = append(out.Estudantes, e)
+		}
+		if err := rows.Err(); err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao carregar lista")
+			return
+		}
+		out.Total = len(out.Estudantes)
+
+		formato := q.Get("formato")
+		if formato == "json" || (formato == "" && !strings.Contains(r.Header.Get("Accept"), "text/html")) {
+			writeJSON(w, http.StatusOK, out)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Content-Security-Policy", cspTurmaCompartilhada)
+		_ = turmaCompartilhadaHTML.Execute(w, out)
+	}
+}
+
+// urlBase devolve esquema://host da requisição (respeita X-Forwarded-Proto atrás de proxy).
+func urlBase(r *http.Request) string {
+	esquema := "http"
+	if r.TLS != nil || strings.EqualFold(r.Header.Get("X-Forwarded-Proto"), "https") {
+		esquema = "https"
+	}
+	return esquema + "://" + r.Host
+}
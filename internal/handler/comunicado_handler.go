@@ -0,0 +1,256 @@
+// ============================================================================
+// 📄 handler/comunicado_handler.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - Comunicados por e-mail aos responsáveis (mala direta):
+//   * POST /api/comunicados      → envia assunto/corpo com campos ({{nome}},
+//     {{estudante}}, {{ano}}, {{turma}}, {{escola}}) aos responsáveis dos
+//     estudantes/turmas selecionados, pelo notificador
+//   * GET  /api/comunicados      → últimos comunicados (e-mail e avisos por
+//     SMS/WhatsApp, ver aviso_handler.go) com as contagens (?limite=)
+//   * GET  /api/comunicados/{id} → situação da entrega por destinatário
+// - Só estudantes ativos entram; responsável sem e-mail fica como "sem_email" e,
+//   sem consentimento de e-mail (consentimento_handler.go), como "sem_consentimento".
+//
+// 🔐 Autenticação/escopo
+// - `X-User-Email` obrigatório; estudantes, responsáveis e comunicados do tenant.
+// - Papel "leitor" é bloqueado pela rota (ExigirEscritaMiddleware); o POST aceita
+//   Idempotency-Key (retentativa não manda os e-mails de novo).
+//
+// 🧱 Limites
+// - Envio síncrono, um e-mail por destinatário, até maxDestinatariosComunicado;
+//   falha de um não interrompe os demais (fica "falhou" com o erro).
+// ============================================================================
+
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"backend/internal/apierr"
+	"backend/internal/logging"
+	"backend/internal/model"
+	"backend/internal/notificador"
+)
+
+// limites dos comunicados
+const (
+	maxDestinatariosComunicado = 1000
+	comunicadosLimitePadrao    = 20
+	comunicadosLimiteMaximo    = 100
+)
+
+// ComunicadosHandler despacha GET/POST /api/comunicados.
+//
+// Regras/erros:
+//   - 401 se não resolver usuário; 405 para outros métodos.
+//   - 400 para JSON inválido ou ?limite fora de 1..100.
+//   - 422 (VALIDACAO) para assunto/corpo/seleção inválidos, campo desconhecido,
+//     nenhum estudante ativo selecionado ou mais de maxDestinatariosComunicado destinatários.
+//   - 201 com o comunicado e a situação de cada destinatário; 200 na listagem.
+func ComunicadosHandler(db *sql.DB, repo *model.EstudanteRepo, nt *notificador.Notificador) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		acesso, err := acessoFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			limite := comunicadosLimitePadrao
+			if v := r.URL.Query().Get("limite"); v != "" {
+				limite, err = strconv.Atoi(v)
+				if err != nil || limite < 1 || limite > comunicadosLimiteMaximo {
+					writeJSONError(w, http.StatusBadRequest, "limite inválido (1 a 100)")
+					return
+				}
+			}
+			ctx, cancel := contextoBanco(r)
+			defer cancel()
+			out, err := model.ListarComunicados(ctx, db, acesso.TenantID, limite)
+			if err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao listar comunicados")
+				return
+			}
+			writeJSON(w, http.StatusOK, out)
+
+		case http.MethodPost:
+			var in model.ComunicadoRequest
+			if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+				writeDecodeError(w, err)
+				return
+			}
+			in.Sanitize()
+			if err := in.Validate(); err != nil {
+				writeValidationError(w, err)
+				return
+			}
+			enviarComunicado(w, r, db, repo, nt, acesso, in)
+
+		default:
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+		}
+	}
+}
+
+// ComunicadoHandler trata GET /api/comunicados/{id}.
+//
+// Regras/erros:
+//   - 401 se não resolver usuário; 400 se id inválido.
+//   - 404 se o comunicado não for do tenant.
+//   - 200 com os destinatários (pendente | enviado | falhou | sem_email | sem_telefone | sem_consentimento).
+func ComunicadoHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+		acesso, err := acessoFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+		id, ok := pathID(r, "id")
+		if !ok {
+			writeAPIError(w, http.StatusBadRequest, apierr.IDInvalido, "ID do comunicado inválido")
+			return
+		}
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+		c, err := model.BuscarComunicado(ctx, db, acesso.TenantID, id)
+		if errors.Is(err, sql.ErrNoRows) {
+			writeAPIError(w, http.StatusNotFound, apierr.ComunicadoNaoEncontrado, "Comunicado não encontrado")
+			return
+		}
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao buscar comunicado")
+			return
+		}
+		writeJSON(w, http.StatusOK, c)
+	}
+}
+
+// ===== helpers =====
+
+// enviarComunicado monta os destinatários, grava o comunicado e envia um e-mail por destinatário.
+func enviarComunicado(w http.ResponseWriter, r *http.Request, db *sql.DB, repo *model.EstudanteRepo,
+	nt *notificador.Notificador, acesso model.Acesso, in model.ComunicadoRequest) {
+	ctx := r.Context()
+	selecionados, responsaveis, ok := destinatariosSelecionados(w, r, db, repo, acesso, in.SelecaoComunicado)
+	if !ok {
+		return
+	}
+	escola, err := nomeEscola(ctx, db, acesso)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao buscar escola")
+		return
+	}
+
+	c := model.Comunicado{Canal: model.CanalEmail, Assunto: in.Assunto, Corpo: in.Corpo, AutorID: acesso.UsuarioID}
+	for _, rsp := range responsaveis {
+		d := model.DestinatarioComunicado{
+			ResponsavelID: rsp.ID,
+			EstudanteID:   rsp.EstudanteID,
+			Nome:          rsp.Nome,
+			Estudante:     selecionados[rsp.EstudanteID].Nome,
+			Email:         rsp.Email,
+			Status:        model.DestinatarioPendente,
+		}
+		if d.Email == "" {
+			d.Status = model.DestinatarioSemEmail
+		}
+		c.Destinatarios = append(c.Destinatarios, d)
+	}
+	if err := destinatariosConsentidos(ctx, db, acesso.TenantID, &c); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao verificar consentimentos")
+		return
+	}
+	c, err = model.CriarComunicado(ctx, db, acesso.TenantID, c)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao registrar comunicado")
+		return
+	}
+
+	entregarComunicado(ctx, db, &c, selecionados, escola, func(ctx context.Context, d *model.DestinatarioComunicado, m model.MesclagemComunicado) error {
+		return nt.Enviar(ctx, d.Email, notificador.Comunicado, notificador.DadosComunicado{
+			Escola:  escola,
+			Assunto: m.Aplicar(c.Assunto),
+			Texto:   m.Aplicar(c.Corpo),
+		})
+	})
+	registrarAtividade(context.WithoutCancel(ctx), db, acesso, model.AcaoComunicadoEnviado, c.ID, c.Assunto,
+		map[string]any{"canal": c.Canal, "destinatarios": c.Total, "enviados": c.Enviados, "falhas": c.Falhas})
+	writeJSON(w, http.StatusCreated, c)
+}
+
+// entregarComunicado chama enviar para cada destinatário pendente, com os campos já
+// calculados, grava o desfecho e recalcula as contagens de c.
+func entregarComunicado(ctx context.Context, db *sql.DB, c *model.Comunicado, selecionados map[int]model.Estudante, escola string,
+	enviar func(ctx context.Context, d *model.DestinatarioComunicado, m model.MesclagemComunicado) error) {
+	// o desfecho de um envio já feito é gravado mesmo se o cliente desconectar
+	gravar := context.WithoutCancel(ctx)
+	for i := range c.Destinatarios {
+		d := &c.Destinatarios[i]
+		if d.Status != model.DestinatarioPendente {
+			continue
+		}
+		if ctx.Err() != nil {
+			break // prazo esgotado: os restantes ficam pendentes
+		}
+		e := selecionados[d.EstudanteID]
+		m := model.MesclagemComunicado{Nome: d.Nome, Estudante: d.Estudante, Ano: e.AnoNome, Turma: e.TurmaNome, Escola: escola}
+		envio := enviar(ctx, d, m)
+		if envio != nil {
+			logging.De(ctx).Error("comunicado: falha no envio", "comunicado_id", c.ID, "canal", c.Canal, "destinatario_id", d.ID, "erro", envio)
+		}
+		if err := model.MarcarDestinatario(gravar, db, d, envio); err != nil {
+			logging.De(ctx).Error("comunicado: falha ao gravar situação", "destinatario_id", d.ID, "erro", err)
+		}
+	}
+	c.Contar()
+}
+
+// destinatariosSelecionados lista os estudantes ativos da seleção (por id) e os responsáveis deles.
+// Sem estudante, sem responsável ou acima de maxDestinatariosComunicado responde 422 e devolve ok=false.
+func destinatariosSelecionados(w http.ResponseWriter, r *http.Request, db *sql.DB, repo *model.EstudanteRepo,
+	acesso model.Acesso, sel model.SelecaoComunicado) (map[int]model.Estudante, []model.Responsavel, bool) {
+	ctx := r.Context()
+	estudantes, err := repo.ListarAtual(ctx, acesso.TenantID, []string{model.StatusAtivo})
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao listar estudantes")
+		return nil, nil, false
+	}
+	selecionados := make(map[int]model.Estudante)
+	ids := []int{}
+	for _, e := range estudantes {
+		if sel.Seleciona(e) {
+			selecionados[e.ID] = e
+			ids = append(ids, e.ID)
+		}
+	}
+	if len(ids) == 0 {
+		writeAPIError(w, http.StatusUnprocessableEntity, apierr.Validacao, "Nenhum estudante ativo selecionado")
+		return nil, nil, false
+	}
+	responsaveis, err := repo.ListarResponsaveis(ctx, acesso.TenantID, ids...)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao listar responsáveis")
+		return nil, nil, false
+	}
+	if len(responsaveis) == 0 {
+		writeAPIError(w, http.StatusUnprocessableEntity, apierr.Validacao, "Os estudantes selecionados não têm responsáveis cadastrados")
+		return nil, nil, false
+	}
+	if len(responsaveis) > maxDestinatariosComunicado {
+		writeAPIError(w, http.StatusUnprocessableEntity, apierr.Validacao,
+			"No máximo "+strconv.Itoa(maxDestinatariosComunicado)+" destinatários por comunicado")
+		return nil, nil, false
+	}
+	return selecionados, responsaveis, true
+}
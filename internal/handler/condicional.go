@@ -0,0 +1,65 @@
+// ============================================================================
+// 📄 handler/condicional.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - GET condicional para listagens (ETag / Last-Modified → 304 Not Modified),
+//   usado por GET /api/estudantes e GET /api/anos nos polls do dashboard.
+//
+// 🔐 Autenticação/escopo
+// - O ETag é calculado depois da autenticação, sobre os dados do próprio
+//   usuário; respostas saem com Cache-Control: private e Vary: X-User-Email.
+// - If-None-Match tem precedência sobre If-Modified-Since (RFC 9110). Exclusões
+//   físicas não mudam a data da última alteração, só a contagem no ETag:
+//   clientes que mandam apenas If-Modified-Since podem ver a lista antiga.
+// ============================================================================
+
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// responderCondicional define ETag/Last-Modified a partir de ultima e partes (contagens,
+// filtros...) e responde 304 quando o cliente já tem essa versão. true = resposta enviada.
+func responderCondicional(w http.ResponseWriter, r *http.Request, ultima time.Time, partes ...any) bool {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s?%s|%d", r.URL.Path, r.URL.RawQuery, ultima.UnixNano())
+	for _, p := range partes {
+		fmt.Fprintf(h, "|%v", p)
+	}
+	etag := `W/"` + hex.EncodeToString(h.Sum(nil))[:20] + `"`
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "private, no-cache")
+	w.Header().Add("Vary", "X-User-Email")
+	if !ultima.IsZero() && ultima.Unix() > 0 {
+		w.Header().Set("Last-Modified", ultima.UTC().Format(http.TimeFormat))
+	}
+
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		if !etagCombina(inm, etag) {
+			return false
+		}
+	} else if ims, err := http.ParseTime(r.Header.Get("If-Modified-Since")); err != nil || ultima.Truncate(time.Second).After(ims) {
+		return false
+	}
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// etagCombina compara If-None-Match (lista ou "*") com o ETag atual (comparação fraca).
+func etagCombina(cabecalho, etag string) bool {
+	alvo := strings.TrimPrefix(etag, "W/")
+	for _, v := range strings.Split(cabecalho, ",") {
+		v = strings.TrimSpace(v)
+		if v == "*" || strings.TrimPrefix(v, "W/") == alvo {
+			return true
+		}
+	}
+	return false
+}
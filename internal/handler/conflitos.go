@@ -0,0 +1,96 @@
+// ============================================================================
+// 📄 handler/conflitos.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - Registro central das restrições únicas do banco → código/mensagem da API
+//   (409). mapPQError consulta o registro; restrição fora dele cai no genérico
+//   REGISTRO_DUPLICADO com os campos lidos do DETAIL do Postgres.
+// - Ao criar um índice/constraint UNIQUE numa migration, acrescente-o aqui.
+//
+// 🔐 Autenticação/escopo
+// - Sem regras próprias. A mensagem nunca repete os valores do DETAIL (podem
+//   ser CPF/e-mail), só os nomes das colunas.
+// ============================================================================
+
+package handler
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+
+	"backend/internal/apierr"
+	"backend/internal/model"
+)
+
+// conflitoRestricao é a resposta da API para a violação de uma restrição única.
+type conflitoRestricao struct {
+	code string
+	msg  string
+}
+
+// conflitosRestricao mapeia o nome da restrição (migrations/*.sql) para a resposta.
+var conflitosRestricao = map[string]conflitoRestricao{
+	// estudantes
+	"estudantes_cpf_hash_usuario_unique": {apierr.EstudanteCPFDuplicado, "CPF já cadastrado para este usuário."},
+	"estudantes_email_usuario_unique":    {apierr.EstudanteEmailDuplicado, "E-mail já cadastrado para este usuário."},
+	// anos, períodos e filtros
+	"anos_nome_usuario_unique":             {apierr.AnoNomeDuplicado, "Já existe um ano/turma com este nome."},
+	"periodos_letivos_nome_usuario_unique": {apierr.PeriodoNomeDuplicado, "Já existe um período letivo com este nome."},
+	"filtros_salvos_nome_usuario_unique":   {apierr.FiltroNomeDuplicado, "Já existe um filtro salvo com este nome."},
+	// usuários e organizações
+	"usuarios_email_unique":              {apierr.EmailJaCadastrado, "E-mail já cadastrado."},
+	"usuarios_google_sub_key":            {apierr.RegistroDuplicado, "Esta conta Google já está vinculada a outro usuário."},
+	"usuarios_google_sub_unique":         {apierr.RegistroDuplicado, "Esta conta Google já está vinculada a outro usuário."},
+	"organizacao_membros_usuario_unique": {apierr.UsuarioJaPertenceOrg, model.ErrJaPertenceOrganizacao.Error()},
+	// frequência e notas
+	"presencas_estudante_data_unique":  {apierr.RegistroDuplicado, "Já existe registro de presença deste estudante nesta data."},
+	"notas_avaliacao_estudante_unique": {apierr.RegistroDuplicado, "Já existe nota deste estudante nesta avaliação."},
+}
+
+// identificadores de coluna dentro da chave do DETAIL (ignora funções e casts)
+var reColunaDetalhe = regexp.MustCompile(`[a-z_][a-z0-9_]*`)
+
+// mapPQError converte erros do Postgres (model.ComoErroBanco) para status, código e
+// mensagem amigável. Hoje trata violação de unicidade (409): restrição registrada em
+// conflitosRestricao usa a resposta dela; demais, REGISTRO_DUPLICADO com as colunas.
+func mapPQError(err error) (status int, code, message string, handled bool) {
+	if err == nil {
+		return 0, "", "", false
+	}
+	e, ok := model.ComoErroBanco(err)
+	if !ok || e.Codigo != model.SQLStateUnicidade {
+		return 0, "", "", false
+	}
+	if c, ok := conflitosRestricao[e.Restricao]; ok {
+		return http.StatusConflict, c.code, c.msg, true
+	}
+	if cols := colunasDetalhe(e.Detalhe); len(cols) > 0 {
+		return http.StatusConflict, apierr.RegistroDuplicado, "Registro já existente (mesmo valor em: " + strings.Join(cols, ", ") + ").", true
+	}
+	return http.StatusConflict, apierr.RegistroDuplicado, "Registro já existente (violação de unicidade).", true
+}
+
+// colunasDetalhe extrai as colunas da chave de um DETAIL de unicidade, em inglês ou
+// português ("Key (usuario_id, lower(nome::text))=(...) already exists." → ["nome"]).
+// usuario_id (escopo do tenant) fica de fora.
+func colunasDetalhe(detalhe string) []string {
+	ini := strings.IndexByte(detalhe, '(')
+	fim := strings.Index(detalhe, ")=(")
+	if ini < 0 || fim <= ini {
+		return nil
+	}
+	chave := detalhe[ini+1 : fim]
+	var cols []string
+	for _, loc := range reColunaDetalhe.FindAllStringIndex(chave, -1) {
+		nome := chave[loc[0]:loc[1]]
+		switch {
+		case loc[1] < len(chave) && chave[loc[1]] == '(': // função: lower(...)
+		case loc[0] >= 2 && chave[loc[0]-2:loc[0]] == "::": // cast: ::text
+		case nome == "usuario_id":
+		default:
+			cols = append(cols, nome)
+		}
+	}
+	return cols
+}
@@ -0,0 +1,169 @@
+// ============================================================================
+// 📄 handler/consentimento_handler.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - Consentimentos (LGPD) aninhados no estudante (tabela: consentimentos):
+//   * GET    /api/estudantes/{id}/consentimentos       → histórico (ativos e revogados)
+//   * POST   /api/estudantes/{id}/consentimentos       → registra (email, sms,
+//     whatsapp: de um responsável; foto: do estudante)
+//   * DELETE /api/estudantes/{id}/consentimentos/{cid} → revoga (o registro fica)
+// - Quem usa: comunicados/avisos (sem consentimento do canal → "sem_consentimento",
+//   nada é enviado) e lista da turma/carteirinha (sem consentimento de foto → sem foto).
+//
+// 🔐 Autenticação/escopo
+// - `X-User-Email` obrigatório; estudante e consentimentos filtrados pelo tenant.
+// - Registro e revogação entram no log de atividades (quem e quando).
+// ============================================================================
+
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"backend/internal/apierr"
+	"backend/internal/model"
+)
+
+// rótulos das finalidades no log de atividades
+var rotulosFinalidade = map[string]string{
+	model.FinalidadeEmail:    "e-mail",
+	model.FinalidadeSMS:      "SMS",
+	model.FinalidadeWhatsApp: "WhatsApp",
+	model.FinalidadeFoto:     "uso de foto",
+}
+
+// ConsentimentosEstudanteHandler despacha /api/estudantes/{id}/consentimentos[/{cid}].
+//
+// Regras/erros:
+//   - 401 se não resolver usuário; 400 se ids/JSON inválidos; 405 para outros métodos.
+//   - 404 se o estudante não for do tenant, se o responsável não for dele (POST) ou
+//     se o consentimento não existir ou já estiver revogado (DELETE).
+//   - 422 (VALIDACAO) para finalidade inválida ou sem responsavel_id (email/sms/whatsapp).
+//   - 409 (CONSENTIMENTO_ATIVO) se já houver consentimento ativo igual.
+//   - 201 no registro; 200 na listagem e na revogação (com o registro revogado).
+func ConsentimentosEstudanteHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		acesso, err := acessoFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+		estID, ok := pathID(r, "id")
+		if !ok {
+			writeAPIError(w, http.StatusBadRequest, apierr.IDInvalido, "ID do estudante inválido")
+			return
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		ok, err = estudanteDoUsuario(ctx, db, estID, acesso.TenantID)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao verificar estudante")
+			return
+		}
+		if !ok {
+			writeAPIError(w, http.StatusNotFound, apierr.EstudanteNaoEncontrado, "Estudante não encontrado")
+			return
+		}
+
+		// Coleção
+		if r.PathValue("cid") == "" {
+			switch r.Method {
+			case http.MethodGet:
+				lista, err := model.ListarConsentimentos(ctx, db, acesso.TenantID, estID)
+				if err != nil {
+					writeJSONError(w, http.StatusInternalServerError, "Erro ao listar consentimentos")
+					return
+				}
+				writeJSON(w, http.StatusOK, lista)
+			case http.MethodPost:
+				var in model.ConsentimentoRequest
+				if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+					writeDecodeError(w, err)
+					return
+				}
+				in.Sanitize()
+				if err := in.Validate(); err != nil {
+					writeValidationError(w, err)
+					return
+				}
+				c, err := model.RegistrarConsentimento(ctx, db, acesso.TenantID, estID, acesso.UsuarioID, in)
+				switch {
+				case errors.Is(err, model.ErrResponsavelDoAluno):
+					writeAPIError(w, http.StatusNotFound, apierr.ResponsavelNaoEncontrado, "Responsável não encontrado")
+					return
+				case errors.Is(err, model.ErrConsentimentoAtivo):
+					writeAPIError(w, http.StatusConflict, apierr.ConsentimentoAtivo, "Já existe um consentimento ativo para essa finalidade")
+					return
+				case err != nil:
+					writeJSONError(w, http.StatusInternalServerError, "Erro ao registrar consentimento")
+					return
+				}
+				registrarConsentimento(ctx, db, acesso, model.AcaoConsentimentoRegistrado, c)
+				writeJSON(w, http.StatusCreated, c)
+			default:
+				writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			}
+			return
+		}
+
+		// Item
+		cid, ok := pathID(r, "cid")
+		if !ok {
+			writeAPIError(w, http.StatusBadRequest, apierr.IDInvalido, "ID do consentimento inválido")
+			return
+		}
+		if r.Method != http.MethodDelete {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+		c, err := model.RevogarConsentimento(ctx, db, acesso.TenantID, estID, int64(cid), acesso.UsuarioID)
+		if errors.Is(err, sql.ErrNoRows) {
+			writeAPIError(w, http.StatusNotFound, apierr.ConsentimentoNaoEncontrado, "Consentimento não encontrado ou já revogado")
+			return
+		}
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao revogar consentimento")
+			return
+		}
+		registrarConsentimento(ctx, db, acesso, model.AcaoConsentimentoRevogado, c)
+		writeJSON(w, http.StatusOK, c)
+	}
+}
+
+// ===== helpers =====
+
+// registrarConsentimento grava o registro/revogação no log de atividades ("de e-mail (Ana)").
+func registrarConsentimento(ctx context.Context, db *sql.DB, acesso model.Acesso, acao string, c model.Consentimento) {
+	resumo := "de " + rotulosFinalidade[c.Finalidade]
+	if c.Responsavel != "" {
+		resumo += " (" + c.Responsavel + ")"
+	}
+	registrarAtividade(context.WithoutCancel(ctx), db, acesso, acao, int(c.ID), resumo, map[string]any{
+		"estudante_id": c.EstudanteID, "responsavel_id": c.ResponsavelID, "finalidade": c.Finalidade,
+	})
+}
+
+// destinatariosConsentidos marca como "sem_consentimento" os destinatários de c cujo
+// responsável não consentiu no canal do comunicado (antes de gravar e enviar).
+func destinatariosConsentidos(ctx context.Context, db *sql.DB, tenantID int, c *model.Comunicado) error {
+	ids := make([]int, 0, len(c.Destinatarios))
+	for _, d := range c.Destinatarios {
+		ids = append(ids, d.ResponsavelID)
+	}
+	consentidos, err := model.ComConsentimento(ctx, db, tenantID, c.Canal, ids)
+	if err != nil {
+		return err
+	}
+	for i := range c.Destinatarios {
+		if d := &c.Destinatarios[i]; !consentidos[d.ResponsavelID] {
+			d.Status = model.DestinatarioSemConsentimento
+		}
+	}
+	return nil
+}
@@ -0,0 +1,304 @@
+// ============================================================================
+// 📄 handler/convite_handler.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - Convites por e-mail para a organização (tabela: organizacao_convites):
+//   * GET    /api/organizacao/convites          → convites pendentes (admin)
+//   * POST   /api/organizacao/convites          → cria e envia convite (admin)
+//   * DELETE /api/organizacao/convites/{id}     → revoga convite pendente (admin)
+//   * POST   /api/organizacao/convites/aceitar  → conta convidada entra na organização
+//
+// ✉️ Envio
+// - Via notificador (modelo Convite); o provedor vem de EMAIL_DRIVER
+//   (log, smtp, sendgrid, ses). Com "log" o e-mail é apenas registrado (dev).
+// - Link do convite: APP_URL + "/convite?token=..." (APP_URL padrão http://localhost:3000).
+// ============================================================================
+
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"backend/internal/apierr"
+	"backend/internal/logging"
+	"backend/internal/model"
+	"backend/internal/notificador"
+)
+
+// adminDaOrganizacao resolve o acesso e exige papel admin numa organização.
+// Em erro já responde (401/403/404) e devolve ok=false.
+func adminDaOrganizacao(w http.ResponseWriter, r *http.Request, db *sql.DB) (model.Acesso, bool) {
+	acesso, err := acessoFromHeader(db, r)
+	if err != nil {
+		writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+		return acesso, false
+	}
+	if acesso.OrganizacaoID == 0 {
+		writeAPIError(w, http.StatusNotFound, apierr.UsuarioSemOrganizacao, "Usuário não pertence a uma organização")
+		return acesso, false
+	}
+	if !acesso.Admin() {
+		writeAPIError(w, http.StatusForbidden, apierr.SemPermissao, model.ErrSemPermissao.Error())
+		return acesso, false
+	}
+	return acesso, true
+}
+
+// ConvitesHandler trata GET/POST /api/organizacao/convites.
+//
+// Regras/erros:
+//   - 401 se não resolver usuário; 400 se JSON inválido.
+//   - 403 se não for admin; 404 se não houver organização.
+//
+// nt envia o e-mail do convite; appURL vem de config.Carregar (APP_URL).
+func ConvitesHandler(db *sql.DB, nt *notificador.Notificador, appURL string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		acesso, ok := adminDaOrganizacao(w, r, db)
+		if !ok {
+			return
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		switch r.Method {
+		case http.MethodGet:
+			listarConvites(ctx, w, db, acesso.OrganizacaoID)
+		case http.MethodPost:
+			criarConvite(ctx, w, r, db, acesso, nt, appURL)
+		default:
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+		}
+	}
+}
+
+// RevogarConviteHandler trata DELETE /api/organizacao/convites/{id} (apenas pendentes).
+func RevogarConviteHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+		acesso, ok := adminDaOrganizacao(w, r, db)
+		if !ok {
+			return
+		}
+		id, ok := pathID(r, "id")
+		if !ok {
+			writeAPIError(w, http.StatusBadRequest, apierr.IDInvalido, "ID do convite inválido")
+			return
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		res, err := db.ExecContext(ctx,
+			`DELETE FROM organizacao_convites WHERE id=$1 AND organizacao_id=$2 AND aceito_em IS NULL`,
+			id, acesso.OrganizacaoID,
+		)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao revogar convite")
+			return
+		}
+		if rows, _ := res.RowsAffected(); rows == 0 {
+			writeAPIError(w, http.StatusNotFound, apierr.ConviteInvalido, "Convite não encontrado")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// AceitarConviteHandler trata POST /api/organizacao/convites/aceitar.
+//
+// Regras/erros:
+//   - 401 se não resolver usuário; 400 se JSON/token ausente.
+//   - 404 se o convite for inválido/expirado; 403 se for para outro e-mail.
+//   - 409 se o usuário já pertencer a uma organização.
+func AceitarConviteHandler(db *sql.DB, acessos *model.CacheAcesso) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+		acesso, err := acessoFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		aceitarConvite(ctx, w, r, db, acessos, acesso)
+	}
+}
+
+// listarConvites devolve os convites ainda não aceitos e não expirados.
+func listarConvites(ctx context.Context, w http.ResponseWriter, db *sql.DB, orgID int) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT id, email, papel, to_char(expira_em, 'YYYY-MM-DD"T"HH24:MI:SSOF')
+		  FROM organizacao_convites
+		 WHERE organizacao_id=$1 AND aceito_em IS NULL AND expira_em > NOW()
+		 ORDER BY criado_em DESC
+	`, orgID)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao listar convites")
+		return
+	}
+	defer rows.Close()
+
+	out := []model.Convite{}
+	for rows.Next() {
+		var c model.Convite
+		if err := rows.Scan(&c.ID, &c.Email, &c.Papel, &c.ExpiraEm); err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao ler convite")
+			return
+		}
+		out = append(out, c)
+	}
+	if err := rows.Err(); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao iterar convites")
+		return
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// criarConvite grava o convite (hash do token) e envia o link por e-mail.
+func criarConvite(ctx context.Context, w http.ResponseWriter, r *http.Request, db *sql.DB, acesso model.Acesso, nt *notificador.Notificador, appURL string) {
+	var in model.MembroRequest
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	in.Sanitize()
+	if err := in.Validate(); err != nil {
+		writeValidationError(w, err)
+		return
+	}
+
+	token, hash, err := model.NovoTokenConvite()
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao gerar convite")
+		return
+	}
+	expira := time.Now().Add(model.ConviteTTL)
+
+	c := model.Convite{Email: in.Email, Papel: in.Papel, ExpiraEm: expira.Format(time.RFC3339)}
+	var orgNome string
+	err = db.QueryRowContext(ctx, `
+		INSERT INTO organizacao_convites (organizacao_id, email, papel, token_hash, convidado_por, expira_em)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, (SELECT nome FROM organizacoes WHERE id=$1)
+	`, acesso.OrganizacaoID, in.Email, in.Papel, hash, acesso.UsuarioID, expira).Scan(&c.ID, &orgNome)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao criar convite")
+		return
+	}
+
+	// O envio usa o contexto da requisição: o prazo de escrita do banco é curto para provedores HTTP.
+	dados := notificador.DadosConvite{
+		Organizacao: orgNome,
+		Papel:       in.Papel,
+		Link:        appURL + "/convite?token=" + token,
+		ExpiraEm:    expira,
+	}
+	if err := nt.Enviar(r.Context(), in.Email, notificador.Convite, dados); err != nil {
+		logging.De(ctx).Error("convite: falha ao enviar e-mail", "convite_id", c.ID, "erro", err)
+		writeJSONError(w, http.StatusBadGateway, "Convite criado, mas o e-mail não pôde ser enviado")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, c)
+}
+
+// aceitarConvite vincula a conta autenticada à organização do convite.
+func aceitarConvite(ctx context.Context, w http.ResponseWriter, r *http.Request, db *sql.DB, acessos *model.CacheAcesso, acesso model.Acesso) {
+	var in model.AceitarConviteRequest
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	in.Sanitize()
+	if err := in.Validate(); err != nil {
+		writeValidationError(w, err)
+		return
+	}
+	if acesso.OrganizacaoID != 0 {
+		writeAPIError(w, http.StatusConflict, apierr.UsuarioJaPertenceOrg, model.ErrJaPertenceOrganizacao.Error())
+		return
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao iniciar transação")
+		return
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var (
+		conviteID, orgID int
+		email, papel     string
+	)
+	err = tx.QueryRowContext(ctx, `
+		SELECT id, organizacao_id, email, papel
+		  FROM organizacao_convites
+		 WHERE token_hash=$1 AND aceito_em IS NULL AND expira_em > NOW()
+		 FOR UPDATE
+	`, model.HashTokenConvite(in.Token)).Scan(&conviteID, &orgID, &email, &papel)
+	if err == sql.ErrNoRows {
+		writeAPIError(w, http.StatusNotFound, apierr.ConviteInvalido, model.ErrConviteInvalido.Error())
+		return
+	}
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao buscar convite")
+		return
+	}
+
+	var emailConta string
+	if err := tx.QueryRowContext(ctx,
+		`SELECT email FROM usuarios WHERE id=$1`, acesso.UsuarioID,
+	).Scan(&emailConta); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao buscar usuário")
+		return
+	}
+	if model.NormalizarEmail(emailConta) != model.NormalizarEmail(email) {
+		writeAPIError(w, http.StatusForbidden, apierr.ConviteOutroEmail, model.ErrConviteEmail.Error())
+		return
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO organizacao_membros (organizacao_id, usuario_id, papel) VALUES ($1, $2, $3)`,
+		orgID, acesso.UsuarioID, papel,
+	)
+	if status, _, _, ok := mapPQError(err); ok {
+		writeAPIError(w, status, apierr.UsuarioJaPertenceOrg, model.ErrJaPertenceOrganizacao.Error())
+		return
+	}
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao vincular à organização")
+		return
+	}
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE organizacao_convites SET aceito_em=NOW(), aceito_por=$1 WHERE id=$2`,
+		acesso.UsuarioID, conviteID,
+	); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao registrar aceite")
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao confirmar aceite")
+		return
+	}
+	acessos.InvalidarUsuario(ctx, db, acesso.UsuarioID)
+
+	o, err := carregarOrganizacao(ctx, db, orgID)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao buscar organização")
+		return
+	}
+	writeJSON(w, http.StatusOK, o)
+}
@@ -0,0 +1,87 @@
+// ============================================================================
+// 📄 handler/docs_handler.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - GET /api/openapi.json → especificação OpenAPI 3 (montada uma única vez,
+//   a partir de openapi_rotas.go)
+// - GET /api/docs         → Swagger UI apontando para /api/openapi.json
+//
+// 🔐 Autenticação/escopo
+// - Rotas públicas; o "Authorize" do Swagger UI preenche o X-User-Email das
+//   chamadas de teste.
+// - Os assets do Swagger UI vêm de CDN (jsDelivr); sem acesso externo, use o
+//   JSON direto em outra ferramenta (Postman, Insomnia, openapi-generator).
+// - A página define a própria Content-Security-Policy (cspDocs): CDN e o script
+//   embutido pelo hash; ao editar scriptDocs o hash acompanha sozinho.
+// ============================================================================
+
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"sync"
+)
+
+// versão do Swagger UI carregada da CDN
+const swaggerUIVersao = "5.17.14"
+
+// scriptDocs inicializa o Swagger UI (único script embutido da página).
+const scriptDocs = `
+    window.ui = SwaggerUIBundle({ url: "/api/openapi.json", dom_id: "#swagger-ui" });
+  `
+
+// paginaDocs é o HTML mínimo do Swagger UI.
+const paginaDocs = `<!DOCTYPE html>
+<html lang="pt-BR">
+<head>
+  <meta charset="utf-8">
+  <title>TecMise API — Documentação</title>
+  <link rel="stylesheet" href="https://cdn.jsdelivr.net/npm/swagger-ui-dist@` + swaggerUIVersao + `/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://cdn.jsdelivr.net/npm/swagger-ui-dist@` + swaggerUIVersao + `/swagger-ui-bundle.js"></script>
+  <script>` + scriptDocs + `</script>
+</body>
+</html>
+`
+
+// cspDocs libera na página do Swagger UI só a CDN e o script embutido (pelo hash);
+// substitui a CSP restritiva padrão da API (SECURITY_CSP).
+var cspDocs = func() string {
+	h := sha256.Sum256([]byte(scriptDocs))
+	return "default-src 'none'; script-src https://cdn.jsdelivr.net 'sha256-" + base64.StdEncoding.EncodeToString(h[:]) + "'; " +
+		"style-src https://cdn.jsdelivr.net 'unsafe-inline'; img-src 'self' data: https://cdn.jsdelivr.net; connect-src 'self'; " +
+		"frame-ancestors 'none'; base-uri 'none'; form-action 'none'"
+}()
+
+// especificacaoJSON serializa o documento na primeira chamada (o catálogo é estático).
+var especificacaoJSON = sync.OnceValues(func() ([]byte, error) {
+	return json.MarshalIndent(montarOpenAPI(), "", "  ")
+})
+
+// OpenAPIHandler serve a especificação OpenAPI 3 em JSON.
+func OpenAPIHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		b, err := especificacaoJSON()
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao gerar especificação")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-cache")
+		_, _ = w.Write(b)
+	}
+}
+
+// DocsHandler serve o Swagger UI.
+func DocsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Content-Security-Policy", cspDocs)
+		_, _ = w.Write([]byte(paginaDocs))
+	}
+}
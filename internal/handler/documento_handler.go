@@ -0,0 +1,305 @@
+// ============================================================================
+// 📄 handler/documento_handler.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - Anexos/documentos por estudante (certidão, laudos, etc.), tabela `documentos`.
+//   * POST   /api/estudantes/{id}/documentos            (multipart: arquivo, tipo)
+//   * GET    /api/estudantes/{id}/documentos            (lista metadados)
+//   * GET    /api/estudantes/{id}/documentos/{docId}    (download)
+//   * DELETE /api/estudantes/{id}/documentos/{docId}
+//
+// 🔐 Autenticação/escopo
+// - `X-User-Email` obrigatório; estudante e documento precisam pertencer ao usuário.
+//
+// 🧱 Armazenamento
+// - Conteúdo no mesmo storage.Storage das fotos, chave
+//   "{usuario_id}/documentos/{estudante_id}/{aleatório}.{ext}".
+// - Limites: maxDocumentoSize e tipos em documentoTypes (PDF/JPEG/PNG).
+// - Antes de gravar, o arquivo passa pelo antivírus (ANTIVIRUS_DRIVER): 422
+//   ARQUIVO_INFECTADO; 503 com o antivírus fora do ar.
+// ============================================================================
+
+package handler
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/internal/antivirus"
+	"backend/internal/apierr"
+	"backend/internal/logging"
+	"backend/internal/storage"
+)
+
+// Documento representa um registro da tabela `documentos`.
+type Documento struct {
+	ID          int       `json:"id"`
+	EstudanteID int       `json:"estudante_id"`
+	Tipo        string    `json:"tipo"`
+	NomeArquivo string    `json:"nome_arquivo"`
+	ContentType string    `json:"content_type"`
+	Tamanho     int64     `json:"tamanho"`
+	CriadoEm    time.Time `json:"criado_em"`
+}
+
+// limite de tamanho de um documento
+const maxDocumentoSize = 10 << 20 // 10 MiB
+
+// tipos de conteúdo aceitos → extensão gravada
+var documentoTypes = map[string]string{
+	"application/pdf": ".pdf",
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+}
+
+// categorias aceitas no campo "tipo"
+var documentoTipos = map[string]bool{
+	"certidao":    true,
+	"laudo":       true,
+	"rg":          true,
+	"comprovante": true,
+	"outro":       true,
+}
+
+// pathID lê o parâmetro de rota {nome} (ex.: "/api/estudantes/{id}") como inteiro > 0.
+func pathID(r *http.Request, nome string) (int, bool) {
+	id, err := strconv.Atoi(r.PathValue(nome))
+	return id, err == nil && id > 0
+}
+
+// estudanteDoUsuario confirma que o estudante existe e pertence ao usuário.
+func estudanteDoUsuario(ctx context.Context, db *sql.DB, estudanteID, uid int) (bool, error) {
+	var ok bool
+	err := db.QueryRowContext(ctx,
+		`SELECT EXISTS(SELECT 1 FROM estudantes WHERE id=$1 AND usuario_id=$2 AND excluido_em IS NULL)`,
+		estudanteID, uid,
+	).Scan(&ok)
+	return ok, err
+}
+
+// DocumentosEstudanteHandler despacha as rotas /api/estudantes/{id}/documentos[/{docId}].
+//
+// Regras/erros:
+//   - 401 se não resolver usuário.
+//   - 400 se ids inválidos.
+//   - 404 se estudante/documento não pertencer ao usuário.
+//   - 405 para métodos não suportados.
+func DocumentosEstudanteHandler(db *sql.DB, st storage.Storage, av *antivirus.Verificador) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid, err := usuarioIDFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+
+		estID, ok := pathID(r, "id")
+		if !ok {
+			writeAPIError(w, http.StatusBadRequest, apierr.IDInvalido, "ID do estudante inválido")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+		defer cancel()
+
+		ok, err = estudanteDoUsuario(ctx, db, estID, uid)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao verificar estudante")
+			return
+		}
+		if !ok {
+			writeAPIError(w, http.StatusNotFound, apierr.EstudanteNaoEncontrado, "Estudante não encontrado")
+			return
+		}
+
+		if r.PathValue("docID") == "" {
+			switch r.Method {
+			case http.MethodGet:
+				listarDocumentos(ctx, w, db, estID, uid)
+			case http.MethodPost:
+				criarDocumento(ctx, w, r, db, st, av, estID, uid)
+			default:
+				writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			}
+			return
+		}
+
+		docID, ok := pathID(r, "docID")
+		if !ok {
+			writeAPIError(w, http.StatusBadRequest, apierr.IDInvalido, "ID do documento inválido")
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			baixarDocumento(ctx, w, db, st, estID, docID, uid)
+		case http.MethodDelete:
+			removerDocumento(ctx, w, db, st, estID, docID, uid)
+		default:
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+		}
+	}
+}
+
+// listarDocumentos responde 200 + JSON com os metadados do estudante.
+func listarDocumentos(ctx context.Context, w http.ResponseWriter, db *sql.DB, estID, uid int) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT id, estudante_id, tipo, nome_arquivo, content_type, tamanho, criado_em
+		  FROM documentos
+		 WHERE estudante_id=$1 AND usuario_id=$2
+		 ORDER BY criado_em DESC, id DESC
+	`, estID, uid)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao listar documentos")
+		return
+	}
+	defer rows.Close()
+
+	docs := []Documento{}
+	for rows.Next() {
+		var d Documento
+		if err := rows.Scan(&d.ID, &d.EstudanteID, &d.Tipo, &d.NomeArquivo, &d.ContentType, &d.Tamanho, &d.CriadoEm); err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao ler documento")
+			return
+		}
+		docs = append(docs, d)
+	}
+	if err := rows.Err(); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao iterar documentos")
+		return
+	}
+	writeJSON(w, http.StatusOK, docs)
+}
+
+// criarDocumento valida o multipart (arquivo + tipo), grava no storage e registra no banco.
+// Em falha do INSERT o arquivo recém-gravado é removido.
+func criarDocumento(ctx context.Context, w http.ResponseWriter, r *http.Request, db *sql.DB, st storage.Storage, av *antivirus.Verificador, estID, uid int) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxDocumentoSize+(1<<20))
+	file, header, err := r.FormFile("arquivo")
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			writeJSONError(w, http.StatusRequestEntityTooLarge, "Documento muito grande")
+			return
+		}
+		writeAPIError(w, http.StatusBadRequest, apierr.ArquivoInvalido, "Campo 'arquivo' ausente ou inválido")
+		return
+	}
+	defer file.Close()
+
+	tipo := strings.ToLower(strings.TrimSpace(r.FormValue("tipo")))
+	if tipo == "" {
+		tipo = "outro"
+	}
+	if !documentoTipos[tipo] {
+		writeAPIError(w, http.StatusBadRequest, apierr.Validacao, "Tipo de documento inválido")
+		return
+	}
+
+	data, err := io.ReadAll(io.LimitReader(file, maxDocumentoSize+1))
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, apierr.ArquivoInvalido, "Falha ao ler arquivo")
+		return
+	}
+	if len(data) > maxDocumentoSize {
+		writeJSONError(w, http.StatusRequestEntityTooLarge, "Documento muito grande")
+		return
+	}
+	contentType := http.DetectContentType(data)
+	ext, ok := documentoTypes[contentType]
+	if !ok {
+		writeJSONError(w, http.StatusUnsupportedMediaType, "Formato de documento não suportado (PDF, JPEG ou PNG)")
+		return
+	}
+	if !verificarAntivirus(w, r, av, data) {
+		return
+	}
+
+	nome := strings.TrimSpace(header.Filename)
+	if nome == "" {
+		nome = "documento" + ext
+	}
+	key, err := novaChaveUpload(uid, ext)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao gerar nome do arquivo")
+		return
+	}
+	key = strings.Replace(key, "/", "/documentos/"+strconv.Itoa(estID)+"/", 1)
+
+	if err := st.Put(ctx, key, bytes.NewReader(data), contentType); err != nil {
+		logging.De(r.Context()).Error("documentos: falha ao gravar arquivo", "erro", err)
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao salvar documento")
+		return
+	}
+
+	d := Documento{EstudanteID: estID, Tipo: tipo, NomeArquivo: nome, ContentType: contentType, Tamanho: int64(len(data))}
+	err = db.QueryRowContext(ctx, `
+		INSERT INTO documentos (estudante_id, usuario_id, tipo, nome_arquivo, content_type, tamanho, storage_key)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, criado_em
+	`, estID, uid, d.Tipo, d.NomeArquivo, d.ContentType, d.Tamanho, key).Scan(&d.ID, &d.CriadoEm)
+	if err != nil {
+		_ = st.Delete(ctx, key)
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao registrar documento")
+		return
+	}
+	writeJSON(w, http.StatusCreated, d)
+}
+
+// baixarDocumento transmite o arquivo com Content-Disposition de anexo.
+func baixarDocumento(ctx context.Context, w http.ResponseWriter, db *sql.DB, st storage.Storage, estID, docID, uid int) {
+	var nome, contentType, key string
+	err := db.QueryRowContext(ctx, `
+		SELECT nome_arquivo, content_type, storage_key
+		  FROM documentos
+		 WHERE id=$1 AND estudante_id=$2 AND usuario_id=$3
+	`, docID, estID, uid).Scan(&nome, &contentType, &key)
+	if err == sql.ErrNoRows {
+		writeAPIError(w, http.StatusNotFound, apierr.DocumentoNaoEncontrado, "Documento não encontrado")
+		return
+	}
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao buscar documento")
+		return
+	}
+
+	rc, err := st.Get(ctx, key)
+	if err != nil {
+		logging.De(ctx).Error("documentos: falha ao ler arquivo", "erro", err)
+		writeAPIError(w, http.StatusNotFound, apierr.DocumentoNaoEncontrado, "Arquivo do documento indisponível")
+		return
+	}
+	defer rc.Close()
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="`+strings.ReplaceAll(nome, `"`, "")+`"`)
+	_, _ = io.Copy(w, rc)
+}
+
+// removerDocumento apaga o registro e, em seguida, o arquivo no storage.
+func removerDocumento(ctx context.Context, w http.ResponseWriter, db *sql.DB, st storage.Storage, estID, docID, uid int) {
+	var key string
+	err := db.QueryRowContext(ctx, `
+		DELETE FROM documentos
+		 WHERE id=$1 AND estudante_id=$2 AND usuario_id=$3
+		RETURNING storage_key
+	`, docID, estID, uid).Scan(&key)
+	if err == sql.ErrNoRows {
+		writeAPIError(w, http.StatusNotFound, apierr.DocumentoNaoEncontrado, "Documento não encontrado")
+		return
+	}
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao remover documento")
+		return
+	}
+	if err := st.Delete(ctx, key); err != nil {
+		// o GC de uploads remove depois, se sobrar
+		logging.De(ctx).Warn("documentos: falha ao remover arquivo", "erro", err)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,61 @@
+// ============================================================================
+// 📄 handler/duplicado_handler.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - GET /api/estudantes/duplicados → grupos de prováveis duplicados do usuário.
+//   * ?min=0.0–1.0 ajusta a confiança mínima (padrão model.LimiarDuplicadoPadrao).
+//
+// 🔐 Autenticação/escopo
+// - `X-User-Email` obrigatório; só compara estudantes do próprio usuário.
+// ============================================================================
+
+package handler
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+
+	"backend/internal/apierr"
+	"backend/internal/model"
+)
+
+// DuplicadosEstudantesHandler lista grupos de estudantes possivelmente duplicados.
+//
+// Regras/erros:
+//   - 405 se método != GET; 401 se não resolver usuário.
+//   - 400 se ?min não for número entre 0 e 1.
+func DuplicadosEstudantesHandler(db *sql.DB, repo *model.EstudanteRepo) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+
+		uid, err := usuarioIDFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+
+		limiar := model.LimiarDuplicadoPadrao
+		if v := r.URL.Query().Get("min"); v != "" {
+			limiar, err = strconv.ParseFloat(v, 64)
+			if err != nil || limiar < 0 || limiar > 1 {
+				writeAPIError(w, http.StatusBadRequest, apierr.Validacao, "Parâmetro min inválido (0 a 1)")
+				return
+			}
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		estudantes, err := repo.Listar(ctx, uid, nil)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao buscar estudantes")
+			return
+		}
+
+		writeJSON(w, http.StatusOK, model.DetectarDuplicados(estudantes, limiar))
+	}
+}
@@ -0,0 +1,219 @@
+// ============================================================================
+// 📄 handler/estudante_export_handler.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - Exportação dos estudantes em formatos de outros sistemas:
+//   * GET /api/estudantes/export?format=educacenso&inep_escola=12345678
+//       → arquivo de migração do Educacenso (INEP); &validar=true só valida
+//   * GET /api/estudantes/export?format=vcf[&ano_id=N]
+//       → contatos (estudantes + responsáveis) em vCard para a agenda do celular
+//
+// 🔐 Autenticação/escopo
+// - `X-User-Email` obrigatório; exporta os estudantes ativos do tenant.
+// ============================================================================
+
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/internal/apierr"
+	"backend/internal/i18n"
+	"backend/internal/logging"
+	"backend/internal/model"
+)
+
+// ExportarEstudantesHandler despacha a exportação pelo formato (?format=).
+//
+// Regras/erros:
+//   - 405 se método != GET; 401 se não resolver usuário.
+//   - 400 se format não for suportado (educacenso | vcf) ou ano_id for inválido.
+//   - educacenso: 422 (VALIDACAO) sem inep_escola válido; 422 (EDUCACENSO_PENDENCIAS)
+//     com as pendências por estudante quando algum não atende aos campos obrigatórios.
+//   - 200 + text/plain (arquivo) ou, com validar=true, 200 + {total, validos, pendencias}.
+//   - vcf: 200 + text/vcard com um cartão por estudante e por responsável com telefone/e-mail.
+func ExportarEstudantesHandler(db *sql.DB, repo *model.EstudanteRepo) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+
+		uid, err := usuarioIDFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+
+		switch strings.ToLower(strings.TrimSpace(r.URL.Query().Get("format"))) {
+		case "educacenso":
+			exportarEducacenso(w, r, db, repo, uid)
+		case "vcf":
+			exportarVCard(w, r, db, repo, uid)
+		default:
+			writeAPIError(w, http.StatusBadRequest, apierr.Validacao, "Parâmetro format inválido (educacenso ou vcf)")
+		}
+	}
+}
+
+// exportarEducacenso valida os estudantes ativos e responde o arquivo (ou só a validação).
+func exportarEducacenso(w http.ResponseWriter, r *http.Request, db *sql.DB, repo *model.EstudanteRepo, uid int) {
+	q := r.URL.Query()
+	inep := strings.TrimSpace(q.Get("inep_escola"))
+	if err := model.ValidarInepEscola(inep); err != nil {
+		writeValidationError(w, err)
+		return
+	}
+
+	ctx, cancel := contextoRelatorio(r)
+	defer cancel()
+
+	alunos, err := carregarAlunosEducacenso(ctx, db, repo, uid)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao carregar estudantes")
+		return
+	}
+
+	validacao := model.ValidarEducacenso(alunos, time.Now())
+	if q.Get("validar") == "true" {
+		writeJSON(w, http.StatusOK, validacao.Traduzir(i18n.DaResposta(w)))
+		return
+	}
+	if len(validacao.Pendencias) > 0 {
+		apierr.Escrever(w, http.StatusUnprocessableEntity, apierr.EducacensoPendencias,
+			"Há estudantes com campos obrigatórios do Educacenso pendentes", validacao)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="educacenso-`+inep+`.txt"`)
+	if err := model.EscreverEducacenso(w, inep, alunos); err != nil {
+		logging.De(ctx).Error("educacenso: falha ao escrever arquivo", "erro", err)
+	}
+}
+
+// exportarVCard responde os contatos dos estudantes ativos (opcionalmente de um ano) e dos responsáveis.
+func exportarVCard(w http.ResponseWriter, r *http.Request, db *sql.DB, repo *model.EstudanteRepo, uid int) {
+	anoID := 0
+	if v := strings.TrimSpace(r.URL.Query().Get("ano_id")); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			writeAPIError(w, http.StatusBadRequest, apierr.IDInvalido, "ano_id inválido")
+			return
+		}
+		anoID = n
+	}
+
+	ctx, cancel := contextoRelatorio(r)
+	defer cancel()
+
+	estudantes, err := repo.Listar(ctx, uid, []string{model.StatusAtivo})
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao carregar estudantes")
+		return
+	}
+	anos, err := nomesAnos(ctx, db, uid)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao carregar estudantes")
+		return
+	}
+	lista, err := repo.ListarResponsaveis(ctx, uid)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao carregar responsáveis")
+		return
+	}
+	responsaveis := map[int][]model.Responsavel{}
+	for _, rp := range lista {
+		responsaveis[rp.EstudanteID] = append(responsaveis[rp.EstudanteID], rp)
+	}
+
+	var contatos []model.ContatoVCard
+	for _, e := range estudantes {
+		if anoID != 0 && e.AnoID != anoID {
+			continue
+		}
+		turma := anos[e.AnoID]
+		contatos = append(contatos, model.ContatoVCard{Nome: e.Nome, Telefone: e.Telefone, Email: e.Email, Org: turma, Nota: "Estudante"})
+		for _, rp := range responsaveis[e.ID] {
+			contatos = append(contatos, model.ContatoResponsavel(rp, e.Nome, turma))
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/vcard; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="contatos.vcf"`)
+	if err := model.EscreverVCards(w, contatos); err != nil {
+		logging.De(ctx).Error("vcard: falha ao escrever arquivo", "erro", err)
+	}
+}
+
+// nomesAnos mapeia id → nome dos anos não excluídos do usuário.
+func nomesAnos(ctx context.Context, db *sql.DB, uid int) (map[int]string, error) {
+	anos := map[int]string{}
+	rows, err := db.QueryContext(ctx, `SELECT id, nome FROM anos WHERE usuario_id = $1 AND excluido_em IS NULL`, uid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int
+		var nome string
+		if err := rows.Scan(&id, &nome); err != nil {
+			return nil, err
+		}
+		anos[id] = nome
+	}
+	return anos, rows.Err()
+}
+
+// carregarAlunosEducacenso junta estudantes ativos, o nome do ano e a filiação (mãe/pai).
+func carregarAlunosEducacenso(ctx context.Context, db *sql.DB, repo *model.EstudanteRepo, uid int) ([]model.AlunoEducacenso, error) {
+	estudantes, err := repo.Listar(ctx, uid, []string{model.StatusAtivo})
+	if err != nil {
+		return nil, err
+	}
+
+	anos, err := nomesAnos(ctx, db, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	// primeiro responsável de cada parentesco (por id) vira a filiação
+	maes, pais := map[int]string{}, map[int]string{}
+	rrows, err := db.QueryContext(ctx, `
+		SELECT estudante_id, nome, parentesco
+		  FROM responsaveis
+		 WHERE usuario_id = $1 AND parentesco IN ('mae', 'pai')
+		 ORDER BY id ASC`, uid)
+	if err != nil {
+		return nil, err
+	}
+	defer rrows.Close()
+	for rrows.Next() {
+		var estID int
+		var nome, parentesco string
+		if err := rrows.Scan(&estID, &nome, &parentesco); err != nil {
+			return nil, err
+		}
+		destino := maes
+		if parentesco == "pai" {
+			destino = pais
+		}
+		if _, ok := destino[estID]; !ok {
+			destino[estID] = nome
+		}
+	}
+	if err := rrows.Err(); err != nil {
+		return nil, err
+	}
+
+	out := make([]model.AlunoEducacenso, 0, len(estudantes))
+	for _, e := range estudantes {
+		out = append(out, model.AlunoEducacenso{Estudante: e, Turma: anos[e.AnoID], Filiacao1: maes[e.ID], Filiacao2: pais[e.ID]})
+	}
+	return out, nil
+}
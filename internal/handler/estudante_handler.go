@@ -0,0 +1,586 @@
+// ============================================================================
+// 📄 handler/estudante_handler.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - Handlers HTTP para estudantes: criar, listar, editar, excluir e checagens
+//   de duplicidade (CPF/E-mail).
+// - Todas as rotas exigem autenticação via Header `X-User-Email`.
+//
+// 🛡️ Segurança e Escopo
+// - Todas as operações são filtradas por `usuario_id` (dono do registro).
+// - Prazos de DB por classe de operação (contextoBanco em `handler/timeout.go`).
+// - CPF e telefone passam por model.EstudanteRepo, que os cifra em repouso.
+// - Concorrência otimista: GET devolve ETag (versão) e PUT exige If-Match.
+//
+// ============================================================================
+
+package handler
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/internal/apierr"
+	"backend/internal/jobs"
+	"backend/internal/logging"
+	"backend/internal/model"
+)
+
+// Content-Type do NDJSON (um objeto JSON por linha)
+const tipoNDJSON = "application/x-ndjson"
+
+// tipos aceitos para a listagem linha a linha: NDJSON e JSON Lines são o mesmo
+// formato; a resposta sai com o tipo que o cliente pediu
+var tiposPorLinha = []string{tipoNDJSON, "application/jsonl", "application/x-jsonlines", "application/jsonlines"}
+
+// tempo máximo da listagem em streaming (dezenas de milhares de estudantes)
+const listagemTimeout = 2 * time.Minute
+
+// ==========================
+// Helpers
+// ==========================
+
+func writeJSON(w http.ResponseWriter, status int, payload any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+// writeJSONError responde o envelope de erro padrão (apierr) com o código
+// genérico do status (ex.: 401 → NAO_AUTENTICADO, 500 → ERRO_INTERNO).
+func writeJSONError(w http.ResponseWriter, status int, msg string) {
+	apierr.Escrever(w, status, "", msg, nil)
+}
+
+// writeAPIError responde o envelope de erro com um código específico
+// (ex.: apierr.EstudanteNaoEncontrado) para o frontend tratar sem ler a mensagem.
+func writeAPIError(w http.ResponseWriter, status int, code, msg string) {
+	apierr.Escrever(w, status, code, msg, nil)
+}
+
+// writeDecodeError responde falhas ao decodificar o corpo JSON: 413 quando o
+// limite de middleware.CorpoJSON estourou no meio da leitura; senão 400 JSON_INVALIDO.
+func writeDecodeError(w http.ResponseWriter, err error) {
+	var grande *http.MaxBytesError
+	if errors.As(err, &grande) {
+		writeJSONError(w, http.StatusRequestEntityTooLarge, "Corpo da requisição muito grande")
+		return
+	}
+	writeAPIError(w, http.StatusBadRequest, apierr.JSONInvalido, "JSON inválido")
+}
+
+// writeValidationError responde erros de Validate(): ErrosValidacao vira 422 com
+// a lista de campos em details ({field, rule, message}); demais erros, 400.
+func writeValidationError(w http.ResponseWriter, err error) {
+	if ev, ok := model.ComoErrosValidacao(err); ok {
+		apierr.Escrever(w, http.StatusUnprocessableEntity, apierr.Validacao, "Dados inválidos", ev)
+		return
+	}
+	writeAPIError(w, http.StatusBadRequest, apierr.Validacao, err.Error())
+}
+
+// etagVersao formata a versão de um registro como ETag forte (ex.: "3").
+func etagVersao(v int) string {
+	return `"` + strconv.Itoa(v) + `"`
+}
+
+// versaoIfMatch lê a versão esperada do If-Match ("*" → 0 = qualquer versão).
+// false quando o valor não é um ETag emitido por etagVersao.
+func versaoIfMatch(r *http.Request) (int, bool) {
+	v := strings.TrimSpace(r.Header.Get("If-Match"))
+	if v == "*" {
+		return 0, true
+	}
+	v = strings.Trim(strings.TrimPrefix(v, "W/"), `"`)
+	n, err := strconv.Atoi(v)
+	return n, err == nil && n > 0
+}
+
+// remove tudo que não for dígito (para checagem de CPF)
+func digitsOnly(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// =============================================
+// 🔹 Criar Estudante (POST) — /api/estudantes
+// =============================================
+//
+// • Exige Nome, CPF, Email e DataNascimento
+// • Insere no banco vinculado ao usuario_id
+// • Retorna o estudante criado em JSON
+// • Publica estudante.created para os webhooks do usuário e registra na auditoria
+func CriarEstudanteHandler(db *sql.DB, repo *model.EstudanteRepo, wh *jobs.Webhooks) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+
+		// 🔐 Dono (reutiliza helper do mesmo package); o autor vai para a auditoria
+		acesso, err := acessoFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+		uid := acesso.TenantID
+
+		// 📨 Decodifica & valida (usa DTO do model)
+		var in model.EstudanteCreateRequest
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			writeDecodeError(w, err)
+			return
+		}
+		in.Sanitize()
+		if err := in.Validate(); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		// 🧱 Insere (CPF/telefone cifrados pelo repositório) e retorna o criado
+		out, err := repo.Criar(ctx, uid, in)
+		if status, code, msg, ok := mapPQError(err); ok {
+			writeAPIError(w, status, code, msg)
+			return
+		}
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao criar estudante")
+			return
+		}
+		publicarEvento(ctx, wh, uid, model.EventoEstudanteCriado, eventoEstudante(out))
+		registrarAtividade(ctx, db, acesso, model.AcaoEstudanteCriado, out.ID, out.Nome, nil)
+
+		writeJSON(w, http.StatusCreated, out)
+	}
+}
+
+// ====================================================
+// 🔹 Listar Estudantes (GET) — /api/estudantes
+// ====================================================
+//
+// • Lista todos os estudantes do usuário autenticado
+// • ?status=ativo[,transferido,...] filtra pelo ciclo de vida (sem filtro = todos)
+// • ?fields=id,nome,foto_url devolve só esses campos (lista branca em model; 400 fora dela)
+// • Ordena pelo ID crescente
+// • GET condicional: ETag/Last-Modified e 304 quando nada mudou (If-None-Match)
+// • Streaming linha a linha do banco (sem montar a lista em memória)
+// • Array JSON por padrão; um estudante por linha com Accept: application/x-ndjson ou application/jsonl
+// • Cada item é model.EstudanteResposta: idade, iniciais e nomes de ano/turma já calculados
+// • Falha no meio do envio aborta a conexão (o cliente vê resposta incompleta, não 200 "válido")
+func ListarEstudantesHandler(db *sql.DB, repo *model.EstudanteRepo) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+
+		uid, err := usuarioIDFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+
+		var status []string
+		if v := strings.TrimSpace(r.URL.Query().Get("status")); v != "" {
+			for _, s := range strings.Split(v, ",") {
+				s = strings.ToLower(strings.TrimSpace(s))
+				if !model.StatusValido(s) {
+					writeAPIError(w, http.StatusBadRequest, apierr.EstudanteStatusInvalido, model.ErrStatusInvalido.Error())
+					return
+				}
+				status = append(status, s)
+			}
+		}
+
+		campos, err := model.CamposEstudante(r.URL.Query().Get("fields"))
+		if err != nil {
+			apierr.Escrever(w, http.StatusBadRequest, apierr.RequisicaoInvalida, err.Error(),
+				map[string]any{"campos_permitidos": model.CamposEstudantePermitidos()})
+			return
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		porLinha := tipoPorLinha(r.Header.Get("Accept"))
+		total, ultima, err := repo.Marca(ctx, uid, status)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao buscar estudantes")
+			return
+		}
+		w.Header().Add("Vary", "Accept")
+		// a idade calculada muda na virada do dia: a listagem vale no máximo desde a meia-noite
+		y, m, d := time.Now().Date()
+		if meiaNoite := time.Date(y, m, d, 0, 0, 0, 0, time.Local); ultima.Before(meiaNoite) {
+			ultima = meiaNoite
+		}
+		if responderCondicional(w, r, ultima, uid, total, porLinha) {
+			return
+		}
+
+		// listas grandes passam do DB_TIMEOUT_READ e do HTTP_WRITE_TIMEOUT
+		ctxLista, cancelLista := context.WithTimeout(r.Context(), listagemTimeout)
+		defer cancelLista()
+		_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(listagemTimeout))
+
+		enviado, err := transmitirEstudantes(ctxLista, w, repo, uid, status, campos, porLinha)
+		if err != nil && !enviado {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao buscar estudantes")
+			return
+		}
+		if err != nil {
+			logging.De(r.Context()).Error("listar estudantes: streaming interrompido", "erro", err)
+			panic(http.ErrAbortHandler)
+		}
+	}
+}
+
+// tipoPorLinha devolve o tipo de tiposPorLinha pedido em Accept ("" = array JSON).
+// Tipos com q=0 (recusados pelo cliente) não contam.
+func tipoPorLinha(accept string) string {
+	for _, parte := range strings.Split(accept, ",") {
+		tipo, params, _ := strings.Cut(parte, ";")
+		tipo = strings.ToLower(strings.TrimSpace(tipo))
+		if !slices.Contains(tiposPorLinha, tipo) {
+			continue
+		}
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if q, err := strconv.ParseFloat(v, 64); err == nil && q == 0 {
+				continue
+			}
+		}
+		return tipo
+	}
+	return ""
+}
+
+// transmitirEstudantes escreve a listagem à medida que as linhas chegam do banco
+// (só com campos, quando informados): array JSON, ou um objeto por linha com o
+// Content-Type porLinha. enviado=true quando o status 200 já saiu (erro depois
+// disso não vira 500).
+func transmitirEstudantes(ctx context.Context, w http.ResponseWriter, repo *model.EstudanteRepo, uid int, status, campos []string, porLinha string) (enviado bool, err error) {
+	ndjson := porLinha != ""
+	bw := bufio.NewWriterSize(w, 32<<10)
+	enc := json.NewEncoder(bw)
+	iniciar := func() {
+		if enviado {
+			return
+		}
+		enviado = true
+		if ndjson {
+			w.Header().Set("Content-Type", porLinha)
+		} else {
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		}
+		w.WriteHeader(http.StatusOK)
+		if !ndjson {
+			_ = bw.WriteByte('[')
+		}
+	}
+
+	n := 0
+	hoje := time.Now()
+	err = repo.PercorrerCampos(ctx, uid, status, campos, func(est model.Estudante) error {
+		iniciar()
+		if !ndjson && n > 0 {
+			if err := bw.WriteByte(','); err != nil {
+				return err
+			}
+		}
+		n++
+		if campos != nil {
+			return enc.Encode(model.NovaEstudanteResposta(est, hoje).Parcial(campos))
+		}
+		return enc.Encode(model.NovaEstudanteResposta(est, hoje))
+	})
+	if err != nil {
+		return enviado, err
+	}
+	iniciar()
+	if !ndjson {
+		_, _ = bw.WriteString("]\n")
+	}
+	return true, bw.Flush()
+}
+
+// =========================================================
+// 🔹 Buscar Estudante (GET) — /api/estudantes/{id}
+// =========================================================
+//
+// • Retorna o estudante do usuário com seus responsáveis e campos derivados (model.EstudanteResposta)
+// • ETag = versão do estudante (enviar de volta em If-Match no PUT)
+func BuscarEstudanteHandler(db *sql.DB, repo *model.EstudanteRepo) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+
+		uid, err := usuarioIDFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+
+		id, ok := pathID(r, "id")
+		if !ok {
+			writeAPIError(w, http.StatusBadRequest, apierr.IDInvalido, "ID do estudante inválido")
+			return
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		est, err := repo.Buscar(ctx, id, uid)
+		if err == sql.ErrNoRows {
+			writeAPIError(w, http.StatusNotFound, apierr.EstudanteNaoEncontrado, "Estudante não encontrado")
+			return
+		}
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao buscar estudante")
+			return
+		}
+
+		out := model.EstudanteDetalhe{EstudanteResposta: model.NovaEstudanteResposta(est, time.Now())}
+		out.Responsaveis, err = repo.ListarResponsaveis(ctx, uid, id)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao buscar responsáveis")
+			return
+		}
+
+		w.Header().Set("ETag", etagVersao(est.Versao))
+		writeJSON(w, http.StatusOK, out)
+	}
+}
+
+// =========================================================
+// 🔹 Editar Estudante (PUT) — /api/estudantes/{id}
+// =========================================================
+//
+//   - Valida campos obrigatórios (mantém contrato atual)
+//   - Atualiza dados apenas se pertencer ao usuário
+//   - Exige If-Match com o ETag do GET: 428 se ausente, 412 se outra pessoa
+//     alterou o estudante nesse meio tempo ("*" ignora a checagem)
+//   - Registra a edição na auditoria
+func EditarEstudanteHandler(db *sql.DB, repo *model.EstudanteRepo) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+
+		acesso, err := acessoFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+		uid := acesso.TenantID
+
+		// ID do path
+		id, ok := pathID(r, "id")
+		if !ok {
+			writeAPIError(w, http.StatusBadRequest, apierr.IDInvalido, "ID do estudante inválido")
+			return
+		}
+
+		if r.Header.Get("If-Match") == "" {
+			writeAPIError(w, http.StatusPreconditionRequired, apierr.PrecondicaoObrigatoria,
+				"Cabeçalho If-Match obrigatório (use o ETag devolvido pelo GET)")
+			return
+		}
+		versao, ok := versaoIfMatch(r)
+		if !ok {
+			writeAPIError(w, http.StatusPreconditionFailed, apierr.VersaoDivergente, model.ErrVersaoDivergente.Error())
+			return
+		}
+
+		// Decodifica & valida (usamos DTO de criação para manter "todos obrigatórios")
+		var in model.EstudanteCreateRequest
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			writeDecodeError(w, err)
+			return
+		}
+		in.Sanitize()
+		if err := in.Validate(); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		nova, err := repo.Atualizar(ctx, id, uid, versao, in)
+		if status, code, msg, mapped := mapPQError(err); mapped {
+			writeAPIError(w, status, code, msg)
+			return
+		}
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			writeAPIError(w, http.StatusNotFound, apierr.EstudanteNaoEncontrado, "Estudante não encontrado")
+			return
+		case errors.Is(err, model.ErrVersaoDivergente):
+			writeAPIError(w, http.StatusPreconditionFailed, apierr.VersaoDivergente, err.Error())
+			return
+		case err != nil:
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao editar estudante")
+			return
+		}
+
+		registrarAtividade(ctx, db, acesso, model.AcaoEstudanteAtualizado, id, in.Nome, nil)
+
+		w.Header().Set("ETag", etagVersao(nova))
+		writeJSON(w, http.StatusOK, map[string]any{"message": "Estudante editado com sucesso", "versao": nova})
+	}
+}
+
+// ==========================================================
+// 🔹 Remover Estudante (DELETE) — /api/estudantes/{id}
+// ==========================================================
+//
+// • Move o estudante para a lixeira apenas se pertencer ao usuário (restaurável)
+// • Publica estudante.deleted para os webhooks do usuário e registra na auditoria
+func RemoverEstudanteHandler(db *sql.DB, repo *model.EstudanteRepo, wh *jobs.Webhooks) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+
+		acesso, err := acessoFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+		uid := acesso.TenantID
+
+		id, ok := pathID(r, "id")
+		if !ok {
+			writeAPIError(w, http.StatusBadRequest, apierr.IDInvalido, "ID do estudante inválido")
+			return
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		nome, err := repo.Remover(ctx, id, uid, acesso.UsuarioID)
+		if errors.Is(err, sql.ErrNoRows) {
+			writeAPIError(w, http.StatusNotFound, apierr.EstudanteNaoEncontrado, "Estudante não encontrado")
+			return
+		}
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao excluir estudante")
+			return
+		}
+		publicarEvento(ctx, wh, uid, model.EventoEstudanteRemovido, map[string]int{"id": id})
+		registrarAtividade(ctx, db, acesso, model.AcaoEstudanteRemovido, id, nome, nil)
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// =============================================================
+// 🔹 Verificar CPF duplicado (GET)
+//
+//	/api/estudantes/check-cpf?cpf=...&ignoreId=...
+//
+// =============================================================
+func VerificarCpfHandler(db *sql.DB, repo *model.EstudanteRepo) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+
+		uid, err := usuarioIDFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+
+		cpf := digitsOnly(strings.TrimSpace(r.URL.Query().Get("cpf")))
+		ignoreID := strings.TrimSpace(r.URL.Query().Get("ignoreId"))
+		if ignoreID == "" {
+			ignoreID = strings.TrimSpace(r.URL.Query().Get("excludeId"))
+		}
+		if cpf == "" {
+			writeAPIError(w, http.StatusBadRequest, apierr.Validacao, "cpf é obrigatório")
+			return
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		ignorar, _ := strconv.Atoi(ignoreID)
+		exists, err := repo.CPFEmUso(ctx, uid, cpf, ignorar)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao verificar CPF")
+			return
+		}
+
+		writeJSON(w, http.StatusOK, map[string]bool{"exists": exists})
+	}
+}
+
+// =============================================================
+// 🔹 Verificar E-mail duplicado (GET)
+//
+//	/api/estudantes/check-email?email=...&ignoreId=...
+//
+// =============================================================
+func VerificarEmailHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+
+		uid, err := usuarioIDFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+
+		emailParam := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("email")))
+		ignoreID := strings.TrimSpace(r.URL.Query().Get("ignoreId"))
+		if ignoreID == "" {
+			ignoreID = strings.TrimSpace(r.URL.Query().Get("excludeId"))
+		}
+		if emailParam == "" {
+			writeAPIError(w, http.StatusBadRequest, apierr.Validacao, "email é obrigatório")
+			return
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		query := `SELECT 1 FROM estudantes WHERE usuario_id=$1 AND email=$2 AND excluido_em IS NULL`
+		args := []any{uid, emailParam}
+		if ignoreID != "" {
+			query += ` AND id<>$3`
+			args = append(args, ignoreID)
+		}
+
+		var dummy int
+		err = db.QueryRowContext(ctx, query, args...).Scan(&dummy)
+		exists := (err == nil)
+
+		writeJSON(w, http.StatusOK, map[string]bool{"exists": exists})
+	}
+}
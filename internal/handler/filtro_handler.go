@@ -0,0 +1,224 @@
+// ============================================================================
+// 📄 handler/filtro_handler.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - Filtros salvos ("listas inteligentes") de estudantes:
+//   * GET    /api/filtros                   → lista os filtros da conta (por nome)
+//   * POST   /api/filtros                   → salva { nome, filtro }
+//   * PUT    /api/filtros/{id}              → renomeia/altera o filtro
+//   * DELETE /api/filtros/{id}              → remove
+//   * GET    /api/filtros/{id}/resultados   → executa o filtro sobre os estudantes atuais
+//
+// 🔐 Autenticação/escopo
+// - `X-User-Email` obrigatório; o filtro pertence à conta que o salvou (pessoal,
+//   inclusive para o papel leitor), e os resultados saem dos dados do tenant.
+// ============================================================================
+
+package handler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+
+	"backend/internal/apierr"
+	"backend/internal/model"
+)
+
+// FiltrosHandler despacha /api/filtros e /api/filtros/{id}.
+//
+// Regras/erros:
+//   - 401 se não resolver usuário; 405 para método não suportado.
+//   - 400 se o id for inválido ou o JSON malformado; 422 (VALIDACAO) para nome/filtro inválidos.
+//   - 404 se o filtro não for da conta; 409 (FILTRO_NOME_DUPLICADO) para nome repetido.
+//   - 201 na criação; 200 na listagem/alteração; 204 na remoção.
+func FiltrosHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		acesso, err := acessoFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		switch r.Method {
+		case http.MethodGet:
+			rows, err := db.QueryContext(ctx, `
+				SELECT id, nome, filtro, criado_em, atualizado_em
+				  FROM filtros_salvos
+				 WHERE usuario_id=$1
+				 ORDER BY LOWER(nome), id
+			`, acesso.UsuarioID)
+			if err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao listar filtros")
+				return
+			}
+			defer rows.Close()
+			out := []model.FiltroSalvo{}
+			for rows.Next() {
+				f, err := scanFiltroSalvo(rows)
+				if err != nil {
+					writeJSONError(w, http.StatusInternalServerError, "Erro ao ler filtros")
+					return
+				}
+				out = append(out, f)
+			}
+			if err := rows.Err(); err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao ler filtros")
+				return
+			}
+			writeJSON(w, http.StatusOK, out)
+
+		case http.MethodPost, http.MethodPut:
+			id := 0
+			if r.Method == http.MethodPut {
+				var ok bool
+				if id, ok = pathID(r, "id"); !ok {
+					writeAPIError(w, http.StatusBadRequest, apierr.IDInvalido, "ID do filtro inválido")
+					return
+				}
+			}
+			var in model.FiltroSalvoRequest
+			if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+				writeDecodeError(w, err)
+				return
+			}
+			in.Sanitize()
+			if err := in.Validate(); err != nil {
+				writeValidationError(w, err)
+				return
+			}
+			spec, err := json.Marshal(in.Filtro)
+			if err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao salvar filtro")
+				return
+			}
+
+			var row *sql.Row
+			status := http.StatusOK
+			if id == 0 {
+				status = http.StatusCreated
+				row = db.QueryRowContext(ctx, `
+					INSERT INTO filtros_salvos (usuario_id, nome, filtro)
+					VALUES ($1, $2, $3)
+					RETURNING id, nome, filtro, criado_em, atualizado_em
+				`, acesso.UsuarioID, in.Nome, string(spec))
+			} else {
+				row = db.QueryRowContext(ctx, `
+					UPDATE filtros_salvos
+					   SET nome=$3, filtro=$4, atualizado_em=NOW()
+					 WHERE id=$1 AND usuario_id=$2
+					RETURNING id, nome, filtro, criado_em, atualizado_em
+				`, id, acesso.UsuarioID, in.Nome, string(spec))
+			}
+			f, err := scanFiltroSalvo(row)
+			if st, code, msg, ok := mapPQError(err); ok {
+				writeAPIError(w, st, code, msg)
+				return
+			}
+			if err == sql.ErrNoRows {
+				writeAPIError(w, http.StatusNotFound, apierr.FiltroNaoEncontrado, "Filtro não encontrado")
+				return
+			}
+			if err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao salvar filtro")
+				return
+			}
+			writeJSON(w, status, f)
+
+		case http.MethodDelete:
+			id, ok := pathID(r, "id")
+			if !ok {
+				writeAPIError(w, http.StatusBadRequest, apierr.IDInvalido, "ID do filtro inválido")
+				return
+			}
+			res, err := db.ExecContext(ctx, `DELETE FROM filtros_salvos WHERE id=$1 AND usuario_id=$2`, id, acesso.UsuarioID)
+			if err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao remover filtro")
+				return
+			}
+			if n, _ := res.RowsAffected(); n == 0 {
+				writeAPIError(w, http.StatusNotFound, apierr.FiltroNaoEncontrado, "Filtro não encontrado")
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+
+		default:
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+		}
+	}
+}
+
+// ResultadosFiltroHandler trata GET /api/filtros/{id}/resultados
+//
+// Regras/erros:
+//   - 405 se método != GET; 401 se não resolver usuário.
+//   - 400 se id inválido; 404 se o filtro não for da conta.
+//   - 200 + { filtro, total, itens } com os estudantes (não excluídos) que atendem ao filtro, por id.
+func ResultadosFiltroHandler(db *sql.DB, repo *model.EstudanteRepo) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+		acesso, err := acessoFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+		id, ok := pathID(r, "id")
+		if !ok {
+			writeAPIError(w, http.StatusBadRequest, apierr.IDInvalido, "ID do filtro inválido")
+			return
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		f, err := scanFiltroSalvo(db.QueryRowContext(ctx, `
+			SELECT id, nome, filtro, criado_em, atualizado_em
+			  FROM filtros_salvos
+			 WHERE id=$1 AND usuario_id=$2
+		`, id, acesso.UsuarioID))
+		if err == sql.ErrNoRows {
+			writeAPIError(w, http.StatusNotFound, apierr.FiltroNaoEncontrado, "Filtro não encontrado")
+			return
+		}
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao buscar filtro")
+			return
+		}
+
+		// status vai para o SQL; os demais critérios são conferidos já decifrados
+		todos, err := repo.Listar(ctx, acesso.TenantID, f.Filtro.Status)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao buscar estudantes")
+			return
+		}
+		out := model.ResultadoFiltro{Filtro: f, Itens: []model.Estudante{}}
+		for _, e := range todos {
+			if f.Filtro.Corresponde(e) {
+				out.Itens = append(out.Itens, e)
+			}
+		}
+		out.Total = len(out.Itens)
+		writeJSON(w, http.StatusOK, out)
+	}
+}
+
+// scanFiltroSalvo lê as colunas id, nome, filtro, criado_em, atualizado_em.
+func scanFiltroSalvo(s interface{ Scan(...any) error }) (model.FiltroSalvo, error) {
+	var (
+		f    model.FiltroSalvo
+		spec []byte
+	)
+	if err := s.Scan(&f.ID, &f.Nome, &spec, &f.CriadoEm, &f.AtualizadoEm); err != nil {
+		return f, err
+	}
+	if err := json.Unmarshal(spec, &f.Filtro); err != nil {
+		return f, err
+	}
+	return f, nil
+}
@@ -0,0 +1,121 @@
+// ============================================================================
+// 📄 handler/google_token.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - Validação dos ID Tokens Google (login e vinculação de contas) com:
+//   * um único idtoken.Validator por processo (as chaves públicas do Google
+//     ficam em cache pelo max-age da resposta, ~horas);
+//   * prazo próprio (GOOGLE_TOKEN_TIMEOUT), separado dos prazos de banco;
+//   * disjuntor na busca das chaves: após GOOGLE_BREAKER_FAILURES falhas
+//     seguidas (rede, timeout ou 5xx), as validações que precisariam da rede
+//     respondem 503 GOOGLE_INDISPONIVEL na hora, por GOOGLE_BREAKER_COOLDOWN.
+//
+// 🔐 Autenticação/escopo
+// - Com as chaves em cache, tokens continuam sendo validados mesmo com o
+//   disjuntor aberto; só a busca de chaves novas é recusada.
+// - Token inválido (assinatura, audience, expiração) nunca conta como falha
+//   do Google: continua respondendo 401.
+// ============================================================================
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"backend/internal/apierr"
+	"backend/internal/disjuntor"
+
+	"google.golang.org/api/idtoken"
+	"google.golang.org/api/option"
+)
+
+// errGoogleIndisponivel: a busca das chaves públicas falhou (ou o disjuntor está aberto)
+var errGoogleIndisponivel = errors.New("serviço de login do Google indisponível no momento")
+
+// validacaoGoogle reúne o validador, o prazo e o disjuntor da validação de tokens.
+type validacaoGoogle struct {
+	timeout   time.Duration
+	disjuntor *disjuntor.Disjuntor
+	validador func() (*idtoken.Validator, error)
+}
+
+// validação em uso (ConfigurarValidacaoGoogle substitui os padrões na subida)
+var tokensGoogle = novaValidacaoGoogle(5*time.Second, 5, 30*time.Second)
+
+// ConfigurarValidacaoGoogle define o prazo da validação e o disjuntor (falhas
+// seguidas até abrir, pausa aberto). Chamar antes de registrar as rotas.
+func ConfigurarValidacaoGoogle(timeout time.Duration, falhas int, pausa time.Duration) {
+	tokensGoogle = novaValidacaoGoogle(timeout, falhas, pausa)
+}
+
+func novaValidacaoGoogle(timeout time.Duration, falhas int, pausa time.Duration) *validacaoGoogle {
+	v := &validacaoGoogle{timeout: timeout, disjuntor: disjuntor.Novo(falhas, pausa)}
+	cliente := &http.Client{
+		Timeout:   timeout,
+		Transport: &transporteGoogle{base: http.DefaultTransport, disjuntor: v.disjuntor},
+	}
+	// criado na primeira validação (NewValidator não acessa a rede)
+	v.validador = sync.OnceValues(func() (*idtoken.Validator, error) {
+		return idtoken.NewValidator(context.Background(), option.WithHTTPClient(cliente))
+	})
+	return v
+}
+
+// validar confere o token no prazo configurado. errGoogleIndisponivel quando
+// as chaves não puderam ser obtidas; qualquer outro erro é token inválido.
+func (v *validacaoGoogle) validar(ctx context.Context, token, clientID string) (*idtoken.Payload, error) {
+	validador, err := v.validador()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errGoogleIndisponivel, err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, v.timeout)
+	defer cancel()
+	return validador.Validate(ctx, token, clientID)
+}
+
+// transporteGoogle passa as buscas de chaves pelo disjuntor.
+type transporteGoogle struct {
+	base      http.RoundTripper
+	disjuntor *disjuntor.Disjuntor
+}
+
+func (t *transporteGoogle) RoundTrip(req *http.Request) (*http.Response, error) {
+	if _, err := t.disjuntor.Permitir(); err != nil {
+		return nil, fmt.Errorf("%w: %w", errGoogleIndisponivel, err)
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		// cliente que desistiu não diz nada sobre o Google
+		if !errors.Is(req.Context().Err(), context.Canceled) {
+			t.disjuntor.Registrar(false)
+		}
+		return nil, fmt.Errorf("%w: %w", errGoogleIndisponivel, err)
+	}
+	if resp.StatusCode >= http.StatusInternalServerError {
+		resp.Body.Close()
+		t.disjuntor.Registrar(false)
+		return nil, fmt.Errorf("%w: status %d", errGoogleIndisponivel, resp.StatusCode)
+	}
+	t.disjuntor.Registrar(true)
+	return resp, nil
+}
+
+// writeErroTokenGoogle responde a falha de validarTokenGoogle: 503 com
+// Retry-After se o Google estiver indisponível, 401 para token inválido.
+func writeErroTokenGoogle(w http.ResponseWriter, err error) {
+	if errors.Is(err, errGoogleIndisponivel) {
+		espera := max(int(math.Ceil(tokensGoogle.disjuntor.Restante().Seconds())), 1)
+		w.Header().Set("Retry-After", strconv.Itoa(espera))
+		writeAPIError(w, http.StatusServiceUnavailable, apierr.GoogleIndisponivel,
+			"Login com Google indisponível no momento; tente novamente em instantes ou entre com e-mail e senha")
+		return
+	}
+	writeAPIError(w, http.StatusUnauthorized, apierr.GoogleTokenInvalido, err.Error())
+}
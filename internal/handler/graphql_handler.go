@@ -0,0 +1,484 @@
+// ============================================================================
+// 📄 handler/graphql_handler.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - GET/POST /api/graphql → domínio de estudantes em GraphQL (pacote graphql):
+//   * Query: estudantes(filtro, limite, offset), estudante(id), anos(arquivados)
+//   * Estudante traz ano/turma (Ano) e responsaveis aninhados
+//   * Mutation: criarEstudante, editarEstudante (parcial), removerEstudante
+// - Reaproveita model.EstudanteRepo (CPF/telefone cifrados) e os mesmos DTOs e
+//   validações das rotas REST; criar/remover publicam os mesmos eventos de webhook.
+//
+// 🔐 Autenticação/escopo
+// - X-User-Email obrigatório (401); dados filtrados pelo tenant, como no REST.
+// - Mutations exigem papel com escrita (403 para "leitor") e só via POST;
+//   GET aceita apenas queries.
+// - Estudantes, anos e responsáveis são carregados uma vez por requisição
+//   (sem N+1 nos campos aninhados); mutations invalidam esse cache.
+// ============================================================================
+
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"backend/internal/apierr"
+	"backend/internal/graphql"
+	"backend/internal/i18n"
+	"backend/internal/jobs"
+	"backend/internal/logging"
+	"backend/internal/model"
+)
+
+// paginação de Query.estudantes
+const (
+	graphqlLimitePadrao = 50
+	graphqlLimiteMaximo = 200
+)
+
+// chaveSessaoGraphQL guarda a sessaoGraphQL no contexto da execução.
+type chaveSessaoGraphQL struct{}
+
+// sessaoGraphQL é o estado de uma requisição: acesso e dados já carregados.
+type sessaoGraphQL struct {
+	db           *sql.DB
+	repo         *model.EstudanteRepo
+	webhooks     *jobs.Webhooks
+	uid          int
+	acesso       model.Acesso // autor das mutações (log de auditoria)
+	estudantes   []model.Estudante
+	anos         []Ano
+	responsaveis map[int][]model.Responsavel
+	mutou        bool // após uma mutation, lê do primário (a réplica pode estar atrás)
+}
+
+// estudanteFiltro é o argumento filtro de Query.estudantes.
+type estudanteFiltro struct {
+	Status  string `json:"status"`
+	AnoID   int    `json:"ano_id"`
+	TurmaID int    `json:"turma_id"`
+	Busca   string `json:"busca"` // trecho do nome ou e-mail (sem diferenciar maiúsculas)
+}
+
+// GraphQLHandler atende /api/graphql (POST com {query, variables, operationName};
+// GET com os mesmos campos na query string).
+//
+// Regras/erros:
+//   - 401 sem usuário; 400 para documento inválido (sintaxe/campos); 403 para
+//     mutation de papel "leitor"; 405 para mutation via GET.
+//   - Erros de resolvers saem em "errors" com status 200 e extensions.code do catálogo apierr.
+func GraphQLHandler(db *sql.DB, repo *model.EstudanteRepo, wh *jobs.Webhooks) http.HandlerFunc {
+	schema := schemaEstudantes()
+	return func(w http.ResponseWriter, r *http.Request) {
+		acesso, err := acessoFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+
+		var req graphql.Requisicao
+		if r.Method == http.MethodGet {
+			q := r.URL.Query()
+			req.Query, req.OperationName = q.Get("query"), q.Get("operationName")
+			if v := q.Get("variables"); v != "" {
+				if err := json.Unmarshal([]byte(v), &req.Variables); err != nil {
+					writeAPIError(w, http.StatusBadRequest, apierr.JSONInvalido, "variables inválido (esperado objeto JSON)")
+					return
+				}
+			}
+		} else if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeDecodeError(w, err)
+			return
+		}
+
+		op, erros := schema.Preparar(req)
+		if len(erros) > 0 {
+			writeJSON(w, http.StatusBadRequest, graphql.Resposta{Errors: traduzirErrosGraphQL(w, erros)})
+			return
+		}
+		if op.Tipo() == "mutation" {
+			if r.Method == http.MethodGet {
+				w.Header().Set("Allow", http.MethodPost)
+				writeAPIError(w, http.StatusMethodNotAllowed, apierr.MetodoNaoPermitido, "Mutations só via POST")
+				return
+			}
+			if !acesso.PodeEscrever() {
+				writeAPIError(w, http.StatusForbidden, apierr.SemPermissao, model.ErrSemPermissao.Error())
+				return
+			}
+		}
+
+		ctx, cancel := contextoRelatorio(r)
+		defer cancel()
+		ctx = context.WithValue(ctx, chaveSessaoGraphQL{}, &sessaoGraphQL{db: db, repo: repo, webhooks: wh, uid: acesso.TenantID, acesso: acesso})
+
+		resp := op.Executar(ctx)
+		resp.Errors = traduzirErrosGraphQL(w, resp.Errors)
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
+
+// traduzirErrosGraphQL passa os erros com extensions.code (erroGraphQL) para o
+// idioma da resposta; erros de sintaxe/validação do documento ficam como estão.
+func traduzirErrosGraphQL(w http.ResponseWriter, erros []*graphql.Erro) []*graphql.Erro {
+	idioma := i18n.DaResposta(w)
+	for _, e := range erros {
+		code, ok := e.Extensions["code"].(string)
+		if !ok {
+			continue
+		}
+		e.Message = apierr.Mensagem(idioma, code, e.Message)
+		if t, ok := e.Extensions["details"].(apierr.Traduzivel); ok {
+			e.Extensions["details"] = t.Traduzir(idioma)
+		}
+	}
+	return erros
+}
+
+// schemaEstudantes declara tipos, campos e resolvers.
+func schemaEstudantes() *graphql.Schema {
+	responsavel := &graphql.Objeto{Nome: "Responsavel", Campos: escalares(
+		"id", "estudante_id", "nome", "cpf", "telefone", "email", "parentesco",
+	)}
+	ano := &graphql.Objeto{Nome: "Ano", Campos: escalares(
+		"id", "nome", "ordem", "arquivado", "periodo_letivo_id", "quantidade_estudantes",
+	)}
+	estudante := &graphql.Objeto{Nome: "Estudante", Campos: escalares(
+		"id", "nome", "cpf", "email", "data_nascimento", "telefone", "foto_url",
+		"ano_id", "turma_id", "status", "versao",
+	)}
+	estudante.Campos["ano"] = &graphql.Campo{Tipo: ano, Resolver: func(ctx context.Context, pai any, _ map[string]any) (any, error) {
+		return sessao(ctx).anoPorID(ctx, pai.(model.Estudante).AnoID)
+	}}
+	estudante.Campos["turma"] = &graphql.Campo{Tipo: ano, Resolver: func(ctx context.Context, pai any, _ map[string]any) (any, error) {
+		return sessao(ctx).anoPorID(ctx, pai.(model.Estudante).TurmaID)
+	}}
+	estudante.Campos["responsaveis"] = &graphql.Campo{Tipo: responsavel, Resolver: func(ctx context.Context, pai any, _ map[string]any) (any, error) {
+		rs, err := sessao(ctx).carregarResponsaveis(ctx)
+		if err != nil {
+			return nil, erroGraphQL(ctx, err)
+		}
+		if lista := rs[pai.(model.Estudante).ID]; lista != nil {
+			return lista, nil
+		}
+		return []model.Responsavel{}, nil
+	}}
+	ano.Campos["estudantes"] = &graphql.Campo{Tipo: estudante, Resolver: func(ctx context.Context, pai any, _ map[string]any) (any, error) {
+		todos, err := sessao(ctx).carregarEstudantes(ctx)
+		if err != nil {
+			return nil, erroGraphQL(ctx, err)
+		}
+		return filtrarEstudantes(todos, estudanteFiltro{AnoID: pai.(Ano).ID}), nil
+	}}
+	pagina := &graphql.Objeto{Nome: "EstudantePagina", Campos: escalares("total", "limite", "offset")}
+	pagina.Campos["itens"] = &graphql.Campo{Tipo: estudante}
+
+	query := &graphql.Objeto{Nome: "Query", Campos: map[string]*graphql.Campo{
+		"estudantes": {
+			Tipo:     pagina,
+			Args:     map[string]any{"filtro": nil, "limite": graphqlLimitePadrao, "offset": 0},
+			Resolver: resolverEstudantes,
+		},
+		"estudante": {
+			Tipo: estudante,
+			Args: map[string]any{"id": nil},
+			Resolver: func(ctx context.Context, _ any, args map[string]any) (any, error) {
+				id, ok := graphql.ArgInt(args, "id")
+				if !ok || id <= 0 {
+					return nil, graphql.NovoErro("id inválido", apierr.IDInvalido, nil)
+				}
+				s := sessao(ctx)
+				est, err := s.repo.Buscar(ctx, id, s.uid)
+				if errors.Is(err, sql.ErrNoRows) {
+					return nil, nil
+				}
+				if err != nil {
+					return nil, erroGraphQL(ctx, err)
+				}
+				return est, nil
+			},
+		},
+		"anos": {
+			Tipo: ano,
+			Args: map[string]any{"arquivados": false},
+			Resolver: func(ctx context.Context, _ any, args map[string]any) (any, error) {
+				anos, err := sessao(ctx).carregarAnos(ctx)
+				if err != nil {
+					return nil, erroGraphQL(ctx, err)
+				}
+				arquivados, _ := graphql.ArgBool(args, "arquivados")
+				out := []Ano{}
+				for _, a := range anos {
+					if arquivados || !a.Arquivado {
+						out = append(out, a)
+					}
+				}
+				return out, nil
+			},
+		},
+	}}
+
+	mutation := &graphql.Objeto{Nome: "Mutation", Campos: map[string]*graphql.Campo{
+		"criarEstudante": {
+			Tipo:     estudante,
+			Args:     map[string]any{"input": nil},
+			Resolver: resolverCriarEstudante,
+		},
+		"editarEstudante": {
+			Tipo:     estudante,
+			Args:     map[string]any{"id": nil, "versao": nil, "input": nil},
+			Resolver: resolverEditarEstudante,
+		},
+		"removerEstudante": {
+			Args: map[string]any{"id": nil},
+			Resolver: func(ctx context.Context, _ any, args map[string]any) (any, error) {
+				id, ok := graphql.ArgInt(args, "id")
+				if !ok || id <= 0 {
+					return nil, graphql.NovoErro("id inválido", apierr.IDInvalido, nil)
+				}
+				s := sessao(ctx)
+				nome, err := s.repo.Remover(ctx, id, s.uid, s.acesso.UsuarioID)
+				if err != nil {
+					return nil, erroGraphQL(ctx, err)
+				}
+				registrarAtividade(ctx, s.db, s.acesso, model.AcaoEstudanteRemovido, id, nome, nil)
+				publicarEvento(ctx, s.webhooks, s.uid, model.EventoEstudanteRemovido, map[string]int{"id": id})
+				s.invalidar()
+				return true, nil
+			},
+		},
+	}}
+
+	return &graphql.Schema{Query: query, Mutation: mutation}
+}
+
+// resolverEstudantes trata Query.estudantes (filtro + paginação por limite/offset).
+func resolverEstudantes(ctx context.Context, _ any, args map[string]any) (any, error) {
+	var f estudanteFiltro
+	if v := args["filtro"]; v != nil {
+		if err := graphql.Decodificar(v, &f); err != nil {
+			return nil, graphql.NovoErro("filtro inválido: "+err.Error(), apierr.Validacao, nil)
+		}
+	}
+	if f.Status != "" && !model.StatusValido(f.Status) {
+		return nil, graphql.NovoErro(model.ErrStatusInvalido.Error(), apierr.EstudanteStatusInvalido, nil)
+	}
+	limite, okL := graphql.ArgInt(args, "limite")
+	offset, okO := graphql.ArgInt(args, "offset")
+	if !okL || limite < 1 || limite > graphqlLimiteMaximo || !okO || offset < 0 {
+		return nil, graphql.NovoErro("limite deve estar entre 1 e 200 e offset >= 0", apierr.Validacao, nil)
+	}
+
+	todos, err := sessao(ctx).carregarEstudantes(ctx)
+	if err != nil {
+		return nil, erroGraphQL(ctx, err)
+	}
+	filtrados := filtrarEstudantes(todos, f)
+	itens := []model.Estudante{}
+	if offset < len(filtrados) {
+		itens = filtrados[offset:min(offset+limite, len(filtrados))]
+	}
+	return map[string]any{"total": len(filtrados), "limite": limite, "offset": offset, "itens": itens}, nil
+}
+
+// resolverCriarEstudante trata Mutation.criarEstudante(input).
+func resolverCriarEstudante(ctx context.Context, _ any, args map[string]any) (any, error) {
+	var in model.EstudanteCreateRequest
+	if err := graphql.Decodificar(args["input"], &in); err != nil {
+		return nil, graphql.NovoErro("input inválido: "+err.Error(), apierr.Validacao, nil)
+	}
+	in.Sanitize()
+	if err := in.Validate(); err != nil {
+		return nil, erroGraphQL(ctx, err)
+	}
+	s := sessao(ctx)
+	est, err := s.repo.Criar(ctx, s.uid, in)
+	if err != nil {
+		return nil, erroGraphQL(ctx, err)
+	}
+	registrarAtividade(ctx, s.db, s.acesso, model.AcaoEstudanteCriado, est.ID, est.Nome, nil)
+	publicarEvento(ctx, s.webhooks, s.uid, model.EventoEstudanteCriado, eventoEstudante(est))
+	s.invalidar()
+	return est, nil
+}
+
+// resolverEditarEstudante trata Mutation.editarEstudante(id, versao, input): aplica só os
+// campos informados; com versao, falha (VERSAO_DIVERGENTE) se o registro mudou.
+func resolverEditarEstudante(ctx context.Context, _ any, args map[string]any) (any, error) {
+	id, ok := graphql.ArgInt(args, "id")
+	if !ok || id <= 0 {
+		return nil, graphql.NovoErro("id inválido", apierr.IDInvalido, nil)
+	}
+	var parcial model.EstudanteUpdateRequest
+	if err := graphql.Decodificar(args["input"], &parcial); err != nil {
+		return nil, graphql.NovoErro("input inválido: "+err.Error(), apierr.Validacao, nil)
+	}
+	parcial.Sanitize()
+	if err := parcial.Validate(); err != nil {
+		return nil, erroGraphQL(ctx, err)
+	}
+
+	s := sessao(ctx)
+	est, err := s.repo.Buscar(ctx, id, s.uid)
+	if err != nil {
+		return nil, erroGraphQL(ctx, err)
+	}
+	if v, informada := graphql.ArgInt(args, "versao"); informada && v != est.Versao {
+		return nil, erroGraphQL(ctx, model.ErrVersaoDivergente)
+	}
+	parcial.ApplyTo(&est)
+	in := model.EstudanteCreateRequest{
+		Nome: est.Nome, CPF: est.CPF, Email: est.Email, DataNascimento: est.DataNascimento,
+		Telefone: est.Telefone, FotoURL: est.FotoURL, AnoID: est.AnoID, TurmaID: est.TurmaID,
+	}
+	if err := in.Validate(); err != nil {
+		return nil, erroGraphQL(ctx, err)
+	}
+	// grava sobre a versão lida: uma alteração concorrente entre Buscar e Atualizar vira conflito
+	est.Versao, err = s.repo.Atualizar(ctx, id, s.uid, est.Versao, in)
+	if err != nil {
+		return nil, erroGraphQL(ctx, err)
+	}
+	registrarAtividade(ctx, s.db, s.acesso, model.AcaoEstudanteAtualizado, id, est.Nome, nil)
+	s.invalidar()
+	return est, nil
+}
+
+// erroGraphQL traduz erros de domínio/banco para o código do catálogo apierr.
+func erroGraphQL(ctx context.Context, err error) error {
+	if ev, ok := model.ComoErrosValidacao(err); ok {
+		return graphql.NovoErro("Dados inválidos", apierr.Validacao, ev)
+	}
+	if _, code, msg, ok := mapPQError(err); ok {
+		return graphql.NovoErro(msg, code, nil)
+	}
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return graphql.NovoErro("Estudante não encontrado", apierr.EstudanteNaoEncontrado, nil)
+	case errors.Is(err, model.ErrVersaoDivergente):
+		return graphql.NovoErro(err.Error(), apierr.VersaoDivergente, nil)
+	}
+	logging.De(ctx).Error("graphql: resolver", "erro", err)
+	return graphql.NovoErro("Erro interno", apierr.ErroInterno, nil)
+}
+
+// escalares declara campos lidos direto do pai (tag json), sem argumentos.
+func escalares(nomes ...string) map[string]*graphql.Campo {
+	out := make(map[string]*graphql.Campo, len(nomes))
+	for _, n := range nomes {
+		out[n] = &graphql.Campo{}
+	}
+	return out
+}
+
+// filtrarEstudantes aplica o filtro em memória (lista já escopada ao tenant).
+func filtrarEstudantes(todos []model.Estudante, f estudanteFiltro) []model.Estudante {
+	busca := strings.ToLower(strings.TrimSpace(f.Busca))
+	out := []model.Estudante{}
+	for _, e := range todos {
+		switch {
+		case f.Status != "" && e.Status != f.Status,
+			f.AnoID != 0 && e.AnoID != f.AnoID,
+			f.TurmaID != 0 && e.TurmaID != f.TurmaID,
+			busca != "" && !strings.Contains(strings.ToLower(e.Nome), busca) && !strings.Contains(e.Email, busca):
+			continue
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+func sessao(ctx context.Context) *sessaoGraphQL {
+	return ctx.Value(chaveSessaoGraphQL{}).(*sessaoGraphQL)
+}
+
+// invalidar descarta o cache após uma mutation.
+func (s *sessaoGraphQL) invalidar() {
+	s.estudantes, s.anos, s.responsaveis = nil, nil, nil
+	s.mutou = true
+}
+
+func (s *sessaoGraphQL) carregarEstudantes(ctx context.Context) ([]model.Estudante, error) {
+	if s.estudantes == nil {
+		listar := s.repo.Listar
+		if s.mutou {
+			listar = s.repo.ListarAtual
+		}
+		lista, err := listar(ctx, s.uid, nil)
+		if err != nil {
+			return nil, err
+		}
+		s.estudantes = append([]model.Estudante{}, lista...)
+	}
+	return s.estudantes, nil
+}
+
+func (s *sessaoGraphQL) carregarAnos(ctx context.Context) ([]Ano, error) {
+	if s.anos != nil {
+		return s.anos, nil
+	}
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT a.id, a.nome, COUNT(e.id), a.ordem, a.arquivado, COALESCE(a.periodo_letivo_id, 0)
+		  FROM anos a
+		  LEFT JOIN estudantes e
+		         ON e.ano_id = a.id AND e.usuario_id = a.usuario_id AND e.excluido_em IS NULL
+		 WHERE a.usuario_id = $1 AND a.excluido_em IS NULL
+		 GROUP BY a.id, a.nome, a.ordem, a.arquivado, a.periodo_letivo_id
+		 ORDER BY a.ordem ASC, a.id ASC
+	`, s.uid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	anos := []Ano{}
+	for rows.Next() {
+		var a Ano
+		if err := rows.Scan(&a.ID, &a.Nome, &a.QuantidadeEstudantes, &a.Ordem, &a.Arquivado, &a.PeriodoLetivoID); err != nil {
+			return nil, err
+		}
+		anos = append(anos, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	s.anos = anos
+	return anos, nil
+}
+
+// anoPorID resolve Estudante.ano/turma (nil quando não vinculado).
+func (s *sessaoGraphQL) anoPorID(ctx context.Context, id int) (any, error) {
+	if id == 0 {
+		return nil, nil
+	}
+	anos, err := s.carregarAnos(ctx)
+	if err != nil {
+		return nil, erroGraphQL(ctx, err)
+	}
+	for _, a := range anos {
+		if a.ID == id {
+			return a, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *sessaoGraphQL) carregarResponsaveis(ctx context.Context) (map[int][]model.Responsavel, error) {
+	if s.responsaveis != nil {
+		return s.responsaveis, nil
+	}
+	lista, err := s.repo.ListarResponsaveis(ctx, s.uid)
+	if err != nil {
+		return nil, err
+	}
+	out := map[int][]model.Responsavel{}
+	for _, rsp := range lista {
+		out[rsp.EstudanteID] = append(out[rsp.EstudanteID], rsp)
+	}
+	s.responsaveis = out
+	return out, nil
+}
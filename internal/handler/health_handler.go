@@ -0,0 +1,137 @@
+// ============================================================================
+// 📄 handler/health_handler.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - Probes para orquestradores (Kubernetes):
+//   * GET /livez   → processo vivo (não toca em dependências); sempre 200
+//   * GET /readyz  → pronto para tráfego: banco responde, sem migrations
+//                    pendentes, storage de uploads gravável/acessível e Redis
+//                    (quando CACHE_DRIVER=redis) respondendo
+// - /healthz (texto "ok") continua em cmd/server/main.go por compatibilidade.
+//
+// 🔐 Autenticação/escopo
+// - Rotas públicas, sem X-User-Email; não expõem dados de usuários.
+// - Detalhes de erro saem no JSON (mensagem do driver/sistema de arquivos)
+//   para facilitar o diagnóstico; não incluem credenciais.
+// ============================================================================
+
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"backend/internal/cache"
+	"backend/internal/migrations"
+	"backend/internal/storage"
+)
+
+// timeout de cada verificação do /readyz
+const readyzTimeout = 2 * time.Second
+
+// Estados de uma verificação do /readyz.
+const (
+	checkOK       = "ok"
+	checkFalha    = "falha"
+	checkIgnorado = "ignorado"
+)
+
+var errMigrationsPendentes = errors.New("há migrations pendentes (rode `migrate`)")
+
+// healthCheck é o resultado de uma verificação individual.
+type healthCheck struct {
+	Status    string   `json:"status"`
+	DuracaoMS int64    `json:"duracao_ms"`
+	Erro      string   `json:"erro,omitempty"`
+	Pendentes []string `json:"pendentes,omitempty"`
+}
+
+// healthResposta é o corpo de /readyz e /livez.
+type healthResposta struct {
+	Status string                 `json:"status"`
+	Checks map[string]healthCheck `json:"checks,omitempty"`
+}
+
+// LivezHandler responde 200 enquanto o processo consegue atender requisições.
+func LivezHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, healthResposta{Status: checkOK})
+	}
+}
+
+// ReadyzHandler verifica banco, migrations, storage e cache; 503 se qualquer um falhar.
+//
+// Checks:
+//   - database: ping com timeout
+//   - migrations: nenhuma migration embutida pendente
+//   - uploads: storage.Checker (diretório gravável / bucket acessível)
+//   - cache: cache.Checker (PING no Redis; "ignorado" no cache em memória)
+func ReadyzHandler(db *sql.DB, st storage.Storage, ch cache.Cache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		checks := map[string]healthCheck{
+			"database": executarCheck(r.Context(), func(ctx context.Context, c *healthCheck) error {
+				return db.PingContext(ctx)
+			}),
+			"migrations": executarCheck(r.Context(), func(ctx context.Context, c *healthCheck) error {
+				pend, err := migrations.Pendentes(ctx, db)
+				if err != nil {
+					return err
+				}
+				for _, m := range pend {
+					c.Pendentes = append(c.Pendentes, m.Nome)
+				}
+				if len(pend) > 0 {
+					return errMigrationsPendentes
+				}
+				return nil
+			}),
+			"uploads": executarCheck(r.Context(), func(ctx context.Context, c *healthCheck) error {
+				chk, ok := st.(storage.Checker)
+				if !ok {
+					c.Status = checkIgnorado
+					return nil
+				}
+				return chk.Check(ctx)
+			}),
+			"cache": executarCheck(r.Context(), func(ctx context.Context, c *healthCheck) error {
+				chk, ok := ch.(cache.Checker)
+				if !ok {
+					c.Status = checkIgnorado
+					return nil
+				}
+				return chk.Check(ctx)
+			}),
+		}
+
+		out := healthResposta{Status: checkOK, Checks: checks}
+		status := http.StatusOK
+		for _, c := range checks {
+			if c.Status == checkFalha {
+				out.Status = checkFalha
+				status = http.StatusServiceUnavailable
+			}
+		}
+		writeJSON(w, status, out)
+	}
+}
+
+// executarCheck roda f com timeout e mede a duração.
+func executarCheck(parent context.Context, f func(ctx context.Context, c *healthCheck) error) healthCheck {
+	ctx, cancel := context.WithTimeout(parent, readyzTimeout)
+	defer cancel()
+
+	var c healthCheck
+	inicio := time.Now()
+	err := f(ctx, &c)
+	c.DuracaoMS = time.Since(inicio).Milliseconds()
+	switch {
+	case err != nil:
+		c.Status, c.Erro = checkFalha, err.Error()
+	case c.Status == "":
+		c.Status = checkOK
+	}
+	return c
+}
@@ -0,0 +1,97 @@
+// ============================================================================
+// 📄 handler/historico_login_handler.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - GET /api/usuario/logins → tentativas de login recentes da própria conta
+//   (método senha/google, sucesso ou motivo da recusa, IP, user agent, data).
+//   * ?limite=1–100 (padrão 20); ?antes=<id> pagina (use "proximo" da resposta).
+// - registrarLogin: usado por /login e /login/google para gravar cada tentativa.
+//
+// 🔐 Autenticação/escopo
+// - `X-User-Email` obrigatório; sempre a conta autenticada (numa organização,
+//   cada membro vê só os próprios logins).
+// ============================================================================
+
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strconv"
+
+	"backend/internal/apierr"
+	"backend/internal/logging"
+	"backend/internal/middleware"
+	"backend/internal/model"
+)
+
+// limites do histórico de logins
+const (
+	loginsLimitePadrao = 20
+	loginsLimiteMaximo = 100
+)
+
+// HistoricoLoginsHandler trata GET /api/usuario/logins
+//
+// Regras/erros:
+//   - 405 se método != GET; 401 se não resolver usuário.
+//   - 400 (VALIDACAO) para ?limite fora de 1..100 ou ?antes inválido.
+//   - 200 + {itens, proximo}; proximo = null na última página.
+func HistoricoLoginsHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+		acesso, err := acessoFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+
+		q := r.URL.Query()
+		limite := loginsLimitePadrao
+		if v := q.Get("limite"); v != "" {
+			limite, err = strconv.Atoi(v)
+			if err != nil || limite < 1 || limite > loginsLimiteMaximo {
+				writeAPIError(w, http.StatusBadRequest, apierr.Validacao, "Parâmetro limite inválido (1 a 100)")
+				return
+			}
+		}
+		var antes int64
+		if v := q.Get("antes"); v != "" {
+			antes, err = strconv.ParseInt(v, 10, 64)
+			if err != nil || antes <= 0 {
+				writeAPIError(w, http.StatusBadRequest, apierr.Validacao, "Parâmetro antes inválido")
+				return
+			}
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		out, err := model.ListarLogins(ctx, db, acesso.UsuarioID, antes, limite)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao listar logins")
+			return
+		}
+		writeJSON(w, http.StatusOK, out)
+	}
+}
+
+// registrarLogin grava a tentativa no histórico da conta. A resposta do login não
+// depende disso, então uma falha aqui só é registrada em log.
+func registrarLogin(ctx context.Context, repo model.UserRepository, r *http.Request, usuarioID int, metodo, motivo string) {
+	err := repo.RegistrarLogin(ctx, model.NovoLogin{
+		UsuarioID: usuarioID,
+		Metodo:    metodo,
+		Sucesso:   motivo == "",
+		Motivo:    motivo,
+		IP:        middleware.IPDe(r),
+		UserAgent: r.UserAgent(),
+	})
+	if err != nil {
+		logging.De(ctx).Error("histórico de logins: falha ao registrar", "usuario_id", usuarioID, "metodo", metodo, "erro", err)
+	}
+}
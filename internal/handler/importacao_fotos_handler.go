@@ -0,0 +1,243 @@
+// ============================================================================
+// 📄 handler/importacao_fotos_handler.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - POST /api/estudantes/import-fotos (multipart, campo "arquivo" com um ZIP):
+//   cada imagem do ZIP vira a foto do estudante cujo CPF ou e-mail está no nome
+//   do arquivo (ex.: "12345678909.jpg", "ana@escola.com.png").
+// - Cada foto passa pelo mesmo caminho do POST /api/uploads: antivírus,
+//   imagem.FotoComMiniatura e gravação no storage; depois a foto_url do
+//   estudante é trocada.
+// - Responde o relatório por arquivo (model.ResultadoImportacaoFotos).
+//
+// 🔐 Autenticação/escopo
+// - `X-User-Email` obrigatório; só estudantes não excluídos do tenant são
+//   candidatos. Papel "leitor" é bloqueado pela rota (ExigirEscritaMiddleware).
+//
+// 🧱 Limites
+// - ZIP até maxZipFotos, no máximo maxArquivosZipFotos fotos; cada foto
+//   descompactada até maxUploadSize (entradas maiores viram "invalido", sem
+//   descompactar o resto — protege contra ZIP bomb).
+// - Cada arquivo é independente: um inválido não impede os demais. A foto
+//   anterior do estudante fica órfã no storage e sai na limpeza de uploads.
+// ============================================================================
+
+package handler
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"backend/internal/antivirus"
+	"backend/internal/apierr"
+	"backend/internal/imagem"
+	"backend/internal/logging"
+	"backend/internal/model"
+	"backend/internal/storage"
+)
+
+// limite do ZIP enviado e da quantidade de fotos dentro dele
+const (
+	maxZipFotos         = 100 << 20 // 100 MiB
+	maxArquivosZipFotos = 1000
+)
+
+// ImportarFotosHandler trata POST /api/estudantes/import-fotos.
+//
+// Regras/erros:
+//   - 401 se não resolver usuário; 405 se não for POST.
+//   - 400 se faltar o campo "arquivo" ou ele não for um ZIP válido.
+//   - 413 se o ZIP exceder maxZipFotos.
+//   - 422 (VALIDACAO) se o ZIP não tiver fotos ou tiver mais de maxArquivosZipFotos.
+//   - 200 com o resultado por arquivo (importada | sem_correspondencia | ambiguo |
+//     repetido | invalido | infectado | erro).
+func ImportarFotosHandler(db *sql.DB, repo *model.EstudanteRepo, st storage.Storage, av *antivirus.Verificador) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+		acesso, err := acessoFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+		uid := acesso.TenantID
+
+		r.Body = http.MaxBytesReader(w, r.Body, maxZipFotos+(1<<20))
+		file, hdr, err := r.FormFile("arquivo")
+		if err != nil {
+			var maxErr *http.MaxBytesError
+			if errors.As(err, &maxErr) {
+				writeJSONError(w, http.StatusRequestEntityTooLarge, "Arquivo muito grande")
+				return
+			}
+			writeAPIError(w, http.StatusBadRequest, apierr.ArquivoInvalido, "Campo 'arquivo' ausente ou inválido")
+			return
+		}
+		defer file.Close()
+		if hdr.Size > maxZipFotos {
+			writeJSONError(w, http.StatusRequestEntityTooLarge, "Arquivo muito grande")
+			return
+		}
+		zr, err := zip.NewReader(file, hdr.Size)
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, apierr.ArquivoInvalido, "O arquivo enviado não é um ZIP válido")
+			return
+		}
+
+		var entradas []*zip.File
+		for _, f := range zr.File {
+			if !model.IgnorarArquivoFoto(f.Name) {
+				entradas = append(entradas, f)
+			}
+		}
+		if len(entradas) == 0 || len(entradas) > maxArquivosZipFotos {
+			writeAPIError(w, http.StatusUnprocessableEntity, apierr.Validacao,
+				"O ZIP precisa ter de 1 a "+strconv.Itoa(maxArquivosZipFotos)+" fotos")
+			return
+		}
+
+		ctx := r.Context()
+		existentes, err := repo.ListarAtual(ctx, uid, nil)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao listar estudantes")
+			return
+		}
+
+		out := model.ResultadoImportacaoFotos{Total: len(entradas), Itens: make([]model.ItemImportacaoFoto, 0, len(entradas))}
+		comFoto := make(map[int]bool) // estudantes que já receberam foto deste ZIP
+		for _, f := range entradas {
+			it := importarFoto(ctx, repo, st, av, uid, f, existentes, comFoto)
+			if it.Situacao == model.FotoImportada {
+				out.Importadas++
+				registrarAtividade(ctx, db, acesso, model.AcaoEstudanteAtualizado, it.EstudanteID, it.Nome,
+					map[string]any{"origem": "importacao_fotos", "arquivo": it.Arquivo})
+			} else {
+				out.Ignoradas++
+			}
+			out.Itens = append(out.Itens, it)
+		}
+		writeJSON(w, http.StatusOK, out)
+	}
+}
+
+// ===== helpers =====
+
+// importarFoto localiza o estudante do arquivo, processa a imagem e troca a foto_url.
+func importarFoto(ctx context.Context, repo *model.EstudanteRepo, st storage.Storage, av *antivirus.Verificador,
+	uid int, f *zip.File, existentes []model.Estudante, comFoto map[int]bool) model.ItemImportacaoFoto {
+	it := model.ItemImportacaoFoto{Arquivo: f.Name}
+
+	id, ok := model.IdentificarFoto(f.Name)
+	if !ok {
+		it.Situacao, it.Motivo = model.FotoSemCorrespondencia, "O nome do arquivo não é um CPF nem um e-mail"
+		return it
+	}
+	candidatos := model.CorresponderFoto(id, existentes)
+	switch {
+	case len(candidatos) == 0:
+		it.Situacao, it.Motivo = model.FotoSemCorrespondencia, "Nenhum estudante com este CPF ou e-mail"
+		return it
+	case len(candidatos) > 1:
+		it.Situacao, it.Motivo = model.FotoAmbigua, "Mais de um estudante com este CPF ou e-mail"
+		return it
+	}
+	est := candidatos[0]
+	it.EstudanteID, it.Nome = est.ID, est.Nome
+	if comFoto[est.ID] {
+		it.Situacao, it.Motivo = model.FotoRepetida, "Outro arquivo do ZIP já trouxe a foto deste estudante"
+		return it
+	}
+
+	data, err := lerEntradaZip(f, maxUploadSize)
+	if err != nil {
+		it.Situacao, it.Motivo = model.FotoInvalida, err.Error()
+		return it
+	}
+	if err := av.Verificar(ctx, data); err != nil {
+		if errors.Is(err, antivirus.ErrInfectado) {
+			logging.De(ctx).Warn("importação de fotos: arquivo recusado pelo antivírus", "arquivo", f.Name, "erro", err)
+			it.Situacao, it.Motivo = model.FotoInfectada, "Arquivo recusado pela verificação antivírus"
+			return it
+		}
+		if !av.FalhaAberta() {
+			logging.De(ctx).Error("importação de fotos: antivírus indisponível", "erro", err)
+			it.Situacao, it.Motivo = model.FotoErro, "Verificação antivírus indisponível; tente novamente em instantes"
+			return it
+		}
+		logging.De(ctx).Error("importação de fotos: antivírus indisponível, arquivo aceito sem verificação", "erro", err)
+	}
+	foto, miniatura, err := imagem.FotoComMiniatura(data, maxLadoFoto, ladoMiniatura)
+	switch {
+	case errors.Is(err, imagem.ErrFormato):
+		it.Situacao, it.Motivo = model.FotoInvalida, "Formato de imagem não suportado (use JPEG, PNG ou GIF)"
+		return it
+	case errors.Is(err, imagem.ErrDimensoes), errors.Is(err, imagem.ErrCorrompida):
+		it.Situacao, it.Motivo = model.FotoInvalida, err.Error()
+		return it
+	case err != nil:
+		logging.De(ctx).Error("importação de fotos: falha ao processar imagem", "arquivo", f.Name, "erro", err)
+		it.Situacao, it.Motivo = model.FotoErro, "Erro ao processar imagem"
+		return it
+	}
+
+	key, err := novaChaveUpload(uid, ".jpg")
+	if err != nil {
+		it.Situacao, it.Motivo = model.FotoErro, "Erro ao gerar nome do arquivo"
+		return it
+	}
+	chaveMini := storage.ChaveMiniatura(key)
+	if err := st.Put(ctx, chaveMini, bytes.NewReader(miniatura), "image/jpeg"); err != nil {
+		logging.De(ctx).Error("importação de fotos: falha ao gravar miniatura", "erro", err)
+		it.Situacao, it.Motivo = model.FotoErro, "Erro ao salvar arquivo"
+		return it
+	}
+	if err := st.Put(ctx, key, bytes.NewReader(foto), "image/jpeg"); err != nil {
+		logging.De(ctx).Error("importação de fotos: falha ao gravar arquivo", "erro", err)
+		_ = st.Delete(ctx, chaveMini)
+		it.Situacao, it.Motivo = model.FotoErro, "Erro ao salvar arquivo"
+		return it
+	}
+	url := uploadsPublicPath + key
+	if err := repo.AtualizarFoto(ctx, est.ID, uid, url); err != nil {
+		logging.De(ctx).Error("importação de fotos: falha ao gravar foto_url", "estudante_id", est.ID, "erro", err)
+		_ = st.Delete(ctx, key)
+		_ = st.Delete(ctx, chaveMini)
+		it.Situacao, it.Motivo = model.FotoErro, "Erro ao atualizar estudante"
+		return it
+	}
+	comFoto[est.ID] = true
+	it.Situacao, it.FotoURL = model.FotoImportada, url
+	return it
+}
+
+// errEntradaGrande: a foto descompactada passa de maxUploadSize
+var errEntradaGrande = errors.New("foto muito grande (máximo de 5 MiB)")
+
+// lerEntradaZip descompacta f até limite bytes; maior que isso é errEntradaGrande
+// (o tamanho declarado no ZIP não é confiável, o limite vale para o que sai de fato).
+func lerEntradaZip(f *zip.File, limite int64) ([]byte, error) {
+	if f.UncompressedSize64 > uint64(limite) {
+		return nil, errEntradaGrande
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, errors.New("entrada do ZIP ilegível")
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(io.LimitReader(rc, limite+1))
+	if err != nil {
+		return nil, errors.New("entrada do ZIP corrompida")
+	}
+	if int64(len(data)) > limite {
+		return nil, errEntradaGrande
+	}
+	return data, nil
+}
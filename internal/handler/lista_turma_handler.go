@@ -0,0 +1,152 @@
+// ============================================================================
+// 📄 handler/lista_turma_handler.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - Lista de estudantes para impressão:
+//   * GET /api/anos/{id}/roster.pdf → PDF A4 com os estudantes ativos do ano,
+//     agrupados por turma, fotos em grade (listaturma.PDF)
+//   * ?colunas=telefone,data_nascimento,email acrescenta essas informações
+//     sob o nome de cada estudante (sem o parâmetro, só foto e nome)
+//
+// 🔐 Autenticação/escopo
+// - `X-User-Email` obrigatório; o ano precisa ser do tenant (senão 404).
+// - Fotos só do armazenamento próprio (/uploads), preferindo a miniatura, e só
+//   de estudantes com consentimento de uso de foto (LGPD); sem consentimento,
+//   foto externa ou ilegível sai como quadro com as iniciais.
+// ============================================================================
+
+package handler
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"backend/internal/apierr"
+	"backend/internal/listaturma"
+	"backend/internal/logging"
+	"backend/internal/model"
+	"backend/internal/storage"
+)
+
+// ListaTurmaPDFHandler gera a lista impressa do ano {id}.
+//
+// Regras/erros:
+//   - 405 se método != GET; 401 se não resolver usuário; 400 para id ou colunas inválidos.
+//   - 404 se o ano não for do usuário (ou estiver na lixeira).
+//   - 200 + application/pdf; turmas em ordem alfabética ("Sem turma" por último),
+//     estudantes por nome.
+func ListaTurmaPDFHandler(db *sql.DB, repo *model.EstudanteRepo, st storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+		acesso, err := acessoFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+		id, ok := pathID(r, "id")
+		if !ok {
+			writeAPIError(w, http.StatusBadRequest, apierr.IDInvalido, "ID do ano/turma inválido")
+			return
+		}
+		var colunas []string
+		for _, c := range strings.Split(r.URL.Query().Get("colunas"), ",") {
+			c = strings.ToLower(strings.TrimSpace(c))
+			if c == "" || slices.Contains(colunas, c) {
+				continue
+			}
+			if !slices.Contains(listaturma.Colunas, c) {
+				apierr.Escrever(w, http.StatusBadRequest, apierr.RequisicaoInvalida, fmt.Sprintf("Coluna desconhecida: %q", c),
+					map[string]any{"colunas_permitidas": listaturma.Colunas})
+				return
+			}
+			colunas = append(colunas, c)
+		}
+
+		ctx := r.Context()
+		escola, ano, err := dadosCarteirinha(ctx, db, acesso, id)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao buscar ano")
+			return
+		}
+		if ano == "" {
+			writeAPIError(w, http.StatusNotFound, apierr.AnoNaoEncontrado, "Ano/Turma não encontrado")
+			return
+		}
+		estudantes, err := repo.ListarDoAno(ctx, acesso.TenantID, id, []string{model.StatusAtivo})
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao listar estudantes")
+			return
+		}
+		ids := make([]int, 0, len(estudantes))
+		for _, e := range estudantes {
+			ids = append(ids, e.ID)
+		}
+		fotos, err := model.ComConsentimento(ctx, db, acesso.TenantID, model.FinalidadeFoto, ids)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao verificar consentimentos")
+			return
+		}
+
+		pdf, err := listaturma.PDF(listaturma.Dados{
+			Escola:    escola,
+			Ano:       ano,
+			EmitidaEm: time.Now(),
+			Colunas:   colunas,
+			Turmas:    agruparPorTurma(ctx, st, estudantes, fotos),
+		})
+		if err != nil {
+			logging.De(ctx).Error("lista da turma: falha ao gerar PDF", "ano_id", id, "erro", err)
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao gerar lista")
+			return
+		}
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="lista-ano-%d.pdf"`, id))
+		w.Header().Set("Cache-Control", "private, no-store")
+		_, _ = w.Write(pdf)
+	}
+}
+
+// ===== helpers =====
+
+// agruparPorTurma separa os estudantes (já ordenados por nome) pelo nome da turma, com as
+// fotos carregadas (só dos ids em fotos); "Sem turma" (nome vazio) fica por último.
+func agruparPorTurma(ctx context.Context, st storage.Storage, estudantes []model.Estudante, fotos map[int]bool) []listaturma.Turma {
+	var turmas []listaturma.Turma
+	indice := make(map[string]int)
+	for _, e := range estudantes {
+		i, ok := indice[e.TurmaNome]
+		if !ok {
+			i = len(turmas)
+			indice[e.TurmaNome] = i
+			turmas = append(turmas, listaturma.Turma{Nome: e.TurmaNome})
+		}
+		resp := model.NovaEstudanteResposta(e, time.Now())
+		item := listaturma.Estudante{
+			Nome:           e.Nome,
+			Iniciais:       resp.Iniciais,
+			Telefone:       e.Telefone,
+			DataNascimento: e.DataNascimento,
+			Email:          e.Email,
+		}
+		if fotos[e.ID] {
+			item.Foto = fotoArmazenada(ctx, st, e.FotoURL, true)
+		}
+		turmas[i].Estudantes = append(turmas[i].Estudantes, item)
+	}
+	slices.SortStableFunc(turmas, func(a, b listaturma.Turma) int {
+		if (a.Nome == "") != (b.Nome == "") {
+			return cmp.Compare(b.Nome, a.Nome) // vazio por último
+		}
+		return cmp.Compare(strings.ToLower(a.Nome), strings.ToLower(b.Nome))
+	})
+	return turmas
+}
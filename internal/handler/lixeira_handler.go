@@ -0,0 +1,331 @@
+// ============================================================================
+// 📄 handler/lixeira_handler.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - Lixeira de estudantes e anos (exclusão lógica via excluido_em):
+//   * GET    /api/lixeira                        → itens excluídos (mais recentes primeiro)
+//   * POST   /api/lixeira/{tipo}/{id}/restaurar  → devolve o item (ano: com os estudantes excluídos junto)
+//   * DELETE /api/lixeira/{tipo}/{id}?confirmar= → exclusão definitiva (confirmar = nome do item)
+//   * {tipo} = estudante | ano.
+//
+// 🔐 Autenticação/escopo
+// - `X-User-Email` obrigatório; a lixeira é a do tenant (papel leitor só lista).
+// - Exclusão definitiva apenas para admin (ou usuário sem organização).
+// ============================================================================
+
+package handler
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"backend/internal/apierr"
+	"backend/internal/model"
+)
+
+// limites da listagem da lixeira
+const (
+	lixeiraLimitePadrao = 50
+	lixeiraLimiteMaximo = 200
+)
+
+// ListarLixeiraHandler trata GET /api/lixeira[?tipo=estudante|ano][&limite=N]
+//
+// Regras/erros:
+//   - 405 se método != GET; 401 se não resolver usuário.
+//   - 400 (VALIDACAO) para tipo desconhecido ou limite fora de 1..200.
+//   - 200 + [ItemLixeira] ordenado por excluido_em (mais recente primeiro).
+func ListarLixeiraHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+		acesso, err := acessoFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+
+		q := r.URL.Query()
+		tipo := q.Get("tipo")
+		if tipo != "" && !model.TipoLixeiraValido(tipo) {
+			writeAPIError(w, http.StatusBadRequest, apierr.Validacao, "Parâmetro tipo inválido (estudante ou ano)")
+			return
+		}
+		limite := lixeiraLimitePadrao
+		if v := q.Get("limite"); v != "" {
+			limite, err = strconv.Atoi(v)
+			if err != nil || limite < 1 || limite > lixeiraLimiteMaximo {
+				writeAPIError(w, http.StatusBadRequest, apierr.Validacao, "Parâmetro limite inválido (1 a 200)")
+				return
+			}
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		rows, err := db.QueryContext(ctx, `
+			SELECT tipo, id, nome, excluido_em, autor_id, autor_nome, estudantes, ano_na_lixeira
+			  FROM (
+				SELECT 'estudante' AS tipo, e.id, e.nome, e.excluido_em,
+				       COALESCE(e.excluido_por, 0) AS autor_id, COALESCE(u.nome, '') AS autor_nome,
+				       0 AS estudantes, COALESCE(a.excluido_em IS NOT NULL, FALSE) AS ano_na_lixeira
+				  FROM estudantes e
+				  LEFT JOIN usuarios u ON u.id = e.excluido_por
+				  LEFT JOIN anos a ON a.id = e.ano_id
+				 WHERE e.usuario_id = $1 AND e.excluido_em IS NOT NULL AND $2 IN ('', 'estudante')
+				UNION ALL
+				SELECT 'ano', a.id, a.nome, a.excluido_em,
+				       COALESCE(a.excluido_por, 0), COALESCE(u.nome, ''),
+				       (SELECT COUNT(*) FROM estudantes e
+				         WHERE e.ano_id = a.id AND e.usuario_id = a.usuario_id AND e.excluido_em = a.excluido_em),
+				       FALSE
+				  FROM anos a
+				  LEFT JOIN usuarios u ON u.id = a.excluido_por
+				 WHERE a.usuario_id = $1 AND a.excluido_em IS NOT NULL AND $2 IN ('', 'ano')
+			  ) l
+			 ORDER BY excluido_em DESC, tipo, id DESC
+			 LIMIT $3
+		`, acesso.TenantID, tipo, limite)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao listar lixeira")
+			return
+		}
+		defer rows.Close()
+
+		out := []model.ItemLixeira{}
+		for rows.Next() {
+			var it model.ItemLixeira
+			if err := rows.Scan(&it.Tipo, &it.ID, &it.Nome, &it.ExcluidoEm,
+				&it.ExcluidoPor.ID, &it.ExcluidoPor.Nome, &it.Estudantes, &it.AnoNaLixeira); err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao ler lixeira")
+				return
+			}
+			it.ExcluidoPor.Voce = it.ExcluidoPor.ID != 0 && it.ExcluidoPor.ID == acesso.UsuarioID
+			out = append(out, it)
+		}
+		if err := rows.Err(); err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao ler lixeira")
+			return
+		}
+		writeJSON(w, http.StatusOK, out)
+	}
+}
+
+// RestaurarLixeiraHandler trata POST /api/lixeira/{tipo}/{id}/restaurar
+//
+// Regras/erros:
+//   - 405 se método != POST; 401 se não resolver usuário.
+//   - 400 para tipo desconhecido ou id inválido; 404 se o item não estiver na lixeira do tenant.
+//   - 409 (LIXEIRA_ANO_EXCLUIDO) ao restaurar estudante cujo ano ainda está na lixeira.
+//   - 409 (ESTUDANTE_*_DUPLICADO / ANO_NOME_DUPLICADO) se outro registro ativo já usa o e-mail, CPF ou nome.
+//   - 200 + { tipo, id, nome, estudantes_restaurados } quando restaurado.
+func RestaurarLixeiraHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+		acesso, err := acessoFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+		tipo, id, ok := itemLixeiraDaRota(w, r)
+		if !ok {
+			return
+		}
+		uid := acesso.TenantID
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao iniciar transação")
+			return
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		var (
+			nome        string
+			restaurados int
+		)
+		switch tipo {
+		case model.LixeiraEstudante:
+			var anoNaLixeira bool
+			err = tx.QueryRowContext(ctx, `
+				SELECT e.nome, COALESCE(a.excluido_em IS NOT NULL, FALSE)
+				  FROM estudantes e
+				  LEFT JOIN anos a ON a.id = e.ano_id
+				 WHERE e.id=$1 AND e.usuario_id=$2 AND e.excluido_em IS NOT NULL
+				   FOR UPDATE OF e
+			`, id, uid).Scan(&nome, &anoNaLixeira)
+			if err == nil && anoNaLixeira {
+				writeAPIError(w, http.StatusConflict, apierr.LixeiraAnoExcluido, "O ano/turma do estudante está na lixeira; restaure-o primeiro")
+				return
+			}
+			if err == nil {
+				_, err = tx.ExecContext(ctx,
+					`UPDATE estudantes SET excluido_em=NULL, excluido_por=NULL WHERE id=$1`, id)
+			}
+
+		case model.LixeiraAno:
+			var excluidoEm time.Time
+			err = tx.QueryRowContext(ctx, `
+				SELECT nome, excluido_em FROM anos
+				 WHERE id=$1 AND usuario_id=$2 AND excluido_em IS NOT NULL
+				   FOR UPDATE
+			`, id, uid).Scan(&nome, &excluidoEm)
+			if err == nil {
+				_, err = tx.ExecContext(ctx,
+					`UPDATE anos SET excluido_em=NULL, excluido_por=NULL WHERE id=$1`, id)
+			}
+			if err == nil {
+				// estudantes que foram para a lixeira junto com o ano (mesma transação)
+				var res sql.Result
+				res, err = tx.ExecContext(ctx, `
+					UPDATE estudantes SET excluido_em=NULL, excluido_por=NULL
+					 WHERE ano_id=$1 AND usuario_id=$2 AND excluido_em=$3
+				`, id, uid, excluidoEm)
+				if err == nil {
+					n, _ := res.RowsAffected()
+					restaurados = int(n)
+				}
+			}
+		}
+		if err == sql.ErrNoRows {
+			writeAPIError(w, http.StatusNotFound, apierr.ItemLixeiraNaoEncontrado, "Item não encontrado na lixeira")
+			return
+		}
+		if status, code, msg, ok := mapPQError(err); ok {
+			writeAPIError(w, status, code, msg)
+			return
+		}
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao restaurar item")
+			return
+		}
+		if err := tx.Commit(); err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao confirmar restauração")
+			return
+		}
+
+		if tipo == model.LixeiraAno {
+			registrarAtividade(ctx, db, acesso, model.AcaoAnoRestaurado, id, nome, map[string]any{"estudantes_restaurados": restaurados})
+		} else {
+			registrarAtividade(ctx, db, acesso, model.AcaoEstudanteRestaurado, id, nome, nil)
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"tipo": tipo, "id": id, "nome": nome, "estudantes_restaurados": restaurados})
+	}
+}
+
+// ExpurgarLixeiraHandler trata DELETE /api/lixeira/{tipo}/{id}?confirmar=<nome do item>
+//
+// Regras/erros:
+//   - 405 se método != DELETE; 401 se não resolver usuário; 403 se não for admin.
+//   - 400 para tipo desconhecido ou id inválido; 404 se o item não estiver na lixeira do tenant.
+//   - 400 (CONFIRMACAO_INVALIDA) se ?confirmar não trouxer o nome do item.
+//   - 409 (ANO_COM_ESTUDANTES) se ainda houver estudantes ativos no ano.
+//   - 204 quando excluído definitivamente (ano: leva os estudantes e avaliações dele).
+func ExpurgarLixeiraHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+		acesso, err := acessoFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+		if !acesso.Admin() {
+			writeAPIError(w, http.StatusForbidden, apierr.SemPermissao, "Apenas administradores excluem definitivamente")
+			return
+		}
+		tipo, id, ok := itemLixeiraDaRota(w, r)
+		if !ok {
+			return
+		}
+		uid := acesso.TenantID
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao iniciar transação")
+			return
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		tabela, acao := "estudantes", model.AcaoEstudanteExpurgado
+		if tipo == model.LixeiraAno {
+			tabela, acao = "anos", model.AcaoAnoExpurgado
+		}
+		var nome string
+		err = tx.QueryRowContext(ctx,
+			`SELECT nome FROM `+tabela+` WHERE id=$1 AND usuario_id=$2 AND excluido_em IS NOT NULL FOR UPDATE`,
+			id, uid,
+		).Scan(&nome)
+		if err == sql.ErrNoRows {
+			writeAPIError(w, http.StatusNotFound, apierr.ItemLixeiraNaoEncontrado, "Item não encontrado na lixeira")
+			return
+		}
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao buscar item")
+			return
+		}
+		if !model.ConfirmacaoLixeira(nome, r.URL.Query().Get("confirmar")) {
+			apierr.Escrever(w, http.StatusBadRequest, apierr.ConfirmacaoInvalida,
+				"Confirme a exclusão definitiva informando o nome do item em ?confirmar=", map[string]string{"nome": nome})
+			return
+		}
+
+		if tipo == model.LixeiraAno {
+			// a FK apaga em cascata os estudantes do ano: só os que estão na lixeira podem ir junto
+			var ativos int
+			if err := tx.QueryRowContext(ctx,
+				`SELECT COUNT(*) FROM estudantes WHERE ano_id=$1 AND usuario_id=$2 AND excluido_em IS NULL`,
+				id, uid,
+			).Scan(&ativos); err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao contar estudantes vinculados")
+				return
+			}
+			if ativos > 0 {
+				apierr.Escrever(w, http.StatusConflict, apierr.AnoComEstudantes,
+					"Ano/Turma possui estudantes ativos; mova-os antes da exclusão definitiva",
+					map[string]int{"quantidade_estudantes": ativos})
+				return
+			}
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM `+tabela+` WHERE id=$1 AND usuario_id=$2`, id, uid); err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao excluir definitivamente")
+			return
+		}
+		if err := tx.Commit(); err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao confirmar exclusão")
+			return
+		}
+		registrarAtividade(ctx, db, acesso, acao, id, nome, nil)
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// itemLixeiraDaRota lê {tipo} e {id}; em erro já responde 400 e devolve ok=false.
+func itemLixeiraDaRota(w http.ResponseWriter, r *http.Request) (tipo string, id int, ok bool) {
+	tipo = r.PathValue("tipo")
+	if !model.TipoLixeiraValido(tipo) {
+		writeAPIError(w, http.StatusBadRequest, apierr.Validacao, "Tipo inválido (estudante ou ano)")
+		return "", 0, false
+	}
+	if id, ok = pathID(r, "id"); !ok {
+		writeAPIError(w, http.StatusBadRequest, apierr.IDInvalido, "ID inválido")
+		return "", 0, false
+	}
+	return tipo, id, true
+}
@@ -0,0 +1,340 @@
+// ============================================================================
+// 📄 handler/matricula_handler.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - Movimentação de matrícula do estudante entre anos/turmas:
+//   * POST /api/estudantes/{id}/transferir → muda ano/turma e registra no histórico
+//   * GET  /api/estudantes/{id}/matriculas → histórico de movimentações
+//   * POST /api/anos/{id}/promover         → move a turma inteira (com dry-run)
+// - Histórico em `matriculas_historico` (de/para ano+turma, data, motivo).
+//
+// 🔐 Autenticação/escopo
+// - `X-User-Email` obrigatório; estudante e anos de origem/destino filtrados por usuario_id.
+// ============================================================================
+
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+
+	"backend/internal/apierr"
+	"backend/internal/model"
+)
+
+// registrarMovimento grava uma linha em matriculas_historico dentro da transação.
+func registrarMovimento(ctx context.Context, tx *sql.Tx, uid int, m model.MovimentoMatricula) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO matriculas_historico
+		       (estudante_id, usuario_id, de_ano_id, de_turma_id, para_ano_id, para_turma_id, data, motivo)
+		VALUES ($1, $2, NULLIF($3, 0), $4, $5, $6, $7, $8)
+	`, m.EstudanteID, uid, m.DeAnoID, m.DeTurmaID, m.ParaAnoID, m.ParaTurmaID, m.Data, m.Motivo)
+	return err
+}
+
+// TransferirEstudanteHandler trata POST /api/estudantes/{id}/transferir.
+//
+// Regras/erros:
+//   - 401 se não resolver usuário; 400 se id/payload inválido.
+//   - 404 se estudante ou ano de destino não pertencerem ao usuário.
+//   - 409 se o destino for o mesmo ano/turma atual.
+func TransferirEstudanteHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+		uid, err := usuarioIDFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+		estID, ok := pathID(r, "id")
+		if !ok {
+			writeAPIError(w, http.StatusBadRequest, apierr.IDInvalido, "ID do estudante inválido")
+			return
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		transferirEstudante(ctx, w, r, db, estID, uid)
+	}
+}
+
+// MatriculasEstudanteHandler trata GET /api/estudantes/{id}/matriculas (histórico de movimentos).
+func MatriculasEstudanteHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+		uid, err := usuarioIDFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+		estID, ok := pathID(r, "id")
+		if !ok {
+			writeAPIError(w, http.StatusBadRequest, apierr.IDInvalido, "ID do estudante inválido")
+			return
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		historicoMatriculas(ctx, w, db, estID, uid)
+	}
+}
+
+// transferirEstudante move o estudante para outro ano/turma e registra o movimento.
+func transferirEstudante(ctx context.Context, w http.ResponseWriter, r *http.Request, db *sql.DB, estID, uid int) {
+	var in model.TransferenciaRequest
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	in.Sanitize()
+	if err := in.Validate(); err != nil {
+		writeValidationError(w, err)
+		return
+	}
+
+	ok, err := anoDoUsuario(ctx, db, in.AnoID, uid)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao verificar ano/turma")
+		return
+	}
+	if !ok {
+		writeAPIError(w, http.StatusNotFound, apierr.AnoNaoEncontrado, "Ano/Turma de destino não encontrado")
+		return
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao iniciar transação")
+		return
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	mov := model.MovimentoMatricula{
+		EstudanteID: estID,
+		ParaAnoID:   in.AnoID,
+		ParaTurmaID: in.TurmaID,
+		Data:        in.Data,
+		Motivo:      in.Motivo,
+	}
+	err = tx.QueryRowContext(ctx, `
+		SELECT COALESCE(ano_id, 0), COALESCE(turma_id, 0)
+		  FROM estudantes
+		 WHERE id=$1 AND usuario_id=$2 AND excluido_em IS NULL
+		 FOR UPDATE
+	`, estID, uid).Scan(&mov.DeAnoID, &mov.DeTurmaID)
+	if err == sql.ErrNoRows {
+		writeAPIError(w, http.StatusNotFound, apierr.EstudanteNaoEncontrado, "Estudante não encontrado")
+		return
+	}
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao buscar estudante")
+		return
+	}
+	if mov.DeAnoID == mov.ParaAnoID && mov.DeTurmaID == mov.ParaTurmaID {
+		writeAPIError(w, http.StatusConflict, apierr.TransferenciaMesmaTurma, model.ErrTransferenciaMesmaTurma.Error())
+		return
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE estudantes SET ano_id=$1, turma_id=$2 WHERE id=$3`,
+		in.AnoID, in.TurmaID, estID,
+	); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao transferir estudante")
+		return
+	}
+	if err := registrarMovimento(ctx, tx, uid, mov); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao registrar histórico")
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao confirmar transferência")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, mov)
+}
+
+// historicoMatriculas lista as movimentações do estudante (mais recentes primeiro).
+func historicoMatriculas(ctx context.Context, w http.ResponseWriter, db *sql.DB, estID, uid int) {
+	ok, err := estudanteDoUsuario(ctx, db, estID, uid)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao verificar estudante")
+		return
+	}
+	if !ok {
+		writeAPIError(w, http.StatusNotFound, apierr.EstudanteNaoEncontrado, "Estudante não encontrado")
+		return
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT id, estudante_id, COALESCE(de_ano_id, 0), de_turma_id, COALESCE(para_ano_id, 0), para_turma_id,
+		       to_char(data, 'YYYY-MM-DD'), motivo
+		  FROM matriculas_historico
+		 WHERE estudante_id=$1 AND usuario_id=$2
+		 ORDER BY data DESC, id DESC
+	`, estID, uid)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao buscar histórico")
+		return
+	}
+	defer rows.Close()
+
+	out := []model.MovimentoMatricula{}
+	for rows.Next() {
+		var m model.MovimentoMatricula
+		if err := rows.Scan(&m.ID, &m.EstudanteID, &m.DeAnoID, &m.DeTurmaID, &m.ParaAnoID, &m.ParaTurmaID, &m.Data, &m.Motivo); err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao ler histórico")
+			return
+		}
+		out = append(out, m)
+	}
+	if err := rows.Err(); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao iterar histórico")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, out)
+}
+
+// PromoverAnoHandler trata POST /api/anos/{id}/promover.
+// Body: { para_ano_id, para_turma_id?, data?, motivo?, dry_run? } (também aceita ?dry_run=true).
+// Retorna { de_ano_id, para_ano_id, para_turma_id, dry_run, quantidade, estudantes: [{id, nome}] }.
+//
+// Regras/erros:
+//   - 405 se método != POST; 401 se não resolver usuário; 400 se payload inválido.
+//   - 404 se ano de origem ou destino não pertencer ao usuário.
+//   - 409 se origem e destino forem o mesmo ano sem turma de destino.
+func PromoverAnoHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+
+		uid, err := usuarioIDFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+
+		deAno, ok := pathID(r, "id")
+		if !ok {
+			writeAPIError(w, http.StatusBadRequest, apierr.IDInvalido, "ID do ano/turma inválido")
+			return
+		}
+
+		var in model.PromocaoRequest
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			writeDecodeError(w, err)
+			return
+		}
+		in.Sanitize()
+		if err := in.Validate(); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+		if r.URL.Query().Get("dry_run") == "true" {
+			in.DryRun = true
+		}
+		if deAno == in.ParaAnoID && in.ParaTurmaID == 0 {
+			writeAPIError(w, http.StatusConflict, apierr.TransferenciaMesmaTurma, "Ano de destino igual ao de origem")
+			return
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		for _, id := range []int{deAno, in.ParaAnoID} {
+			ok, err := anoDoUsuario(ctx, db, id, uid)
+			if err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao verificar ano/turma")
+				return
+			}
+			if !ok {
+				writeAPIError(w, http.StatusNotFound, apierr.AnoNaoEncontrado, "Ano/Turma não encontrado")
+				return
+			}
+		}
+
+		type afetado struct {
+			ID      int    `json:"id"`
+			Nome    string `json:"nome"`
+			turmaID int
+		}
+		var afetados []afetado
+		err = model.ComTransacao(ctx, db, func(tx *sql.Tx) error {
+			afetados = []afetado{}
+			rows, err := tx.QueryContext(ctx, `
+				SELECT id, nome, COALESCE(turma_id, 0)
+				  FROM estudantes
+				 WHERE ano_id=$1 AND usuario_id=$2 AND excluido_em IS NULL AND status=$3
+				 ORDER BY nome ASC
+				 FOR UPDATE
+			`, deAno, uid, model.StatusAtivo)
+			if err != nil {
+				return err
+			}
+			defer rows.Close()
+			for rows.Next() {
+				var a afetado
+				if err := rows.Scan(&a.ID, &a.Nome, &a.turmaID); err != nil {
+					return err
+				}
+				afetados = append(afetados, a)
+			}
+			if err := rows.Err(); err != nil {
+				return err
+			}
+			rows.Close()
+
+			// dry_run só lista: nada é gravado e o commit não altera o banco
+			if in.DryRun {
+				return nil
+			}
+			for _, a := range afetados {
+				if _, err := tx.ExecContext(ctx,
+					`UPDATE estudantes SET ano_id=$1, turma_id=$2 WHERE id=$3`,
+					in.ParaAnoID, in.ParaTurmaID, a.ID,
+				); err != nil {
+					return err
+				}
+				mov := model.MovimentoMatricula{
+					EstudanteID: a.ID,
+					DeAnoID:     deAno,
+					DeTurmaID:   a.turmaID,
+					ParaAnoID:   in.ParaAnoID,
+					ParaTurmaID: in.ParaTurmaID,
+					Data:        in.Data,
+					Motivo:      in.Motivo,
+				}
+				if err := registrarMovimento(ctx, tx, uid, mov); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			escreverFalhaTx(w, err, "Erro ao promover estudantes")
+			return
+		}
+
+		writeJSON(w, http.StatusOK, map[string]any{
+			"de_ano_id":     deAno,
+			"para_ano_id":   in.ParaAnoID,
+			"para_turma_id": in.ParaTurmaID,
+			"dry_run":       in.DryRun,
+			"quantidade":    len(afetados),
+			"estudantes":    afetados,
+		})
+	}
+}
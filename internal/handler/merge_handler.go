@@ -0,0 +1,157 @@
+// ============================================================================
+// 📄 handler/merge_handler.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - POST /api/estudantes/merge → mescla um estudante secundário no principal.
+//   * Em uma transação: move presenças, notas, documentos, responsáveis,
+//     consentimentos e os históricos de matrícula e de status do secundário
+//     para o principal e marca o secundário como excluído (soft delete, com
+//     excluido_por: aparece na lixeira com o autor).
+//   * Conflitos (mesmo dia de chamada / mesma avaliação): prevalece o principal.
+//   * Consentimento ativo do secundário que repete um ativo do principal
+//     (mesmo responsável e finalidade) é revogado antes de mover: a linha
+//     fica como histórico, sem violar consentimentos_ativo_unico.
+//   * Telefone/foto vazios no principal herdam os valores do secundário.
+//   * Depois do commit, o feed registra estudante.removido do secundário
+//     (dados.mesclado_em = principal).
+//
+// 🔐 Autenticação/escopo
+// - `X-User-Email` obrigatório; ambos os estudantes precisam ser do usuário.
+// ============================================================================
+
+package handler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+
+	"backend/internal/apierr"
+	"backend/internal/model"
+)
+
+// MesclarEstudantesHandler trata POST /api/estudantes/merge.
+// Body: { "principal_id": 1, "secundario_id": 2 }
+// Retorna { principal: Estudante, movidos: {presencas, notas, ..., estudante_status_historico} }.
+//
+// Regras/erros:
+//   - 405 se método != POST; 401 se não resolver usuário; 400 se payload inválido.
+//   - 404 se algum dos estudantes não existir/pertencer ao usuário (ou já estiver excluído).
+//   - 409 (CONFLITO) se outra operação concorrente persistir após as repetições da transação.
+func MesclarEstudantesHandler(db *sql.DB, repo *model.EstudanteRepo) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+
+		acesso, err := acessoFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+		uid := acesso.TenantID
+
+		var in model.MesclarEstudantesRequest
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			writeDecodeError(w, err)
+			return
+		}
+		if err := in.Validate(); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+		p, s := in.PrincipalID, in.SecundarioID
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		var (
+			movidos      = map[string]int64{}
+			nomeMesclado string
+		)
+		err = model.ComTransacao(ctx, db, func(tx *sql.Tx) error {
+			// Trava os dois registros (evita mescla concorrente do mesmo par)
+			var encontrados int
+			if err := tx.QueryRowContext(ctx, `
+				SELECT COUNT(*) FROM (
+					SELECT id FROM estudantes
+					 WHERE id IN ($1, $2) AND usuario_id=$3 AND excluido_em IS NULL
+					 FOR UPDATE
+				) t
+			`, p, s, uid).Scan(&encontrados); err != nil {
+				return err
+			}
+			if encontrados != 2 {
+				return falhar(http.StatusNotFound, apierr.EstudanteNaoEncontrado, "Estudante não encontrado")
+			}
+
+			// Remove do secundário o que colidiria com as chaves únicas do principal
+			conflitos := []string{
+				`DELETE FROM presencas WHERE estudante_id=$2
+				   AND data IN (SELECT data FROM presencas WHERE estudante_id=$1)`,
+				`DELETE FROM notas WHERE estudante_id=$2
+				   AND avaliacao_id IN (SELECT avaliacao_id FROM notas WHERE estudante_id=$1)`,
+			}
+			for _, q := range conflitos {
+				if _, err := tx.ExecContext(ctx, q, p, s); err != nil {
+					return err
+				}
+			}
+			// Consentimento é prova (LGPD): o repetido é revogado, não apagado
+			if _, err := tx.ExecContext(ctx, `
+				UPDATE consentimentos AS sec
+				   SET revogado_em = NOW(), revogado_por = NULLIF($3, 0)
+				 WHERE sec.estudante_id=$2 AND sec.revogado_em IS NULL
+				   AND EXISTS (
+					SELECT 1 FROM consentimentos pr
+					 WHERE pr.estudante_id=$1 AND pr.revogado_em IS NULL
+					   AND pr.finalidade = sec.finalidade
+					   AND COALESCE(pr.responsavel_id, 0) = COALESCE(sec.responsavel_id, 0)
+				   )
+			`, p, s, acesso.UsuarioID); err != nil {
+				return err
+			}
+
+			tabelas := []string{"presencas", "notas", "documentos", "responsaveis", "consentimentos",
+				"matriculas_historico", "estudante_status_historico"}
+			for _, tabela := range tabelas {
+				res, err := tx.ExecContext(ctx,
+					`UPDATE `+tabela+` SET estudante_id=$1 WHERE estudante_id=$2`, p, s)
+				if err != nil {
+					return err
+				}
+				movidos[tabela], _ = res.RowsAffected()
+			}
+
+			// telefone cifrado é copiado como está (mesma chave/formato)
+			if _, err := tx.ExecContext(ctx, `
+				UPDATE estudantes AS pr
+				   SET telefone = COALESCE(NULLIF(pr.telefone, ''), sec.telefone),
+				       foto_url = COALESCE(NULLIF(pr.foto_url, ''), sec.foto_url)
+				  FROM estudantes AS sec
+				 WHERE pr.id=$1 AND sec.id=$2
+			`, p, s); err != nil {
+				return err
+			}
+			return tx.QueryRowContext(ctx,
+				`UPDATE estudantes SET excluido_em=NOW(), excluido_por=NULLIF($2, 0) WHERE id=$1 RETURNING nome`,
+				s, acesso.UsuarioID,
+			).Scan(&nomeMesclado)
+		})
+		if err != nil {
+			escreverFalhaTx(w, err, "Erro ao mesclar estudantes")
+			return
+		}
+		registrarAtividade(ctx, db, acesso, model.AcaoEstudanteRemovido, s, nomeMesclado,
+			map[string]any{"mesclado_em": p})
+
+		out, err := repo.Buscar(ctx, p, uid)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao buscar estudante")
+			return
+		}
+
+		writeJSON(w, http.StatusOK, map[string]any{"principal": out, "movidos": movidos})
+	}
+}
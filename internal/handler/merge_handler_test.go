@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"backend/internal/model"
+)
+
+// Consentimento ativo do secundário que repete um do principal é revogado antes da mudança
+// de estudante_id; sem isso o UPDATE violaria consentimentos_ativo_unico.
+func TestMesclarMoveConsentimentos(t *testing.T) {
+	db := bancoDeTeste(t,
+		resultadoRoteiro{trecho: "FOR UPDATE", colunas: []string{"count"}, linhas: [][]driver.Value{{int64(2)}}},
+		resultadoRoteiro{trecho: "DELETE FROM"},
+		resultadoRoteiro{trecho: "SET revogado_em", linhas: [][]driver.Value{{}}},
+		resultadoRoteiro{trecho: "SET estudante_id=$1", linhas: [][]driver.Value{{}, {}}},
+		resultadoRoteiro{trecho: "SET telefone"},
+		resultadoRoteiro{trecho: "SET excluido_em", colunas: []string{"nome"}, linhas: [][]driver.Value{{"Ana S."}}},
+		resultadoRoteiro{trecho: "FROM estudantes e LEFT JOIN anos a",
+			colunas: []string{"id", "nome", "cpf", "email", "data_nascimento", "telefone", "foto_url",
+				"ano_id", "turma_id", "status", "versao", "ano", "turma"},
+			linhas: [][]driver.Value{{int64(1), "Ana Souza", "", "", "", "", "", int64(3), int64(0), "ativo", int64(2), "8º ano", ""}}},
+		resultadoRoteiro{trecho: "INSERT INTO auditoria", linhas: [][]driver.Value{{}}},
+	)
+	escritas := escritasDeTeste(t)
+
+	req := comAcesso(httptest.NewRequest(http.MethodPost, "/api/estudantes/merge",
+		strings.NewReader(`{"principal_id": 1, "secundario_id": 2}`)))
+	rec := httptest.NewRecorder()
+	MesclarEstudantesHandler(db, model.NewEstudanteRepo(db, nil)).ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, corpo = %s", rec.Code, rec.Body)
+	}
+
+	var out struct {
+		Movidos map[string]int64 `json:"movidos"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Movidos["consentimentos"] != 2 {
+		t.Errorf("movidos = %v, esperado consentimentos: 2", out.Movidos)
+	}
+
+	revogacao := slices.IndexFunc(*escritas, func(e escritaRoteiro) bool { return strings.Contains(e.query, "SET revogado_em") })
+	mudanca := slices.IndexFunc(*escritas, func(e escritaRoteiro) bool {
+		return strings.Contains(e.query, "UPDATE consentimentos SET estudante_id")
+	})
+	if revogacao < 0 || mudanca < 0 || revogacao > mudanca {
+		t.Fatalf("revogação (%d) deve vir antes de mover os consentimentos (%d)", revogacao, mudanca)
+	}
+	// principal, secundário, quem revogou
+	if args := (*escritas)[revogacao].args; args[0] != 1 || args[1] != 2 || args[2] != 7 {
+		t.Errorf("revogação com argumentos inesperados: %v", args)
+	}
+}
+
+// Os históricos acompanham o principal; o secundário vai para a lixeira com o autor e entra no feed.
+func TestMesclarMoveHistoricosEAudita(t *testing.T) {
+	db := bancoDeTeste(t,
+		resultadoRoteiro{trecho: "FOR UPDATE", colunas: []string{"count"}, linhas: [][]driver.Value{{int64(2)}}},
+		resultadoRoteiro{trecho: "DELETE FROM"},
+		resultadoRoteiro{trecho: "SET revogado_em"},
+		resultadoRoteiro{trecho: "SET estudante_id=$1", linhas: [][]driver.Value{{}}},
+		resultadoRoteiro{trecho: "SET telefone"},
+		resultadoRoteiro{trecho: "SET excluido_em", colunas: []string{"nome"}, linhas: [][]driver.Value{{"Ana S."}}},
+		resultadoRoteiro{trecho: "FROM estudantes e LEFT JOIN anos a",
+			colunas: []string{"id", "nome", "cpf", "email", "data_nascimento", "telefone", "foto_url",
+				"ano_id", "turma_id", "status", "versao", "ano", "turma"},
+			linhas: [][]driver.Value{{int64(1), "Ana Souza", "", "", "", "", "", int64(3), int64(0), "ativo", int64(2), "8º ano", ""}}},
+		resultadoRoteiro{trecho: "INSERT INTO auditoria", linhas: [][]driver.Value{{}}},
+	)
+	escritas := escritasDeTeste(t)
+
+	req := comAcesso(httptest.NewRequest(http.MethodPost, "/api/estudantes/merge",
+		strings.NewReader(`{"principal_id": 1, "secundario_id": 2}`)))
+	rec := httptest.NewRecorder()
+	MesclarEstudantesHandler(db, model.NewEstudanteRepo(db, nil)).ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, corpo = %s", rec.Code, rec.Body)
+	}
+
+	for _, tabela := range []string{"matriculas_historico", "estudante_status_historico"} {
+		if !slices.ContainsFunc(*escritas, func(e escritaRoteiro) bool {
+			return strings.Contains(e.query, "UPDATE "+tabela+" SET estudante_id")
+		}) {
+			t.Errorf("%s não foi movido para o principal", tabela)
+		}
+		if !strings.Contains(rec.Body.String(), `"`+tabela+`":1`) {
+			t.Errorf("movidos sem %s: %s", tabela, rec.Body)
+		}
+	}
+
+	i := slices.IndexFunc(*escritas, func(e escritaRoteiro) bool { return strings.Contains(e.query, "INSERT INTO auditoria") })
+	if i < 0 {
+		t.Fatalf("mescla sem auditoria: %+v", *escritas)
+	}
+	// usuario_id, autor_id, acao, entidade, entidade_id, resumo, dados, personificado_por
+	args := (*escritas)[i].args
+	if args[1] != 7 || args[2] != model.AcaoEstudanteRemovido || args[4] != 2 || args[5] != "Ana S." {
+		t.Errorf("auditoria com argumentos inesperados: %v", args)
+	}
+	if args[6] != `{"mesclado_em":1}` {
+		t.Errorf("dados = %v", args[6])
+	}
+}
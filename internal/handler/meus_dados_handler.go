@@ -0,0 +1,93 @@
+// ============================================================================
+// 📄 handler/meus_dados_handler.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - Portabilidade de dados (LGPD): exportação assíncrona em ZIP.
+//   * GET /api/meus-dados/export       → inicia (ou reaproveita) a exportação; 202
+//   * GET /api/meus-dados/export/{id}  → status; quando "pronto", traz download_url
+//
+// 🔐 Autenticação/escopo
+// - `X-User-Email` obrigatório; cada usuário só enxerga as próprias exportações.
+// - A geração roda em jobs.Exportacoes (estado na tabela exportacoes);
+//   download_url é uma URL assinada do storage (válida por signedURLTTL),
+//   pedida novamente a cada consulta. Sem ela, /uploads/{key} do ZIP só
+//   responde ao próprio solicitante (nem o dono da organização).
+// ============================================================================
+
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"backend/internal/apierr"
+	"backend/internal/jobs"
+)
+
+// exportacaoResposta é o JSON devolvido pelas rotas de exportação.
+type exportacaoResposta struct {
+	jobs.Exportacao
+	StatusURL   string `json:"status_url"`
+	DownloadURL string `json:"download_url,omitempty"`
+}
+
+// ExportarMeusDadosHandler despacha /api/meus-dados/export[/{id}].
+//
+// Regras/erros:
+//   - 401 se não resolver usuário; 405 para métodos diferentes de GET.
+//   - 404 se a exportação não existir, for de outro usuário ou já tiver expirado.
+//   - 500 se o banco ou o storage falharem; 503 ao iniciar durante o desligamento.
+func ExportarMeusDadosHandler(db *sql.DB, exp *jobs.Exportacoes) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+		acesso, err := acessoFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		id := r.PathValue("id")
+		if id == "" {
+			x, err := exp.Iniciar(ctx, acesso.UsuarioID, acesso.TenantID)
+			if errors.Is(err, jobs.ErrExportacoesEncerradas) {
+				writeAPIError(w, http.StatusServiceUnavailable, apierr.Indisponivel, "Servidor reiniciando; tente novamente em instantes")
+				return
+			}
+			if err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao iniciar exportação")
+				return
+			}
+			writeJSON(w, http.StatusAccepted, exportacaoResposta{
+				Exportacao: x,
+				StatusURL:  "/api/meus-dados/export/" + x.ID,
+			})
+			return
+		}
+
+		x, key, err := exp.Buscar(ctx, id, acesso.UsuarioID)
+		if err == sql.ErrNoRows {
+			writeAPIError(w, http.StatusNotFound, apierr.ExportacaoNaoEncontrada, "Exportação não encontrada")
+			return
+		}
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao buscar exportação")
+			return
+		}
+		out := exportacaoResposta{Exportacao: x, StatusURL: "/api/meus-dados/export/" + x.ID}
+		if x.Status == jobs.ExportacaoPronta {
+			out.DownloadURL, err = exp.Storage.SignedURL(ctx, key, signedURLTTL)
+			if err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao gerar link de download")
+				return
+			}
+		}
+		writeJSON(w, http.StatusOK, out)
+	}
+}
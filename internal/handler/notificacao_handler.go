@@ -0,0 +1,169 @@
+// ============================================================================
+// 📄 handler/notificacao_handler.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - Notificações in-app do usuário autenticado (sino do frontend):
+//   * GET /api/notificacoes            → mais recentes primeiro + contador de não lidas
+//                                        (?nao_lidas=true, ?limite=)
+//   * PUT /api/notificacoes/{id}/lida  → marca como lida (idempotente)
+// - Os registros são gravados pelos produtores via model.CriarNotificacao
+//   (exportação pronta, aniversários, importações).
+//
+// 🔐 Autenticação/escopo
+// - `X-User-Email` obrigatório; cada conta vê apenas as próprias notificações
+//   (membros de uma organização não compartilham o sino do dono).
+// ============================================================================
+
+package handler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"backend/internal/apierr"
+	"backend/internal/model"
+)
+
+// limites da listagem de notificações
+const (
+	notificacoesLimitePadrao = 30
+	notificacoesLimiteMaximo = 100
+)
+
+// ListarNotificacoesHandler trata GET /api/notificacoes
+//
+// Regras/erros:
+//   - 401 se não resolver usuário.
+//   - 400 para ?nao_lidas diferente de true/false ou ?limite fora de 1..100.
+//   - 200 + {nao_lidas, itens}; nao_lidas conta todas as não lidas, não só as listadas.
+func ListarNotificacoesHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+		acesso, err := acessoFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+
+		apenasNaoLidas := false
+		if v := r.URL.Query().Get("nao_lidas"); v != "" {
+			if apenasNaoLidas, err = strconv.ParseBool(v); err != nil {
+				writeJSONError(w, http.StatusBadRequest, "nao_lidas inválido (true ou false)")
+				return
+			}
+		}
+		limite := notificacoesLimitePadrao
+		if v := r.URL.Query().Get("limite"); v != "" {
+			limite, err = strconv.Atoi(v)
+			if err != nil || limite < 1 || limite > notificacoesLimiteMaximo {
+				writeJSONError(w, http.StatusBadRequest, "limite inválido (1 a 100)")
+				return
+			}
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		out := model.ListaNotificacoes{Itens: []model.Notificacao{}}
+		if err := db.QueryRowContext(ctx,
+			`SELECT COUNT(*) FROM notificacoes WHERE usuario_id=$1 AND lida_em IS NULL`, acesso.UsuarioID,
+		).Scan(&out.NaoLidas); err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao contar notificações")
+			return
+		}
+
+		rows, err := db.QueryContext(ctx, `
+			SELECT id, tipo, titulo, mensagem, dados, lida_em, criado_em
+			  FROM notificacoes
+			 WHERE usuario_id=$1 AND (NOT $2 OR lida_em IS NULL)
+			 ORDER BY id DESC
+			 LIMIT $3
+		`, acesso.UsuarioID, apenasNaoLidas, limite)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao listar notificações")
+			return
+		}
+		defer rows.Close()
+		for rows.Next() {
+			n, err := scanNotificacao(rows)
+			if err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao ler notificações")
+				return
+			}
+			out.Itens = append(out.Itens, n)
+		}
+		if err := rows.Err(); err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao ler notificações")
+			return
+		}
+		writeJSON(w, http.StatusOK, out)
+	}
+}
+
+// MarcarNotificacaoLidaHandler trata PUT /api/notificacoes/{id}/lida
+//
+// Regras/erros:
+//   - 401 se não resolver usuário; 400 se o ID for inválido.
+//   - 404 se a notificação não for do usuário.
+//   - 200 + notificação; marcar de novo mantém o lida_em original.
+func MarcarNotificacaoLidaHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+		acesso, err := acessoFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+		id, ok := pathID(r, "id")
+		if !ok {
+			writeAPIError(w, http.StatusBadRequest, apierr.IDInvalido, "ID da notificação inválido")
+			return
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		n, err := scanNotificacao(db.QueryRowContext(ctx, `
+			UPDATE notificacoes
+			   SET lida_em = COALESCE(lida_em, NOW())
+			 WHERE id=$1 AND usuario_id=$2
+			RETURNING id, tipo, titulo, mensagem, dados, lida_em, criado_em
+		`, id, acesso.UsuarioID))
+		if err == sql.ErrNoRows {
+			writeAPIError(w, http.StatusNotFound, apierr.NotificacaoNaoEncontrada, "Notificação não encontrada")
+			return
+		}
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao marcar notificação")
+			return
+		}
+		writeJSON(w, http.StatusOK, n)
+	}
+}
+
+// scanNotificacao lê as colunas id, tipo, titulo, mensagem, dados, lida_em, criado_em.
+func scanNotificacao(s interface{ Scan(...any) error }) (model.Notificacao, error) {
+	var (
+		n     model.Notificacao
+		dados []byte
+		lida  sql.NullTime
+	)
+	if err := s.Scan(&n.ID, &n.Tipo, &n.Titulo, &n.Mensagem, &dados, &lida, &n.CriadoEm); err != nil {
+		return n, err
+	}
+	if err := json.Unmarshal(dados, &n.Dados); err != nil {
+		return n, err
+	}
+	if lida.Valid {
+		n.Lida, n.LidaEm = true, &lida.Time
+	}
+	return n, nil
+}
@@ -0,0 +1,97 @@
+// ============================================================================
+// 📄 handler/onboarding_handler.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - GET /api/usuario/onboarding → checklist de onboarding da conta
+//   ({etapas, concluidas, total, completo}); etapas automáticas (primeiro ano,
+//   primeiro estudante, foto de perfil) são detectadas a partir dos dados.
+// - PUT /api/usuario/onboarding/etapas/{etapa} → conclui uma etapa manual (hoje, "tutorial").
+//
+// 🔐 Autenticação/escopo
+// - `X-User-Email` obrigatório; ano/estudante contam os dados do tenant,
+//   tutorial e foto são da própria conta.
+// ============================================================================
+
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"backend/internal/apierr"
+	"backend/internal/model"
+)
+
+// OnboardingHandler trata GET /api/usuario/onboarding
+//
+// Regras/erros:
+//   - 405 se método != GET; 401 se não resolver usuário.
+//   - 200 + progresso (etapas na ordem do catálogo).
+func OnboardingHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+		acesso, err := acessoFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		p, err := model.CarregarOnboarding(ctx, db, acesso)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao buscar onboarding")
+			return
+		}
+		writeJSON(w, http.StatusOK, p)
+	}
+}
+
+// ConcluirEtapaHandler trata PUT /api/usuario/onboarding/etapas/{etapa}
+//
+// Regras/erros:
+//   - 405 se método != PUT; 401 se não resolver usuário.
+//   - 404 (NAO_ENCONTRADO) para etapa desconhecida.
+//   - 409 (CONFLITO) para etapa automática (concluída pelos próprios dados).
+//   - 200 + progresso atualizado; concluir de novo não muda nada.
+func ConcluirEtapaHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+		acesso, err := acessoFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		err = model.ConcluirEtapa(ctx, db, acesso.UsuarioID, r.PathValue("etapa"))
+		switch {
+		case errors.Is(err, model.ErrEtapaDesconhecida):
+			writeAPIError(w, http.StatusNotFound, apierr.NaoEncontrado, err.Error())
+			return
+		case errors.Is(err, model.ErrEtapaAutomatica):
+			writeAPIError(w, http.StatusConflict, apierr.Conflito, err.Error())
+			return
+		case err != nil:
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao concluir etapa")
+			return
+		}
+
+		p, err := model.CarregarOnboarding(ctx, db, acesso)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao buscar onboarding")
+			return
+		}
+		writeJSON(w, http.StatusOK, p)
+	}
+}
@@ -0,0 +1,375 @@
+// ============================================================================
+// 📄 handler/openapi.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - Monta o documento OpenAPI 3 a partir do catálogo de rotas
+//   (openapi_rotas.go) e dos structs de request/response.
+// - Esquemas saem por reflexão das tags `json` (omitempty, "-", structs
+//   embutidos); tipos nomeados viram components/schemas reaproveitáveis.
+//
+// 🔐 Autenticação/escopo
+// - Documento público (sem X-User-Email); descreve o contrato, não dados.
+// - O esquema de segurança "sessao" representa o Authorization: Bearer do login; "usuario", o header X-User-Email;
+//   "personificacao", o X-Impersonation-Token do suporte.
+// ============================================================================
+
+package handler
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/internal/apierr"
+	"backend/internal/model"
+)
+
+// esquemaDoc é um objeto JSON Schema/OpenAPI escrito à mão (respostas ad hoc em map).
+type esquemaDoc = map[string]any
+
+// tipoGo marca, dentro de um esquemaDoc, um valor cujo esquema sai por reflexão.
+type tipoGo struct{ v any }
+
+// parametroDoc descreve um parâmetro de query.
+type parametroDoc struct {
+	Nome      string
+	Tipo      string // string | integer | number | boolean
+	Descricao string
+}
+
+// operacaoDoc descreve uma rota do catálogo.
+type operacaoDoc struct {
+	Rota          string // "MÉTODO /caminho", igual ao registrado em cmd/server/main.go
+	Tag           string
+	Resumo        string
+	Descricao     string
+	Publica       bool           // sem X-User-Email
+	SoSessao      bool           // só a sessão do login (Authorization: Bearer); X-User-Email não basta
+	IDTexto       bool           // parâmetros de caminho são texto (padrão: inteiros)
+	Query         []parametroDoc // parâmetros de query
+	Cabecalhos    []string       // componentes de parâmetros de header (ex.: "If-Match")
+	Corpo         any            // tipo (valor zero) ou esquemaDoc do corpo JSON
+	CorpoOpcional bool           // corpo JSON pode ser omitido
+	Multipart     esquemaDoc     // corpo multipart/form-data
+	Status        int            // status de sucesso
+	Resposta      any            // tipo (valor zero) ou esquemaDoc; nil = sem corpo
+	TipoConteudo  string         // content-type da resposta (padrão application/json)
+	ETag          bool           // resposta traz ETag
+	Erros         []int          // status de erro relevantes (além de default)
+}
+
+// versão do contrato publicada em info.version
+const versaoAPI = "1.0.0"
+
+var reParametroCaminho = regexp.MustCompile(`\{(\w+)(\.\.\.)?\}`)
+
+var tipoTempo = reflect.TypeOf(time.Time{})
+
+// montarOpenAPI gera o documento completo (paths + components).
+func montarOpenAPI() esquemaDoc {
+	comps := esquemaDoc{}
+	paths := esquemaDoc{}
+
+	for _, op := range catalogoRotas {
+		metodo, caminho, _ := strings.Cut(op.Rota, " ")
+		caminho = reParametroCaminho.ReplaceAllString(caminho, "{$1}")
+		item, _ := paths[caminho].(esquemaDoc)
+		if item == nil {
+			item = esquemaDoc{}
+			paths[caminho] = item
+		}
+		item[strings.ToLower(metodo)] = montarOperacao(op, comps)
+	}
+
+	comps["Erro"] = esquemaDeTipo(reflect.TypeOf(apierr.Erro{}), comps, false)
+	comps["ErroCampo"] = esquemaDeTipo(reflect.TypeOf(model.ErroCampo{}), comps, false)
+
+	return esquemaDoc{
+		"openapi": "3.0.3",
+		"info": esquemaDoc{
+			"title":       "TecMise API",
+			"version":     versaoAPI,
+			"description": "API de gestão escolar do TecMise. Erros seguem o envelope Erro (code estável, message para exibição); validações respondem 422 com a lista de ErroCampo em details. Mensagens em pt-BR (padrão) ou en via Accept-Language.",
+		},
+		"paths":    paths,
+		"security": []esquemaDoc{{"sessao": []string{}}, {"usuario": []string{}}, {"personificacao": []string{}}},
+		"components": esquemaDoc{
+			"schemas": comps,
+			"securitySchemes": esquemaDoc{
+				"sessao": esquemaDoc{
+					"type":        "http",
+					"scheme":      "bearer",
+					"description": "Token devolvido por POST /login e /login/google; revogado, expirado ou desconhecido: 401 SESSAO_INVALIDA.",
+				},
+				"usuario": esquemaDoc{
+					"type":        "apiKey",
+					"in":          "header",
+					"name":        "X-User-Email",
+					"description": "E-mail do usuário autenticado; define o escopo (usuário/organização) dos dados. Ignorado com AUTH_REQUIRE_SESSION=true.",
+				},
+				"personificacao": esquemaDoc{
+					"type":        "apiKey",
+					"in":          "header",
+					"name":        "X-Impersonation-Token",
+					"description": "Token do suporte (POST /api/admin/impersonate); a requisição atua como a conta personificada e dispensa X-User-Email.",
+				},
+			},
+			"parameters": parametrosCabecalho,
+			"responses": esquemaDoc{
+				"Erro": esquemaDoc{
+					"description": "Erro no envelope padrão (ver apierr)",
+					"content":     conteudoJSON(esquemaDoc{"$ref": "#/components/schemas/Erro"}),
+				},
+			},
+		},
+	}
+}
+
+// montarOperacao converte um item do catálogo em Operation Object.
+func montarOperacao(op operacaoDoc, comps esquemaDoc) esquemaDoc {
+	out := esquemaDoc{
+		"tags":        []string{op.Tag},
+		"summary":     op.Resumo,
+		"operationId": idOperacao(op.Rota),
+	}
+	if op.Descricao != "" {
+		out["description"] = op.Descricao
+	}
+	switch {
+	case op.Publica:
+		out["security"] = []esquemaDoc{}
+	case op.SoSessao:
+		out["security"] = []esquemaDoc{{"sessao": []string{}}}
+	}
+
+	params := []esquemaDoc{}
+	for _, m := range reParametroCaminho.FindAllStringSubmatch(op.Rota, -1) {
+		tipo := "integer"
+		if op.IDTexto || m[2] != "" {
+			tipo = "string"
+		}
+		params = append(params, esquemaDoc{"name": m[1], "in": "path", "required": true, "schema": esquemaDoc{"type": tipo}})
+	}
+	for _, q := range op.Query {
+		p := esquemaDoc{"name": q.Nome, "in": "query", "schema": esquemaDoc{"type": q.Tipo}}
+		if q.Descricao != "" {
+			p["description"] = q.Descricao
+		}
+		params = append(params, p)
+	}
+	for _, c := range op.Cabecalhos {
+		params = append(params, esquemaDoc{"$ref": "#/components/parameters/" + c})
+	}
+	if len(params) > 0 {
+		out["parameters"] = params
+	}
+
+	switch {
+	case op.Multipart != nil:
+		out["requestBody"] = esquemaDoc{
+			"required": true,
+			"content":  esquemaDoc{"multipart/form-data": esquemaDoc{"schema": op.Multipart}},
+		}
+	case op.Corpo != nil:
+		out["requestBody"] = esquemaDoc{"required": !op.CorpoOpcional, "content": conteudoJSON(esquemaDe(op.Corpo, comps))}
+	}
+
+	status := op.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	sucesso := esquemaDoc{"description": http.StatusText(status)}
+	if op.Resposta != nil {
+		tipo := op.TipoConteudo
+		if tipo == "" {
+			tipo = "application/json"
+		}
+		sucesso["content"] = esquemaDoc{tipo: esquemaDoc{"schema": esquemaDe(op.Resposta, comps)}}
+	}
+	if op.ETag {
+		sucesso["headers"] = esquemaDoc{"ETag": esquemaDoc{"schema": esquemaDoc{"type": "string"}}}
+	}
+	respostas := esquemaDoc{strconv.Itoa(status): sucesso}
+	for _, e := range op.Erros {
+		if e == http.StatusNotModified {
+			respostas[strconv.Itoa(e)] = esquemaDoc{"description": "Não modificado (If-None-Match/If-Modified-Since)"}
+			continue
+		}
+		respostas[strconv.Itoa(e)] = esquemaDoc{
+			"description": http.StatusText(e),
+			"content":     conteudoJSON(esquemaDoc{"$ref": "#/components/schemas/Erro"}),
+		}
+	}
+	respostas["default"] = esquemaDoc{"$ref": "#/components/responses/Erro"}
+	out["responses"] = respostas
+	return out
+}
+
+// esquemaDe aceita um esquemaDoc (montado com objeto/tipo) ou um valor de exemplo (tipo Go).
+func esquemaDe(v any, comps esquemaDoc) esquemaDoc {
+	if e, ok := v.(esquemaDoc); ok {
+		return resolver(e, comps).(esquemaDoc)
+	}
+	return esquemaDeTipo(reflect.TypeOf(v), comps, true)
+}
+
+// resolver troca, recursivamente, as marcas tipoGo pelo esquema do tipo correspondente.
+func resolver(v any, comps esquemaDoc) any {
+	switch x := v.(type) {
+	case tipoGo:
+		return esquemaDeTipo(reflect.TypeOf(x.v), comps, true)
+	case esquemaDoc:
+		out := make(esquemaDoc, len(x))
+		for k, vv := range x {
+			out[k] = resolver(vv, comps)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// esquemaDeTipo traduz um tipo Go; structs nomeados viram $ref quando ref=true.
+func esquemaDeTipo(t reflect.Type, comps esquemaDoc, ref bool) esquemaDoc {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == tipoTempo {
+		return esquemaDoc{"type": "string", "format": "date-time"}
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return esquemaDoc{"type": "string"}
+	case reflect.Bool:
+		return esquemaDoc{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return esquemaDoc{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return esquemaDoc{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return esquemaDoc{"type": "array", "items": esquemaDeTipo(t.Elem(), comps, true)}
+	case reflect.Map:
+		return esquemaDoc{"type": "object", "additionalProperties": esquemaDeTipo(t.Elem(), comps, true)}
+	case reflect.Struct:
+		if ref && t.Name() != "" {
+			nome := nomeComponente(t)
+			if _, ok := comps[nome]; !ok {
+				comps[nome] = esquemaDoc{} // reserva (tipos recursivos)
+				comps[nome] = esquemaDeTipo(t, comps, false)
+			}
+			return esquemaDoc{"$ref": "#/components/schemas/" + nome}
+		}
+		props := esquemaDoc{}
+		coletarCampos(t, comps, props)
+		return esquemaDoc{"type": "object", "properties": props}
+	default: // interface{} (ex.: details do Erro)
+		return esquemaDoc{}
+	}
+}
+
+// coletarCampos percorre os campos exportados, achatando structs embutidos sem tag.
+func coletarCampos(t reflect.Type, comps esquemaDoc, props esquemaDoc) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		nome, _, _ := strings.Cut(tag, ",")
+		if f.Anonymous && nome == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				coletarCampos(ft, comps, props)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if nome == "" {
+			nome = f.Name
+		}
+		props[nome] = esquemaDeTipo(f.Type, comps, true)
+	}
+}
+
+// nomeComponente usa o nome do tipo Go (com inicial maiúscula para os não exportados).
+func nomeComponente(t reflect.Type) string {
+	nome := t.Name()
+	return strings.ToUpper(nome[:1]) + nome[1:]
+}
+
+// idOperacao gera um operationId estável a partir de "MÉTODO /caminho".
+func idOperacao(rota string) string {
+	metodo, caminho, _ := strings.Cut(rota, " ")
+	var b strings.Builder
+	b.WriteString(strings.ToLower(metodo))
+	for _, parte := range strings.FieldsFunc(caminho, func(r rune) bool { return r == '/' || r == '-' || r == '.' }) {
+		parte = strings.Trim(parte, "{}")
+		if parte == "api" || parte == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(parte[:1]) + parte[1:])
+	}
+	return b.String()
+}
+
+// conteudoJSON embrulha um esquema em content application/json.
+func conteudoJSON(schema esquemaDoc) esquemaDoc {
+	return esquemaDoc{"application/json": esquemaDoc{"schema": schema}}
+}
+
+// objeto monta um esquema inline a partir de pares nome → valor, onde valor é o nome de
+// um tipo primitivo ("string", "integer"...), um esquemaDoc ou um valor de exemplo (tipo Go).
+func objeto(campos ...any) esquemaDoc {
+	props := esquemaDoc{}
+	for i := 0; i+1 < len(campos); i += 2 {
+		nome := campos[i].(string)
+		switch v := campos[i+1].(type) {
+		case string:
+			props[nome] = esquemaDoc{"type": v}
+		case esquemaDoc, tipoGo:
+			props[nome] = v
+		default:
+			props[nome] = tipoGo{v}
+		}
+	}
+	return esquemaDoc{"type": "object", "properties": props}
+}
+
+// listaDe é o esquema de array cujos itens são o esquema informado.
+func listaDe(itens esquemaDoc) esquemaDoc {
+	return esquemaDoc{"type": "array", "items": itens}
+}
+
+// rotasDocumentadas indexa o catálogo por "MÉTODO /caminho".
+func rotasDocumentadas() map[string]bool {
+	out := make(map[string]bool, len(catalogoRotas))
+	for _, op := range catalogoRotas {
+		out[op.Rota] = true
+	}
+	return out
+}
+
+// RotasSemDocumentacao devolve (ordenadas) as rotas registradas que faltam no catálogo OpenAPI.
+// Rotas sem método (ex.: o fallback "/") são ignoradas.
+func RotasSemDocumentacao(registradas []string) []string {
+	doc := rotasDocumentadas()
+	faltando := []string{}
+	for _, r := range registradas {
+		if !strings.Contains(r, " ") || doc[r] {
+			continue
+		}
+		faltando = append(faltando, r)
+	}
+	sort.Strings(faltando)
+	return faltando
+}
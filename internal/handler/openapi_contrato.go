@@ -0,0 +1,223 @@
+// ============================================================================
+// 📄 handler/openapi_contrato.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - Testes de contrato: confere o JSON das respostas contra o esquema do
+//   catálogo OpenAPI (openapi_rotas.go), pegando desvios como fotoUrl vs
+//   foto_url ou um número que virou texto.
+//   * ValidarRespostaContrato → usada direto em testes (httptest)
+//   * ValidarContrato         → middleware que loga as violações (ligado por
+//     OPENAPI_VALIDATE_RESPONSES, recusado com APP_ENV=production)
+//
+// 🔐 Autenticação/escopo
+// - Não altera a resposta: só lê uma cópia do corpo depois de enviado.
+// - Verifica respostas de sucesso no status documentado e erros (>= 400) no
+//   envelope Erro; 204/304, redirecionamentos e corpos não JSON ficam de fora.
+// - Regras: campo não documentado, tipo divergente e item de lista fora do
+//   esquema. null é aceito em qualquer campo (slices/ponteiros nil) e campos
+//   ausentes não são cobrados (omitempty).
+// ============================================================================
+
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"backend/internal/apierr"
+	"backend/internal/logging"
+)
+
+// corpo máximo copiado para validação (respostas maiores não são conferidas)
+const contratoMaxCorpo = 4 << 20
+
+// contratoRota é o esquema da resposta de sucesso de uma rota do catálogo.
+type contratoRota struct {
+	status int
+	schema esquemaDoc // nil = sem corpo ou conteúdo não JSON
+}
+
+// contratos indexa o catálogo por "MÉTODO /caminho" (montado uma vez).
+var contratos = sync.OnceValues(func() (map[string]contratoRota, esquemaDoc) {
+	comps := esquemaDoc{}
+	rotas := make(map[string]contratoRota, len(catalogoRotas))
+	for _, op := range catalogoRotas {
+		c := contratoRota{status: op.Status}
+		if c.status == 0 {
+			c.status = http.StatusOK
+		}
+		if op.Resposta != nil && op.TipoConteudo == "" {
+			c.schema = esquemaDe(op.Resposta, comps)
+		}
+		rotas[op.Rota] = c
+	}
+	comps["Erro"] = esquemaDeTipo(reflect.TypeOf(apierr.Erro{}), comps, false)
+	return rotas, comps
+})
+
+// respostaCopiada repassa a resposta e guarda status e (até contratoMaxCorpo) o corpo.
+type respostaCopiada struct {
+	http.ResponseWriter
+	status int
+	corpo  bytes.Buffer
+	grande bool
+}
+
+// ValidarContrato confere cada resposta contra o catálogo OpenAPI e registra as
+// violações em log (nível error, com a rota e a lista de problemas).
+func ValidarContrato(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw := &respostaCopiada{ResponseWriter: w}
+		next.ServeHTTP(rw, r)
+		// r.Pattern é preenchido pelo ServeMux (router) durante next.ServeHTTP
+		if r.Pattern == "" || r.Method == http.MethodHead || rw.grande ||
+			!strings.HasPrefix(rw.Header().Get("Content-Type"), "application/json") {
+			return
+		}
+		if rw.status == 0 {
+			rw.status = http.StatusOK
+		}
+		rota := r.Method + " " + r.Pattern
+		if v := ValidarRespostaContrato(rota, rw.status, rw.corpo.Bytes()); len(v) > 0 {
+			logging.De(r.Context()).Error("contrato: resposta fora do esquema OpenAPI", "rota", rota, "status", rw.status, "violacoes", v)
+		}
+	})
+}
+
+// ValidarRespostaContrato devolve as violações do corpo JSON de uma resposta de rota
+// ("MÉTODO /caminho", como no catálogo) com o status informado. Rota fora do
+// catálogo, status não documentado ou resposta sem esquema: nenhuma violação.
+func ValidarRespostaContrato(rota string, status int, corpo []byte) []string {
+	rotas, comps := contratos()
+	var schema esquemaDoc
+	switch c, ok := rotas[rota]; {
+	case status >= http.StatusBadRequest:
+		schema = esquemaDoc{"$ref": "#/components/schemas/Erro"}
+	case ok && status == c.status && c.schema != nil:
+		schema = c.schema
+	default:
+		return nil
+	}
+	var v any
+	if err := json.Unmarshal(corpo, &v); err != nil {
+		return []string{"corpo não é JSON válido: " + err.Error()}
+	}
+	var out []string
+	conferirEsquema(v, schema, comps, "$", &out)
+	return out
+}
+
+// conferirEsquema acumula em out as divergências entre o valor v (decodificado de
+// JSON) e o esquema s; caminho localiza o valor (ex.: "$[0].foto_url").
+func conferirEsquema(v any, s esquemaDoc, comps esquemaDoc, caminho string, out *[]string) {
+	if ref, ok := s["$ref"].(string); ok {
+		s, _ = comps[strings.TrimPrefix(ref, "#/components/schemas/")].(esquemaDoc)
+	}
+	tipo, _ := s["type"].(string)
+	if v == nil || tipo == "" {
+		return
+	}
+	divergente := func() {
+		*out = append(*out, fmt.Sprintf("%s: esperado %s, recebido %s", caminho, tipo, tipoJSON(v)))
+	}
+	switch tipo {
+	case "object":
+		obj, ok := v.(map[string]any)
+		if !ok {
+			divergente()
+			return
+		}
+		props, _ := s["properties"].(esquemaDoc)
+		extra, _ := s["additionalProperties"].(esquemaDoc)
+		chaves := make([]string, 0, len(obj))
+		for k := range obj {
+			chaves = append(chaves, k)
+		}
+		sort.Strings(chaves)
+		for _, k := range chaves {
+			switch p, ok := props[k].(esquemaDoc); {
+			case ok:
+				conferirEsquema(obj[k], p, comps, caminho+"."+k, out)
+			case extra != nil:
+				conferirEsquema(obj[k], extra, comps, caminho+"."+k, out)
+			case props != nil:
+				*out = append(*out, fmt.Sprintf("%s.%s: campo não documentado", caminho, k))
+			}
+		}
+	case "array":
+		lista, ok := v.([]any)
+		if !ok {
+			divergente()
+			return
+		}
+		itens, _ := s["items"].(esquemaDoc)
+		for i, item := range lista {
+			conferirEsquema(item, itens, comps, fmt.Sprintf("%s[%d]", caminho, i), out)
+		}
+	case "string":
+		if _, ok := v.(string); !ok {
+			divergente()
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			divergente()
+		}
+	case "number":
+		if _, ok := v.(float64); !ok {
+			divergente()
+		}
+	case "integer":
+		if n, ok := v.(float64); !ok || n != math.Trunc(n) {
+			divergente()
+		}
+	}
+}
+
+// tipoJSON nomeia o tipo JSON de um valor decodificado (mensagens de violação).
+func tipoJSON(v any) string {
+	switch v.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	}
+	return "null"
+}
+
+func (rw *respostaCopiada) WriteHeader(status int) {
+	if rw.status == 0 {
+		rw.status = status
+	}
+	rw.ResponseWriter.WriteHeader(status)
+}
+
+func (rw *respostaCopiada) Write(b []byte) (int, error) {
+	if rw.status == 0 {
+		rw.status = http.StatusOK
+	}
+	if !rw.grande {
+		if rw.corpo.Len()+len(b) > contratoMaxCorpo {
+			rw.grande = true
+			rw.corpo.Reset()
+		} else {
+			rw.corpo.Write(b)
+		}
+	}
+	return rw.ResponseWriter.Write(b)
+}
+
+// Unwrap permite que http.ResponseController alcance o writer original.
+func (rw *respostaCopiada) Unwrap() http.ResponseWriter { return rw.ResponseWriter }
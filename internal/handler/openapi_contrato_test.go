@@ -0,0 +1,392 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"backend/internal/cripto"
+	"backend/internal/middleware"
+	"backend/internal/model"
+	"backend/internal/router"
+)
+
+/// ============ Banco roteirizado (database/sql) ============
+
+// resultadoRoteiro é a resposta do banco falso para consultas que contêm trecho
+// (numa escrita, linhas dá o número de linhas afetadas).
+type resultadoRoteiro struct {
+	trecho  string
+	colunas []string
+	linhas  [][]driver.Value
+}
+
+// roteiros indexa os resultados por DSN (um por teste); escritasRoteiro guarda as
+// escritas aceitas (*[]escritaRoteiro) para os testes conferirem.
+var roteiros, escritasRoteiro sync.Map
+
+// escritaRoteiro é um Exec que casou com algum trecho do roteiro.
+type escritaRoteiro struct {
+	query string
+	args  []driver.Value
+}
+
+type bancoRoteiro struct{}
+
+type conexaoRoteiro struct {
+	dsn        string
+	resultados []resultadoRoteiro
+}
+
+type consultaRoteiro struct {
+	c     *conexaoRoteiro
+	query string
+}
+
+// txRoteiro aceita Begin/Commit/Rollback sem efeito: as escritas valem na hora.
+type txRoteiro struct{}
+
+type linhasRoteiro struct {
+	colunas []string
+	linhas  [][]driver.Value
+}
+
+func init() { sql.Register("roteiro", bancoRoteiro{}) }
+
+func (bancoRoteiro) Open(dsn string) (driver.Conn, error) {
+	r, ok := roteiros.Load(dsn)
+	if !ok {
+		return nil, fmt.Errorf("roteiro %q não registrado", dsn)
+	}
+	return &conexaoRoteiro{dsn: dsn, resultados: r.([]resultadoRoteiro)}, nil
+}
+
+func (c *conexaoRoteiro) Prepare(q string) (driver.Stmt, error) {
+	return &consultaRoteiro{c: c, query: q}, nil
+}
+func (c *conexaoRoteiro) Close() error              { return nil }
+func (c *conexaoRoteiro) Begin() (driver.Tx, error) { return txRoteiro{}, nil }
+
+func (txRoteiro) Commit() error   { return nil }
+func (txRoteiro) Rollback() error { return nil }
+
+// CheckNamedValue aceita qualquer argumento (arrays, Valuers do model).
+func (c *conexaoRoteiro) CheckNamedValue(*driver.NamedValue) error { return nil }
+
+func (s *consultaRoteiro) Close() error  { return nil }
+func (s *consultaRoteiro) NumInput() int { return -1 }
+func (s *consultaRoteiro) Exec(args []driver.Value) (driver.Result, error) {
+	for _, r := range s.c.resultados {
+		if strings.Contains(s.query, r.trecho) {
+			if e, ok := escritasRoteiro.Load(s.c.dsn); ok {
+				lista := e.(*[]escritaRoteiro)
+				*lista = append(*lista, escritaRoteiro{query: s.query, args: args})
+			}
+			return driver.RowsAffected(len(r.linhas)), nil
+		}
+	}
+	return nil, fmt.Errorf("roteiro: escrita não prevista: %s", s.query)
+}
+func (s *consultaRoteiro) Query([]driver.Value) (driver.Rows, error) {
+	for _, r := range s.c.resultados {
+		if strings.Contains(s.query, r.trecho) {
+			return &linhasRoteiro{colunas: r.colunas, linhas: r.linhas}, nil
+		}
+	}
+	return nil, fmt.Errorf("roteiro: consulta não prevista: %s", s.query)
+}
+
+func (l *linhasRoteiro) Columns() []string { return l.colunas }
+func (l *linhasRoteiro) Close() error      { return nil }
+func (l *linhasRoteiro) Next(dest []driver.Value) error {
+	if len(l.linhas) == 0 {
+		return io.EOF
+	}
+	copy(dest, l.linhas[0])
+	l.linhas = l.linhas[1:]
+	return nil
+}
+
+// bancoDeTeste abre um *sql.DB que responde às consultas com os resultados dados.
+func bancoDeTeste(t *testing.T, resultados ...resultadoRoteiro) *sql.DB {
+	t.Helper()
+	roteiros.Store(t.Name(), resultados)
+	db, err := sql.Open("roteiro", t.Name())
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = db.Close()
+		roteiros.Delete(t.Name())
+		escritasRoteiro.Delete(t.Name())
+	})
+	return db
+}
+
+// escritasDeTeste passa a guardar as escritas aceitas pelo banco do teste atual.
+func escritasDeTeste(t *testing.T) *[]escritaRoteiro {
+	lista := &[]escritaRoteiro{}
+	escritasRoteiro.Store(t.Name(), lista)
+	return lista
+}
+
+/// ============ Violações registradas por ValidarContrato ============
+
+// coletorViolacoes é um slog.Handler que guarda o atributo "violacoes" dos logs do contrato.
+type coletorViolacoes struct {
+	mu        sync.Mutex
+	violacoes []string
+}
+
+func (c *coletorViolacoes) Enabled(context.Context, slog.Level) bool { return true }
+func (c *coletorViolacoes) WithAttrs([]slog.Attr) slog.Handler       { return c }
+func (c *coletorViolacoes) WithGroup(string) slog.Handler            { return c }
+func (c *coletorViolacoes) Handle(_ context.Context, r slog.Record) error {
+	r.Attrs(func(a slog.Attr) bool {
+		if a.Key == "violacoes" {
+			if v, ok := a.Value.Any().([]string); ok {
+				c.mu.Lock()
+				c.violacoes = append(c.violacoes, v...)
+				c.mu.Unlock()
+			}
+		}
+		return true
+	})
+	return nil
+}
+
+// servirComContrato registra h em padrao num router, envolve com ValidarContrato e
+// devolve a resposta de req e as violações do esquema OpenAPI.
+func servirComContrato(t *testing.T, padrao string, h http.Handler, req *http.Request) (*httptest.ResponseRecorder, []string) {
+	t.Helper()
+	coletor := &coletorViolacoes{}
+	anterior := slog.Default()
+	slog.SetDefault(slog.New(coletor))
+	t.Cleanup(func() { slog.SetDefault(anterior) })
+
+	rt := router.New()
+	rt.Handle(padrao, h)
+	rec := httptest.NewRecorder()
+	ValidarContrato(rt).ServeHTTP(rec, req)
+	return rec, coletor.violacoes
+}
+
+// comAcesso põe na requisição o usuário que o middleware Autenticacao resolveria.
+func comAcesso(req *http.Request) *http.Request {
+	a := model.Acesso{UsuarioID: 7, TenantID: 7, Email: "ana@escola.com", Papel: model.PapelAdmin}
+	return req.WithContext(middleware.ComAcesso(req.Context(), a))
+}
+
+/// ============ Testes ============
+
+func TestContratoListarEstudantes(t *testing.T) {
+	agora := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	db := bancoDeTeste(t,
+		resultadoRoteiro{trecho: "SELECT COUNT(*), GREATEST", colunas: []string{"count", "greatest"},
+			linhas: [][]driver.Value{{int64(2), agora}}},
+		resultadoRoteiro{trecho: "FROM estudantes e LEFT JOIN anos a",
+			colunas: []string{"id", "nome", "cpf", "email", "data_nascimento", "telefone", "foto_url",
+				"ano_id", "turma_id", "status", "versao", "ano", "turma"},
+			linhas: [][]driver.Value{
+				{int64(1), "Ana Souza", "12345678909", "ana@aluno.com", "2012-03-04", "11999990000", "",
+					int64(3), int64(4), "ativo", int64(1), "8º ano", "8º A"},
+				{int64(2), "Bruno Lima", "98765432100", "bruno@aluno.com", "2011-11-30", "", "/uploads/b.jpg",
+					int64(3), int64(0), "transferido", int64(5), "8º ano", ""},
+			}},
+	)
+	repo := model.NewEstudanteRepo(db, nil)
+
+	req := comAcesso(httptest.NewRequest(http.MethodGet, "/api/estudantes", nil))
+	rec, violacoes := servirComContrato(t, "GET /api/estudantes", ListarEstudantesHandler(db, repo), req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, corpo = %s", rec.Code, rec.Body)
+	}
+	if !strings.Contains(rec.Body.String(), "Bruno Lima") {
+		t.Fatalf("listagem sem os estudantes do banco: %s", rec.Body)
+	}
+	if len(violacoes) > 0 {
+		t.Errorf("GET /api/estudantes fora do esquema: %v", violacoes)
+	}
+}
+
+func TestContratoListarAnos(t *testing.T) {
+	agora := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	db := bancoDeTeste(t,
+		resultadoRoteiro{trecho: "SELECT (SELECT COUNT(*) FROM anos", colunas: []string{"a", "b", "c", "d"},
+			linhas: [][]driver.Value{{int64(2), agora, int64(1), agora}}},
+		resultadoRoteiro{trecho: "SELECT a.id, a.nome, COUNT(e.id)",
+			colunas: []string{"id", "nome", "count", "ordem", "arquivado", "periodo_letivo_id"},
+			linhas: [][]driver.Value{
+				{int64(3), "8º ano", int64(1), int64(0), false, int64(0)},
+				{int64(4), "8º A", int64(0), int64(1), true, int64(2)},
+			}},
+	)
+
+	req := comAcesso(httptest.NewRequest(http.MethodGet, "/api/anos?arquivados=true", nil))
+	rec, violacoes := servirComContrato(t, "GET /api/anos", ListarAnosHandler(db, nil, 0), req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, corpo = %s", rec.Code, rec.Body)
+	}
+	if len(violacoes) > 0 {
+		t.Errorf("GET /api/anos fora do esquema: %v", violacoes)
+	}
+}
+
+func TestContratoPerfilUsuario(t *testing.T) {
+	perfil := resultadoRoteiro{trecho: "FROM usuarios", colunas: []string{"id", "nome", "email", "foto_url", "tutorial_visto"},
+		linhas: [][]driver.Value{{int64(7), "Ana", "ana@escola.com", "/uploads/ana.jpg", true}}}
+
+	for _, padrao := range []string{"GET /api/perfil", "GET /api/usuario"} {
+		t.Run(padrao, func(t *testing.T) {
+			db := bancoDeTeste(t, perfil)
+			_, caminho, _ := strings.Cut(padrao, " ")
+			req := comAcesso(httptest.NewRequest(http.MethodGet, caminho, nil))
+			rec, violacoes := servirComContrato(t, padrao, PerfilHandler(db), req)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, corpo = %s", rec.Code, rec.Body)
+			}
+			if len(violacoes) > 0 {
+				t.Errorf("%s fora do esquema: %v", padrao, violacoes)
+			}
+		})
+	}
+}
+
+func TestContratoListarSessoes(t *testing.T) {
+	agora := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	db := bancoDeTeste(t,
+		resultadoRoteiro{trecho: "FROM sessoes", colunas: []string{"id", "metodo", "ip", "user_agent", "criada_em", "ultimo_uso", "expira_em"},
+			linhas: [][]driver.Value{
+				{int64(11), "senha", "10.0.0.1", "Firefox", agora, agora, agora.Add(720 * time.Hour)},
+				{int64(9), "google", "10.0.0.2", "", agora.Add(-time.Hour), agora.Add(-time.Hour), agora.Add(719 * time.Hour)},
+			}},
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/usuario/sessoes", nil)
+	a := model.Acesso{UsuarioID: 7, TenantID: 7, Email: "ana@escola.com", Papel: model.PapelAdmin, SessaoID: 11}
+	req = req.WithContext(middleware.ComAcesso(req.Context(), a))
+	rec, violacoes := servirComContrato(t, "GET /api/usuario/sessoes", SessoesHandler(db), req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, corpo = %s", rec.Code, rec.Body)
+	}
+	if !strings.Contains(rec.Body.String(), `"id":11,"metodo":"senha"`) || strings.Count(rec.Body.String(), `"atual":true`) != 1 {
+		t.Errorf("sessão atual não marcada: %s", rec.Body)
+	}
+	if len(violacoes) > 0 {
+		t.Errorf("GET /api/usuario/sessoes fora do esquema: %v", violacoes)
+	}
+}
+
+func TestContratoResponsaveisCifrados(t *testing.T) {
+	pii, err := cripto.Novo("1", map[string][]byte{"1": []byte(strings.Repeat("k", 32))}, []byte(strings.Repeat("i", 16)))
+	if err != nil {
+		t.Fatalf("cripto.Novo: %v", err)
+	}
+	cpf, _ := pii.Cifrar("12345678909")
+	tel, _ := pii.Cifrar("11988887777")
+	db := bancoDeTeste(t,
+		resultadoRoteiro{trecho: "SELECT EXISTS(SELECT 1 FROM estudantes", colunas: []string{"exists"},
+			linhas: [][]driver.Value{{true}}},
+		resultadoRoteiro{trecho: "FROM responsaveis", colunas: []string{"id", "estudante_id", "nome", "cpf", "telefone", "email", "parentesco"},
+			linhas: [][]driver.Value{
+				{int64(5), int64(1), "Maria Souza", cpf, tel, "maria@email.com", "mae"},
+				{int64(6), int64(1), "João Souza", "", "11977776666", "", "pai"}, // gravado antes de cifrar-pii
+			}},
+	)
+
+	req := comAcesso(httptest.NewRequest(http.MethodGet, "/api/estudantes/1/responsaveis", nil))
+	padrao := "GET /api/estudantes/{id}/responsaveis"
+	rec, violacoes := servirComContrato(t, padrao, ResponsaveisEstudanteHandler(db, model.NewEstudanteRepo(db, pii)), req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, corpo = %s", rec.Code, rec.Body)
+	}
+	corpo := rec.Body.String()
+	for _, v := range []string{`"cpf":"12345678909"`, `"telefone":"11988887777"`, `"telefone":"11977776666"`} {
+		if !strings.Contains(corpo, v) {
+			t.Errorf("resposta sem %s: %s", v, corpo)
+		}
+	}
+	if strings.Contains(corpo, "enc:") {
+		t.Errorf("valor cifrado vazou na resposta: %s", corpo)
+	}
+	if len(violacoes) > 0 {
+		t.Errorf("%s fora do esquema: %v", padrao, violacoes)
+	}
+}
+
+func TestContratoEnvelopeDeErro(t *testing.T) {
+	casos := []struct {
+		nome   string
+		padrao string
+		h      func(db *sql.DB) http.Handler
+		req    *http.Request
+		status int
+	}{
+		{"401 sem usuário", "GET /api/anos",
+			func(db *sql.DB) http.Handler { return ListarAnosHandler(db, nil, 0) },
+			httptest.NewRequest(http.MethodGet, "/api/anos", nil), http.StatusUnauthorized},
+		{"400 status inválido", "GET /api/estudantes",
+			func(db *sql.DB) http.Handler { return ListarEstudantesHandler(db, model.NewEstudanteRepo(db, nil)) },
+			comAcesso(httptest.NewRequest(http.MethodGet, "/api/estudantes?status=sumido", nil)), http.StatusBadRequest},
+		{"404 usuário removido", "GET /api/perfil",
+			func(db *sql.DB) http.Handler { return PerfilHandler(db) },
+			comAcesso(httptest.NewRequest(http.MethodGet, "/api/perfil", nil)), http.StatusNotFound},
+		{"405 do roteador", "GET /api/anos",
+			func(db *sql.DB) http.Handler { return ListarAnosHandler(db, nil, 0) },
+			comAcesso(httptest.NewRequest(http.MethodDelete, "/api/anos", nil)), http.StatusMethodNotAllowed},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			db := bancoDeTeste(t, resultadoRoteiro{trecho: "FROM usuarios", colunas: []string{"id", "nome", "email", "foto_url", "tutorial_visto"}})
+			rec, violacoes := servirComContrato(t, c.padrao, c.h(db), c.req)
+			if rec.Code != c.status {
+				t.Fatalf("status = %d, esperado %d (corpo = %s)", rec.Code, c.status, rec.Body)
+			}
+			if len(violacoes) > 0 {
+				t.Errorf("erro fora do envelope: %v", violacoes)
+			}
+		})
+	}
+}
+
+// O 404 do roteador (caminho sem rota) não tem r.Pattern: confere o envelope direto.
+func TestContratoEnvelopeRotaInexistente(t *testing.T) {
+	rec := httptest.NewRecorder()
+	router.New().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/nada", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d", rec.Code)
+	}
+	if v := ValidarRespostaContrato("GET /api/nada", rec.Code, rec.Body.Bytes()); len(v) > 0 {
+		t.Errorf("404 fora do envelope: %v", v)
+	}
+}
+
+// Desvios de nome/tipo (foto_url no lugar de fotoUrl, id como texto) precisam falhar.
+func TestContratoDetectaDesvio(t *testing.T) {
+	desviado := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]any{
+			"id": "7", "nome": "Ana", "email": "ana@escola.com", "foto_url": "/uploads/ana.jpg", "tutorial_visto": true,
+		})
+	})
+	_, violacoes := servirComContrato(t, "GET /api/perfil", desviado, httptest.NewRequest(http.MethodGet, "/api/perfil", nil))
+	if len(violacoes) != 2 {
+		t.Fatalf("violações = %v, esperadas 2 (id e foto_url)", violacoes)
+	}
+
+	erroDesviado := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusNotFound, map[string]any{"erro": "não encontrado"})
+	})
+	_, violacoes = servirComContrato(t, "GET /api/perfil", erroDesviado, httptest.NewRequest(http.MethodGet, "/api/perfil", nil))
+	if len(violacoes) == 0 {
+		t.Fatal("erro fora do envelope passou sem violações")
+	}
+}
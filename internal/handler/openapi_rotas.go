@@ -0,0 +1,613 @@
+// ============================================================================
+// 📄 handler/openapi_rotas.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - Catálogo das rotas documentadas no OpenAPI (GET /api/openapi.json).
+// - Cada entrada aponta os tipos reais de request/response; respostas montadas
+//   com map nos handlers são descritas com objeto(...).
+//
+// 🔐 Autenticação/escopo
+// - Rotas sem Publica=true exigem X-User-Email (esquema "usuario").
+// - Ao criar/alterar uma rota em cmd/server/main.go, atualize esta lista: a subida do
+//   servidor registra em log as rotas que ficaram sem documentação.
+// ============================================================================
+
+package handler
+
+import (
+	"net/http"
+
+	"backend/internal/classroom"
+	"backend/internal/model"
+)
+
+// Parâmetros de cabeçalho compartilhados (components/parameters).
+var parametrosCabecalho = esquemaDoc{
+	"If-Match": esquemaDoc{
+		"name": "If-Match", "in": "header", "required": true,
+		"description": `ETag recebido no GET (ex.: "3"); "*" grava sem checar a versão.`,
+		"schema":      esquemaDoc{"type": "string"},
+	},
+	"If-None-Match": esquemaDoc{
+		"name": "If-None-Match", "in": "header",
+		"description": "ETag da última listagem; responde 304 se nada mudou.",
+		"schema":      esquemaDoc{"type": "string"},
+	},
+	"If-Modified-Since": esquemaDoc{
+		"name": "If-Modified-Since", "in": "header",
+		"description": "Last-Modified da última listagem (ignorado se houver If-None-Match).",
+		"schema":      esquemaDoc{"type": "string"},
+	},
+	"Idempotency-Key": esquemaDoc{
+		"name": "Idempotency-Key", "in": "header",
+		"description": "Chave única da tentativa (até 255 caracteres); repetições em 24h devolvem a resposta original.",
+		"schema":      esquemaDoc{"type": "string", "maxLength": 255},
+	},
+}
+
+// parâmetros de query reaproveitados
+var (
+	queryPeriodoLetivo = parametroDoc{"periodo_letivo_id", "integer", "Filtra pelo período letivo"}
+	queryDe            = parametroDoc{"de", "string", "Data inicial (YYYY-MM-DD)"}
+	queryAte           = parametroDoc{"ate", "string", "Data final (YYYY-MM-DD)"}
+	queryIgnoreID      = parametroDoc{"ignoreId", "integer", "Estudante a desconsiderar (edição); alias: excludeId"}
+)
+
+// esquemas ad hoc reaproveitados
+var (
+	esquemaOK       = objeto("ok", "boolean")
+	esquemaExiste   = objeto("exists", "boolean")
+	esquemaUsuario  = model.UserPublic{}
+	esquemaArquivo  = esquemaDoc{"type": "string", "format": "binary"}
+	esquemaResumoPr = tipoGo{model.ResumoPresenca{}}
+	esquemaGraphQL  = objeto("data", "object", "errors", listaDe(objeto("message", "string", "path", listaDe(esquemaDoc{}), "extensions", "object")))
+)
+
+// catalogoRotas lista todas as rotas registradas em cmd/server/main.go (registrarRotas).
+var catalogoRotas = []operacaoDoc{
+	// ---------- Autenticação ----------
+	{Rota: "POST /register", Tag: "Autenticação", Resumo: "Cadastrar usuário", Publica: true,
+		Descricao: "Com captcha ligado no servidor (CAPTCHA_DRIVER), envie o token do hCaptcha/reCAPTCHA em captcha; " +
+			"ausente ou recusado: 400 CAPTCHA_INVALIDO.",
+		Corpo: model.RegisterRequest{}, Status: http.StatusCreated, Resposta: esquemaOK,
+		Erros: []int{http.StatusBadRequest, http.StatusConflict, http.StatusUnprocessableEntity, http.StatusBadGateway}},
+	{Rota: "POST /login", Tag: "Autenticação", Resumo: "Login com e-mail e senha", Publica: true,
+		Corpo: model.LoginRequest{},
+		Descricao: "Cada tentativa de uma conta existente entra no histórico de logins (GET /api/usuario/logins). " +
+			"Sucesso emite uma sessão do dispositivo: envie token em Authorization: Bearer nas próximas requisições.",
+		Resposta: model.RespostaLogin{},
+		Erros:    []int{http.StatusUnauthorized, http.StatusForbidden}},
+	{Rota: "POST /login/google", Tag: "Autenticação", Resumo: "Login com Google (ID token)", Publica: true,
+		Descricao: "Aceita o token em idToken, id_token ou credential; cria o usuário no primeiro acesso. " +
+			"Responde o mesmo usuário e token de sessão de POST /login. Google fora do ar: 503 GOOGLE_INDISPONIVEL com Retry-After.",
+		Corpo: googleLoginRequest{}, Resposta: model.RespostaLogin{},
+		Erros: []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusServiceUnavailable}},
+
+	// ---------- Usuário ----------
+	{Rota: "GET /api/perfil", Tag: "Usuário", Resumo: "Perfil do usuário logado",
+		Resposta: esquemaUsuario},
+	{Rota: "PUT /api/perfil", Tag: "Usuário", Resumo: "Atualizar nome, foto e/ou senha do perfil",
+		Corpo: model.UpdatePerfilRequest{}, Resposta: esquemaOK,
+		Erros: []int{http.StatusForbidden, http.StatusUnprocessableEntity}},
+	{Rota: "POST /api/perfil/foto", Tag: "Usuário", Resumo: "Enviar foto de perfil (multipart)",
+		Descricao: "JPEG, PNG ou GIF até 5 MiB. A imagem é cortada em quadrado, reduzida a 512 px e regravada em JPEG (sem EXIF); " +
+			"foto_url do perfil passa a apontar para ela. Papel leitor: 403. Com antivírus ligado: 422 ARQUIVO_INFECTADO; 503 se ele estiver fora.",
+		Multipart: objeto("arquivo", esquemaArquivo),
+		Resposta:  objeto("foto_url", "string", "signed_url", "string"),
+		Erros: []int{http.StatusForbidden, http.StatusRequestEntityTooLarge, http.StatusUnsupportedMediaType,
+			http.StatusUnprocessableEntity, http.StatusServiceUnavailable}},
+	{Rota: "GET /api/usuario", Tag: "Usuário", Resumo: "Perfil do usuário logado (legado)",
+		Descricao: "Mesma resposta de GET /api/perfil. ?email só é aceito se for o do próprio usuário; " +
+			"a busca de outras contas é GET /api/admin/usuario.",
+		Query:    []parametroDoc{{"email", "string", "E-mail do próprio usuário (opcional)"}},
+		Resposta: esquemaUsuario, Erros: []int{http.StatusForbidden}},
+	{Rota: "PUT /api/usuario/{id}/tutorial", Tag: "Usuário", Resumo: "Marcar tutorial como visto",
+		Descricao: "Corpo opcional; sem tutorial_visto, grava true.",
+		Corpo:     model.TutorialUpdateRequest{}, CorpoOpcional: true, Status: http.StatusNoContent},
+	{Rota: "GET /api/usuario/logins", Tag: "Usuário", Resumo: "Histórico de logins da própria conta",
+		Descricao: "Tentativas de login (senha ou Google), com sucesso ou motivo da recusa, IP e user agent; as mais recentes primeiro. " +
+			"Para a próxima página, envie proximo em ?antes=.",
+		Query: []parametroDoc{
+			{"limite", "integer", "Quantidade (1 a 100, padrão 20)"},
+			{"antes", "integer", "Cursor: itens com id menor que este"},
+		},
+		Resposta: model.HistoricoLogins{}},
+	{Rota: "GET /api/usuario/sessoes", Tag: "Usuário", Resumo: "Sessões ativas da própria conta",
+		Descricao: "Uma por login em dispositivo, com IP, user agent, último uso e validade; as usadas mais recentemente primeiro. " +
+			"atual marca a sessão da própria requisição.",
+		Resposta: []model.Sessao{}},
+	{Rota: "DELETE /api/usuario/sessoes/{id}", Tag: "Usuário", Resumo: "Encerrar sessão",
+		Descricao: "O dispositivo recebe 401 SESSAO_INVALIDA na próxima requisição; encerrar a sessão atual equivale a logout. " +
+			"Durante a personificação: 403.",
+		Status: http.StatusNoContent, Erros: []int{http.StatusForbidden, http.StatusNotFound}},
+	{Rota: "GET /api/usuario/preferencias", Tag: "Usuário", Resumo: "Preferências de interface da conta",
+		Descricao: "Tema, ano/turma padrão e colunas visíveis das tabelas, com os padrões aplicados; ano_padrao removido volta como null.",
+		Resposta:  model.Preferencias{}},
+	{Rota: "GET /api/usuario/onboarding", Tag: "Usuário", Resumo: "Checklist de onboarding da conta",
+		Descricao: "Etapas na ordem de exibição: tutorial (manual), primeiro_ano, primeiro_estudante e foto_perfil " +
+			"(automáticas, detectadas a partir dos dados). Concluída, a etapa não volta atrás.",
+		Resposta: model.ProgressoOnboarding{}},
+	{Rota: "PUT /api/usuario/onboarding/etapas/{etapa}", Tag: "Usuário", Resumo: "Concluir etapa manual do onboarding", IDTexto: true,
+		Descricao: "Hoje só \"tutorial\" é manual (grava também tutorial_visto). Etapa automática: 409.",
+		Resposta:  model.ProgressoOnboarding{}, Erros: []int{http.StatusNotFound, http.StatusConflict}},
+	{Rota: "POST /api/usuario/definir-senha", Tag: "Usuário", Resumo: "Definir senha numa conta criada pelo Google",
+		Descricao: "Além do X-User-Email, exige um ID Token Google recém-emitido da conta Google vinculada. " +
+			"Conta que já tem senha: 409 (trocar em PUT /api/perfil).",
+		Corpo: model.DefinirSenhaRequest{}, Status: http.StatusNoContent,
+		Erros: []int{http.StatusForbidden, http.StatusConflict, http.StatusUnprocessableEntity, http.StatusServiceUnavailable}},
+	{Rota: "POST /api/usuario/vincular-google", Tag: "Usuário", Resumo: "Vincular o Google a uma conta com senha",
+		Descricao: "Exige a senha atual e um ID Token Google recém-emitido. Conta Google já usada por outro usuário: 409.",
+		Corpo:     model.VincularGoogleRequest{}, Status: http.StatusNoContent,
+		Erros: []int{http.StatusForbidden, http.StatusConflict, http.StatusUnprocessableEntity, http.StatusServiceUnavailable}},
+	{Rota: "PUT /api/usuario/preferencias", Tag: "Usuário", Resumo: "Salvar preferências de interface",
+		Descricao: "Substitui o documento inteiro (campos omitidos voltam ao padrão). tema: claro, escuro ou sistema; " +
+			"ano_padrao: ano ativo do tenant ou null; colunas: {\"estudantes\"|\"anos\": [nomes snake_case, até 30]}. Chaves desconhecidas: 400.",
+		Corpo: model.Preferencias{}, Resposta: model.Preferencias{},
+		Erros: []int{http.StatusUnprocessableEntity}},
+
+	// ---------- Organização ----------
+	{Rota: "GET /api/organizacao", Tag: "Organização", Resumo: "Organização do usuário (com membros)",
+		Resposta: model.Organizacao{}, Erros: []int{http.StatusNotFound}},
+	{Rota: "POST /api/organizacao", Tag: "Organização", Resumo: "Criar organização (usuário vira admin)",
+		Corpo: model.OrganizacaoRequest{}, Status: http.StatusCreated, Resposta: model.Organizacao{},
+		Erros: []int{http.StatusConflict}},
+	{Rota: "POST /api/organizacao/membros", Tag: "Organização", Resumo: "Adicionar membro existente",
+		Corpo: model.MembroRequest{}, Status: http.StatusCreated, Resposta: model.Membro{},
+		Erros: []int{http.StatusForbidden, http.StatusNotFound, http.StatusConflict}},
+	{Rota: "PUT /api/organizacao/membros/{usuarioID}", Tag: "Organização", Resumo: "Alterar papel do membro",
+		Corpo: objeto("papel", "string"), Resposta: objeto("usuario_id", "integer", "papel", "string"),
+		Erros: []int{http.StatusForbidden, http.StatusNotFound}},
+	{Rota: "DELETE /api/organizacao/membros/{usuarioID}", Tag: "Organização", Resumo: "Remover membro",
+		Status: http.StatusNoContent, Erros: []int{http.StatusForbidden, http.StatusNotFound}},
+	{Rota: "GET /api/organizacao/convites", Tag: "Organização", Resumo: "Listar convites pendentes",
+		Resposta: []model.Convite{}, Erros: []int{http.StatusForbidden}},
+	{Rota: "POST /api/organizacao/convites", Tag: "Organização", Resumo: "Convidar por e-mail",
+		Descricao: "Envia o link APP_URL/convite?token=... (expira em 7 dias).",
+		Corpo:     model.MembroRequest{}, Status: http.StatusCreated, Resposta: model.Convite{},
+		Erros: []int{http.StatusForbidden, http.StatusBadGateway}},
+	{Rota: "POST /api/organizacao/convites/aceitar", Tag: "Organização", Resumo: "Aceitar convite",
+		Corpo: model.AceitarConviteRequest{}, Resposta: model.Organizacao{},
+		Erros: []int{http.StatusForbidden, http.StatusNotFound, http.StatusConflict}},
+	{Rota: "DELETE /api/organizacao/convites/{id}", Tag: "Organização", Resumo: "Revogar convite",
+		Status: http.StatusNoContent, Erros: []int{http.StatusForbidden, http.StatusNotFound}},
+
+	// ---------- Admin (equipe de suporte) ----------
+	{Rota: "GET /api/admin/usuarios", Tag: "Admin", SoSessao: true, Resumo: "Listar contas",
+		Descricao: "Apenas contas com suporte=true, autenticadas pela sessão do login (X-User-Email sozinho: 401 SESSAO_OBRIGATORIA); enxerga todas as contas. Para a próxima página, envie proximo em ?antes=.",
+		Query: []parametroDoc{
+			{"busca", "string", "Trecho do nome ou e-mail (sem diferenciar caixa)"},
+			{"limite", "integer", "Quantidade (1 a 100, padrão 20)"},
+			{"antes", "integer", "Cursor: contas com id menor que este"},
+		},
+		Resposta: model.PaginaUsuariosAdmin{}, Erros: []int{http.StatusUnauthorized, http.StatusForbidden}},
+	{Rota: "GET /api/admin/usuario", Tag: "Admin", SoSessao: true, Resumo: "Buscar conta por e-mail",
+		Descricao: "Apenas contas com suporte=true; e-mail exato (sem diferenciar caixa).",
+		Query:     []parametroDoc{{"email", "string", "E-mail da conta"}},
+		Resposta:  model.UsuarioAdmin{}, Erros: []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound}},
+	{Rota: "PUT /api/admin/usuarios/{id}/bloquear", Tag: "Admin", SoSessao: true, Resumo: "Bloquear ou desbloquear conta",
+		Descricao: "Conta bloqueada não autentica (401) nem faz login (403 CONTA_BLOQUEADA); os dados ficam intactos. " +
+			"Corpo omitido = bloquear; {\"bloqueado\": false} desbloqueia.",
+		Corpo: model.BloqueioRequest{}, CorpoOpcional: true, Resposta: model.UsuarioAdmin{},
+		Erros: []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound}},
+	{Rota: "DELETE /api/admin/usuarios/{id}", Tag: "Admin", SoSessao: true, Resumo: "Excluir conta e dados",
+		Descricao: "Apaga a conta e, em cascata, os dados dela. Recusada (409 DONO_COM_MEMBROS) se a conta for dona de organização com outros membros.",
+		Status:    http.StatusNoContent, Erros: []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusConflict}},
+	{Rota: "POST /api/admin/impersonate", Tag: "Admin", SoSessao: true, Resumo: "Personificar conta (token curto)",
+		Descricao: "Emite um token de 30 minutos para agir como a conta (cabeçalho X-Impersonation-Token, no lugar de X-User-Email). " +
+			"A sessão e o motivo ficam registrados; toda escrita feita com o token sai na auditoria (requisicao.executada) com personificado_por. " +
+			"Contas de suporte e bloqueadas não podem ser personificadas (403 PERSONIFICACAO_NAO_PERMITIDA).",
+		Corpo: model.PersonificarRequest{}, Status: http.StatusCreated, Resposta: model.Personificacao{},
+		Erros: []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound}},
+
+	// ---------- LGPD ----------
+	{Rota: "GET /api/meus-dados/export", Tag: "LGPD", Resumo: "Iniciar exportação dos dados (ZIP)",
+		Status: http.StatusAccepted, Resposta: exportacaoResposta{}, Erros: []int{http.StatusServiceUnavailable}},
+	{Rota: "GET /api/meus-dados/export/{id}", Tag: "LGPD", Resumo: "Status/download da exportação", IDTexto: true,
+		Resposta: exportacaoResposta{}, Erros: []int{http.StatusNotFound}},
+
+	// ---------- Estudantes ----------
+	{Rota: "GET /api/estudantes/check-cpf", Tag: "Estudantes", Resumo: "Verificar se o CPF já está cadastrado",
+		Query:    []parametroDoc{{"cpf", "string", "CPF (com ou sem máscara)"}, queryIgnoreID},
+		Resposta: esquemaExiste},
+	{Rota: "GET /api/estudantes/check-email", Tag: "Estudantes", Resumo: "Verificar se o e-mail já está cadastrado",
+		Query:    []parametroDoc{{"email", "string", "E-mail do estudante"}, queryIgnoreID},
+		Resposta: esquemaExiste},
+	{Rota: "GET /api/estudantes/duplicados", Tag: "Estudantes", Resumo: "Grupos de possíveis duplicados",
+		Query:    []parametroDoc{{"min", "number", "Confiança mínima (0 a 1)"}},
+		Resposta: []model.GrupoDuplicado{}},
+	{Rota: "GET /api/estudantes/aniversariantes", Tag: "Estudantes", Resumo: "Próximos aniversários (estudantes ativos)",
+		Descricao: "Sem parâmetros: de hoje até 30 dias. Nascidos em 29/02 comemoram em 28/02 nos anos não bissextos.",
+		Query: []parametroDoc{
+			{"mes", "integer", "Mês (1 a 12); o próximo, se já passou neste ano"},
+			{"dias", "integer", "Janela a partir de hoje (1 a 366, padrão 30)"},
+		},
+		Resposta: []model.Aniversariante{}},
+	{Rota: "GET /api/estudantes/export", Tag: "Estudantes", Resumo: "Exportar estudantes ativos (Educacenso ou vCard)",
+		Descricao: "format=educacenso: arquivo de migração do INEP (registros 20, 30 e 60 separados por \"|\"), só com os campos " +
+			"que o Tecmise guarda. Estudantes com pendências (nome com símbolos, sem data de nascimento ou ano, CPF inválido) " +
+			"bloqueiam o arquivo com 422 EDUCACENSO_PENDENCIAS; validar=true devolve só o relatório. " +
+			"format=vcf: text/vcard com um cartão por estudante e por responsável (telefone/e-mail), para importar na agenda.",
+		Query: []parametroDoc{
+			{"format", "string", "educacenso ou vcf"},
+			{"inep_escola", "string", "educacenso: código INEP da escola (8 dígitos)"},
+			{"validar", "boolean", "educacenso: só valida e responde {total, validos, pendencias}"},
+			{"ano_id", "integer", "vcf: só os estudantes (e responsáveis) do ano"},
+		},
+		Resposta: esquemaDoc{"type": "string"}, TipoConteudo: "text/plain",
+		Erros: []int{http.StatusUnprocessableEntity}},
+	{Rota: "POST /api/relatorios", Tag: "Estudantes", Resumo: "Relatório agrupado de estudantes",
+		Descricao: "agrupar_por: ano (colunas ano_id e ano), turma, status. metricas: quantidade (padrão), idade_media, " +
+			"idade_minima, idade_maxima. periodos[].campo: data_nascimento ou atualizado_em (de/ate inclusivos). " +
+			"Somente leitura: liberado ao papel leitor.",
+		Query:    []parametroDoc{{"formato", "string", "json (padrão) ou csv"}},
+		Corpo:    model.RelatorioSpec{},
+		Resposta: model.Relatorio{}, Erros: []int{http.StatusUnprocessableEntity}},
+	{Rota: "GET /api/filtros", Tag: "Estudantes", Resumo: "Filtros salvos da conta",
+		Resposta: []model.FiltroSalvo{}},
+	{Rota: "POST /api/filtros", Tag: "Estudantes", Resumo: "Salvar filtro de estudantes",
+		Descricao: "filtro.sem lista campos vazios (telefone, email, cpf, foto_url, data_nascimento, turma_id). " +
+			"Critérios vazios não restringem; os informados são combinados com E. Pessoal: liberado ao papel leitor.",
+		Corpo: model.FiltroSalvoRequest{}, Status: http.StatusCreated,
+		Resposta: model.FiltroSalvo{}, Erros: []int{http.StatusConflict, http.StatusUnprocessableEntity}},
+	{Rota: "PUT /api/filtros/{id}", Tag: "Estudantes", Resumo: "Alterar filtro salvo",
+		Corpo:    model.FiltroSalvoRequest{},
+		Resposta: model.FiltroSalvo{}, Erros: []int{http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity}},
+	{Rota: "DELETE /api/filtros/{id}", Tag: "Estudantes", Resumo: "Remover filtro salvo",
+		Status: http.StatusNoContent, Erros: []int{http.StatusNotFound}},
+	{Rota: "GET /api/filtros/{id}/resultados", Tag: "Estudantes", Resumo: "Executar filtro salvo",
+		Descricao: "Aplica o filtro aos estudantes atuais (não excluídos), ordenados por id.",
+		Resposta:  model.ResultadoFiltro{}, Erros: []int{http.StatusNotFound}},
+	{Rota: "POST /api/estudantes/merge", Tag: "Estudantes", Resumo: "Mesclar dois estudantes",
+		Descricao: "Move presenças, notas, documentos, responsáveis, consentimentos e os históricos de matrícula e de status do secundário para o principal e exclui o secundário (vai para a lixeira e para o feed); consentimento ativo repetido no secundário fica revogado.",
+		Corpo:     model.MesclarEstudantesRequest{},
+		Resposta:  objeto("principal", model.Estudante{}, "movidos", map[string]int64{}),
+		Erros:     []int{http.StatusNotFound, http.StatusConflict}},
+	{Rota: "GET /api/estudantes", Tag: "Estudantes", Resumo: "Listar estudantes",
+		Descricao: "Enviada em streaming. Com Accept: application/x-ndjson (ou JSON Lines: application/jsonl), " +
+			"um estudante por linha em vez do array.",
+		Query: []parametroDoc{
+			{"status", "string", "ativo | transferido | formado"},
+			{"fields", "string", "Campos devolvidos, separados por vírgula (ex.: id,nome,foto_url); sem ele, todos"},
+		},
+		Cabecalhos: []string{"If-None-Match", "If-Modified-Since"},
+		Resposta:   []model.EstudanteResposta{}, ETag: true, Erros: []int{http.StatusBadRequest, http.StatusNotModified}},
+	{Rota: "POST /api/estudantes", Tag: "Estudantes", Resumo: "Criar estudante",
+		Cabecalhos: []string{"Idempotency-Key"},
+		Corpo:      model.EstudanteCreateRequest{}, Status: http.StatusCreated, Resposta: model.Estudante{},
+		Erros: []int{http.StatusConflict, http.StatusUnprocessableEntity}},
+	{Rota: "GET /api/estudantes/{id}", Tag: "Estudantes", Resumo: "Detalhar estudante (com responsáveis)",
+		Resposta: model.EstudanteDetalhe{}, ETag: true, Erros: []int{http.StatusNotFound}},
+	{Rota: "PUT /api/estudantes/{id}", Tag: "Estudantes", Resumo: "Editar estudante (concorrência otimista)",
+		Cabecalhos: []string{"If-Match"},
+		Corpo:      model.EstudanteUpdateRequest{}, Resposta: objeto("message", "string", "versao", "integer"), ETag: true,
+		Erros: []int{http.StatusNotFound, http.StatusConflict, http.StatusPreconditionFailed,
+			http.StatusUnprocessableEntity, http.StatusPreconditionRequired}},
+	{Rota: "DELETE /api/estudantes/{id}", Tag: "Estudantes", Resumo: "Excluir estudante (vai para a lixeira)",
+		Status: http.StatusNoContent, Erros: []int{http.StatusNotFound}},
+	{Rota: "POST /api/estudantes/import-fotos", Tag: "Estudantes", Resumo: "Importar fotos em lote (ZIP multipart)",
+		Descricao: "ZIP de até 100 MiB com até 1000 imagens nomeadas pelo CPF (com ou sem máscara) ou e-mail do estudante, " +
+			"ex.: 12345678909.jpg, ana@escola.com.png. Cada foto é tratada como em POST /api/uploads e vira a foto_url do estudante; " +
+			"o resultado vem por arquivo (importada | sem_correspondencia | ambiguo | repetido | invalido | infectado | erro).",
+		Multipart: objeto("arquivo", esquemaArquivo),
+		Resposta:  model.ResultadoImportacaoFotos{},
+		Erros:     []int{http.StatusRequestEntityTooLarge, http.StatusUnprocessableEntity}},
+	{Rota: "POST /api/graphql", Tag: "Estudantes", Resumo: "GraphQL do domínio de estudantes",
+		Descricao: "Query: estudantes(filtro, limite, offset), estudante(id), anos(arquivados); " +
+			"Mutation: criarEstudante, editarEstudante, removerEstudante. Sem introspecção.",
+		Corpo:    objeto("query", "string", "operationName", "string", "variables", "object"),
+		Resposta: esquemaGraphQL,
+		Erros:    []int{http.StatusForbidden}},
+	{Rota: "GET /api/graphql", Tag: "Estudantes", Resumo: "GraphQL (somente queries)",
+		Query: []parametroDoc{
+			{"query", "string", "Documento GraphQL"},
+			{"operationName", "string", "Operação a executar (documentos com várias)"},
+			{"variables", "string", "Variáveis em JSON"},
+		},
+		Resposta: esquemaGraphQL,
+		Erros:    []int{http.StatusMethodNotAllowed}},
+
+	// ---------- Sub-recursos de estudante ----------
+	{Rota: "GET /api/estudantes/{id}/documentos", Tag: "Documentos", Resumo: "Listar documentos do estudante",
+		Resposta: []Documento{}, Erros: []int{http.StatusNotFound}},
+	{Rota: "POST /api/estudantes/{id}/documentos", Tag: "Documentos", Resumo: "Enviar documento (multipart)",
+		Descricao: "Com antivírus ligado: 422 ARQUIVO_INFECTADO; 503 se ele estiver fora.",
+		Multipart: objeto("arquivo", esquemaArquivo, "tipo", "string"),
+		Status:    http.StatusCreated, Resposta: Documento{},
+		Erros: []int{http.StatusNotFound, http.StatusRequestEntityTooLarge, http.StatusUnprocessableEntity, http.StatusServiceUnavailable}},
+	{Rota: "GET /api/estudantes/{id}/documentos/{docID}", Tag: "Documentos", Resumo: "Baixar documento",
+		Resposta: esquemaArquivo, TipoConteudo: "application/octet-stream", Erros: []int{http.StatusNotFound}},
+	{Rota: "DELETE /api/estudantes/{id}/documentos/{docID}", Tag: "Documentos", Resumo: "Remover documento",
+		Status: http.StatusNoContent, Erros: []int{http.StatusNotFound}},
+	{Rota: "GET /api/estudantes/{id}/presencas", Tag: "Frequência", Resumo: "Presenças do estudante no intervalo",
+		Query:    []parametroDoc{queryDe, queryAte},
+		Resposta: objeto("registros", []model.Presenca{}, "resumo", esquemaResumoPr),
+		Erros:    []int{http.StatusNotFound}},
+	{Rota: "GET /api/estudantes/{id}/presencas/resumo", Tag: "Frequência", Resumo: "Frequência agrupada por mês ou ano",
+		Query:    []parametroDoc{queryDe, queryAte, {"agrupar", "string", "mes (padrão) | ano"}},
+		Resposta: []model.ResumoPresenca{}, Erros: []int{http.StatusNotFound}},
+	{Rota: "GET /api/estudantes/{id}/boletim", Tag: "Avaliações", Resumo: "Boletim (médias por disciplina/período)",
+		Query:    []parametroDoc{queryPeriodoLetivo},
+		Resposta: objeto("estudante_id", "integer", "linhas", []model.BoletimLinha{}, "media_geral", "number"),
+		Erros:    []int{http.StatusNotFound}},
+	{Rota: "GET /api/estudantes/{id}/carteirinha", Tag: "Carteirinha", Resumo: "Carteirinha do estudante (PDF) ou só o QR code (PNG)",
+		Descricao: "PDF no tamanho de cartão (85,6 × 54 mm) com foto, nome, turma e QR code assinado (CARTEIRINHA_KEY). " +
+			"formato=png devolve só o QR code. Foto fora de /uploads ou sem consentimento de uso de foto sai como \"sem foto\".",
+		Query:    []parametroDoc{{"formato", "string", "pdf (padrão) ou png"}},
+		Resposta: esquemaArquivo, TipoConteudo: "application/pdf",
+		Erros: []int{http.StatusNotFound, http.StatusServiceUnavailable}},
+	{Rota: "GET /api/carteirinhas/verificar", Tag: "Carteirinha", Resumo: "Verificar o QR code de uma carteirinha",
+		Descricao: "valido=false traz motivo: codigo_invalido (assinatura), outra_conta, nao_encontrado (excluído) ou inativo (transferido/formado).",
+		Query:     []parametroDoc{{"codigo", "string", "Conteúdo lido do QR code"}},
+		Resposta:  model.VerificacaoCarteirinha{}, Erros: []int{http.StatusServiceUnavailable}},
+	{Rota: "GET /api/estudantes/{id}/responsaveis", Tag: "Responsáveis", Resumo: "Listar responsáveis",
+		Resposta: []model.Responsavel{}, Erros: []int{http.StatusNotFound}},
+	{Rota: "POST /api/estudantes/{id}/responsaveis", Tag: "Responsáveis", Resumo: "Adicionar responsável",
+		Corpo: model.ResponsavelRequest{}, Status: http.StatusCreated, Resposta: model.Responsavel{},
+		Erros: []int{http.StatusNotFound}},
+	{Rota: "PUT /api/estudantes/{id}/responsaveis/{rid}", Tag: "Responsáveis", Resumo: "Editar responsável",
+		Corpo: model.ResponsavelRequest{}, Resposta: model.Responsavel{}, Erros: []int{http.StatusNotFound}},
+	{Rota: "DELETE /api/estudantes/{id}/responsaveis/{rid}", Tag: "Responsáveis", Resumo: "Remover responsável",
+		Status: http.StatusNoContent, Erros: []int{http.StatusNotFound}},
+	{Rota: "GET /api/estudantes/{id}/consentimentos", Tag: "Responsáveis", Resumo: "Consentimentos LGPD (ativos e revogados)",
+		Resposta: []model.Consentimento{}, Erros: []int{http.StatusNotFound}},
+	{Rota: "POST /api/estudantes/{id}/consentimentos", Tag: "Responsáveis", Resumo: "Registrar consentimento",
+		Descricao: "finalidade: email, sms e whatsapp (com responsavel_id: autoriza comunicados/avisos àquele responsável) " +
+			"ou foto (uso da foto do estudante na lista da turma e na carteirinha). Sem consentimento ativo nada é enviado " +
+			"(sem_consentimento) e a foto não é usada.",
+		Corpo: model.ConsentimentoRequest{}, Status: http.StatusCreated, Resposta: model.Consentimento{},
+		Erros: []int{http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity}},
+	{Rota: "DELETE /api/estudantes/{id}/consentimentos/{cid}", Tag: "Responsáveis", Resumo: "Revogar consentimento",
+		Descricao: "O registro continua no histórico com revogado_em.",
+		Resposta:  model.Consentimento{}, Erros: []int{http.StatusNotFound}},
+	{Rota: "GET /api/estudantes/{id}/status", Tag: "Matrícula", Resumo: "Status atual e histórico",
+		Resposta: objeto("status", "string", "historico", []model.HistoricoStatus{}),
+		Erros:    []int{http.StatusNotFound}},
+	{Rota: "PUT /api/estudantes/{id}/status", Tag: "Matrícula", Resumo: "Mudar status (ativo/transferido/formado)",
+		Corpo: model.MudancaStatusRequest{}, Resposta: objeto("id", "integer", "status", "string"),
+		Erros: []int{http.StatusNotFound, http.StatusConflict}},
+	{Rota: "POST /api/estudantes/{id}/transferir", Tag: "Matrícula", Resumo: "Transferir de turma",
+		Corpo: model.TransferenciaRequest{}, Resposta: model.MovimentoMatricula{},
+		Erros: []int{http.StatusNotFound, http.StatusConflict}},
+	{Rota: "GET /api/estudantes/{id}/matriculas", Tag: "Matrícula", Resumo: "Histórico de movimentações",
+		Resposta: []model.MovimentoMatricula{}, Erros: []int{http.StatusNotFound}},
+
+	// ---------- Avaliações ----------
+	{Rota: "GET /api/avaliacoes", Tag: "Avaliações", Resumo: "Listar avaliações",
+		Query:    []parametroDoc{{"ano_id", "integer", "Filtra pelo ano/turma"}, queryPeriodoLetivo},
+		Resposta: []model.Avaliacao{}},
+	{Rota: "POST /api/avaliacoes", Tag: "Avaliações", Resumo: "Criar avaliação",
+		Corpo: model.AvaliacaoCreateRequest{}, Status: http.StatusCreated, Resposta: model.Avaliacao{},
+		Erros: []int{http.StatusNotFound, http.StatusConflict}},
+	{Rota: "DELETE /api/avaliacoes/{id}", Tag: "Avaliações", Resumo: "Remover avaliação",
+		Status: http.StatusNoContent, Erros: []int{http.StatusNotFound}},
+	{Rota: "GET /api/avaliacoes/{id}/notas", Tag: "Avaliações", Resumo: "Notas lançadas",
+		Resposta: []model.NotaItem{}, Erros: []int{http.StatusNotFound}},
+	{Rota: "PUT /api/avaliacoes/{id}/notas", Tag: "Avaliações", Resumo: "Lançar notas (em lote)",
+		Corpo: model.NotasRequest{}, Resposta: objeto("lancadas", "integer"),
+		Erros: []int{http.StatusNotFound, http.StatusConflict}},
+
+	// ---------- Períodos letivos ----------
+	{Rota: "GET /api/periodos", Tag: "Períodos", Resumo: "Listar períodos letivos",
+		Resposta: []model.PeriodoLetivo{}},
+	{Rota: "POST /api/periodos", Tag: "Períodos", Resumo: "Criar período letivo",
+		Corpo: model.PeriodoLetivoRequest{}, Status: http.StatusCreated, Resposta: model.PeriodoLetivo{},
+		Erros: []int{http.StatusConflict}},
+	{Rota: "PUT /api/periodos/{id}/fechar", Tag: "Períodos", Resumo: "Fechar período (bloqueia lançamentos)",
+		Resposta: objeto("id", "integer", "fechado", "boolean"), Erros: []int{http.StatusNotFound}},
+	{Rota: "PUT /api/periodos/{id}/abrir", Tag: "Períodos", Resumo: "Reabrir período",
+		Resposta: objeto("id", "integer", "fechado", "boolean"), Erros: []int{http.StatusNotFound}},
+	{Rota: "POST /api/periodos/{id}/clonar", Tag: "Períodos", Resumo: "Novo período com os anos/turmas do atual",
+		Corpo: model.PeriodoLetivoRequest{}, Status: http.StatusCreated,
+		Resposta: objeto("periodo", model.PeriodoLetivo{}, "anos", []Ano{}),
+		Erros:    []int{http.StatusNotFound, http.StatusConflict}},
+
+	// ---------- Turmas ----------
+	{Rota: "POST /api/turmas/{id}/chamada", Tag: "Frequência", Resumo: "Registrar chamada do dia",
+		Corpo:    model.ChamadaRequest{},
+		Resposta: objeto("ano_id", "integer", "data", "string", "resumo", esquemaResumoPr),
+		Erros:    []int{http.StatusNotFound, http.StatusConflict}},
+	{Rota: "GET /api/turmas/{id}/presencas/resumo", Tag: "Frequência", Resumo: "Frequência de cada estudante da turma",
+		Query: []parametroDoc{queryDe, queryAte},
+		Resposta: listaDe(objeto("estudante_id", "integer", "nome", "string", "periodo", "string",
+			"total", "integer", "presentes", "integer", "faltas", "integer", "percentual", "number")),
+		Erros: []int{http.StatusNotFound}},
+
+	// ---------- Anos/turmas ----------
+	{Rota: "GET /api/anos", Tag: "Anos", Resumo: "Listar anos/turmas",
+		Query: []parametroDoc{
+			{"arquivados", "boolean", "Inclui anos arquivados"}, queryPeriodoLetivo,
+		},
+		Cabecalhos: []string{"If-None-Match", "If-Modified-Since"},
+		Resposta:   []Ano{}, ETag: true, Erros: []int{http.StatusNotModified}},
+	{Rota: "POST /api/anos", Tag: "Anos", Resumo: "Criar ano/turma",
+		Cabecalhos: []string{"Idempotency-Key"},
+		Corpo:      objeto("nome", "string", "periodo_letivo_id", "integer"), Status: http.StatusCreated,
+		Resposta: objeto("id", "integer", "nome", "string", "ordem", "integer", "periodo_letivo_id", "integer"),
+		Erros:    []int{http.StatusConflict}},
+	{Rota: "PUT /api/anos/reorder", Tag: "Anos", Resumo: "Reordenar anos",
+		Corpo: objeto("ids", []int{}), Status: http.StatusNoContent},
+	{Rota: "POST /api/anos/bulk", Tag: "Anos", Resumo: "Criar anos em lote a partir de um modelo",
+		Corpo: model.AnoTemplate{}, Status: http.StatusCreated,
+		Resposta: listaDe(objeto("serie", "string", "anos", []Ano{})),
+		Erros:    []int{http.StatusConflict, http.StatusUnprocessableEntity}},
+	{Rota: "DELETE /api/anos/{id}", Tag: "Anos", Resumo: "Excluir ano/turma (vai para a lixeira)",
+		Descricao: "Com estudantes vinculados responde 409, a menos que force=true ou move_to_ano_id seja informado. " +
+			"Com force=true os estudantes vão para a lixeira junto e voltam ao restaurar o ano.",
+		Query: []parametroDoc{
+			{"force", "boolean", "Exclui também os estudantes vinculados"},
+			{"move_to_ano_id", "integer", "Move os estudantes para outro ano antes de excluir"},
+		},
+		Status: http.StatusNoContent, Erros: []int{http.StatusNotFound, http.StatusConflict}},
+	{Rota: "POST /api/anos/{id}/promover", Tag: "Matrícula", Resumo: "Promover os estudantes ativos do ano",
+		Query: []parametroDoc{{"dry_run", "boolean", "Só lista quem seria promovido"}},
+		Corpo: model.PromocaoRequest{},
+		Resposta: objeto("de_ano_id", "integer", "para_ano_id", "integer", "para_turma_id", "integer",
+			"dry_run", "boolean", "quantidade", "integer",
+			"estudantes", listaDe(objeto("id", "integer", "nome", "string"))),
+		Erros: []int{http.StatusNotFound, http.StatusConflict}},
+	{Rota: "PUT /api/anos/{id}/arquivar", Tag: "Anos", Resumo: "Arquivar/desarquivar ano",
+		Descricao: "Corpo opcional; sem arquivado, arquiva.",
+		Corpo:     objeto("arquivado", "boolean"), CorpoOpcional: true, Resposta: objeto("id", "integer", "arquivado", "boolean"),
+		Erros: []int{http.StatusNotFound}},
+	{Rota: "GET /api/anos/{id}/roster.pdf", Tag: "Anos", Resumo: "Lista impressa dos estudantes do ano, por turma (PDF)",
+		Descricao: "PDF A4 com os estudantes ativos agrupados por turma (uma página nova por turma), foto e nome em grade. " +
+			"Foto fora de /uploads ou sem consentimento de uso de foto sai como quadro com as iniciais.",
+		Query:    []parametroDoc{{"colunas", "string", "Extras sob o nome, separados por vírgula: telefone, data_nascimento, email"}},
+		Resposta: esquemaArquivo, TipoConteudo: "application/pdf",
+		Erros: []int{http.StatusBadRequest, http.StatusNotFound}},
+	{Rota: "POST /api/anos/{id}/share", Tag: "Anos", Resumo: "Criar link público (somente leitura) da lista de estudantes",
+		Descricao: "Corpo opcional; expira_em_horas de 1 a 720 (padrão 72). O link assinado (COMPARTILHAR_KEY) mostra só o nome dos estudantes ativos.",
+		Corpo:     model.CompartilharAnoRequest{}, CorpoOpcional: true, Status: http.StatusCreated, Resposta: model.LinkCompartilhamento{},
+		Erros: []int{http.StatusNotFound, http.StatusServiceUnavailable}},
+	{Rota: "GET /compartilhado/anos/{id}", Tag: "Anos", Resumo: "Lista pública de um ano/turma (link compartilhado)", Publica: true,
+		Descricao: "HTML quando o navegador pede text/html; JSON com formato=json ou Accept sem text/html. Link expirado ou adulterado responde 403 LINK_INVALIDO.",
+		Query: []parametroDoc{{"exp", "integer", "Validade (Unix) gerada no link"}, {"sig", "string", "Assinatura gerada no link"},
+			{"formato", "string", "json ou html"}},
+		Resposta: model.TurmaCompartilhada{}, Erros: []int{http.StatusForbidden, http.StatusServiceUnavailable}},
+
+	// ---------- Integrações ----------
+	{Rota: "POST /api/integracoes/classroom/cursos", Tag: "Integrações", Resumo: "Cursos ativos do professor no Google Classroom",
+		Descricao: "access_token OAuth obtido no front-end (emitido para GOOGLE_CLIENT_ID) com os escopos " +
+			"classroom.courses.readonly, classroom.rosters.readonly e classroom.profile.emails. O token não é armazenado.",
+		Corpo: objeto("access_token", "string"), Resposta: []classroom.Curso{},
+		Erros: []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusUnprocessableEntity, http.StatusBadGateway}},
+	{Rota: "POST /api/integracoes/classroom/previa", Tag: "Integrações", Resumo: "Prévia da importação: alunos do curso × estudantes existentes",
+		Descricao: "Nada é gravado. situacao: novo, duplicado (mesmo e-mail) ou possivel_duplicado (nome parecido).",
+		Corpo:     objeto("access_token", "string", "curso_id", "string"), Resposta: model.PreviaClassroom{},
+		Erros: []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusUnprocessableEntity, http.StatusBadGateway}},
+	{Rota: "POST /api/integracoes/classroom/importar", Tag: "Integrações", Resumo: "Confirmar a importação dos alunos escolhidos",
+		Descricao: "Nome e e-mail vêm do Classroom (pelo google_id); CPF e data de nascimento são obrigatórios por aluno. " +
+			"Cada aluno é independente (criado, duplicado, invalido ou fora_do_curso); ao final há notificação e e-mail com o resumo.",
+		Corpo: model.ImportarClassroomRequest{}, Resposta: model.ResultadoImportacaoClassroom{},
+		Erros: []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusUnprocessableEntity, http.StatusBadGateway}},
+
+	// ---------- Lixeira ----------
+	{Rota: "GET /api/lixeira", Tag: "Lixeira", Resumo: "Estudantes e anos excluídos (mais recentes primeiro)",
+		Query: []parametroDoc{
+			{"tipo", "string", "estudante ou ano"},
+			{"limite", "integer", "Quantidade (1 a 200, padrão 50)"},
+		},
+		Resposta: []model.ItemLixeira{}},
+	{Rota: "POST /api/lixeira/{tipo}/{id}/restaurar", Tag: "Lixeira", Resumo: "Restaurar item da lixeira",
+		Descricao: "Ano: restaura também os estudantes excluídos junto (force=true). Estudante cujo ano está na lixeira: 409.",
+		Resposta:  objeto("tipo", "string", "id", "integer", "nome", "string", "estudantes_restaurados", "integer"),
+		Erros:     []int{http.StatusNotFound, http.StatusConflict}},
+	{Rota: "DELETE /api/lixeira/{tipo}/{id}", Tag: "Lixeira", Resumo: "Excluir definitivamente (admin)",
+		Descricao: "Exige ?confirmar= com o nome do item. Ano: leva os estudantes e as avaliações dele; 409 se houver estudantes ativos.",
+		Query:     []parametroDoc{{"confirmar", "string", "Nome do item (confirmação)"}},
+		Status:    http.StatusNoContent, Erros: []int{http.StatusForbidden, http.StatusNotFound, http.StatusConflict}},
+
+	// ---------- Comunicados ----------
+	{Rota: "POST /api/comunicados", Tag: "Comunicados", Resumo: "Enviar e-mail aos responsáveis dos estudantes/turmas selecionados",
+		Descricao: "Campos em assunto e corpo: {{nome}} (responsável), {{estudante}}, {{ano}}, {{turma}} e {{escola}}; campo desconhecido é 422. " +
+			"turma_ids aceita ids de anos ou turmas. Só estudantes ativos; um e-mail por responsável e estudante, " +
+			"enviado na hora, só a quem consentiu (LGPD): a resposta traz a situação de cada um (enviado, falhou, sem_email ou sem_consentimento).",
+		Corpo: model.ComunicadoRequest{}, Status: http.StatusCreated, Resposta: model.Comunicado{},
+		Cabecalhos: []string{"Idempotency-Key"}, Erros: []int{http.StatusUnprocessableEntity}},
+	{Rota: "GET /api/comunicados", Tag: "Comunicados", Resumo: "Comunicados enviados (mais recentes primeiro), com as contagens",
+		Query:    []parametroDoc{{"limite", "integer", "Quantidade (1 a 100, padrão 20)"}},
+		Resposta: []model.Comunicado{}},
+	{Rota: "GET /api/comunicados/{id}", Tag: "Comunicados", Resumo: "Situação da entrega por destinatário",
+		Descricao: "canal: email, sms ou whatsapp. status: pendente, enviado, falhou (com erro), sem_email, sem_telefone ou sem_consentimento.",
+		Resposta:  model.Comunicado{}, Erros: []int{http.StatusNotFound}},
+
+	// ---------- Avisos (SMS/WhatsApp) ----------
+	{Rota: "POST /api/avisos", Tag: "Avisos", Resumo: "Enviar aviso curto por SMS/WhatsApp aos responsáveis",
+		Descricao: "Texto de até 320 caracteres com os mesmos campos dos comunicados; enviado na hora pelo provedor da conta " +
+			"(409 se não houver). Fica registrado como comunicado (canal sms ou whatsapp): a resposta traz a situação de " +
+			"cada responsável (enviado, falhou, sem_telefone ou sem_consentimento). No WhatsApp, texto livre fora da janela de 24h pode ser recusado.",
+		Corpo: model.AvisoRequest{}, Status: http.StatusCreated, Resposta: model.Comunicado{},
+		Cabecalhos: []string{"Idempotency-Key"}, Erros: []int{http.StatusConflict, http.StatusUnprocessableEntity}},
+	{Rota: "GET /api/avisos/provedor", Tag: "Avisos", Resumo: "Provedor de SMS/WhatsApp da conta (sem o token)",
+		Resposta: model.ProvedorMensagem{}, Erros: []int{http.StatusForbidden, http.StatusNotFound}},
+	{Rota: "PUT /api/avisos/provedor", Tag: "Avisos", Resumo: "Configurar provedor de SMS/WhatsApp (Twilio ou Zenvia)",
+		Descricao: "Twilio: conta_sid (Account SID), token (Auth Token) e remetente (número). Zenvia: token (API token) e " +
+			"remetente (nome do remetente). Credenciais gravadas cifradas; sem PII_KEY responde 503.",
+		Corpo: model.ProvedorMensagemRequest{}, Resposta: model.ProvedorMensagem{},
+		Erros: []int{http.StatusForbidden, http.StatusUnprocessableEntity, http.StatusServiceUnavailable}},
+	{Rota: "DELETE /api/avisos/provedor", Tag: "Avisos", Resumo: "Remover credenciais do provedor",
+		Status: http.StatusNoContent, Erros: []int{http.StatusForbidden, http.StatusNotFound}},
+
+	// ---------- Webhooks ----------
+	{Rota: "GET /api/webhooks", Tag: "Webhooks", Resumo: "Listar webhooks (sem o segredo)",
+		Resposta: []model.Webhook{}, Erros: []int{http.StatusForbidden}},
+	{Rota: "POST /api/webhooks", Tag: "Webhooks", Resumo: "Cadastrar webhook",
+		Descricao: "Eventos: estudante.created, estudante.deleted, ano.removed. O segredo (gerado se omitido) " +
+			"só aparece nesta resposta; cada entrega traz X-Tecmise-Signature: t=<unix>,v1=<HMAC-SHA256 de \"<t>.<corpo>\">.",
+		Corpo: model.WebhookRequest{}, Status: http.StatusCreated, Resposta: model.Webhook{},
+		Erros: []int{http.StatusForbidden, http.StatusUnprocessableEntity}},
+	{Rota: "DELETE /api/webhooks/{id}", Tag: "Webhooks", Resumo: "Remover webhook (e o histórico de entregas)",
+		Status: http.StatusNoContent, Erros: []int{http.StatusForbidden, http.StatusNotFound}},
+	{Rota: "GET /api/webhooks/{id}/entregas", Tag: "Webhooks", Resumo: "Log de entregas (mais recentes primeiro)",
+		Query: []parametroDoc{
+			{"status", "string", "pendente, entregue ou falhou"},
+			{"limite", "integer", "Quantidade (1 a 200, padrão 50)"},
+		},
+		Resposta: []model.WebhookEntrega{}, Erros: []int{http.StatusForbidden, http.StatusNotFound}},
+
+	// ---------- Notificações ----------
+	{Rota: "GET /api/notificacoes", Tag: "Notificações", Resumo: "Notificações do usuário (mais recentes primeiro)",
+		Descricao: "Tipos: importacao.concluida, estudante.aniversario, exportacao.pronta. " +
+			"nao_lidas conta todas as não lidas, independentemente do limite.",
+		Query: []parametroDoc{
+			{"nao_lidas", "boolean", "Apenas não lidas"},
+			{"limite", "integer", "Quantidade (1 a 100, padrão 30)"},
+		},
+		Resposta: model.ListaNotificacoes{}},
+	{Rota: "PUT /api/notificacoes/{id}/lida", Tag: "Notificações", Resumo: "Marcar notificação como lida",
+		Resposta: model.Notificacao{}, Erros: []int{http.StatusNotFound}},
+	{Rota: "GET /api/atividades", Tag: "Notificações", Resumo: "Feed de atividades recentes",
+		Descricao: "Derivado do log de auditoria: criação/edição/remoção de estudantes e criação/remoção de anos. " +
+			"Numa organização inclui as ações dos demais membros. Para a próxima página, envie proximo em ?antes=.",
+		Query: []parametroDoc{
+			{"limite", "integer", "Quantidade (1 a 100, padrão 20)"},
+			{"antes", "integer", "Cursor: itens com id menor que este"},
+			{"meus", "boolean", "Apenas as ações da própria conta"},
+		},
+		Resposta: model.FeedAtividades{}},
+
+	// ---------- Uploads ----------
+	{Rota: "POST /api/uploads", Tag: "Uploads", Resumo: "Enviar imagem (multipart)",
+		Descricao: "JPEG, PNG ou GIF; regravado em JPEG sem EXIF (maior lado até 1600 px) com miniatura " +
+			"quadrada de 256 px. Papel leitor: 403. Com antivírus ligado: 422 ARQUIVO_INFECTADO; 503 se ele estiver fora.",
+		Multipart: objeto("arquivo", esquemaArquivo), Status: http.StatusCreated,
+		Resposta: objeto("key", "string", "url", "string", "signed_url", "string",
+			"miniatura_url", "string", "miniatura_signed_url", "string"),
+		Erros: []int{http.StatusForbidden, http.StatusRequestEntityTooLarge, http.StatusUnsupportedMediaType,
+			http.StatusUnprocessableEntity, http.StatusServiceUnavailable}},
+	{Rota: "GET /api/uploads/assinar", Tag: "Uploads", Resumo: "URL assinada temporária (15 min)",
+		Query: []parametroDoc{
+			{"url", "string", "Caminho /uploads/... gravado em foto_url"},
+			{"key", "string", "Chave do arquivo (alternativa a url)"},
+		},
+		Resposta: objeto("signed_url", "string", "expires_in", "integer"),
+		Erros:    []int{http.StatusForbidden, http.StatusNotFound}},
+	{Rota: "GET /uploads/{key...}", Tag: "Uploads", Resumo: "Ler arquivo enviado",
+		Descricao: "Exige URL assinada (?exp=&sig=) ou o X-User-Email do dono do arquivo. " +
+			"Aceita Range (206) e If-None-Match (304); nomes gerados pela API saem com Cache-Control immutable.",
+		Query: []parametroDoc{
+			{"exp", "integer", "Expiração (unix) da URL assinada"},
+			{"sig", "string", "Assinatura HMAC"},
+		},
+		Resposta: esquemaArquivo, TipoConteudo: "application/octet-stream",
+		Erros: []int{http.StatusForbidden, http.StatusNotFound}},
+
+	// ---------- Desenvolvimento ----------
+	{Rota: "POST /api/dev/seed", Tag: "Desenvolvimento", Resumo: "Gerar estudantes fictícios (admin; só com APP_ENV=development)",
+		Descricao: "Cria N estudantes com nomes, CPFs válidos, datas e telefones realistas, distribuídos entre os anos " +
+			"do tenant (sem anos, cria 1º a 3º Ano). CPF/e-mail repetidos são pulados. Em produção a rota não existe (404).",
+		Corpo: model.SeedRequest{}, Status: http.StatusCreated, Resposta: objeto("solicitados", "integer", "criados", "integer"),
+		Erros: []int{http.StatusBadRequest, http.StatusForbidden}},
+
+	// ---------- Operação ----------
+	{Rota: "GET /livez", Tag: "Operação", Resumo: "Liveness probe", Publica: true,
+		Resposta: healthResposta{}},
+	{Rota: "GET /readyz", Tag: "Operação", Resumo: "Readiness probe (banco, migrations, storage)", Publica: true,
+		Resposta: healthResposta{}, Erros: []int{http.StatusServiceUnavailable}},
+	{Rota: "GET /healthz", Tag: "Operação", Resumo: "Health check legado (texto \"ok\")", Publica: true,
+		Resposta: esquemaDoc{"type": "string"}, TipoConteudo: "text/plain"},
+	{Rota: "GET /api/openapi.json", Tag: "Operação", Resumo: "Esta especificação (OpenAPI 3)", Publica: true,
+		Resposta: esquemaDoc{"type": "object"}},
+	{Rota: "GET /api/docs", Tag: "Operação", Resumo: "Swagger UI", Publica: true,
+		Resposta: esquemaDoc{"type": "string"}, TipoConteudo: "text/html"},
+}
@@ -0,0 +1,292 @@
+// ============================================================================
+// 📄 handler/organizacao_handler.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - Organização (escola) compartilhada entre usuários, com papéis por membro:
+//   * GET    /api/organizacao                       → organização atual + membros
+//   * POST   /api/organizacao                       → cria (chamador vira dono/admin)
+//   * POST   /api/organizacao/membros               → adiciona usuário existente (admin)
+//   * PUT    /api/organizacao/membros/{usuario_id}  → altera papel (admin)
+//   * DELETE /api/organizacao/membros/{usuario_id}  → remove membro (admin ou o próprio)
+//
+// 🔐 Autenticação/escopo
+// - `X-User-Email` obrigatório. Os dados da organização ficam no usuario_id do
+//   dono; usuarioIDFromHeader devolve esse id para todos os membros.
+// - Papel "leitor" é barrado em escritas por middleware.ExigirEscritaMiddleware.
+// - Toda mudança de vínculo/papel invalida o cache de acesso do usuário afetado.
+// ============================================================================
+
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+
+	"backend/internal/apierr"
+	"backend/internal/model"
+)
+
+// carregarOrganizacao busca a organização e seus membros.
+func carregarOrganizacao(ctx context.Context, db *sql.DB, orgID int) (model.Organizacao, error) {
+	o := model.Organizacao{ID: orgID, Membros: []model.Membro{}}
+	if err := db.QueryRowContext(ctx,
+		`SELECT nome, dono_id FROM organizacoes WHERE id=$1`, orgID,
+	).Scan(&o.Nome, &o.DonoID); err != nil {
+		return o, err
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT u.id, COALESCE(u.nome, ''), u.email, m.papel
+		  FROM organizacao_membros m
+		  JOIN usuarios u ON u.id = m.usuario_id
+		 WHERE m.organizacao_id = $1
+		 ORDER BY u.nome, u.id
+	`, orgID)
+	if err != nil {
+		return o, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var m model.Membro
+		if err := rows.Scan(&m.UsuarioID, &m.Nome, &m.Email, &m.Papel); err != nil {
+			return o, err
+		}
+		o.Membros = append(o.Membros, m)
+	}
+	return o, rows.Err()
+}
+
+// OrganizacaoHandler trata GET/POST /api/organizacao.
+//
+// Regras/erros:
+//   - 401 se não resolver usuário; 400 se JSON inválido.
+//   - GET: 404 se o usuário não pertencer a nenhuma organização.
+//   - POST: 409 se o usuário já pertencer a uma organização.
+func OrganizacaoHandler(db *sql.DB, acessos *model.CacheAcesso) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		acesso, err := acessoFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		switch r.Method {
+		case http.MethodGet:
+			if acesso.OrganizacaoID == 0 {
+				writeAPIError(w, http.StatusNotFound, apierr.UsuarioSemOrganizacao, "Usuário não pertence a uma organização")
+				return
+			}
+			o, err := carregarOrganizacao(ctx, db, acesso.OrganizacaoID)
+			if err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao buscar organização")
+				return
+			}
+			writeJSON(w, http.StatusOK, o)
+
+		case http.MethodPost:
+			if acesso.OrganizacaoID != 0 {
+				writeAPIError(w, http.StatusConflict, apierr.UsuarioJaPertenceOrg, model.ErrJaPertenceOrganizacao.Error())
+				return
+			}
+			var in model.OrganizacaoRequest
+			if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+				writeDecodeError(w, err)
+				return
+			}
+			in.Sanitize()
+			if err := in.Validate(); err != nil {
+				writeValidationError(w, err)
+				return
+			}
+
+			tx, err := db.BeginTx(ctx, nil)
+			if err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao iniciar transação")
+				return
+			}
+			defer func() { _ = tx.Rollback() }()
+
+			var orgID int
+			if err := tx.QueryRowContext(ctx,
+				`INSERT INTO organizacoes (nome, dono_id) VALUES ($1, $2) RETURNING id`,
+				in.Nome, acesso.UsuarioID,
+			).Scan(&orgID); err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao criar organização")
+				return
+			}
+			if _, err := tx.ExecContext(ctx,
+				`INSERT INTO organizacao_membros (organizacao_id, usuario_id, papel) VALUES ($1, $2, $3)`,
+				orgID, acesso.UsuarioID, model.PapelAdmin,
+			); err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao vincular dono à organização")
+				return
+			}
+			if err := tx.Commit(); err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao confirmar criação")
+				return
+			}
+			acessos.InvalidarUsuario(ctx, db, acesso.UsuarioID)
+
+			o, err := carregarOrganizacao(ctx, db, orgID)
+			if err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao buscar organização")
+				return
+			}
+			writeJSON(w, http.StatusCreated, o)
+
+		default:
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+		}
+	}
+}
+
+// OrganizacaoMembrosHandler despacha /api/organizacao/membros[/{usuario_id}].
+//
+// Regras/erros:
+//   - 401 se não resolver usuário; 404 se não houver organização.
+//   - 403 se o chamador não for admin (exceto DELETE de si mesmo).
+//   - 400 se tentar alterar/remover o dono; 404 se o membro não existir.
+//   - 409 ao adicionar usuário que já pertence a uma organização.
+func OrganizacaoMembrosHandler(db *sql.DB, acessos *model.CacheAcesso) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		acesso, err := acessoFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+		if acesso.OrganizacaoID == 0 {
+			writeAPIError(w, http.StatusNotFound, apierr.UsuarioSemOrganizacao, "Usuário não pertence a uma organização")
+			return
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		// Coleção: adiciona usuário já cadastrado
+		if r.PathValue("usuarioID") == "" {
+			if r.Method != http.MethodPost {
+				writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+				return
+			}
+			if !acesso.Admin() {
+				writeAPIError(w, http.StatusForbidden, apierr.SemPermissao, model.ErrSemPermissao.Error())
+				return
+			}
+			var in model.MembroRequest
+			if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+				writeDecodeError(w, err)
+				return
+			}
+			in.Sanitize()
+			if err := in.Validate(); err != nil {
+				writeValidationError(w, err)
+				return
+			}
+
+			var novoID int
+			err := db.QueryRowContext(ctx, `SELECT id FROM usuarios WHERE email=$1`, in.Email).Scan(&novoID)
+			if err == sql.ErrNoRows {
+				writeAPIError(w, http.StatusNotFound, apierr.UsuarioNaoEncontrado, "Usuário não encontrado")
+				return
+			}
+			if err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao buscar usuário")
+				return
+			}
+			_, err = db.ExecContext(ctx,
+				`INSERT INTO organizacao_membros (organizacao_id, usuario_id, papel) VALUES ($1, $2, $3)`,
+				acesso.OrganizacaoID, novoID, in.Papel,
+			)
+			if status, _, _, ok := mapPQError(err); ok {
+				writeAPIError(w, status, apierr.UsuarioJaPertenceOrg, model.ErrJaPertenceOrganizacao.Error())
+				return
+			}
+			if err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao adicionar membro")
+				return
+			}
+			acessos.InvalidarUsuario(ctx, db, novoID)
+			writeJSON(w, http.StatusCreated, model.Membro{UsuarioID: novoID, Email: in.Email, Papel: in.Papel})
+			return
+		}
+
+		// Item
+		membroID, ok := pathID(r, "usuarioID")
+		if !ok {
+			writeAPIError(w, http.StatusBadRequest, apierr.IDInvalido, "ID do membro inválido")
+			return
+		}
+		var donoID int
+		if err := db.QueryRowContext(ctx,
+			`SELECT dono_id FROM organizacoes WHERE id=$1`, acesso.OrganizacaoID,
+		).Scan(&donoID); err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao buscar organização")
+			return
+		}
+		if membroID == donoID {
+			writeAPIError(w, http.StatusBadRequest, apierr.Validacao, "O dono da organização não pode ser alterado ou removido")
+			return
+		}
+
+		switch r.Method {
+		case http.MethodPut:
+			if !acesso.Admin() {
+				writeAPIError(w, http.StatusForbidden, apierr.SemPermissao, model.ErrSemPermissao.Error())
+				return
+			}
+			var in struct {
+				Papel string `json:"papel"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+				writeDecodeError(w, err)
+				return
+			}
+			if !model.PapelValido(in.Papel) {
+				writeAPIError(w, http.StatusBadRequest, apierr.PapelInvalido, model.ErrPapelInvalido.Error())
+				return
+			}
+			res, err := db.ExecContext(ctx,
+				`UPDATE organizacao_membros SET papel=$1 WHERE organizacao_id=$2 AND usuario_id=$3`,
+				in.Papel, acesso.OrganizacaoID, membroID,
+			)
+			if err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao alterar papel")
+				return
+			}
+			if rows, _ := res.RowsAffected(); rows == 0 {
+				writeAPIError(w, http.StatusNotFound, apierr.MembroNaoEncontrado, "Membro não encontrado")
+				return
+			}
+			acessos.InvalidarUsuario(ctx, db, membroID)
+			writeJSON(w, http.StatusOK, map[string]any{"usuario_id": membroID, "papel": in.Papel})
+
+		case http.MethodDelete:
+			if !acesso.Admin() && membroID != acesso.UsuarioID {
+				writeAPIError(w, http.StatusForbidden, apierr.SemPermissao, model.ErrSemPermissao.Error())
+				return
+			}
+			res, err := db.ExecContext(ctx,
+				`DELETE FROM organizacao_membros WHERE organizacao_id=$1 AND usuario_id=$2`,
+				acesso.OrganizacaoID, membroID,
+			)
+			if err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao remover membro")
+				return
+			}
+			if rows, _ := res.RowsAffected(); rows == 0 {
+				writeAPIError(w, http.StatusNotFound, apierr.MembroNaoEncontrado, "Membro não encontrado")
+				return
+			}
+			acessos.InvalidarUsuario(ctx, db, membroID)
+			w.WriteHeader(http.StatusNoContent)
+
+		default:
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+		}
+	}
+}
@@ -0,0 +1,318 @@
+//
+// ======================================================================
+// 📌 handler/perfil_handler.go
+// ======================================================================
+// 🎯 Responsabilidade
+//    - Atualiza nome/foto e, opcionalmente, a senha do usuário logado.
+//    - Recebe a foto de perfil como arquivo (POST /api/perfil/foto), já
+//      recortada/reduzida pelo pacote imagem e gravada no storage.
+//    - Devolve o perfil do usuário logado (inclui `tutorial_visto`).
+//
+// 🔒 Autenticação
+//    - Todas as rotas exigem header `X-User-Email`; o perfil é sempre o do
+//      próprio usuário (busca de outras contas só em GET /api/admin/usuario).
+//
+// 🧱 Banco
+//    - Tabela `usuarios`: id, nome, email, foto_url, senha_hash, tutorial_visto.
+//
+// 💡 Notas
+//    - Reutiliza helpers `writeJSON` e `writeJSONError` já definidos no package.
+//    - Usa `contextoBanco` (handler/timeout.go) para operações de banco.
+//    - Usa `model.MinPasswordLen` para validar a senha.
+// ======================================================================
+//
+
+package handler
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"backend/internal/antivirus"
+	"backend/internal/apierr"
+	"backend/internal/imagem"
+	"backend/internal/logging"
+	"backend/internal/middleware"
+	"backend/internal/model"
+	"backend/internal/storage"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// lado (px) da foto de perfil gravada por POST /api/perfil/foto
+const fotoPerfilLado = 512
+
+// ======================================================================
+// 🔄 Atualizar Perfil
+// ----------------------------------------------------------------------
+// PUT /api/perfil
+//
+// Body JSON (senha é opcional):
+//
+//	{ "nome": "...", "foto_url": "...", "fotoUrl": "...", "senha": "..." }
+//
+// Regras:
+//   - Nome >= 2 caracteres
+//   - Se senha vier preenchida: >= model.MinPasswordLen e sem espaços
+//   - Violações saem juntas num 422 (model.UpdatePerfilRequest.Validate)
+//   - 403 (PERSONIFICACAO_NAO_PERMITIDA) sob personificação: o suporte não troca
+//     nome, foto ou senha da conta personificada
+//
+// ======================================================================
+func AtualizarPerfilHandler(db *sql.DB) http.HandlerFunc {
+	type perfilInput struct {
+		Nome    string `json:"nome"`
+		FotoURL string `json:"foto_url"` // snake_case
+		FotoUrl string `json:"fotoUrl"`  // camelCase (compat)
+		Senha   string `json:"senha"`    // opcional
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+
+		// Autenticação via header
+		email := model.NormalizarEmail(r.Header.Get("X-User-Email"))
+		if email == "" {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+		if a, ok := middleware.AcessoDe(r.Context()); ok && a.PersonificadoPor != 0 {
+			writeAPIError(w, http.StatusForbidden, apierr.PersonificacaoNaoPermitida, "O suporte não altera o perfil da conta personificada")
+			return
+		}
+
+		// Decodifica JSON
+		var req perfilInput
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeDecodeError(w, err)
+			return
+		}
+
+		// Validações
+		nome := strings.TrimSpace(req.Nome)
+		senha := strings.TrimSpace(req.Senha)
+		if err := (model.UpdatePerfilRequest{Nome: &nome, Senha: &senha}).Validate(); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		// Foto: prioriza `foto_url`; se vazio e existir `fotoUrl`, usa-a
+		fotoFinal := strings.TrimSpace(req.FotoURL)
+		if fotoFinal == "" && strings.TrimSpace(req.FotoUrl) != "" {
+			fotoFinal = strings.TrimSpace(req.FotoUrl)
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		// Se senha foi enviada, validar e atualizar com hash
+		if s := senha; s != "" {
+			hash, err := bcrypt.GenerateFromPassword([]byte(s), bcrypt.DefaultCost)
+			if err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao processar senha")
+				return
+			}
+
+			res, err := db.ExecContext(ctx,
+				`UPDATE usuarios SET nome=$1, foto_url=$2, senha_hash=$3 WHERE email=$4`,
+				nome, fotoFinal, string(hash), email,
+			)
+			if err != nil {
+				logging.De(r.Context()).Error("perfil: falha ao atualizar (com senha)", "erro", err)
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao atualizar perfil")
+				return
+			}
+			if rows, _ := res.RowsAffected(); rows == 0 {
+				writeAPIError(w, http.StatusNotFound, apierr.UsuarioNaoEncontrado, "Usuário não encontrado")
+				return
+			}
+		} else {
+			// Atualiza sem senha
+			res, err := db.ExecContext(ctx,
+				`UPDATE usuarios SET nome=$1, foto_url=$2 WHERE email=$3`,
+				nome, fotoFinal, email,
+			)
+			if err != nil {
+				logging.De(r.Context()).Error("perfil: falha ao atualizar", "erro", err)
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao atualizar perfil")
+				return
+			}
+			if rows, _ := res.RowsAffected(); rows == 0 {
+				writeAPIError(w, http.StatusNotFound, apierr.UsuarioNaoEncontrado, "Usuário não encontrado")
+				return
+			}
+		}
+
+		writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
+	}
+}
+
+// ======================================================================
+// 🔎 Perfil do Usuário Logado
+// ----------------------------------------------------------------------
+// GET /api/perfil
+// GET /api/usuario[?email=...] (legado; mesmo handler)
+//
+// Regras:
+//   - 401 sem usuário autenticado
+//   - ?email (legado) só é aceito se for o do próprio usuário; outro e-mail
+//     responde 403 sem revelar se a conta existe (o suporte usa
+//     GET /api/admin/usuario)
+//   - 200 + { id, nome, email, fotoUrl, tutorial_visto } (model.UserPublic)
+//
+// ======================================================================
+func PerfilHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		acesso, err := acessoFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+		if email := r.URL.Query().Get("email"); email != "" && model.NormalizarEmail(email) != model.NormalizarEmail(acesso.Email) {
+			writeAPIError(w, http.StatusForbidden, apierr.SemPermissao, "Só é possível consultar o próprio perfil")
+			return
+		}
+
+		// Mesmo formato de /login e /login/google
+		var user model.UserPublic
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		err = db.QueryRowContext(ctx, `
+			SELECT id,
+			       nome,
+			       email,
+			       COALESCE(foto_url, ''),
+			       COALESCE(tutorial_visto, false)
+			  FROM usuarios
+			 WHERE id=$1
+		`, acesso.UsuarioID).Scan(&user.ID, &user.Nome, &user.Email, &user.FotoURL, &user.TutorialVisto)
+
+		if err != nil {
+			if err == sql.ErrNoRows {
+				writeAPIError(w, http.StatusNotFound, apierr.UsuarioNaoEncontrado, "Usuário não encontrado")
+			} else {
+				logging.De(r.Context()).Error("perfil: falha ao consultar", "erro", err)
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao buscar usuário")
+			}
+			return
+		}
+
+		writeJSON(w, http.StatusOK, user)
+	}
+}
+
+// ======================================================================
+// 📷 Foto de Perfil
+// ----------------------------------------------------------------------
+// POST /api/perfil/foto (multipart, campo "arquivo")
+//
+// Regras:
+//   - JPEG, PNG ou GIF até 5 MiB, de 32 px a ~25 megapixels
+//   - Cortada no centro em quadrado, reduzida a fotoPerfilLado px e
+//     regravada em JPEG (sem EXIF) no storage, com a chave do upload comum
+//   - 401 sem usuário; 403 sob personificação; 400/413/415/422 para arquivo
+//     ausente, grande demais, formato não aceito ou dimensões fora do limite
+//   - Antes de processar, passa pelo antivírus (ANTIVIRUS_DRIVER): 422
+//     ARQUIVO_INFECTADO; 503 com o antivírus fora do ar
+//   - 200 + {foto_url, signed_url}; foto_url já fica gravada no perfil (a foto
+//     anterior vira órfã e sai na limpeza de uploads)
+//
+// ======================================================================
+func FotoPerfilHandler(db *sql.DB, st storage.Storage, av *antivirus.Verificador) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+		acesso, err := acessoFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+		if acesso.PersonificadoPor != 0 {
+			writeAPIError(w, http.StatusForbidden, apierr.PersonificacaoNaoPermitida, "O suporte não altera o perfil da conta personificada")
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize+(1<<20))
+		file, _, err := r.FormFile("arquivo")
+		if err != nil {
+			var maxErr *http.MaxBytesError
+			if errors.As(err, &maxErr) {
+				writeJSONError(w, http.StatusRequestEntityTooLarge, "Arquivo muito grande")
+				return
+			}
+			writeAPIError(w, http.StatusBadRequest, apierr.ArquivoInvalido, "Campo 'arquivo' ausente ou inválido")
+			return
+		}
+		defer file.Close()
+
+		data, err := io.ReadAll(io.LimitReader(file, maxUploadSize+1))
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, apierr.ArquivoInvalido, "Falha ao ler arquivo")
+			return
+		}
+		if len(data) > maxUploadSize {
+			writeJSONError(w, http.StatusRequestEntityTooLarge, "Arquivo muito grande")
+			return
+		}
+		if !verificarAntivirus(w, r, av, data) {
+			return
+		}
+
+		foto, err := imagem.FotoPerfil(data, fotoPerfilLado)
+		switch {
+		case errors.Is(err, imagem.ErrFormato):
+			writeJSONError(w, http.StatusUnsupportedMediaType, err.Error())
+			return
+		case errors.Is(err, imagem.ErrDimensoes), errors.Is(err, imagem.ErrCorrompida):
+			writeAPIError(w, http.StatusUnprocessableEntity, apierr.ArquivoInvalido, err.Error())
+			return
+		case err != nil:
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao processar imagem")
+			return
+		}
+
+		key, err := novaChaveUpload(acesso.TenantID, ".jpg")
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao gerar nome do arquivo")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+		defer cancel()
+
+		if err := st.Put(ctx, key, bytes.NewReader(foto), "image/jpeg"); err != nil {
+			logging.De(ctx).Error("perfil: falha ao gravar foto", "erro", err)
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao salvar arquivo")
+			return
+		}
+		url := uploadsPublicPath + key
+		if _, err := db.ExecContext(ctx, `UPDATE usuarios SET foto_url=$1 WHERE id=$2`, url, acesso.UsuarioID); err != nil {
+			logging.De(ctx).Error("perfil: falha ao gravar foto_url", "erro", err)
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao atualizar perfil")
+			return
+		}
+		signed, err := st.SignedURL(ctx, key, signedURLTTL)
+		if err != nil {
+			signed = ""
+		}
+
+		writeJSON(w, http.StatusOK, map[string]string{
+			"foto_url":   url,
+			"signed_url": signed,
+		})
+	}
+}
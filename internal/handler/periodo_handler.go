@@ -0,0 +1,293 @@
+// ============================================================================
+// 📄 handler/periodo_handler.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - Períodos letivos (tabela: periodos_letivos), que agrupam anos/turmas:
+//   * GET  /api/periodos                  → lista períodos do usuário
+//   * POST /api/periodos                  → cria período (aberto)
+//   * PUT  /api/periodos/{id}/fechar      → encerra (bloqueia chamada/notas)
+//   * PUT  /api/periodos/{id}/abrir       → reabre
+//   * POST /api/periodos/{id}/clonar      → novo período com a mesma estrutura de anos
+//
+// 🔐 Autenticação/escopo
+// - `X-User-Email` obrigatório; períodos e anos filtrados por usuario_id.
+// ============================================================================
+
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+
+	"backend/internal/apierr"
+	"backend/internal/model"
+)
+
+// anoEmPeriodoFechado informa se o ano pertence a um período letivo encerrado.
+// Anos sem período vinculado nunca estão fechados.
+func anoEmPeriodoFechado(ctx context.Context, db *sql.DB, anoID int) (bool, error) {
+	var fechado bool
+	err := db.QueryRowContext(ctx, `
+		SELECT COALESCE(p.fechado, FALSE)
+		  FROM anos a
+		  LEFT JOIN periodos_letivos p ON p.id = a.periodo_letivo_id
+		 WHERE a.id = $1
+	`, anoID).Scan(&fechado)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return fechado, err
+}
+
+// periodoDoUsuario confirma que o período existe e pertence ao usuário, devolvendo se está fechado.
+func periodoDoUsuario(ctx context.Context, db *sql.DB, periodoID, uid int) (existe, fechado bool, err error) {
+	err = db.QueryRowContext(ctx,
+		`SELECT fechado FROM periodos_letivos WHERE id=$1 AND usuario_id=$2`,
+		periodoID, uid,
+	).Scan(&fechado)
+	if err == sql.ErrNoRows {
+		return false, false, nil
+	}
+	return err == nil, fechado, err
+}
+
+// PeriodosHandler trata GET/POST /api/periodos.
+//
+// Regras/erros:
+//   - 401 se não resolver usuário; 400 se JSON inválido.
+//   - 409 se já existir período com o mesmo nome.
+func PeriodosHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid, err := usuarioIDFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		switch r.Method {
+		case http.MethodGet:
+			rows, err := db.QueryContext(ctx, `
+				SELECT id, nome, COALESCE(to_char(inicio, 'YYYY-MM-DD'), ''),
+				       COALESCE(to_char(fim, 'YYYY-MM-DD'), ''), fechado
+				  FROM periodos_letivos
+				 WHERE usuario_id = $1
+				 ORDER BY inicio DESC NULLS LAST, id DESC
+			`, uid)
+			if err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao listar períodos")
+				return
+			}
+			defer rows.Close()
+
+			out := []model.PeriodoLetivo{}
+			for rows.Next() {
+				var p model.PeriodoLetivo
+				if err := rows.Scan(&p.ID, &p.Nome, &p.Inicio, &p.Fim, &p.Fechado); err != nil {
+					writeJSONError(w, http.StatusInternalServerError, "Erro ao ler período")
+					return
+				}
+				out = append(out, p)
+			}
+			if err := rows.Err(); err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao iterar períodos")
+				return
+			}
+			writeJSON(w, http.StatusOK, out)
+
+		case http.MethodPost:
+			in, ok := decodePeriodo(w, r)
+			if !ok {
+				return
+			}
+			p, err := inserirPeriodo(ctx, db, uid, in)
+			if status, code, msg, ok := mapPQError(err); ok {
+				writeAPIError(w, status, code, msg)
+				return
+			}
+			if err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao criar período")
+				return
+			}
+			writeJSON(w, http.StatusCreated, p)
+
+		default:
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+		}
+	}
+}
+
+// FecharPeriodoHandler trata PUT /api/periodos/{id}/fechar (fechado=true) e
+// PUT /api/periodos/{id}/abrir (fechado=false).
+//
+// Regras/erros:
+//   - 401 se não resolver usuário; 400 se id inválido.
+//   - 404 se o período não pertencer ao usuário.
+func FecharPeriodoHandler(db *sql.DB, fechado bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+		uid, err := usuarioIDFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+		id, ok := pathID(r, "id")
+		if !ok {
+			writeAPIError(w, http.StatusBadRequest, apierr.IDInvalido, "ID do período inválido")
+			return
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		res, err := db.ExecContext(ctx,
+			`UPDATE periodos_letivos SET fechado=$1 WHERE id=$2 AND usuario_id=$3`,
+			fechado, id, uid,
+		)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao atualizar período")
+			return
+		}
+		if rows, _ := res.RowsAffected(); rows == 0 {
+			writeAPIError(w, http.StatusNotFound, apierr.PeriodoNaoEncontrado, "Período letivo não encontrado")
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"id": id, "fechado": fechado})
+	}
+}
+
+// ClonarPeriodoHandler trata POST /api/periodos/{id}/clonar.
+//
+// Regras/erros:
+//   - 401 se não resolver usuário; 400 se id/JSON inválidos.
+//   - 404 se o período não pertencer ao usuário.
+func ClonarPeriodoHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+		uid, err := usuarioIDFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+		id, ok := pathID(r, "id")
+		if !ok {
+			writeAPIError(w, http.StatusBadRequest, apierr.IDInvalido, "ID do período inválido")
+			return
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		clonarPeriodo(ctx, w, r, db, id, uid)
+	}
+}
+
+// decodePeriodo decodifica, saneia e valida o payload; responde 400 em falha.
+func decodePeriodo(w http.ResponseWriter, r *http.Request) (model.PeriodoLetivoRequest, bool) {
+	var in model.PeriodoLetivoRequest
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeDecodeError(w, err)
+		return in, false
+	}
+	in.Sanitize()
+	if err := in.Validate(); err != nil {
+		writeValidationError(w, err)
+		return in, false
+	}
+	return in, true
+}
+
+// queryRower é satisfeito por *sql.DB e *sql.Tx.
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// inserirPeriodo cria o período (aberto) e devolve o registro gravado.
+func inserirPeriodo(ctx context.Context, q queryRower, uid int, in model.PeriodoLetivoRequest) (model.PeriodoLetivo, error) {
+	p := model.PeriodoLetivo{Nome: in.Nome, Inicio: in.Inicio, Fim: in.Fim}
+	err := q.QueryRowContext(ctx, `
+		INSERT INTO periodos_letivos (usuario_id, nome, inicio, fim)
+		VALUES ($1, $2, NULLIF($3,'')::date, NULLIF($4,'')::date)
+		RETURNING id
+	`, uid, in.Nome, in.Inicio, in.Fim).Scan(&p.ID)
+	return p, err
+}
+
+// clonarPeriodo cria um novo período copiando nome/ordem dos anos do período de origem
+// (sem estudantes, presenças ou notas). Responde 201 + { periodo, anos }.
+func clonarPeriodo(ctx context.Context, w http.ResponseWriter, r *http.Request, db *sql.DB, origemID, uid int) {
+	in, ok := decodePeriodo(w, r)
+	if !ok {
+		return
+	}
+	existe, _, err := periodoDoUsuario(ctx, db, origemID, uid)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao verificar período")
+		return
+	}
+	if !existe {
+		writeAPIError(w, http.StatusNotFound, apierr.PeriodoNaoEncontrado, "Período letivo não encontrado")
+		return
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao iniciar transação")
+		return
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	novo, err := inserirPeriodo(ctx, tx, uid, in)
+	if status, code, msg, ok := mapPQError(err); ok {
+		writeAPIError(w, status, code, msg)
+		return
+	}
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao criar período")
+		return
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		INSERT INTO anos (nome, usuario_id, ordem, periodo_letivo_id)
+		SELECT nome, usuario_id, ordem, $1
+		  FROM anos
+		 WHERE periodo_letivo_id = $2 AND usuario_id = $3 AND NOT arquivado AND excluido_em IS NULL
+		 ORDER BY ordem, id
+		RETURNING id, nome, ordem
+	`, novo.ID, origemID, uid)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao clonar anos")
+		return
+	}
+	anos := []Ano{}
+	for rows.Next() {
+		a := Ano{PeriodoLetivoID: novo.ID}
+		if err := rows.Scan(&a.ID, &a.Nome, &a.Ordem); err != nil {
+			rows.Close()
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao ler anos clonados")
+			return
+		}
+		anos = append(anos, a)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao clonar anos")
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao confirmar clonagem")
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]any{"periodo": novo, "anos": anos})
+}
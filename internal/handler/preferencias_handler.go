@@ -0,0 +1,82 @@
+// ============================================================================
+// 📄 handler/preferencias_handler.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - GET /api/usuario/preferencias → preferências de interface da conta
+//   (tema, ano_padrao, colunas das tabelas), com os padrões aplicados.
+// - PUT /api/usuario/preferencias → substitui o documento inteiro (campos
+//   omitidos voltam ao padrão); chaves desconhecidas são recusadas.
+//
+// 🔐 Autenticação/escopo
+// - `X-User-Email` obrigatório; preferências são da conta autenticada, e
+//   ano_padrao precisa ser um ano ativo do tenant.
+// ============================================================================
+
+package handler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"backend/internal/model"
+)
+
+// PreferenciasHandler trata GET/PUT /api/usuario/preferencias
+//
+// Regras/erros:
+//   - 401 se não resolver usuário; 400 se JSON inválido ou com chave desconhecida.
+//   - 422 (VALIDACAO) para tema/colunas fora do formato ou ano_padrao inexistente no tenant.
+//   - 200 + preferências (no PUT, as gravadas).
+func PreferenciasHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		acesso, err := acessoFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		switch r.Method {
+		case http.MethodGet:
+			p, err := model.CarregarPreferencias(ctx, db, acesso.UsuarioID, acesso.TenantID)
+			if err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao buscar preferências")
+				return
+			}
+			writeJSON(w, http.StatusOK, p)
+
+		case http.MethodPut:
+			var p model.Preferencias
+			dec := json.NewDecoder(r.Body)
+			dec.DisallowUnknownFields()
+			if err := dec.Decode(&p); err != nil {
+				writeDecodeError(w, err)
+				return
+			}
+			p.Normalizar()
+			if err := p.Validate(); err != nil {
+				writeValidationError(w, err)
+				return
+			}
+			err := model.SalvarPreferencias(ctx, db, acesso.UsuarioID, acesso.TenantID, p)
+			if errors.Is(err, model.ErrAnoPadraoInvalido) {
+				var ev model.ErrosValidacao
+				ev.Add("ano_padrao", model.RegraFormato, err)
+				writeValidationError(w, ev)
+				return
+			}
+			if err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao salvar preferências")
+				return
+			}
+			writeJSON(w, http.StatusOK, p)
+
+		default:
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+		}
+	}
+}
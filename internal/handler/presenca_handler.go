@@ -0,0 +1,385 @@
+// ============================================================================
+// 📄 handler/presenca_handler.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - Controle de frequência (tabela: presencas).
+//   * POST /api/turmas/{id}/chamada                → lança a chamada do dia
+//   * GET  /api/turmas/{id}/presencas/resumo       → % de presença por estudante
+//   * GET  /api/estudantes/{id}/presencas          → registros + resumo geral
+//   * GET  /api/estudantes/{id}/presencas/resumo   → % por período (mês/ano)
+//
+// 🧭 Convenção
+// - "Turma" aqui é o registro de `anos` (Ano/Turma, ex.: "8º A"); {id} = anos.id.
+// - Filtros opcionais ?de=YYYY-MM-DD&ate=YYYY-MM-DD em todas as leituras.
+//
+// 🔐 Autenticação/escopo
+// - `X-User-Email` obrigatório; turma e estudantes precisam pertencer ao usuário.
+// - Relançar a chamada de um dia sobrescreve as marcações (upsert por estudante+data).
+// ============================================================================
+
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"backend/internal/apierr"
+	"backend/internal/model"
+)
+
+// intervalo padrão quando ?de/?ate não são informados
+const (
+	presencaDataMin = "1900-01-01"
+	presencaDataMax = "9999-12-31"
+)
+
+// periodoFromQuery lê ?de e ?ate (YYYY-MM-DD) aplicando os limites padrão.
+func periodoFromQuery(r *http.Request) (de, ate string, ok bool) {
+	de, ate = r.URL.Query().Get("de"), r.URL.Query().Get("ate")
+	if de == "" {
+		de = presencaDataMin
+	}
+	if ate == "" {
+		ate = presencaDataMax
+	}
+	if _, err := time.Parse("2006-01-02", de); err != nil {
+		return "", "", false
+	}
+	if _, err := time.Parse("2006-01-02", ate); err != nil {
+		return "", "", false
+	}
+	return de, ate, true
+}
+
+// anoDoUsuario confirma que o Ano/Turma existe, pertence ao usuário e não está na lixeira.
+func anoDoUsuario(ctx context.Context, db *sql.DB, anoID, uid int) (bool, error) {
+	var ok bool
+	err := db.QueryRowContext(ctx,
+		`SELECT EXISTS(SELECT 1 FROM anos WHERE id=$1 AND usuario_id=$2 AND excluido_em IS NULL)`,
+		anoID, uid,
+	).Scan(&ok)
+	return ok, err
+}
+
+// turmaDoUsuario lê {id} da rota e confirma que o ano/turma pertence ao usuário.
+// Em erro já responde (400/401/404/500) e devolve ok=false.
+func turmaDoUsuario(ctx context.Context, w http.ResponseWriter, r *http.Request, db *sql.DB) (anoID, uid int, ok bool) {
+	uid, err := usuarioIDFromHeader(db, r)
+	if err != nil {
+		writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+		return 0, 0, false
+	}
+	anoID, valido := pathID(r, "id")
+	if !valido {
+		writeAPIError(w, http.StatusBadRequest, apierr.IDInvalido, "ID do ano/turma inválido")
+		return 0, 0, false
+	}
+	existe, err := anoDoUsuario(ctx, db, anoID, uid)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao verificar ano/turma")
+		return 0, 0, false
+	}
+	if !existe {
+		writeAPIError(w, http.StatusNotFound, apierr.AnoNaoEncontrado, "Ano/Turma não encontrado")
+		return 0, 0, false
+	}
+	return anoID, uid, true
+}
+
+// ChamadaTurmaHandler trata POST /api/turmas/{id}/chamada.
+//
+// Regras/erros:
+//   - 401 se não resolver usuário.
+//   - 400 se id/JSON inválidos ou estudante fora da turma.
+//   - 404 se a turma não pertencer ao usuário.
+func ChamadaTurmaHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		anoID, uid, ok := turmaDoUsuario(ctx, w, r, db)
+		if !ok {
+			return
+		}
+		registrarChamada(ctx, w, r, db, anoID, uid)
+	}
+}
+
+// ResumoPresencasTurmaHandler trata GET /api/turmas/{id}/presencas/resumo.
+//
+// Regras/erros:
+//   - 401 se não resolver usuário; 400 se id/período inválidos.
+//   - 404 se a turma não pertencer ao usuário.
+func ResumoPresencasTurmaHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		anoID, uid, ok := turmaDoUsuario(ctx, w, r, db)
+		if !ok {
+			return
+		}
+		resumoPresencasTurma(ctx, w, r, db, anoID, uid)
+	}
+}
+
+// registrarChamada valida o payload e faz upsert das marcações em transação.
+func registrarChamada(ctx context.Context, w http.ResponseWriter, r *http.Request, db *sql.DB, anoID, uid int) {
+	var in model.ChamadaRequest
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	in.Sanitize()
+	if err := in.Validate(); err != nil {
+		writeValidationError(w, err)
+		return
+	}
+
+	if fechado, err := anoEmPeriodoFechado(ctx, db, anoID); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao verificar período letivo")
+		return
+	} else if fechado {
+		writeAPIError(w, http.StatusConflict, apierr.PeriodoFechado, model.ErrPeriodoFechado.Error())
+		return
+	}
+
+	ids := make([]int64, len(in.Presencas))
+	for i, p := range in.Presencas {
+		ids[i] = int64(p.EstudanteID)
+	}
+
+	presentes := 0
+	err := model.ComTransacao(ctx, db, func(tx *sql.Tx) error {
+		presentes = 0
+
+		// Todos os estudantes precisam ser do usuário e da turma
+		var encontrados int
+		if err := tx.QueryRowContext(ctx, `
+			SELECT COUNT(*) FROM estudantes
+			 WHERE usuario_id=$1 AND ano_id=$2 AND id = ANY($3) AND excluido_em IS NULL
+		`, uid, anoID, model.Array(ids)).Scan(&encontrados); err != nil {
+			return err
+		}
+		if encontrados != len(ids) {
+			return falhar(http.StatusBadRequest, apierr.Validacao, "Há estudantes que não pertencem a esta turma")
+		}
+
+		for _, p := range in.Presencas {
+			if _, err := tx.ExecContext(ctx, `
+				INSERT INTO presencas (estudante_id, usuario_id, ano_id, data, presente, observacao)
+				VALUES ($1, $2, $3, $4, $5, $6)
+				ON CONFLICT (estudante_id, data)
+				DO UPDATE SET presente=EXCLUDED.presente, observacao=EXCLUDED.observacao, ano_id=EXCLUDED.ano_id
+			`, p.EstudanteID, uid, anoID, in.Data, p.Presente, p.Observacao); err != nil {
+				return err
+			}
+			if p.Presente {
+				presentes++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		escreverFalhaTx(w, err, "Erro ao registrar chamada")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"ano_id": anoID,
+		"data":   in.Data,
+		"resumo": model.NovoResumoPresenca(in.Data, len(in.Presencas), presentes),
+	})
+}
+
+// resumoPresencasTurma devolve o percentual de presença de cada estudante da turma.
+func resumoPresencasTurma(ctx context.Context, w http.ResponseWriter, r *http.Request, db *sql.DB, anoID, uid int) {
+	de, ate, ok := periodoFromQuery(r)
+	if !ok {
+		writeAPIError(w, http.StatusBadRequest, apierr.Validacao, "Período inválido (esperado YYYY-MM-DD)")
+		return
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT e.id, e.nome,
+		       COUNT(p.id),
+		       COUNT(p.id) FILTER (WHERE p.presente)
+		  FROM estudantes e
+		  LEFT JOIN presencas p
+		         ON p.estudante_id = e.id AND p.data BETWEEN $3 AND $4
+		 WHERE e.usuario_id=$1 AND e.ano_id=$2 AND e.excluido_em IS NULL
+		 GROUP BY e.id, e.nome
+		 ORDER BY e.nome ASC
+	`, uid, anoID, de, ate)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao calcular frequência")
+		return
+	}
+	defer rows.Close()
+
+	type item struct {
+		EstudanteID int    `json:"estudante_id"`
+		Nome        string `json:"nome"`
+		model.ResumoPresenca
+	}
+	out := []item{}
+	for rows.Next() {
+		var (
+			it               item
+			total, presentes int
+		)
+		if err := rows.Scan(&it.EstudanteID, &it.Nome, &total, &presentes); err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao ler frequência")
+			return
+		}
+		it.ResumoPresenca = model.NovoResumoPresenca("", total, presentes)
+		out = append(out, it)
+	}
+	if err := rows.Err(); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao iterar frequência")
+		return
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// PresencasEstudanteHandler trata GET /api/estudantes/{id}/presencas (resumo=false)
+// e GET /api/estudantes/{id}/presencas/resumo (resumo=true).
+//
+// Query:
+//   - ?de=&ate= (YYYY-MM-DD) limitam o período.
+//   - ?agrupar=mes|ano (apenas /resumo; padrão mes).
+func PresencasEstudanteHandler(db *sql.DB, resumo bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+
+		uid, err := usuarioIDFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+
+		estID, ok := pathID(r, "id")
+		if !ok {
+			writeAPIError(w, http.StatusBadRequest, apierr.IDInvalido, "ID do estudante inválido")
+			return
+		}
+		de, ate, ok := periodoFromQuery(r)
+		if !ok {
+			writeAPIError(w, http.StatusBadRequest, apierr.Validacao, "Período inválido (esperado YYYY-MM-DD)")
+			return
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		ok, err = estudanteDoUsuario(ctx, db, estID, uid)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao verificar estudante")
+			return
+		}
+		if !ok {
+			writeAPIError(w, http.StatusNotFound, apierr.EstudanteNaoEncontrado, "Estudante não encontrado")
+			return
+		}
+
+		if resumo {
+			resumoPresencasEstudante(ctx, w, r, db, estID, uid, de, ate)
+			return
+		}
+
+		rows, err := db.QueryContext(ctx, `
+			SELECT id, estudante_id, ano_id, to_char(data, 'YYYY-MM-DD'), presente, COALESCE(observacao,'')
+			  FROM presencas
+			 WHERE estudante_id=$1 AND usuario_id=$2 AND data BETWEEN $3 AND $4
+			 ORDER BY data ASC
+		`, estID, uid, de, ate)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao buscar presenças")
+			return
+		}
+		defer rows.Close()
+
+		registros := []model.Presenca{}
+		presentes := 0
+		for rows.Next() {
+			var p model.Presenca
+			if err := rows.Scan(&p.ID, &p.EstudanteID, &p.AnoID, &p.Data, &p.Presente, &p.Observacao); err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao ler presença")
+				return
+			}
+			if p.Presente {
+				presentes++
+			}
+			registros = append(registros, p)
+		}
+		if err := rows.Err(); err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao iterar presenças")
+			return
+		}
+
+		writeJSON(w, http.StatusOK, map[string]any{
+			"registros": registros,
+			"resumo":    model.NovoResumoPresenca("", len(registros), presentes),
+		})
+	}
+}
+
+// resumoPresencasEstudante agrega o percentual por mês ("YYYY-MM") ou ano ("YYYY").
+func resumoPresencasEstudante(ctx context.Context, w http.ResponseWriter, r *http.Request, db *sql.DB, estID, uid int, de, ate string) {
+	formato := "YYYY-MM"
+	switch r.URL.Query().Get("agrupar") {
+	case "", "mes":
+	case "ano":
+		formato = "YYYY"
+	default:
+		writeAPIError(w, http.StatusBadRequest, apierr.Validacao, "agrupar deve ser 'mes' ou 'ano'")
+		return
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT to_char(data, $5) AS periodo,
+		       COUNT(*),
+		       COUNT(*) FILTER (WHERE presente)
+		  FROM presencas
+		 WHERE estudante_id=$1 AND usuario_id=$2 AND data BETWEEN $3 AND $4
+		 GROUP BY periodo
+		 ORDER BY periodo ASC
+	`, estID, uid, de, ate, formato)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao calcular frequência")
+		return
+	}
+	defer rows.Close()
+
+	out := []model.ResumoPresenca{}
+	for rows.Next() {
+		var (
+			periodo          string
+			total, presentes int
+		)
+		if err := rows.Scan(&periodo, &total, &presentes); err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao ler frequência")
+			return
+		}
+		out = append(out, model.NovoResumoPresenca(periodo, total, presentes))
+	}
+	if err := rows.Err(); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao iterar frequência")
+		return
+	}
+	writeJSON(w, http.StatusOK, out)
+}
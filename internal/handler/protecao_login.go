@@ -0,0 +1,127 @@
+// ============================================================================
+// 📄 handler/protecao_login.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - Dificultar enumeração de contas e força bruta em POST /login:
+//   * compararSenha gasta o mesmo bcrypt com ou sem conta (hash fictício para
+//     e-mail desconhecido ou conta só-Google), igualando o tempo de resposta.
+//   * falhasLogin conta falhas recentes por e-mail e por IP; a partir da
+//     limiarFalhasLogin-ésima, cada nova tentativa espera um atraso crescente
+//     (exponencial, com jitter aleatório) antes de ser processada.
+//
+// 🔐 Autenticação/escopo
+// - Contagem em memória, por processo (várias réplicas contam separado) e
+//   zerada no restart; o login bem-sucedido limpa a contagem do e-mail.
+// - O atraso não bloqueia a conta: quem sabe a senha continua entrando.
+// ============================================================================
+
+package handler
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// parâmetros do atraso progressivo
+const (
+	limiarFalhasLogin = 3                // falhas toleradas sem atraso
+	janelaFalhasLogin = 15 * time.Minute // falha mais antiga que isso não conta
+	atrasoBaseLogin   = 250 * time.Millisecond
+	atrasoMaximoLogin = 5 * time.Second
+	maxChavesLogin    = 10000 // acima disso, descarta as entradas vencidas
+)
+
+// hashFicticio é comparado quando não há hash real (mesmo custo do cadastro).
+var hashFicticio = sync.OnceValue(func() []byte {
+	h, _ := bcrypt.GenerateFromPassword([]byte("senha-ficticia-para-tempo-constante"), bcrypt.DefaultCost)
+	return h
+})
+
+// falhas de login do processo (usadas por LoginHandler)
+var falhasDeLogin = &falhasLogin{m: map[string]registroFalhas{}}
+
+// registroFalhas é a contagem de uma chave (e-mail ou IP) dentro da janela.
+type registroFalhas struct {
+	n      int
+	ultima time.Time
+}
+
+// falhasLogin guarda as falhas recentes por chave ("email:..." / "ip:...").
+type falhasLogin struct {
+	mu sync.Mutex
+	m  map[string]registroFalhas
+}
+
+// compararSenha confere senha contra hash; sem hash (e-mail desconhecido ou conta
+// só-Google), compara com hashFicticio para gastar o mesmo tempo e devolve false.
+func compararSenha(hash, senha string) bool {
+	if hash == "" {
+		_ = bcrypt.CompareHashAndPassword(hashFicticio(), []byte(senha))
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(senha)) == nil
+}
+
+// esperar aplica o atraso devido às falhas recentes do e-mail e do IP (o maior
+// dos dois). Volta antes se ctx for cancelado.
+func (f *falhasLogin) esperar(ctx context.Context, email, ip string) {
+	d := max(f.atraso("email:"+email), f.atraso("ip:"+ip))
+	if d <= 0 {
+		return
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+	case <-ctx.Done():
+	}
+}
+
+// falhou registra uma tentativa malsucedida do e-mail e do IP.
+func (f *falhasLogin) falhou(email, ip string) {
+	agora := time.Now()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.m) >= maxChavesLogin {
+		for k, r := range f.m {
+			if agora.Sub(r.ultima) > janelaFalhasLogin {
+				delete(f.m, k)
+			}
+		}
+	}
+	for _, k := range []string{"email:" + email, "ip:" + ip} {
+		r := f.m[k]
+		if agora.Sub(r.ultima) > janelaFalhasLogin {
+			r.n = 0
+		}
+		f.m[k] = registroFalhas{n: r.n + 1, ultima: agora}
+	}
+}
+
+// sucesso limpa a contagem do e-mail (a do IP continua valendo para outras contas).
+func (f *falhasLogin) sucesso(email string) {
+	f.mu.Lock()
+	delete(f.m, "email:"+email)
+	f.mu.Unlock()
+}
+
+// atraso calcula a espera da chave: 0 até limiarFalhasLogin falhas; depois
+// atrasoBaseLogin dobrando a cada falha (até atrasoMaximoLogin), mais jitter de até
+// metade do valor.
+func (f *falhasLogin) atraso(chave string) time.Duration {
+	f.mu.Lock()
+	r, ok := f.m[chave]
+	f.mu.Unlock()
+	if !ok || r.n < limiarFalhasLogin || time.Since(r.ultima) > janelaFalhasLogin {
+		return 0
+	}
+	d := atrasoMaximoLogin
+	if exp := r.n - limiarFalhasLogin; exp < 5 {
+		d = min(atrasoBaseLogin<<exp, atrasoMaximoLogin)
+	}
+	return d + rand.N(d/2+1)
+}
@@ -0,0 +1,135 @@
+// ============================================================================
+// 📄 handler/relatorio_handler.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - POST /api/relatorios → relatório tabular de estudantes a partir de uma spec
+//   declarativa (model.RelatorioSpec): agrupar_por (ano, turma, status),
+//   metricas (quantidade, idade_media, idade_minima, idade_maxima), filtros e
+//   periodos de datas.
+//   * Resposta JSON {colunas, linhas}; ?formato=csv devolve o mesmo conteúdo
+//     como anexo CSV (exportação).
+//
+// 🔐 Autenticação/escopo
+// - `X-User-Email` obrigatório; apenas estudantes do próprio usuário (tenant).
+// - Somente leitura: POST apenas por causa do corpo, liberado também ao papel leitor.
+// - A consulta roda na réplica de leitura quando configurada (DATABASE_URL_RO).
+// ============================================================================
+
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"backend/internal/apierr"
+	"backend/internal/model"
+)
+
+// RelatorioHandler executa a spec e devolve o resultado.
+//
+// Regras/erros:
+//   - 405 se método != POST; 401 se não resolver usuário.
+//   - 400 se JSON inválido ou ?formato fora de json|csv; 422 (VALIDACAO) para spec inválida.
+//   - 200 + {colunas, linhas} (ou text/csv com cabeçalho = colunas).
+func RelatorioHandler(db *sql.DB, replica *model.Replica) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+
+		uid, err := usuarioIDFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+
+		formato := r.URL.Query().Get("formato")
+		if formato != "" && formato != "json" && formato != "csv" {
+			writeAPIError(w, http.StatusBadRequest, apierr.Validacao, "Parâmetro formato inválido (json ou csv)")
+			return
+		}
+
+		var spec model.RelatorioSpec
+		if err := json.NewDecoder(r.Body).Decode(&spec); err != nil {
+			writeDecodeError(w, err)
+			return
+		}
+		spec.Sanitize()
+		if err := spec.Validate(); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		ctx, cancel := contextoRelatorio(r)
+		defer cancel()
+
+		rel, err := executarRelatorio(ctx, replica, uid, spec)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao gerar relatório")
+			return
+		}
+
+		if formato == "csv" {
+			w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+			w.Header().Set("Content-Disposition", `attachment; filename="relatorio.csv"`)
+			cw := csv.NewWriter(w)
+			_ = cw.Write(rel.Colunas)
+			for _, linha := range rel.Linhas {
+				campos := make([]string, len(linha))
+				for i, v := range linha {
+					campos[i] = celulaCSV(v)
+				}
+				_ = cw.Write(campos)
+			}
+			cw.Flush()
+			return
+		}
+		writeJSON(w, http.StatusOK, rel)
+	}
+}
+
+// executarRelatorio roda a consulta da spec e monta as linhas (NULL vira nil).
+func executarRelatorio(ctx context.Context, replica *model.Replica, uid int, spec model.RelatorioSpec) (model.Relatorio, error) {
+	rel := model.Relatorio{Colunas: spec.Colunas(), Linhas: [][]any{}}
+	query, args := spec.SQL(uid)
+	rows, err := replica.Query(ctx, query, args...)
+	if err != nil {
+		return rel, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		linha := make([]any, len(rel.Colunas))
+		ptrs := make([]any, len(linha))
+		for i := range linha {
+			ptrs[i] = &linha[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return rel, err
+		}
+		for i, v := range linha {
+			if b, ok := v.([]byte); ok {
+				linha[i] = string(b)
+			}
+		}
+		rel.Linhas = append(rel.Linhas, linha)
+	}
+	return rel, rows.Err()
+}
+
+// celulaCSV formata um valor do relatório (nil = vazio).
+func celulaCSV(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	default:
+		return fmt.Sprint(x)
+	}
+}
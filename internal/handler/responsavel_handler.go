@@ -0,0 +1,143 @@
+// ============================================================================
+// 📄 handler/responsavel_handler.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - CRUD de responsáveis (pais/tutores) aninhado no estudante (tabela: responsaveis).
+//   * GET    /api/estudantes/{id}/responsaveis
+//   * POST   /api/estudantes/{id}/responsaveis
+//   * PUT    /api/estudantes/{id}/responsaveis/{rid}
+//   * DELETE /api/estudantes/{id}/responsaveis/{rid}
+//
+// 🔐 Autenticação/escopo
+// - `X-User-Email` obrigatório; estudante e responsável filtrados por usuario_id.
+//
+// 🔒 Dados pessoais
+// - CPF e telefone cifrados em repouso com índice cego do CPF (EstudanteRepo,
+//   model/responsavel_repo.go); a API recebe e devolve texto puro.
+// ============================================================================
+
+package handler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+
+	"backend/internal/apierr"
+	"backend/internal/model"
+)
+
+// ResponsaveisEstudanteHandler despacha /api/estudantes/{id}/responsaveis[/{rid}].
+//
+// Regras/erros:
+//   - 401 se não resolver usuário; 400 se ids/JSON inválidos.
+//   - 404 se estudante/responsável não pertencer ao usuário.
+//   - 201 na criação; 200 na edição/listagem; 204 na remoção.
+func ResponsaveisEstudanteHandler(db *sql.DB, repo *model.EstudanteRepo) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid, err := usuarioIDFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+
+		estID, ok := pathID(r, "id")
+		if !ok {
+			writeAPIError(w, http.StatusBadRequest, apierr.IDInvalido, "ID do estudante inválido")
+			return
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		ok, err = estudanteDoUsuario(ctx, db, estID, uid)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao verificar estudante")
+			return
+		}
+		if !ok {
+			writeAPIError(w, http.StatusNotFound, apierr.EstudanteNaoEncontrado, "Estudante não encontrado")
+			return
+		}
+
+		// Coleção
+		if r.PathValue("rid") == "" {
+			switch r.Method {
+			case http.MethodGet:
+				lista, err := repo.ListarResponsaveis(ctx, uid, estID)
+				if err != nil {
+					writeJSONError(w, http.StatusInternalServerError, "Erro ao listar responsáveis")
+					return
+				}
+				writeJSON(w, http.StatusOK, lista)
+			case http.MethodPost:
+				in, ok := decodeResponsavel(w, r)
+				if !ok {
+					return
+				}
+				out, err := repo.CriarResponsavel(ctx, uid, estID, in)
+				if err != nil {
+					writeJSONError(w, http.StatusInternalServerError, "Erro ao criar responsável")
+					return
+				}
+				writeJSON(w, http.StatusCreated, out)
+			default:
+				writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			}
+			return
+		}
+
+		// Item
+		rid, ok := pathID(r, "rid")
+		if !ok {
+			writeAPIError(w, http.StatusBadRequest, apierr.IDInvalido, "ID do responsável inválido")
+			return
+		}
+		switch r.Method {
+		case http.MethodPut:
+			in, ok := decodeResponsavel(w, r)
+			if !ok {
+				return
+			}
+			editado, err := repo.AtualizarResponsavel(ctx, uid, estID, rid, in)
+			if err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao editar responsável")
+				return
+			}
+			if !editado {
+				writeAPIError(w, http.StatusNotFound, apierr.ResponsavelNaoEncontrado, "Responsável não encontrado")
+				return
+			}
+			writeJSON(w, http.StatusOK, model.Responsavel{ID: rid, EstudanteID: estID, Nome: in.Nome, CPF: in.CPF, Telefone: in.Telefone, Email: in.Email, Parentesco: in.Parentesco})
+		case http.MethodDelete:
+			res, err := db.ExecContext(ctx,
+				`DELETE FROM responsaveis WHERE id=$1 AND estudante_id=$2 AND usuario_id=$3`, rid, estID, uid)
+			if err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao remover responsável")
+				return
+			}
+			if rows, _ := res.RowsAffected(); rows == 0 {
+				writeAPIError(w, http.StatusNotFound, apierr.ResponsavelNaoEncontrado, "Responsável não encontrado")
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+		}
+	}
+}
+
+// decodeResponsavel decodifica, saneia e valida o payload; responde 400 em falha.
+func decodeResponsavel(w http.ResponseWriter, r *http.Request) (model.ResponsavelRequest, bool) {
+	var in model.ResponsavelRequest
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeDecodeError(w, err)
+		return in, false
+	}
+	in.Sanitize()
+	if err := in.Validate(); err != nil {
+		writeValidationError(w, err)
+		return in, false
+	}
+	return in, true
+}
@@ -0,0 +1,70 @@
+// ============================================================================
+// 📄 handler/seed_handler.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - Dados fictícios para desenvolvimento e demonstrações:
+//   * POST /api/dev/seed → cria N estudantes realistas no tenant (model.SemearEstudantes)
+// - Registrada só com APP_ENV=development (em produção a rota não existe: 404).
+//
+// 🔐 Autenticação/escopo
+// - `X-User-Email` obrigatório; os estudantes vão para o tenant do usuário.
+// - Apenas admin (ou usuário sem organização): 403 para os demais.
+// ============================================================================
+
+package handler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"backend/internal/apierr"
+	"backend/internal/logging"
+	"backend/internal/model"
+)
+
+// SeedHandler trata POST /api/dev/seed (corpo opcional: {"estudantes": N}).
+//
+// Regras/erros:
+//   - 401 se não resolver usuário; 403 se não for admin.
+//   - 400 se JSON inválido ou estudantes fora de 1..model.MaxEstudantesSeed.
+//   - 500 se a gravação falhar (os já criados permanecem).
+//   - 201 + {"solicitados", "criados"}; CPF/e-mail repetidos no tenant são pulados.
+//
+// Roda no prazo de relatórios (DB_TIMEOUT_REPORT): lotes grandes são lentos.
+func SeedHandler(db *sql.DB, repo *model.EstudanteRepo) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		acesso, err := acessoFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+		if !acesso.Admin() {
+			writeAPIError(w, http.StatusForbidden, apierr.SemPermissao, "Apenas administradores geram dados de teste")
+			return
+		}
+
+		var in model.SeedRequest
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil && err != io.EOF {
+			writeDecodeError(w, err)
+			return
+		}
+		in.Sanitize()
+		if err := in.Validate(); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		ctx, cancel := contextoRelatorio(r)
+		defer cancel()
+
+		criados, err := model.SemearEstudantes(ctx, db, repo, acesso.TenantID, in.Estudantes)
+		if err != nil {
+			logging.De(r.Context()).Error("seed de estudantes", "erro", err, "criados", criados)
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao gerar estudantes")
+			return
+		}
+		writeJSON(w, http.StatusCreated, map[string]int{"solicitados": in.Estudantes, "criados": criados})
+	}
+}
@@ -0,0 +1,114 @@
+// ============================================================================
+// 📄 handler/sessao_handler.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - Sessões por dispositivo da própria conta (tabela: sessoes):
+//   * GET    /api/usuario/sessoes      → sessões ativas (IP, user agent, último
+//     uso, validade); "atual" marca a da própria requisição
+//   * DELETE /api/usuario/sessoes/{id} → encerra a sessão (o dispositivo recebe
+//     401 SESSAO_INVALIDA na próxima requisição); a própria sessão = logout.
+//     Só desconecta de fato com AUTH_REQUIRE_SESSION=true: sem ele, o cliente que
+//     voltar ao X-User-Email continua autenticado.
+// - emitirSessao: usado por /login e /login/google para criar a sessão e
+//   devolver o token (Authorization: Bearer) na resposta.
+//
+// 🔐 Autenticação/escopo
+// - Sessão (Authorization: Bearer) ou `X-User-Email`; sempre a conta
+//   autenticada (numa organização, cada membro vê só as próprias sessões).
+// - Durante a personificação o suporte só lista: encerrar sessões responde 403.
+// ============================================================================
+
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"time"
+
+	"backend/internal/apierr"
+	"backend/internal/logging"
+	"backend/internal/middleware"
+	"backend/internal/model"
+)
+
+// validade das sessões emitidas no login (sobrescrita por ConfigurarSessoes na subida)
+var sessaoTTL = 30 * 24 * time.Hour
+
+// ConfigurarSessoes define a validade das sessões emitidas no login. Chamar antes de
+// registrar as rotas (não é seguro em paralelo com requisições).
+func ConfigurarSessoes(ttl time.Duration) {
+	sessaoTTL = ttl
+}
+
+// SessoesHandler despacha GET /api/usuario/sessoes e DELETE /api/usuario/sessoes/{id}.
+//
+// Regras/erros:
+//   - 401 se não resolver usuário; 405 para outros métodos; 400 se o id for inválido.
+//   - 403 (PERSONIFICACAO_NAO_PERMITIDA) ao encerrar sessão durante a personificação.
+//   - 404 (SESSAO_NAO_ENCONTRADA) se a sessão não for da conta ou já estiver encerrada.
+//   - 200 + lista na consulta; 204 no encerramento.
+func SessoesHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		acesso, err := acessoFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		switch r.Method {
+		case http.MethodGet:
+			lista, err := model.ListarSessoes(ctx, db, acesso.UsuarioID, acesso.SessaoID)
+			if err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao listar sessões")
+				return
+			}
+			writeJSON(w, http.StatusOK, lista)
+
+		case http.MethodDelete:
+			id, ok := pathID(r, "id")
+			if !ok {
+				writeAPIError(w, http.StatusBadRequest, apierr.IDInvalido, "ID da sessão inválido")
+				return
+			}
+			if acesso.PersonificadoPor != 0 {
+				writeAPIError(w, http.StatusForbidden, apierr.PersonificacaoNaoPermitida, "O suporte não encerra sessões da conta personificada")
+				return
+			}
+			encerrada, err := model.RevogarSessao(ctx, db, acesso.UsuarioID, int64(id))
+			if err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao encerrar sessão")
+				return
+			}
+			if !encerrada {
+				writeAPIError(w, http.StatusNotFound, apierr.SessaoNaoEncontrada, "Sessão não encontrada ou já encerrada")
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+
+		default:
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+		}
+	}
+}
+
+// ===== helpers =====
+
+// emitirSessao cria a sessão do login bem-sucedido e devolve o token. Sem sessão
+// o cliente não teria como se autenticar, então a falha vira 500 no login.
+func emitirSessao(ctx context.Context, repo model.UserRepository, r *http.Request, usuarioID int, metodo string) (string, error) {
+	token, err := repo.CriarSessao(ctx, model.NovaSessao{
+		UsuarioID: usuarioID,
+		Metodo:    metodo,
+		IP:        middleware.IPDe(r),
+		UserAgent: r.UserAgent(),
+		TTL:       sessaoTTL,
+	})
+	if err != nil {
+		logging.De(ctx).Error("sessões: falha ao emitir", "usuario_id", usuarioID, "metodo", metodo, "erro", err)
+	}
+	return token, err
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"database/sql/driver"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"backend/internal/middleware"
+)
+
+// Sessão encerrada (DELETE /api/usuario/sessoes/{id}) sai da consulta de ResolverSessao.
+// Com AUTH_REQUIRE_SESSION o dispositivo fica de fora: token recusado e X-User-Email
+// ignorado. Sem ele, só o token é barrado e o X-User-Email continua autenticando.
+func TestRevogacaoSoDesconectaComSessaoObrigatoria(t *testing.T) {
+	casos := []struct {
+		nome         string
+		exigirSessao bool
+		cabecalho    map[string]string
+		status       int
+		autenticado  bool
+	}{
+		{"token revogado", true, map[string]string{"Authorization": "Bearer tok-revogado"}, http.StatusUnauthorized, false},
+		{"token revogado sem modo sessão", false, map[string]string{"Authorization": "Bearer tok-revogado"}, http.StatusUnauthorized, false},
+		{"X-User-Email no modo sessão", true, map[string]string{"X-User-Email": "ana@escola.com"}, http.StatusOK, false},
+		{"X-User-Email sem modo sessão", false, map[string]string{"X-User-Email": "ana@escola.com"}, http.StatusOK, true},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			db := bancoDeTeste(t,
+				resultadoRoteiro{trecho: "FROM sessoes s", colunas: []string{"id", "email", "ultimo_uso"}}, // revogada: nenhuma linha
+				resultadoRoteiro{trecho: "LEFT JOIN organizacao_membros", colunas: []string{"id", "dono_id", "organizacao_id", "papel", "suporte"},
+					linhas: [][]driver.Value{{int64(7), int64(7), int64(0), "admin", false}}},
+			)
+			autenticado := false
+			rota := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, autenticado = middleware.AcessoDe(r.Context())
+			})
+			req := httptest.NewRequest(http.MethodGet, "/api/estudantes", nil)
+			for k, v := range c.cabecalho {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			middleware.Autenticacao(db, nil, c.exigirSessao)(rota).ServeHTTP(rec, req)
+			if rec.Code != c.status {
+				t.Fatalf("status = %d, esperado %d (corpo = %s)", rec.Code, c.status, rec.Body)
+			}
+			if c.status == http.StatusUnauthorized && !strings.Contains(rec.Body.String(), "SESSAO_INVALIDA") {
+				t.Errorf("corpo sem SESSAO_INVALIDA: %s", rec.Body)
+			}
+			if autenticado != c.autenticado {
+				t.Errorf("usuário resolvido = %v, esperado %v", autenticado, c.autenticado)
+			}
+		})
+	}
+}
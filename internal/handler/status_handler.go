@@ -0,0 +1,161 @@
+// ============================================================================
+// 📄 handler/status_handler.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - Ciclo de vida do estudante (ativo/transferido/formado):
+//   * GET /api/estudantes/{id}/status → status atual + histórico de mudanças
+//   * PUT /api/estudantes/{id}/status → muda o status (com motivo)
+// - Regras de transição ficam em model/estudante_status.go.
+//
+// 🔐 Autenticação/escopo
+// - `X-User-Email` obrigatório; estudante filtrado por usuario_id.
+// ============================================================================
+
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+
+	"backend/internal/apierr"
+	"backend/internal/model"
+)
+
+// StatusEstudanteHandler despacha /api/estudantes/{id}/status.
+//
+// Regras/erros:
+//   - 401 se não resolver usuário; 400 se id/payload inválido.
+//   - 404 se o estudante não pertencer ao usuário.
+//   - 409 se a transição não for permitida (ex.: formado → ativo).
+func StatusEstudanteHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid, err := usuarioIDFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+
+		estID, ok := pathID(r, "id")
+		if !ok {
+			writeAPIError(w, http.StatusBadRequest, apierr.IDInvalido, "ID do estudante inválido")
+			return
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		switch r.Method {
+		case http.MethodGet:
+			historicoStatus(ctx, w, db, estID, uid)
+		case http.MethodPut:
+			mudarStatus(ctx, w, r, db, estID, uid)
+		default:
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+		}
+	}
+}
+
+// historicoStatus responde { status, historico: [...] } (histórico do mais recente ao mais antigo).
+func historicoStatus(ctx context.Context, w http.ResponseWriter, db *sql.DB, estID, uid int) {
+	var atual string
+	err := db.QueryRowContext(ctx,
+		`SELECT status FROM estudantes WHERE id=$1 AND usuario_id=$2 AND excluido_em IS NULL`,
+		estID, uid,
+	).Scan(&atual)
+	if err == sql.ErrNoRows {
+		writeAPIError(w, http.StatusNotFound, apierr.EstudanteNaoEncontrado, "Estudante não encontrado")
+		return
+	}
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao buscar estudante")
+		return
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT de, para, motivo, to_char(criado_em, 'YYYY-MM-DD"T"HH24:MI:SSOF')
+		  FROM estudante_status_historico
+		 WHERE estudante_id=$1 AND usuario_id=$2
+		 ORDER BY criado_em DESC, id DESC
+	`, estID, uid)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao buscar histórico")
+		return
+	}
+	defer rows.Close()
+
+	historico := []model.HistoricoStatus{}
+	for rows.Next() {
+		var h model.HistoricoStatus
+		if err := rows.Scan(&h.De, &h.Para, &h.Motivo, &h.CriadoEm); err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao ler histórico")
+			return
+		}
+		historico = append(historico, h)
+	}
+	if err := rows.Err(); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao iterar histórico")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"status": atual, "historico": historico})
+}
+
+// mudarStatus valida a transição e grava status + histórico na mesma transação.
+func mudarStatus(ctx context.Context, w http.ResponseWriter, r *http.Request, db *sql.DB, estID, uid int) {
+	var in model.MudancaStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	in.Sanitize()
+	if err := in.Validate(); err != nil {
+		writeValidationError(w, err)
+		return
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao iniciar transação")
+		return
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var atual string
+	err = tx.QueryRowContext(ctx,
+		`SELECT status FROM estudantes WHERE id=$1 AND usuario_id=$2 AND excluido_em IS NULL FOR UPDATE`,
+		estID, uid,
+	).Scan(&atual)
+	if err == sql.ErrNoRows {
+		writeAPIError(w, http.StatusNotFound, apierr.EstudanteNaoEncontrado, "Estudante não encontrado")
+		return
+	}
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao buscar estudante")
+		return
+	}
+	if err := model.TransicaoPermitida(atual, in.Status); err != nil {
+		apierr.Escrever(w, http.StatusConflict, apierr.TransicaoStatus, err.Error(), map[string]string{"de": atual, "para": in.Status})
+		return
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE estudantes SET status=$1 WHERE id=$2`, in.Status, estID); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao atualizar status")
+		return
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO estudante_status_historico (estudante_id, usuario_id, de, para, motivo)
+		VALUES ($1, $2, $3, $4, $5)
+	`, estID, uid, atual, in.Status, in.Motivo); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao registrar histórico")
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Erro ao confirmar alteração")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"id": estID, "status": in.Status})
+}
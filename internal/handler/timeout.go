@@ -0,0 +1,48 @@
+// ============================================================================
+// 📄 handler/timeout.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - Prazo das chamadas ao banco por classe de operação: leitura (GET/HEAD),
+//   escrita (demais métodos) e relatório (relatórios, exportações, GraphQL).
+//   Valores de DB_TIMEOUT_READ/WRITE/REPORT, aplicados por ConfigurarTimeouts.
+//
+// 🔐 Autenticação/escopo
+// - O contexto deriva de r.Context(): cliente que desconecta cancela a
+//   consulta em andamento (o pgx envia o cancelamento ao Postgres).
+// - Listagem em streaming (GET /api/estudantes) usa prazo próprio (listagemTimeout).
+// ============================================================================
+
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// prazos padrão (sobrescritos por ConfigurarTimeouts na subida)
+var (
+	timeoutLeitura   = 5 * time.Second
+	timeoutEscrita   = 5 * time.Second
+	timeoutRelatorio = 30 * time.Second
+)
+
+// ConfigurarTimeouts define os prazos das classes de operação. Chamar antes de
+// registrar as rotas (não é seguro em paralelo com requisições).
+func ConfigurarTimeouts(leitura, escrita, relatorio time.Duration) {
+	timeoutLeitura, timeoutEscrita, timeoutRelatorio = leitura, escrita, relatorio
+}
+
+// contextoBanco devolve o contexto da requisição com o prazo de leitura (GET/HEAD)
+// ou de escrita (demais métodos).
+func contextoBanco(r *http.Request) (context.Context, context.CancelFunc) {
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		return context.WithTimeout(r.Context(), timeoutLeitura)
+	}
+	return context.WithTimeout(r.Context(), timeoutEscrita)
+}
+
+// contextoRelatorio devolve o contexto da requisição com o prazo de relatórios.
+func contextoRelatorio(r *http.Request) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(r.Context(), timeoutRelatorio)
+}
@@ -0,0 +1,52 @@
+// ============================================================================
+// 📄 handler/transacao.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - Ponte entre model.ComTransacao e as respostas HTTP: dentro do bloco
+//   transacional o handler devolve falhar(...) em vez de escrever a resposta,
+//   a transação é desfeita e escreverFalhaTx responde depois.
+//
+// 🔐 Autenticação/escopo
+// - Sem regras próprias; o bloco roda depois da autenticação do handler.
+// - O bloco pode ser repetido (conflito de concorrência): webhooks, feed de
+//   atividades e demais efeitos ficam para depois do commit.
+// ============================================================================
+
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"backend/internal/apierr"
+	"backend/internal/model"
+)
+
+// falhaTx é uma resposta de erro decidida dentro da transação (404, 409...).
+type falhaTx struct {
+	status  int
+	code    string // vazio = código genérico do status
+	msg     string
+	details any
+}
+
+func (f *falhaTx) Error() string { return f.msg }
+
+// falhar interrompe o bloco transacional com a resposta de erro indicada.
+func falhar(status int, code, msg string) error {
+	return &falhaTx{status: status, code: code, msg: msg}
+}
+
+// escreverFalhaTx responde o erro de model.ComTransacao: falhaTx como decidida;
+// conflito de concorrência que persistiu após as repetições, 409; demais, 500 com msgErro.
+func escreverFalhaTx(w http.ResponseWriter, err error, msgErro string) {
+	var f *falhaTx
+	switch {
+	case errors.As(err, &f):
+		apierr.Escrever(w, f.status, f.code, f.msg, f.details)
+	case model.ConflitoConcorrencia(err):
+		writeAPIError(w, http.StatusConflict, apierr.Conflito, "Operação concorrente em andamento; tente novamente")
+	default:
+		writeJSONError(w, http.StatusInternalServerError, msgErro)
+	}
+}
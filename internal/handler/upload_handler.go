@@ -0,0 +1,387 @@
+// ============================================================================
+// 📄 handler/upload_handler.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - Receber uploads de imagens (fotos de estudantes/perfil) e gravá-los no
+//   backend de armazenamento configurado (storage.Storage: local ou S3).
+// - Normalizar cada foto (imagem.FotoComMiniatura): EXIF/GPS removidos,
+//   orientação aplicada, JPEG limitado a maxLadoFoto e miniatura quadrada de
+//   ladoMiniatura gravada em storage.ChaveMiniatura(key).
+// - Servir os arquivos gravados em /uploads/{key}, independentemente do backend,
+//   com Range, ETag/If-None-Match e Cache-Control (http.ServeContent): nomes
+//   aleatórios (novaChaveUpload, exportações) nunca mudam de conteúdo e saem
+//   como `immutable` por um ano; os demais (legados) por 5 minutos.
+// - Emitir URLs assinadas de curta duração para uso em <img src>.
+// - Passar cada arquivo enviado pelo antivírus (verificarAntivirus, também
+//   usado por fotos de perfil e documentos) antes de gravar.
+//
+// 🔐 Autenticação
+// - POST /api/uploads e GET /api/uploads/assinar exigem `X-User-Email`.
+// - As chaves são prefixadas pelo id do dono: "{usuario_id}/{aleatório}.{ext}".
+// - GET /uploads/{key} só entrega o arquivo quando:
+//   * a URL traz assinatura válida (exp/sig), ou
+//   * o `X-User-Email` pertence ao dono (prefixo da chave ou foto_url de
+//     estudante/perfil do usuário — cobre arquivos legados sem prefixo).
+//   Caso contrário responde 403.
+// - Exportações de dados ("{usuario_id}/exports/{id}.zip") são do solicitante,
+//   não da organização: só ele lê, e só enquanto o pedido existir (tabela
+//   exportacoes, jobs.ExportacaoDisponivel).
+//
+// 📤 Formato das respostas
+// - 201 + JSON { key, url, signed_url, miniatura_url, miniatura_signed_url }
+//   no upload.
+// - `url` é o caminho estável (/uploads/...) que deve ir para foto_url; a
+//   miniatura é derivada dela e não precisa ser guardada.
+// ============================================================================
+
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/internal/antivirus"
+	"backend/internal/apierr"
+	"backend/internal/imagem"
+	"backend/internal/jobs"
+	"backend/internal/logging"
+	"backend/internal/model"
+	"backend/internal/storage"
+)
+
+// limite de tamanho de um upload (imagens)
+const maxUploadSize = 5 << 20 // 5 MiB
+
+// prefixo HTTP onde os arquivos são servidos (registrado em cmd/server/main.go)
+const uploadsPublicPath = "/uploads/"
+
+// validade padrão das URLs assinadas devolvidas ao frontend
+const signedURLTTL = 15 * time.Minute
+
+// nomes gerados aleatoriamente (hex, opcionalmente "_mini"): o conteúdo de
+// uma chave assim nunca muda, então pode ficar em cache sem revalidar
+var nomeUploadImutavel = regexp.MustCompile(`^[0-9a-f]{24,}(_mini)?\.[0-9a-z]+$`)
+
+// arquivos sem Seek (ex.: S3) até este tamanho são bufferizados para atender Range
+const maxBufferUpload = 16 << 20 // 16 MiB
+
+// dimensões gravadas: maior lado da foto e lado da miniatura quadrada (px)
+const (
+	maxLadoFoto   = 1600
+	ladoMiniatura = 256
+)
+
+// novaChaveUpload gera "{uid}/{hex aleatório}{ext}".
+func novaChaveUpload(uid int, ext string) (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return strconv.Itoa(uid) + "/" + hex.EncodeToString(b[:]) + ext, nil
+}
+
+// UploadHandler trata POST /api/uploads (multipart, campo "arquivo").
+//
+// Regras/erros:
+//   - 401 se não resolver usuário.
+//   - 400 se o multipart for inválido ou faltar o campo.
+//   - 413 se exceder maxUploadSize.
+//   - 415 se o conteúdo não for JPEG, PNG ou GIF.
+//   - 422 se a imagem for pequena/grande demais, estiver corrompida ou for
+//     recusada pelo antivírus.
+//   - 500 se o backend de armazenamento falhar.
+func UploadHandler(db *sql.DB, st storage.Storage, av *antivirus.Verificador) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+
+		uid, err := usuarioIDFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize+(1<<20))
+		file, _, err := r.FormFile("arquivo")
+		if err != nil {
+			var maxErr *http.MaxBytesError
+			if errors.As(err, &maxErr) {
+				writeJSONError(w, http.StatusRequestEntityTooLarge, "Arquivo muito grande")
+				return
+			}
+			writeAPIError(w, http.StatusBadRequest, apierr.ArquivoInvalido, "Campo 'arquivo' ausente ou inválido")
+			return
+		}
+		defer file.Close()
+
+		data, err := io.ReadAll(io.LimitReader(file, maxUploadSize+1))
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, apierr.ArquivoInvalido, "Falha ao ler arquivo")
+			return
+		}
+		if len(data) > maxUploadSize {
+			writeJSONError(w, http.StatusRequestEntityTooLarge, "Arquivo muito grande")
+			return
+		}
+
+		if !verificarAntivirus(w, r, av, data) {
+			return
+		}
+		foto, miniatura, err := imagem.FotoComMiniatura(data, maxLadoFoto, ladoMiniatura)
+		switch {
+		case errors.Is(err, imagem.ErrFormato):
+			writeJSONError(w, http.StatusUnsupportedMediaType, "Formato de imagem não suportado (use JPEG, PNG ou GIF)")
+			return
+		case errors.Is(err, imagem.ErrDimensoes), errors.Is(err, imagem.ErrCorrompida):
+			writeAPIError(w, http.StatusUnprocessableEntity, apierr.ArquivoInvalido, err.Error())
+			return
+		case err != nil:
+			logging.De(r.Context()).Error("upload: falha ao processar imagem", "erro", err)
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao processar imagem")
+			return
+		}
+
+		key, err := novaChaveUpload(uid, ".jpg")
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao gerar nome do arquivo")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+		defer cancel()
+
+		chaveMini := storage.ChaveMiniatura(key)
+		if err := st.Put(ctx, chaveMini, bytes.NewReader(miniatura), "image/jpeg"); err != nil {
+			logging.De(r.Context()).Error("upload: falha ao gravar miniatura", "erro", err)
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao salvar arquivo")
+			return
+		}
+		if err := st.Put(ctx, key, bytes.NewReader(foto), "image/jpeg"); err != nil {
+			logging.De(r.Context()).Error("upload: falha ao gravar arquivo", "erro", err)
+			_ = st.Delete(ctx, chaveMini)
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao salvar arquivo")
+			return
+		}
+		signed, err := st.SignedURL(ctx, key, signedURLTTL)
+		if err != nil {
+			signed = ""
+		}
+		signedMini, err := st.SignedURL(ctx, chaveMini, signedURLTTL)
+		if err != nil {
+			signedMini = ""
+		}
+
+		writeJSON(w, http.StatusCreated, map[string]string{
+			"key":                  key,
+			"url":                  uploadsPublicPath + key,
+			"signed_url":           signed,
+			"miniatura_url":        uploadsPublicPath + chaveMini,
+			"miniatura_signed_url": signedMini,
+		})
+	}
+}
+
+// verificarAntivirus passa data pelo antivírus (ANTIVIRUS_DRIVER) e responde quando o
+// arquivo não pode seguir: 422 ARQUIVO_INFECTADO, ou 503 com o antivírus fora do ar
+// (salvo ANTIVIRUS_FAIL_OPEN, que aceita sem verificar).
+func verificarAntivirus(w http.ResponseWriter, r *http.Request, av *antivirus.Verificador, data []byte) bool {
+	err := av.Verificar(r.Context(), data)
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, antivirus.ErrInfectado):
+		logging.De(r.Context()).Warn("upload: arquivo recusado pelo antivírus", "erro", err)
+		writeAPIError(w, http.StatusUnprocessableEntity, apierr.ArquivoInfectado, "Arquivo recusado pela verificação antivírus")
+		return false
+	case av.FalhaAberta():
+		logging.De(r.Context()).Error("upload: antivírus indisponível, arquivo aceito sem verificação", "erro", err)
+		return true
+	default:
+		logging.De(r.Context()).Error("upload: antivírus indisponível", "erro", err)
+		writeAPIError(w, http.StatusServiceUnavailable, apierr.Indisponivel, "Verificação antivírus indisponível; tente novamente em instantes")
+		return false
+	}
+}
+
+// signatureVerifier é implementado por backends que assinam URLs servidas
+// pelo próprio backend (ex.: storage.Local).
+type signatureVerifier interface {
+	VerifySignature(key, exp, sig string) bool
+}
+
+// usuarioPodeLerUpload verifica se o usuário é dono da chave.
+// Regras: exportações ("*/exports/*") só para o solicitante (acesso.UsuarioID, não o
+// tenant) enquanto o pedido estiver pronto e retido; demais chaves com prefixo
+// "{tenant}/", ou referência em estudantes.foto_url / usuarios.foto_url do próprio
+// usuário (arquivos legados sem prefixo).
+func usuarioPodeLerUpload(ctx context.Context, db *sql.DB, acesso model.Acesso, key string) bool {
+	if dono, ok := jobs.DonoExportacao(key); ok {
+		if dono != acesso.UsuarioID {
+			return false
+		}
+		disponivel, err := jobs.ExportacaoDisponivel(ctx, db, acesso.UsuarioID, key)
+		return err == nil && disponivel
+	}
+	uid := acesso.TenantID
+	if strings.HasPrefix(key, strconv.Itoa(uid)+"/") {
+		return true
+	}
+	var ok bool
+	err := db.QueryRowContext(ctx, `
+		SELECT EXISTS(SELECT 1 FROM estudantes WHERE usuario_id=$1 AND foto_url=$2)
+		    OR EXISTS(SELECT 1 FROM usuarios   WHERE id=$1         AND foto_url=$2)
+	`, uid, uploadsPublicPath+key).Scan(&ok)
+	return err == nil && ok
+}
+
+// AssinarUploadHandler trata GET /api/uploads/assinar?url=/uploads/{key}
+// (também aceita ?key=...). Retorna { signed_url, expires_in } para o dono.
+//
+// Regras/erros:
+//   - 401 se não resolver usuário.
+//   - 400 se a chave for inválida.
+//   - 403 se o arquivo não pertencer ao usuário.
+func AssinarUploadHandler(db *sql.DB, st storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+
+		acesso, err := acessoFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+
+		raw := r.URL.Query().Get("key")
+		if raw == "" {
+			raw = strings.TrimPrefix(r.URL.Query().Get("url"), uploadsPublicPath)
+		}
+		key, err := storage.CleanKey(raw)
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, apierr.ArquivoInvalido, "Arquivo inválido")
+			return
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		if !usuarioPodeLerUpload(ctx, db, acesso, key) {
+			writeJSONError(w, http.StatusForbidden, "Acesso negado")
+			return
+		}
+		signed, err := st.SignedURL(ctx, key, signedURLTTL)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao assinar URL")
+			return
+		}
+
+		writeJSON(w, http.StatusOK, map[string]any{
+			"signed_url": signed,
+			"expires_in": int(signedURLTTL.Seconds()),
+		})
+	}
+}
+
+// ServirUploadsHandler trata GET /uploads/{key...} lendo do backend de armazenamento.
+// O acesso exige URL assinada ou usuário dono (ver cabeçalho do arquivo).
+// O Content-Type é inferido pela extensão da chave; Range, If-None-Match e
+// If-Modified-Since ficam com http.ServeContent (206/304/416).
+func ServirUploadsHandler(db *sql.DB, st storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+		key, err := storage.CleanKey(r.PathValue("key"))
+		if err != nil {
+			writeAPIError(w, http.StatusNotFound, apierr.NaoEncontrado, "Arquivo não encontrado")
+			return
+		}
+
+		// 🔐 Assinatura válida ou dono autenticado
+		q := r.URL.Query()
+		allowed := false
+		if v, ok := st.(signatureVerifier); ok {
+			allowed = v.VerifySignature(key, q.Get("exp"), q.Get("sig"))
+		}
+		if !allowed {
+			if acesso, err := acessoFromHeader(db, r); err == nil {
+				ctx, cancel := contextoBanco(r)
+				allowed = usuarioPodeLerUpload(ctx, db, acesso, key)
+				cancel()
+			}
+		}
+		if !allowed {
+			writeJSONError(w, http.StatusForbidden, "Acesso negado")
+			return
+		}
+
+		rc, err := st.Get(r.Context(), key)
+		if errors.Is(err, storage.ErrNotFound) {
+			writeAPIError(w, http.StatusNotFound, apierr.NaoEncontrado, "Arquivo não encontrado")
+			return
+		}
+		if err != nil {
+			logging.De(r.Context()).Error("upload: falha ao ler arquivo", "erro", err)
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao ler arquivo")
+			return
+		}
+		defer rc.Close()
+
+		h := w.Header()
+		if ct := mime.TypeByExtension(path.Ext(key)); ct != "" {
+			h.Set("Content-Type", ct)
+		}
+		if nome := path.Base(key); nomeUploadImutavel.MatchString(nome) {
+			h.Set("Cache-Control", "private, max-age=31536000, immutable")
+			h.Set("ETag", `"`+nome+`"`)
+		} else {
+			h.Set("Cache-Control", "private, max-age=300")
+		}
+
+		var modificado time.Time
+		if f, ok := rc.(interface{ Stat() (fs.FileInfo, error) }); ok {
+			if info, err := f.Stat(); err == nil {
+				modificado = info.ModTime()
+			}
+		}
+		if rs, ok := rc.(io.ReadSeeker); ok {
+			http.ServeContent(w, r, "", modificado, rs)
+			return
+		}
+
+		// backend sem Seek: bufferiza arquivos pequenos; os maiores saem inteiros, sem Range
+		buf, err := io.ReadAll(io.LimitReader(rc, maxBufferUpload+1))
+		if err != nil {
+			logging.De(r.Context()).Error("upload: falha ao ler arquivo", "erro", err)
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao ler arquivo")
+			return
+		}
+		if len(buf) <= maxBufferUpload {
+			http.ServeContent(w, r, "", modificado, bytes.NewReader(buf))
+			return
+		}
+		h.Set("Accept-Ranges", "none")
+		if r.Method == http.MethodHead {
+			return
+		}
+		_, _ = io.Copy(w, io.MultiReader(bytes.NewReader(buf), rc))
+	}
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"backend/internal/model"
+)
+
+func TestUsuarioPodeLerUploadExportacao(t *testing.T) {
+	db := bancoDeTeste(t,
+		resultadoRoteiro{trecho: "FROM exportacoes", colunas: []string{"exists"}, linhas: [][]driver.Value{{true}}},
+	)
+	dono := model.Acesso{UsuarioID: 7, TenantID: 7}
+	membro := model.Acesso{UsuarioID: 8, TenantID: 7}
+
+	casos := []struct {
+		nome   string
+		acesso model.Acesso
+		key    string
+		pode   bool
+	}{
+		{"dono lê upload do tenant", dono, "7/abc.jpg", true},
+		{"membro lê upload do tenant", membro, "7/abc.jpg", true},
+		{"solicitante lê a própria exportação", dono, "7/exports/ff00.zip", true},
+		{"membro não lê a exportação do dono", membro, "7/exports/ff00.zip", false},
+		{"dono não lê a exportação do membro", dono, "8/exports/ff00.zip", false},
+		{"membro lê a própria exportação", membro, "8/exports/ff00.zip", true},
+		{"prefixo inválido", dono, "x/exports/ff00.zip", false},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			if got := usuarioPodeLerUpload(context.Background(), db, c.acesso, c.key); got != c.pode {
+				t.Errorf("usuarioPodeLerUpload(%+v, %q) = %v", c.acesso, c.key, got)
+			}
+		})
+	}
+}
+
+func TestUsuarioPodeLerUploadExportacaoVencida(t *testing.T) {
+	// pedido expirado, com erro ou inexistente: EXISTS falso
+	db := bancoDeTeste(t,
+		resultadoRoteiro{trecho: "FROM exportacoes", colunas: []string{"exists"}, linhas: [][]driver.Value{{false}}},
+	)
+	if usuarioPodeLerUpload(context.Background(), db, model.Acesso{UsuarioID: 7, TenantID: 7}, "7/exports/ff00.zip") {
+		t.Error("exportação sem pedido pronto liberada pelo prefixo")
+	}
+}
@@ -0,0 +1,334 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/handler/usuario_handler.go
+/// Responsabilidade: Handlers HTTP para cadastro, login e atualização do flag de tutorial do usuário.
+/// Dependências principais: database/sql (Postgres), backend/internal/model (DTOs), bcrypt (hash de senha), mapPQError (conflitos.go).
+/// Pontos de atenção:
+/// - Não há aplicação dos middlewares de validação em cmd/server/main.go para /register e /login; este handler faz validação "defensiva".
+/// - Divergência potencial com model.MinPasswordLen (6) — aqui exigimos 8 caracteres (alinhado ao frontend).
+/// - usuarios.email é CITEXT: `email = $1` já ignora maiúsculas/minúsculas e usa o índice único.
+/// - writeJSON / writeJSONError e contextoBanco são dependências implícitas deste pacote (definidas em outro arquivo do package).
+/// - /login, /login/google e GET /api/usuario respondem model.UserPublic ({id, nome, email, fotoUrl, tutorial_visto});
+///   com AUTH_LEGACY_RESPONSE=true, os logins voltam ao formato antigo de cada rota (respostaLogin).
+/// - Erros são propositadamente genéricos para não vazar detalhes sensíveis (e.g., distinção de usuário inexistente).
+*/
+
+// backend/internal/handler/usuario_handler.go
+package handler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/mail"
+	"strings"
+
+	"backend/internal/apierr"
+	"backend/internal/captcha"
+	"backend/internal/logging"
+	"backend/internal/middleware"
+	"backend/internal/model"
+	"backend/internal/notificador"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// -----------------------------------------------------------------------------
+// 🔹 POST /register
+//   - Cadastra novo usuário (nome, email, senha).
+//   - Valida dados, confere unicidade (case-insensitive) e salva hash da senha.
+//   - Respostas: 201 (ok), 400 (validação), 409 (e-mail já existe), 500 (erro).
+//
+// -----------------------------------------------------------------------------
+
+/**
+ * RegisterHandler registra novos usuários.
+ *
+ * Regras de validação (model.RegisterRequest.Validate; todas as violações num único 422):
+ * - Nome: trim e tamanho mínimo 2.
+ * - E-mail: validação via net/mail.ParseAddress (case-insensitive no banco).
+ * - Senha: mínimo 8 caracteres e sem espaços (alinhado ao frontend).
+ *
+ * Captcha:
+ * - Com CAPTCHA_DRIVER ligado, o campo "captcha" é conferido no provedor (backend/internal/captcha)
+ *   antes de tocar no banco: ausente/recusado → 400 CAPTCHA_INVALIDO; provedor fora → 502.
+ *
+ * Persistência:
+ * - Confere unicidade por e-mail (CITEXT, sem diferenciar caixa).
+ * - Hash de senha com bcrypt.DefaultCost.
+ * - Em conflito (unique constraint 23505), retorna 409.
+ *
+ * Notificação:
+ * - E-mail de boas-vindas (notificador.BoasVindas) em segundo plano; falha no envio não afeta a resposta.
+ *
+ * Erros e respostas:
+ * - 201 com {"ok": true} em sucesso.
+ * - 400/409/422/500/502 no envelope de erro padrão via writeJSONError/writeAPIError.
+ *
+ * Dependências:
+ * - contextoBanco (context deadline), writeJSON e writeJSONError (helpers locais do pacote).
+ */
+func RegisterHandler(db *sql.DB, nt *notificador.Notificador, appURL string, cv *captcha.Verificador) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req model.RegisterRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeDecodeError(w, err)
+			return
+		}
+
+		// Normaliza & valida (defensivo, mesmo com middleware)
+		req.Sanitize()
+		if err := req.Validate(); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		// Anti-robô: antes de qualquer consulta (não revela se o e-mail existe)
+		if err := cv.Verificar(r.Context(), req.Captcha, middleware.IPDe(r)); err != nil {
+			if errors.Is(err, captcha.ErrTokenAusente) || errors.Is(err, captcha.ErrRecusado) {
+				writeAPIError(w, http.StatusBadRequest, apierr.CaptchaInvalido, "Captcha inválido ou expirado. Tente novamente.")
+				return
+			}
+			logging.De(r.Context()).Error("cadastro: falha ao verificar captcha", "erro", err)
+			writeAPIError(w, http.StatusBadGateway, apierr.FalhaServicoExterno, "Não foi possível verificar o captcha")
+			return
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		// Confere unicidade (case-insensitive)
+		var exists bool
+		if err := db.QueryRowContext(ctx,
+			`SELECT EXISTS(SELECT 1 FROM usuarios WHERE email=$1)`, req.Email,
+		).Scan(&exists); err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao verificar e-mail")
+			return
+		}
+		if exists {
+			writeAPIError(w, http.StatusConflict, apierr.EmailJaCadastrado, "E-mail já cadastrado")
+			return
+		}
+
+		hash, err := bcrypt.GenerateFromPassword([]byte(req.Senha), bcrypt.DefaultCost)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao processar senha")
+			return
+		}
+
+		_, err = db.ExecContext(ctx,
+			`INSERT INTO usuarios (nome, email, senha_hash) VALUES ($1, $2, $3)`,
+			req.Nome, req.Email, string(hash),
+		)
+		if err != nil {
+			// corrida com outro cadastro do mesmo e-mail (índice único, ver conflitos.go)
+			if status, code, msg, ok := mapPQError(err); ok {
+				writeAPIError(w, status, code, msg)
+				return
+			}
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao salvar usuário")
+			return
+		}
+
+		nt.EnviarEmSegundoPlano(r.Context(), req.Email, notificador.BoasVindas,
+			notificador.DadosBoasVindas{Nome: req.Nome, AppURL: appURL})
+
+		writeJSON(w, http.StatusCreated, map[string]bool{"ok": true})
+	}
+}
+
+// -----------------------------------------------------------------------------
+// 🔹 POST /login
+//   - Autentica com email/senha.
+//   - Respostas: 200 (dados do usuário + token da sessão), 400/401/500.
+//
+// -----------------------------------------------------------------------------
+
+/**
+ * LoginHandler autentica o usuário por e-mail e senha.
+ *
+ * Validação:
+ * - E-mail com mail.ParseAddress.
+ * - Senha com mínimo 8 caracteres e sem espaços.
+ *
+ * Fluxo:
+ * - Busca usuário por e-mail (CITEXT; users.BuscarCredenciais, prepared statement).
+ * - Compara senha via bcrypt (compararSenha): e-mail desconhecido ou conta só-Google
+ *   gastam o mesmo tempo contra um hash fictício, sem revelar se a conta existe.
+ * - Após limiarFalhasLogin falhas recentes do e-mail ou do IP, cada tentativa espera
+ *   um atraso crescente com jitter antes de ser processada (protecao_login.go).
+ * - Registra a tentativa (sucesso, senha incorreta ou conta bloqueada) no histórico
+ *   de logins da conta (GET /api/usuario/logins); e-mail desconhecido não é registrado.
+ * - Em sucesso, emite a sessão do dispositivo (GET /api/usuario/sessoes) e retorna
+ *   model.RespostaLogin ({id, nome, email, fotoUrl, tutorial_visto, token}).
+ *
+ * Respostas:
+ * - 200 OK com o usuário e o token (legado=true: só {id, nome, email, fotoUrl}, formato anterior).
+ * - 400 para payload inválido.
+ * - 401 para credenciais incorretas.
+ * - 403 (CONTA_BLOQUEADA) para conta bloqueada pelo suporte (só após a senha conferir).
+ * - 500 para erros internos/DB.
+ *
+ * Observações:
+ * - Campo FotoURL vem de COALESCE(foto_url,'') no select.
+ * - E-mail retornado é o gravado na conta.
+ */
+func LoginHandler(users *model.SQLUserRepo, legado bool) http.HandlerFunc {
+	hashFicticio() // gera o hash fictício na subida, fora do tempo da primeira tentativa
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req model.LoginRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeDecodeError(w, err)
+			return
+		}
+		req.Sanitize()
+
+		if _, err := mail.ParseAddress(req.Email); err != nil {
+			writeAPIError(w, http.StatusBadRequest, apierr.Validacao, "E-mail inválido")
+			return
+		}
+		if len(req.Senha) < 8 || strings.Contains(req.Senha, " ") {
+			writeAPIError(w, http.StatusBadRequest, apierr.Validacao, "Senha inválida")
+			return
+		}
+
+		// Atraso progressivo após falhas recentes deste e-mail ou IP (protecao_login.go)
+		ip := middleware.IPDe(r)
+		falhasDeLogin.esperar(r.Context(), req.Email, ip)
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		cred, err := users.BuscarCredenciais(ctx, req.Email)
+		if err != nil && err != sql.ErrNoRows {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao verificar usuário")
+			return
+		}
+
+		// E-mail desconhecido compara com o hash fictício: mesmo tempo e mesma resposta
+		if !compararSenha(cred.SenhaHash, req.Senha) {
+			falhasDeLogin.falhou(req.Email, ip)
+			if err == nil {
+				registrarLogin(ctx, users, r, cred.ID, model.MetodoLoginSenha, model.MotivoLoginSenhaIncorreta)
+			}
+			writeAPIError(w, http.StatusUnauthorized, apierr.CredenciaisInvalidas, "E-mail ou senha incorretos")
+			return
+		}
+		falhasDeLogin.sucesso(req.Email)
+		// Só depois da senha: sem ela, não revela que a conta existe
+		if cred.Bloqueado {
+			registrarLogin(ctx, users, r, cred.ID, model.MetodoLoginSenha, model.MotivoLoginContaBloqueada)
+			writeAPIError(w, http.StatusForbidden, apierr.ContaBloqueada, "Conta bloqueada. Entre em contato com o suporte.")
+			return
+		}
+
+		registrarLogin(ctx, users, r, cred.ID, model.MetodoLoginSenha, "")
+		token, err := emitirSessao(ctx, users, r, cred.ID, model.MetodoLoginSenha)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao iniciar sessão")
+			return
+		}
+
+		writeJSON(w, http.StatusOK, respostaLogin(cred.Usuario().Public(), token, legado, true))
+	}
+}
+
+// respostaLogin é o corpo de sucesso de /login e /login/google (usuário + token
+// da sessão). Com legado (AUTH_LEGACY_RESPONSE), devolve o formato anterior de
+// cada rota: sem token e tutorial_visto e, no login Google (foto=false), também
+// sem fotoUrl.
+func respostaLogin(u model.UserPublic, token string, legado, foto bool) any {
+	switch {
+	case !legado:
+		return model.RespostaLogin{UserPublic: u, Token: token}
+	case foto:
+		return struct {
+			ID      int    `json:"id"`
+			Nome    string `json:"nome"`
+			Email   string `json:"email"`
+			FotoURL string `json:"fotoUrl"`
+		}{u.ID, u.Nome, u.Email, u.FotoURL}
+	default:
+		return struct {
+			ID    int    `json:"id"`
+			Nome  string `json:"nome"`
+			Email string `json:"email"`
+		}{u.ID, u.Nome, u.Email}
+	}
+}
+
+// -----------------------------------------------------------------------------
+// 🔹 PUT /api/usuario/{id}/tutorial
+//   - Marca/Desmarca `tutorial_visto`.
+//   - Aceita body opcional: { "tutorial_visto": <bool> } (default=true).
+//
+// -----------------------------------------------------------------------------
+
+/**
+ * MarcarTutorialVistoHandler atualiza o flag tutorial_visto de um usuário.
+ *
+ * Rota:
+ * - PUT /api/usuario/{id}/tutorial
+ *
+ * Regras:
+ * - {id} deve ser inteiro > 0.
+ * - Body opcional {"tutorial_visto": bool}; default=true quando ausente.
+ * - Espelha o valor na etapa "tutorial" do onboarding (GET /api/usuario/onboarding);
+ *   clientes novos usam PUT /api/usuario/onboarding/etapas/tutorial.
+ *
+ * Respostas:
+ * - 204 (No Content) em sucesso.
+ * - 400 para id inválido/JSON inválido.
+ * - 404 quando o usuário não for encontrado.
+ * - 405 para método diferente de PUT.
+ * - 500 em falhas de atualização.
+ *
+ */
+func MarcarTutorialVistoHandler(db *sql.DB) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+			return
+		}
+
+		// {id} vem do padrão da rota: PUT /api/usuario/{id}/tutorial
+		id, ok := pathID(r, "id")
+		if !ok {
+			writeAPIError(w, http.StatusBadRequest, apierr.IDInvalido, "id inválido")
+			return
+		}
+
+		var body struct {
+			TutorialVisto *bool `json:"tutorial_visto"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		val := true
+		if body.TutorialVisto != nil {
+			val = *body.TutorialVisto
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		res, err := db.ExecContext(ctx,
+			`UPDATE usuarios SET tutorial_visto=$1 WHERE id=$2`, val, id,
+		)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao atualizar")
+			return
+		}
+		if rows, _ := res.RowsAffected(); rows == 0 {
+			writeAPIError(w, http.StatusNotFound, apierr.UsuarioNaoEncontrado, "Usuário não encontrado")
+			return
+		}
+		// Mantém a etapa "tutorial" do onboarding em sincronia com o flag
+		if err := model.SincronizarTutorial(ctx, db, id, val); err != nil {
+			logging.De(ctx).Error("onboarding: falha ao sincronizar tutorial", "usuario_id", id, "erro", err)
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// TODO: considerar logs estruturados (com request id) para falhas 5xx.
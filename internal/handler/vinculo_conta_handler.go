@@ -0,0 +1,193 @@
+// ============================================================================
+// 📄 handler/vinculo_conta_handler.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - POST /api/usuario/definir-senha  → conta criada pelo Google (sem senha)
+//   passa a entrar também com e-mail/senha. Corpo: {senha, idToken}.
+// - POST /api/usuario/vincular-google → conta com senha passa a entrar também
+//   com o Google. Corpo: {senha (atual), idToken}.
+//
+// 🔐 Autenticação/escopo
+// - `X-User-Email` obrigatório, mas não basta: as duas rotas exigem um ID Token
+//   Google recém-emitido (GIS) e, para vincular, a senha atual. definir-senha só
+//   aceita o token da conta Google já vinculada.
+// - Recusadas sob personificação (403): o suporte não altera credenciais.
+// - Sem GOOGLE_CLIENT_ID configurado, respondem 500 GOOGLE_NAO_CONFIGURADO.
+// ============================================================================
+
+package handler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"backend/internal/apierr"
+	"backend/internal/logging"
+	"backend/internal/model"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// DefinirSenhaHandler trata POST /api/usuario/definir-senha
+//
+// Regras/erros:
+//   - 401 se não resolver usuário; 403 sob personificação; 400 se JSON inválido.
+//   - 422 (VALIDACAO) sem idToken ou com senha fora das regras do cadastro.
+//   - 401 (GOOGLE_TOKEN_INVALIDO) para token inválido; 503 (GOOGLE_INDISPONIVEL) com o Google fora.
+//   - 409 (SENHA_JA_DEFINIDA) se a conta já tem senha (trocar: PUT /api/perfil).
+//   - 409 (GOOGLE_CONTA_DIFERENTE) se a conta não for do Google ou o token for de
+//     outra conta Google.
+//   - 204 em sucesso.
+func DefinirSenhaHandler(db *sql.DB, clientID string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		acesso, ok := acessoCredenciais(w, r, db, clientID)
+		if !ok {
+			return
+		}
+
+		var req model.DefinirSenhaRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeDecodeError(w, err)
+			return
+		}
+		req.Sanitize()
+		if err := req.Validate(); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		c, err := validarTokenGoogle(r.Context(), req.IDToken, clientID)
+		if err != nil {
+			writeErroTokenGoogle(w, err)
+			return
+		}
+		hash, err := bcrypt.GenerateFromPassword([]byte(req.Senha), bcrypt.DefaultCost)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao processar senha")
+			return
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+		err = model.DefinirSenha(ctx, db, acesso.UsuarioID, c.Sub, string(hash))
+		switch {
+		case err == nil:
+		case errors.Is(err, model.ErrSenhaJaDefinida):
+			writeAPIError(w, http.StatusConflict, apierr.SenhaJaDefinida, err.Error())
+			return
+		case errors.Is(err, model.ErrGoogleNaoVinculado), errors.Is(err, model.ErrGoogleOutraConta):
+			writeAPIError(w, http.StatusConflict, apierr.GoogleContaDiferente, err.Error())
+			return
+		case errors.Is(err, model.ErrUsuarioNaoEncontrado):
+			writeAPIError(w, http.StatusNotFound, apierr.UsuarioNaoEncontrado, "Usuário não encontrado")
+			return
+		default:
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao definir senha")
+			return
+		}
+
+		logging.De(ctx).Info("conta: senha definida em conta Google", "usuario_id", acesso.UsuarioID)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// VincularGoogleHandler trata POST /api/usuario/vincular-google
+//
+// Regras/erros:
+//   - 401 se não resolver usuário; 403 sob personificação; 400 se JSON inválido.
+//   - 422 (VALIDACAO) sem senha ou idToken.
+//   - 409 (CONTA_SEM_SENHA) se a conta não tem senha (já entra pelo Google).
+//   - 401 (CREDENCIAIS_INVALIDAS) se a senha atual não conferir.
+//   - 401 (GOOGLE_TOKEN_INVALIDO) para token inválido; 503 (GOOGLE_INDISPONIVEL) com o Google fora.
+//   - 409 (GOOGLE_JA_VINCULADO) se a conta já está vinculada a outra conta Google;
+//     409 (REGISTRO_DUPLICADO) se a conta Google já é de outro usuário.
+//   - 204 em sucesso (vincular de novo a mesma conta Google também).
+func VincularGoogleHandler(db *sql.DB, users *model.SQLUserRepo, clientID string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		acesso, ok := acessoCredenciais(w, r, db, clientID)
+		if !ok {
+			return
+		}
+
+		var req model.VincularGoogleRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeDecodeError(w, err)
+			return
+		}
+		req.Sanitize()
+		if err := req.Validate(); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		ctxCred, cancelCred := contextoBanco(r)
+		cred, err := users.BuscarCredenciais(ctxCred, acesso.Email)
+		cancelCred()
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao verificar usuário")
+			return
+		}
+		if cred.SenhaHash == "" {
+			writeAPIError(w, http.StatusConflict, apierr.ContaSemSenha, model.ErrContaSemSenha.Error())
+			return
+		}
+		if bcrypt.CompareHashAndPassword([]byte(cred.SenhaHash), []byte(req.Senha)) != nil {
+			writeAPIError(w, http.StatusUnauthorized, apierr.CredenciaisInvalidas, "Senha incorreta")
+			return
+		}
+		c, err := validarTokenGoogle(r.Context(), req.IDToken, clientID)
+		if err != nil {
+			writeErroTokenGoogle(w, err)
+			return
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+		err = model.VincularGoogle(ctx, db, acesso.UsuarioID, c.Sub, c.Foto)
+		if errors.Is(err, model.ErrGoogleJaVinculado) {
+			writeAPIError(w, http.StatusConflict, apierr.GoogleJaVinculado, err.Error())
+			return
+		}
+		if errors.Is(err, model.ErrUsuarioNaoEncontrado) {
+			writeAPIError(w, http.StatusNotFound, apierr.UsuarioNaoEncontrado, "Usuário não encontrado")
+			return
+		}
+		if err != nil {
+			// conta Google já vinculada a outro usuário (usuarios_google_sub_*, ver conflitos.go)
+			if status, code, msg, ok := mapPQError(err); ok {
+				writeAPIError(w, status, code, msg)
+				return
+			}
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao vincular conta Google")
+			return
+		}
+
+		logging.De(ctx).Info("conta: Google vinculado", "usuario_id", acesso.UsuarioID)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// acessoCredenciais resolve o usuário das rotas que alteram credenciais e responde
+// 405/500/401/403 quando não podem seguir (método, sem GOOGLE_CLIENT_ID, sem usuário, personificação).
+func acessoCredenciais(w http.ResponseWriter, r *http.Request, db *sql.DB, clientID string) (model.Acesso, bool) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+		return model.Acesso{}, false
+	}
+	if clientID == "" {
+		writeAPIError(w, http.StatusInternalServerError, apierr.GoogleNaoConfigurado, "Servidor sem GOOGLE_CLIENT_ID configurado")
+		return model.Acesso{}, false
+	}
+	acesso, err := acessoFromHeader(db, r)
+	if err != nil {
+		writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+		return model.Acesso{}, false
+	}
+	if acesso.PersonificadoPor != 0 {
+		writeAPIError(w, http.StatusForbidden, apierr.PersonificacaoNaoPermitida, "O suporte não altera as credenciais da conta personificada")
+		return model.Acesso{}, false
+	}
+	return acesso, true
+}
@@ -0,0 +1,268 @@
+// ============================================================================
+// 📄 handler/webhook_handler.go
+// ============================================================================
+// 🎯 Responsabilidade
+// - Cadastro de webhooks do tenant e log de entregas:
+//   * GET    /api/webhooks                → lista (sem o segredo)
+//   * POST   /api/webhooks                → cadastra; devolve o segredo uma única vez
+//   * DELETE /api/webhooks/{id}           → remove (e o histórico de entregas)
+//   * GET    /api/webhooks/{id}/entregas  → últimas entregas (?status=, ?limite=)
+// - publicarEvento: usado pelos handlers de estudantes/anos após confirmar a operação.
+//
+// 🔐 Autenticação/escopo
+// - `X-User-Email` obrigatório; webhooks pertencem ao dono dos dados (tenant).
+// - Apenas admin (ou usuário sem organização) gerencia webhooks: 403 para os demais.
+// ============================================================================
+
+package handler
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"backend/internal/apierr"
+	"backend/internal/jobs"
+	"backend/internal/logging"
+	"backend/internal/model"
+)
+
+// limites do log de entregas
+const (
+	entregasLimitePadrao = 50
+	entregasLimiteMaximo = 200
+)
+
+// WebhooksHandler despacha /api/webhooks e /api/webhooks/{id}.
+//
+// Regras/erros:
+//   - 401 se não resolver usuário; 403 se não for admin.
+//   - 422 (VALIDACAO) para url/eventos/segredo inválidos; 404 se o webhook não for do tenant.
+//   - 201 na criação (com segredo); 200 na listagem; 204 na remoção.
+func WebhooksHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		acesso, err := acessoFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+		if !acesso.Admin() {
+			writeAPIError(w, http.StatusForbidden, apierr.SemPermissao, "Apenas administradores gerenciam webhooks")
+			return
+		}
+		uid := acesso.TenantID
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		switch r.Method {
+		case http.MethodGet:
+			rows, err := db.QueryContext(ctx, `
+				SELECT id, url, eventos, ativo, criado_em
+				  FROM webhooks
+				 WHERE usuario_id=$1
+				 ORDER BY id ASC
+			`, uid)
+			if err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao listar webhooks")
+				return
+			}
+			defer rows.Close()
+			out := []model.Webhook{}
+			for rows.Next() {
+				var wh model.Webhook
+				if err := rows.Scan(&wh.ID, &wh.URL, model.Array(&wh.Eventos), &wh.Ativo, &wh.CriadoEm); err != nil {
+					writeJSONError(w, http.StatusInternalServerError, "Erro ao ler webhooks")
+					return
+				}
+				out = append(out, wh)
+			}
+			if err := rows.Err(); err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao ler webhooks")
+				return
+			}
+			writeJSON(w, http.StatusOK, out)
+
+		case http.MethodPost:
+			var in model.WebhookRequest
+			if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+				writeDecodeError(w, err)
+				return
+			}
+			in.Sanitize()
+			if err := in.Validate(); err != nil {
+				writeValidationError(w, err)
+				return
+			}
+			if in.Segredo == "" {
+				if in.Segredo, err = novoSegredoWebhook(); err != nil {
+					writeJSONError(w, http.StatusInternalServerError, "Erro ao gerar segredo")
+					return
+				}
+			}
+			wh := model.Webhook{URL: in.URL, Eventos: in.Eventos, Ativo: true, Segredo: in.Segredo}
+			if err := db.QueryRowContext(ctx, `
+				INSERT INTO webhooks (usuario_id, url, segredo, eventos)
+				VALUES ($1, $2, $3, $4)
+				RETURNING id, criado_em
+			`, uid, in.URL, in.Segredo, model.Array(in.Eventos)).Scan(&wh.ID, &wh.CriadoEm); err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao cadastrar webhook")
+				return
+			}
+			writeJSON(w, http.StatusCreated, wh)
+
+		case http.MethodDelete:
+			id, ok := pathID(r, "id")
+			if !ok {
+				writeAPIError(w, http.StatusBadRequest, apierr.IDInvalido, "ID do webhook inválido")
+				return
+			}
+			res, err := db.ExecContext(ctx, `DELETE FROM webhooks WHERE id=$1 AND usuario_id=$2`, id, uid)
+			if err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao remover webhook")
+				return
+			}
+			if n, _ := res.RowsAffected(); n == 0 {
+				writeAPIError(w, http.StatusNotFound, apierr.WebhookNaoEncontrado, "Webhook não encontrado")
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+
+		default:
+			writeJSONError(w, http.StatusMethodNotAllowed, "Método não permitido")
+		}
+	}
+}
+
+// EntregasWebhookHandler trata GET /api/webhooks/{id}/entregas
+//
+// Regras/erros:
+//   - 401/403 como em WebhooksHandler; 404 se o webhook não for do tenant.
+//   - 400 para ?status fora de pendente|entregue|falhou ou ?limite fora de 1..200.
+//   - 200 + entregas mais recentes primeiro (padrão 50).
+func EntregasWebhookHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		acesso, err := acessoFromHeader(db, r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "Usuário não autenticado")
+			return
+		}
+		if !acesso.Admin() {
+			writeAPIError(w, http.StatusForbidden, apierr.SemPermissao, "Apenas administradores gerenciam webhooks")
+			return
+		}
+
+		id, ok := pathID(r, "id")
+		if !ok {
+			writeAPIError(w, http.StatusBadRequest, apierr.IDInvalido, "ID do webhook inválido")
+			return
+		}
+		status := r.URL.Query().Get("status")
+		switch status {
+		case "", model.EntregaPendente, model.EntregaEntregue, model.EntregaFalhou:
+		default:
+			writeJSONError(w, http.StatusBadRequest, "status inválido (pendente, entregue, falhou)")
+			return
+		}
+		limite := entregasLimitePadrao
+		if v := r.URL.Query().Get("limite"); v != "" {
+			limite, err = strconv.Atoi(v)
+			if err != nil || limite < 1 || limite > entregasLimiteMaximo {
+				writeJSONError(w, http.StatusBadRequest, "limite inválido (1 a 200)")
+				return
+			}
+		}
+
+		ctx, cancel := contextoBanco(r)
+		defer cancel()
+
+		var existe bool
+		if err := db.QueryRowContext(ctx,
+			`SELECT EXISTS(SELECT 1 FROM webhooks WHERE id=$1 AND usuario_id=$2)`,
+			id, acesso.TenantID,
+		).Scan(&existe); err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao verificar webhook")
+			return
+		}
+		if !existe {
+			writeAPIError(w, http.StatusNotFound, apierr.WebhookNaoEncontrado, "Webhook não encontrado")
+			return
+		}
+
+		rows, err := db.QueryContext(ctx, `
+			SELECT id, evento, status, tentativas, COALESCE(ultimo_status_http, 0), COALESCE(ultimo_erro, ''),
+			       criado_em, proxima_tentativa_em, entregue_em
+			  FROM webhook_entregas
+			 WHERE webhook_id=$1 AND ($2 = '' OR status = $2)
+			 ORDER BY id DESC
+			 LIMIT $3
+		`, id, status, limite)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao listar entregas")
+			return
+		}
+		defer rows.Close()
+
+		out := []model.WebhookEntrega{}
+		for rows.Next() {
+			var (
+				e       model.WebhookEntrega
+				proxima sql.NullTime
+				entrega sql.NullTime
+			)
+			if err := rows.Scan(&e.ID, &e.Evento, &e.Status, &e.Tentativas, &e.UltimoStatusHTTP, &e.UltimoErro,
+				&e.CriadoEm, &proxima, &entrega); err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "Erro ao ler entregas")
+				return
+			}
+			if e.Status == model.EntregaPendente && proxima.Valid {
+				e.ProximaTentativaEm = &proxima.Time
+			}
+			if entrega.Valid {
+				e.EntregueEm = &entrega.Time
+			}
+			out = append(out, e)
+		}
+		if err := rows.Err(); err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Erro ao ler entregas")
+			return
+		}
+		writeJSON(w, http.StatusOK, out)
+	}
+}
+
+// publicarEvento enfileira o evento para os webhooks do tenant. A operação principal
+// já foi confirmada, então uma falha aqui só é registrada em log.
+func publicarEvento(ctx context.Context, wh *jobs.Webhooks, uid int, evento string, dados any) {
+	if err := wh.Publicar(ctx, uid, evento, dados); err != nil {
+		logging.De(ctx).Error("webhooks: falha ao publicar evento", "evento", evento, "erro", err)
+	}
+}
+
+// eventoEstudante é o payload de estudante.created (sem CPF/telefone: o receptor
+// consulta a API se precisar deles).
+func eventoEstudante(e model.Estudante) map[string]any {
+	return map[string]any{
+		"id":              e.ID,
+		"nome":            e.Nome,
+		"email":           e.Email,
+		"data_nascimento": e.DataNascimento,
+		"foto_url":        e.FotoURL,
+		"ano_id":          e.AnoID,
+		"turma_id":        e.TurmaID,
+		"status":          e.Status,
+	}
+}
+
+// novoSegredoWebhook gera um segredo aleatório ("whsec_" + 64 hex).
+func novoSegredoWebhook() (string, error) {
+	var b [32]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b[:]), nil
+}
@@ -0,0 +1,85 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/i18n/i18n.go
+/// Responsabilidade: Idiomas das mensagens da API (pt-BR padrão e en): negociação pelo Accept-Language e textos traduzidos.
+/// Dependências principais: net/http, strconv, strings.
+/// Pontos de atenção:
+/// - O idioma escolhido vai no cabeçalho de resposta Content-Language (middleware.Idioma, antes de tudo), e é de lá que
+///   apierr.Escrever e os handlers o leem com DaResposta — mesmo esquema do X-Request-ID.
+/// - pt-BR é o idioma do código: mensagens específicas escritas nos handlers valem como estão; en troca pela tradução
+///   do código do erro (apierr) ou da regra/sentinela de validação (model).
+/// - Só traduz mensagens: códigos (code, rule) e dados do usuário nunca mudam.
+*/
+
+package i18n
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// Texto guarda as versões de uma mensagem em cada idioma suportado.
+type Texto struct {
+	PtBR string
+	EN   string
+}
+
+/// ============ Configurações & Constantes ============
+
+// Idiomas suportados (valores de Content-Language).
+const (
+	PtBR   = "pt-BR"
+	EN     = "en"
+	Padrao = PtBR
+)
+
+/// ============ Funções Públicas ============
+
+// Em devolve o texto no idioma pedido (pt-BR quando não houver tradução).
+func (t Texto) Em(idioma string) string {
+	if idioma == EN && t.EN != "" {
+		return t.EN
+	}
+	return t.PtBR
+}
+
+// Negociar escolhe o idioma de um Accept-Language ("en-US,en;q=0.9,pt;q=0.5"):
+// o de maior q entre pt* e en*; empate fica com o primeiro listado. Padrao
+// quando nenhum é aceito ou o cabeçalho está vazio.
+func Negociar(accept string) string {
+	melhor, melhorQ := Padrao, 0.0
+	for _, parte := range strings.Split(accept, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(parte), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = f
+		}
+		if q <= melhorQ {
+			continue
+		}
+		base, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		switch base {
+		case "pt":
+			melhor, melhorQ = PtBR, q
+		case "en":
+			melhor, melhorQ = EN, q
+		}
+	}
+	return melhor
+}
+
+// DaResposta devolve o idioma negociado para a resposta em w (Content-Language
+// definido por middleware.Idioma); Padrao fora da cadeia de middlewares.
+func DaResposta(w http.ResponseWriter) string {
+	if w.Header().Get("Content-Language") == EN {
+		return EN
+	}
+	return Padrao
+}
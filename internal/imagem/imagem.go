@@ -0,0 +1,243 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/imagem/imagem.go
+/// Responsabilidade: Normalizar fotos enviadas pelo usuário: decodifica JPEG/PNG/GIF, aplica a orientação EXIF, reduz (perfil: quadrado central; estudantes: foto limitada + miniatura quadrada) e regrava em JPEG.
+/// Dependências principais: image, image/draw, image/jpeg, image/png, image/gif, bytes, encoding/binary.
+/// Pontos de atenção:
+/// - Só biblioteca padrão: WebP não é decodificado nem codificado (ErrFormato).
+/// - As dimensões são lidas antes de decodificar (DecodeConfig): imagens acima de maxPixels são recusadas sem alocar o bitmap.
+/// - A saída é sempre JPEG: metadados (EXIF, GPS) do original não são copiados; transparência vira fundo branco.
+/// - A orientação EXIF (fotos de celular) é aplicada aos pixels antes de descartar o EXIF, senão a foto sairia deitada.
+/// - Redução por média de área (box filter), suficiente para fotos de cadastro; imagens menores que o lado pedido não são ampliadas.
+*/
+
+package imagem
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+
+	_ "image/gif" // decodificadores registrados para image.Decode
+	_ "image/png"
+)
+
+/// ============ Configurações & Constantes ============
+
+const (
+	maxPixels     = 25_000_000 // ~5000×5000: acima disso, recusa antes de decodificar
+	minLado       = 32         // menor lado aceito (px)
+	qualidadeJPEG = 85
+)
+
+var (
+	ErrFormato    = errors.New("formato de imagem não suportado (use JPEG, PNG ou GIF)")
+	ErrDimensoes  = errors.New("imagem muito pequena ou muito grande")
+	ErrCorrompida = errors.New("imagem inválida ou corrompida")
+)
+
+/// ============ Funções Públicas ============
+
+// FotoPerfil devolve o JPEG quadrado (até lado × lado) gerado a partir de dados.
+// ErrFormato, ErrDimensoes ou ErrCorrompida quando a imagem não pode ser usada.
+func FotoPerfil(dados []byte, lado int) ([]byte, error) {
+	src, err := decodificar(dados)
+	if err != nil {
+		return nil, err
+	}
+	return codificar(quadrado(src, lado))
+}
+
+// FotoComMiniatura devolve a foto inteira reduzida para caber em maxLado × maxLado
+// (proporção mantida) e a miniatura quadrada central de até ladoMiniatura, ambas em
+// JPEG sem metadados. Mesmos erros de FotoPerfil.
+func FotoComMiniatura(dados []byte, maxLado, ladoMiniatura int) (foto, miniatura []byte, err error) {
+	src, err := decodificar(dados)
+	if err != nil {
+		return nil, nil, err
+	}
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	out := src
+	if maior := max(w, h); maior > maxLado {
+		out = reduzir(src, max(w*maxLado/maior, 1), max(h*maxLado/maior, 1))
+	}
+	if foto, err = codificar(out); err != nil {
+		return nil, nil, err
+	}
+	if miniatura, err = codificar(quadrado(src, ladoMiniatura)); err != nil {
+		return nil, nil, err
+	}
+	return foto, miniatura, nil
+}
+
+/// ============ Funções Internas (helpers) ============
+
+// decodificar confere as dimensões, decodifica dados e devolve os pixels em RGBA
+// sobre fundo branco (achata transparência), já na orientação EXIF.
+func decodificar(dados []byte) (*image.RGBA, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(dados))
+	if errors.Is(err, image.ErrFormat) {
+		return nil, ErrFormato
+	}
+	if err != nil {
+		return nil, ErrCorrompida
+	}
+	if cfg.Width < minLado || cfg.Height < minLado || cfg.Width*cfg.Height > maxPixels {
+		return nil, ErrDimensoes
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(dados))
+	if err != nil {
+		return nil, ErrCorrompida
+	}
+	b := img.Bounds()
+	src := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(src, src.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(src, src.Bounds(), img, b.Min, draw.Over)
+	return orientar(src, orientacaoEXIF(dados)), nil
+}
+
+// codificar grava img em JPEG (qualidadeJPEG).
+func codificar(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: qualidadeJPEG}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// quadrado recorta o quadrado central de src e o reduz a lado × lado (sem ampliar).
+func quadrado(src *image.RGBA, lado int) *image.RGBA {
+	b := src.Bounds()
+	q := min(b.Dx(), b.Dy())
+	recorte := src.SubImage(image.Rect(0, 0, q, q).Add(image.Pt((b.Dx()-q)/2, (b.Dy()-q)/2))).(*image.RGBA)
+	out := image.NewRGBA(image.Rect(0, 0, q, q))
+	draw.Draw(out, out.Bounds(), recorte, recorte.Bounds().Min, draw.Src)
+	if q > lado {
+		out = reduzir(out, lado, lado)
+	}
+	return out
+}
+
+// reduzir faz a média de área de src para largura × altura (não amplia: ambos
+// menores ou iguais aos de src).
+func reduzir(src *image.RGBA, largura, altura int) *image.RGBA {
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	out := image.NewRGBA(image.Rect(0, 0, largura, altura))
+	for y := 0; y < altura; y++ {
+		y0, y1 := y*h/altura, (y+1)*h/altura
+		for x := 0; x < largura; x++ {
+			x0, x1 := x*w/largura, (x+1)*w/largura
+			var r, g, bl, n int
+			for sy := y0; sy < y1; sy++ {
+				i := src.PixOffset(x0, sy)
+				for sx := x0; sx < x1; sx++ {
+					r += int(src.Pix[i])
+					g += int(src.Pix[i+1])
+					bl += int(src.Pix[i+2])
+					n++
+					i += 4
+				}
+			}
+			j := out.PixOffset(x, y)
+			out.Pix[j] = uint8(r / n)
+			out.Pix[j+1] = uint8(g / n)
+			out.Pix[j+2] = uint8(bl / n)
+			out.Pix[j+3] = 0xff
+		}
+	}
+	return out
+}
+
+// orientar aplica a orientação EXIF o (1–8) a src: espelha e/ou gira para a
+// posição em que a foto deve ser vista. 1 ou desconhecida devolve src.
+func orientar(src *image.RGBA, o int) *image.RGBA {
+	if o < 2 || o > 8 {
+		return src
+	}
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	ow, oh := w, h
+	if o >= 5 { // 5–8 trocam largura e altura
+		ow, oh = h, w
+	}
+	out := image.NewRGBA(image.Rect(0, 0, ow, oh))
+	for y := 0; y < oh; y++ {
+		for x := 0; x < ow; x++ {
+			var sx, sy int
+			switch o {
+			case 2:
+				sx, sy = w-1-x, y
+			case 3:
+				sx, sy = w-1-x, h-1-y
+			case 4:
+				sx, sy = x, h-1-y
+			case 5:
+				sx, sy = y, x
+			case 6:
+				sx, sy = y, h-1-x
+			case 7:
+				sx, sy = w-1-y, h-1-x
+			case 8:
+				sx, sy = w-1-y, x
+			}
+			copy(out.Pix[out.PixOffset(x, y):][:4], src.Pix[src.PixOffset(sx, sy):][:4])
+		}
+	}
+	return out
+}
+
+// orientacaoEXIF lê a tag Orientation (0x0112) do IFD0 do EXIF de um JPEG.
+// Devolve 1 (normal) se não for JPEG, não houver EXIF ou ele estiver malformado.
+func orientacaoEXIF(dados []byte) int {
+	if len(dados) < 4 || dados[0] != 0xFF || dados[1] != 0xD8 {
+		return 1
+	}
+	for i := 2; i+4 <= len(dados) && dados[i] == 0xFF; {
+		marcador := dados[i+1]
+		tam := int(binary.BigEndian.Uint16(dados[i+2:]))
+		if marcador == 0xDA || tam < 2 || i+2+tam > len(dados) { // início dos dados da imagem
+			return 1
+		}
+		seg := dados[i+4 : i+2+tam]
+		if marcador == 0xE1 && len(seg) >= 14 && string(seg[:6]) == "Exif\x00\x00" {
+			return orientacaoTIFF(seg[6:])
+		}
+		i += 2 + tam
+	}
+	return 1
+}
+
+// orientacaoTIFF procura a tag Orientation no IFD0 de um bloco TIFF (EXIF).
+func orientacaoTIFF(t []byte) int {
+	var ordem binary.ByteOrder
+	switch string(t[:2]) {
+	case "II":
+		ordem = binary.LittleEndian
+	case "MM":
+		ordem = binary.BigEndian
+	default:
+		return 1
+	}
+	ifd := int(ordem.Uint32(t[4:]))
+	if ifd < 8 || ifd+2 > len(t) {
+		return 1
+	}
+	n := int(ordem.Uint16(t[ifd:]))
+	for k := 0; k < n; k++ {
+		e := ifd + 2 + 12*k
+		if e+12 > len(t) {
+			return 1
+		}
+		if ordem.Uint16(t[e:]) == 0x0112 {
+			if o := int(ordem.Uint16(t[e+8:])); o >= 1 && o <= 8 {
+				return o
+			}
+			return 1
+		}
+	}
+	return 1
+}
@@ -0,0 +1,165 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/jobs/aniversarios.go
+/// Responsabilidade: Job de aniversários: notificação in-app no dia do aniversário e resumo semanal por e-mail (segunda a domingo) para o dono dos dados.
+/// Dependências principais: database/sql (Postgres), backend/internal/model (aniversariantes, notificações), backend/internal/notificador.
+/// Pontos de atenção:
+/// - O resumo da semana sai na primeira varredura a partir de segunda-feira (horário local do servidor); semanas sem aniversariantes não geram e-mail.
+/// - resumos_enviados garante um resumo por usuário e semana mesmo com várias instâncias; falha no envio libera a reserva para a próxima varredura.
+/// - Notificações do dia usam a chave "aniversario:<data>:<estudante_id>" (sem duplicar a cada varredura).
+/// - Destinatário = dono dos dados (usuario_id dos estudantes); membros da organização não recebem o resumo.
+*/
+
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"backend/internal/model"
+	"backend/internal/notificador"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// Aniversarios gera as notificações do dia e envia o resumo semanal.
+type Aniversarios struct {
+	DB          *sql.DB
+	Notificador *notificador.Notificador
+	AppURL      string        // link do e-mail
+	Interval    time.Duration // intervalo entre varreduras (<= 0 desativa)
+}
+
+/// ============ Configurações & Constantes ============
+
+// tipo gravado em resumos_enviados
+const resumoAniversariosSemanal = "aniversarios_semana"
+
+/// ============ Funções Públicas ============
+
+// Run executa o job em laço até ctx ser cancelado (primeira varredura logo na subida).
+func (a *Aniversarios) Run(ctx context.Context) {
+	if a.Interval <= 0 {
+		return
+	}
+	slog.Info("aniversarios: ativo", "intervalo", a.Interval.String())
+	t := time.NewTicker(a.Interval)
+	defer t.Stop()
+	for {
+		notificados, resumos, err := a.RunOnce(ctx, time.Now())
+		if err != nil {
+			slog.Error("aniversarios: falha na varredura", "erro", err)
+		} else if notificados > 0 || resumos > 0 {
+			slog.Info("aniversarios: concluído", "notificacoes", notificados, "resumos", resumos)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// RunOnce processa a semana de agora: notifica os aniversários de hoje e envia os
+// resumos ainda não enviados. Retorna quantas notificações e resumos saíram.
+func (a *Aniversarios) RunOnce(ctx context.Context, agora time.Time) (notificados, resumos int, err error) {
+	hoje := time.Date(agora.Year(), agora.Month(), agora.Day(), 0, 0, 0, 0, agora.Location())
+	segunda := hoje.AddDate(0, 0, -((int(hoje.Weekday()) + 6) % 7))
+
+	uids, err := model.UsuariosComAniversariantes(ctx, a.DB, segunda, 7)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, uid := range uids {
+		lista, err := model.ListarAniversariantes(ctx, a.DB, uid, segunda, 7, hoje)
+		if err != nil {
+			return notificados, resumos, fmt.Errorf("usuario %d: %w", uid, err)
+		}
+		n, err := a.notificarHoje(ctx, uid, lista)
+		notificados += n
+		if err != nil {
+			return notificados, resumos, fmt.Errorf("usuario %d: %w", uid, err)
+		}
+		enviado, err := a.enviarResumo(ctx, uid, segunda, lista)
+		if err != nil {
+			slog.Error("aniversarios: falha no resumo", "usuario_id", uid, "erro", err)
+			continue
+		}
+		if enviado {
+			resumos++
+		}
+	}
+	return notificados, resumos, nil
+}
+
+/// ============ Funções Internas (helpers) ============
+
+// notificarHoje cria a notificação in-app de cada aniversariante do dia.
+func (a *Aniversarios) notificarHoje(ctx context.Context, uid int, lista []model.Aniversariante) (int, error) {
+	n := 0
+	for _, e := range lista {
+		if e.DiasRestantes != 0 {
+			continue
+		}
+		criada, err := model.CriarNotificacao(ctx, a.DB, uid, model.NovaNotificacao{
+			Tipo:     model.NotificacaoAniversario,
+			Titulo:   "Hoje é aniversário de " + e.Nome,
+			Mensagem: fmt.Sprintf("%s completa %d anos hoje.", e.Nome, e.Idade),
+			Dados:    map[string]any{"estudante_id": e.ID, "idade": e.Idade},
+			Chave:    "aniversario:" + e.Aniversario + ":" + strconv.Itoa(e.ID),
+		})
+		if err != nil {
+			return n, err
+		}
+		if criada {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// enviarResumo reserva o resumo da semana em resumos_enviados e envia o e-mail;
+// se o envio falhar, a reserva é desfeita para a próxima varredura tentar de novo.
+func (a *Aniversarios) enviarResumo(ctx context.Context, uid int, segunda time.Time, lista []model.Aniversariante) (bool, error) {
+	if len(lista) == 0 {
+		return false, nil
+	}
+	var nome, email string
+	err := a.DB.QueryRowContext(ctx, `
+		INSERT INTO resumos_enviados (usuario_id, tipo, periodo)
+		VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING
+		RETURNING (SELECT nome FROM usuarios WHERE id=$1), (SELECT email FROM usuarios WHERE id=$1)
+	`, uid, resumoAniversariosSemanal, segunda.Format("2006-01-02")).Scan(&nome, &email)
+	if err == sql.ErrNoRows {
+		return false, nil // já enviado nesta semana
+	}
+	if err != nil {
+		return false, err
+	}
+
+	dados := notificador.DadosResumoAniversarios{
+		Nome:   nome,
+		Inicio: segunda,
+		Fim:    segunda.AddDate(0, 0, 6),
+		AppURL: a.AppURL,
+	}
+	for _, e := range lista {
+		data, _ := time.ParseInLocation("2006-01-02", e.Aniversario, segunda.Location())
+		dados.Aniversariantes = append(dados.Aniversariantes, notificador.AniversarianteResumo{Nome: e.Nome, Data: data, Idade: e.Idade})
+	}
+	if err := a.Notificador.Enviar(ctx, email, notificador.ResumoAniversarios, dados); err != nil {
+		if _, errDel := a.DB.ExecContext(ctx,
+			`DELETE FROM resumos_enviados WHERE usuario_id=$1 AND tipo=$2 AND periodo=$3`,
+			uid, resumoAniversariosSemanal, segunda.Format("2006-01-02"),
+		); errDel != nil {
+			slog.Error("aniversarios: falha ao liberar reserva do resumo", "usuario_id", uid, "erro", errDel)
+		}
+		return false, err
+	}
+	return true, nil
+}
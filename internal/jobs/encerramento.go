@@ -0,0 +1,56 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/jobs/encerramento.go
+/// Responsabilidade: Acompanhar as goroutines dos jobs em segundo plano para que o desligamento espere o trabalho em andamento.
+/// Dependências principais: context, sync.
+/// Pontos de atenção:
+/// - Cancelar o contexto interrompe as varreduras (idempotentes: a próxima subida retoma); webhooks concluem a entrega em curso. Aguardar espera as goroutines retornarem.
+/// - Se o prazo vencer, Aguardar devolve ctx.Err() e as goroutines seguem até o processo sair (entregas de webhook voltam para a fila pelo prazo da reserva).
+*/
+
+package jobs
+
+import (
+	"context"
+	"sync"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// Trabalhadores agrupa os laços Run dos jobs periódicos (uploads GC, webhooks, aniversários).
+type Trabalhadores struct {
+	wg sync.WaitGroup
+}
+
+/// ============ Funções Públicas ============
+
+// Iniciar roda run(ctx) em uma goroutine acompanhada.
+func (t *Trabalhadores) Iniciar(ctx context.Context, run func(context.Context)) {
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		run(ctx)
+	}()
+}
+
+// Aguardar espera todos os jobs retornarem ou ctx vencer.
+func (t *Trabalhadores) Aguardar(ctx context.Context) error {
+	return aguardar(ctx, &t.wg)
+}
+
+/// ============ Funções Internas (helpers) ============
+
+// aguardar espera wg com prazo.
+func aguardar(ctx context.Context, wg *sync.WaitGroup) error {
+	feito := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(feito)
+	}()
+	select {
+	case <-feito:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,525 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/jobs/exportacao.go
+/// Responsabilidade: Exportação assíncrona dos dados do usuário (portabilidade LGPD) em um ZIP com JSON/CSV de perfil, estudantes e anos, além dos arquivos enviados.
+/// Dependências principais: archive/zip, encoding/csv, encoding/json, database/sql (Postgres), backend/internal/storage, backend/internal/cripto.
+/// Pontos de atenção:
+/// - O estado dos pedidos fica na tabela exportacoes (0024_exportacoes.sql); pendente abandonado por reinício vira erro no próximo pedido.
+/// - O ZIP é gravado no storage em "{usuario_id}/exports/{id}.zip" e baixado via URL assinada; após ExportacaoRetencao o registro e o arquivo são descartados.
+/// - Só o solicitante lê o ZIP (ExportacaoDisponivel, usada por handler/upload_handler.go), nem mesmo outros membros da organização.
+/// - Estudantes, anos e uploads só entram quando o usuário é o dono dos dados (sem organização ou dono dela); membros exportam apenas o próprio perfil.
+/// - Uma exportação por usuário em andamento (índice parcial único); pedidos repetidos devolvem a mesma.
+/// - Ao concluir, o solicitante recebe uma notificação in-app (exportacao.pronta).
+/// - No desligamento, Encerrar recusa novos pedidos (ErrExportacoesEncerradas) e espera os ZIPs em geração.
+*/
+
+package jobs
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"backend/internal/cripto"
+	"backend/internal/model"
+	"backend/internal/storage"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// Exportacao é o estado público de um pedido de exportação.
+type Exportacao struct {
+	ID          string     `json:"id"`
+	Status      string     `json:"status"` // pendente | pronto | erro
+	CriadoEm    time.Time  `json:"criado_em"`
+	ConcluidoEm *time.Time `json:"concluido_em,omitempty"`
+	Erro        string     `json:"erro,omitempty"`
+
+	usuarioID int
+	tenantID  int
+	key       string
+}
+
+// Exportacoes gerencia os pedidos de exportação (tabela exportacoes) e a geração dos ZIPs.
+type Exportacoes struct {
+	DB      *sql.DB
+	Storage storage.Storage
+	PII     *cripto.Cifrador // decifra colunas sensíveis (nil = texto puro)
+
+	mu        sync.Mutex // protege encerrado
+	encerrado bool
+	emGeracao sync.WaitGroup
+}
+
+// tabelaExport descreve um conjunto de dados exportado como <nome>.json e <nome>.csv.
+type tabelaExport struct {
+	nome     string
+	query    string   // $1 = usuario_id dono dos dados
+	cifradas []string // colunas gravadas com cripto.Cifrador
+}
+
+/// ============ Configurações & Constantes ============
+
+const (
+	ExportacaoPendente = "pendente"
+	ExportacaoPronta   = "pronto"
+	ExportacaoErro     = "erro"
+)
+
+// ErrExportacoesEncerradas é devolvido por Iniciar durante o desligamento do servidor.
+var ErrExportacoesEncerradas = errors.New("exportações encerradas (servidor desligando)")
+
+// ExportacaoRetencao é por quanto tempo um pedido (e seu ZIP) fica disponível.
+const ExportacaoRetencao = 24 * time.Hour
+
+// tempo máximo de geração de um ZIP
+const exportacaoTimeout = 2 * time.Minute
+
+// pendente mais antigo que isso foi abandonado (processo reiniciado durante a geração)
+const exportacaoAbandonada = 2 * exportacaoTimeout
+
+// segmento das chaves de exportação no storage ("{usuario_id}/exports/{id}.zip")
+const pastaExportacoes = "exports"
+
+// colunas lidas por scanExportacao (mesma ordem)
+const colunasExportacao = `id, usuario_id, tenant_id, status, arquivo, erro, criado_em, concluido_em`
+
+var tabelasExport = []tabelaExport{
+	{"estudantes", `
+		SELECT e.id, e.nome, e.cpf, e.email, to_char(e.data_nascimento, 'YYYY-MM-DD') AS data_nascimento,
+		       e.telefone, e.foto_url, e.ano_id, a.nome AS ano, e.status
+		  FROM estudantes e
+		  LEFT JOIN anos a ON a.id = e.ano_id
+		 WHERE e.usuario_id = $1 AND e.excluido_em IS NULL
+		 ORDER BY e.nome, e.id`,
+		[]string{"cpf", "telefone"}},
+	{"anos", `
+		SELECT id, nome, ordem, arquivado, periodo_letivo_id
+		  FROM anos
+		 WHERE usuario_id = $1 AND excluido_em IS NULL
+		 ORDER BY ordem, id`, nil},
+}
+
+/// ============ Inicialização/Bootstrap ============
+
+// NovasExportacoes cria o gerenciador de exportações.
+func NovasExportacoes(db *sql.DB, st storage.Storage, pii *cripto.Cifrador) *Exportacoes {
+	return &Exportacoes{DB: db, Storage: st, PII: pii}
+}
+
+/// ============ Funções Públicas ============
+
+// Iniciar enfileira a exportação de usuarioID (dados do tenant quando for o dono).
+// Se já houver uma pendente para o usuário, ela é devolvida.
+func (e *Exportacoes) Iniciar(ctx context.Context, usuarioID, tenantID int) (Exportacao, error) {
+	e.mu.Lock()
+	if e.encerrado {
+		e.mu.Unlock()
+		return Exportacao{}, ErrExportacoesEncerradas
+	}
+	e.emGeracao.Add(1) // reservado já aqui para Encerrar não deixar passar a geração
+	e.mu.Unlock()
+	gerando := false
+	defer func() {
+		if !gerando {
+			e.emGeracao.Done()
+		}
+	}()
+
+	if err := e.limparExpiradas(ctx); err != nil {
+		return Exportacao{}, err
+	}
+
+	var b [12]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return Exportacao{}, err
+	}
+	id := hex.EncodeToString(b[:])
+	x := &Exportacao{
+		ID:        id,
+		usuarioID: usuarioID,
+		tenantID:  tenantID,
+		key:       strconv.Itoa(usuarioID) + "/" + pastaExportacoes + "/" + id + ".zip",
+	}
+	err := e.DB.QueryRowContext(ctx, `
+		INSERT INTO exportacoes (id, usuario_id, tenant_id, arquivo)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (usuario_id) WHERE status = 'pendente' DO NOTHING
+		RETURNING status, criado_em
+	`, x.ID, usuarioID, tenantID, x.key).Scan(&x.Status, &x.CriadoEm)
+	if err == sql.ErrNoRows {
+		// já há uma pendente: devolve o pedido mais recente do usuário
+		atual, err := scanExportacao(e.DB.QueryRowContext(ctx, `
+			SELECT `+colunasExportacao+` FROM exportacoes
+			 WHERE usuario_id = $1
+			 ORDER BY criado_em DESC LIMIT 1
+		`, usuarioID))
+		return atual, err
+	}
+	if err != nil {
+		return Exportacao{}, err
+	}
+
+	gerando = true
+	go func() {
+		defer e.emGeracao.Done()
+		e.gerar(x)
+	}()
+	return *x, nil
+}
+
+// Buscar devolve a exportação id do usuário e a chave do ZIP no storage.
+// sql.ErrNoRows se não existir, for de outro usuário ou já tiver expirado.
+func (e *Exportacoes) Buscar(ctx context.Context, id string, usuarioID int) (Exportacao, string, error) {
+	x, err := scanExportacao(e.DB.QueryRowContext(ctx, `
+		SELECT `+colunasExportacao+` FROM exportacoes
+		 WHERE id = $1 AND usuario_id = $2 AND criado_em > $3
+	`, id, usuarioID, time.Now().Add(-ExportacaoRetencao)))
+	if err != nil {
+		return Exportacao{}, "", err
+	}
+	return x, x.key, nil
+}
+
+// Encerrar recusa novos pedidos e espera as exportações em andamento até ctx vencer.
+func (e *Exportacoes) Encerrar(ctx context.Context) error {
+	e.mu.Lock()
+	e.encerrado = true
+	e.mu.Unlock()
+	return aguardar(ctx, &e.emGeracao)
+}
+
+// DonoExportacao devolve o usuário de uma chave "*/exports/..." (ok=false para as demais chaves;
+// prefixo que não é um id devolve 0, que não casa com nenhum usuário).
+func DonoExportacao(key string) (usuarioID int, ok bool) {
+	partes := strings.SplitN(key, "/", 3)
+	if len(partes) != 3 || partes[1] != pastaExportacoes {
+		return 0, false
+	}
+	usuarioID, _ = strconv.Atoi(partes[0])
+	return usuarioID, true
+}
+
+// ExportacaoDisponivel informa se key é o ZIP de uma exportação pronta de usuarioID, ainda dentro da retenção.
+func ExportacaoDisponivel(ctx context.Context, db *sql.DB, usuarioID int, key string) (bool, error) {
+	var ok bool
+	err := db.QueryRowContext(ctx, `
+		SELECT EXISTS(
+			SELECT 1 FROM exportacoes
+			 WHERE usuario_id = $1 AND arquivo = $2 AND status = $3 AND criado_em > $4
+		)
+	`, usuarioID, key, ExportacaoPronta, time.Now().Add(-ExportacaoRetencao)).Scan(&ok)
+	return ok, err
+}
+
+/// ============ Funções Internas (helpers) ============
+
+// gerar monta o ZIP, grava no storage e atualiza o status.
+func (e *Exportacoes) gerar(x *Exportacao) {
+	ctx, cancel := context.WithTimeout(context.Background(), exportacaoTimeout)
+	err := e.montarZIP(ctx, x)
+	cancel()
+
+	// prazo próprio: a geração pode ter esgotado o dela
+	ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	status, msg := ExportacaoPronta, ""
+	if err != nil {
+		slog.Error("exportacao: falha ao gerar", "exportacao_id", x.ID, "usuario_id", x.usuarioID, "erro", err)
+		status, msg = ExportacaoErro, "falha ao gerar exportação"
+	}
+	if _, err := e.DB.ExecContext(ctx,
+		`UPDATE exportacoes SET status = $1, erro = $2, concluido_em = NOW() WHERE id = $3`,
+		status, msg, x.ID,
+	); err != nil {
+		slog.Error("exportacao: falha ao gravar status", "exportacao_id", x.ID, "status", status, "erro", err)
+		return
+	}
+	if status != ExportacaoPronta {
+		return
+	}
+
+	if _, err := model.CriarNotificacao(ctx, e.DB, x.usuarioID, model.NovaNotificacao{
+		Tipo:     model.NotificacaoExportacaoPronta,
+		Titulo:   "Exportação dos seus dados concluída",
+		Mensagem: "O arquivo fica disponível para download por 24 horas.",
+		Dados:    map[string]any{"exportacao_id": x.ID},
+	}); err != nil {
+		slog.Error("exportacao: falha ao criar notificação", "exportacao_id", x.ID, "erro", err)
+	}
+}
+
+func (e *Exportacoes) montarZIP(ctx context.Context, x *Exportacao) error {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	perfil, err := consultar(ctx, e.DB, `
+		SELECT id, nome, email, foto_url, tutorial_visto, google_sub IS NOT NULL AS login_google
+		  FROM usuarios WHERE id = $1`, x.usuarioID)
+	if err != nil {
+		return fmt.Errorf("perfil: %w", err)
+	}
+	if len(perfil.linhas) == 1 {
+		if err := escreverJSON(zw, "perfil.json", perfil.objetos()[0]); err != nil {
+			return err
+		}
+	}
+
+	dono := x.usuarioID == x.tenantID
+	if dono {
+		for _, t := range tabelasExport {
+			res, err := consultar(ctx, e.DB, t.query, x.tenantID)
+			if err != nil {
+				return fmt.Errorf("%s: %w", t.nome, err)
+			}
+			if err := res.decifrar(e.PII, t.cifradas...); err != nil {
+				return fmt.Errorf("%s: %w", t.nome, err)
+			}
+			if err := escreverJSON(zw, t.nome+".json", res.objetos()); err != nil {
+				return err
+			}
+			if err := escreverCSV(zw, t.nome+".csv", res); err != nil {
+				return err
+			}
+		}
+		if err := e.copiarUploads(ctx, zw, x.tenantID); err != nil {
+			return fmt.Errorf("uploads: %w", err)
+		}
+	}
+
+	if err := escreverLeiame(zw, dono); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return e.Storage.Put(ctx, x.key, &buf, "application/zip")
+}
+
+// copiarUploads inclui em uploads/ os arquivos do prefixo "{uid}/" (exceto exportações).
+func (e *Exportacoes) copiarUploads(ctx context.Context, zw *zip.Writer, uid int) error {
+	prefixo := strconv.Itoa(uid) + "/"
+	objs, err := e.Storage.List(ctx, prefixo)
+	if err != nil {
+		return err
+	}
+	for _, o := range objs {
+		nome := strings.TrimPrefix(o.Key, prefixo)
+		if strings.HasPrefix(nome, pastaExportacoes+"/") {
+			continue
+		}
+		rc, err := e.Storage.Get(ctx, o.Key)
+		if err != nil {
+			return err
+		}
+		w, err := zw.Create("uploads/" + nome)
+		if err == nil {
+			_, err = io.Copy(w, rc)
+		}
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// limparExpiradas marca como erro os pendentes abandonados e apaga os pedidos
+// vencidos com seus ZIPs (em segundo plano).
+func (e *Exportacoes) limparExpiradas(ctx context.Context) error {
+	if _, err := e.DB.ExecContext(ctx, `
+		UPDATE exportacoes SET status = $1, erro = $2, concluido_em = NOW()
+		 WHERE status = $3 AND criado_em < $4
+	`, ExportacaoErro, "geração interrompida; peça a exportação novamente", ExportacaoPendente, time.Now().Add(-exportacaoAbandonada)); err != nil {
+		return err
+	}
+
+	rows, err := e.DB.QueryContext(ctx, `
+		DELETE FROM exportacoes
+		 WHERE status <> $1 AND criado_em < $2
+		RETURNING arquivo, status
+	`, ExportacaoPendente, time.Now().Add(-ExportacaoRetencao))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	var arquivos []string
+	for rows.Next() {
+		var key, status string
+		if err := rows.Scan(&key, &status); err != nil {
+			return err
+		}
+		if status == ExportacaoPronta {
+			arquivos = append(arquivos, key)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, key := range arquivos {
+		go func(key string) {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			if err := e.Storage.Delete(ctx, key); err != nil {
+				slog.Warn("exportacao: falha ao remover arquivo expirado", "key", key, "erro", err)
+			}
+		}(key)
+	}
+	return nil
+}
+
+// scanExportacao lê uma linha de colunasExportacao.
+func scanExportacao(row *sql.Row) (Exportacao, error) {
+	var x Exportacao
+	var concluido sql.NullTime
+	if err := row.Scan(&x.ID, &x.usuarioID, &x.tenantID, &x.Status, &x.key, &x.Erro, &x.CriadoEm, &concluido); err != nil {
+		return Exportacao{}, err
+	}
+	if concluido.Valid {
+		x.ConcluidoEm = &concluido.Time
+	}
+	return x, nil
+}
+
+// resultado guarda colunas e valores de uma consulta genérica.
+type resultado struct {
+	colunas []string
+	linhas  [][]any
+}
+
+// objetos converte as linhas em mapas coluna → valor (para JSON).
+func (r resultado) objetos() []map[string]any {
+	out := make([]map[string]any, 0, len(r.linhas))
+	for _, l := range r.linhas {
+		m := make(map[string]any, len(r.colunas))
+		for i, c := range r.colunas {
+			m[c] = l[i]
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+// decifrar substitui, nas colunas indicadas, os valores cifrados pelo texto puro.
+func (r resultado) decifrar(c *cripto.Cifrador, colunas ...string) error {
+	for i, col := range r.colunas {
+		if !slices.Contains(colunas, col) {
+			continue
+		}
+		for _, l := range r.linhas {
+			v, ok := l[i].(string)
+			if !ok {
+				continue
+			}
+			pt, err := c.Decifrar(v)
+			if err != nil {
+				return err
+			}
+			l[i] = pt
+		}
+	}
+	return nil
+}
+
+func consultar(ctx context.Context, db *sql.DB, q string, args ...any) (resultado, error) {
+	rows, err := db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return resultado{}, err
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return resultado{}, err
+	}
+	res := resultado{colunas: cols}
+	for rows.Next() {
+		vals := make([]any, len(cols))
+		ptrs := make([]any, len(cols))
+		for i := range vals {
+			ptrs[i] = &vals[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return resultado{}, err
+		}
+		for i, v := range vals {
+			if b, ok := v.([]byte); ok {
+				vals[i] = string(b)
+			}
+		}
+		res.linhas = append(res.linhas, vals)
+	}
+	return res, rows.Err()
+}
+
+func escreverJSON(zw *zip.Writer, nome string, v any) error {
+	w, err := zw.Create(nome)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func escreverCSV(zw *zip.Writer, nome string, r resultado) error {
+	w, err := zw.Create(nome)
+	if err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(r.colunas); err != nil {
+		return err
+	}
+	reg := make([]string, len(r.colunas))
+	for _, l := range r.linhas {
+		for i, v := range l {
+			if v == nil {
+				reg[i] = ""
+				continue
+			}
+			reg[i] = fmt.Sprint(v)
+		}
+		if err := cw.Write(reg); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func escreverLeiame(zw *zip.Writer, dono bool) error {
+	w, err := zw.Create("LEIAME.txt")
+	if err != nil {
+		return err
+	}
+	txt := "Exportação de dados pessoais (LGPD, art. 18, V) — Tecmise\n" +
+		"Gerada em: " + time.Now().Format(time.RFC3339) + "\n\n" +
+		"perfil.json         dados da sua conta\n"
+	if dono {
+		txt += "estudantes.json/csv estudantes cadastrados (ativos, transferidos e formados)\n" +
+			"anos.json/csv       anos/turmas\n" +
+			"uploads/            arquivos enviados (fotos e documentos)\n"
+	} else {
+		txt += "\nVocê participa de uma organização: os dados escolares pertencem ao dono\n" +
+			"da organização e não fazem parte desta exportação.\n"
+	}
+	_, err = io.WriteString(w, txt)
+	return err
+}
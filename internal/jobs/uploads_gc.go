@@ -0,0 +1,149 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/jobs/uploads_gc.go
+/// Responsabilidade: Job em segundo plano que remove uploads órfãos (arquivos que nenhum registro do banco referencia mais).
+/// Dependências principais: database/sql (Postgres), backend/internal/storage.
+/// Pontos de atenção:
+/// - Só remove arquivos mais antigos que o período de carência (Grace), para não apagar uploads recém-enviados ainda não gravados em foto_url.
+/// - Referências são extraídas de estudantes.foto_url, usuarios.foto_url (trecho após "/uploads/", sem query string), documentos.storage_key e exportacoes.arquivo (ZIPs prontos).
+/// - A miniatura de uma foto (storage.ChaveMiniatura) conta como referenciada junto com a foto.
+/// - DryRun=true apenas registra em log o que seria removido.
+*/
+
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"strings"
+	"time"
+
+	"backend/internal/storage"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// UploadsGC cruza os arquivos do storage com o banco e apaga os órfãos.
+type UploadsGC struct {
+	DB       *sql.DB
+	Storage  storage.Storage
+	Interval time.Duration // intervalo entre execuções (<= 0 desativa)
+	Grace    time.Duration // idade mínima de um arquivo para ser removido
+	DryRun   bool          // apenas loga, sem remover
+}
+
+/// ============ Configurações & Constantes ============
+
+// consultas que devolvem URLs/caminhos de arquivos ainda referenciados
+var uploadsReferenciados = []string{
+	`SELECT foto_url FROM estudantes WHERE COALESCE(foto_url,'') <> ''`,
+	`SELECT foto_url FROM usuarios   WHERE COALESCE(foto_url,'') <> ''`,
+	`SELECT '/uploads/' || storage_key FROM documentos`,
+	`SELECT '/uploads/' || arquivo FROM exportacoes WHERE status = 'pronto'`,
+}
+
+/// ============ Funções Públicas ============
+
+// Run executa o job em laço até ctx ser cancelado.
+// A primeira execução ocorre após o primeiro intervalo.
+func (g *UploadsGC) Run(ctx context.Context) {
+	if g.Interval <= 0 {
+		return
+	}
+	slog.Info("uploads-gc: ativo", "intervalo", g.Interval.String(), "carencia", g.Grace.String(), "dry_run", g.DryRun)
+	t := time.NewTicker(g.Interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			removidos, err := g.RunOnce(ctx)
+			if err != nil {
+				slog.Error("uploads-gc: falha na varredura", "erro", err)
+				continue
+			}
+			slog.Info("uploads-gc: concluído", "orfaos", removidos)
+		}
+	}
+}
+
+// RunOnce faz uma varredura completa e retorna quantos órfãos foram (ou seriam) removidos.
+func (g *UploadsGC) RunOnce(ctx context.Context) (int, error) {
+	refs, err := g.referencias(ctx)
+	if err != nil {
+		return 0, err
+	}
+	objs, err := g.Storage.List(ctx, "")
+	if err != nil {
+		return 0, err
+	}
+
+	limite := time.Now().Add(-g.Grace)
+	removidos := 0
+	for _, o := range objs {
+		if _, ok := refs[o.Key]; ok || o.ModTime.After(limite) {
+			continue
+		}
+		if g.DryRun {
+			slog.Info("uploads-gc: (dry-run) removeria", "key", o.Key)
+			removidos++
+			continue
+		}
+		if err := g.Storage.Delete(ctx, o.Key); err != nil {
+			slog.Warn("uploads-gc: falha ao remover", "key", o.Key, "erro", err)
+			continue
+		}
+		removidos++
+	}
+	return removidos, nil
+}
+
+/// ============ Funções Internas (helpers) ============
+
+// referencias carrega o conjunto de chaves ainda usadas pelo banco.
+func (g *UploadsGC) referencias(ctx context.Context) (map[string]struct{}, error) {
+	refs := make(map[string]struct{})
+	for _, q := range uploadsReferenciados {
+		rows, err := g.DB.QueryContext(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var u string
+			if err := rows.Scan(&u); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			if k := chaveDeURL(u); k != "" {
+				refs[k] = struct{}{}
+				refs[storage.ChaveMiniatura(k)] = struct{}{}
+			}
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return refs, nil
+}
+
+// chaveDeURL extrai a chave de storage de um foto_url
+// ("/uploads/12/a.jpg", "http://host/uploads/12/a.jpg?exp=..." → "12/a.jpg").
+func chaveDeURL(u string) string {
+	i := strings.Index(u, "/uploads/")
+	if i < 0 {
+		return ""
+	}
+	k := u[i+len("/uploads/"):]
+	if j := strings.IndexAny(k, "?#"); j >= 0 {
+		k = k[:j]
+	}
+	k, err := storage.CleanKey(k)
+	if err != nil {
+		return ""
+	}
+	return k
+}
@@ -0,0 +1,329 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/jobs/webhooks.go
+/// Responsabilidade: Publicação de eventos para webhooks (fila em webhook_entregas) e job de entrega com assinatura HMAC-SHA256 e retentativas com backoff.
+/// Dependências principais: crypto/hmac, crypto/sha256, net/http, database/sql (Postgres).
+/// Pontos de atenção:
+/// - Publicar só grava na fila (uma linha por webhook que assina o evento); a entrega é assíncrona e não atrasa a requisição.
+/// - Entregas são reservadas com FOR UPDATE SKIP LOCKED e um prazo (lease): várias instâncias podem rodar o job sem entregar duas vezes; se o processo cair no meio, a entrega volta para a fila quando o prazo vence.
+/// - Sucesso = resposta 2xx. Após len(webhookBackoff)+1 tentativas a entrega fica como "falhou".
+/// - Assinatura: X-Tecmise-Signature: t=<unix>,v1=<hex(HMAC-SHA256(segredo, "<t>.<corpo>"))>; o receptor deve recalcular e rejeitar t muito antigo.
+/// - A entrega é "pelo menos uma vez": receptores devem deduplicar por X-Tecmise-Delivery.
+/// - SSRF: o cliente só conecta em endereços públicos (model.EnderecoInterno conferido no IP de cada conexão, já
+///   resolvido: cobre DNS rebinding), não segue redirecionamentos (3xx conta como falha) e ignora HTTP(S)_PROXY.
+///   O log de entregas, visível aos admins do tenant, guarda só o status HTTP ou uma categoria do erro, nunca o
+///   corpo da resposta nem a mensagem da conexão; o detalhe vai para o log do servidor.
+*/
+
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"syscall"
+	"time"
+
+	"backend/internal/model"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// Webhooks publica eventos e entrega a fila de webhook_entregas.
+type Webhooks struct {
+	DB       *sql.DB
+	Client   *http.Client  // timeout de cada entrega
+	Interval time.Duration // intervalo entre varreduras da fila (<= 0 desativa a entrega)
+
+	acordar chan struct{}
+}
+
+// entregaReservada é uma entrega retirada da fila para envio.
+type entregaReservada struct {
+	id         int64
+	evento     string
+	payload    json.RawMessage
+	tentativas int
+	criadoEm   time.Time
+	url        string
+	segredo    string
+}
+
+/// ============ Configurações & Constantes ============
+
+// webhookBackoff é a espera antes de cada nova tentativa (1ª falha → 1 min, ...).
+var webhookBackoff = []time.Duration{
+	time.Minute,
+	5 * time.Minute,
+	30 * time.Minute,
+	2 * time.Hour,
+	6 * time.Hour,
+}
+
+const (
+	webhookLote         = 20              // entregas reservadas por vez
+	webhookReserva      = 2 * time.Minute // prazo da reserva (lease) de uma entrega
+	webhookUserAgent    = "Tecmise-Webhooks/1.0"
+	webhookLeituraCorpo = 4 << 10 // bytes da resposta descartados antes de fechar (reuso da conexão)
+)
+
+// errDestinoInterno é devolvido pelo dialer quando o host resolve para um endereço interno.
+var errDestinoInterno = errors.New("destino interno bloqueado")
+
+/// ============ Inicialização/Bootstrap ============
+
+// NovosWebhooks cria o publicador/entregador; timeout limita cada POST ao receptor.
+func NovosWebhooks(db *sql.DB, interval, timeout time.Duration) *Webhooks {
+	return &Webhooks{
+		DB:       db,
+		Client:   clienteWebhooks(timeout),
+		Interval: interval,
+		acordar:  make(chan struct{}, 1),
+	}
+}
+
+// clienteWebhooks monta o http.Client das entregas: sem proxy, sem seguir
+// redirecionamentos e recusando conexões a endereços internos.
+func clienteWebhooks(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: 10 * time.Second, KeepAlive: 30 * time.Second, Control: bloquearInterno}
+	transporte := &http.Transport{
+		Proxy:                 nil,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          20,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: time.Second,
+	}
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transporte,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// bloquearInterno roda antes de cada connect, com o IP já resolvido (address = "ip:porta").
+func bloquearInterno(_, address string, _ syscall.RawConn) error {
+	ap, err := netip.ParseAddrPort(address)
+	if err != nil || model.EnderecoInterno(ap.Addr()) {
+		return errDestinoInterno
+	}
+	return nil
+}
+
+/// ============ Funções Públicas ============
+
+// Publicar enfileira o evento para cada webhook ativo do usuário uid que o assina.
+// dados vira o campo "dados" do corpo enviado. Receptor nil não faz nada.
+func (w *Webhooks) Publicar(ctx context.Context, uid int, evento string, dados any) error {
+	if w == nil {
+		return nil
+	}
+	payload, err := json.Marshal(dados)
+	if err != nil {
+		return err
+	}
+	res, err := w.DB.ExecContext(ctx, `
+		INSERT INTO webhook_entregas (webhook_id, evento, payload)
+		SELECT id, $2, $3 FROM webhooks
+		 WHERE usuario_id = $1 AND ativo AND $2 = ANY(eventos)
+	`, uid, evento, string(payload))
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		select {
+		case w.acordar <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// Run entrega a fila em laço até ctx ser cancelado: a cada Interval ou logo após
+// uma publicação.
+func (w *Webhooks) Run(ctx context.Context) {
+	if w.Interval <= 0 {
+		return
+	}
+	slog.Info("webhooks: ativo", "intervalo", w.Interval.String(), "timeout", w.Client.Timeout.String())
+	t := time.NewTicker(w.Interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		case <-w.acordar:
+		}
+		if n, err := w.RunOnce(ctx); err != nil {
+			slog.Error("webhooks: falha ao processar a fila", "erro", err)
+		} else if n > 0 {
+			slog.Info("webhooks: entregas processadas", "quantidade", n)
+		}
+	}
+}
+
+// RunOnce processa as entregas vencidas e retorna quantas foram tentadas.
+// Cancelar ctx interrompe entre entregas: a que está em curso termina e é registrada;
+// as demais do lote voltam para a fila quando a reserva vence.
+func (w *Webhooks) RunOnce(ctx context.Context) (int, error) {
+	total := 0
+	for ctx.Err() == nil {
+		lote, err := w.reservar(ctx)
+		if err != nil {
+			return total, err
+		}
+		for _, e := range lote {
+			if ctx.Err() != nil {
+				return total, nil
+			}
+			w.entregar(context.WithoutCancel(ctx), e)
+			total++
+		}
+		if len(lote) < webhookLote {
+			break
+		}
+	}
+	return total, nil
+}
+
+// Assinar calcula o valor de X-Tecmise-Signature para corpo no instante t.
+func Assinar(segredo string, t time.Time, corpo []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(segredo))
+	mac.Write([]byte(ts + "."))
+	mac.Write(corpo)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+/// ============ Funções Internas (helpers) ============
+
+// reservar retira da fila até webhookLote entregas vencidas, adiando-as pelo prazo da reserva.
+func (w *Webhooks) reservar(ctx context.Context) ([]entregaReservada, error) {
+	rows, err := w.DB.QueryContext(ctx, `
+		UPDATE webhook_entregas e
+		   SET proxima_tentativa_em = NOW() + $2 * INTERVAL '1 second'
+		  FROM webhooks wh
+		 WHERE wh.id = e.webhook_id
+		   AND e.id IN (
+		        SELECT id FROM webhook_entregas
+		         WHERE status = 'pendente' AND proxima_tentativa_em <= NOW()
+		         ORDER BY proxima_tentativa_em
+		         LIMIT $1
+		           FOR UPDATE SKIP LOCKED)
+		RETURNING e.id, e.evento, e.payload, e.tentativas, e.criado_em, wh.url, wh.segredo
+	`, webhookLote, int(webhookReserva/time.Second))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []entregaReservada
+	for rows.Next() {
+		var e entregaReservada
+		if err := rows.Scan(&e.id, &e.evento, &e.payload, &e.tentativas, &e.criadoEm, &e.url, &e.segredo); err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// entregar envia uma entrega e registra o resultado (sucesso, nova tentativa ou falha definitiva).
+func (w *Webhooks) entregar(ctx context.Context, e entregaReservada) {
+	status, erroEnvio := w.enviar(ctx, e)
+	tentativas := e.tentativas + 1
+
+	var err error
+	switch {
+	case erroEnvio == nil:
+		_, err = w.DB.ExecContext(ctx, `
+			UPDATE webhook_entregas
+			   SET status = $2, tentativas = $3, ultimo_status_http = $4, ultimo_erro = NULL, entregue_em = NOW()
+			 WHERE id = $1
+		`, e.id, model.EntregaEntregue, tentativas, status)
+	case tentativas > len(webhookBackoff):
+		slog.Warn("webhooks: entrega descartada após retentativas", "entrega_id", e.id, "evento", e.evento, "erro", erroEnvio)
+		_, err = w.DB.ExecContext(ctx, `
+			UPDATE webhook_entregas
+			   SET status = $2, tentativas = $3, ultimo_status_http = NULLIF($4, 0), ultimo_erro = $5
+			 WHERE id = $1
+		`, e.id, model.EntregaFalhou, tentativas, status, erroPublico(status, erroEnvio))
+	default:
+		espera := webhookBackoff[tentativas-1]
+		slog.Info("webhooks: entrega falhou, nova tentativa agendada", "entrega_id", e.id, "evento", e.evento, "erro", erroEnvio)
+		_, err = w.DB.ExecContext(ctx, `
+			UPDATE webhook_entregas
+			   SET tentativas = $2, ultimo_status_http = NULLIF($3, 0), ultimo_erro = $4,
+			       proxima_tentativa_em = NOW() + $5 * INTERVAL '1 second'
+			 WHERE id = $1
+		`, e.id, tentativas, status, erroPublico(status, erroEnvio), int(espera/time.Second))
+	}
+	if err != nil {
+		// a reserva vence e a entrega é tentada de novo
+		slog.Error("webhooks: falha ao registrar entrega", "entrega_id", e.id, "erro", err)
+	}
+}
+
+// enviar faz o POST assinado; devolve o status HTTP (0 sem resposta) e erro se não for 2xx.
+func (w *Webhooks) enviar(ctx context.Context, e entregaReservada) (int, error) {
+	corpo, err := json.Marshal(map[string]any{
+		"id":        e.id,
+		"evento":    e.evento,
+		"criado_em": e.criadoEm.UTC(),
+		"dados":     e.payload,
+	})
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(corpo))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", webhookUserAgent)
+	req.Header.Set("X-Tecmise-Event", e.evento)
+	req.Header.Set("X-Tecmise-Delivery", strconv.FormatInt(e.id, 10))
+	req.Header.Set("X-Tecmise-Signature", Assinar(e.segredo, time.Now(), corpo))
+
+	resp, err := w.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, webhookLeituraCorpo))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// erroPublico é o ultimo_erro exibido no log de entregas: o status HTTP ou uma
+// categoria do erro de conexão, sem repassar o que o destino respondeu.
+func erroPublico(status int, err error) string {
+	var ne net.Error
+	switch {
+	case status != 0:
+		return "HTTP " + strconv.Itoa(status)
+	case errors.Is(err, errDestinoInterno):
+		return "destino bloqueado: endereço interno"
+	case errors.As(err, &ne) && ne.Timeout():
+		return "tempo esgotado"
+	default:
+		return "falha de conexão"
+	}
+}
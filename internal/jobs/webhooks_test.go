@@ -0,0 +1,77 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// O receptor em 127.0.0.1 nunca recebe a entrega: o dialer barra o IP já resolvido.
+func TestWebhookDestinoInternoBloqueado(t *testing.T) {
+	recebeu := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recebeu = true
+	}))
+	defer srv.Close()
+
+	// nome que resolve para loopback: o mesmo caminho de um DNS rebinding
+	for _, url := range []string{srv.URL, "http://localhost:" + strconv.Itoa(srv.Listener.Addr().(*net.TCPAddr).Port)} {
+		w := &Webhooks{Client: clienteWebhooks(5 * time.Second)}
+		status, err := w.enviar(context.Background(), entregaReservada{id: 1, evento: "estudante.created", payload: []byte(`{}`), url: url, segredo: "s"})
+		if status != 0 || !errors.Is(err, errDestinoInterno) {
+			t.Errorf("%s: status = %d, erro = %v", url, status, err)
+		}
+		if got := erroPublico(status, err); got != "destino bloqueado: endereço interno" {
+			t.Errorf("%s: erro público = %q", url, got)
+		}
+	}
+	if recebeu {
+		t.Fatal("entrega chegou ao endereço interno")
+	}
+}
+
+// Redirecionamento não é seguido e o corpo da resposta não chega ao log de entregas.
+// (Transport padrão só para alcançar o httptest em loopback; o resto é o cliente real.)
+func TestWebhookSemRedirecionamentoNemCorpo(t *testing.T) {
+	seguiu := false
+	mux := http.NewServeMux()
+	mux.HandleFunc("/redireciona", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/interno", http.StatusTemporaryRedirect)
+	})
+	mux.HandleFunc("/interno", func(w http.ResponseWriter, r *http.Request) { seguiu = true })
+	mux.HandleFunc("/erro", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "ami-id: i-0123 segredo-interno", http.StatusInternalServerError)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c := clienteWebhooks(5 * time.Second)
+	c.Transport = http.DefaultTransport
+	w := &Webhooks{Client: c}
+	casos := []struct {
+		caminho string
+		status  int
+	}{
+		{"/redireciona", http.StatusTemporaryRedirect},
+		{"/erro", http.StatusInternalServerError},
+	}
+	for _, caso := range casos {
+		status, err := w.enviar(context.Background(), entregaReservada{id: 1, evento: "estudante.created", payload: []byte(`{}`), url: srv.URL + caso.caminho, segredo: "s"})
+		if status != caso.status || err == nil {
+			t.Fatalf("%s: status = %d, erro = %v", caso.caminho, status, err)
+		}
+		publico := erroPublico(status, err)
+		if publico != "HTTP "+strconv.Itoa(caso.status) || strings.Contains(err.Error(), "segredo") {
+			t.Errorf("%s: erro público = %q, erro = %v", caso.caminho, publico, err)
+		}
+	}
+	if seguiu {
+		t.Error("cliente seguiu o redirecionamento")
+	}
+}
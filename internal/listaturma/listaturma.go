@@ -0,0 +1,194 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/listaturma/listaturma.go
+/// Responsabilidade: Lista de estudantes impressa (PDF A4) de um ano, agrupada por turma, com as fotos em grade e colunas opcionais (telefone, data de nascimento, e-mail).
+/// Dependências principais: image, time, backend/internal/pdf.
+/// Pontos de atenção:
+/// - Cada turma começa em página nova; turmas que não cabem numa página continuam na seguinte com "(continuação)" no título.
+/// - Fotos entram quadradas com fotoPx pixels (o suficiente para impressão a ~150 dpi); sem foto, o quadro mostra as iniciais.
+/// - Textos longos são truncados pela largura da célula; caracteres fora do Latin-1 viram "?" (limitação do pacote pdf).
+/// - O rodapé "Página i de n" é escrito depois da grade, quando o total de páginas já é conhecido.
+*/
+
+package listaturma
+
+import (
+	"fmt"
+	"image"
+	"strconv"
+	"time"
+
+	"backend/internal/pdf"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// Dados é o conteúdo da lista impressa.
+type Dados struct {
+	Escola    string    // organização (ou nome da conta)
+	Ano       string    // nome do ano
+	EmitidaEm time.Time // data no rodapé
+	Colunas   []string  // informações extras sob o nome (Colunas*), na ordem pedida
+	Turmas    []Turma
+}
+
+// Turma é um grupo da lista (nome vazio = estudantes sem turma).
+type Turma struct {
+	Nome       string
+	Estudantes []Estudante
+}
+
+// Estudante é uma célula da grade.
+type Estudante struct {
+	Nome           string
+	Iniciais       string // no quadro, quando não há foto
+	Telefone       string
+	DataNascimento string // ISO (YYYY-MM-DD, aceita timestamp)
+	Email          string
+	Foto           image.Image // nil = quadro com as iniciais
+}
+
+/// ============ Configurações & Constantes ============
+
+// Colunas opcionais aceitas em Dados.Colunas.
+const (
+	ColunaTelefone       = "telefone"
+	ColunaDataNascimento = "data_nascimento"
+	ColunaEmail          = "email"
+)
+
+// Colunas lista as colunas opcionais válidas (para validar o pedido).
+var Colunas = []string{ColunaTelefone, ColunaDataNascimento, ColunaEmail}
+
+// Layout em pontos (A4 retrato).
+const (
+	margem       = 36.0
+	porLinha     = 4 // células por linha da grade
+	fotoLado     = 72.0
+	fotoPx       = 160 // pixels da foto embutida (quadrada)
+	alturaTopo   = 58.0
+	alturaRodape = 24.0
+	linhaNome    = 10.0
+	linhaExtra   = 9.0
+	folgaCelula  = 14.0
+)
+
+// cor da faixa do título da turma (RGB 0–1), a mesma da carteirinha
+const corTitulo = "0.11 0.30 0.60"
+
+/// ============ Funções Públicas ============
+
+// PDF monta a lista: uma ou mais páginas A4 por turma.
+func PDF(d Dados) ([]byte, error) {
+	doc := pdf.Novo(pdf.A4Largura, pdf.A4Altura)
+	larguraCelula := (pdf.A4Largura - 2*margem) / porLinha
+	alturaCelula := fotoLado + 6 + 2*linhaNome + float64(len(d.Colunas))*linhaExtra + folgaCelula
+	topoGrade := pdf.A4Altura - margem - alturaTopo
+	linhasPorPagina := max(int((topoGrade-margem-alturaRodape)/alturaCelula), 1)
+
+	var paginas []*pdf.Pagina
+	turmas := d.Turmas
+	if len(turmas) == 0 {
+		turmas = []Turma{{}}
+	}
+	for _, t := range turmas {
+		porPagina := linhasPorPagina * porLinha
+		for ini := 0; ini == 0 || ini < len(t.Estudantes); ini += porPagina {
+			pg := doc.NovaPagina()
+			paginas = append(paginas, pg)
+			cabecalho(pg, d, t, ini > 0)
+			if len(t.Estudantes) == 0 {
+				pg.Texto(pdf.FonteRegular, 10, margem, topoGrade-14, "0.35 g", "Nenhum estudante ativo.")
+				break
+			}
+			fim := min(ini+porPagina, len(t.Estudantes))
+			for i, e := range t.Estudantes[ini:fim] {
+				x := margem + float64(i%porLinha)*larguraCelula
+				y := topoGrade - float64(i/porLinha+1)*alturaCelula
+				if err := celula(doc, pg, d.Colunas, e, ini+i+1, x, y, larguraCelula, alturaCelula); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	for i, pg := range paginas {
+		rodape := "Emitida em " + d.EmitidaEm.Format("02/01/2006")
+		pg.Texto(pdf.FonteRegular, 7, margem, margem-12, "0.35 g", rodape)
+		num := fmt.Sprintf("Página %d de %d", i+1, len(paginas))
+		pg.Texto(pdf.FonteRegular, 7, pdf.A4Largura-margem-pdf.LarguraTexto(num, 7), margem-12, "0.35 g", num)
+	}
+	return doc.Bytes(), nil
+}
+
+/// ============ Funções Internas (helpers) ============
+
+// cabecalho escreve escola, ano e a faixa com o nome da turma e o total.
+func cabecalho(pg *pdf.Pagina, d Dados, t Turma, continuacao bool) {
+	largura := pdf.A4Largura - 2*margem
+	topo := pdf.A4Altura - margem
+	pg.Texto(pdf.FonteNegrito, 14, margem, topo-12, "0 g", pdf.Truncar(d.Escola, 14, largura))
+	pg.Texto(pdf.FonteRegular, 10, margem, topo-26, "0.35 g", pdf.Truncar("Lista de estudantes - "+d.Ano, 10, largura))
+
+	titulo := "Turma " + t.Nome
+	if t.Nome == "" {
+		titulo = "Sem turma"
+	}
+	titulo += " (" + strconv.Itoa(len(t.Estudantes)) + " estudantes)"
+	if continuacao {
+		titulo += " (continuação)"
+	}
+	pg.Comando("%s rg %.2f %.2f %.2f 18 re f", corTitulo, margem, topo-52, largura)
+	pg.Texto(pdf.FonteNegrito, 10, margem+6, topo-46, "1 g", pdf.Truncar(titulo, 10, largura-12))
+}
+
+// celula desenha foto (ou iniciais), número e nome e as colunas extras, com o canto inferior esquerdo em (x, y).
+func celula(doc *pdf.Documento, pg *pdf.Pagina, colunas []string, e Estudante, numero int, x, y, w, h float64) error {
+	fotoX := x + (w-fotoLado)/2
+	fotoY := y + h - fotoLado - 4
+	if e.Foto != nil {
+		foto, err := pdf.FotoJPEG(e.Foto, fotoPx, fotoPx)
+		if err != nil {
+			return err
+		}
+		pg.Imagem(doc.ImagemJPEG(foto, fotoPx, fotoPx), fotoX, fotoY, fotoLado, fotoLado)
+	} else {
+		pg.Comando("0.93 g %.2f %.2f %.2f %.2f re f", fotoX, fotoY, fotoLado, fotoLado)
+		pg.Texto(pdf.FonteNegrito, 22, fotoX+(fotoLado-pdf.LarguraTexto(e.Iniciais, 22))/2, fotoY+fotoLado/2-8, "0.55 g", e.Iniciais)
+	}
+	pg.Comando("0.75 G 0.5 w %.2f %.2f %.2f %.2f re S", fotoX, fotoY, fotoLado, fotoLado)
+
+	largura := w - 8
+	ty := fotoY - 6 - linhaNome + 2
+	for _, linha := range pdf.QuebrarLinhas(strconv.Itoa(numero)+". "+e.Nome, 8, largura, 2) {
+		pg.Texto(pdf.FonteNegrito, 8, x+4, ty, "0 g", linha)
+		ty -= linhaNome
+	}
+	ty = fotoY - 6 - 2*linhaNome + 2 - linhaExtra + 1
+	for _, c := range colunas {
+		if v := valorColuna(e, c); v != "" {
+			pg.Texto(pdf.FonteRegular, 7, x+4, ty, "0.25 g", pdf.Truncar(v, 7, largura))
+		}
+		ty -= linhaExtra
+	}
+	return nil
+}
+
+// valorColuna formata a coluna extra c do estudante ("" quando não preenchida).
+func valorColuna(e Estudante, c string) string {
+	switch c {
+	case ColunaTelefone:
+		if e.Telefone != "" {
+			return "Tel.: " + e.Telefone
+		}
+	case ColunaDataNascimento:
+		if len(e.DataNascimento) >= 10 {
+			if t, err := time.Parse("2006-01-02", e.DataNascimento[:10]); err == nil {
+				return "Nasc.: " + t.Format("02/01/2006")
+			}
+		}
+	case ColunaEmail:
+		return e.Email
+	}
+	return ""
+}
@@ -0,0 +1,107 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/logging/logging.go
+/// Responsabilidade: Logging estruturado (log/slog) do backend e propagação do request ID pelo context.
+/// Dependências principais: log/slog, context, crypto/rand.
+/// Pontos de atenção:
+/// - Configurar troca o logger padrão do slog; chamadas remanescentes ao pacote log também saem pelo mesmo handler.
+/// - De(ctx) devolve o logger já com request_id quando a requisição passou por middleware.RequestID.
+/// - Campos padronizados: "erro" para erros, "request_id" para correlação.
+*/
+
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// chave privada do request ID no context
+type chaveRequestID struct{}
+
+/// ============ Configurações & Constantes ============
+
+// HeaderRequestID é o cabeçalho lido/devolvido com o ID da requisição.
+const HeaderRequestID = "X-Request-ID"
+
+// tamanho máximo aceito para um X-Request-ID recebido do cliente/proxy
+const maxRequestID = 128
+
+/// ============ Inicialização/Bootstrap ============
+
+// Configurar instala o logger padrão. nivel: debug|info|warn|error; formato: json|text.
+func Configurar(saida io.Writer, nivel, formato string) error {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(nivel)); err != nil {
+		return fmt.Errorf("nível de log inválido %q (debug, info, warn, error)", nivel)
+	}
+	opts := &slog.HandlerOptions{Level: lvl}
+	var h slog.Handler
+	switch strings.ToLower(formato) {
+	case "json":
+		h = slog.NewJSONHandler(saida, opts)
+	case "text":
+		h = slog.NewTextHandler(saida, opts)
+	default:
+		return fmt.Errorf("formato de log inválido %q (json, text)", formato)
+	}
+	slog.SetDefault(slog.New(h))
+	return nil
+}
+
+/// ============ Funções Públicas ============
+
+// ComRequestID devolve um context carregando o request ID.
+func ComRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, chaveRequestID{}, id)
+}
+
+// RequestID lê o request ID do context ("" se ausente).
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(chaveRequestID{}).(string)
+	return id
+}
+
+// De devolve o logger padrão com o request_id do context (quando houver).
+func De(ctx context.Context) *slog.Logger {
+	if id := RequestID(ctx); id != "" {
+		return slog.Default().With("request_id", id)
+	}
+	return slog.Default()
+}
+
+// NovoRequestID gera um ID aleatório (32 caracteres hex).
+func NovoRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// RequestIDValido aceita IDs recebidos de fora só com caracteres seguros para logs.
+func RequestIDValido(id string) bool {
+	if id == "" || len(id) > maxRequestID {
+		return false
+	}
+	for _, c := range id {
+		ok := c == '-' || c == '_' || c == '.' || c == ':' ||
+			(c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Fatal registra msg em nível error e encerra o processo (substitui log.Fatal).
+func Fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
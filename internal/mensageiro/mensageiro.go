@@ -0,0 +1,132 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/mensageiro/mensageiro.go
+/// Responsabilidade: Abstração de envio de avisos curtos por SMS e WhatsApp com provedores intercambiáveis (Twilio, Zenvia), configurados por conta.
+/// Dependências principais: context, net/http; implementações em twilio.go e zenvia.go.
+/// Pontos de atenção:
+/// - Diferente do notificador (um provedor por processo, via ambiente), aqui as credenciais são de cada conta: o chamador as lê do banco (cifradas) e chama Novo a cada envio.
+/// - Telefones são normalizados para E.164 assumindo Brasil (+55) quando vierem sem código do país; número que não fecha vira ErrTelefoneInvalido sem chamar o provedor.
+/// - WhatsApp fora da janela de 24h exige modelo aprovado no provedor; texto livre pode ser recusado por ele (o erro volta como falha do envio).
+/// - Enviar é síncrono; quem chama decide o que gravar com o erro.
+*/
+
+package mensageiro
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// Mensagem é um aviso pronto para envio.
+type Mensagem struct {
+	Canal string // CanalSMS | CanalWhatsApp
+	Para  string // telefone como cadastrado (normalizado pelo provedor)
+	Texto string
+}
+
+// Provedor entrega uma mensagem (um por serviço).
+type Provedor interface {
+	Enviar(ctx context.Context, m Mensagem) error
+}
+
+// Credenciais são os dados de acesso de uma conta ao provedor (gravados cifrados).
+type Credenciais struct {
+	Provedor  string `json:"provedor"`            // ProvedorTwilio | ProvedorZenvia
+	ContaSID  string `json:"conta_sid,omitempty"` // Twilio: Account SID
+	Token     string `json:"token"`               // Twilio: Auth Token; Zenvia: API token
+	Remetente string `json:"remetente"`           // número (Twilio) ou nome do remetente (Zenvia)
+}
+
+/// ============ Configurações & Constantes ============
+
+// Canais de envio.
+const (
+	CanalSMS      = "sms"
+	CanalWhatsApp = "whatsapp"
+)
+
+// Provedores suportados.
+const (
+	ProvedorTwilio = "twilio"
+	ProvedorZenvia = "zenvia"
+)
+
+// Canais e Provedores listam os valores aceitos (validação de payloads).
+var (
+	Canais     = []string{CanalSMS, CanalWhatsApp}
+	Provedores = []string{ProvedorTwilio, ProvedorZenvia}
+)
+
+// timeout das chamadas HTTP aos provedores
+const provedorHTTPTimeout = 15 * time.Second
+
+var (
+	ErrProvedorDesconhecido = errors.New("provedor de SMS/WhatsApp desconhecido")
+	ErrCanalDesconhecido    = errors.New("canal desconhecido (sms ou whatsapp)")
+	ErrTelefoneInvalido     = errors.New("telefone inválido")
+)
+
+/// ============ Inicialização/Bootstrap ============
+
+// Novo instancia o provedor das credenciais ("twilio" ou "zenvia").
+func Novo(c Credenciais) (Provedor, error) {
+	switch c.Provedor {
+	case ProvedorTwilio:
+		return NovoTwilio(c), nil
+	case ProvedorZenvia:
+		return NovoZenvia(c), nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrProvedorDesconhecido, c.Provedor)
+}
+
+/// ============ Funções Públicas ============
+
+// NormalizarTelefone devolve o telefone em E.164 sem o "+" (ex.: "5511987654321").
+// Sem código do país (10 ou 11 dígitos, com DDD), assume +55.
+func NormalizarTelefone(tel string) (string, error) {
+	internacional := strings.HasPrefix(strings.TrimSpace(tel), "+")
+	var b strings.Builder
+	for _, r := range tel {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	d := strings.TrimLeft(b.String(), "0")
+	switch {
+	case !internacional && (len(d) == 10 || len(d) == 11):
+		return "55" + d, nil
+	case len(d) >= 11 && len(d) <= 15:
+		return d, nil
+	}
+	return "", ErrTelefoneInvalido
+}
+
+/// ============ Funções Internas (helpers) ============
+
+// novoClienteHTTP é o cliente usado pelos provedores.
+func novoClienteHTTP() *http.Client {
+	return &http.Client{Timeout: provedorHTTPTimeout}
+}
+
+// executarHTTP faz a chamada e transforma respostas fora de 2xx em erro (com o trecho inicial do corpo).
+func executarHTTP(client *http.Client, req *http.Request, provedor string) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", provedor, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	trecho, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return fmt.Errorf("%s: HTTP %d: %s", provedor, resp.StatusCode, bytes.TrimSpace(trecho))
+}
@@ -0,0 +1,77 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/mensageiro/twilio.go
+/// Responsabilidade: Provedor de SMS/WhatsApp via API REST da Twilio (POST /2010-04-01/Accounts/{sid}/Messages.json).
+/// Dependências principais: net/http, net/url.
+/// Pontos de atenção:
+/// - Autenticação básica com Account SID e Auth Token; o remetente é um número da conta em E.164 (o mesmo serve ao WhatsApp, se habilitado nele).
+/// - WhatsApp usa os prefixos "whatsapp:" em To e From.
+/// - Sucesso = 201; a Twilio entrega de forma assíncrona (falhas posteriores só aparecem no painel dela).
+*/
+
+package mensageiro
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// Twilio envia mensagens pela API REST da Twilio.
+type Twilio struct {
+	contaSID  string
+	token     string
+	remetente string
+	endpoint  string
+	client    *http.Client
+}
+
+/// ============ Configurações & Constantes ============
+
+const twilioEndpoint = "https://api.twilio.com/2010-04-01/Accounts/"
+
+/// ============ Inicialização/Bootstrap ============
+
+// NovoTwilio cria o provedor Twilio com as credenciais da conta.
+func NovoTwilio(c Credenciais) *Twilio {
+	return &Twilio{
+		contaSID:  c.ContaSID,
+		token:     c.Token,
+		remetente: c.Remetente,
+		endpoint:  twilioEndpoint + url.PathEscape(c.ContaSID) + "/Messages.json",
+		client:    novoClienteHTTP(),
+	}
+}
+
+/// ============ Funções Públicas ============
+
+// Enviar cria a mensagem (form To/From/Body) no canal pedido.
+func (t *Twilio) Enviar(ctx context.Context, m Mensagem) error {
+	para, err := NormalizarTelefone(m.Para)
+	if err != nil {
+		return err
+	}
+	de := t.remetente
+	if !strings.HasPrefix(de, "+") {
+		de = "+" + de
+	}
+	para = "+" + para
+	switch m.Canal {
+	case CanalSMS:
+	case CanalWhatsApp:
+		para, de = "whatsapp:"+para, "whatsapp:"+de
+	default:
+		return ErrCanalDesconhecido
+	}
+	form := url.Values{"To": {para}, "From": {de}, "Body": {m.Texto}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(t.contaSID, t.token)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return executarHTTP(t.client, req, "twilio")
+}
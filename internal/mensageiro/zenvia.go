@@ -0,0 +1,68 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/mensageiro/zenvia.go
+/// Responsabilidade: Provedor de SMS/WhatsApp via API v2 da Zenvia (POST /v2/channels/{canal}/messages).
+/// Dependências principais: net/http, encoding/json.
+/// Pontos de atenção:
+/// - Autenticação pelo cabeçalho X-API-TOKEN; o remetente é o nome/keyword (SMS) ou o número (WhatsApp) cadastrado na Zenvia.
+/// - Destino em E.164 sem o "+" (ex.: 5511987654321).
+/// - Sucesso = 200; o status de entrega chega depois por webhook da Zenvia (não usado aqui).
+*/
+
+package mensageiro
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// Zenvia envia mensagens pela API v2 da Zenvia.
+type Zenvia struct {
+	token     string
+	remetente string
+	endpoint  string // base; o canal entra no caminho
+	client    *http.Client
+}
+
+/// ============ Configurações & Constantes ============
+
+const zenviaEndpoint = "https://api.zenvia.com/v2/channels/"
+
+/// ============ Inicialização/Bootstrap ============
+
+// NovoZenvia cria o provedor Zenvia com as credenciais da conta.
+func NovoZenvia(c Credenciais) *Zenvia {
+	return &Zenvia{token: c.Token, remetente: c.Remetente, endpoint: zenviaEndpoint, client: novoClienteHTTP()}
+}
+
+/// ============ Funções Públicas ============
+
+// Enviar publica a mensagem de texto no canal pedido (sms ou whatsapp).
+func (z *Zenvia) Enviar(ctx context.Context, m Mensagem) error {
+	if m.Canal != CanalSMS && m.Canal != CanalWhatsApp {
+		return ErrCanalDesconhecido
+	}
+	para, err := NormalizarTelefone(m.Para)
+	if err != nil {
+		return err
+	}
+	corpo, err := json.Marshal(map[string]any{
+		"from":     z.remetente,
+		"to":       para,
+		"contents": []any{map[string]string{"type": "text", "text": m.Texto}},
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, z.endpoint+m.Canal+"/messages", bytes.NewReader(corpo))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-API-TOKEN", z.token)
+	req.Header.Set("Content-Type", "application/json")
+	return executarHTTP(z.client, req, "zenvia")
+}
@@ -0,0 +1,190 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/middleware/autenticacao.go
+/// Responsabilidade: Resolver uma única vez por requisição o usuário da sessão (Authorization: Bearer), do X-User-Email ou do X-Impersonation-Token do suporte e guardá-lo no context.
+/// Dependências principais: context, database/sql, errors, net/http, strings, time, backend/internal/model (CacheAcesso, personificação), backend/internal/apierr, backend/internal/logging.
+/// Pontos de atenção:
+/// - Não responde 401 por falta de usuário: sem cabeçalho ou com e-mail desconhecido a requisição segue sem usuário e o handler decide (rotas públicas continuam públicas).
+/// - Authorization: Bearer <token> (emitido no login, tabela sessoes) tem precedência sobre X-User-Email, que é reescrito com o e-mail da sessão;
+///   token revogado (DELETE /api/usuario/sessoes/{id}), expirado ou de conta bloqueada responde 401 SESSAO_INVALIDA aqui mesmo.
+/// - Com exigirSessao (AUTH_REQUIRE_SESSION) o X-User-Email sem sessão é descartado: a requisição segue sem usuário e as rotas autenticadas respondem 401.
+///   Sem ele, revogar uma sessão só barra o token: o cliente que passar a mandar só o X-User-Email continua autenticado.
+///   Revogação (e logout) só valem de fato no modo sessão; as rotas /api/admin exigem a sessão sempre (ExigirSuporte).
+/// - A resolução passa pelo CacheAcesso (e-mail → Acesso com TTL); handlers e ExigirEscritaMiddleware leem o context com AcessoDe.
+/// - X-Impersonation-Token (POST /api/admin/impersonate) tem precedência sobre X-User-Email: a requisição segue como a conta personificada
+///   (o cabeçalho X-User-Email é reescrito para ela) com Acesso.PersonificadoPor preenchido, e cada requisição vai para o log com suporte_id.
+///   Toda escrita (POST/PUT/PATCH/DELETE) personificada também vai para a auditoria (requisicao.executada, com personificado_por e o status),
+///   registrada aqui depois da resposta, mesmo nas rotas cujo handler não chama registrarAtividade.
+///   Token inválido ou expirado responde 401 aqui mesmo, para não cair silenciosamente na conta do próprio suporte.
+/// - Deve vir depois de RequestID (logs com request_id) e antes dos middlewares que dependem do usuário.
+*/
+
+package middleware
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"backend/internal/apierr"
+	"backend/internal/logging"
+	"backend/internal/model"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// chave privada do Acesso no context
+type chaveAcesso struct{}
+
+/// ============ Configurações & Constantes ============
+
+// HeaderPersonificacao carrega o token emitido por POST /api/admin/impersonate.
+const HeaderPersonificacao = "X-Impersonation-Token"
+
+// prefixo do token de sessão no cabeçalho Authorization
+const prefixoBearer = "bearer "
+
+/// ============ Funções Públicas (Middlewares) ============
+
+// Autenticacao resolve a sessão ou o X-User-Email (via cache) e injeta o model.Acesso no context.
+// exigirSessao descarta o X-User-Email de requisições sem sessão.
+func Autenticacao(db *sql.DB, cache *model.CacheAcesso, exigirSessao bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if token := strings.TrimSpace(r.Header.Get(HeaderPersonificacao)); token != "" {
+				personificar(w, r, next, db, cache, token)
+				return
+			}
+			if token := tokenSessao(r); token != "" {
+				autenticarSessao(w, r, next, db, cache, token)
+				return
+			}
+			if exigirSessao {
+				r.Header.Del("X-User-Email")
+				next.ServeHTTP(w, r)
+				return
+			}
+			email := model.NormalizarEmail(r.Header.Get("X-User-Email"))
+			if email == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+			acesso, err := cache.Resolver(ctx, db, email)
+			cancel()
+			switch {
+			case err == nil:
+				r = r.WithContext(ComAcesso(r.Context(), acesso))
+			case err != sql.ErrNoRows:
+				// falha de banco: o handler tenta de novo e responde como antes
+				logging.De(r.Context()).Warn("autenticação: resolver usuário", "erro", err)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+/// ============ Funções Internas (helpers) ============
+
+// personificar resolve o token do suporte e segue como a conta personificada (401 se inválido).
+func personificar(w http.ResponseWriter, r *http.Request, next http.Handler, db *sql.DB, cache *model.CacheAcesso, token string) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	suporteID, email, err := model.ResolverPersonificacao(ctx, db, token)
+	var acesso model.Acesso
+	if err == nil {
+		acesso, err = cache.Resolver(ctx, db, email)
+	}
+	cancel()
+	if err != nil {
+		if !errors.Is(err, model.ErrPersonificacaoInvalida) && err != sql.ErrNoRows {
+			logging.De(r.Context()).Warn("autenticação: resolver personificação", "erro", err)
+		}
+		apierr.Escrever(w, http.StatusUnauthorized, apierr.PersonificacaoInvalida, model.ErrPersonificacaoInvalida.Error(), nil)
+		return
+	}
+
+	acesso.PersonificadoPor = suporteID
+	r.Header.Set("X-User-Email", email)
+	logging.De(r.Context()).Info("personificação: requisição em nome do usuário",
+		"suporte_id", suporteID, "usuario_id", acesso.UsuarioID, "metodo", r.Method, "caminho", r.URL.Path)
+	r = r.WithContext(ComAcesso(r.Context(), acesso))
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		next.ServeHTTP(w, r)
+		return
+	}
+
+	rw := &respostaRegistrada{ResponseWriter: w}
+	next.ServeHTTP(rw, r)
+	if rw.status == 0 {
+		rw.status = http.StatusOK
+	}
+	auditarPersonificacao(r, db, acesso, rw.status)
+}
+
+// auditarPersonificacao grava a escrita feita pelo suporte na auditoria do tenant
+// (falha só vai para o log: a resposta já foi enviada).
+func auditarPersonificacao(r *http.Request, db *sql.DB, acesso model.Acesso, status int) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), 5*time.Second)
+	defer cancel()
+	err := model.RegistrarAtividade(ctx, db, model.NovaAtividade{
+		TenantID:         acesso.TenantID,
+		AutorID:          acesso.UsuarioID,
+		PersonificadoPor: acesso.PersonificadoPor,
+		Acao:             model.AcaoRequisicaoPersonificada,
+		Resumo:           r.Method + " " + r.URL.Path,
+		Dados:            map[string]any{"metodo": r.Method, "caminho": r.URL.Path, "status": status},
+	})
+	if err != nil {
+		logging.De(r.Context()).Error("personificação: falha ao auditar escrita",
+			"suporte_id", acesso.PersonificadoPor, "usuario_id", acesso.UsuarioID, "caminho", r.URL.Path, "erro", err)
+	}
+}
+
+// tokenSessao devolve o token de Authorization: Bearer ("" sem o cabeçalho).
+func tokenSessao(r *http.Request) string {
+	h := strings.TrimSpace(r.Header.Get("Authorization"))
+	if len(h) <= len(prefixoBearer) || !strings.EqualFold(h[:len(prefixoBearer)], prefixoBearer) {
+		return ""
+	}
+	return strings.TrimSpace(h[len(prefixoBearer):])
+}
+
+// autenticarSessao resolve o token de sessão e segue como a conta dele (401 se inválido).
+func autenticarSessao(w http.ResponseWriter, r *http.Request, next http.Handler, db *sql.DB, cache *model.CacheAcesso, token string) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	sessaoID, email, err := model.ResolverSessao(ctx, db, token)
+	var acesso model.Acesso
+	if err == nil {
+		acesso, err = cache.Resolver(ctx, db, email)
+	}
+	cancel()
+	if err != nil {
+		if !errors.Is(err, model.ErrSessaoInvalida) && err != sql.ErrNoRows {
+			logging.De(r.Context()).Warn("autenticação: resolver sessão", "erro", err)
+		}
+		apierr.Escrever(w, http.StatusUnauthorized, apierr.SessaoInvalida, model.ErrSessaoInvalida.Error(), nil)
+		return
+	}
+
+	acesso.SessaoID = sessaoID
+	r.Header.Set("X-User-Email", email)
+	next.ServeHTTP(w, r.WithContext(ComAcesso(r.Context(), acesso)))
+}
+
+/// ============ Funções Públicas (context) ============
+
+// ComAcesso devolve um context com o usuário resolvido.
+func ComAcesso(ctx context.Context, a model.Acesso) context.Context {
+	return context.WithValue(ctx, chaveAcesso{}, a)
+}
+
+// AcessoDe devolve o usuário resolvido pelo middleware Autenticacao (ok=false se não houver).
+func AcessoDe(ctx context.Context) (model.Acesso, bool) {
+	a, ok := ctx.Value(chaveAcesso{}).(model.Acesso)
+	return a, ok
+}
@@ -0,0 +1,79 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/middleware/cache.go
+/// Responsabilidade: Invalidar o cache dos dados do tenant (lista de anos com contagens) depois de cada escrita bem-sucedida.
+/// Dependências principais: context, net/http, backend/internal/cache, backend/internal/model (ChaveCacheAnos).
+/// Pontos de atenção:
+/// - Depende do usuário resolvido por Autenticacao; sem ele (401) não há o que invalidar.
+/// - Só escritas com status < 400 invalidam, no momento em que o status é enviado (antes de o cliente receber a resposta).
+/// - GraphQL (mutations via POST) entra na mesma regra.
+/// - Escritas fora da API (CLI, SQL manual) só aparecem após o TTL (CACHE_TTL_ANOS).
+*/
+
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"backend/internal/cache"
+	"backend/internal/model"
+)
+
+/// ============ Funções Públicas (Middlewares) ============
+
+// InvalidarCacheDados apaga as chaves de cache do tenant quando a requisição altera dados.
+func InvalidarCacheDados(c cache.Cache) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				next.ServeHTTP(w, r)
+				return
+			}
+			acesso, ok := AcessoDe(r.Context())
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			rw := &respostaInvalidadora{ResponseWriter: w, invalidar: func() {
+				cache.Invalidar(context.WithoutCancel(r.Context()), c, model.ChaveCacheAnos(acesso.TenantID))
+			}}
+			next.ServeHTTP(rw, r)
+			if !rw.enviado { // handler sem corpo nem status explícito: 200 implícito
+				rw.invalidar()
+			}
+		})
+	}
+}
+
+/// ============ Funções Internas (helpers) ============
+
+// respostaInvalidadora invalida antes de enviar o status (sucesso), para que o cliente
+// que reage à resposta já leia dados novos.
+type respostaInvalidadora struct {
+	http.ResponseWriter
+	invalidar func()
+	enviado   bool
+}
+
+func (rw *respostaInvalidadora) WriteHeader(status int) {
+	if !rw.enviado {
+		rw.enviado = true
+		if status < http.StatusBadRequest {
+			rw.invalidar()
+		}
+	}
+	rw.ResponseWriter.WriteHeader(status)
+}
+
+func (rw *respostaInvalidadora) Write(b []byte) (int, error) {
+	if !rw.enviado {
+		rw.WriteHeader(http.StatusOK)
+	}
+	return rw.ResponseWriter.Write(b)
+}
+
+// Unwrap permite que http.ResponseController alcance o writer original (Flush, deadlines).
+func (rw *respostaInvalidadora) Unwrap() http.ResponseWriter { return rw.ResponseWriter }
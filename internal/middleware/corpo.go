@@ -0,0 +1,68 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/middleware/corpo.go
+/// Responsabilidade: Limite global de tamanho do corpo e exigência de Content-Type application/json nas rotas JSON.
+/// Dependências principais: net/http, mime, backend/internal/apierr.
+/// Pontos de atenção:
+/// - Content-Length acima do limite responde 413 antes de ler; corpos chunked são cortados pelo MaxBytesReader
+///   e o handler responde 413 via writeDecodeError.
+/// - Requisições sem corpo (GET, DELETE, POST sem payload) passam sem exigir Content-Type.
+/// - Rotas multipart (POST /api/uploads, POST /api/estudantes/{id}/documentos) NÃO usam este middleware;
+///   elas aplicam limites próprios (maxUploadSize/maxDocumentoSize).
+/// - Aceita application/json e variantes "+json" (ex.: application/merge-patch+json), com ou sem charset.
+*/
+
+package middleware
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+
+	"backend/internal/apierr"
+)
+
+/// ============ Funções Públicas (Middlewares) ============
+
+// CorpoJSON limita o corpo a limite bytes e responde 415 para corpos que não sejam JSON.
+func CorpoJSON(limite int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !temCorpo(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if r.ContentLength > limite {
+				apierr.Escrever(w, http.StatusRequestEntityTooLarge, apierr.PayloadMuitoGrande,
+					"Corpo da requisição muito grande", map[string]int64{"limite_bytes": limite})
+				return
+			}
+			if !ehJSON(r.Header.Get("Content-Type")) {
+				apierr.Escrever(w, http.StatusUnsupportedMediaType, apierr.TipoNaoSuportado,
+					"Content-Type deve ser application/json", nil)
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, limite)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+/// ============ Funções Internas (helpers) ============
+
+// temCorpo indica se a requisição traz payload (Content-Length > 0 ou chunked).
+func temCorpo(r *http.Request) bool {
+	if r.Body == nil || r.Body == http.NoBody {
+		return false
+	}
+	return r.ContentLength != 0
+}
+
+// ehJSON aceita application/json e tipos "+json".
+func ehJSON(contentType string) bool {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mt == "application/json" || (strings.HasPrefix(mt, "application/") && strings.HasSuffix(mt, "+json"))
+}
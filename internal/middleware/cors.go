@@ -0,0 +1,125 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/middleware/cors.go
+/// Responsabilidade: Middleware CORS configurável (origens, inclusive curinga de subdomínio, métodos, cabeçalhos, credenciais, max-age) a partir de config.CORS.
+/// Dependências principais: net/http, strconv, strings, backend/internal/config.
+/// Pontos de atenção:
+/// - É o único CORS do projeto (cmd/server/main.go aplica Cors(cfg.CORS) no grupo base de rotas).
+/// - Quando CORS_ALLOW_CREDENTIALS=true, Access-Control-Allow-Origin nunca será "*" (espelha a Origin permitida).
+/// - Access-Control-Expose-Headers vem de CORS_EXPOSE_HEADERS (ETag para If-Match, X-Request-ID para suporte).
+/// - "Vary: Origin" só é enviado quando a resposta depende da Origin (origem espelhada); com "*" aberto a resposta é a mesma para todos.
+/// - Curinga só no primeiro rótulo do host: "https://*.minhaescola.com.br" aceita https://app.minhaescola.com.br (e níveis abaixo), não o domínio raiz nem outro esquema/porta.
+/// - Origin "null" (iframe sandbox, file://, redirecionamentos) nunca é aceita: qualquer página consegue enviá-la.
+*/
+
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"backend/internal/config"
+)
+
+/// ============ Configurações & Constantes ============
+
+/**
+ * originAllowed verifica se uma origem é aceita pela lista configurada.
+ * Regras:
+ * - Lista vazia ou Origin "null" -> false
+ * - Primeiro item "*" -> qualquer outra origem permitida
+ * - Item "esquema://*.dominio" -> Origin com o mesmo esquema e host terminado em ".dominio"
+ * - Caso contrário, compara igualdade literal com a Origin recebida
+ */
+func originAllowed(origin string, allowed []string) bool {
+	if len(allowed) == 0 || origin == "null" {
+		return false
+	}
+	if allowed[0] == "*" {
+		return true
+	}
+	for _, o := range allowed {
+		if o == origin {
+			return true
+		}
+		if esquema, sufixo, ok := strings.Cut(o, "://*."); ok &&
+			strings.HasPrefix(origin, esquema+"://") && strings.HasSuffix(origin, "."+sufixo) {
+			// o subdomínio não pode conter porta, caminho ou userinfo ("://evil.com/.x" etc.)
+			sub := strings.TrimSuffix(strings.TrimPrefix(origin, esquema+"://"), "."+sufixo)
+			if sub != "" && !strings.ContainsAny(sub, "/:@?#") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+/// ============ Funções Públicas (Middlewares) ============
+
+/**
+ * Cors devolve o middleware que adiciona cabeçalhos CORS e trata pré-flight (OPTIONS).
+ *
+ * Configuração (config.CORS):
+ * - AllowOrigins (lista ou "*")
+ * - AllowMethods / AllowHeaders / ExposeHeaders
+ * - MaxAge (segundos)
+ * - AllowCredentials
+ *
+ * Comportamento:
+ * - Adiciona "Vary: Origin" sempre que espelha a Origin (resposta varia por origem).
+ * - Se credenciais habilitadas, espelha a Origin permitida e define "Access-Control-Allow-Credentials: true".
+ * - Caso contrário, usa "*" se habilitado globalmente, ou espelha Origins específicas.
+ * - Responde 200 em OPTIONS com cabeçalhos CORS configurados.
+ */
+func Cors(cfg config.CORS) func(http.Handler) http.Handler {
+	allowedOrigins := cfg.AllowOrigins
+	allowedMethods := cfg.AllowMethods
+	allowedHeaders := cfg.AllowHeaders
+	exposedHeaders := cfg.ExposeHeaders
+	maxAge := strconv.Itoa(cfg.MaxAge)
+	allowCreds := cfg.AllowCredentials
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+
+			// Definição de origem permitida
+			if allowCreds {
+				// Com credenciais não podemos usar "*"
+				w.Header().Add("Vary", "Origin")
+				if origin != "" && originAllowed(origin, allowedOrigins) {
+					w.Header().Set("Access-Control-Allow-Origin", origin)
+					w.Header().Set("Access-Control-Allow-Credentials", "true")
+				}
+			} else {
+				// Modo aberto por padrão
+				if len(allowedOrigins) > 0 && allowedOrigins[0] == "*" {
+					w.Header().Set("Access-Control-Allow-Origin", "*")
+				} else {
+					// Origem espelhada: caches precisam variar por Origin
+					w.Header().Add("Vary", "Origin")
+					if origin != "" && originAllowed(origin, allowedOrigins) {
+						w.Header().Set("Access-Control-Allow-Origin", origin)
+					}
+				}
+			}
+
+			// Métodos e cabeçalhos
+			w.Header().Set("Access-Control-Allow-Methods", allowedMethods)
+			w.Header().Set("Access-Control-Allow-Headers", allowedHeaders)
+			w.Header().Set("Access-Control-Max-Age", maxAge)
+			if exposedHeaders != "" {
+				w.Header().Set("Access-Control-Expose-Headers", exposedHeaders)
+			}
+
+			// Pré-flight
+			if r.Method == http.MethodOptions {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
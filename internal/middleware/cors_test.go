@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend/internal/config"
+)
+
+func TestOriginAllowed(t *testing.T) {
+	lista := []string{"https://app.tecmise.com.br", "https://*.minhaescola.com.br"}
+	casos := []struct {
+		origin    string
+		permitida []string
+		ok        bool
+	}{
+		{"https://app.tecmise.com.br", lista, true},
+		{"https://sede.minhaescola.com.br", lista, true},
+		{"https://a.b.minhaescola.com.br", lista, true}, // níveis abaixo também
+		{"https://minhaescola.com.br", lista, false},    // domínio raiz
+		{"https://.minhaescola.com.br", lista, false},   // rótulo vazio
+		{"http://sede.minhaescola.com.br", lista, false},
+		{"https://sede.minhaescola.com.br:8443", lista, false},
+		{"https://evilminhaescola.com.br", lista, false},
+		{"https://sede.minhaescola.com.br.evil.com", lista, false},
+		{"https://evil.com/.minhaescola.com.br", lista, false},
+		{"https://evil.com?.minhaescola.com.br", lista, false},
+		{"https://user@evil.com#.minhaescola.com.br", lista, false},
+		{"https://app.tecmise.com.br.evil.com", lista, false},
+		{"null", lista, false},
+		{"null", []string{"*"}, false},
+		{"null", []string{"null"}, false},
+		{"https://qualquer.com", []string{"*"}, true},
+		{"https://app.tecmise.com.br", nil, false},
+		{"", lista, false},
+	}
+	for _, c := range casos {
+		if got := originAllowed(c.origin, c.permitida); got != c.ok {
+			t.Errorf("originAllowed(%q, %q) = %v, esperado %v", c.origin, c.permitida, got, c.ok)
+		}
+	}
+}
+
+// Com credenciais a Origin é espelhada, então "null" não pode passar nem com "*".
+func TestCorsCredenciaisNaoEspelhaNull(t *testing.T) {
+	h := Cors(config.CORS{AllowOrigins: []string{"*"}, AllowCredentials: true})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	for origin, espelhada := range map[string]bool{"null": false, "https://app.tecmise.com.br": true} {
+		req := httptest.NewRequest(http.MethodGet, "/api/estudantes", nil)
+		req.Header.Set("Origin", origin)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		got := rec.Header().Get("Access-Control-Allow-Origin")
+		if espelhada && (got != origin || rec.Header().Get("Access-Control-Allow-Credentials") != "true") {
+			t.Errorf("%s: Allow-Origin = %q, esperado espelhado com credenciais", origin, got)
+		}
+		if !espelhada && (got != "" || rec.Header().Get("Access-Control-Allow-Credentials") != "") {
+			t.Errorf("%s: Allow-Origin = %q, esperado ausente", origin, got)
+		}
+		if rec.Header().Get("Vary") != "Origin" {
+			t.Errorf("%s: sem Vary: Origin", origin)
+		}
+	}
+}
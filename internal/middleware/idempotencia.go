@@ -0,0 +1,227 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/middleware/idempotencia.go
+/// Responsabilidade: Honrar o cabeçalho Idempotency-Key em POSTs, guardando a resposta na tabela idempotencia e repetindo-a em novas tentativas.
+/// Dependências principais: database/sql (Postgres), crypto/sha256, net/http, backend/internal/apierr, backend/internal/logging.
+/// Pontos de atenção:
+/// - Sem Idempotency-Key (ou sem X-User-Email) a requisição segue normalmente; o cabeçalho é opcional.
+/// - A chave é reservada antes de chamar o handler (INSERT ... ON CONFLICT DO NOTHING): duas tentativas simultâneas
+///   não executam o handler duas vezes; a segunda recebe 409 IDEMPOTENCIA_EM_ANDAMENTO.
+/// - Respostas 5xx não são guardadas: a reserva é desfeita para que o cliente possa tentar de novo.
+/// - Mesma chave com outro método/caminho/corpo responde 422 IDEMPOTENCIA_CHAVE_REUTILIZADA.
+/// - Deve vir depois de CorpoJSON na cadeia (o corpo é lido inteiro para calcular o hash).
+*/
+
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"backend/internal/apierr"
+	"backend/internal/logging"
+	"backend/internal/model"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// respostaGravada repassa a resposta ao cliente e guarda uma cópia para o snapshot.
+type respostaGravada struct {
+	http.ResponseWriter
+	status int
+	corpo  bytes.Buffer
+}
+
+/// ============ Configurações & Constantes ============
+
+// HeaderIdempotencia é o cabeçalho com a chave escolhida pelo cliente.
+const HeaderIdempotencia = "Idempotency-Key"
+
+const (
+	idempotenciaTTL        = 24 * time.Hour
+	idempotenciaTimeout    = 5 * time.Second
+	maxChaveIdempotencia   = 255
+	headerRespostaRepetida = "Idempotent-Replayed"
+)
+
+/// ============ Funções Públicas (Middlewares) ============
+
+// Idempotencia guarda a primeira resposta de cada Idempotency-Key (por usuário) e a devolve
+// nas repetições, sem executar o handler de novo.
+func Idempotencia(db *sql.DB) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			chave := strings.TrimSpace(r.Header.Get(HeaderIdempotencia))
+			email := model.NormalizarEmail(r.Header.Get("X-User-Email"))
+			if r.Method != http.MethodPost || chave == "" || email == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(chave) > maxChaveIdempotencia {
+				apierr.Escrever(w, http.StatusBadRequest, apierr.IdempotenciaChaveInvalida,
+					"Idempotency-Key muito longa (máximo 255 caracteres)", nil)
+				return
+			}
+
+			corpo, err := io.ReadAll(r.Body)
+			if err != nil {
+				var grande *http.MaxBytesError
+				if errors.As(err, &grande) {
+					apierr.Escrever(w, http.StatusRequestEntityTooLarge, "", "Corpo da requisição muito grande", nil)
+					return
+				}
+				apierr.Escrever(w, http.StatusBadRequest, "", "Falha ao ler corpo da requisição", nil)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(corpo))
+			hash := hashRequisicao(r, corpo)
+
+			ctx, cancel := context.WithTimeout(r.Context(), idempotenciaTimeout)
+			defer cancel()
+			reservada, err := reservarChave(ctx, db, email, chave, hash)
+			if err != nil {
+				logging.De(r.Context()).Error("idempotência: reservar chave", "erro", err)
+				apierr.Escrever(w, http.StatusInternalServerError, apierr.ErroInterno, "Erro ao verificar Idempotency-Key", nil)
+				return
+			}
+			if !reservada {
+				repetirResposta(ctx, w, r, db, email, chave, hash)
+				return
+			}
+
+			rw := &respostaGravada{ResponseWriter: w}
+			concluida := false
+			defer func() {
+				// contexto próprio: a resposta já foi enviada e o cliente pode ter desconectado
+				ctxSalvar, cancelSalvar := context.WithTimeout(context.Background(), idempotenciaTimeout)
+				defer cancelSalvar()
+				var err error
+				if !concluida || rw.status >= http.StatusInternalServerError {
+					// panic (recoverMiddleware responde 500) ou 5xx: libera a chave para nova tentativa
+					err = liberarChave(ctxSalvar, db, email, chave)
+				} else {
+					err = salvarResposta(ctxSalvar, db, email, chave, rw.status, rw.Header().Get("Content-Type"), rw.corpo.Bytes())
+				}
+				if err != nil {
+					logging.De(r.Context()).Error("idempotência: gravar resposta", "erro", err)
+				}
+			}()
+			next.ServeHTTP(rw, r)
+			if rw.status == 0 {
+				rw.status = http.StatusOK
+			}
+			concluida = true
+		})
+	}
+}
+
+/// ============ Funções Internas (helpers) ============
+
+func (rw *respostaGravada) WriteHeader(status int) {
+	if rw.status == 0 {
+		rw.status = status
+	}
+	rw.ResponseWriter.WriteHeader(status)
+}
+
+func (rw *respostaGravada) Write(b []byte) (int, error) {
+	if rw.status == 0 {
+		rw.status = http.StatusOK
+	}
+	rw.corpo.Write(b)
+	return rw.ResponseWriter.Write(b)
+}
+
+// Unwrap permite que http.ResponseController alcance o writer original.
+func (rw *respostaGravada) Unwrap() http.ResponseWriter { return rw.ResponseWriter }
+
+// hashRequisicao identifica a requisição original (método, caminho e corpo).
+func hashRequisicao(r *http.Request, corpo []byte) string {
+	h := sha256.New()
+	io.WriteString(h, r.Method+" "+r.URL.Path+"\n")
+	h.Write(corpo)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// reservarChave cria o registro "em andamento"; false se a chave já existia (e não expirou).
+func reservarChave(ctx context.Context, db *sql.DB, email, chave, hash string) (bool, error) {
+	if _, err := db.ExecContext(ctx,
+		`DELETE FROM idempotencia WHERE usuario_email=$1 AND chave=$2 AND criada_em < $3`,
+		email, chave, time.Now().Add(-idempotenciaTTL),
+	); err != nil {
+		return false, err
+	}
+	res, err := db.ExecContext(ctx, `
+		INSERT INTO idempotencia (usuario_email, chave, hash_requisicao)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (usuario_email, chave) DO NOTHING
+	`, email, chave, hash)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+// repetirResposta devolve o snapshot guardado (ou o erro adequado se não puder).
+func repetirResposta(ctx context.Context, w http.ResponseWriter, r *http.Request, db *sql.DB, email, chave, hash string) {
+	var (
+		hashOriginal string
+		status       sql.NullInt64
+		contentType  string
+		corpo        []byte
+	)
+	err := db.QueryRowContext(ctx, `
+		SELECT hash_requisicao, status, content_type, corpo
+		  FROM idempotencia
+		 WHERE usuario_email=$1 AND chave=$2
+	`, email, chave).Scan(&hashOriginal, &status, &contentType, &corpo)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		// a original falhou (5xx) e liberou a chave entre o INSERT e este SELECT
+		apierr.Escrever(w, http.StatusConflict, apierr.IdempotenciaEmAndamento,
+			"Requisição com esta Idempotency-Key ainda em processamento; tente novamente", nil)
+	case err != nil:
+		logging.De(r.Context()).Error("idempotência: ler resposta", "erro", err)
+		apierr.Escrever(w, http.StatusInternalServerError, apierr.ErroInterno, "Erro ao verificar Idempotency-Key", nil)
+	case hashOriginal != hash:
+		apierr.Escrever(w, http.StatusUnprocessableEntity, apierr.IdempotenciaChaveReutilizada,
+			"Idempotency-Key já usada com outra requisição", nil)
+	case !status.Valid:
+		apierr.Escrever(w, http.StatusConflict, apierr.IdempotenciaEmAndamento,
+			"Requisição com esta Idempotency-Key ainda em processamento; tente novamente", nil)
+	default:
+		if contentType != "" {
+			w.Header().Set("Content-Type", contentType)
+		}
+		w.Header().Set(headerRespostaRepetida, "true")
+		w.WriteHeader(int(status.Int64))
+		_, _ = w.Write(corpo)
+	}
+}
+
+// salvarResposta grava o snapshot da resposta original.
+func salvarResposta(ctx context.Context, db *sql.DB, email, chave string, status int, contentType string, corpo []byte) error {
+	_, err := db.ExecContext(ctx, `
+		UPDATE idempotencia SET status=$3, content_type=$4, corpo=$5
+		 WHERE usuario_email=$1 AND chave=$2
+	`, email, chave, status, contentType, corpo)
+	return err
+}
+
+// liberarChave desfaz a reserva (resposta 5xx não é guardada).
+func liberarChave(ctx context.Context, db *sql.DB, email, chave string) error {
+	_, err := db.ExecContext(ctx,
+		`DELETE FROM idempotencia WHERE usuario_email=$1 AND chave=$2 AND status IS NULL`,
+		email, chave,
+	)
+	return err
+}
@@ -0,0 +1,30 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/middleware/idioma.go
+/// Responsabilidade: Escolher o idioma das mensagens de erro (pt-BR ou en) pelo Accept-Language de cada requisição.
+/// Dependências principais: net/http, backend/internal/i18n.
+/// Pontos de atenção:
+/// - O idioma vai no Content-Language da resposta antes do handler: apierr.Escrever o lê de lá (i18n.DaResposta).
+/// - Roda antes do roteador (envolve o Router em cmd/server/main.go), para cobrir também 404/405 e os erros dos demais middlewares.
+/// - Vary: Accept-Language avisa caches HTTP que a mesma URL pode ter corpos diferentes por idioma.
+*/
+
+package middleware
+
+import (
+	"net/http"
+
+	"backend/internal/i18n"
+)
+
+/// ============ Funções Públicas (Middlewares) ============
+
+// Idioma negocia o idioma da resposta (i18n.Negociar) e o grava em Content-Language.
+func Idioma(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h := w.Header()
+		h.Set("Content-Language", i18n.Negociar(r.Header.Get("Accept-Language")))
+		h.Add("Vary", "Accept-Language")
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,53 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/middleware/ip_cliente.go
+/// Responsabilidade: Determinar o IP do cliente (r.RemoteAddr) quando a API roda atrás de um proxy reverso (HTTP_TRUST_PROXY).
+/// Dependências principais: net, net/http, strings.
+/// Pontos de atenção:
+/// - Só confie no X-Forwarded-For com um proxy na frente: sem ele, qualquer cliente escolheria o próprio IP.
+/// - Usa o último endereço da lista (o que o proxy viu); os anteriores vêm do cliente e não são confiáveis.
+/// - Valor inválido no cabeçalho é ignorado (fica o RemoteAddr da conexão).
+*/
+
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+/// ============ Funções Públicas (Middlewares) ============
+
+// IPCliente, com confiarProxy, troca r.RemoteAddr pelo IP que o proxy reverso
+// anexou ao X-Forwarded-For. Sem confiarProxy, não faz nada.
+func IPCliente(confiarProxy bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if !confiarProxy {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			lista := r.Header.Values("X-Forwarded-For")
+			if len(lista) > 0 {
+				partes := strings.Split(lista[len(lista)-1], ",")
+				if ip := net.ParseIP(strings.TrimSpace(partes[len(partes)-1])); ip != nil {
+					r2 := *r
+					r2.RemoteAddr = net.JoinHostPort(ip.String(), "0")
+					r = &r2
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+/// ============ Funções Públicas (helpers) ============
+
+// IPDe devolve o IP do cliente (r.RemoteAddr sem a porta).
+func IPDe(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,61 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/middleware/metodo.go
+/// Responsabilidade: X-HTTP-Method-Override para redes (proxies de escola) que só deixam passar GET e POST (HTTP_METHOD_OVERRIDE).
+/// Dependências principais: net/http, strings, backend/internal/apierr.
+/// Pontos de atenção:
+/// - Só POST pode ser sobreposto, e só por PUT, PATCH ou DELETE: GET nunca vira escrita, e POST continua sendo o
+///   método "inseguro" que proxies e navegadores já tratam como tal.
+/// - Roda antes do roteador (envolve o Router em cmd/server/main.go): o método trocado é o que casa a rota, passa pelos
+///   middlewares e aparece nos logs.
+/// - Valor não suportado responde 400 em vez de seguir como POST, para o cliente não gravar algo por engano.
+/// - Com o override ligado, config acrescenta o cabeçalho a CORS_ALLOW_HEADERS (senão o pré-flight o recusa).
+*/
+
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"backend/internal/apierr"
+)
+
+/// ============ Configurações & Constantes ============
+
+// CabecalhoMethodOverride é o cabeçalho lido por SobreporMetodo.
+const CabecalhoMethodOverride = "X-HTTP-Method-Override"
+
+// métodos que um POST pode assumir
+var metodosSobrepostos = map[string]bool{
+	http.MethodPut:    true,
+	http.MethodPatch:  true,
+	http.MethodDelete: true,
+}
+
+/// ============ Funções Públicas (Middlewares) ============
+
+// SobreporMetodo, com ativo, troca o método de um POST pelo informado em
+// X-HTTP-Method-Override (PUT, PATCH ou DELETE). Sem ativo, não faz nada.
+func SobreporMetodo(ativo bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if !ativo {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			metodo := strings.ToUpper(strings.TrimSpace(r.Header.Get(CabecalhoMethodOverride)))
+			if metodo == "" || r.Method != http.MethodPost {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if !metodosSobrepostos[metodo] {
+				apierr.Escrever(w, http.StatusBadRequest, apierr.RequisicaoInvalida,
+					CabecalhoMethodOverride+" aceita apenas PUT, PATCH ou DELETE", nil)
+				return
+			}
+			r2 := *r
+			r2.Method = metodo
+			next.ServeHTTP(w, &r2)
+		})
+	}
+}
@@ -0,0 +1,84 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/middleware/papel.go
+/// Responsabilidade: Bloquear escrita (POST/PUT/DELETE) para membros de organização com papel somente leitura e restringir as rotas /api/admin à equipe de suporte.
+/// Dependências principais: context, database/sql, net/http, strings, time, backend/internal/model (ResolverAcesso), backend/internal/apierr.
+/// Pontos de atenção:
+/// - Sem X-User-Email ou usuário desconhecido, a requisição segue adiante: o handler responde 401 como antes.
+/// - GET/HEAD/OPTIONS nunca são bloqueados; usa o usuário já resolvido por Autenticacao (sem ele, consulta o banco).
+/// - Aplicar somente nas rotas de dados (estudantes, anos, avaliações...) e nos uploads (gravam no storage do tenant, inclusive a foto de perfil); perfil (JSON) e tutorial continuam liberados.
+/// - ExigirSuporte, ao contrário, responde 401/403 por conta própria: rotas /api/admin nunca chegam ao handler sem usuário de suporte.
+/// - ExigirSuporte só aceita a sessão do login (Authorization: Bearer): o X-User-Email, aceito enquanto AUTH_REQUIRE_SESSION=false, é só um
+///   cabeçalho que qualquer cliente envia, e não pode liberar listar, bloquear, excluir ou personificar contas.
+*/
+
+package middleware
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"time"
+
+	"backend/internal/apierr"
+	"backend/internal/model"
+)
+
+/// ============ Middlewares ============
+
+// ExigirEscritaMiddleware responde 403 quando um usuário com papel "leitor"
+// tenta alterar dados.
+func ExigirEscritaMiddleware(db *sql.DB) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				next.ServeHTTP(w, r)
+				return
+			}
+			acesso, ok := AcessoDe(r.Context())
+			if !ok {
+				email := model.NormalizarEmail(r.Header.Get("X-User-Email"))
+				if email == "" {
+					next.ServeHTTP(w, r)
+					return
+				}
+				ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+				a, err := model.ResolverAcesso(ctx, db, email)
+				cancel()
+				acesso, ok = a, err == nil
+			}
+			if ok && !acesso.PodeEscrever() {
+				apierr.Escrever(w, http.StatusForbidden, apierr.SemPermissao, model.ErrSemPermissao.Error(), nil)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ExigirSuporte libera a rota só para contas da equipe de suporte (Acesso.Suporte)
+// autenticadas por sessão: 401 sem usuário resolvido ou sem sessão (X-User-Email),
+// 403 para as demais contas (inclusive admins de organização). Deve vir depois de Autenticacao.
+func ExigirSuporte(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+		acesso, ok := AcessoDe(r.Context())
+		if !ok {
+			apierr.Escrever(w, http.StatusUnauthorized, apierr.NaoAutenticado, "Usuário não autenticado", nil)
+			return
+		}
+		if acesso.SessaoID == 0 {
+			apierr.Escrever(w, http.StatusUnauthorized, apierr.SessaoObrigatoria, "Rotas de suporte exigem a sessão do login (Authorization: Bearer)", nil)
+			return
+		}
+		if !acesso.Suporte {
+			apierr.Escrever(w, http.StatusForbidden, apierr.SemPermissao, "Apenas a equipe de suporte acessa esta rota", nil)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
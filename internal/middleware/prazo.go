@@ -0,0 +1,193 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/middleware/prazo.go
+/// Responsabilidade: Prazo total por requisição (HTTP_REQUEST_TIMEOUT_*), independente dos prazos de banco: vencido, responde 504 JSON e libera a conexão.
+/// Dependências principais: context, net/http, sync, time, backend/internal/apierr, backend/internal/logging.
+/// Pontos de atenção:
+/// - O handler roda em outra goroutine (como no http.TimeoutHandler); vencido o prazo, o contexto é cancelado e as
+///   escritas seguintes do handler são descartadas (http.ErrHandlerTimeout). Nada é bufferizado: a resposta sai direto.
+/// - Se o handler já tinha começado a responder, não dá para trocar por 504: a conexão é derrubada (http.ErrAbortHandler).
+/// - O prazo de escrita da conexão (HTTP_WRITE_TIMEOUT) é ajustado ao prazo da rota, senão rotas longas seriam cortadas antes.
+/// - Rotas com prazo 0 no mapa (streaming com prazo próprio, ex.: GET /api/estudantes) passam direto, sem goroutine.
+/// - Panic do handler é repassado à goroutine da requisição (recoverMiddleware responde 500); depois do prazo, só vai para o log.
+*/
+
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"backend/internal/apierr"
+	"backend/internal/logging"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// respostaComPrazo repassa a resposta do handler enquanto o prazo não vence.
+// Os cabeçalhos ficam num mapa próprio até o envio, para o 504 não disputar o
+// mapa com a goroutine do handler.
+type respostaComPrazo struct {
+	w         http.ResponseWriter
+	cabecalho http.Header
+
+	mu        sync.Mutex
+	iniciada  bool // status e cabeçalhos já enviados
+	vencida   bool // prazo esgotado: escritas descartadas
+	concluida bool // handler retornou
+}
+
+/// ============ Configurações & Constantes ============
+
+// folga entre o prazo da requisição e o prazo de escrita da conexão (cabe o 504)
+const folgaEscritaPrazo = 2 * time.Second
+
+/// ============ Funções Públicas (Middlewares) ============
+
+// PrazoRequisicao limita o tempo total de cada requisição: leitura (GET/HEAD) ou
+// escrita (demais métodos), salvo as rotas de rotas ("MÉTODO /caminho" → prazo;
+// 0 = sem prazo). Vencido o prazo, responde 504 TEMPO_ESGOTADO.
+func PrazoRequisicao(leitura, escrita time.Duration, rotas map[string]time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			metodo := r.Method
+			if metodo == http.MethodHead {
+				metodo = http.MethodGet
+			}
+			prazo, ok := rotas[metodo+" "+r.Pattern]
+			if !ok {
+				prazo = escrita
+				if metodo == http.MethodGet {
+					prazo = leitura
+				}
+			}
+			if prazo <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), prazo)
+			defer cancel()
+			_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(prazo + folgaEscritaPrazo))
+
+			rw := &respostaComPrazo{w: w, cabecalho: w.Header().Clone()}
+			fim := make(chan struct{})
+			panico := make(chan any, 1)
+			go func() {
+				defer func() {
+					if p := recover(); p != nil {
+						if p != http.ErrAbortHandler {
+							p = fmt.Sprintf("%v\n\n%s", p, debug.Stack())
+						}
+						if rw.concluir() {
+							logging.De(r.Context()).Error("panic depois do prazo da requisição", "valor", p)
+							return
+						}
+						panico <- p
+					}
+				}()
+				next.ServeHTTP(rw, r.WithContext(ctx))
+				rw.concluir()
+				close(fim)
+			}()
+
+			select {
+			case <-fim:
+			case p := <-panico:
+				panic(p)
+			case <-ctx.Done():
+				rw.mu.Lock()
+				defer rw.mu.Unlock()
+				if rw.concluida { // terminou junto com o prazo: vale o resultado do handler
+					select {
+					case p := <-panico:
+						panic(p)
+					default:
+					}
+					return
+				}
+				rw.vencida = true
+				if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+					return // cliente desconectou: não há a quem responder
+				}
+				logging.De(r.Context()).Warn("requisição excedeu o prazo", "metodo", r.Method, "rota", r.Pattern, "prazo", prazo.String())
+				if rw.iniciada {
+					panic(http.ErrAbortHandler)
+				}
+				apierr.Escrever(w, http.StatusGatewayTimeout, apierr.TempoEsgotado,
+					"A requisição excedeu o tempo limite", map[string]string{"prazo": prazo.String()})
+			}
+		})
+	}
+}
+
+/// ============ Funções Internas (helpers) ============
+
+func (rw *respostaComPrazo) Header() http.Header { return rw.cabecalho }
+
+func (rw *respostaComPrazo) WriteHeader(status int) {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+	if rw.vencida || rw.iniciada {
+		return
+	}
+	rw.iniciar(status)
+}
+
+func (rw *respostaComPrazo) Write(b []byte) (int, error) {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+	if rw.vencida {
+		return 0, http.ErrHandlerTimeout
+	}
+	if !rw.iniciada {
+		rw.iniciar(http.StatusOK)
+	}
+	return rw.w.Write(b)
+}
+
+// FlushError atende http.ResponseController (streaming dentro do prazo).
+func (rw *respostaComPrazo) FlushError() error {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+	if rw.vencida {
+		return http.ErrHandlerTimeout
+	}
+	if !rw.iniciada {
+		rw.iniciar(http.StatusOK)
+	}
+	return http.NewResponseController(rw.w).Flush()
+}
+
+// SetWriteDeadline atende http.ResponseController.
+func (rw *respostaComPrazo) SetWriteDeadline(t time.Time) error {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+	if rw.vencida {
+		return http.ErrHandlerTimeout
+	}
+	return http.NewResponseController(rw.w).SetWriteDeadline(t)
+}
+
+// iniciar envia os cabeçalhos do handler e o status (com rw.mu travado).
+func (rw *respostaComPrazo) iniciar(status int) {
+	h := rw.w.Header()
+	clear(h)
+	maps.Copy(h, rw.cabecalho)
+	rw.iniciada = true
+	rw.w.WriteHeader(status)
+}
+
+// concluir marca o fim do handler e informa se o prazo já tinha vencido.
+func (rw *respostaComPrazo) concluir() bool {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+	rw.concluida = true
+	return rw.vencida
+}
@@ -0,0 +1,83 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/middleware/request_id.go
+/// Responsabilidade: Middleware que gera/propaga X-Request-ID, injeta o ID no context e registra uma linha de log estruturado por requisição.
+/// Dependências principais: net/http, log/slog, backend/internal/logging.
+/// Pontos de atenção:
+/// - Um X-Request-ID recebido só é reaproveitado se for curto e com caracteres seguros; senão um novo é gerado.
+/// - O ID volta no cabeçalho da resposta (definido antes do handler, então writeJSONError também o enxerga).
+/// - Deve ser o primeiro middleware da cadeia para que recover e handlers já tenham o ID no context.
+*/
+
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"backend/internal/logging"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// respostaRegistrada guarda o status e o tamanho escritos para o log de acesso.
+type respostaRegistrada struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+/// ============ Funções Públicas (Middlewares) ============
+
+// RequestID propaga/gera o X-Request-ID e registra método, caminho, status e duração de cada requisição.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(logging.HeaderRequestID)
+		if !logging.RequestIDValido(id) {
+			id = logging.NovoRequestID()
+		}
+		w.Header().Set(logging.HeaderRequestID, id)
+		r = r.WithContext(logging.ComRequestID(r.Context(), id))
+
+		rw := &respostaRegistrada{ResponseWriter: w}
+		inicio := time.Now()
+		next.ServeHTTP(rw, r)
+		if rw.status == 0 {
+			rw.status = http.StatusOK
+		}
+
+		nivel := slog.LevelInfo
+		if rw.status >= http.StatusInternalServerError {
+			nivel = slog.LevelError
+		}
+		logging.De(r.Context()).Log(r.Context(), nivel, "requisição",
+			"metodo", r.Method,
+			"caminho", r.URL.Path,
+			"status", rw.status,
+			"bytes", rw.bytes,
+			"duracao_ms", time.Since(inicio).Milliseconds(),
+		)
+	})
+}
+
+/// ============ Funções Internas (helpers) ============
+
+func (rw *respostaRegistrada) WriteHeader(status int) {
+	if rw.status == 0 {
+		rw.status = status
+	}
+	rw.ResponseWriter.WriteHeader(status)
+}
+
+func (rw *respostaRegistrada) Write(b []byte) (int, error) {
+	if rw.status == 0 {
+		rw.status = http.StatusOK
+	}
+	n, err := rw.ResponseWriter.Write(b)
+	rw.bytes += n
+	return n, err
+}
+
+// Unwrap permite que http.ResponseController alcance o writer original (Flush, deadlines).
+func (rw *respostaRegistrada) Unwrap() http.ResponseWriter { return rw.ResponseWriter }
@@ -1,22 +1,22 @@
 /*
 /// Projeto: Tecmise
-/// Arquivo: backend/middleware/validacao.go
+/// Arquivo: backend/internal/middleware/validacao.go
 /// Responsabilidade: Middlewares HTTP para saneamento e validação de payloads de cadastro, login e e-mail de estudante.
-/// Dependências principais: net/http, net/mail, encoding/json, backend/model (DTOs e MinPasswordLen).
+/// Dependências principais: net/http, net/mail, encoding/json, backend/internal/model (DTOs e MinPasswordLen), backend/internal/apierr.
 /// Pontos de atenção:
 /// - Reatribuição de r.Body após defer Close: o defer fecha o body original; o novo NopCloser não é fechado explicitamente (memória, sem fd).
 /// - normalizeEmail usa http.ErrNoLocation/ErrUseLastResponse como sentinelas; são reaproveitados apenas como marcadores internos.
 /// - Limites de tamanho: Login/Cadastro usam MaxBytesReader; o middleware do estudante usa LimitReader (comportamentos levemente distintos).
-/// - Mensagens de erro são em texto simples (http.Error) e status 400, compatíveis com os handlers existentes.
+/// - Erros saem no envelope padrão (apierr): JSON malformado é 400 JSON_INVALIDO; regras violadas são 422 VALIDACAO com details = [{field, rule, message}].
 /// - Divergência possível com frontend: comprimento mínimo de senha no frontend pode ser maior do que model.MinPasswordLen.
 */
 
 //
-// backend/middleware/validacao.go
+// backend/internal/middleware/validacao.go
 //
 // 🔹 Objetivo:
 // Middlewares de validação/saneamento para cadastro, login e email do estudante.
-// Mantém comportamento (status 400 e mensagens em texto) e reduz duplicação.
+// Responde no envelope de erro padrão (422 com a lista de campos inválidos).
 // - Reutiliza DTOs e regras do package model (RegisterRequest, LoginRequest, MinPasswordLen)
 // - Usa net/mail para validação de e-mail (mais robusto que regex)
 // - Reinsere o corpo normalizado sem conversões desnecessárias
@@ -32,7 +32,8 @@ import (
 	"net/mail"
 	"strings"
 
-	"backend/model"
+	"backend/internal/apierr"
+	"backend/internal/model"
 )
 
 /// ============ Configurações & Constantes ============
@@ -42,6 +43,13 @@ const maxBodySize = 1 << 20 // 1 MiB
 
 /// ============ Funções Internas (helpers) ============
 
+// erroValidacao responde 422 VALIDACAO com um único erro de campo em details
+// (mesmo formato de model.ErrosValidacao usado pelos handlers).
+func erroValidacao(w http.ResponseWriter, campo, regra, msg string) {
+	apierr.Escrever(w, http.StatusUnprocessableEntity, apierr.Validacao, msg,
+		model.ErrosValidacao{{Field: campo, Rule: regra, Message: msg}})
+}
+
 // normalizeEmail normaliza e valida um endereço de e-mail.
 // Regras:
 //   - Trim de espaços nas bordas.
@@ -73,10 +81,10 @@ func normalizeEmail(raw string) (string, error) {
 /// ============ Middlewares ============
 
 // ValidarCadastroMiddleware valida o payload de cadastro de usuário.
-// Regras aplicadas:
-//   - Nome: trim e tamanho mínimo (2).
+// Regras aplicadas (model.RegisterRequest.Validate, todas de uma vez):
+//   - Nome: trim e tamanho mínimo (model.MinNomeLen).
 //   - E-mail: normalizeEmail (trim, validação RFC-ish, lowercase).
-//   - Senha: comprimento mínimo model.MinPasswordLen e sem espaços.
+//   - Senha: comprimento mínimo model.MinPasswordLenCadastro e sem espaços.
 //
 // Em sucesso, reescreve o corpo com o JSON normalizado e chama o próximo handler.
 func ValidarCadastroMiddleware(next http.HandlerFunc) http.HandlerFunc {
@@ -86,38 +94,18 @@ func ValidarCadastroMiddleware(next http.HandlerFunc) http.HandlerFunc {
 
 		var req model.RegisterRequest
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			http.Error(w, "JSON inválido", http.StatusBadRequest)
-			return
-		}
-
-		// Nome
-		req.Nome = strings.TrimSpace(req.Nome)
-		if len(req.Nome) < 2 {
-			http.Error(w, "Nome muito curto", http.StatusBadRequest)
-			return
-		}
-
-		// E-mail
-		normEmail, err := normalizeEmail(req.Email)
-		if err != nil {
-			// mensagens mais amigáveis (sem mudar status/mídia)
-			switch {
-			case err == http.ErrNoLocation:
-				http.Error(w, "E-mail é obrigatório", http.StatusBadRequest)
-			default:
-				http.Error(w, "E-mail inválido", http.StatusBadRequest)
-			}
+			apierr.Escrever(w, http.StatusBadRequest, apierr.JSONInvalido, "JSON inválido", nil)
 			return
 		}
-		req.Email = normEmail
 
-		// Senha
-		if len(req.Senha) < model.MinPasswordLen {
-			http.Error(w, "Senha muito curta (mínimo "+strconvI(model.MinPasswordLen)+" caracteres)", http.StatusBadRequest)
-			return
+		// Nome / E-mail / Senha: todas as violações num único 422
+		req.Sanitize()
+		if normEmail, err := normalizeEmail(req.Email); err == nil {
+			req.Email = normEmail
 		}
-		if strings.Contains(req.Senha, " ") {
-			http.Error(w, "Senha não pode conter espaços!", http.StatusBadRequest)
+		if err := req.Validate(); err != nil {
+			ev, _ := model.ComoErrosValidacao(err)
+			apierr.Escrever(w, http.StatusUnprocessableEntity, apierr.Validacao, "Dados inválidos", ev)
 			return
 		}
 
@@ -142,7 +130,7 @@ func ValidarLoginMiddleware(next http.HandlerFunc) http.HandlerFunc {
 
 		var req model.LoginRequest
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			http.Error(w, "JSON inválido", http.StatusBadRequest)
+			apierr.Escrever(w, http.StatusBadRequest, apierr.JSONInvalido, "JSON inválido", nil)
 			return
 		}
 
@@ -151,9 +139,9 @@ func ValidarLoginMiddleware(next http.HandlerFunc) http.HandlerFunc {
 		if err != nil {
 			switch {
 			case err == http.ErrNoLocation:
-				http.Error(w, "E-mail é obrigatório", http.StatusBadRequest)
+				erroValidacao(w, "email", model.RegraObrigatorio, "E-mail é obrigatório")
 			default:
-				http.Error(w, "E-mail inválido", http.StatusBadRequest)
+				erroValidacao(w, "email", model.RegraFormato, "E-mail inválido")
 			}
 			return
 		}
@@ -161,11 +149,11 @@ func ValidarLoginMiddleware(next http.HandlerFunc) http.HandlerFunc {
 
 		// Senha
 		if len(req.Senha) < model.MinPasswordLen {
-			http.Error(w, "Senha deve ter pelo menos "+strconvI(model.MinPasswordLen)+" caracteres.", http.StatusBadRequest)
+			erroValidacao(w, "senha", model.RegraTamanhoMinimo, "Senha deve ter pelo menos "+strconvI(model.MinPasswordLen)+" caracteres.")
 			return
 		}
 		if strings.Contains(req.Senha, " ") {
-			http.Error(w, "Senha não pode conter espaços!", http.StatusBadRequest)
+			erroValidacao(w, "senha", model.RegraSemEspacos, "Senha não pode conter espaços!")
 			return
 		}
 
@@ -177,34 +165,33 @@ func ValidarLoginMiddleware(next http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
-// ValidarEstudanteEmailMiddleware valida somente o campo "email" do estudante,
+// ValidarEstudanteEmailMiddleware normaliza somente o campo "email" do estudante,
 // preservando o JSON original (campos extras são mantidos).
-// Em sucesso, substitui apenas o valor normalizado de "email" e encaminha ao próximo handler.
+// Se o e-mail for válido, substitui o valor normalizado; caso contrário encaminha o
+// corpo original para que o handler reporte o erro junto com os demais campos.
 func ValidarEstudanteEmailMiddleware(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		defer r.Body.Close()
 		orig, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
 		if err != nil {
-			http.Error(w, "Falha ao ler corpo da requisição", http.StatusBadRequest)
+			apierr.Escrever(w, http.StatusBadRequest, apierr.RequisicaoInvalida, "Falha ao ler corpo da requisição", nil)
 			return
 		}
 
 		// Preserva o payload como map genérico
 		var payload map[string]any
 		if err := json.Unmarshal(orig, &payload); err != nil {
-			http.Error(w, "JSON inválido", http.StatusBadRequest)
+			apierr.Escrever(w, http.StatusBadRequest, apierr.JSONInvalido, "JSON inválido", nil)
 			return
 		}
 
+		// E-mail ausente/inválido segue como veio: o Validate do handler o reporta
+		// junto com os demais campos (422 com todas as violações).
 		rawEmail, _ := payload["email"].(string)
 		normEmail, err := normalizeEmail(rawEmail)
 		if err != nil {
-			switch {
-			case err == http.ErrNoLocation:
-				http.Error(w, "E-mail do estudante é obrigatório", http.StatusBadRequest)
-			default:
-				http.Error(w, "E-mail do estudante inválido", http.StatusBadRequest)
-			}
+			r.Body = io.NopCloser(bytes.NewReader(orig))
+			next(w, r)
 			return
 		}
 
@@ -0,0 +1,218 @@
+-- 0001_baseline.sql
+--
+-- 📦 Estrutura base do banco de dados TecMise (antigo schema.sql)
+--
+-- Objetivo:
+--   Cria as tabelas de autenticação de usuários e dos dados escolares
+--   (anos/turmas, estudantes e seus anexos, organizações e convites).
+--
+-- Boas práticas seguidas:
+-- - `IF NOT EXISTS`: bases criadas antes do controle de migrations recebem
+--   esta baseline sem erro (ela é idempotente).
+-- - `id serial PRIMARY KEY`: chave primária incremental.
+-- - `email UNIQUE`: garante que não existam contas duplicadas.
+-- - `senha_hash`: senha nunca é armazenada em texto puro, sempre hash.
+-- - Toda tabela de dados tem `usuario_id` (dono) para isolar os registros.
+--
+-- Mudanças de schema daqui em diante vão em novos arquivos NNNN_descricao.sql;
+-- migrations já aplicadas não devem ser editadas.
+
+CREATE TABLE IF NOT EXISTS usuarios (
+    id SERIAL PRIMARY KEY,           -- Identificador único (auto incremento)
+    nome VARCHAR(100),               -- Nome do usuário (não obrigatório)
+    email VARCHAR(200) NOT NULL UNIQUE, -- Email único, obrigatório (login)
+    senha_hash VARCHAR(300) NOT NULL,   -- Hash seguro da senha (bcrypt/argon2)
+    foto_url TEXT,                      -- Foto de perfil (caminho /uploads/... ou URL externa)
+    tutorial_visto BOOLEAN DEFAULT FALSE, -- Flag de onboarding
+    google_sub VARCHAR(255) UNIQUE      -- "sub" do Google (login GIS), opcional
+);
+ALTER TABLE usuarios ADD COLUMN IF NOT EXISTS foto_url TEXT;
+ALTER TABLE usuarios ADD COLUMN IF NOT EXISTS tutorial_visto BOOLEAN DEFAULT FALSE;
+ALTER TABLE usuarios ADD COLUMN IF NOT EXISTS google_sub VARCHAR(255) UNIQUE;
+
+-- Anos/Turmas do usuário
+-- Períodos letivos (ano escolar, ex.: "2025"); fechado bloqueia chamada/notas
+CREATE TABLE IF NOT EXISTS periodos_letivos (
+    id SERIAL PRIMARY KEY,
+    usuario_id INT NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    nome VARCHAR(40) NOT NULL,
+    inicio DATE,
+    fim DATE,
+    fechado BOOLEAN NOT NULL DEFAULT FALSE,
+    CONSTRAINT periodos_letivos_nome_usuario_unique UNIQUE (usuario_id, nome)
+);
+
+CREATE TABLE IF NOT EXISTS anos (
+    id SERIAL PRIMARY KEY,
+    nome VARCHAR(120) NOT NULL,
+    usuario_id INT NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    ordem INT NOT NULL DEFAULT 0,            -- posição definida pelo usuário (PUT /api/anos/reorder)
+    arquivado BOOLEAN NOT NULL DEFAULT FALSE -- oculto da listagem padrão
+);
+ALTER TABLE anos ADD COLUMN IF NOT EXISTS ordem INT NOT NULL DEFAULT 0;
+ALTER TABLE anos ADD COLUMN IF NOT EXISTS arquivado BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE anos ADD COLUMN IF NOT EXISTS periodo_letivo_id INT REFERENCES periodos_letivos(id) ON DELETE SET NULL;
+
+-- Nome do ano único por usuário e período letivo (sem diferenciar maiúsculas/minúsculas).
+-- Bases com nomes já repetidos precisam renomear os duplicados antes deste índice.
+CREATE UNIQUE INDEX IF NOT EXISTS anos_nome_usuario_unique
+    ON anos (usuario_id, COALESCE(periodo_letivo_id, 0), LOWER(nome));
+
+-- Estudantes (CPF e e-mail únicos por usuário)
+CREATE TABLE IF NOT EXISTS estudantes (
+    id SERIAL PRIMARY KEY,
+    nome VARCHAR(120) NOT NULL,
+    cpf TEXT NOT NULL,                      -- cifrado pela aplicação (AES-GCM, "enc:v1:...")
+    cpf_hash VARCHAR(64),                   -- índice cego (HMAC) para busca/unicidade de CPF
+    email VARCHAR(200),
+    data_nascimento DATE,
+    telefone TEXT,                          -- cifrado pela aplicação
+    foto_url TEXT,
+    ano_id INT REFERENCES anos(id) ON DELETE CASCADE,
+    turma_id INT,
+    usuario_id INT NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    excluido_em TIMESTAMPTZ                 -- soft delete (ex.: registro mesclado em outro)
+);
+ALTER TABLE estudantes ADD COLUMN IF NOT EXISTS excluido_em TIMESTAMPTZ;
+
+-- Ciclo de vida: ativo | transferido | formado (transições validadas no model)
+ALTER TABLE estudantes ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'ativo';
+CREATE INDEX IF NOT EXISTS estudantes_usuario_status_idx ON estudantes (usuario_id, status);
+
+-- Unicidade de CPF/E-mail por usuário vale só para registros ativos
+-- (bases antigas: remove as constraints totais e recria como índices parciais).
+ALTER TABLE estudantes DROP CONSTRAINT IF EXISTS estudantes_cpf_usuario_unique;
+ALTER TABLE estudantes DROP CONSTRAINT IF EXISTS estudantes_email_usuario_unique;
+CREATE UNIQUE INDEX IF NOT EXISTS estudantes_email_usuario_unique
+    ON estudantes (email, usuario_id) WHERE excluido_em IS NULL;
+
+-- CPF/telefone cifrados em repouso: colunas maiores e unicidade pelo índice cego.
+-- Após aplicar, rode `go run . cifrar-pii` para cifrar as linhas existentes.
+ALTER TABLE estudantes ALTER COLUMN cpf TYPE TEXT;
+ALTER TABLE estudantes ALTER COLUMN telefone TYPE TEXT;
+ALTER TABLE estudantes ADD COLUMN IF NOT EXISTS cpf_hash VARCHAR(64);
+DROP INDEX IF EXISTS estudantes_cpf_usuario_unique;
+CREATE UNIQUE INDEX IF NOT EXISTS estudantes_cpf_hash_usuario_unique
+    ON estudantes (cpf_hash, usuario_id) WHERE excluido_em IS NULL;
+
+-- Documentos anexados a estudantes (conteúdo no storage de uploads)
+CREATE TABLE IF NOT EXISTS documentos (
+    id SERIAL PRIMARY KEY,
+    estudante_id INT NOT NULL REFERENCES estudantes(id) ON DELETE CASCADE,
+    usuario_id INT NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    tipo VARCHAR(30) NOT NULL,              -- certidao | laudo | rg | comprovante | outro
+    nome_arquivo VARCHAR(255) NOT NULL,     -- nome original enviado
+    content_type VARCHAR(100) NOT NULL,
+    tamanho BIGINT NOT NULL,                -- bytes
+    storage_key TEXT NOT NULL,              -- chave no storage.Storage
+    criado_em TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS documentos_estudante_idx ON documentos (estudante_id);
+
+-- Frequência: uma marcação por estudante/dia (ano_id = turma da chamada)
+CREATE TABLE IF NOT EXISTS presencas (
+    id SERIAL PRIMARY KEY,
+    estudante_id INT NOT NULL REFERENCES estudantes(id) ON DELETE CASCADE,
+    usuario_id INT NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    ano_id INT REFERENCES anos(id) ON DELETE SET NULL,
+    data DATE NOT NULL,
+    presente BOOLEAN NOT NULL,
+    observacao TEXT,
+    CONSTRAINT presencas_estudante_data_unique UNIQUE (estudante_id, data)
+);
+CREATE INDEX IF NOT EXISTS presencas_ano_data_idx ON presencas (ano_id, data);
+
+-- Avaliações (por Ano/Turma) e notas por estudante
+CREATE TABLE IF NOT EXISTS avaliacoes (
+    id SERIAL PRIMARY KEY,
+    usuario_id INT NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    ano_id INT NOT NULL REFERENCES anos(id) ON DELETE CASCADE,
+    disciplina VARCHAR(80) NOT NULL,
+    titulo VARCHAR(120) NOT NULL,
+    periodo VARCHAR(40),                    -- ex.: "1º bimestre"
+    peso NUMERIC(6,2) NOT NULL DEFAULT 1,
+    nota_maxima NUMERIC(6,2) NOT NULL DEFAULT 10,
+    data DATE
+);
+
+CREATE TABLE IF NOT EXISTS notas (
+    id SERIAL PRIMARY KEY,
+    avaliacao_id INT NOT NULL REFERENCES avaliacoes(id) ON DELETE CASCADE,
+    estudante_id INT NOT NULL REFERENCES estudantes(id) ON DELETE CASCADE,
+    usuario_id INT NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    valor NUMERIC(6,2) NOT NULL,
+    CONSTRAINT notas_avaliacao_estudante_unique UNIQUE (avaliacao_id, estudante_id)
+);
+CREATE INDEX IF NOT EXISTS notas_estudante_idx ON notas (estudante_id);
+
+-- Responsáveis (pais/tutores) de cada estudante
+CREATE TABLE IF NOT EXISTS responsaveis (
+    id SERIAL PRIMARY KEY,
+    estudante_id INT NOT NULL REFERENCES estudantes(id) ON DELETE CASCADE,
+    usuario_id INT NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    nome VARCHAR(120) NOT NULL,
+    cpf VARCHAR(14),
+    telefone VARCHAR(32),
+    email VARCHAR(200),
+    parentesco VARCHAR(30) NOT NULL DEFAULT 'outro'
+);
+CREATE INDEX IF NOT EXISTS responsaveis_estudante_idx ON responsaveis (estudante_id);
+
+-- Histórico de mudanças de status do estudante
+CREATE TABLE IF NOT EXISTS estudante_status_historico (
+    id SERIAL PRIMARY KEY,
+    estudante_id INT NOT NULL REFERENCES estudantes(id) ON DELETE CASCADE,
+    usuario_id INT NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    de VARCHAR(20) NOT NULL,
+    para VARCHAR(20) NOT NULL,
+    motivo TEXT NOT NULL,
+    criado_em TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS estudante_status_historico_estudante_idx ON estudante_status_historico (estudante_id);
+
+-- Movimentações de matrícula (transferências entre anos/turmas e promoções)
+CREATE TABLE IF NOT EXISTS matriculas_historico (
+    id SERIAL PRIMARY KEY,
+    estudante_id INT NOT NULL REFERENCES estudantes(id) ON DELETE CASCADE,
+    usuario_id INT NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    de_ano_id INT REFERENCES anos(id) ON DELETE SET NULL,
+    de_turma_id INT NOT NULL DEFAULT 0,
+    para_ano_id INT REFERENCES anos(id) ON DELETE SET NULL,
+    para_turma_id INT NOT NULL DEFAULT 0,
+    data DATE NOT NULL,
+    motivo TEXT NOT NULL DEFAULT '',
+    criado_em TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS matriculas_historico_estudante_idx ON matriculas_historico (estudante_id);
+
+-- Organizações (escolas): membros compartilham os dados gravados no usuario_id do dono
+CREATE TABLE IF NOT EXISTS organizacoes (
+    id SERIAL PRIMARY KEY,
+    nome VARCHAR(120) NOT NULL,
+    dono_id INT NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    criado_em TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS organizacao_membros (
+    organizacao_id INT NOT NULL REFERENCES organizacoes(id) ON DELETE CASCADE,
+    usuario_id INT NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    papel VARCHAR(20) NOT NULL DEFAULT 'editor',   -- admin | editor | leitor
+    criado_em TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (organizacao_id, usuario_id),
+    CONSTRAINT organizacao_membros_usuario_unique UNIQUE (usuario_id) -- uma organização por usuário
+);
+
+-- Convites por e-mail para entrar numa organização (apenas o hash do token é gravado)
+CREATE TABLE IF NOT EXISTS organizacao_convites (
+    id SERIAL PRIMARY KEY,
+    organizacao_id INT NOT NULL REFERENCES organizacoes(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    papel VARCHAR(20) NOT NULL DEFAULT 'editor',   -- admin | editor | leitor
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    convidado_por INT REFERENCES usuarios(id) ON DELETE SET NULL,
+    expira_em TIMESTAMPTZ NOT NULL,
+    aceito_em TIMESTAMPTZ,
+    aceito_por INT REFERENCES usuarios(id) ON DELETE SET NULL,
+    criado_em TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_organizacao_convites_org ON organizacao_convites (organizacao_id);
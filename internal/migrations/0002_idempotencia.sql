@@ -0,0 +1,28 @@
+-- 0002_idempotencia.sql
+--
+-- 🔁 Respostas guardadas por Idempotency-Key (middleware.Idempotencia)
+--
+-- Objetivo:
+--   Permitir que POSTs repetidos pelo cliente (rede móvel instável) com o mesmo
+--   cabeçalho Idempotency-Key devolvam a resposta original sem duplicar registros.
+--
+-- Observações:
+-- - A chave é escopada pelo e-mail do usuário (X-User-Email): chaves iguais de
+--   usuários diferentes não colidem.
+-- - hash_requisicao (sha256 de método, caminho e corpo) detecta reuso da chave
+--   com outro payload.
+-- - status NULL = requisição original ainda em andamento.
+-- - Registros expiram em 24h (removidos sob demanda pelo middleware).
+
+CREATE TABLE IF NOT EXISTS idempotencia (
+    usuario_email TEXT NOT NULL,
+    chave TEXT NOT NULL,
+    hash_requisicao CHAR(64) NOT NULL,
+    status INT,
+    content_type TEXT NOT NULL DEFAULT '',
+    corpo BYTEA,
+    criada_em TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (usuario_email, chave)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotencia_criada_em ON idempotencia (criada_em);
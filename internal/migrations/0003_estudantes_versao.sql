@@ -0,0 +1,27 @@
+-- 0003_estudantes_versao.sql
+--
+-- 🔒 Controle de concorrência otimista em estudantes (ETag / If-Match)
+--
+-- Objetivo:
+--   Cada alteração em um estudante incrementa `versao`. O GET devolve a
+--   versão no cabeçalho ETag e o PUT só grava se o If-Match ainda bater,
+--   evitando que duas pessoas editando o mesmo aluno se sobrescrevam.
+--
+-- Observações:
+-- - O incremento é feito por trigger para cobrir todas as escritas
+--   (edição, transferência, mudança de status, mescla, cifragem de PII).
+-- - Linhas existentes começam na versão 1.
+
+ALTER TABLE estudantes ADD COLUMN IF NOT EXISTS versao INT NOT NULL DEFAULT 1;
+
+CREATE OR REPLACE FUNCTION estudantes_incrementar_versao() RETURNS trigger AS $$
+BEGIN
+    NEW.versao := OLD.versao + 1;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_estudantes_versao ON estudantes;
+CREATE TRIGGER trg_estudantes_versao
+    BEFORE UPDATE ON estudantes
+    FOR EACH ROW EXECUTE FUNCTION estudantes_incrementar_versao();
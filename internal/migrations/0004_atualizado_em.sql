@@ -0,0 +1,32 @@
+-- 0004_atualizado_em.sql
+--
+-- 🕒 Data da última alteração em estudantes e anos (GET condicional)
+--
+-- Objetivo:
+--   As listagens GET /api/estudantes e GET /api/anos devolvem ETag e
+--   Last-Modified calculados a partir de COUNT(*) e MAX(atualizado_em), para
+--   que o dashboard receba 304 quando nada mudou.
+--
+-- Observações:
+-- - Mantido por trigger (qualquer UPDATE, inclusive soft delete, atualiza a data).
+-- - Linhas existentes recebem NOW() na criação da coluna.
+
+ALTER TABLE estudantes ADD COLUMN IF NOT EXISTS atualizado_em TIMESTAMPTZ NOT NULL DEFAULT NOW();
+ALTER TABLE anos ADD COLUMN IF NOT EXISTS atualizado_em TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+CREATE OR REPLACE FUNCTION definir_atualizado_em() RETURNS trigger AS $$
+BEGIN
+    NEW.atualizado_em := NOW();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_estudantes_atualizado_em ON estudantes;
+CREATE TRIGGER trg_estudantes_atualizado_em
+    BEFORE UPDATE ON estudantes
+    FOR EACH ROW EXECUTE FUNCTION definir_atualizado_em();
+
+DROP TRIGGER IF EXISTS trg_anos_atualizado_em ON anos;
+CREATE TRIGGER trg_anos_atualizado_em
+    BEFORE UPDATE ON anos
+    FOR EACH ROW EXECUTE FUNCTION definir_atualizado_em();
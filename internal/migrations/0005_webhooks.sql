@@ -0,0 +1,46 @@
+-- 0005_webhooks.sql
+--
+-- 🔔 Webhooks: URLs cadastradas pelo usuário e fila/log de entregas
+--
+-- Objetivo:
+--   Notificar sistemas externos (ERP, planilhas, automações) quando estudantes
+--   são criados/excluídos e anos removidos, com assinatura HMAC-SHA256 e
+--   retentativas com backoff (jobs.Webhooks).
+--
+-- Observações:
+-- - eventos lista os tipos assinados (ex.: {estudante.created,ano.removed}).
+-- - segredo é o segredo compartilhado da assinatura (precisa ser legível para
+--   assinar; não é devolvido nas listagens).
+-- - webhook_entregas é ao mesmo tempo a fila (status 'pendente' +
+--   proxima_tentativa_em) e o histórico exibido em GET /api/webhooks/{id}/entregas.
+-- - Remover o webhook apaga as entregas em cascata.
+
+CREATE TABLE IF NOT EXISTS webhooks (
+    id SERIAL PRIMARY KEY,
+    usuario_id INT NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    segredo TEXT NOT NULL,
+    eventos TEXT[] NOT NULL,
+    ativo BOOLEAN NOT NULL DEFAULT TRUE,
+    criado_em TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhooks_usuario ON webhooks (usuario_id);
+
+CREATE TABLE IF NOT EXISTS webhook_entregas (
+    id BIGSERIAL PRIMARY KEY,
+    webhook_id INT NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    evento TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pendente', -- pendente | entregue | falhou
+    tentativas INT NOT NULL DEFAULT 0,
+    proxima_tentativa_em TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ultimo_status_http INT,
+    ultimo_erro TEXT,
+    criado_em TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    entregue_em TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_entregas_fila
+    ON webhook_entregas (proxima_tentativa_em) WHERE status = 'pendente';
+CREATE INDEX IF NOT EXISTS idx_webhook_entregas_webhook ON webhook_entregas (webhook_id, id DESC);
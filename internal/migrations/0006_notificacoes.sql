@@ -0,0 +1,32 @@
+-- 0006_notificacoes.sql
+--
+-- 🔔 Notificações in-app (sino do frontend)
+--
+-- Objetivo:
+--   Guardar eventos que o backend quer mostrar ao usuário (importação
+--   concluída, aniversário de estudante, exportação pronta) até que sejam
+--   marcados como lidos em PUT /api/notificacoes/{id}/lida.
+--
+-- Observações:
+-- - usuario_id é o destinatário (a conta autenticada), não o tenant: membros
+--   de uma organização têm notificações próprias.
+-- - chave (opcional) evita duplicar o mesmo evento para o mesmo usuário
+--   (ex.: "aniversario:2026-03-14:42" gerado mais de uma vez no dia).
+-- - dados guarda o contexto do evento (ids, contagens) para o frontend montar links.
+
+CREATE TABLE IF NOT EXISTS notificacoes (
+    id BIGSERIAL PRIMARY KEY,
+    usuario_id INT NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    tipo TEXT NOT NULL,
+    titulo TEXT NOT NULL,
+    mensagem TEXT NOT NULL DEFAULT '',
+    dados JSONB NOT NULL DEFAULT '{}',
+    chave TEXT,
+    lida_em TIMESTAMPTZ,
+    criado_em TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (usuario_id, chave)
+);
+
+CREATE INDEX IF NOT EXISTS idx_notificacoes_usuario ON notificacoes (usuario_id, id DESC);
+CREATE INDEX IF NOT EXISTS idx_notificacoes_nao_lidas
+    ON notificacoes (usuario_id) WHERE lida_em IS NULL;
@@ -0,0 +1,21 @@
+-- 0007_resumos_enviados.sql
+--
+-- 📬 Controle de resumos periódicos enviados por e-mail
+--
+-- Objetivo:
+--   Garantir que cada resumo (ex.: aniversariantes da semana) seja enviado uma
+--   única vez por usuário e período, mesmo com várias instâncias rodando o job
+--   (jobs.Aniversarios).
+--
+-- Observações:
+-- - periodo é o primeiro dia do período coberto (segunda-feira, no resumo semanal).
+-- - O job reserva a linha antes de enviar (INSERT ... ON CONFLICT DO NOTHING) e a
+--   apaga se o envio falhar, para tentar de novo na próxima varredura.
+
+CREATE TABLE IF NOT EXISTS resumos_enviados (
+    usuario_id INT NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    tipo TEXT NOT NULL,
+    periodo DATE NOT NULL,
+    enviado_em TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (usuario_id, tipo, periodo)
+);
@@ -0,0 +1,29 @@
+-- 0008_auditoria.sql
+--
+-- 📜 Log de auditoria das alterações de dados (fonte do feed de atividades)
+--
+-- Objetivo:
+--   Registrar quem fez o quê (criou/editou/removeu estudantes e anos) para o
+--   feed GET /api/atividades e para consultas de suporte.
+--
+-- Observações:
+-- - usuario_id é o dono dos dados (tenant); autor_id é a conta que fez a ação
+--   (um membro da organização, por exemplo). Remover o autor mantém o registro.
+-- - resumo guarda o nome da entidade no momento da ação (continua legível depois
+--   que ela é renomeada ou removida).
+-- - Registros são apenas inseridos (nunca atualizados).
+
+CREATE TABLE IF NOT EXISTS auditoria (
+    id BIGSERIAL PRIMARY KEY,
+    usuario_id INT NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    autor_id INT REFERENCES usuarios(id) ON DELETE SET NULL,
+    acao TEXT NOT NULL,
+    entidade TEXT NOT NULL,
+    entidade_id INT NOT NULL,
+    resumo TEXT NOT NULL DEFAULT '',
+    dados JSONB NOT NULL DEFAULT '{}',
+    criado_em TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_auditoria_usuario ON auditoria (usuario_id, id DESC);
+CREATE INDEX IF NOT EXISTS idx_auditoria_autor ON auditoria (autor_id, id DESC);
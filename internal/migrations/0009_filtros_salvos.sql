@@ -0,0 +1,25 @@
+-- 0009_filtros_salvos.sql
+--
+-- 🔎 Filtros salvos ("listas inteligentes") de estudantes
+--
+-- Objetivo:
+--   Guardar filtros nomeados (ex.: "8º ano sem telefone") para executar de novo
+--   com GET /api/filtros/{id}/resultados.
+--
+-- Observações:
+-- - usuario_id é a conta que salvou o filtro (pessoal, mesmo dentro de uma
+--   organização); os resultados sempre saem dos dados do tenant atual.
+-- - filtro guarda model.FiltroEstudantes já validado (JSON).
+-- - Nome único por conta, sem diferenciar maiúsculas/minúsculas.
+
+CREATE TABLE IF NOT EXISTS filtros_salvos (
+    id SERIAL PRIMARY KEY,
+    usuario_id INT NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    nome TEXT NOT NULL,
+    filtro JSONB NOT NULL DEFAULT '{}',
+    criado_em TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    atualizado_em TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS filtros_salvos_nome_usuario_unique
+    ON filtros_salvos (usuario_id, LOWER(nome));
@@ -0,0 +1,30 @@
+-- 0010_lixeira.sql
+--
+-- 🗑️ Lixeira: exclusão lógica de estudantes e anos, com autor da exclusão
+--
+-- Objetivo:
+--   DELETE de estudantes e anos passa a marcar excluido_em (soft delete) para
+--   que GET /api/lixeira liste os itens e seja possível restaurá-los ou
+--   excluí-los definitivamente.
+--
+-- Observações:
+-- - excluido_por é a conta que excluiu (NULL para exclusões antigas, mesclagens
+--   ou conta removida).
+-- - O nome do ano passa a ser único só entre os anos não excluídos (um ano na
+--   lixeira não impede criar outro com o mesmo nome; restaurar pode dar conflito).
+-- - Estudantes removidos junto com o ano (force=true) recebem o mesmo
+--   excluido_em do ano (mesma transação): é assim que a restauração os encontra.
+
+ALTER TABLE estudantes ADD COLUMN IF NOT EXISTS excluido_por INT REFERENCES usuarios(id) ON DELETE SET NULL;
+
+ALTER TABLE anos ADD COLUMN IF NOT EXISTS excluido_em TIMESTAMPTZ;
+ALTER TABLE anos ADD COLUMN IF NOT EXISTS excluido_por INT REFERENCES usuarios(id) ON DELETE SET NULL;
+
+DROP INDEX IF EXISTS anos_nome_usuario_unique;
+CREATE UNIQUE INDEX anos_nome_usuario_unique
+    ON anos (usuario_id, COALESCE(periodo_letivo_id, 0), LOWER(nome)) WHERE excluido_em IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_estudantes_lixeira
+    ON estudantes (usuario_id, excluido_em DESC) WHERE excluido_em IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_anos_lixeira
+    ON anos (usuario_id, excluido_em DESC) WHERE excluido_em IS NOT NULL;
@@ -0,0 +1,34 @@
+-- 0011_usuarios_email_unico.sql
+--
+-- 👤 E-mail único sem diferenciar maiúsculas/minúsculas e google_sub único
+--
+-- Objetivo:
+--   Permitir o upsert do login Google num único INSERT ... ON CONFLICT
+--   (model.SQLUserRepo.UpsertFromGoogle), sem a janela entre SELECT e INSERT
+--   que criava contas duplicadas em logins simultâneos.
+--
+-- Observações:
+-- - Os logins já comparam LOWER(email); o índice passa a garantir isso no banco.
+--   Bases com contas repetidas só na caixa (ex.: Ana@x.com e ana@x.com) precisam
+--   mesclá-las antes desta migration.
+-- - google_sub já nasce UNIQUE na baseline, mas bases em que a coluna foi criada
+--   antes dela (detecção em runtime do antigo repositório) podem estar sem o
+--   índice; ele só é criado quando nenhum índice único cobre a coluna.
+
+CREATE UNIQUE INDEX IF NOT EXISTS usuarios_email_lower_unique
+    ON usuarios (LOWER(email));
+
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1
+          FROM pg_index i
+          JOIN pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = ANY(i.indkey)
+         WHERE i.indrelid = 'usuarios'::regclass
+           AND i.indisunique
+           AND i.indnatts = 1
+           AND a.attname = 'google_sub'
+    ) THEN
+        CREATE UNIQUE INDEX usuarios_google_sub_unique ON usuarios (google_sub);
+    END IF;
+END $$;
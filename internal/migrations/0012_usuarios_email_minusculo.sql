@@ -0,0 +1,18 @@
+-- 0012_usuarios_email_minusculo.sql
+--
+-- ✉️ E-mails de conta gravados já normalizados (minúsculas)
+--
+-- Objetivo:
+--   O backend passa a normalizar o e-mail de conta num único lugar
+--   (model.NormalizarEmail) antes de gravar e de comparar; contas antigas
+--   criadas pelo login Google guardavam o e-mail como veio (ex.: Ana@Escola.com).
+--
+-- Observações:
+-- - Sem risco de colisão: usuarios_email_lower_unique (0011) já garante que
+--   não há dois e-mails iguais ignorando a caixa.
+-- - A forma canônica do Gmail (EMAIL_GMAIL_CANONICAL) não é aplicada aqui: é
+--   opção de configuração e vale só para bases novas.
+
+UPDATE usuarios
+   SET email = LOWER(email)
+ WHERE email <> LOWER(email);
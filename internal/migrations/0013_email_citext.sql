@@ -0,0 +1,32 @@
+-- 0013_email_citext.sql
+--
+-- ✉️ E-mails de usuários e estudantes como CITEXT
+--
+-- Objetivo:
+--   Comparar e-mails sem diferenciar maiúsculas/minúsculas no próprio tipo da
+--   coluna. As consultas passam a usar `email = $1` (em vez de
+--   LOWER(email) = LOWER($1)) e os índices únicos comuns atendem a igualdade.
+--
+-- Observações:
+-- - usuarios: o índice funcional usuarios_email_lower_unique (0011) e a
+--   constraint usuarios_email_key da baseline dão lugar a um único índice
+--   usuarios_email_unique, alvo do ON CONFLICT (email) do login Google.
+-- - estudantes: estudantes_email_usuario_unique (por usuário, só ativos) é
+--   recriado pelo próprio ALTER TYPE e passa a ignorar a caixa. Bases com
+--   e-mails de estudantes repetidos só na caixa no mesmo tenant precisam
+--   ajustá-los antes desta migration.
+-- - CITEXT não tem limite de tamanho: os 200 caracteres do VARCHAR antigo
+--   seguem garantidos pela validação da API.
+-- - Parâmetros não podem ser convertidos com ::text na comparação; com
+--   citext = text o Postgres não usa o índice.
+-- - Requer a extensão citext (confiável desde o Postgres 13: o dono do banco
+--   pode criá-la sem superusuário).
+
+CREATE EXTENSION IF NOT EXISTS citext;
+
+DROP INDEX IF EXISTS usuarios_email_lower_unique;
+ALTER TABLE usuarios DROP CONSTRAINT IF EXISTS usuarios_email_key;
+ALTER TABLE usuarios ALTER COLUMN email TYPE CITEXT;
+CREATE UNIQUE INDEX IF NOT EXISTS usuarios_email_unique ON usuarios (email);
+
+ALTER TABLE estudantes ALTER COLUMN email TYPE CITEXT;
@@ -0,0 +1,18 @@
+-- 0014_usuarios_suporte.sql
+--
+-- 🛟 Equipe de suporte e bloqueio de contas
+--
+-- Objetivo:
+--   Permitir que a equipe de suporte gerencie contas pela API
+--   (GET /api/admin/usuarios, bloquear, excluir).
+--
+-- Observações:
+-- - suporte é um papel da plataforma, independente dos papéis da organização
+--   (admin/editor/leitor). Só é concedido fora da API:
+--   `backend createuser --suporte` ou UPDATE direto no banco.
+-- - bloqueado_em preenchido = conta bloqueada: o X-User-Email deixa de resolver
+--   (401 nas rotas autenticadas) e o login responde 403 CONTA_BLOQUEADA.
+--   Os dados da conta continuam intactos; desbloquear volta tudo ao normal.
+
+ALTER TABLE usuarios ADD COLUMN IF NOT EXISTS suporte BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE usuarios ADD COLUMN IF NOT EXISTS bloqueado_em TIMESTAMPTZ;
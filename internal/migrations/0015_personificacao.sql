@@ -0,0 +1,30 @@
+-- 0015_personificacao.sql
+--
+-- 🕵️ Personificação de contas pela equipe de suporte
+--
+-- Objetivo:
+--   Permitir que o suporte atue como um usuário (POST /api/admin/impersonate)
+--   para investigar problemas relatados, deixando rastro de cada sessão e de
+--   cada ação feita em nome do usuário.
+--
+-- Observações:
+-- - personificacoes guarda só o hash do token (como organizacao_convites), quem
+--   pediu, para qual conta, o motivo e a validade (curta, PersonificacaoTTL).
+-- - auditoria.personificado_por preenchido = ação feita pelo suporte em nome do
+--   autor_id; aparece no feed de atividades como "suporte".
+-- - Sessões não são apagadas ao expirar: a tabela é o histórico de acessos do
+--   suporte. Excluir a conta do suporte mantém o registro (suporte_id NULL).
+
+CREATE TABLE IF NOT EXISTS personificacoes (
+    id BIGSERIAL PRIMARY KEY,
+    suporte_id INT REFERENCES usuarios(id) ON DELETE SET NULL,
+    usuario_id INT NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    token_hash TEXT NOT NULL UNIQUE,
+    motivo TEXT NOT NULL,
+    criado_em TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expira_em TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_personificacoes_usuario ON personificacoes (usuario_id, id DESC);
+
+ALTER TABLE auditoria ADD COLUMN IF NOT EXISTS personificado_por INT REFERENCES usuarios(id) ON DELETE SET NULL;
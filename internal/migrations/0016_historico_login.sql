@@ -0,0 +1,29 @@
+-- 0016_historico_login.sql
+--
+-- 🔑 Histórico de logins por conta
+--
+-- Objetivo:
+--   Registrar cada tentativa de login (senha ou Google) de uma conta existente,
+--   com IP e user agent, para o usuário revisar os acessos recentes em
+--   GET /api/usuario/logins e perceber atividade suspeita.
+--
+-- Observações:
+-- - Só entram tentativas de contas existentes: e-mail desconhecido não tem a
+--   quem ser mostrado (e registrar revelaria quais e-mails existem).
+-- - sucesso=false traz o motivo (senha_incorreta, conta_bloqueada).
+-- - ip vem de r.RemoteAddr, ou do X-Forwarded-For com HTTP_TRUST_PROXY=true.
+-- - O histórico é limitado por conta (model.historicoLoginMaximo, os mais
+--   recentes); o excedente é apagado a cada novo registro.
+
+CREATE TABLE IF NOT EXISTS logins (
+    id BIGSERIAL PRIMARY KEY,
+    usuario_id INT NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    metodo TEXT NOT NULL CHECK (metodo IN ('senha', 'google')),
+    sucesso BOOLEAN NOT NULL,
+    motivo TEXT NOT NULL DEFAULT '',
+    ip TEXT NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    criado_em TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_logins_usuario ON logins (usuario_id, id DESC);
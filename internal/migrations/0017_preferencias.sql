@@ -0,0 +1,18 @@
+-- 0017_preferencias.sql
+--
+-- ⚙️ Preferências do usuário
+--
+-- Objetivo:
+--   Guardar as preferências de interface de cada conta (tema, ano/turma padrão,
+--   colunas das tabelas) para GET/PUT /api/usuario/preferencias, em vez de uma
+--   coluna por preferência (como tutorial_visto).
+--
+-- Observações:
+-- - O formato é validado pela API (model.Preferencias); o banco só garante um
+--   objeto JSON. Chaves novas não exigem migration.
+-- - Preferências são da conta, não do tenant: membros de uma organização têm
+--   as suas. ano_padrao aponta para um ano do tenant e some da resposta quando
+--   o ano é removido.
+
+ALTER TABLE usuarios ADD COLUMN IF NOT EXISTS preferencias JSONB NOT NULL DEFAULT '{}'
+    CHECK (jsonb_typeof(preferencias) = 'object');
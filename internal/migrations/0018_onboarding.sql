@@ -0,0 +1,28 @@
+-- 0018_onboarding.sql
+--
+-- ✅ Etapas de onboarding por conta
+--
+-- Objetivo:
+--   Generalizar o flag tutorial_visto numa lista de etapas (ver o tutorial,
+--   criar o primeiro ano, cadastrar o primeiro estudante, enviar foto de
+--   perfil) para o frontend mostrar o progresso (GET /api/usuario/onboarding).
+--
+-- Observações:
+-- - Uma linha por etapa concluída. As etapas automáticas são detectadas a
+--   partir dos dados a cada GET e gravadas; depois de concluída, a etapa não
+--   volta atrás (remover o único estudante não reabre a etapa).
+-- - A lista de etapas fica no código (model.EtapasOnboarding); etapa nova não
+--   exige migration.
+-- - tutorial_visto continua existindo (PUT /api/usuario/{id}/tutorial): contas
+--   que já viram o tutorial entram com a etapa concluída.
+
+CREATE TABLE IF NOT EXISTS onboarding_etapas (
+    usuario_id INT NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    etapa TEXT NOT NULL,
+    concluida_em TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (usuario_id, etapa)
+);
+
+INSERT INTO onboarding_etapas (usuario_id, etapa)
+SELECT id, 'tutorial' FROM usuarios WHERE tutorial_visto
+ON CONFLICT DO NOTHING;
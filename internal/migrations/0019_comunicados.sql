@@ -0,0 +1,45 @@
+-- 0019_comunicados.sql
+--
+-- ✉️ Comunicados por e-mail aos responsáveis (mala direta)
+--
+-- Objetivo:
+--   Registrar cada comunicado enviado por POST /api/comunicados (assunto e
+--   corpo com campos como {{nome}}) e a situação da entrega para cada
+--   responsável, consultada em GET /api/comunicados/{id}.
+--
+-- Observações:
+-- - usuario_id é o dono dos dados (tenant); autor_id é quem enviou (membro da
+--   organização ou o próprio dono).
+-- - assunto/corpo guardam o texto com os campos, antes da mesclagem; cada
+--   destinatário guarda o e-mail e os nomes do momento do envio (editar ou
+--   remover o responsável depois não altera o histórico).
+-- - status: pendente (gravado antes do envio) | enviado | falhou |
+--   sem_email (responsável sem e-mail cadastrado, nada foi enviado).
+-- - Remover o comunicado apaga os destinatários em cascata.
+
+CREATE TABLE IF NOT EXISTS comunicados (
+    id SERIAL PRIMARY KEY,
+    usuario_id INT NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    autor_id INT REFERENCES usuarios(id) ON DELETE SET NULL,
+    assunto TEXT NOT NULL,
+    corpo TEXT NOT NULL,
+    criado_em TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_comunicados_usuario ON comunicados (usuario_id, id DESC);
+
+CREATE TABLE IF NOT EXISTS comunicado_destinatarios (
+    id BIGSERIAL PRIMARY KEY,
+    comunicado_id INT NOT NULL REFERENCES comunicados(id) ON DELETE CASCADE,
+    responsavel_id INT REFERENCES responsaveis(id) ON DELETE SET NULL,
+    estudante_id INT REFERENCES estudantes(id) ON DELETE SET NULL,
+    nome TEXT NOT NULL,
+    estudante TEXT NOT NULL,
+    email TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'pendente', -- pendente | enviado | falhou | sem_email
+    erro TEXT,
+    enviado_em TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_comunicado_destinatarios_comunicado
+    ON comunicado_destinatarios (comunicado_id, id);
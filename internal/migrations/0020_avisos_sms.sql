@@ -0,0 +1,26 @@
+-- 0020_avisos_sms.sql
+--
+-- 📱 Avisos por SMS/WhatsApp aos responsáveis
+--
+-- Objetivo:
+--   Guardar as credenciais do provedor de SMS/WhatsApp (Twilio ou Zenvia) de
+--   cada conta e registrar os avisos enviados por POST /api/avisos nas mesmas
+--   tabelas dos comunicados por e-mail, distinguidos pelo canal.
+--
+-- Observações:
+-- - provedores_mensagem tem uma linha por dono dos dados (tenant); credenciais
+--   guarda o JSON de mensageiro.Credenciais cifrado pela aplicação (PII_KEY,
+--   "enc:v1:..."); sem PII_KEY a aplicação não grava credenciais.
+-- - comunicados.canal: email (padrão, linhas anteriores) | sms | whatsapp.
+-- - comunicado_destinatarios.telefone é o telefone do responsável no envio;
+--   status ganha sem_telefone (responsável sem telefone, nada foi enviado).
+
+CREATE TABLE IF NOT EXISTS provedores_mensagem (
+    usuario_id INT PRIMARY KEY REFERENCES usuarios(id) ON DELETE CASCADE,
+    provedor TEXT NOT NULL, -- twilio | zenvia
+    credenciais TEXT NOT NULL,
+    atualizado_em TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE comunicados ADD COLUMN IF NOT EXISTS canal TEXT NOT NULL DEFAULT 'email';
+ALTER TABLE comunicado_destinatarios ADD COLUMN IF NOT EXISTS telefone TEXT NOT NULL DEFAULT '';
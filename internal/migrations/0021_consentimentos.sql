@@ -0,0 +1,39 @@
+-- 0021_consentimentos.sql
+--
+-- 🛡️ Consentimentos (LGPD) para comunicações e uso de foto
+--
+-- Objetivo:
+--   Registrar o consentimento dado pelos responsáveis, por estudante, para
+--   receber comunicados por e-mail, avisos por SMS/WhatsApp e para o uso da
+--   foto do estudante em documentos gerados (lista da turma, carteirinha).
+--   Sem consentimento ativo nada é enviado (status sem_consentimento) e a foto
+--   não é usada.
+--
+-- Observações:
+-- - finalidade: email | sms | whatsapp (exigem responsavel_id: é o contato
+--   daquele responsável) | foto (por estudante; responsavel_id opcional, quem
+--   autorizou).
+-- - Revogar não apaga a linha: preenche revogado_em/revogado_por e o
+--   histórico fica como prova (um novo registro cria outra linha).
+-- - No máximo um consentimento ativo por estudante, responsável e finalidade
+--   (índice parcial); remover o estudante ou o responsável apaga em cascata.
+
+CREATE TABLE IF NOT EXISTS consentimentos (
+    id BIGSERIAL PRIMARY KEY,
+    usuario_id INT NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    estudante_id INT NOT NULL REFERENCES estudantes(id) ON DELETE CASCADE,
+    responsavel_id INT REFERENCES responsaveis(id) ON DELETE CASCADE,
+    finalidade TEXT NOT NULL, -- email | sms | whatsapp | foto
+    observacao TEXT NOT NULL DEFAULT '',
+    concedido_em TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    registrado_por INT REFERENCES usuarios(id) ON DELETE SET NULL,
+    revogado_em TIMESTAMPTZ,
+    revogado_por INT REFERENCES usuarios(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_consentimentos_estudante
+    ON consentimentos (estudante_id, concedido_em DESC);
+
+CREATE UNIQUE INDEX IF NOT EXISTS consentimentos_ativo_unico
+    ON consentimentos (estudante_id, COALESCE(responsavel_id, 0), finalidade)
+    WHERE revogado_em IS NULL;
@@ -0,0 +1,32 @@
+-- 0022_sessoes.sql
+--
+-- 📱 Sessões por dispositivo
+--
+-- Objetivo:
+--   Cada login bem-sucedido (senha ou Google) emite um token de sessão, enviado
+--   em Authorization: Bearer. O usuário lista os dispositivos conectados em
+--   GET /api/usuario/sessoes e encerra um deles em DELETE /api/usuario/sessoes/{id}.
+--
+-- Observações:
+-- - Só o hash do token é gravado (como personificacoes e organizacao_convites).
+-- - Revogar preenche revogada_em; o middleware de autenticação recusa (401)
+--   token revogado, expirado ou de conta bloqueada.
+-- - ultimo_uso é atualizado no máximo a cada poucos minutos por sessão
+--   (model.sessaoUltimoUsoIntervalo), não a cada requisição.
+-- - Sessões expiradas ou revogadas não aparecem na listagem; o registro fica
+--   até a conta ser excluída.
+
+CREATE TABLE IF NOT EXISTS sessoes (
+    id BIGSERIAL PRIMARY KEY,
+    usuario_id INT NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    token_hash TEXT NOT NULL UNIQUE,
+    metodo TEXT NOT NULL CHECK (metodo IN ('senha', 'google')),
+    ip TEXT NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    criada_em TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ultimo_uso TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expira_em TIMESTAMPTZ NOT NULL,
+    revogada_em TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_sessoes_usuario_ativas ON sessoes (usuario_id, ultimo_uso DESC) WHERE revogada_em IS NULL;
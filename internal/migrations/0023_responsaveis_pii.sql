@@ -0,0 +1,23 @@
+-- 0023_responsaveis_pii.sql
+--
+-- 🔒 CPF e telefone dos responsáveis cifrados em repouso
+--
+-- Objetivo:
+--   Dar a responsaveis.cpf/telefone o mesmo tratamento de estudantes.cpf/telefone:
+--   valores cifrados pela aplicação (AES-GCM, "enc:v1:...", PII_KEY) e o índice
+--   cego cpf_hash (HMAC, PII_INDEX_KEY) para buscar por CPF sem decifrar.
+--
+-- Observações:
+-- - As colunas crescem para TEXT: o valor cifrado não cabe em VARCHAR(14)/(32).
+-- - O SQL não tem a chave: linhas existentes continuam em texto puro (a leitura
+--   aceita os dois formatos) até rodar `go run . cifrar-pii`, que cifra e
+--   preenche cpf_hash de estudantes e responsáveis (idempotente).
+-- - cpf_hash não é único: o mesmo responsável pode estar cadastrado em cada
+--   irmão. CPF vazio fica com cpf_hash NULL.
+
+ALTER TABLE responsaveis ALTER COLUMN cpf TYPE TEXT;
+ALTER TABLE responsaveis ALTER COLUMN telefone TYPE TEXT;
+ALTER TABLE responsaveis ADD COLUMN IF NOT EXISTS cpf_hash VARCHAR(64);
+
+CREATE INDEX IF NOT EXISTS responsaveis_cpf_hash_usuario_idx
+    ON responsaveis (usuario_id, cpf_hash) WHERE cpf_hash IS NOT NULL;
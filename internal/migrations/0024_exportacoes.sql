@@ -0,0 +1,33 @@
+-- 0024_exportacoes.sql
+--
+-- 📦 Pedidos de exportação dos dados (LGPD)
+--
+-- Objetivo:
+--   Guardar no banco o estado de GET /api/meus-dados/export (antes em memória):
+--   o pedido sobrevive a reinícios e a outras instâncias, e o arquivo no
+--   storage ("{usuario_id}/exports/{id}.zip") só é servido ao próprio
+--   solicitante enquanto o pedido existir.
+--
+-- Observações:
+-- - status: pendente | pronto | erro. No máximo um pendente por usuário
+--   (índice parcial); pedidos repetidos devolvem o mesmo.
+-- - Pendente abandonado (processo reiniciado no meio da geração) vira erro
+--   no próximo pedido (jobs.Exportacoes).
+-- - tenant_id é o dono dos dados exportados (o próprio usuário ou o dono da
+--   organização); membros exportam só o próprio perfil.
+-- - Após 24h (jobs.ExportacaoRetencao) a linha e o ZIP são apagados.
+
+CREATE TABLE IF NOT EXISTS exportacoes (
+    id TEXT PRIMARY KEY,
+    usuario_id INT NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    tenant_id INT NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    status TEXT NOT NULL DEFAULT 'pendente' CHECK (status IN ('pendente', 'pronto', 'erro')),
+    arquivo TEXT NOT NULL,
+    erro TEXT NOT NULL DEFAULT '',
+    criado_em TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    concluido_em TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS exportacoes_usuario_pendente_unique
+    ON exportacoes (usuario_id) WHERE status = 'pendente';
+CREATE INDEX IF NOT EXISTS exportacoes_criado_em_idx ON exportacoes (criado_em);
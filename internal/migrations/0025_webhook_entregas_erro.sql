@@ -0,0 +1,17 @@
+-- 0025_webhook_entregas_erro.sql
+--
+-- 🧹 Log de entregas de webhooks sem o corpo das respostas
+--
+-- Objetivo:
+--   ultimo_erro guardava até 4 KB do corpo respondido pelo destino (ou a
+--   mensagem da conexão, com IP e porta). Como o log é lido pelos admins do
+--   tenant, isso expunha respostas de serviços internos. A partir daqui o job
+--   grava só "HTTP <status>" ou uma categoria do erro; esta migration reescreve
+--   as entregas antigas no mesmo formato.
+
+UPDATE webhook_entregas
+   SET ultimo_erro = CASE
+           WHEN ultimo_status_http IS NOT NULL THEN 'HTTP ' || ultimo_status_http
+           ELSE 'falha de conexão'
+       END
+ WHERE ultimo_erro IS NOT NULL;
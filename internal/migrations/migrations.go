@@ -0,0 +1,255 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/migrations/migrations.go
+/// Responsabilidade: Migrations SQL embutidas no binário (NNNN_descricao.sql) e o executor que as aplica em ordem, registrando cada versão em schema_migrations.
+/// Dependências principais: embed, database/sql (Postgres).
+/// Pontos de atenção:
+/// - Cada arquivo roda em uma transação própria junto com o registro da versão; falha desfaz só aquele arquivo.
+/// - pg_advisory_lock serializa instâncias subindo ao mesmo tempo (apenas uma aplica; as demais esperam e não encontram pendências).
+/// - Migrations aplicadas são imutáveis: o checksum gravado é comparado e divergências abortam (edite criando um novo arquivo).
+/// - 0001_baseline.sql é idempotente para bases criadas antes deste controle (antigo schema.sql).
+*/
+
+package migrations
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// Migration é um arquivo SQL embutido.
+type Migration struct {
+	Versao   int
+	Nome     string // nome do arquivo (ex.: "0001_baseline.sql")
+	SQL      string
+	Checksum string // sha256 hex do conteúdo
+}
+
+// Status descreve uma migration e se já foi aplicada no banco.
+type Status struct {
+	Migration
+	Aplicada bool
+}
+
+/// ============ Configurações & Constantes ============
+
+//go:embed *.sql
+var arquivos embed.FS
+
+// chave do pg_advisory_lock (constante arbitrária do projeto)
+const lockID = 7_310_424_015
+
+// ErrChecksum indica que um arquivo já aplicado foi alterado depois.
+var ErrChecksum = errors.New("migration aplicada foi modificada")
+
+/// ============ Funções Públicas ============
+
+// Todas devolve as migrations embutidas, ordenadas por versão.
+func Todas() ([]Migration, error) {
+	nomes, err := fs.Glob(arquivos, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Migration, 0, len(nomes))
+	vistas := make(map[int]string, len(nomes))
+	for _, nome := range nomes {
+		prefixo, _, ok := strings.Cut(nome, "_")
+		v, err := strconv.Atoi(prefixo)
+		if !ok || err != nil || v <= 0 {
+			return nil, fmt.Errorf("migration %q: nome deve seguir NNNN_descricao.sql", nome)
+		}
+		if outro, dup := vistas[v]; dup {
+			return nil, fmt.Errorf("migrations %q e %q com a mesma versão %d", outro, nome, v)
+		}
+		vistas[v] = nome
+		b, err := arquivos.ReadFile(nome)
+		if err != nil {
+			return nil, err
+		}
+		soma := sha256.Sum256(b)
+		out = append(out, Migration{Versao: v, Nome: nome, SQL: string(b), Checksum: hex.EncodeToString(soma[:])})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Versao < out[j].Versao })
+	return out, nil
+}
+
+// Aplicar executa as migrations pendentes em ordem e devolve as que foram aplicadas agora.
+func Aplicar(ctx context.Context, db *sql.DB) ([]Migration, error) {
+	todas, err := Todas()
+	if err != nil {
+		return nil, err
+	}
+
+	// Lock de sessão: precisa da mesma conexão para adquirir e liberar.
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	// DB_STATEMENT_TIMEOUT vale para a API; a espera do lock e as migrations (índices,
+	// backfills) só respeitam MIGRATE_TIMEOUT. RESET volta ao valor da conexão antes de devolvê-la ao pool.
+	if _, err := conn.ExecContext(ctx, `SET statement_timeout = 0`); err != nil {
+		return nil, err
+	}
+	defer func() { _, _ = conn.ExecContext(context.Background(), `RESET statement_timeout`) }()
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, lockID); err != nil {
+		return nil, fmt.Errorf("adquirir lock de migrations: %w", err)
+	}
+	defer func() { _, _ = conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, lockID) }()
+
+	if err := criarTabelaControle(ctx, conn); err != nil {
+		return nil, err
+	}
+	aplicadas, err := versoesAplicadas(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	var feitas []Migration
+	for _, m := range todas {
+		if soma, ok := aplicadas[m.Versao]; ok {
+			if soma != m.Checksum {
+				return feitas, fmt.Errorf("%w: %s", ErrChecksum, m.Nome)
+			}
+			continue
+		}
+		if err := aplicarUma(ctx, conn, m); err != nil {
+			return feitas, fmt.Errorf("migration %s: %w", m.Nome, err)
+		}
+		feitas = append(feitas, m)
+	}
+	return feitas, nil
+}
+
+// Situacao lista todas as migrations embutidas indicando quais já foram aplicadas.
+func Situacao(ctx context.Context, db *sql.DB) ([]Status, error) {
+	todas, err := Todas()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if err := criarTabelaControle(ctx, conn); err != nil {
+		return nil, err
+	}
+	aplicadas, err := versoesAplicadas(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Status, 0, len(todas))
+	for _, m := range todas {
+		_, ok := aplicadas[m.Versao]
+		out = append(out, Status{Migration: m, Aplicada: ok})
+	}
+	return out, nil
+}
+
+// Pendentes devolve as migrations embutidas ainda não aplicadas, sem alterar o banco
+// (não cria schema_migrations; sem a tabela, todas estão pendentes). Usado por /readyz.
+func Pendentes(ctx context.Context, db *sql.DB) ([]Migration, error) {
+	todas, err := Todas()
+	if err != nil {
+		return nil, err
+	}
+	var existe bool
+	if err := db.QueryRowContext(ctx, `SELECT to_regclass('schema_migrations') IS NOT NULL`).Scan(&existe); err != nil {
+		return nil, err
+	}
+	if !existe {
+		return todas, nil
+	}
+	rows, err := db.QueryContext(ctx, `SELECT versao FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	aplicadas := make(map[int]bool)
+	for rows.Next() {
+		var v int
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		aplicadas[v] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	var out []Migration
+	for _, m := range todas {
+		if !aplicadas[m.Versao] {
+			out = append(out, m)
+		}
+	}
+	return out, nil
+}
+
+/// ============ Funções Internas (helpers) ============
+
+// criarTabelaControle garante a tabela schema_migrations.
+func criarTabelaControle(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			versao INT PRIMARY KEY,
+			nome TEXT NOT NULL,
+			checksum CHAR(64) NOT NULL,
+			aplicada_em TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("criar schema_migrations: %w", err)
+	}
+	return nil
+}
+
+// versoesAplicadas devolve versão → checksum do que já está no banco.
+func versoesAplicadas(ctx context.Context, conn *sql.Conn) (map[int]string, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT versao, checksum FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := make(map[int]string)
+	for rows.Next() {
+		var v int
+		var soma string
+		if err := rows.Scan(&v, &soma); err != nil {
+			return nil, err
+		}
+		out[v] = soma
+	}
+	return out, rows.Err()
+}
+
+// aplicarUma roda o SQL e registra a versão na mesma transação.
+func aplicarUma(ctx context.Context, conn *sql.Conn, m Migration) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, m.SQL); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO schema_migrations (versao, nome, checksum) VALUES ($1, $2, $3)`,
+		m.Versao, m.Nome, m.Checksum,
+	); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,84 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/model/acesso_cache.go
+/// Responsabilidade: Cache da resolução e-mail → Acesso (usuário, tenant, organização, papel) e chaves de cache dos dados por tenant, evitando consultas repetidas.
+/// Dependências principais: context, database/sql, strconv, time, backend/internal/cache.
+/// Pontos de atenção:
+/// - Só resoluções bem-sucedidas entram no cache; e-mail desconhecido continua indo ao banco (cadastro recém-feito já vale).
+/// - Mudanças de organização/papel chamam InvalidarUsuario (bloqueio/exclusão de conta, InvalidarEmail); o TTL limita a defasagem de alterações feitas fora da API.
+/// - Falha do backend de cache (Redis fora do ar) cai para a consulta no banco.
+*/
+
+package model
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	"backend/internal/cache"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// CacheAcesso resolve Acesso por e-mail normalizado, guardando o resultado por ttl.
+type CacheAcesso struct {
+	c   cache.Cache
+	ttl time.Duration
+}
+
+/// ============ Inicialização/Bootstrap ============
+
+// NovoCacheAcesso cria o cache sobre c; ttl <= 0 (ou c nil) desliga o cache.
+func NovoCacheAcesso(c cache.Cache, ttl time.Duration) *CacheAcesso {
+	return &CacheAcesso{c: c, ttl: ttl}
+}
+
+/// ============ Funções Públicas ============
+
+// ChaveCacheAnos é a chave da lista de anos (com contagens) do tenant; escritas nos dados a invalidam.
+func ChaveCacheAnos(tenantID int) string { return "anos:" + strconv.Itoa(tenantID) }
+
+// Resolver devolve o Acesso do e-mail (normalizado pelo chamador), do cache ou de ResolverAcesso.
+// Cache nil resolve sempre no banco.
+func (c *CacheAcesso) Resolver(ctx context.Context, db *sql.DB, email string) (Acesso, error) {
+	if c == nil || c.ttl <= 0 {
+		return ResolverAcesso(ctx, db, email)
+	}
+	var a Acesso
+	if cache.ObterJSON(ctx, c.c, chaveCacheAcesso(email), &a) {
+		return a, nil
+	}
+	a, err := ResolverAcesso(ctx, db, email)
+	if err != nil {
+		return a, err
+	}
+	cache.GuardarJSON(ctx, c.c, chaveCacheAcesso(email), a, c.ttl)
+	return a, nil
+}
+
+// InvalidarUsuario descarta a resolução em cache do usuário (membro adicionado/removido, papel alterado).
+func (c *CacheAcesso) InvalidarUsuario(ctx context.Context, db *sql.DB, usuarioID int) {
+	if c == nil || c.ttl <= 0 {
+		return
+	}
+	var email string
+	if err := db.QueryRowContext(ctx, `SELECT email FROM usuarios WHERE id = $1`, usuarioID).Scan(&email); err != nil {
+		return
+	}
+	cache.Invalidar(ctx, c.c, chaveCacheAcesso(email))
+}
+
+// InvalidarEmail descarta a resolução em cache de um e-mail (conta bloqueada ou excluída,
+// quando o id já não leva ao e-mail).
+func (c *CacheAcesso) InvalidarEmail(ctx context.Context, email string) {
+	if c == nil || c.ttl <= 0 {
+		return
+	}
+	cache.Invalidar(ctx, c.c, chaveCacheAcesso(NormalizarEmail(email)))
+}
+
+/// ============ Funções Internas (helpers) ============
+
+func chaveCacheAcesso(email string) string { return "acesso:" + email }
@@ -0,0 +1,178 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/model/admin_usuario.go
+/// Responsabilidade: Gestão de contas pela equipe de suporte (GET /api/admin/usuarios, GET /api/admin/usuario, bloquear, excluir): listagem paginada, busca por e-mail, bloqueio e exclusão de usuários.
+/// Dependências principais: context, database/sql, errors, strings, time.
+/// Pontos de atenção:
+/// - Quem chama já foi autorizado (Acesso.Suporte, middleware.ExigirSuporte); aqui não há escopo por tenant: as funções enxergam todas as contas.
+/// - Bloquear não apaga nada: ResolverAcesso ignora contas com bloqueado_em e o login responde 403. O chamador invalida o CacheAcesso.
+/// - Excluir apaga a conta e, em cascata (FKs ON DELETE CASCADE), todos os dados gravados com o usuario_id dela e a organização de que é dona.
+///   Por isso a exclusão é recusada se a organização dela tiver outros membros (ErrDonoComMembros).
+/// - Arquivos de upload/documentos da conta excluída ficam órfãos no storage até a limpeza de uploads (UPLOADS_GC_INTERVAL).
+*/
+
+package model
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// UsuarioAdmin é uma conta como vista pela equipe de suporte.
+type UsuarioAdmin struct {
+	ID            int        `json:"id"`
+	Nome          string     `json:"nome"`
+	Email         string     `json:"email"`
+	Google        bool       `json:"google"` // conta vinculada ao login Google
+	Suporte       bool       `json:"suporte"`
+	BloqueadoEm   *time.Time `json:"bloqueado_em"`    // null = conta ativa
+	OrganizacaoID int        `json:"organizacao_id"`  // 0 = sem organização
+	Papel         string     `json:"papel,omitempty"` // papel na organização
+}
+
+// PaginaUsuariosAdmin é a resposta paginada de GET /api/admin/usuarios
+// (proximo = valor de ?antes= da próxima página).
+type PaginaUsuariosAdmin struct {
+	Itens   []UsuarioAdmin `json:"itens"`
+	Proximo *int           `json:"proximo"`
+}
+
+// BloqueioRequest é o payload (opcional) de PUT /api/admin/usuarios/{id}/bloquear.
+type BloqueioRequest struct {
+	Bloqueado *bool `json:"bloqueado"` // omitido = true; false desbloqueia
+}
+
+/// ============ Configurações & Constantes ============
+
+var (
+	ErrUsuarioNaoEncontrado = errors.New("usuário não encontrado")
+	ErrPropriaConta         = errors.New("não é possível bloquear ou excluir a própria conta")
+	ErrDonoComMembros       = errors.New("usuário é dono de uma organização com outros membros; remova os membros antes de excluir a conta")
+)
+
+// colunas de UsuarioAdmin (usuarios u + organizacao_membros m)
+const colunasUsuarioAdmin = `u.id, COALESCE(u.nome, ''), u.email, u.google_sub IS NOT NULL, u.suporte, u.bloqueado_em,
+	COALESCE(m.organizacao_id, 0), COALESCE(m.papel, '')`
+
+/// ============ Funções Públicas ============
+
+// ListarUsuariosAdmin devolve até limite contas com id menor que antes (0 = do início),
+// da mais recente para a mais antiga. busca filtra nome/e-mail (contém, sem diferenciar caixa).
+func ListarUsuariosAdmin(ctx context.Context, db *sql.DB, busca string, antes, limite int) (PaginaUsuariosAdmin, error) {
+	busca = strings.ToLower(strings.TrimSpace(busca))
+	// Busca um item a mais para saber se há próxima página.
+	rows, err := db.QueryContext(ctx, `
+		SELECT `+colunasUsuarioAdmin+`
+		  FROM usuarios u
+		  LEFT JOIN organizacao_membros m ON m.usuario_id = u.id
+		 WHERE ($1 = 0 OR u.id < $1)
+		   AND ($2 = '' OR strpos(LOWER(COALESCE(u.nome, '')), $2) > 0 OR strpos(LOWER(u.email), $2) > 0)
+		 ORDER BY u.id DESC
+		 LIMIT $3
+	`, antes, busca, limite+1)
+	if err != nil {
+		return PaginaUsuariosAdmin{}, err
+	}
+	defer rows.Close()
+
+	out := PaginaUsuariosAdmin{Itens: []UsuarioAdmin{}}
+	for rows.Next() {
+		u, err := scanUsuarioAdmin(rows)
+		if err != nil {
+			return PaginaUsuariosAdmin{}, err
+		}
+		out.Itens = append(out.Itens, u)
+	}
+	if err := rows.Err(); err != nil {
+		return PaginaUsuariosAdmin{}, err
+	}
+	if len(out.Itens) > limite {
+		out.Itens = out.Itens[:limite]
+		proximo := out.Itens[limite-1].ID
+		out.Proximo = &proximo
+	}
+	return out, nil
+}
+
+// BuscarUsuarioAdmin devolve a conta com o e-mail informado (comparação sem diferenciar
+// caixa, CITEXT). ErrUsuarioNaoEncontrado se não existir.
+func BuscarUsuarioAdmin(ctx context.Context, db *sql.DB, email string) (UsuarioAdmin, error) {
+	u, err := scanUsuarioAdmin(db.QueryRowContext(ctx, `
+		SELECT `+colunasUsuarioAdmin+`
+		  FROM usuarios u
+		  LEFT JOIN organizacao_membros m ON m.usuario_id = u.id
+		 WHERE u.email = $1
+	`, NormalizarEmail(email)))
+	if err == sql.ErrNoRows {
+		return UsuarioAdmin{}, ErrUsuarioNaoEncontrado
+	}
+	return u, err
+}
+
+// BloquearUsuario marca (bloquear=true) ou limpa bloqueado_em da conta id e devolve a
+// conta atualizada. Bloquear de novo mantém a data original. ErrUsuarioNaoEncontrado se não existir.
+func BloquearUsuario(ctx context.Context, db *sql.DB, id int, bloquear bool) (UsuarioAdmin, error) {
+	u, err := scanUsuarioAdmin(db.QueryRowContext(ctx, `
+		WITH alterado AS (
+			UPDATE usuarios
+			   SET bloqueado_em = CASE WHEN $2 THEN COALESCE(bloqueado_em, NOW()) END
+			 WHERE id = $1
+			RETURNING *
+		)
+		SELECT `+colunasUsuarioAdmin+`
+		  FROM alterado u
+		  LEFT JOIN organizacao_membros m ON m.usuario_id = u.id
+	`, id, bloquear))
+	if err == sql.ErrNoRows {
+		return UsuarioAdmin{}, ErrUsuarioNaoEncontrado
+	}
+	return u, err
+}
+
+// ExcluirUsuario apaga a conta id (e, em cascata, os dados dela) e devolve o e-mail
+// excluído. ErrUsuarioNaoEncontrado se não existir; ErrDonoComMembros se for dona de
+// uma organização com outros membros.
+func ExcluirUsuario(ctx context.Context, db *sql.DB, id int) (string, error) {
+	var email string
+	err := ComTransacao(ctx, db, func(tx *sql.Tx) error {
+		if err := tx.QueryRowContext(ctx,
+			`SELECT email FROM usuarios WHERE id = $1 FOR UPDATE`, id,
+		).Scan(&email); err != nil {
+			return err
+		}
+		var comMembros bool
+		if err := tx.QueryRowContext(ctx, `
+			SELECT EXISTS(
+				SELECT 1
+				  FROM organizacoes o
+				  JOIN organizacao_membros m ON m.organizacao_id = o.id
+				 WHERE o.dono_id = $1 AND m.usuario_id <> $1
+			)`, id,
+		).Scan(&comMembros); err != nil {
+			return err
+		}
+		if comMembros {
+			return ErrDonoComMembros
+		}
+		_, err := tx.ExecContext(ctx, `DELETE FROM usuarios WHERE id = $1`, id)
+		return err
+	})
+	if err == sql.ErrNoRows {
+		return "", ErrUsuarioNaoEncontrado
+	}
+	return email, err
+}
+
+/// ============ Funções Internas (helpers) ============
+
+// scanUsuarioAdmin lê as colunasUsuarioAdmin de uma linha (*sql.Row ou *sql.Rows).
+func scanUsuarioAdmin(s interface{ Scan(...any) error }) (UsuarioAdmin, error) {
+	var u UsuarioAdmin
+	err := s.Scan(&u.ID, &u.Nome, &u.Email, &u.Google, &u.Suporte, &u.BloqueadoEm, &u.OrganizacaoID, &u.Papel)
+	return u, err
+}
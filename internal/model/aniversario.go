@@ -0,0 +1,157 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/model/aniversario.go
+/// Responsabilidade: Aniversariantes (estudantes ativos) numa janela de dias: consulta por dia/mês e cálculo da próxima data e da idade a completar.
+/// Dependências principais: context, database/sql, Array (driver.go), sort, time.
+/// Pontos de atenção:
+/// - A busca compara apenas dia e mês (to_char 'MM-DD'); o ano de nascimento não importa.
+/// - Nascidos em 29/02 comemoram em 28/02 nos anos não bissextos.
+/// - Estudantes sem data_nascimento, excluídos ou com status diferente de "ativo" ficam de fora.
+*/
+
+package model
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// Aniversariante é um estudante com aniversário dentro da janela consultada.
+type Aniversariante struct {
+	ID             int    `json:"id"`
+	Nome           string `json:"nome"`
+	DataNascimento string `json:"data_nascimento"` // YYYY-MM-DD
+	FotoURL        string `json:"foto_url"`
+	AnoID          int    `json:"ano_id"`
+	TurmaID        int    `json:"turma_id"`
+	Aniversario    string `json:"aniversario"`    // data da comemoração dentro da janela (YYYY-MM-DD)
+	Idade          int    `json:"idade"`          // idade completada no aniversário
+	DiasRestantes  int    `json:"dias_restantes"` // a partir de hoje (negativo = já passou)
+}
+
+/// ============ Funções Públicas ============
+
+// ListarAniversariantes devolve os estudantes ativos do usuário que fazem aniversário
+// entre de (inclusive) e de+dias (exclusive), ordenados pela data e pelo nome.
+// hoje é a referência de DiasRestantes.
+func ListarAniversariantes(ctx context.Context, db *sql.DB, uid int, de time.Time, dias int, hoje time.Time) ([]Aniversariante, error) {
+	de = inicioDoDia(de)
+	rows, err := db.QueryContext(ctx, `
+		SELECT id, nome, to_char(data_nascimento, 'YYYY-MM-DD'), COALESCE(foto_url, ''),
+		       COALESCE(ano_id, 0), COALESCE(turma_id, 0)
+		  FROM estudantes
+		 WHERE usuario_id = $1 AND excluido_em IS NULL AND status = $2
+		   AND data_nascimento IS NOT NULL
+		   AND to_char(data_nascimento, 'MM-DD') = ANY($3)
+	`, uid, StatusAtivo, Array(diasMes(de, dias)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []Aniversariante{}
+	for rows.Next() {
+		var a Aniversariante
+		if err := rows.Scan(&a.ID, &a.Nome, &a.DataNascimento, &a.FotoURL, &a.AnoID, &a.TurmaID); err != nil {
+			return nil, err
+		}
+		nasc, err := time.ParseInLocation(dateLayoutISO, a.DataNascimento, de.Location())
+		if err != nil {
+			return nil, err
+		}
+		data := ProximoAniversario(nasc, de)
+		a.Aniversario = data.Format(dateLayoutISO)
+		a.Idade = data.Year() - nasc.Year()
+		a.DiasRestantes = diasEntre(inicioDoDia(hoje), data)
+		out = append(out, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Aniversario != out[j].Aniversario {
+			return out[i].Aniversario < out[j].Aniversario
+		}
+		return out[i].Nome < out[j].Nome
+	})
+	return out, nil
+}
+
+// UsuariosComAniversariantes lista os donos de dados (usuario_id) com ao menos um
+// estudante ativo fazendo aniversário entre de e de+dias.
+func UsuariosComAniversariantes(ctx context.Context, db *sql.DB, de time.Time, dias int) ([]int, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT DISTINCT usuario_id
+		  FROM estudantes
+		 WHERE excluido_em IS NULL AND status = $1
+		   AND data_nascimento IS NOT NULL
+		   AND to_char(data_nascimento, 'MM-DD') = ANY($2)
+		 ORDER BY usuario_id
+	`, StatusAtivo, Array(diasMes(inicioDoDia(de), dias)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []int
+	for rows.Next() {
+		var uid int
+		if err := rows.Scan(&uid); err != nil {
+			return nil, err
+		}
+		out = append(out, uid)
+	}
+	return out, rows.Err()
+}
+
+// ProximoAniversario devolve a primeira comemoração de quem nasceu em nasc a partir
+// de desde (inclusive); 29/02 vira 28/02 em anos não bissextos.
+func ProximoAniversario(nasc, desde time.Time) time.Time {
+	desde = inicioDoDia(desde)
+	for ano := desde.Year(); ; ano++ {
+		d := aniversarioNoAno(nasc, ano, desde.Location())
+		if !d.Before(desde) {
+			return d
+		}
+	}
+}
+
+/// ============ Funções Internas (helpers) ============
+
+// aniversarioNoAno é a data da comemoração no ano informado.
+func aniversarioNoAno(nasc time.Time, ano int, loc *time.Location) time.Time {
+	if nasc.Month() == time.February && nasc.Day() == 29 && !bissexto(ano) {
+		return time.Date(ano, time.February, 28, 0, 0, 0, 0, loc)
+	}
+	return time.Date(ano, nasc.Month(), nasc.Day(), 0, 0, 0, 0, loc)
+}
+
+// diasMes lista os "MM-DD" de nascimento comemorados na janela (inclui 02-29
+// quando a janela passa por 28/02 de um ano não bissexto).
+func diasMes(de time.Time, dias int) []string {
+	out := make([]string, 0, dias+1)
+	for i := 0; i < dias; i++ {
+		d := de.AddDate(0, 0, i)
+		out = append(out, d.Format("01-02"))
+		if d.Month() == time.February && d.Day() == 28 && !bissexto(d.Year()) {
+			out = append(out, "02-29")
+		}
+	}
+	return out
+}
+
+func bissexto(ano int) bool { return ano%4 == 0 && (ano%100 != 0 || ano%400 == 0) }
+
+func inicioDoDia(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// diasEntre conta dias de calendário (robusto a horário de verão).
+func diasEntre(de, ate time.Time) int {
+	a := time.Date(de.Year(), de.Month(), de.Day(), 0, 0, 0, 0, time.UTC)
+	b := time.Date(ate.Year(), ate.Month(), ate.Day(), 0, 0, 0, 0, time.UTC)
+	return int(b.Sub(a).Hours() / 24)
+}
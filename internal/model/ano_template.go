@@ -0,0 +1,127 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/model/ano_template.go
+/// Responsabilidade: Template de criação em lote de anos (série + turmas), expandido nos nomes que serão gravados em `anos`.
+/// Dependências principais: errors, regexp, strconv, strings.
+/// Pontos de atenção:
+/// - Serie aceita um número ("8º") ou intervalo ("1º ao 9º", "1 a 5", "6-9"); o símbolo º é sempre normalizado.
+/// - Turmas vazias geram um ano por série sem sufixo ("1º"); caso contrário "1º A", "1º B", ...
+/// - Limites (maxSeriesTemplate/maxTurmasTemplate) evitam criação acidental de centenas de anos.
+*/
+
+package model
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// AnoTemplate é o payload de POST /api/anos/bulk.
+type AnoTemplate struct {
+	Serie  string   `json:"serie"`
+	Turmas []string `json:"turmas"`
+}
+
+// SerieGerada agrupa os nomes de anos gerados para uma série.
+type SerieGerada struct {
+	Serie string   `json:"serie"`
+	Nomes []string `json:"-"`
+}
+
+/// ============ Configurações & Constantes ============
+
+const (
+	maxSeriesTemplate = 20
+	maxTurmasTemplate = 26
+)
+
+var (
+	reSerieUnica     = regexp.MustCompile(`^(\d{1,2})\s*[º°o]?$`)
+	reSerieIntervalo = regexp.MustCompile(`^(\d{1,2})\s*[º°o]?\s*(?:ao|a|até|-)\s*(\d{1,2})\s*[º°o]?$`)
+
+	ErrSerieInvalida     = errors.New(`serie inválida (ex.: "8º" ou "1º ao 9º")`)
+	ErrTemplateGrande    = errors.New("template gera anos demais (máx. 20 séries e 26 turmas)")
+	ErrTurmaVazia        = errors.New("nome de turma vazio no template")
+	ErrTurmaRepetidaTmpl = errors.New("turma repetida no template")
+)
+
+/// ============ Funções Públicas ============
+
+// Sanitize faz trim da série e das turmas.
+func (t *AnoTemplate) Sanitize() {
+	t.Serie = strings.TrimSpace(t.Serie)
+	for i := range t.Turmas {
+		t.Turmas[i] = strings.TrimSpace(t.Turmas[i])
+	}
+}
+
+// Validate confere a série, as turmas e os limites do template.
+func (t AnoTemplate) Validate() error {
+	if _, err := t.series(); err != nil {
+		return err
+	}
+	if len(t.Turmas) > maxTurmasTemplate {
+		return ErrTemplateGrande
+	}
+	vistas := map[string]bool{}
+	for _, tu := range t.Turmas {
+		if tu == "" {
+			return ErrTurmaVazia
+		}
+		k := strings.ToLower(tu)
+		if vistas[k] {
+			return ErrTurmaRepetidaTmpl
+		}
+		vistas[k] = true
+	}
+	return nil
+}
+
+// Expandir gera os nomes de anos agrupados por série, na ordem do template.
+func (t AnoTemplate) Expandir() ([]SerieGerada, error) {
+	series, err := t.series()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]SerieGerada, 0, len(series))
+	for _, n := range series {
+		s := SerieGerada{Serie: strconv.Itoa(n) + "º"}
+		if len(t.Turmas) == 0 {
+			s.Nomes = []string{s.Serie}
+		}
+		for _, tu := range t.Turmas {
+			s.Nomes = append(s.Nomes, s.Serie+" "+tu)
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+// series interpreta o campo Serie como lista de números (intervalo inclusivo).
+func (t AnoTemplate) series() ([]int, error) {
+	var de, ate int
+	if m := reSerieIntervalo.FindStringSubmatch(t.Serie); m != nil {
+		de, _ = strconv.Atoi(m[1])
+		ate, _ = strconv.Atoi(m[2])
+	} else if m := reSerieUnica.FindStringSubmatch(t.Serie); m != nil {
+		de, _ = strconv.Atoi(m[1])
+		ate = de
+	} else {
+		return nil, ErrSerieInvalida
+	}
+	if de <= 0 || ate < de {
+		return nil, ErrSerieInvalida
+	}
+	if ate-de+1 > maxSeriesTemplate {
+		return nil, ErrTemplateGrande
+	}
+	out := make([]int, 0, ate-de+1)
+	for n := de; n <= ate; n++ {
+		out = append(out, n)
+	}
+	return out, nil
+}
@@ -0,0 +1,147 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/model/atividade.go
+/// Responsabilidade: Log de auditoria (tabela `auditoria`): ações registradas pelos handlers e sua apresentação como feed de atividades ("Você criou o estudante X").
+/// Dependências principais: context, database/sql, encoding/json, strconv, time.
+/// Pontos de atenção:
+/// - O registro é feito após a operação confirmada; falha ao registrar não desfaz a operação (apenas log).
+/// - Descrição e link são montados na leitura (a partir de acao/resumo), então mudar o texto não exige migração.
+/// - personificado_por marca as ações feitas pelo suporte em nome do autor (X-Impersonation-Token); o feed as sinaliza em autor.suporte.
+///   Além das ações dos handlers, toda escrita personificada gera um requisicao.executada (middleware.Autenticacao).
+/// - Link aponta para o recurso na API e é omitido nas remoções (lixeira ou definitiva).
+*/
+
+package model
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// NovaAtividade é o que um handler registra no log de auditoria.
+type NovaAtividade struct {
+	TenantID         int    // dono dos dados
+	AutorID          int    // conta que executou a ação
+	PersonificadoPor int    // suporte que agiu em nome de AutorID (0 = o próprio autor)
+	Acao             string // ex.: AcaoEstudanteCriado
+	EntidadeID       int
+	Resumo           string         // nome da entidade no momento da ação
+	Dados            map[string]any // detalhes opcionais (ex.: estudantes removidos junto com o ano)
+}
+
+// Atividade é um item do feed GET /api/atividades.
+type Atividade struct {
+	ID         int64          `json:"id"`
+	Acao       string         `json:"acao"`
+	Entidade   string         `json:"entidade"`
+	EntidadeID int            `json:"entidade_id"`
+	Descricao  string         `json:"descricao"`
+	Link       string         `json:"link,omitempty"` // recurso na API (ausente em remoções)
+	Autor      AutorAtividade `json:"autor"`
+	Dados      map[string]any `json:"dados"`
+	CriadoEm   time.Time      `json:"criado_em"`
+}
+
+// AutorAtividade identifica quem executou a ação (ID 0 = conta removida).
+type AutorAtividade struct {
+	ID      int    `json:"id"`
+	Nome    string `json:"nome"`
+	Voce    bool   `json:"voce"`    // true quando é o próprio usuário autenticado
+	Suporte bool   `json:"suporte"` // true quando a equipe de suporte agiu em nome do autor (personificação)
+}
+
+// FeedAtividades é a resposta paginada do feed (proximo = valor de ?antes= da próxima página).
+type FeedAtividades struct {
+	Itens   []Atividade `json:"itens"`
+	Proximo *int64      `json:"proximo"`
+}
+
+/// ============ Configurações & Constantes ============
+
+// Ações registradas na auditoria ("<entidade>.<verbo>").
+const (
+	AcaoEstudanteCriado     = "estudante.criado"
+	AcaoEstudanteAtualizado = "estudante.atualizado"
+	AcaoEstudanteRemovido   = "estudante.removido"
+	AcaoEstudanteRestaurado = "estudante.restaurado"
+	AcaoEstudanteExpurgado  = "estudante.expurgado" // excluído definitivamente da lixeira
+	AcaoAnoCriado           = "ano.criado"
+	AcaoAnoRemovido         = "ano.removido"
+	AcaoAnoRestaurado       = "ano.restaurado"
+	AcaoAnoExpurgado        = "ano.expurgado"
+	AcaoAnoCompartilhado    = "ano.compartilhado"  // link público da lista de estudantes
+	AcaoComunicadoEnviado   = "comunicado.enviado" // e-mail aos responsáveis
+
+	AcaoConsentimentoRegistrado = "consentimento.registrado" // LGPD: e-mail, SMS/WhatsApp ou foto
+	AcaoConsentimentoRevogado   = "consentimento.revogado"
+
+	AcaoRequisicaoPersonificada = "requisicao.executada" // escrita do suporte com X-Impersonation-Token (resumo = "MÉTODO /caminho")
+)
+
+// verbos (pretérito) e artigos usados na descrição
+var (
+	verbosAtividade = map[string]string{
+		"criado": "criou", "atualizado": "editou", "removido": "removeu",
+		"restaurado": "restaurou", "expurgado": "excluiu definitivamente",
+		"compartilhado": "compartilhou", "enviado": "enviou",
+		"registrado": "registrou", "revogado": "revogou", "executada": "executou",
+	}
+	nomesEntidade = map[string]string{
+		"estudante": "o estudante", "ano": "o ano/turma", "comunicado": "o comunicado", "consentimento": "o consentimento",
+		"requisicao": "a requisição",
+	}
+	rotasEntidade = map[string]string{"estudante": "/api/estudantes/", "ano": "/api/anos/", "comunicado": "/api/comunicados/"}
+)
+
+/// ============ Funções Públicas ============
+
+// RegistrarAtividade grava a ação no log de auditoria.
+func RegistrarAtividade(ctx context.Context, db *sql.DB, a NovaAtividade) error {
+	dados := a.Dados
+	if dados == nil {
+		dados = map[string]any{}
+	}
+	payload, err := json.Marshal(dados)
+	if err != nil {
+		return err
+	}
+	entidade, _, _ := strings.Cut(a.Acao, ".")
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO auditoria (usuario_id, autor_id, acao, entidade, entidade_id, resumo, dados, personificado_por)
+		VALUES ($1, NULLIF($2, 0), $3, $4, $5, $6, $7, NULLIF($8, 0))
+	`, a.TenantID, a.AutorID, a.Acao, entidade, a.EntidadeID, a.Resumo, string(payload), a.PersonificadoPor)
+	return err
+}
+
+// Descrever preenche Descricao e Link a partir de acao, resumo e de quem está lendo.
+func (a *Atividade) Descrever(resumo string) {
+	entidade, verbo, _ := strings.Cut(a.Acao, ".")
+	quem := a.Autor.Nome
+	switch {
+	case a.Autor.Voce:
+		quem = "Você"
+	case quem == "":
+		quem = "Alguém"
+	}
+	acao := verbosAtividade[verbo]
+	if acao == "" {
+		acao = verbo
+	}
+	alvo := nomesEntidade[entidade]
+	if alvo == "" {
+		alvo = entidade
+	}
+	a.Descricao = strings.TrimSpace(quem + " " + acao + " " + alvo + " " + resumo)
+	if a.Autor.Suporte {
+		a.Descricao += " (pela equipe de suporte)"
+	}
+	if rota, ok := rotasEntidade[entidade]; ok && verbo != "removido" && verbo != "expurgado" {
+		a.Link = rota + strconv.Itoa(a.EntidadeID)
+	}
+}
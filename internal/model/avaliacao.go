@@ -0,0 +1,140 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/model/avaliacao.go
+/// Responsabilidade: DTOs e validação de avaliações (provas/trabalhos por Ano/Turma) e notas por estudante, além das linhas do boletim.
+/// Dependências principais: errors, strings, time.
+/// Pontos de atenção:
+/// - Notas vão de 0 até NotaMaxima (padrão 10); a média do boletim é normalizada para a escala 0–10.
+/// - Média ponderada pelo campo Peso (padrão 1).
+/// - Periodo é texto livre curto (ex.: "1º bimestre"); o boletim agrupa por disciplina + período.
+*/
+
+package model
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// Avaliacao representa um registro da tabela `avaliacoes`.
+type Avaliacao struct {
+	ID         int     `json:"id"`
+	AnoID      int     `json:"ano_id"`
+	Disciplina string  `json:"disciplina"`
+	Titulo     string  `json:"titulo"`
+	Periodo    string  `json:"periodo"`
+	Peso       float64 `json:"peso"`
+	NotaMaxima float64 `json:"nota_maxima"`
+	Data       string  `json:"data,omitempty"` // YYYY-MM-DD
+}
+
+// AvaliacaoCreateRequest é o payload de POST /api/avaliacoes.
+type AvaliacaoCreateRequest struct {
+	AnoID      int     `json:"ano_id"`
+	Disciplina string  `json:"disciplina"`
+	Titulo     string  `json:"titulo"`
+	Periodo    string  `json:"periodo"`
+	Peso       float64 `json:"peso"`
+	NotaMaxima float64 `json:"nota_maxima"`
+	Data       string  `json:"data"`
+}
+
+// NotaItem é a nota de um estudante em uma avaliação.
+type NotaItem struct {
+	EstudanteID int     `json:"estudante_id"`
+	Valor       float64 `json:"valor"`
+}
+
+// NotasRequest é o payload de PUT /api/avaliacoes/{id}/notas.
+type NotasRequest struct {
+	Notas []NotaItem `json:"notas"`
+}
+
+// BoletimLinha agrega a média de um estudante em uma disciplina/período.
+type BoletimLinha struct {
+	Disciplina  string  `json:"disciplina"`
+	Periodo     string  `json:"periodo"`
+	Media       float64 `json:"media"` // 0–10, duas casas
+	Avaliacoes  int     `json:"avaliacoes"`
+	PesoLancado float64 `json:"peso_lancado"`
+}
+
+/// ============ Configurações & Constantes ============
+
+const (
+	notaMaximaPadrao = 10.0
+	pesoPadrao       = 1.0
+)
+
+var (
+	ErrDisciplinaObrigatoria = errors.New("disciplina é obrigatória")
+	ErrTituloObrigatorio     = errors.New("título da avaliação é obrigatório")
+	ErrAnoObrigatorio        = errors.New("ano_id é obrigatório")
+	ErrPesoInvalido          = errors.New("peso deve ser maior que zero")
+	ErrDataAvaliacao         = errors.New("data inválida (esperado YYYY-MM-DD)")
+	ErrNotasVazias           = errors.New("informe ao menos uma nota")
+	ErrNotaForaDaEscala      = errors.New("nota fora da escala da avaliação")
+	ErrEstudanteIDInvalido   = errors.New("estudante_id inválido")
+)
+
+/// ============ Funções Públicas ============
+
+// Sanitize faz trim dos textos e aplica padrões de peso/nota máxima.
+func (a *AvaliacaoCreateRequest) Sanitize() {
+	a.Disciplina = strings.TrimSpace(a.Disciplina)
+	a.Titulo = strings.TrimSpace(a.Titulo)
+	a.Periodo = strings.TrimSpace(a.Periodo)
+	a.Data = strings.TrimSpace(a.Data)
+	if a.Peso == 0 {
+		a.Peso = pesoPadrao
+	}
+	if a.NotaMaxima == 0 {
+		a.NotaMaxima = notaMaximaPadrao
+	}
+}
+
+// Validate exige ano, disciplina, título, peso/nota máxima positivos e data ISO (se enviada).
+func (a AvaliacaoCreateRequest) Validate() error {
+	if a.AnoID <= 0 {
+		return ErrAnoObrigatorio
+	}
+	if a.Disciplina == "" {
+		return ErrDisciplinaObrigatoria
+	}
+	if a.Titulo == "" {
+		return ErrTituloObrigatorio
+	}
+	if a.Peso <= 0 || a.NotaMaxima <= 0 {
+		return ErrPesoInvalido
+	}
+	if a.Data != "" {
+		if _, err := time.Parse(dateLayoutISO, a.Data); err != nil {
+			return ErrDataAvaliacao
+		}
+	}
+	return nil
+}
+
+// Validate confere a lista de notas contra a escala da avaliação.
+func (n NotasRequest) Validate(notaMaxima float64) error {
+	if len(n.Notas) == 0 {
+		return ErrNotasVazias
+	}
+	vistos := make(map[int]bool, len(n.Notas))
+	for _, it := range n.Notas {
+		if it.EstudanteID <= 0 {
+			return ErrEstudanteIDInvalido
+		}
+		if vistos[it.EstudanteID] {
+			return ErrEstudanteRepetido
+		}
+		if it.Valor < 0 || it.Valor > notaMaxima {
+			return ErrNotaForaDaEscala
+		}
+		vistos[it.EstudanteID] = true
+	}
+	return nil
+}
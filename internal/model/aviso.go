@@ -0,0 +1,200 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/model/aviso.go
+/// Responsabilidade: Avisos curtos por SMS/WhatsApp aos responsáveis (payload de envio) e as credenciais do provedor de cada conta, gravadas cifradas em provedores_mensagem.
+/// Dependências principais: context, database/sql (Postgres), encoding/json, backend/internal/cripto, backend/internal/mensageiro.
+/// Pontos de atenção:
+/// - Avisos são gravados como comunicados (canal sms/whatsapp, sem assunto) e usam os mesmos campos de mesclagem.
+/// - As credenciais só são gravadas com um Cifrador ativo (PII_KEY): sem ele, SalvarProvedorMensagem devolve ErrCifradorInativo.
+/// - O token nunca volta nas respostas; ProvedorMensagem mostra só os últimos caracteres.
+*/
+
+package model
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"backend/internal/cripto"
+	"backend/internal/mensageiro"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// AvisoRequest é o payload de POST /api/avisos.
+type AvisoRequest struct {
+	Canal string `json:"canal"` // sms | whatsapp
+	Texto string `json:"texto"`
+	SelecaoComunicado
+}
+
+// ProvedorMensagemRequest é o payload de PUT /api/avisos/provedor.
+type ProvedorMensagemRequest struct {
+	Provedor  string `json:"provedor"`  // twilio | zenvia
+	ContaSID  string `json:"conta_sid"` // só Twilio
+	Token     string `json:"token"`
+	Remetente string `json:"remetente"`
+}
+
+// ProvedorMensagem é a configuração exibida (sem o token).
+type ProvedorMensagem struct {
+	Provedor     string    `json:"provedor"`
+	ContaSID     string    `json:"conta_sid,omitempty"`
+	Remetente    string    `json:"remetente"`
+	TokenFinal   string    `json:"token_final"` // últimos caracteres do token, para conferência
+	AtualizadoEm time.Time `json:"atualizado_em"`
+}
+
+/// ============ Configurações & Constantes ============
+
+// tamanho máximo do texto de um aviso (dois SMS)
+const avisoTextoMaximo = 320
+
+var (
+	ErrAvisoCanal             = errors.New("canal inválido (sms ou whatsapp)")
+	ErrAvisoTexto             = errors.New("texto obrigatório, com no máximo 320 caracteres")
+	ErrProvedorMensagem       = errors.New("provedor inválido (twilio ou zenvia)")
+	ErrProvedorContaSID       = errors.New("conta_sid é obrigatório para a Twilio")
+	ErrProvedorToken          = errors.New("token é obrigatório")
+	ErrProvedorRemetente      = errors.New("remetente é obrigatório")
+	ErrCifradorInativo        = errors.New("credenciais exigem criptografia ativa (PII_KEY)")
+	ErrProvedorNaoConfigurado = errors.New("provedor de SMS/WhatsApp não configurado")
+)
+
+/// ============ Funções Públicas ============
+
+// Sanitize normaliza canal (minúsculas), texto e ids.
+func (r *AvisoRequest) Sanitize() {
+	r.Canal = strings.ToLower(strings.TrimSpace(r.Canal))
+	r.Texto = strings.TrimSpace(strings.ReplaceAll(r.Texto, "\r\n", "\n"))
+	r.SelecaoComunicado.Sanitize()
+}
+
+// Validate exige canal conhecido, texto curto só com campos conhecidos e a seleção.
+func (r AvisoRequest) Validate() error {
+	var ev ErrosValidacao
+	if !slices.Contains(mensageiro.Canais, r.Canal) {
+		ev.Add("canal", RegraFormato, ErrAvisoCanal)
+	}
+	switch {
+	case r.Texto == "":
+		ev.Add("texto", RegraObrigatorio, ErrAvisoTexto)
+	case utf8.RuneCountInString(r.Texto) > avisoTextoMaximo:
+		ev.Add("texto", RegraFormato, ErrAvisoTexto)
+	case !camposConhecidos(r.Texto):
+		ev.Add("texto", RegraFormato, ErrComunicadoCampo)
+	}
+	r.SelecaoComunicado.validar(&ev)
+	return ev.Err()
+}
+
+// Sanitize faz trim dos campos e deixa o provedor em minúsculas.
+func (r *ProvedorMensagemRequest) Sanitize() {
+	r.Provedor = strings.ToLower(strings.TrimSpace(r.Provedor))
+	r.ContaSID = strings.TrimSpace(r.ContaSID)
+	r.Token = strings.TrimSpace(r.Token)
+	r.Remetente = strings.TrimSpace(r.Remetente)
+}
+
+// Validate exige provedor conhecido, token, remetente e, na Twilio, o conta_sid.
+func (r ProvedorMensagemRequest) Validate() error {
+	var ev ErrosValidacao
+	if !slices.Contains(mensageiro.Provedores, r.Provedor) {
+		ev.Add("provedor", RegraFormato, ErrProvedorMensagem)
+	}
+	if r.Provedor == mensageiro.ProvedorTwilio && r.ContaSID == "" {
+		ev.Add("conta_sid", RegraObrigatorio, ErrProvedorContaSID)
+	}
+	if r.Token == "" {
+		ev.Add("token", RegraObrigatorio, ErrProvedorToken)
+	}
+	if r.Remetente == "" {
+		ev.Add("remetente", RegraObrigatorio, ErrProvedorRemetente)
+	}
+	return ev.Err()
+}
+
+// Credenciais converte o payload nas credenciais do mensageiro.
+func (r ProvedorMensagemRequest) Credenciais() mensageiro.Credenciais {
+	return mensageiro.Credenciais{Provedor: r.Provedor, ContaSID: r.ContaSID, Token: r.Token, Remetente: r.Remetente}
+}
+
+// SalvarProvedorMensagem grava (ou troca) as credenciais da conta, cifradas com pii.
+func SalvarProvedorMensagem(ctx context.Context, db *sql.DB, pii *cripto.Cifrador, uid int, c mensageiro.Credenciais) (ProvedorMensagem, error) {
+	if !pii.Ativo() {
+		return ProvedorMensagem{}, ErrCifradorInativo
+	}
+	payload, err := json.Marshal(c)
+	if err != nil {
+		return ProvedorMensagem{}, err
+	}
+	cifrado, err := pii.Cifrar(string(payload))
+	if err != nil {
+		return ProvedorMensagem{}, err
+	}
+	var atualizado time.Time
+	err = db.QueryRowContext(ctx, `
+		INSERT INTO provedores_mensagem (usuario_id, provedor, credenciais)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (usuario_id) DO UPDATE
+		   SET provedor = EXCLUDED.provedor, credenciais = EXCLUDED.credenciais, atualizado_em = NOW()
+		RETURNING atualizado_em
+	`, uid, c.Provedor, cifrado).Scan(&atualizado)
+	if err != nil {
+		return ProvedorMensagem{}, err
+	}
+	return exibirProvedor(c, atualizado), nil
+}
+
+// BuscarProvedorMensagem lê e decifra as credenciais da conta (ErrProvedorNaoConfigurado se não houver).
+func BuscarProvedorMensagem(ctx context.Context, db *sql.DB, pii *cripto.Cifrador, uid int) (mensageiro.Credenciais, ProvedorMensagem, error) {
+	var cifrado string
+	var atualizado time.Time
+	err := db.QueryRowContext(ctx,
+		`SELECT credenciais, atualizado_em FROM provedores_mensagem WHERE usuario_id=$1`, uid,
+	).Scan(&cifrado, &atualizado)
+	if err == sql.ErrNoRows {
+		return mensageiro.Credenciais{}, ProvedorMensagem{}, ErrProvedorNaoConfigurado
+	}
+	if err != nil {
+		return mensageiro.Credenciais{}, ProvedorMensagem{}, err
+	}
+	payload, err := pii.Decifrar(cifrado)
+	if err != nil {
+		return mensageiro.Credenciais{}, ProvedorMensagem{}, err
+	}
+	var c mensageiro.Credenciais
+	if err := json.Unmarshal([]byte(payload), &c); err != nil {
+		return mensageiro.Credenciais{}, ProvedorMensagem{}, err
+	}
+	return c, exibirProvedor(c, atualizado), nil
+}
+
+// RemoverProvedorMensagem apaga as credenciais da conta (false se não havia).
+func RemoverProvedorMensagem(ctx context.Context, db *sql.DB, uid int) (bool, error) {
+	res, err := db.ExecContext(ctx, `DELETE FROM provedores_mensagem WHERE usuario_id=$1`, uid)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+/// ============ Funções Internas (helpers) ============
+
+// exibirProvedor monta a visão sem segredo das credenciais.
+func exibirProvedor(c mensageiro.Credenciais, atualizado time.Time) ProvedorMensagem {
+	final := c.Token
+	if n := len(final); n > 4 {
+		final = "..." + final[n-4:]
+	} else {
+		final = "..."
+	}
+	return ProvedorMensagem{Provedor: c.Provedor, ContaSID: c.ContaSID, Remetente: c.Remetente, TokenFinal: final, AtualizadoEm: atualizado}
+}
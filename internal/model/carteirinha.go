@@ -0,0 +1,90 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/model/carteirinha.go
+/// Responsabilidade: Código assinado do QR code da carteirinha do estudante (emissão e leitura) e resposta da verificação.
+/// Dependências principais: crypto/hmac, crypto/sha256, encoding/base64, errors, strconv, strings.
+/// Pontos de atenção:
+/// - Formato: "TCM1.<tenant>.<estudante>.<assinatura>", assinatura = HMAC-SHA256(CARTEIRINHA_KEY) truncado em 16 bytes (base64url).
+/// - O código só identifica o estudante; nome, turma e situação são lidos do banco na verificação (carteirinha de aluno excluído deixa de valer).
+/// - Trocar CARTEIRINHA_KEY invalida todas as carteirinhas impressas.
+*/
+
+package model
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// VerificacaoCarteirinha é a resposta de GET /api/carteirinhas/verificar.
+type VerificacaoCarteirinha struct {
+	Valido    bool                  `json:"valido"`
+	Motivo    string                `json:"motivo,omitempty"` // codigo_invalido | outra_conta | nao_encontrado | inativo
+	Estudante *EstudanteCarteirinha `json:"estudante,omitempty"`
+}
+
+// EstudanteCarteirinha é o que a verificação mostra do estudante.
+type EstudanteCarteirinha struct {
+	ID      int    `json:"id"`
+	Nome    string `json:"nome"`
+	Turma   string `json:"turma"`
+	Status  string `json:"status"`
+	FotoURL string `json:"foto_url"`
+}
+
+/// ============ Configurações & Constantes ============
+
+// Motivos de uma carteirinha inválida.
+const (
+	CarteirinhaCodigoInvalido = "codigo_invalido"
+	CarteirinhaOutraConta     = "outra_conta"
+	CarteirinhaNaoEncontrada  = "nao_encontrado"
+	CarteirinhaInativa        = "inativo"
+)
+
+const (
+	prefixoCarteirinha     = "TCM1"
+	assinaturaCarteirinhaN = 16 // bytes do HMAC mantidos (QR menor)
+)
+
+var ErrCodigoCarteirinha = errors.New("código de carteirinha inválido")
+
+/// ============ Funções Públicas ============
+
+// CodigoCarteirinha gera o conteúdo assinado do QR code.
+func CodigoCarteirinha(chave []byte, tenantID, estudanteID int) string {
+	corpo := prefixoCarteirinha + "." + strconv.Itoa(tenantID) + "." + strconv.Itoa(estudanteID)
+	return corpo + "." + assinarCarteirinha(chave, corpo)
+}
+
+// LerCodigoCarteirinha confere a assinatura e devolve tenant e estudante.
+func LerCodigoCarteirinha(chave []byte, codigo string) (tenantID, estudanteID int, err error) {
+	partes := strings.Split(strings.TrimSpace(codigo), ".")
+	if len(partes) != 4 || partes[0] != prefixoCarteirinha {
+		return 0, 0, ErrCodigoCarteirinha
+	}
+	corpo := strings.Join(partes[:3], ".")
+	if !hmac.Equal([]byte(partes[3]), []byte(assinarCarteirinha(chave, corpo))) {
+		return 0, 0, ErrCodigoCarteirinha
+	}
+	tenantID, err1 := strconv.Atoi(partes[1])
+	estudanteID, err2 := strconv.Atoi(partes[2])
+	if err1 != nil || err2 != nil || tenantID <= 0 || estudanteID <= 0 {
+		return 0, 0, ErrCodigoCarteirinha
+	}
+	return tenantID, estudanteID, nil
+}
+
+/// ============ Funções Internas (helpers) ============
+
+func assinarCarteirinha(chave []byte, corpo string) string {
+	m := hmac.New(sha256.New, chave)
+	m.Write([]byte(corpo))
+	return base64.RawURLEncoding.EncodeToString(m.Sum(nil)[:assinaturaCarteirinhaN])
+}
@@ -0,0 +1,93 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/model/compartilhamento.go
+/// Responsabilidade: Links públicos, assinados e com validade, da lista de estudantes de um ano/turma (para quem não tem conta, ex.: coordenação).
+/// Dependências principais: crypto/hmac, crypto/sha256, encoding/hex, errors, strconv, time.
+/// Pontos de atenção:
+/// - Sem estado no banco: assinatura = HMAC-SHA256(COMPARTILHAR_KEY, "ano:<id>:<dono>:<exp>"); o link não pode ser revogado individualmente (trocar a chave revoga todos).
+/// - A lista pública traz só nome dos estudantes ativos; CPF, e-mail, telefone e data de nascimento nunca saem.
+/// - Validade entre 1 hora e 30 dias (padrão 72 horas).
+*/
+
+package model
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// CompartilharAnoRequest é o corpo (opcional) de POST /api/anos/{id}/share.
+type CompartilharAnoRequest struct {
+	ExpiraEmHoras int `json:"expira_em_horas"`
+}
+
+// LinkCompartilhamento é a resposta da criação do link.
+type LinkCompartilhamento struct {
+	URL      string    `json:"url"`
+	ExpiraEm time.Time `json:"expira_em"`
+}
+
+// TurmaCompartilhada é a lista pública (somente leitura) de um ano/turma.
+type TurmaCompartilhada struct {
+	Ano        string                   `json:"ano"`
+	Total      int                      `json:"total"`
+	Estudantes []EstudanteCompartilhado `json:"estudantes"`
+	ExpiraEm   time.Time                `json:"expira_em"`
+}
+
+// EstudanteCompartilhado é uma linha da lista pública.
+type EstudanteCompartilhado struct {
+	Numero int    `json:"numero"` // ordem alfabética (nº de chamada)
+	Nome   string `json:"nome"`
+}
+
+/// ============ Configurações & Constantes ============
+
+const (
+	compartilharHorasPadrao = 72
+	compartilharHorasMax    = 30 * 24
+)
+
+var ErrCompartilharValidade = errors.New("expira_em_horas deve estar entre 1 e 720")
+
+/// ============ Funções Públicas ============
+
+// Sanitize aplica a validade padrão quando omitida.
+func (r *CompartilharAnoRequest) Sanitize() {
+	if r.ExpiraEmHoras == 0 {
+		r.ExpiraEmHoras = compartilharHorasPadrao
+	}
+}
+
+// Validate confere a validade pedida.
+func (r CompartilharAnoRequest) Validate() error {
+	if r.ExpiraEmHoras < 1 || r.ExpiraEmHoras > compartilharHorasMax {
+		var ev ErrosValidacao
+		ev.Add("expira_em_horas", RegraFormato, ErrCompartilharValidade)
+		return ev.Err()
+	}
+	return nil
+}
+
+// AssinarCompartilhamento gera a assinatura do link do ano (dono = tenant dos dados).
+func AssinarCompartilhamento(chave []byte, anoID, donoID int, exp int64) string {
+	m := hmac.New(sha256.New, chave)
+	m.Write([]byte("ano:" + strconv.Itoa(anoID) + ":" + strconv.Itoa(donoID) + ":" + strconv.FormatInt(exp, 10)))
+	return hex.EncodeToString(m.Sum(nil))
+}
+
+// ConferirCompartilhamento valida exp/sig do link em relação a agora.
+func ConferirCompartilhamento(chave []byte, anoID, donoID int, exp, sig string, agora time.Time) (time.Time, bool) {
+	ts, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil || sig == "" || agora.Unix() > ts {
+		return time.Time{}, false
+	}
+	ok := hmac.Equal([]byte(sig), []byte(AssinarCompartilhamento(chave, anoID, donoID, ts)))
+	return time.Unix(ts, 0), ok
+}
@@ -0,0 +1,357 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/model/comunicado.go
+/// Responsabilidade: Comunicados aos responsáveis (mala direta por e-mail; avisos por SMS/WhatsApp em aviso.go): payload de envio, campos de mesclagem ({{nome}}, {{estudante}}...) e gravação da situação por destinatário.
+/// Dependências principais: context, database/sql (Postgres), regexp, slices, strings, time, backend/internal/mensageiro (canais).
+/// Pontos de atenção:
+/// - Os campos são substituídos por texto simples (sem text/template sobre o texto do usuário); campo desconhecido é erro de validação, não sai em branco.
+/// - Um destinatário por par (responsável, estudante): quem é responsável por dois estudantes selecionados recebe uma mensagem para cada um.
+/// - Os destinatários são gravados como "pendente" antes do envio; o handler marca cada um com MarcarDestinatario depois da tentativa.
+/// - Responsável sem consentimento ativo no canal (consentimento.go) é gravado como "sem_consentimento" e não recebe nada.
+*/
+
+package model
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"backend/internal/mensageiro"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// SelecaoComunicado escolhe os estudantes cujos responsáveis recebem a mensagem.
+type SelecaoComunicado struct {
+	EstudanteIDs []int `json:"estudante_ids"`
+	TurmaIDs     []int `json:"turma_ids"` // anos.id: estudantes com esse ano ou turma
+}
+
+// ComunicadoRequest é o payload de POST /api/comunicados.
+type ComunicadoRequest struct {
+	Assunto string `json:"assunto"`
+	Corpo   string `json:"corpo"`
+	SelecaoComunicado
+}
+
+// Comunicado representa um registro da tabela `comunicados` com o resumo das entregas.
+type Comunicado struct {
+	ID               int                      `json:"id"`
+	Canal            string                   `json:"canal"`             // email | sms | whatsapp
+	Assunto          string                   `json:"assunto,omitempty"` // vazio nos avisos
+	Corpo            string                   `json:"corpo"`
+	AutorID          int                      `json:"autor_id,omitempty"`
+	CriadoEm         time.Time                `json:"criado_em"`
+	Total            int                      `json:"total"`
+	Enviados         int                      `json:"enviados"`
+	Falhas           int                      `json:"falhas"`
+	SemEmail         int                      `json:"sem_email"`
+	SemTelefone      int                      `json:"sem_telefone"`
+	SemConsentimento int                      `json:"sem_consentimento"`       // responsável sem consentimento (LGPD) no canal
+	Destinatarios    []DestinatarioComunicado `json:"destinatarios,omitempty"` // só no detalhe/criação
+}
+
+// DestinatarioComunicado é a entrega a um responsável (tabela `comunicado_destinatarios`).
+type DestinatarioComunicado struct {
+	ID            int64      `json:"id"`
+	ResponsavelID int        `json:"responsavel_id,omitempty"` // 0 = responsável removido depois
+	EstudanteID   int        `json:"estudante_id,omitempty"`
+	Nome          string     `json:"nome"`      // responsável
+	Estudante     string     `json:"estudante"` // nome do estudante
+	Email         string     `json:"email,omitempty"`
+	Telefone      string     `json:"telefone,omitempty"`
+	Status        string     `json:"status"` // pendente | enviado | falhou | sem_email | sem_telefone | sem_consentimento
+	Erro          string     `json:"erro,omitempty"`
+	EnviadoEm     *time.Time `json:"enviado_em,omitempty"`
+}
+
+// MesclagemComunicado são os valores dos campos para um destinatário.
+type MesclagemComunicado struct {
+	Nome      string // responsável
+	Estudante string
+	Ano       string
+	Turma     string
+	Escola    string
+}
+
+/// ============ Configurações & Constantes ============
+
+// Situações de um destinatário.
+const (
+	DestinatarioPendente    = "pendente"
+	DestinatarioEnviado     = "enviado"
+	DestinatarioFalhou      = "falhou"
+	DestinatarioSemEmail    = "sem_email"
+	DestinatarioSemTelefone = "sem_telefone"
+
+	DestinatarioSemConsentimento = "sem_consentimento" // sem consentimento ativo no canal (consentimento.go)
+)
+
+// Canais de um comunicado.
+const (
+	CanalEmail    = "email"
+	CanalSMS      = mensageiro.CanalSMS
+	CanalWhatsApp = mensageiro.CanalWhatsApp
+)
+
+// CamposComunicado lista os campos aceitos em assunto/corpo ({{campo}}).
+var CamposComunicado = []string{"nome", "estudante", "ano", "turma", "escola"}
+
+// limites do comunicado
+const (
+	comunicadoAssuntoMaximo = 200
+	comunicadoCorpoMaximo   = 20000
+	comunicadoMaxIDs        = 500
+)
+
+// {{campo}}, com espaços opcionais dentro das chaves
+var campoComunicado = regexp.MustCompile(`\{\{\s*([A-Za-z_]+)\s*\}\}`)
+
+var (
+	ErrComunicadoAssunto = errors.New("assunto obrigatório, com no máximo 200 caracteres")
+	ErrComunicadoCorpo   = errors.New("corpo obrigatório, com no máximo 20000 caracteres")
+	ErrComunicadoCampo   = errors.New("campo desconhecido (use {{nome}}, {{estudante}}, {{ano}}, {{turma}} ou {{escola}})")
+	ErrComunicadoSelecao = errors.New("informe estudante_ids e/ou turma_ids")
+	ErrComunicadoIDs     = errors.New("no máximo 500 ids por lista")
+)
+
+/// ============ Funções Públicas ============
+
+// Sanitize normaliza assunto (uma linha), corpo e listas de ids (sem repetição).
+func (r *ComunicadoRequest) Sanitize() {
+	r.Assunto = strings.Join(strings.Fields(r.Assunto), " ")
+	r.Corpo = strings.TrimSpace(strings.ReplaceAll(r.Corpo, "\r\n", "\n"))
+	r.SelecaoComunicado.Sanitize()
+}
+
+// Validate exige assunto e corpo dentro dos limites, só com campos conhecidos,
+// e ao menos um estudante ou turma selecionado.
+func (r ComunicadoRequest) Validate() error {
+	var ev ErrosValidacao
+	switch {
+	case r.Assunto == "":
+		ev.Add("assunto", RegraObrigatorio, ErrComunicadoAssunto)
+	case utf8.RuneCountInString(r.Assunto) > comunicadoAssuntoMaximo:
+		ev.Add("assunto", RegraFormato, ErrComunicadoAssunto)
+	case !camposConhecidos(r.Assunto):
+		ev.Add("assunto", RegraFormato, ErrComunicadoCampo)
+	}
+	switch {
+	case r.Corpo == "":
+		ev.Add("corpo", RegraObrigatorio, ErrComunicadoCorpo)
+	case utf8.RuneCountInString(r.Corpo) > comunicadoCorpoMaximo:
+		ev.Add("corpo", RegraFormato, ErrComunicadoCorpo)
+	case !camposConhecidos(r.Corpo):
+		ev.Add("corpo", RegraFormato, ErrComunicadoCampo)
+	}
+	r.SelecaoComunicado.validar(&ev)
+	return ev.Err()
+}
+
+// Sanitize remove ids repetidos.
+func (r *SelecaoComunicado) Sanitize() {
+	r.EstudanteIDs = semRepetir(r.EstudanteIDs)
+	r.TurmaIDs = semRepetir(r.TurmaIDs)
+}
+
+// Seleciona diz se o estudante foi escolhido pelo id ou pelo ano/turma.
+func (r SelecaoComunicado) Seleciona(e Estudante) bool {
+	for _, id := range r.TurmaIDs {
+		if id == e.AnoID || id == e.TurmaID {
+			return true
+		}
+	}
+	for _, id := range r.EstudanteIDs {
+		if id == e.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// Aplicar troca os campos {{...}} do texto pelos valores do destinatário.
+func (m MesclagemComunicado) Aplicar(texto string) string {
+	return campoComunicado.ReplaceAllStringFunc(texto, func(s string) string {
+		switch strings.ToLower(campoComunicado.FindStringSubmatch(s)[1]) {
+		case "nome":
+			return m.Nome
+		case "estudante":
+			return m.Estudante
+		case "ano":
+			return m.Ano
+		case "turma":
+			return m.Turma
+		case "escola":
+			return m.Escola
+		}
+		return s
+	})
+}
+
+// Contar recalcula total e contagens a partir dos destinatários carregados.
+func (c *Comunicado) Contar() {
+	c.Total, c.Enviados, c.Falhas, c.SemEmail, c.SemTelefone, c.SemConsentimento = len(c.Destinatarios), 0, 0, 0, 0, 0
+	for _, d := range c.Destinatarios {
+		switch d.Status {
+		case DestinatarioEnviado:
+			c.Enviados++
+		case DestinatarioFalhou:
+			c.Falhas++
+		case DestinatarioSemEmail:
+			c.SemEmail++
+		case DestinatarioSemTelefone:
+			c.SemTelefone++
+		case DestinatarioSemConsentimento:
+			c.SemConsentimento++
+		}
+	}
+}
+
+// CriarComunicado grava o comunicado c (canal, textos, autor) e os destinatários (status
+// pendente, sem_email, sem_telefone ou sem_consentimento) numa transação e devolve c com os ids preenchidos.
+func CriarComunicado(ctx context.Context, db *sql.DB, tenantID int, c Comunicado) (Comunicado, error) {
+	err := ComTransacao(ctx, db, func(tx *sql.Tx) error {
+		if err := tx.QueryRowContext(ctx, `
+			INSERT INTO comunicados (usuario_id, autor_id, canal, assunto, corpo)
+			VALUES ($1, NULLIF($2, 0), $3, $4, $5)
+			RETURNING id, criado_em
+		`, tenantID, c.AutorID, c.Canal, c.Assunto, c.Corpo).Scan(&c.ID, &c.CriadoEm); err != nil {
+			return err
+		}
+		for i := range c.Destinatarios {
+			d := &c.Destinatarios[i]
+			if err := tx.QueryRowContext(ctx, `
+				INSERT INTO comunicado_destinatarios (comunicado_id, responsavel_id, estudante_id, nome, estudante, email, telefone, status)
+				VALUES ($1, NULLIF($2, 0), NULLIF($3, 0), $4, $5, $6, $7, $8)
+				RETURNING id
+			`, c.ID, d.ResponsavelID, d.EstudanteID, d.Nome, d.Estudante, d.Email, d.Telefone, d.Status).Scan(&d.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return Comunicado{}, err
+	}
+	c.Contar()
+	return c, nil
+}
+
+// MarcarDestinatario grava o desfecho do envio (enviado ou falhou, com o erro).
+func MarcarDestinatario(ctx context.Context, db *sql.DB, d *DestinatarioComunicado, envio error) error {
+	d.Status, d.Erro = DestinatarioEnviado, ""
+	if envio != nil {
+		d.Status, d.Erro = DestinatarioFalhou, envio.Error()
+	} else {
+		agora := time.Now()
+		d.EnviadoEm = &agora
+	}
+	_, err := db.ExecContext(ctx, `
+		UPDATE comunicado_destinatarios
+		   SET status=$2, erro=NULLIF($3, ''), enviado_em=$4
+		 WHERE id=$1
+	`, d.ID, d.Status, d.Erro, d.EnviadoEm)
+	return err
+}
+
+// ListarComunicados devolve os comunicados do tenant (mais recentes primeiro) com as contagens.
+func ListarComunicados(ctx context.Context, db *sql.DB, tenantID, limite int) ([]Comunicado, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT c.id, c.canal, c.assunto, c.corpo, COALESCE(c.autor_id, 0), c.criado_em,
+		       COUNT(d.id),
+		       COUNT(d.id) FILTER (WHERE d.status = 'enviado'),
+		       COUNT(d.id) FILTER (WHERE d.status = 'falhou'),
+		       COUNT(d.id) FILTER (WHERE d.status = 'sem_email'),
+		       COUNT(d.id) FILTER (WHERE d.status = 'sem_telefone'),
+		       COUNT(d.id) FILTER (WHERE d.status = 'sem_consentimento')
+		  FROM comunicados c
+		  LEFT JOIN comunicado_destinatarios d ON d.comunicado_id = c.id
+		 WHERE c.usuario_id=$1
+		 GROUP BY c.id
+		 ORDER BY c.id DESC
+		 LIMIT $2
+	`, tenantID, limite)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []Comunicado{}
+	for rows.Next() {
+		var c Comunicado
+		if err := rows.Scan(&c.ID, &c.Canal, &c.Assunto, &c.Corpo, &c.AutorID, &c.CriadoEm,
+			&c.Total, &c.Enviados, &c.Falhas, &c.SemEmail, &c.SemTelefone, &c.SemConsentimento); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+// BuscarComunicado carrega o comunicado do tenant com os destinatários (sql.ErrNoRows se não existir).
+func BuscarComunicado(ctx context.Context, db *sql.DB, tenantID, id int) (Comunicado, error) {
+	var c Comunicado
+	err := db.QueryRowContext(ctx, `
+		SELECT id, canal, assunto, corpo, COALESCE(autor_id, 0), criado_em
+		  FROM comunicados
+		 WHERE id=$1 AND usuario_id=$2
+	`, id, tenantID).Scan(&c.ID, &c.Canal, &c.Assunto, &c.Corpo, &c.AutorID, &c.CriadoEm)
+	if err != nil {
+		return Comunicado{}, err
+	}
+	rows, err := db.QueryContext(ctx, `
+		SELECT id, COALESCE(responsavel_id, 0), COALESCE(estudante_id, 0), nome, estudante, email, telefone, status,
+		       COALESCE(erro, ''), enviado_em
+		  FROM comunicado_destinatarios
+		 WHERE comunicado_id=$1
+		 ORDER BY id ASC
+	`, c.ID)
+	if err != nil {
+		return Comunicado{}, err
+	}
+	defer rows.Close()
+	c.Destinatarios = []DestinatarioComunicado{}
+	for rows.Next() {
+		var d DestinatarioComunicado
+		if err := rows.Scan(&d.ID, &d.ResponsavelID, &d.EstudanteID, &d.Nome, &d.Estudante, &d.Email, &d.Telefone, &d.Status,
+			&d.Erro, &d.EnviadoEm); err != nil {
+			return Comunicado{}, err
+		}
+		c.Destinatarios = append(c.Destinatarios, d)
+	}
+	if err := rows.Err(); err != nil {
+		return Comunicado{}, err
+	}
+	c.Contar()
+	return c, nil
+}
+
+/// ============ Funções Internas (helpers) ============
+
+// validar exige ao menos um estudante ou turma e respeita o limite de ids.
+func (r SelecaoComunicado) validar(ev *ErrosValidacao) {
+	if len(r.EstudanteIDs) == 0 && len(r.TurmaIDs) == 0 {
+		ev.Add("estudante_ids", RegraObrigatorio, ErrComunicadoSelecao)
+	}
+	if len(r.EstudanteIDs) > comunicadoMaxIDs {
+		ev.Add("estudante_ids", RegraFormato, ErrComunicadoIDs)
+	}
+	if len(r.TurmaIDs) > comunicadoMaxIDs {
+		ev.Add("turma_ids", RegraFormato, ErrComunicadoIDs)
+	}
+}
+
+// camposConhecidos diz se todos os {{campo}} do texto estão em CamposComunicado.
+func camposConhecidos(texto string) bool {
+	for _, m := range campoComunicado.FindAllStringSubmatch(texto, -1) {
+		if !slices.Contains(CamposComunicado, strings.ToLower(m[1])) {
+			return false
+		}
+	}
+	return true
+}
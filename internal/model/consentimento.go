@@ -0,0 +1,211 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/model/consentimento.go
+/// Responsabilidade: Consentimentos (LGPD) dos responsáveis, por estudante, para comunicados por e-mail, avisos por SMS/WhatsApp e uso da foto do estudante: registro, revogação e consulta de quem pode receber/aparecer.
+/// Dependências principais: context, database/sql (Postgres), errors, slices, strings, time, unicode/utf8.
+/// Pontos de atenção:
+/// - Finalidades de contato (email, sms, whatsapp) são do responsável (responsavel_id obrigatório); foto é do estudante e vale com qualquer consentimento ativo.
+/// - Revogar só preenche revogado_em: o histórico fica como prova do que foi autorizado e quando.
+/// - Sem registro ativo vale "não": quem envia ou gera documentos consulta ComConsentimento antes e pula quem não consentiu.
+*/
+
+package model
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// ConsentimentoRequest é o payload de POST /api/estudantes/{id}/consentimentos.
+type ConsentimentoRequest struct {
+	Finalidade    string `json:"finalidade"`     // email | sms | whatsapp | foto
+	ResponsavelID int    `json:"responsavel_id"` // obrigatório exceto em foto
+	Observacao    string `json:"observacao"`     // ex.: "termo assinado na matrícula"
+}
+
+// Consentimento representa um registro da tabela `consentimentos`.
+type Consentimento struct {
+	ID            int64      `json:"id"`
+	EstudanteID   int        `json:"estudante_id"`
+	ResponsavelID int        `json:"responsavel_id,omitempty"`
+	Responsavel   string     `json:"responsavel,omitempty"` // nome do responsável
+	Finalidade    string     `json:"finalidade"`
+	Observacao    string     `json:"observacao,omitempty"`
+	Ativo         bool       `json:"ativo"`
+	ConcedidoEm   time.Time  `json:"concedido_em"`
+	RegistradoPor int        `json:"registrado_por,omitempty"`
+	RevogadoEm    *time.Time `json:"revogado_em,omitempty"`
+	RevogadoPor   int        `json:"revogado_por,omitempty"`
+}
+
+/// ============ Configurações & Constantes ============
+
+// Finalidades de consentimento (as de contato coincidem com os canais dos comunicados).
+const (
+	FinalidadeEmail    = CanalEmail
+	FinalidadeSMS      = CanalSMS
+	FinalidadeWhatsApp = CanalWhatsApp
+	FinalidadeFoto     = "foto"
+)
+
+// Finalidades lista os valores aceitos em finalidade.
+var Finalidades = []string{FinalidadeEmail, FinalidadeSMS, FinalidadeWhatsApp, FinalidadeFoto}
+
+// tamanho máximo da observação
+const consentimentoObservacaoMaxima = 500
+
+var (
+	ErrFinalidadeInvalida   = errors.New("finalidade inválida (email, sms, whatsapp ou foto)")
+	ErrConsentimentoContato = errors.New("responsavel_id é obrigatório para email, sms e whatsapp")
+	ErrObservacaoLonga      = errors.New("observacao com no máximo 500 caracteres")
+	ErrConsentimentoAtivo   = errors.New("já existe consentimento ativo para essa finalidade")
+	ErrResponsavelDoAluno   = errors.New("responsável não pertence ao estudante")
+)
+
+// colunas lidas de consentimentos (c) com o nome do responsável (r)
+const colunasConsentimento = `
+	c.id, c.estudante_id, COALESCE(c.responsavel_id, 0), COALESCE(r.nome, ''), c.finalidade, c.observacao,
+	c.concedido_em, COALESCE(c.registrado_por, 0), c.revogado_em, COALESCE(c.revogado_por, 0)`
+
+/// ============ Funções Públicas ============
+
+// Sanitize normaliza finalidade (minúsculas) e observação.
+func (r *ConsentimentoRequest) Sanitize() {
+	r.Finalidade = strings.ToLower(strings.TrimSpace(r.Finalidade))
+	r.Observacao = strings.TrimSpace(r.Observacao)
+}
+
+// Validate exige finalidade conhecida, responsável nas finalidades de contato e observação curta.
+func (r ConsentimentoRequest) Validate() error {
+	var ev ErrosValidacao
+	if !slices.Contains(Finalidades, r.Finalidade) {
+		ev.Add("finalidade", RegraFormato, ErrFinalidadeInvalida)
+	} else if r.ResponsavelID < 0 || (r.Finalidade != FinalidadeFoto && r.ResponsavelID == 0) {
+		ev.Add("responsavel_id", RegraObrigatorio, ErrConsentimentoContato)
+	}
+	if utf8.RuneCountInString(r.Observacao) > consentimentoObservacaoMaxima {
+		ev.Add("observacao", RegraFormato, ErrObservacaoLonga)
+	}
+	return ev.Err()
+}
+
+// ListarConsentimentos devolve o histórico do estudante (mais recentes primeiro, revogados inclusive).
+func ListarConsentimentos(ctx context.Context, db *sql.DB, tenantID, estudanteID int) ([]Consentimento, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT `+colunasConsentimento+`
+		  FROM consentimentos c
+		  LEFT JOIN responsaveis r ON r.id = c.responsavel_id
+		 WHERE c.usuario_id=$1 AND c.estudante_id=$2
+		 ORDER BY c.concedido_em DESC, c.id DESC
+	`, tenantID, estudanteID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []Consentimento{}
+	for rows.Next() {
+		c, err := scanConsentimento(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+// RegistrarConsentimento grava um consentimento ativo para o estudante. Devolve
+// ErrResponsavelDoAluno se o responsável não for dele e ErrConsentimentoAtivo se já houver um ativo.
+func RegistrarConsentimento(ctx context.Context, db *sql.DB, tenantID, estudanteID, autorID int, in ConsentimentoRequest) (Consentimento, error) {
+	c := Consentimento{EstudanteID: estudanteID, ResponsavelID: in.ResponsavelID, Finalidade: in.Finalidade,
+		Observacao: in.Observacao, Ativo: true, RegistradoPor: autorID}
+	if in.ResponsavelID > 0 {
+		err := db.QueryRowContext(ctx,
+			`SELECT nome FROM responsaveis WHERE id=$1 AND estudante_id=$2 AND usuario_id=$3`,
+			in.ResponsavelID, estudanteID, tenantID,
+		).Scan(&c.Responsavel)
+		if err == sql.ErrNoRows {
+			return Consentimento{}, ErrResponsavelDoAluno
+		}
+		if err != nil {
+			return Consentimento{}, err
+		}
+	}
+	err := db.QueryRowContext(ctx, `
+		INSERT INTO consentimentos (usuario_id, estudante_id, responsavel_id, finalidade, observacao, registrado_por)
+		VALUES ($1, $2, NULLIF($3, 0), $4, $5, NULLIF($6, 0))
+		RETURNING id, concedido_em
+	`, tenantID, estudanteID, in.ResponsavelID, in.Finalidade, in.Observacao, autorID).Scan(&c.ID, &c.ConcedidoEm)
+	if e, ok := ComoErroBanco(err); ok && e.Codigo == SQLStateUnicidade {
+		return Consentimento{}, ErrConsentimentoAtivo
+	}
+	if err != nil {
+		return Consentimento{}, err
+	}
+	return c, nil
+}
+
+// RevogarConsentimento marca o consentimento ativo id do estudante como revogado
+// (sql.ErrNoRows se não existir ou já estiver revogado).
+func RevogarConsentimento(ctx context.Context, db *sql.DB, tenantID, estudanteID int, id int64, autorID int) (Consentimento, error) {
+	row := db.QueryRowContext(ctx, `
+		WITH revogado AS (
+			UPDATE consentimentos
+			   SET revogado_em = NOW(), revogado_por = NULLIF($4, 0)
+			 WHERE id=$1 AND estudante_id=$2 AND usuario_id=$3 AND revogado_em IS NULL
+			RETURNING *
+		)
+		SELECT `+colunasConsentimento+`
+		  FROM revogado c
+		  LEFT JOIN responsaveis r ON r.id = c.responsavel_id
+	`, id, estudanteID, tenantID, autorID)
+	return scanConsentimento(row)
+}
+
+// ComConsentimento devolve, entre ids, os que têm consentimento ativo para a finalidade:
+// ids de responsáveis nas finalidades de contato, ids de estudantes em foto.
+func ComConsentimento(ctx context.Context, db *sql.DB, tenantID int, finalidade string, ids []int) (map[int]bool, error) {
+	out := make(map[int]bool)
+	if len(ids) == 0 {
+		return out, nil
+	}
+	coluna := "responsavel_id"
+	if finalidade == FinalidadeFoto {
+		coluna = "estudante_id"
+	}
+	rows, err := db.QueryContext(ctx, `
+		SELECT DISTINCT `+coluna+`
+		  FROM consentimentos
+		 WHERE usuario_id=$1 AND finalidade=$2 AND `+coluna+` = ANY($3) AND revogado_em IS NULL
+	`, tenantID, finalidade, Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		out[id] = true
+	}
+	return out, rows.Err()
+}
+
+/// ============ Funções Internas (helpers) ============
+
+// scanConsentimento lê uma linha de colunasConsentimento.
+func scanConsentimento(s interface{ Scan(...any) error }) (Consentimento, error) {
+	var c Consentimento
+	err := s.Scan(&c.ID, &c.EstudanteID, &c.ResponsavelID, &c.Responsavel, &c.Finalidade, &c.Observacao,
+		&c.ConcedidoEm, &c.RegistradoPor, &c.RevogadoEm, &c.RevogadoPor)
+	c.Ativo = c.RevogadoEm == nil
+	return c, err
+}
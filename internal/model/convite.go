@@ -0,0 +1,77 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/model/convite.go
+/// Responsabilidade: Convites por e-mail para entrar numa organização (token com validade e papel escolhido por quem convida).
+/// Dependências principais: crypto/rand, crypto/sha256, encoding/base64, encoding/hex, errors, strings, time.
+/// Pontos de atenção:
+/// - Só o hash SHA-256 do token é persistido; o token em claro existe apenas no e-mail enviado.
+/// - O convite só pode ser aceito pela conta com o mesmo e-mail convidado.
+/// - ConviteTTL define a validade padrão (7 dias); o payload de criação reaproveita MembroRequest (email + papel).
+*/
+
+package model
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// Convite representa um registro pendente de `organizacao_convites`.
+type Convite struct {
+	ID       int    `json:"id"`
+	Email    string `json:"email"`
+	Papel    string `json:"papel"`
+	ExpiraEm string `json:"expira_em"`
+}
+
+// AceitarConviteRequest é o payload de POST /api/organizacao/convites/aceitar.
+type AceitarConviteRequest struct {
+	Token string `json:"token"`
+}
+
+/// ============ Configurações & Constantes ============
+
+// ConviteTTL é a validade de um convite a partir da criação.
+const ConviteTTL = 7 * 24 * time.Hour
+
+var (
+	ErrConviteInvalido  = errors.New("convite inválido, expirado ou já utilizado")
+	ErrConviteEmail     = errors.New("convite emitido para outro e-mail")
+	ErrTokenObrigatorio = errors.New("token é obrigatório")
+)
+
+/// ============ Funções Públicas ============
+
+// NovoTokenConvite gera um token aleatório (URL-safe) e o hash a ser gravado.
+func NovoTokenConvite() (token, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err = rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token = base64.RawURLEncoding.EncodeToString(b)
+	return token, HashTokenConvite(token), nil
+}
+
+// HashTokenConvite devolve o SHA-256 (hex) do token.
+func HashTokenConvite(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Sanitize faz trim do token.
+func (a *AceitarConviteRequest) Sanitize() { a.Token = strings.TrimSpace(a.Token) }
+
+// Validate exige token.
+func (a AceitarConviteRequest) Validate() error {
+	if a.Token == "" {
+		return ErrTokenObrigatorio
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/model/driver.go
+/// Responsabilidade: Ponto único de dependência do driver Postgres: nome do driver no database/sql, arrays como parâmetro/destino e leitura estruturada dos erros do banco (SQLSTATE e constraint).
+/// Dependências principais: database/sql, database/sql/driver, errors, github.com/jackc/pgx/v5 (stdlib, pgconn, pgtype).
+/// Pontos de atenção:
+/// - Nenhum outro pacote importa o pgx: handlers e repositórios usam Array, ComoErroBanco e as constantes de SQLSTATE daqui.
+/// - O import de pgx/v5/stdlib registra o driver "pgx" no database/sql; o pool continua sendo o do *sql.DB (DB_MAX_*).
+/// - Parâmetros: o pgx codifica slices Go como arrays Postgres sem conversão; Scan de array passa pelo pgtype (texto "{a,b}" → slice).
+/// - Erros comparados por SQLSTATE (errors.As), nunca pela mensagem: funcionam embrulhados com %w.
+*/
+
+package model
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	_ "github.com/jackc/pgx/v5/stdlib" // registra o driver "pgx"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// ErroBanco é a parte estruturada de um erro devolvido pelo Postgres.
+type ErroBanco struct {
+	Codigo    string // SQLSTATE (ex.: "23505")
+	Restricao string // constraint violada, quando houver
+	Tabela    string
+	Detalhe   string // DETAIL do Postgres (ex.: "Key (email)=(...) already exists."); pode conter dados
+}
+
+// ArraySQL é um array Postgres usável como parâmetro ou destino de Scan.
+type ArraySQL interface {
+	driver.Valuer
+	sql.Scanner
+}
+
+// arrayPgx implementa ArraySQL sobre um slice (parâmetro) ou ponteiro para slice (Scan).
+type arrayPgx struct{ v any }
+
+/// ============ Configurações & Constantes ============
+
+// DriverSQL é o nome registrado no database/sql (sql.Open).
+const DriverSQL = "pgx"
+
+// SQLSTATE usados pela aplicação.
+const (
+	SQLStateUnicidade        = "23505" // unique_violation
+	SQLStateChaveEstrangeira = "23503" // foreign_key_violation
+	SQLStateSerializacao     = "40001" // serialization_failure
+	SQLStateDeadlock         = "40P01" // deadlock_detected
+	SQLStateCancelada        = "57014" // query_canceled (inclui statement_timeout)
+)
+
+/// ============ Funções Públicas ============
+
+// Array embrulha um slice (ou ponteiro para slice, no Scan) como array Postgres.
+func Array(a any) ArraySQL {
+	return arrayPgx{v: a}
+}
+
+// Value entrega o slice como está: o pgx (CheckNamedValue aceita qualquer tipo)
+// codifica pelo tipo do parâmetro na consulta (text[], int4[]...).
+func (a arrayPgx) Value() (driver.Value, error) {
+	return a.v, nil
+}
+
+// Scan lê o array (o stdlib do pgx entrega arrays no formato texto) para o ponteiro.
+func (a arrayPgx) Scan(src any) error {
+	return pgtype.NewMap().SQLScanner(a.v).Scan(src)
+}
+
+// ComoErroBanco extrai SQLSTATE e constraint de err (também quando embrulhado).
+func ComoErroBanco(err error) (ErroBanco, bool) {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return ErroBanco{}, false
+	}
+	return ErroBanco{Codigo: pgErr.Code, Restricao: pgErr.ConstraintName, Tabela: pgErr.TableName, Detalhe: pgErr.Detail}, true
+}
+
+// ClasseSQLState devolve os dois primeiros caracteres (classe) de um SQLSTATE.
+func ClasseSQLState(codigo string) string {
+	if len(codigo) < 2 {
+		return ""
+	}
+	return codigo[:2]
+}
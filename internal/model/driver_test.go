@@ -0,0 +1,58 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestArrayParametro(t *testing.T) {
+	// o pgx chama Value e codifica o slice pelo tipo do parâmetro
+	buf, err := pgtype.NewMap().Encode(pgtype.Int4ArrayOID, pgtype.TextFormatCode, Array([]int{3, 1, 2}), nil)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if string(buf) != "{3,1,2}" {
+		t.Errorf("int4[] = %q", buf)
+	}
+	buf, err = pgtype.NewMap().Encode(pgtype.TextArrayOID, pgtype.TextFormatCode, Array([]string{"ativo", "formado"}), nil)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if string(buf) != "{ativo,formado}" {
+		t.Errorf("text[] = %q", buf)
+	}
+}
+
+func TestArrayScan(t *testing.T) {
+	// o stdlib do pgx entrega text[] como string no formato texto
+	var eventos []string
+	if err := Array(&eventos).Scan(`{estudante.criado,"com espaço"}`); err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if !slices.Equal(eventos, []string{"estudante.criado", "com espaço"}) {
+		t.Errorf("eventos = %q", eventos)
+	}
+	if err := Array(&eventos).Scan(nil); err != nil || eventos != nil {
+		t.Errorf("NULL: eventos = %q, err = %v", eventos, err)
+	}
+}
+
+func TestComoErroBanco(t *testing.T) {
+	pgErr := &pgconn.PgError{Code: SQLStateUnicidade, ConstraintName: "usuarios_email_key", TableName: "usuarios", Detail: "Key (email)=(a@b.c) already exists."}
+	e, ok := ComoErroBanco(fmt.Errorf("criar usuário: %w", pgErr))
+	if !ok {
+		t.Fatal("erro embrulhado não reconhecido")
+	}
+	want := ErroBanco{Codigo: "23505", Restricao: "usuarios_email_key", Tabela: "usuarios", Detalhe: pgErr.Detail}
+	if e != want {
+		t.Errorf("ErroBanco = %+v, esperado %+v", e, want)
+	}
+	if _, ok := ComoErroBanco(errors.New("outro erro")); ok {
+		t.Error("erro comum reconhecido como erro do banco")
+	}
+}
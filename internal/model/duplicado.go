@@ -0,0 +1,232 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/model/duplicado.go
+/// Responsabilidade: Detecção de prováveis estudantes duplicados (similaridade de nome por trigramas, mesma data de nascimento, mesmo telefone).
+/// Dependências principais: errors, sort, strings, unicode.
+/// Pontos de atenção:
+/// - Cálculo feito em memória (O(n²) pares); adequado para o volume de um usuário, não para a base inteira.
+/// - Nome é normalizado (minúsculas, sem acentos/pontuação) antes dos trigramas; similaridade = Jaccard dos conjuntos.
+/// - Confiança combina os sinais com pesos fixos (nome 0.6, data 0.2, telefone 0.2); grupos são componentes conexos dos pares acima do limiar.
+/// - MesclarEstudantesRequest é o payload de POST /api/estudantes/merge (o secundário é absorvido pelo principal).
+*/
+
+package model
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// GrupoDuplicado agrupa estudantes que provavelmente são o mesmo aluno.
+type GrupoDuplicado struct {
+	Confianca  float64     `json:"confianca"` // 0–1, maior par do grupo
+	Motivos    []string    `json:"motivos"`   // nome | data_nascimento | telefone
+	Estudantes []Estudante `json:"estudantes"`
+}
+
+// MesclarEstudantesRequest indica qual registro sobrevive (principal) e qual é absorvido.
+type MesclarEstudantesRequest struct {
+	PrincipalID  int `json:"principal_id"`
+	SecundarioID int `json:"secundario_id"`
+}
+
+/// ============ Configurações & Constantes ============
+
+const (
+	pesoNome     = 0.6
+	pesoData     = 0.2
+	pesoTelefone = 0.2
+
+	// LimiarDuplicadoPadrao é a confiança mínima de um par para entrar no resultado.
+	LimiarDuplicadoPadrao = 0.5
+)
+
+var (
+	ErrMesclarIDs     = errors.New("principal_id e secundario_id são obrigatórios")
+	ErrMesclarMesmoID = errors.New("principal_id e secundario_id devem ser diferentes")
+)
+
+// acentos mapeia letras acentuadas comuns em nomes brasileiros para a forma base.
+var acentos = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+)
+
+/// ============ Funções Internas (helpers) ============
+
+// normalizarNome deixa o nome em minúsculas, sem acentos e com espaços simples.
+func normalizarNome(s string) string {
+	s = acentos.Replace(strings.ToLower(s))
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return ' '
+	}, s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// trigramas gera o conjunto de trigramas por palavra (com padding, como no pg_trgm).
+func trigramas(s string) map[string]struct{} {
+	out := map[string]struct{}{}
+	for _, palavra := range strings.Fields(s) {
+		r := []rune("  " + palavra + " ")
+		for i := 0; i+3 <= len(r); i++ {
+			out[string(r[i:i+3])] = struct{}{}
+		}
+	}
+	return out
+}
+
+// mesmaData compara apenas a parte YYYY-MM-DD (o driver pode devolver timestamp completo).
+func mesmaData(a, b string) bool {
+	if len(a) < 10 || len(b) < 10 {
+		return false
+	}
+	return a[:10] == b[:10]
+}
+
+// mesmoTelefone compara os últimos 8 dígitos (ignora DDI/DDD e formatação).
+func mesmoTelefone(a, b string) bool {
+	a, b = digitsOnly(a), digitsOnly(b)
+	if len(a) < 8 || len(b) < 8 {
+		return false
+	}
+	return a[len(a)-8:] == b[len(b)-8:]
+}
+
+// contem informa se s está na lista.
+func contem(lista []string, s string) bool {
+	for _, v := range lista {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+/// ============ Funções Públicas ============
+
+// SimilaridadeNome retorna a similaridade (0–1) entre dois nomes via trigramas.
+func SimilaridadeNome(a, b string) float64 {
+	ta, tb := trigramas(normalizarNome(a)), trigramas(normalizarNome(b))
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+	comuns := 0
+	for t := range ta {
+		if _, ok := tb[t]; ok {
+			comuns++
+		}
+	}
+	return float64(comuns) / float64(len(ta)+len(tb)-comuns)
+}
+
+// Validate exige os dois ids, positivos e distintos.
+func (m MesclarEstudantesRequest) Validate() error {
+	if m.PrincipalID <= 0 || m.SecundarioID <= 0 {
+		return ErrMesclarIDs
+	}
+	if m.PrincipalID == m.SecundarioID {
+		return ErrMesclarMesmoID
+	}
+	return nil
+}
+
+// DetectarDuplicados compara todos os pares e devolve os grupos com confiança >= limiar,
+// ordenados da maior para a menor confiança.
+func DetectarDuplicados(estudantes []Estudante, limiar float64) []GrupoDuplicado {
+	n := len(estudantes)
+	pai := make([]int, n)
+	for i := range pai {
+		pai[i] = i
+	}
+	var raiz func(int) int
+	raiz = func(i int) int {
+		if pai[i] != i {
+			pai[i] = raiz(pai[i])
+		}
+		return pai[i]
+	}
+
+	confianca := make([]float64, n)
+	motivos := make([]map[string]bool, n)
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			a, b := estudantes[i], estudantes[j]
+			sim := SimilaridadeNome(a.Nome, b.Nome)
+			score := pesoNome * sim
+			var m []string
+			if sim >= 0.5 {
+				m = append(m, "nome")
+			}
+			if mesmaData(a.DataNascimento, b.DataNascimento) {
+				score += pesoData
+				m = append(m, "data_nascimento")
+			}
+			if mesmoTelefone(a.Telefone, b.Telefone) {
+				score += pesoTelefone
+				m = append(m, "telefone")
+			}
+			if score < limiar {
+				continue
+			}
+			ri, rj := raiz(i), raiz(j)
+			if ri != rj {
+				pai[rj] = ri
+			}
+			for _, k := range []int{i, j} {
+				if score > confianca[k] {
+					confianca[k] = score
+				}
+				if motivos[k] == nil {
+					motivos[k] = map[string]bool{}
+				}
+				for _, x := range m {
+					motivos[k][x] = true
+				}
+			}
+		}
+	}
+
+	porRaiz := map[int]*GrupoDuplicado{}
+	var ordem []int
+	for i := 0; i < n; i++ {
+		if motivos[i] == nil {
+			continue
+		}
+		r := raiz(i)
+		g, ok := porRaiz[r]
+		if !ok {
+			g = &GrupoDuplicado{Motivos: []string{}}
+			porRaiz[r] = g
+			ordem = append(ordem, r)
+		}
+		g.Estudantes = append(g.Estudantes, estudantes[i])
+		if confianca[i] > g.Confianca {
+			g.Confianca = confianca[i]
+		}
+		for _, x := range []string{"nome", "data_nascimento", "telefone"} {
+			if motivos[i][x] && !contem(g.Motivos, x) {
+				g.Motivos = append(g.Motivos, x)
+			}
+		}
+	}
+
+	out := make([]GrupoDuplicado, 0, len(ordem))
+	for _, r := range ordem {
+		g := porRaiz[r]
+		g.Confianca = float64(int(g.Confianca*100+0.5)) / 100
+		out = append(out, *g)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Confianca > out[j].Confianca })
+	return out
+}
@@ -0,0 +1,95 @@
+package model
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestSimilaridadeNome(t *testing.T) {
+	casos := []struct {
+		a, b string
+		sim  float64
+	}{
+		{"João Conceição", "Joao Conceicao", 1},  // acentos
+		{"Maria Silva", "Silva Maria", 1},        // ordem das palavras
+		{"ANA  SOUZA!", "ana souza", 1},          // caixa, pontuação e espaços
+		{"Ana Souza", "Ana Souza Lima", 2.0 / 3}, // sobrenome a mais
+		{"Ana Souza", "Ana Sousa", 7.0 / 13},     // grafia diferente
+		{"Ana", "Bruno", 0},
+		{"", "Ana", 0},
+		{"", "", 0},
+	}
+	for _, c := range casos {
+		got := SimilaridadeNome(c.a, c.b)
+		if math.Abs(got-c.sim) > 1e-9 {
+			t.Errorf("SimilaridadeNome(%q, %q) = %v, esperado %v", c.a, c.b, got, c.sim)
+		}
+		if inv := SimilaridadeNome(c.b, c.a); inv != got {
+			t.Errorf("SimilaridadeNome não é simétrica para %q/%q: %v x %v", c.a, c.b, got, inv)
+		}
+	}
+}
+
+func TestDetectarDuplicados(t *testing.T) {
+	ana := Estudante{ID: 1, Nome: "Ana Souza", DataNascimento: "2012-03-04", Telefone: "(11) 99999-0000"}
+	anaSemAcento := Estudante{ID: 2, Nome: "ana souza", DataNascimento: "2012-03-04T00:00:00Z"}
+	bruno := Estudante{ID: 3, Nome: "Bruno Lima", DataNascimento: "2011-11-30", Telefone: "+55 11 99999-0000"}
+	carla := Estudante{ID: 4, Nome: "Carla Dias", DataNascimento: "2011-11-30"}
+	davi := Estudante{ID: 5, Nome: "Davi Rocha", DataNascimento: "2010-01-01", Telefone: "21 98888-7777"}
+	eli := Estudante{ID: 6, Nome: "Eli Gomes", Telefone: "98888-7777"}
+
+	casos := []struct {
+		nome       string
+		estudantes []Estudante
+		limiar     float64
+		grupos     [][]int // ids por grupo, na ordem do resultado
+		confianca  []float64
+	}{
+		{"mesmo nome e data", []Estudante{ana, anaSemAcento, davi}, LimiarDuplicadoPadrao, [][]int{{1, 2}}, []float64{0.8}},
+		{"só telefone fica abaixo do padrão", []Estudante{ana, bruno}, LimiarDuplicadoPadrao, nil, nil},
+		// bruno e carla só têm a data em comum: par de 0.2
+		{"limiar exato entra", []Estudante{bruno, carla}, 0.2, [][]int{{3, 4}}, []float64{0.2}},
+		{"limiar acima não entra", []Estudante{bruno, carla}, 0.21, nil, nil},
+		// ana~bruno (telefone) e bruno~carla (data): carla entra pelo bruno, sem par direto com ana
+		{"grupo transitivo", []Estudante{ana, bruno, carla, davi}, 0.2, [][]int{{1, 3, 4}}, []float64{0.2}},
+		{"grupo fica com a maior confiança", []Estudante{bruno, ana, carla, anaSemAcento}, 0.2, [][]int{{3, 1, 4, 2}}, []float64{0.8}},
+		{"grupos ordenados por confiança", []Estudante{davi, eli, ana, anaSemAcento}, 0.2, [][]int{{1, 2}, {5, 6}}, []float64{0.8, 0.2}},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			grupos := DetectarDuplicados(c.estudantes, c.limiar)
+			if len(grupos) != len(c.grupos) {
+				t.Fatalf("grupos = %+v, esperados %v", grupos, c.grupos)
+			}
+			for i, g := range grupos {
+				var ids []int
+				for _, e := range g.Estudantes {
+					ids = append(ids, e.ID)
+				}
+				if !slices.Equal(ids, c.grupos[i]) {
+					t.Errorf("grupo %d = %v, esperado %v", i, ids, c.grupos[i])
+				}
+				if g.Confianca != c.confianca[i] {
+					t.Errorf("grupo %d: confiança = %v, esperada %v", i, g.Confianca, c.confianca[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDetectarDuplicadosMotivos(t *testing.T) {
+	grupos := DetectarDuplicados([]Estudante{
+		{ID: 1, Nome: "José Conceição", DataNascimento: "2012-03-04", Telefone: "11 99999-0000"},
+		{ID: 2, Nome: "Conceicao Jose", DataNascimento: "2012-03-04", Telefone: "99999-0000"},
+	}, LimiarDuplicadoPadrao)
+	if len(grupos) != 1 {
+		t.Fatalf("grupos = %+v", grupos)
+	}
+	if want := []string{"nome", "data_nascimento", "telefone"}; !slices.Equal(grupos[0].Motivos, want) {
+		t.Errorf("motivos = %v, esperados %v", grupos[0].Motivos, want)
+	}
+	if grupos[0].Confianca != 1 {
+		t.Errorf("confiança = %v, esperada 1", grupos[0].Confianca)
+	}
+}
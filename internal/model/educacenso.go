@@ -0,0 +1,216 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/model/educacenso.go
+/// Responsabilidade: Arquivo de migração do Educacenso (INEP) com os estudantes ativos e validação prévia dos campos obrigatórios.
+/// Dependências principais: errors, fmt, io, sort, strings, time, unicode.
+/// Pontos de atenção:
+/// - Registros delimitados por "|", um por linha: 20 (turma = ano), 30 (pessoa física) e 60 (vínculo aluno–turma).
+/// - Só saem os campos que o Tecmise guarda (até filiação 2 no registro 30); sexo, cor/raça, nacionalidade etc. são completados no sistema do Educacenso.
+/// - Textos vão em maiúsculas sem acentos (o Educacenso rejeita caracteres especiais em nomes).
+/// - Filiação 1 = responsável com parentesco "mae"; filiação 2 = "pai"; sem nenhum, filiação "0" (não declarado).
+*/
+
+package model
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// AlunoEducacenso reúne o que o arquivo precisa de um estudante ativo.
+type AlunoEducacenso struct {
+	Estudante
+	Turma     string // nome do ano/turma (anos.nome)
+	Filiacao1 string // mãe
+	Filiacao2 string // pai
+}
+
+// PendenciaEducacenso lista os campos que impedem o estudante de entrar no arquivo.
+type PendenciaEducacenso struct {
+	EstudanteID int            `json:"estudante_id"`
+	Nome        string         `json:"nome"`
+	Erros       ErrosValidacao `json:"erros"`
+}
+
+// ValidacaoEducacenso é o resultado da validação prévia (?validar=true).
+type ValidacaoEducacenso struct {
+	Total      int                   `json:"total"`
+	Validos    int                   `json:"validos"`
+	Pendencias []PendenciaEducacenso `json:"pendencias"`
+}
+
+/// ============ Configurações & Constantes ============
+
+const (
+	registroTurma        = "20"
+	registroPessoa       = "30"
+	registroVinculo      = "60"
+	inepEscolaDigitos    = 8
+	dataLayoutEducacenso = "02/01/2006"
+)
+
+var (
+	ErrInepEscola         = errors.New("inep_escola deve ter 8 dígitos (código INEP da escola)")
+	ErrEducacensoNome     = errors.New("nome deve ter só letras e espaços (sem números ou símbolos)")
+	ErrEducacensoData     = errors.New("data_nascimento ausente, inválida ou no futuro")
+	ErrEducacensoCPF      = errors.New("cpf com dígitos verificadores inválidos")
+	ErrEducacensoTurma    = errors.New("estudante sem ano/turma")
+	ErrEducacensoFiliacao = errors.New("nome do responsável (filiação) com números ou símbolos")
+)
+
+/// ============ Funções Públicas ============
+
+// ValidarInepEscola confere o código INEP da escola (8 dígitos).
+func ValidarInepEscola(inep string) error {
+	if len(inep) != inepEscolaDigitos || digitsOnly(inep) != inep {
+		var ev ErrosValidacao
+		ev.Add("inep_escola", RegraFormato, ErrInepEscola)
+		return ev.Err()
+	}
+	return nil
+}
+
+// ValidarAlunoEducacenso devolve os campos obrigatórios ausentes/inválidos do estudante (nil = ok).
+func ValidarAlunoEducacenso(a AlunoEducacenso, hoje time.Time) error {
+	var ev ErrosValidacao
+	if !nomeEducacensoValido(a.Nome) {
+		ev.Add("nome", RegraFormato, ErrEducacensoNome)
+	}
+	if nasc, err := time.Parse(dateLayoutISO, a.DataNascimento); err != nil || nasc.After(hoje) {
+		ev.Add("data_nascimento", RegraObrigatorio, ErrEducacensoData)
+	}
+	if a.CPF != "" && !cpfValido(a.CPF) {
+		ev.Add("cpf", RegraFormato, ErrEducacensoCPF)
+	}
+	if a.AnoID == 0 || strings.TrimSpace(a.Turma) == "" {
+		ev.Add("ano_id", RegraObrigatorio, ErrEducacensoTurma)
+	}
+	for _, f := range []string{a.Filiacao1, a.Filiacao2} {
+		if f != "" && !nomeEducacensoValido(f) {
+			ev.Add("responsaveis", RegraFormato, ErrEducacensoFiliacao)
+			break
+		}
+	}
+	return ev.Err()
+}
+
+// ValidarEducacenso roda ValidarAlunoEducacenso em todos os alunos.
+func ValidarEducacenso(alunos []AlunoEducacenso, hoje time.Time) ValidacaoEducacenso {
+	out := ValidacaoEducacenso{Total: len(alunos), Pendencias: []PendenciaEducacenso{}}
+	for _, a := range alunos {
+		ev, ok := ComoErrosValidacao(ValidarAlunoEducacenso(a, hoje))
+		if !ok {
+			out.Validos++
+			continue
+		}
+		out.Pendencias = append(out.Pendencias, PendenciaEducacenso{EstudanteID: a.ID, Nome: a.Nome, Erros: ev})
+	}
+	return out
+}
+
+// Traduzir devolve a validação com as mensagens das pendências no idioma
+// (apierr.Traduzivel; ver ErrosValidacao.Traduzir).
+func (v ValidacaoEducacenso) Traduzir(idioma string) any {
+	out := v
+	out.Pendencias = make([]PendenciaEducacenso, len(v.Pendencias))
+	for i, p := range v.Pendencias {
+		p.Erros = p.Erros.Traduzir(idioma).(ErrosValidacao)
+		out.Pendencias[i] = p
+	}
+	return out
+}
+
+// EscreverEducacenso grava o arquivo de migração: os registros 20 das turmas
+// usadas e, por aluno, os registros 30 e 60. Os alunos devem estar validados.
+func EscreverEducacenso(w io.Writer, inep string, alunos []AlunoEducacenso) error {
+	turmas := map[int]string{}
+	for _, a := range alunos {
+		turmas[a.AnoID] = a.Turma
+	}
+	ids := make([]int, 0, len(turmas))
+	for id := range turmas {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		if err := escreverRegistro(w, registroTurma, inep, strconv.Itoa(id), "", textoEducacenso(turmas[id])); err != nil {
+			return err
+		}
+	}
+
+	for _, a := range alunos {
+		nasc, _ := time.Parse(dateLayoutISO, a.DataNascimento)
+		filiacao := "0"
+		if a.Filiacao1 != "" || a.Filiacao2 != "" {
+			filiacao = "1"
+		}
+		codigo := strconv.Itoa(a.ID)
+		if err := escreverRegistro(w, registroPessoa, inep, codigo, "", a.CPF, textoEducacenso(a.Nome),
+			nasc.Format(dataLayoutEducacenso), filiacao, textoEducacenso(a.Filiacao1), textoEducacenso(a.Filiacao2)); err != nil {
+			return err
+		}
+		if err := escreverRegistro(w, registroVinculo, inep, codigo, "", strconv.Itoa(a.AnoID)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/// ============ Funções Internas (helpers) ============
+
+// escreverRegistro grava uma linha com os campos separados por "|".
+func escreverRegistro(w io.Writer, campos ...string) error {
+	_, err := fmt.Fprintln(w, strings.Join(campos, "|"))
+	return err
+}
+
+// textoEducacenso deixa o texto em maiúsculas ASCII (sem acentos; "º", "|" etc. caem) e com espaços simples.
+func textoEducacenso(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if r > unicode.MaxASCII || r == '|' {
+			return -1
+		}
+		return r
+	}, acentos.Replace(strings.ToLower(s)))
+	return strings.ToUpper(strings.Join(strings.Fields(s), " "))
+}
+
+// nomeEducacensoValido aceita apenas letras (acentuadas ou não) e espaços.
+func nomeEducacensoValido(nome string) bool {
+	nome = acentos.Replace(strings.ToLower(strings.TrimSpace(nome)))
+	if nome == "" {
+		return false
+	}
+	for _, r := range nome {
+		if r != ' ' && (r < 'a' || r > 'z') {
+			return false
+		}
+	}
+	return true
+}
+
+// cpfValido confere os dois dígitos verificadores (e rejeita sequências repetidas).
+func cpfValido(cpf string) bool {
+	if len(cpf) != cpfDigitsRequired || strings.Count(cpf, cpf[:1]) == len(cpf) {
+		return false
+	}
+	for _, n := range []int{9, 10} {
+		soma := 0
+		for i := 0; i < n; i++ {
+			soma += int(cpf[i]-'0') * (n + 1 - i)
+		}
+		dv := soma * 10 % 11 % 10
+		if dv != int(cpf[n]-'0') {
+			return false
+		}
+	}
+	return true
+}
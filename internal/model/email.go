@@ -0,0 +1,44 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/model/email.go
+/// Responsabilidade: Normalização única do e-mail de contas (X-User-Email, cadastro, login, login Google, CLI) antes de gravar ou comparar com usuarios.email.
+/// Dependências principais: strings, sync/atomic.
+/// Pontos de atenção:
+/// - usuarios.email é CITEXT (migrations/0013_email_citext.sql): o banco já compara sem diferenciar caixa; o valor gravado sai normalizado daqui.
+/// - E-mails de estudantes/responsáveis não são contas: continuam só com trim + minúsculas nos próprios Sanitize.
+/// - Gmail canônico (EMAIL_GMAIL_CANONICAL) remove pontos e o sufixo "+tag" da parte local de gmail.com/googlemail.com. Contas gravadas antes de ligar a opção continuam com o e-mail antigo e deixam de casar: ligue só em bases novas (ou normalize os registros antes).
+*/
+
+package model
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+/// ============ Configurações & Constantes ============
+
+// gmailCanonico liga a forma canônica dos endereços Gmail (ConfigurarEmail).
+var gmailCanonico atomic.Bool
+
+/// ============ Inicialização/Bootstrap ============
+
+// ConfigurarEmail define, na subida, se endereços Gmail são comparados na forma canônica.
+func ConfigurarEmail(canonicoGmail bool) { gmailCanonico.Store(canonicoGmail) }
+
+/// ============ Funções Públicas ============
+
+// NormalizarEmail devolve o e-mail de conta como ele é gravado e comparado: sem espaços
+// nas pontas, em minúsculas e, com Gmail canônico, sem pontos/+tag na parte local.
+func NormalizarEmail(email string) string {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if !gmailCanonico.Load() {
+		return email
+	}
+	local, dominio, ok := strings.Cut(email, "@")
+	if !ok || (dominio != "gmail.com" && dominio != "googlemail.com") {
+		return email
+	}
+	local, _, _ = strings.Cut(local, "+")
+	return strings.ReplaceAll(local, ".", "") + "@gmail.com"
+}
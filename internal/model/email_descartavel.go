@@ -0,0 +1,84 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/model/email_descartavel.go
+/// Responsabilidade: Recusar e-mails de contas em domínios descartáveis (lista embutida + EMAIL_BLOCKED_DOMAINS).
+/// Dependências principais: embed, errors, strings, sync, sync/atomic.
+/// Pontos de atenção:
+/// - ValidarDominioEmail é o validador único dos fluxos que gravam o e-mail de uma conta (hoje, o cadastro em RegisterRequest.Validate); login e login Google não passam por aqui, para não trancar contas já existentes.
+/// - Subdomínios de um domínio bloqueado também são recusados (x.mailinator.com).
+/// - A lista embutida (dominios_descartaveis.txt) cobre os provedores mais comuns, não todos; EMAIL_BLOCKED_DOMAINS acrescenta domínios sem recompilar.
+*/
+
+package model
+
+import (
+	_ "embed"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+/// ============ Configurações & Constantes ============
+
+//go:embed dominios_descartaveis.txt
+var listaDescartaveis string
+
+// dominiosBloqueados é o conjunto em uso (ConfigurarDominiosBloqueados); nil = lista embutida.
+var dominiosBloqueados atomic.Pointer[map[string]bool]
+
+var ErrEmailDescartavel = errors.New("domínio de e-mail não aceito (descartável ou bloqueado)")
+
+/// ============ Inicialização/Bootstrap ============
+
+// ConfigurarDominiosBloqueados define, na subida, os domínios recusados: a lista
+// embutida (se descartaveis) mais extras (EMAIL_BLOCKED_DOMAINS).
+func ConfigurarDominiosBloqueados(descartaveis bool, extras []string) {
+	m := map[string]bool{}
+	if descartaveis {
+		for d := range listaEmbutida() {
+			m[d] = true
+		}
+	}
+	for _, d := range extras {
+		if d = strings.ToLower(strings.Trim(strings.TrimSpace(d), ".")); d != "" {
+			m[d] = true
+		}
+	}
+	dominiosBloqueados.Store(&m)
+}
+
+/// ============ Funções Públicas ============
+
+// ValidarDominioEmail devolve ErrEmailDescartavel se o domínio de email (ou um domínio
+// pai dele) estiver bloqueado. E-mail sem "@" passa: o formato é conferido à parte.
+func ValidarDominioEmail(email string) error {
+	_, dominio, ok := strings.Cut(strings.ToLower(strings.TrimSpace(email)), "@")
+	if !ok {
+		return nil
+	}
+	bloqueados := listaEmbutida()
+	if p := dominiosBloqueados.Load(); p != nil {
+		bloqueados = *p
+	}
+	for dominio != "" {
+		if bloqueados[dominio] {
+			return ErrEmailDescartavel
+		}
+		_, dominio, _ = strings.Cut(dominio, ".")
+	}
+	return nil
+}
+
+/// ============ Funções Internas (helpers) ============
+
+// listaEmbutida lê dominios_descartaveis.txt (ignora linhas vazias e comentários).
+var listaEmbutida = sync.OnceValue(func() map[string]bool {
+	m := map[string]bool{}
+	for _, linha := range strings.Split(listaDescartaveis, "\n") {
+		if linha = strings.TrimSpace(linha); linha != "" && !strings.HasPrefix(linha, "#") {
+			m[strings.ToLower(linha)] = true
+		}
+	}
+	return m
+})
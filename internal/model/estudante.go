@@ -1,9 +1,10 @@
 /*
 /// Projeto: Tecmise
-/// Arquivo: backend/model/estudante.go
+/// Arquivo: backend/internal/model/estudante.go
 /// Responsabilidade: Definir modelo e DTOs de Estudante com rotinas de saneamento e validação leves (compatíveis com o contrato JSON do frontend).
 /// Dependências principais: time (parse ISO date), net/mail (validação básica de e-mail), unicode/strings (saneamento).
 /// Pontos de atenção:
+/// - Validate devolve ErrosValidacao (model/validacao.go) com todos os campos inválidos, não só o primeiro.
 /// - CPF: valida apenas quantidade de dígitos (11). Não executa validação de dígitos verificadores (DV).
 /// - Data de nascimento: aceita formato ISO (YYYY-MM-DD) via time.Parse; não verifica coerência (ex.: datas futuras).
 /// - E-mail: usa mail.ParseAddress (permissivo) e não restringe provedores.
@@ -13,7 +14,7 @@
 */
 
 //
-// backend/model/estudante.go
+// backend/internal/model/estudante.go
 //
 // 🔹 Objetivo:
 // Definir os DTOs e o modelo de Estudante, com funções de saneamento e
@@ -41,16 +42,31 @@ import (
 // Estudante representa o registro persistido e também o payload de resposta
 // exposto pela API. As tags JSON são contratuais com o frontend.
 type Estudante struct {
-	ID             int    `json:"id"`              // Identificador único do estudante
-	Nome           string `json:"nome"`            // Nome completo
-	CPF            string `json:"cpf"`             // CPF (documento nacional)
-	Email          string `json:"email"`           // E-mail válido
-	DataNascimento string `json:"data_nascimento"` // Data de nascimento (ISO 8601: YYYY-MM-DD)
-	Telefone       string `json:"telefone"`        // Número de telefone
-	FotoURL        string `json:"foto_url"`        // Foto de perfil do aluno
-	AnoID          int    `json:"ano_id"`          // Relacionamento com tabela de anos
-	TurmaID        int    `json:"turma_id"`        // Relacionamento com tabela de turmas
-	UsuarioID      int    `json:"usuario_id"`      // Usuário dono do registro
+	ID             int    `json:"id"`               // Identificador único do estudante
+	Nome           string `json:"nome"`             // Nome completo
+	CPF            string `json:"cpf"`              // CPF (documento nacional)
+	Email          string `json:"email"`            // E-mail válido
+	DataNascimento string `json:"data_nascimento"`  // Data de nascimento (ISO 8601: YYYY-MM-DD)
+	Telefone       string `json:"telefone"`         // Número de telefone
+	FotoURL        string `json:"foto_url"`         // Foto de perfil do aluno
+	AnoID          int    `json:"ano_id"`           // Relacionamento com tabela de anos
+	TurmaID        int    `json:"turma_id"`         // Relacionamento com tabela de turmas
+	UsuarioID      int    `json:"usuario_id"`       // Usuário dono do registro
+	Status         string `json:"status,omitempty"` // ativo | transferido | formado
+	Versao         int    `json:"versao"`           // incrementada a cada alteração (ETag / If-Match)
+	AnoNome        string `json:"-"`                // anos.nome de AnoID (leituras do EstudanteRepo; ver EstudanteResposta)
+	TurmaNome      string `json:"-"`                // anos.nome de TurmaID
+}
+
+// EstudanteResposta é o estudante como sai nas leituras da API (GET /api/estudantes
+// e /{id}): o registro mais campos derivados calculados no servidor, para o
+// frontend não repetir a conta.
+type EstudanteResposta struct {
+	Estudante
+	Idade     *int   `json:"idade"`      // anos completos hoje; null sem data de nascimento válida
+	Iniciais  string `json:"iniciais"`   // primeira letra do primeiro e do último nome (avatar sem foto)
+	AnoNome   string `json:"ano_nome"`   // nome do ano (vazio sem ano_id)
+	TurmaNome string `json:"turma_nome"` // nome da turma (vazio sem turma_id)
 }
 
 /// ============ DTOs (criação/atualização) ============
@@ -119,6 +135,46 @@ func digitsOnly(s string) string {
 	}, s)
 }
 
+// idadeEm calcula os anos completos em hoje de uma data ISO (aceita o prefixo
+// YYYY-MM-DD de um timestamp). nil se a data for inválida ou futura.
+func idadeEm(data string, hoje time.Time) *int {
+	if len(data) > len(dateLayoutISO) {
+		data = data[:len(dateLayoutISO)]
+	}
+	nasc, err := time.Parse(dateLayoutISO, data)
+	if err != nil {
+		return nil
+	}
+	idade := hoje.Year() - nasc.Year()
+	if hoje.Month() < nasc.Month() || (hoje.Month() == nasc.Month() && hoje.Day() < nasc.Day()) {
+		idade--
+	}
+	if idade < 0 {
+		return nil
+	}
+	return &idade
+}
+
+// iniciais devolve a primeira letra do primeiro e do último nome, em maiúsculas
+// ("Ana Maria da Silva" → "AS"; nome único → uma letra).
+func iniciais(nome string) string {
+	partes := strings.Fields(nome)
+	if len(partes) == 0 {
+		return ""
+	}
+	primeira := func(s string) string {
+		for _, r := range s {
+			return string(unicode.ToUpper(r))
+		}
+		return ""
+	}
+	out := primeira(partes[0])
+	if len(partes) > 1 {
+		out += primeira(partes[len(partes)-1])
+	}
+	return out
+}
+
 // isValidISODate verifica se a string representa uma data válida no layout ISO (YYYY-MM-DD).
 func isValidISODate(s string) bool {
 	if len(strings.TrimSpace(s)) == 0 {
@@ -130,6 +186,17 @@ func isValidISODate(s string) bool {
 
 /// ============ Funções Públicas ============
 
+// NovaEstudanteResposta monta a resposta de e com a idade calculada em hoje.
+func NovaEstudanteResposta(e Estudante, hoje time.Time) EstudanteResposta {
+	return EstudanteResposta{
+		Estudante: e,
+		Idade:     idadeEm(e.DataNascimento, hoje),
+		Iniciais:  iniciais(e.Nome),
+		AnoNome:   e.AnoNome,
+		TurmaNome: e.TurmaNome,
+	}
+}
+
 // --- Create: Sanitize/Validate ---
 
 // Sanitize padroniza espaços e caixa dos campos de criação:
@@ -145,25 +212,27 @@ func (r *EstudanteCreateRequest) Sanitize() {
 	r.FotoURL = strings.TrimSpace(r.FotoURL)
 }
 
-// Validate executa verificações mínimas de negócio para criação:
+// Validate executa verificações mínimas de negócio para criação e devolve
+// todas as violações de uma vez (ErrosValidacao):
 // - Nome obrigatório
 // - CPF com 11 dígitos
 // - E-mail válido (mail.ParseAddress)
 // - Data de nascimento em formato ISO
 func (r EstudanteCreateRequest) Validate() error {
+	var ev ErrosValidacao
 	if strings.TrimSpace(r.Nome) == "" {
-		return ErrNomeObrigatorio
+		ev.Add("nome", RegraObrigatorio, ErrNomeObrigatorio)
 	}
 	if len(digitsOnly(r.CPF)) != cpfDigitsRequired {
-		return ErrCPFInvalido
+		ev.Add("cpf", RegraFormato, ErrCPFInvalido)
 	}
 	if _, err := mail.ParseAddress(r.Email); err != nil {
-		return ErrEmailInvalido
+		ev.Add("email", RegraFormato, ErrEmailInvalido)
 	}
 	if !isValidISODate(r.DataNascimento) {
-		return ErrDataNascimentoInvalida
+		ev.Add("data_nascimento", RegraFormato, ErrDataNascimentoInvalida)
 	}
-	return nil
+	return ev.Err()
 }
 
 // --- Update: Sanitize/Validate (só valida o que vier no payload) ---
@@ -199,23 +268,24 @@ func (r *EstudanteUpdateRequest) Sanitize() {
 }
 
 // Validate verifica os campos informados (não-nil) no payload parcial de update.
-// Mantém as mesmas regras do create onde aplicável.
+// Mantém as mesmas regras do create onde aplicável, acumulando as violações.
 func (r EstudanteUpdateRequest) Validate() error {
+	var ev ErrosValidacao
 	if r.Nome != nil && strings.TrimSpace(*r.Nome) == "" {
-		return ErrNomeObrigatorio
+		ev.Add("nome", RegraObrigatorio, ErrNomeObrigatorio)
 	}
 	if r.CPF != nil && len(digitsOnly(*r.CPF)) != cpfDigitsRequired {
-		return ErrCPFInvalido
+		ev.Add("cpf", RegraFormato, ErrCPFInvalido)
 	}
 	if r.Email != nil {
 		if _, err := mail.ParseAddress(*r.Email); err != nil {
-			return ErrEmailInvalido
+			ev.Add("email", RegraFormato, ErrEmailInvalido)
 		}
 	}
 	if r.DataNascimento != nil && !isValidISODate(*r.DataNascimento) {
-		return ErrDataNascimentoInvalida
+		ev.Add("data_nascimento", RegraFormato, ErrDataNascimentoInvalida)
 	}
-	return nil
+	return ev.Err()
 }
 
 /// ============ Helpers de conversão (opcional) ============
@@ -275,4 +345,3 @@ func (u EstudanteUpdateRequest) ApplyTo(e *Estudante) {
 }
 
 // TODO: considerar regras adicionais de negócio (ex.: impedir datas futuras, validar formato E.164 para telefone, validar DV do CPF)
-// TODO: se necessário, internacionalizar mensagens de erro (i18n) mantendo contrato da API
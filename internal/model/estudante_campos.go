@@ -0,0 +1,114 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/model/estudante_campos.go
+/// Responsabilidade: Seleção de campos (?fields=) da listagem de estudantes: lista branca campo JSON → coluna SQL e a projeção do JSON com só os campos pedidos.
+/// Dependências principais: encoding/json, strings.
+/// Pontos de atenção:
+/// - Só nomes de camposEstudante chegam ao SQL (EstudanteRepo.PercorrerCampos); qualquer outro é ErrCampoDesconhecido.
+/// - Campos derivados puxam a coluna de origem: idade lê data_nascimento, iniciais lê nome.
+/// - CPF/telefone só são lidos e decifrados quando pedidos.
+/// - A projeção mantém a ordem pedida (sem repetição), como um objeto JSON comum.
+*/
+
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// campoEstudante liga um campo JSON à coluna lida e ao valor na resposta.
+type campoEstudante struct {
+	coluna  string                      // expressão SQL (aliases de deEstudantes)
+	destino func(*Estudante) any        // onde scanear a coluna
+	valor   func(EstudanteResposta) any // valor do campo na resposta
+}
+
+// EstudanteParcial é um EstudanteResposta serializado só com os campos pedidos.
+type EstudanteParcial struct {
+	EstudanteResposta
+	campos []string
+}
+
+/// ============ Configurações & Constantes ============
+
+// camposEstudante é a lista branca de ?fields= (nomes iguais às tags JSON de EstudanteResposta).
+var camposEstudante = map[string]campoEstudante{
+	"id":              {"e.id", func(e *Estudante) any { return &e.ID }, func(r EstudanteResposta) any { return r.ID }},
+	"nome":            {"e.nome", func(e *Estudante) any { return &e.Nome }, func(r EstudanteResposta) any { return r.Nome }},
+	"cpf":             {"e.cpf", func(e *Estudante) any { return &e.CPF }, func(r EstudanteResposta) any { return r.CPF }},
+	"email":           {"e.email", func(e *Estudante) any { return &e.Email }, func(r EstudanteResposta) any { return r.Email }},
+	"data_nascimento": {"e.data_nascimento", func(e *Estudante) any { return &e.DataNascimento }, func(r EstudanteResposta) any { return r.DataNascimento }},
+	"telefone":        {"e.telefone", func(e *Estudante) any { return &e.Telefone }, func(r EstudanteResposta) any { return r.Telefone }},
+	"foto_url":        {"e.foto_url", func(e *Estudante) any { return &e.FotoURL }, func(r EstudanteResposta) any { return r.FotoURL }},
+	"ano_id":          {"e.ano_id", func(e *Estudante) any { return &e.AnoID }, func(r EstudanteResposta) any { return r.AnoID }},
+	"turma_id":        {"e.turma_id", func(e *Estudante) any { return &e.TurmaID }, func(r EstudanteResposta) any { return r.TurmaID }},
+	"status":          {"e.status", func(e *Estudante) any { return &e.Status }, func(r EstudanteResposta) any { return r.Status }},
+	"versao":          {"e.versao", func(e *Estudante) any { return &e.Versao }, func(r EstudanteResposta) any { return r.Versao }},
+	"ano_nome":        {"COALESCE(a.nome, '')", func(e *Estudante) any { return &e.AnoNome }, func(r EstudanteResposta) any { return r.AnoNome }},
+	"turma_nome":      {"COALESCE(t.nome, '')", func(e *Estudante) any { return &e.TurmaNome }, func(r EstudanteResposta) any { return r.TurmaNome }},
+	"idade":           {"e.data_nascimento", func(e *Estudante) any { return &e.DataNascimento }, func(r EstudanteResposta) any { return r.Idade }},
+	"iniciais":        {"e.nome", func(e *Estudante) any { return &e.Nome }, func(r EstudanteResposta) any { return r.Iniciais }},
+}
+
+var ErrCampoDesconhecido = errors.New("campo desconhecido em fields")
+
+/// ============ Funções Públicas ============
+
+// CamposEstudante lê ?fields= ("id,nome,foto_url"): nomes sem espaços nas bordas,
+// em minúsculas e sem repetição. nil para lista vazia (todos os campos);
+// ErrCampoDesconhecido (com o nome) fora da lista branca.
+func CamposEstudante(lista string) ([]string, error) {
+	var out []string
+	for _, c := range strings.Split(lista, ",") {
+		c = strings.ToLower(strings.TrimSpace(c))
+		if c == "" || slices.Contains(out, c) {
+			continue
+		}
+		if _, ok := camposEstudante[c]; !ok {
+			return nil, fmt.Errorf("%w: %q", ErrCampoDesconhecido, c)
+		}
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+// CamposEstudantePermitidos lista os nomes aceitos em ?fields=, em ordem alfabética.
+func CamposEstudantePermitidos() []string {
+	out := make([]string, 0, len(camposEstudante))
+	for c := range camposEstudante {
+		out = append(out, c)
+	}
+	slices.Sort(out)
+	return out
+}
+
+// Parcial devolve r para serialização só com campos (validados por CamposEstudante).
+func (r EstudanteResposta) Parcial(campos []string) EstudanteParcial {
+	return EstudanteParcial{EstudanteResposta: r, campos: campos}
+}
+
+// MarshalJSON escreve o objeto com os campos na ordem pedida.
+func (p EstudanteParcial) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, c := range p.campos {
+		v, err := json.Marshal(camposEstudante[c].valor(p.EstudanteResposta))
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(`"` + c + `":`)
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,462 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/model/estudante_repo.go
+/// Responsabilidade: Repositório de estudantes (PostgreSQL) que cifra/decifra CPF e telefone de forma transparente e mantém o índice cego cpf_hash.
+/// Dependências principais: database/sql (Postgres), Array (driver.go), backend/internal/cripto.
+/// Pontos de atenção:
+/// - Handlers recebem/devolvem sempre texto puro; só o repositório enxerga o formato cifrado.
+/// - Buscas por CPF usam cpf_hash; linhas ainda não migradas (cpf_hash NULL) caem no fallback por cpf em texto puro.
+/// - CifrarExistentes é idempotente e processa em lotes; pode ser executado de novo após rotação de chave.
+/// - Erros do banco (ex.: violação de unicidade) são devolvidos sem tradução para o handler mapear.
+/// - Responsáveis (responsaveis.cpf/telefone) seguem o mesmo esquema, em responsavel_repo.go.
+/// - versao é incrementada por trigger (0003_estudantes_versao.sql) em qualquer UPDATE; Atualizar a usa para If-Match.
+/// - Remover é exclusão lógica (lixeira); a exclusão definitiva fica com a lixeira (handler/lixeira_handler.go).
+/// - Listar, Buscar e Criar usam prepared statements depois de Preparar (preparadas.go); sem ele, queries diretas.
+/// - Com UsarReplica, Listar/ListarDoAno/Percorrer/Marca leem da réplica (replica.go); ListarAtual lê sempre do primário.
+/// - PercorrerCampos monta o SELECT só com colunas da lista branca camposEstudante (estudante_campos.go), nunca com texto do cliente.
+/// - Listar/Buscar trazem os nomes de ano e turma (LEFT JOIN em anos) para EstudanteResposta; Criar não os preenche.
+*/
+
+package model
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"backend/internal/cripto"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// EstudanteRepo concentra o acesso à tabela `estudantes` que envolve campos cifrados.
+type EstudanteRepo struct {
+	db      *sql.DB
+	pii     *cripto.Cifrador
+	stmts   preparadas // preenchido por Preparar (antes de servir requisições)
+	replica *Replica   // leituras pesadas; nil = primário
+}
+
+/// ============ Configurações & Constantes ============
+
+// colunas lidas por Listar/Buscar (mesma ordem de scanEstudante), com os nomes de ano e turma
+const colunasEstudante = `e.id, e.nome, e.cpf, e.email, e.data_nascimento, e.telefone, e.foto_url, e.ano_id, e.turma_id, e.status, e.versao,
+	COALESCE(a.nome, ''), COALESCE(t.nome, '')`
+
+// estudantes (e) com o ano (a) e a turma (t), ambos em anos
+const deEstudantes = ` FROM estudantes e LEFT JOIN anos a ON a.id = e.ano_id LEFT JOIN anos t ON t.id = e.turma_id`
+
+// consultas quentes, preparadas por Preparar
+const (
+	sqlListarEstudantes       = `SELECT ` + colunasEstudante + deEstudantes + ` WHERE e.usuario_id = $1 AND e.excluido_em IS NULL ORDER BY e.id ASC`
+	sqlListarEstudantesStatus = `SELECT ` + colunasEstudante + deEstudantes + ` WHERE e.usuario_id = $1 AND e.excluido_em IS NULL AND e.status = ANY($2) ORDER BY e.id ASC`
+	sqlBuscarEstudante        = `SELECT ` + colunasEstudante + deEstudantes + ` WHERE e.id = $1 AND e.usuario_id = $2 AND e.excluido_em IS NULL`
+	sqlCriarEstudante         = `
+		INSERT INTO estudantes (nome, cpf, cpf_hash, email, data_nascimento, telefone, foto_url, ano_id, turma_id, usuario_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, versao`
+)
+
+// tamanho do lote usado por CifrarExistentes
+const loteMigracaoPII = 500
+
+// ErrVersaoDivergente indica que o estudante mudou desde a versão informada (If-Match).
+var ErrVersaoDivergente = errors.New("o estudante foi alterado por outra pessoa; recarregue antes de salvar")
+
+/// ============ Inicialização/Bootstrap ============
+
+// NewEstudanteRepo cria o repositório. pii nil = sem criptografia (desenvolvimento).
+func NewEstudanteRepo(db *sql.DB, pii *cripto.Cifrador) *EstudanteRepo {
+	return &EstudanteRepo{db: db, pii: pii}
+}
+
+/// ============ Funções Públicas ============
+
+// Preparar cria os prepared statements de Listar, Buscar e Criar. Chamar uma vez, na
+// inicialização (não é seguro em paralelo com as consultas).
+func (r *EstudanteRepo) Preparar(ctx context.Context) error {
+	p, err := preparar(ctx, r.db, sqlListarEstudantes, sqlListarEstudantesStatus, sqlBuscarEstudante, sqlCriarEstudante)
+	if err != nil {
+		return fmt.Errorf("estudantes: %w", err)
+	}
+	r.stmts = p
+	return nil
+}
+
+// UsarReplica direciona Listar, Percorrer e Marca para a réplica de leitura. Chamar na
+// inicialização, depois de Preparar.
+func (r *EstudanteRepo) UsarReplica(ctx context.Context, rep *Replica) {
+	rep.Preparar(ctx, sqlListarEstudantes, sqlListarEstudantesStatus)
+	r.replica = rep
+}
+
+// Listar devolve os estudantes não excluídos do usuário (status vazio = todos), por id.
+// Com réplica, pode não refletir escritas de instantes atrás (veja ListarAtual).
+func (r *EstudanteRepo) Listar(ctx context.Context, uid int, status []string) ([]Estudante, error) {
+	return r.listar(ctx, uid, status, true)
+}
+
+// ListarAtual é Listar lido do primário: para decidir escritas a partir da lista
+// (ex.: deduplicar antes de importar).
+func (r *EstudanteRepo) ListarAtual(ctx context.Context, uid int, status []string) ([]Estudante, error) {
+	return r.listar(ctx, uid, status, false)
+}
+
+// ListarDoAno devolve os estudantes não excluídos do ano anoID (status vazio = todos),
+// por nome. Com réplica, como Listar.
+func (r *EstudanteRepo) ListarDoAno(ctx context.Context, uid, anoID int, status []string) ([]Estudante, error) {
+	query, args := `SELECT `+colunasEstudante+deEstudantes+` WHERE e.usuario_id = $1 AND e.ano_id = $2 AND e.excluido_em IS NULL`, []any{uid, anoID}
+	if len(status) > 0 {
+		query, args = query+` AND e.status = ANY($3)`, append(args, Array(status))
+	}
+	rows, err := r.consultaLeitura(ctx, true, query+` ORDER BY LOWER(e.nome), e.id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Estudante
+	for rows.Next() {
+		est, err := r.scanEstudante(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, est)
+	}
+	return out, rows.Err()
+}
+
+// Percorrer chama fn para cada estudante de Listar, na mesma ordem, sem acumular a
+// lista em memória (listagens grandes/streaming). Erro de fn interrompe e é devolvido.
+func (r *EstudanteRepo) Percorrer(ctx context.Context, uid int, status []string, fn func(Estudante) error) error {
+	return r.percorrer(ctx, uid, status, true, fn)
+}
+
+// PercorrerCampos é Percorrer lendo só as colunas de campos (validados por
+// CamposEstudante); os demais campos do Estudante passado a fn ficam zerados.
+func (r *EstudanteRepo) PercorrerCampos(ctx context.Context, uid int, status []string, campos []string, fn func(Estudante) error) error {
+	var colunas []string
+	for _, c := range campos {
+		ce, ok := camposEstudante[c]
+		if !ok {
+			return fmt.Errorf("%w: %q", ErrCampoDesconhecido, c)
+		}
+		if !slices.Contains(colunas, ce.coluna) {
+			colunas = append(colunas, ce.coluna)
+		}
+	}
+	if len(colunas) == 0 {
+		return r.Percorrer(ctx, uid, status, fn)
+	}
+
+	query, args := `SELECT `+strings.Join(colunas, ", ")+deEstudantes+` WHERE e.usuario_id = $1 AND e.excluido_em IS NULL`, []any{uid}
+	if len(status) > 0 {
+		query, args = query+` AND e.status = ANY($2)`, append(args, Array(status))
+	}
+	rows, err := r.consultaLeitura(ctx, true, query+` ORDER BY e.id ASC`, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	destinos := make([]any, len(colunas))
+	for rows.Next() {
+		var est Estudante
+		for _, c := range campos {
+			ce := camposEstudante[c]
+			destinos[slices.Index(colunas, ce.coluna)] = ce.destino(&est)
+		}
+		if err := rows.Scan(destinos...); err != nil {
+			return err
+		}
+		if est.CPF, err = r.pii.Decifrar(est.CPF); err != nil {
+			return fmt.Errorf("estudante %d (cpf): %w", est.ID, err)
+		}
+		if est.Telefone, err = r.pii.Decifrar(est.Telefone); err != nil {
+			return fmt.Errorf("estudante %d (telefone): %w", est.ID, err)
+		}
+		if err := fn(est); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// Marca resume a listagem de Listar com os mesmos filtros: quantidade de linhas e
+// última alteração (atualizado_em dos estudantes e dos anos, cujos nomes vão na
+// listagem). Barata: não lê nem decifra os registros.
+func (r *EstudanteRepo) Marca(ctx context.Context, uid int, status []string) (int, time.Time, error) {
+	query := `SELECT COUNT(*), GREATEST(COALESCE(MAX(atualizado_em), 'epoch'),
+		COALESCE((SELECT MAX(atualizado_em) FROM anos WHERE usuario_id = $1), 'epoch'))
+		FROM estudantes WHERE usuario_id = $1 AND excluido_em IS NULL`
+	args := []any{uid}
+	if len(status) > 0 {
+		query += ` AND status = ANY($2)`
+		args = append(args, Array(status))
+	}
+	var (
+		total  int
+		ultima time.Time
+	)
+	rows, err := r.consultaLeitura(ctx, true, query, args...)
+	if err != nil {
+		return 0, ultima, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return 0, ultima, err
+		}
+		return 0, ultima, sql.ErrNoRows
+	}
+	err = rows.Scan(&total, &ultima)
+	return total, ultima, err
+}
+
+// Buscar devolve um estudante não excluído do usuário (sql.ErrNoRows se não existir).
+func (r *EstudanteRepo) Buscar(ctx context.Context, id, uid int) (Estudante, error) {
+	return r.scanEstudante(r.stmts.queryRow(ctx, r.db, sqlBuscarEstudante, id, uid))
+}
+
+// Criar insere o estudante e devolve o registro em texto puro.
+func (r *EstudanteRepo) Criar(ctx context.Context, uid int, in EstudanteCreateRequest) (Estudante, error) {
+	cpf, tel, err := r.cifrarCampos(in.CPF, in.Telefone)
+	if err != nil {
+		return Estudante{}, err
+	}
+	out := Estudante{
+		Nome:           in.Nome,
+		CPF:            in.CPF,
+		Email:          in.Email,
+		DataNascimento: in.DataNascimento,
+		Telefone:       in.Telefone,
+		FotoURL:        in.FotoURL,
+		AnoID:          in.AnoID,
+		TurmaID:        in.TurmaID,
+	}
+	err = r.stmts.queryRow(ctx, r.db, sqlCriarEstudante,
+		in.Nome, cpf, r.pii.Indice(in.CPF), in.Email, in.DataNascimento, tel, in.FotoURL, in.AnoID, in.TurmaID, uid,
+	).Scan(&out.ID, &out.Versao)
+	return out, err
+}
+
+// Atualizar regrava os dados do estudante se a versão ainda for versaoEsperada
+// (0 = qualquer versão) e devolve a nova versão.
+// Erros: sql.ErrNoRows se não existir para o usuário; ErrVersaoDivergente se outra
+// alteração aconteceu desde a leitura.
+func (r *EstudanteRepo) Atualizar(ctx context.Context, id, uid, versaoEsperada int, in EstudanteCreateRequest) (int, error) {
+	cpf, tel, err := r.cifrarCampos(in.CPF, in.Telefone)
+	if err != nil {
+		return 0, err
+	}
+	var nova int
+	err = r.db.QueryRowContext(ctx, `
+		UPDATE estudantes
+		   SET nome=$1, cpf=$2, cpf_hash=$3, email=$4, data_nascimento=$5, telefone=$6, foto_url=$7, ano_id=$8, turma_id=$9
+		 WHERE id=$10 AND usuario_id=$11 AND excluido_em IS NULL AND ($12 = 0 OR versao = $12)
+		RETURNING versao
+	`,
+		in.Nome, cpf, r.pii.Indice(in.CPF), in.Email, in.DataNascimento,
+		tel, in.FotoURL, in.AnoID, in.TurmaID,
+		id, uid, versaoEsperada,
+	).Scan(&nova)
+	if !errors.Is(err, sql.ErrNoRows) || versaoEsperada == 0 {
+		return nova, err
+	}
+	// nada atualizado: distingue "não existe" de "versão mudou"
+	var existe bool
+	if err := r.db.QueryRowContext(ctx,
+		`SELECT EXISTS(SELECT 1 FROM estudantes WHERE id=$1 AND usuario_id=$2 AND excluido_em IS NULL)`,
+		id, uid,
+	).Scan(&existe); err != nil {
+		return 0, err
+	}
+	if existe {
+		return 0, ErrVersaoDivergente
+	}
+	return 0, sql.ErrNoRows
+}
+
+// AtualizarFoto troca só a foto_url do estudante (sql.ErrNoRows se não existir para o usuário).
+func (r *EstudanteRepo) AtualizarFoto(ctx context.Context, id, uid int, url string) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE estudantes SET foto_url=$1 WHERE id=$2 AND usuario_id=$3 AND excluido_em IS NULL`, url, id, uid)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// Remover move o estudante do usuário para a lixeira (soft delete, autor = conta que
+// excluiu) e devolve o nome dele (sql.ErrNoRows se não existir ou já estiver excluído).
+func (r *EstudanteRepo) Remover(ctx context.Context, id, uid, autorID int) (string, error) {
+	var nome string
+	err := r.db.QueryRowContext(ctx, `
+		UPDATE estudantes SET excluido_em=NOW(), excluido_por=NULLIF($3, 0)
+		 WHERE id=$1 AND usuario_id=$2 AND excluido_em IS NULL
+		RETURNING nome
+	`, id, uid, autorID).Scan(&nome)
+	return nome, err
+}
+
+// CPFEmUso informa se outro estudante ativo do usuário já usa o CPF (ignorarID 0 = nenhum).
+func (r *EstudanteRepo) CPFEmUso(ctx context.Context, uid int, cpf string, ignorarID int) (bool, error) {
+	var existe bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM estudantes
+			 WHERE usuario_id = $1 AND excluido_em IS NULL AND id <> $4
+			   AND (cpf_hash = $2 OR (cpf_hash IS NULL AND cpf = $3))
+		)
+	`, uid, r.pii.Indice(cpf), cpf, ignorarID).Scan(&existe)
+	return existe, err
+}
+
+// CifrarExistentes regrava CPF/telefone de todas as linhas que ainda não estão
+// cifradas com a chave atual e preenche cpf_hash. Devolve quantas linhas mudaram.
+func (r *EstudanteRepo) CifrarExistentes(ctx context.Context) (int, error) {
+	total, ultimo := 0, 0
+	for {
+		rows, err := r.db.QueryContext(ctx, `
+			SELECT id, cpf, COALESCE(telefone, ''), cpf_hash IS NULL
+			  FROM estudantes
+			 WHERE id > $1
+			 ORDER BY id
+			 LIMIT $2
+		`, ultimo, loteMigracaoPII)
+		if err != nil {
+			return total, err
+		}
+		type linha struct {
+			id            int
+			cpf, telefone string
+			semHash       bool
+		}
+		var lote []linha
+		for rows.Next() {
+			var l linha
+			if err := rows.Scan(&l.id, &l.cpf, &l.telefone, &l.semHash); err != nil {
+				rows.Close()
+				return total, err
+			}
+			lote = append(lote, l)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return total, err
+		}
+		if len(lote) == 0 {
+			return total, nil
+		}
+
+		for _, l := range lote {
+			ultimo = l.id
+			if !l.semHash && r.pii.Atualizado(l.cpf) && r.pii.Atualizado(l.telefone) {
+				continue
+			}
+			cpf, err := r.pii.Decifrar(l.cpf)
+			if err != nil {
+				return total, fmt.Errorf("estudante %d (cpf): %w", l.id, err)
+			}
+			tel, err := r.pii.Decifrar(l.telefone)
+			if err != nil {
+				return total, fmt.Errorf("estudante %d (telefone): %w", l.id, err)
+			}
+			cpfC, telC, err := r.cifrarCampos(cpf, tel)
+			if err != nil {
+				return total, err
+			}
+			if _, err := r.db.ExecContext(ctx,
+				`UPDATE estudantes SET cpf=$1, cpf_hash=$2, telefone=$3 WHERE id=$4`,
+				cpfC, r.pii.Indice(cpf), telC, l.id,
+			); err != nil {
+				return total, fmt.Errorf("estudante %d: %w", l.id, err)
+			}
+			total++
+		}
+	}
+}
+
+/// ============ Funções Internas (helpers) ============
+
+func (r *EstudanteRepo) listar(ctx context.Context, uid int, status []string, daReplica bool) ([]Estudante, error) {
+	var out []Estudante
+	err := r.percorrer(ctx, uid, status, daReplica, func(est Estudante) error {
+		out = append(out, est)
+		return nil
+	})
+	return out, err
+}
+
+func (r *EstudanteRepo) percorrer(ctx context.Context, uid int, status []string, daReplica bool, fn func(Estudante) error) error {
+	query, args := sqlListarEstudantes, []any{uid}
+	if len(status) > 0 {
+		query, args = sqlListarEstudantesStatus, append(args, Array(status))
+	}
+
+	rows, err := r.consultaLeitura(ctx, daReplica, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		est, err := r.scanEstudante(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(est); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// consultaLeitura abre q na réplica (daReplica e configurada) ou no primário.
+func (r *EstudanteRepo) consultaLeitura(ctx context.Context, daReplica bool, q string, args ...any) (*sql.Rows, error) {
+	if daReplica && r.replica.Ativa() {
+		return r.replica.query(ctx, r.stmts, q, args...)
+	}
+	return r.stmts.query(ctx, r.db, q, args...)
+}
+
+// scanEstudante lê uma linha de colunasEstudante decifrando CPF e telefone.
+func (r *EstudanteRepo) scanEstudante(row interface{ Scan(...any) error }) (Estudante, error) {
+	var est Estudante
+	if err := row.Scan(
+		&est.ID, &est.Nome, &est.CPF, &est.Email, &est.DataNascimento,
+		&est.Telefone, &est.FotoURL, &est.AnoID, &est.TurmaID, &est.Status, &est.Versao,
+		&est.AnoNome, &est.TurmaNome,
+	); err != nil {
+		return est, err
+	}
+	var err error
+	if est.CPF, err = r.pii.Decifrar(est.CPF); err != nil {
+		return est, fmt.Errorf("estudante %d (cpf): %w", est.ID, err)
+	}
+	if est.Telefone, err = r.pii.Decifrar(est.Telefone); err != nil {
+		return est, fmt.Errorf("estudante %d (telefone): %w", est.ID, err)
+	}
+	return est, nil
+}
+
+// cifrarCampos cifra CPF e telefone com a chave atual.
+func (r *EstudanteRepo) cifrarCampos(cpf, telefone string) (string, string, error) {
+	c, err := r.pii.Cifrar(cpf)
+	if err != nil {
+		return "", "", err
+	}
+	t, err := r.pii.Cifrar(telefone)
+	if err != nil {
+		return "", "", err
+	}
+	return c, t, nil
+}
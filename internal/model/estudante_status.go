@@ -0,0 +1,89 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/model/estudante_status.go
+/// Responsabilidade: Ciclo de vida do estudante (ativo/transferido/formado), transições permitidas e DTO de mudança de status.
+/// Dependências principais: errors, strings.
+/// Pontos de atenção:
+/// - Transições: ativo → transferido|formado; transferido → ativo (rematrícula). "formado" é estado final.
+/// - Toda mudança exige motivo (fica no histórico estudante_status_historico).
+/// - Novos estudantes nascem "ativo" (DEFAULT da coluna).
+*/
+
+package model
+
+import (
+	"errors"
+	"strings"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// MudancaStatusRequest é o payload de PUT /api/estudantes/{id}/status.
+type MudancaStatusRequest struct {
+	Status string `json:"status"`
+	Motivo string `json:"motivo"`
+}
+
+// HistoricoStatus é uma linha de estudante_status_historico.
+type HistoricoStatus struct {
+	De       string `json:"de"`
+	Para     string `json:"para"`
+	Motivo   string `json:"motivo"`
+	CriadoEm string `json:"criado_em"`
+}
+
+/// ============ Configurações & Constantes ============
+
+const (
+	StatusAtivo       = "ativo"
+	StatusTransferido = "transferido"
+	StatusFormado     = "formado"
+)
+
+// transicoesStatus lista, para cada status, os destinos permitidos.
+var transicoesStatus = map[string][]string{
+	StatusAtivo:       {StatusTransferido, StatusFormado},
+	StatusTransferido: {StatusAtivo},
+	StatusFormado:     {},
+}
+
+var (
+	ErrStatusInvalido    = errors.New("status inválido (ativo, transferido, formado)")
+	ErrMotivoObrigatorio = errors.New("motivo é obrigatório")
+	ErrTransicaoStatus   = errors.New("transição de status não permitida")
+)
+
+/// ============ Funções Públicas ============
+
+// StatusValido informa se s é um dos status conhecidos.
+func StatusValido(s string) bool {
+	_, ok := transicoesStatus[s]
+	return ok
+}
+
+// TransicaoPermitida verifica se o estudante pode ir de `de` para `para`.
+func TransicaoPermitida(de, para string) error {
+	for _, d := range transicoesStatus[de] {
+		if d == para {
+			return nil
+		}
+	}
+	return ErrTransicaoStatus
+}
+
+// Sanitize normaliza status (minúsculas) e faz trim do motivo.
+func (m *MudancaStatusRequest) Sanitize() {
+	m.Status = strings.ToLower(strings.TrimSpace(m.Status))
+	m.Motivo = strings.TrimSpace(m.Motivo)
+}
+
+// Validate exige status conhecido e motivo preenchido.
+func (m MudancaStatusRequest) Validate() error {
+	if !StatusValido(m.Status) {
+		return ErrStatusInvalido
+	}
+	if m.Motivo == "" {
+		return ErrMotivoObrigatorio
+	}
+	return nil
+}
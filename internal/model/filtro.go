@@ -0,0 +1,200 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/model/filtro.go
+/// Responsabilidade: Filtros salvos ("listas inteligentes") de estudantes: especificação validada, gravada como JSON, e sua aplicação sobre a listagem.
+/// Dependências principais: errors, slices, strings, time, unicode/utf8.
+/// Pontos de atenção:
+/// - O filtro é aplicado em memória sobre EstudanteRepo.Listar (CPF/telefone são cifrados no banco; "sem telefone" só é visível decifrado).
+/// - Critérios vazios não restringem; todos os informados precisam ser atendidos (E lógico).
+/// - Ids de ano/turma não são conferidos na gravação: um ano removido depois apenas deixa de trazer resultados.
+*/
+
+package model
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// FiltroEstudantes é a especificação gravada de um filtro salvo.
+type FiltroEstudantes struct {
+	Status        []string `json:"status"`         // ativo | transferido | formado
+	AnoID         []int    `json:"ano_id"`         // qualquer um dos anos
+	TurmaID       []int    `json:"turma_id"`       // qualquer uma das turmas
+	Busca         string   `json:"busca"`          // trecho do nome ou e-mail (sem diferenciar maiúsculas)
+	Sem           []string `json:"sem"`            // campos vazios: telefone | email | cpf | foto_url | data_nascimento | turma_id
+	NascimentoDe  string   `json:"nascimento_de"`  // YYYY-MM-DD (inclusive)
+	NascimentoAte string   `json:"nascimento_ate"` // YYYY-MM-DD (inclusive)
+}
+
+// FiltroSalvo representa um registro da tabela `filtros_salvos`.
+type FiltroSalvo struct {
+	ID           int              `json:"id"`
+	Nome         string           `json:"nome"`
+	Filtro       FiltroEstudantes `json:"filtro"`
+	CriadoEm     time.Time        `json:"criado_em"`
+	AtualizadoEm time.Time        `json:"atualizado_em"`
+}
+
+// FiltroSalvoRequest é o payload de POST /api/filtros e PUT /api/filtros/{id}.
+type FiltroSalvoRequest struct {
+	Nome   string           `json:"nome"`
+	Filtro FiltroEstudantes `json:"filtro"`
+}
+
+// ResultadoFiltro é a resposta de GET /api/filtros/{id}/resultados.
+type ResultadoFiltro struct {
+	Filtro FiltroSalvo `json:"filtro"`
+	Total  int         `json:"total"`
+	Itens  []Estudante `json:"itens"`
+}
+
+/// ============ Configurações & Constantes ============
+
+// camposFiltroSem lista os campos aceitos em "sem" e como saber se estão vazios.
+var camposFiltroSem = map[string]func(Estudante) bool{
+	"telefone":        func(e Estudante) bool { return e.Telefone == "" },
+	"email":           func(e Estudante) bool { return e.Email == "" },
+	"cpf":             func(e Estudante) bool { return e.CPF == "" },
+	"foto_url":        func(e Estudante) bool { return e.FotoURL == "" },
+	"data_nascimento": func(e Estudante) bool { return dataISO(e.DataNascimento) == "" },
+	"turma_id":        func(e Estudante) bool { return e.TurmaID == 0 },
+}
+
+// limites do filtro salvo
+const (
+	filtroNomeMaximo  = 80
+	filtroBuscaMaximo = 100
+	filtroMaxIDs      = 500
+)
+
+var (
+	ErrFiltroNomeObrigatorio = errors.New("nome do filtro é obrigatório")
+	ErrFiltroNomeLongo       = errors.New("nome do filtro com no máximo 80 caracteres")
+	ErrFiltroStatus          = errors.New("filtro.status aceita ativo, transferido e formado")
+	ErrFiltroIDs             = errors.New("filtro com no máximo 500 ids")
+	ErrFiltroBusca           = errors.New("filtro.busca com no máximo 100 caracteres")
+	ErrFiltroSem             = errors.New("filtro.sem aceita telefone, email, cpf, foto_url, data_nascimento e turma_id")
+	ErrFiltroNascimento      = errors.New("filtro.nascimento_de/nascimento_ate devem estar no formato YYYY-MM-DD, com de <= ate")
+)
+
+/// ============ Funções Públicas ============
+
+// Sanitize normaliza nome, listas (minúsculas, sem repetição) e datas.
+func (r *FiltroSalvoRequest) Sanitize() {
+	r.Nome = strings.TrimSpace(r.Nome)
+	r.Filtro.Sanitize()
+}
+
+// Validate exige nome e um filtro válido.
+func (r FiltroSalvoRequest) Validate() error {
+	var ev ErrosValidacao
+	switch {
+	case r.Nome == "":
+		ev.Add("nome", RegraObrigatorio, ErrFiltroNomeObrigatorio)
+	case utf8.RuneCountInString(r.Nome) > filtroNomeMaximo:
+		ev.Add("nome", RegraFormato, ErrFiltroNomeLongo)
+	}
+	if filtro, ok := ComoErrosValidacao(r.Filtro.Validate()); ok {
+		ev = append(ev, filtro...)
+	}
+	return ev.Err()
+}
+
+// Sanitize normaliza listas (minúsculas, sem repetição), busca e datas.
+func (f *FiltroEstudantes) Sanitize() {
+	f.Status = semRepetir(normalizarLista(f.Status))
+	f.Sem = semRepetir(normalizarLista(f.Sem))
+	f.AnoID = semRepetir(f.AnoID)
+	f.TurmaID = semRepetir(f.TurmaID)
+	f.Busca = strings.TrimSpace(f.Busca)
+	f.NascimentoDe = strings.TrimSpace(f.NascimentoDe)
+	f.NascimentoAte = strings.TrimSpace(f.NascimentoAte)
+}
+
+// Validate confere cada critério contra os valores aceitos.
+func (f FiltroEstudantes) Validate() error {
+	var ev ErrosValidacao
+	for _, st := range f.Status {
+		if !StatusValido(st) {
+			ev.Add("filtro.status", RegraFormato, ErrFiltroStatus)
+			break
+		}
+	}
+	if len(f.AnoID) > filtroMaxIDs {
+		ev.Add("filtro.ano_id", RegraFormato, ErrFiltroIDs)
+	}
+	if len(f.TurmaID) > filtroMaxIDs {
+		ev.Add("filtro.turma_id", RegraFormato, ErrFiltroIDs)
+	}
+	if utf8.RuneCountInString(f.Busca) > filtroBuscaMaximo {
+		ev.Add("filtro.busca", RegraFormato, ErrFiltroBusca)
+	}
+	for _, c := range f.Sem {
+		if _, ok := camposFiltroSem[c]; !ok {
+			ev.Add("filtro.sem", RegraFormato, ErrFiltroSem)
+			break
+		}
+	}
+	if (f.NascimentoDe != "" && !isValidISODate(f.NascimentoDe)) ||
+		(f.NascimentoAte != "" && !isValidISODate(f.NascimentoAte)) ||
+		(f.NascimentoDe != "" && f.NascimentoAte != "" && f.NascimentoDe > f.NascimentoAte) {
+		ev.Add("filtro.nascimento", RegraFormato, ErrFiltroNascimento)
+	}
+	return ev.Err()
+}
+
+// Corresponde diz se o estudante atende a todos os critérios do filtro.
+func (f FiltroEstudantes) Corresponde(e Estudante) bool {
+	if len(f.Status) > 0 && !slices.Contains(f.Status, e.Status) {
+		return false
+	}
+	if len(f.AnoID) > 0 && !slices.Contains(f.AnoID, e.AnoID) {
+		return false
+	}
+	if len(f.TurmaID) > 0 && !slices.Contains(f.TurmaID, e.TurmaID) {
+		return false
+	}
+	if busca := strings.ToLower(f.Busca); busca != "" &&
+		!strings.Contains(strings.ToLower(e.Nome), busca) && !strings.Contains(strings.ToLower(e.Email), busca) {
+		return false
+	}
+	for _, c := range f.Sem {
+		if vazio := camposFiltroSem[c]; vazio != nil && !vazio(e) {
+			return false
+		}
+	}
+	if f.NascimentoDe != "" || f.NascimentoAte != "" {
+		nasc := dataISO(e.DataNascimento)
+		if nasc == "" || (f.NascimentoDe != "" && nasc < f.NascimentoDe) || (f.NascimentoAte != "" && nasc > f.NascimentoAte) {
+			return false
+		}
+	}
+	return true
+}
+
+/// ============ Funções Internas (helpers) ============
+
+// dataISO reduz data/timestamp ("2010-05-01T00:00:00Z") ao dia (YYYY-MM-DD); "" se não houver.
+func dataISO(s string) string {
+	if len(s) < len(dateLayoutISO) {
+		return ""
+	}
+	return s[:len(dateLayoutISO)]
+}
+
+// semRepetir remove repetições mantendo a ordem da primeira ocorrência.
+func semRepetir[T comparable](xs []T) []T {
+	out := make([]T, 0, len(xs))
+	for _, x := range xs {
+		if !slices.Contains(out, x) {
+			out = append(out, x)
+		}
+	}
+	return out
+}
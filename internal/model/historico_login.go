@@ -0,0 +1,128 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/model/historico_login.go
+/// Responsabilidade: Histórico de logins (tabela `logins`): registro de cada tentativa de uma conta existente e listagem paginada para GET /api/usuario/logins.
+/// Dependências principais: context, database/sql, time, unicode/utf8.
+/// Pontos de atenção:
+/// - Registrado pelos handlers de login depois de decidir a resposta; falha ao registrar não muda o resultado do login (apenas log).
+/// - Cada conta guarda só os historicoLoginMaximo registros mais recentes.
+/// - user_agent é truncado (userAgentMaximo): o valor vem do cliente.
+*/
+
+package model
+
+import (
+	"context"
+	"database/sql"
+	"time"
+	"unicode/utf8"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// NovoLogin é o que um handler de login registra no histórico.
+type NovoLogin struct {
+	UsuarioID int
+	Metodo    string // MetodoLoginSenha | MetodoLoginGoogle
+	Sucesso   bool
+	Motivo    string // vazio em sucesso; ex.: MotivoLoginSenhaIncorreta
+	IP        string
+	UserAgent string
+}
+
+// RegistroLogin é um item de GET /api/usuario/logins.
+type RegistroLogin struct {
+	ID        int64     `json:"id"`
+	Metodo    string    `json:"metodo"`
+	Sucesso   bool      `json:"sucesso"`
+	Motivo    string    `json:"motivo,omitempty"`
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"user_agent"`
+	CriadoEm  time.Time `json:"criado_em"`
+}
+
+// HistoricoLogins é a resposta paginada do histórico (proximo = valor de ?antes= da próxima página).
+type HistoricoLogins struct {
+	Itens   []RegistroLogin `json:"itens"`
+	Proximo *int64          `json:"proximo"`
+}
+
+/// ============ Configurações & Constantes ============
+
+// Métodos de login.
+const (
+	MetodoLoginSenha  = "senha"
+	MetodoLoginGoogle = "google"
+)
+
+// Motivos de tentativas recusadas.
+const (
+	MotivoLoginSenhaIncorreta = "senha_incorreta"
+	MotivoLoginContaBloqueada = "conta_bloqueada"
+)
+
+const (
+	historicoLoginMaximo = 200 // registros mantidos por conta
+	userAgentMaximo      = 512 // caracteres gravados do User-Agent
+)
+
+/// ============ Funções Públicas ============
+
+// RegistrarLogin grava a tentativa e descarta os registros da conta além dos
+// historicoLoginMaximo mais recentes.
+func RegistrarLogin(ctx context.Context, db *sql.DB, l NovoLogin) error {
+	ua := l.UserAgent
+	if utf8.RuneCountInString(ua) > userAgentMaximo {
+		ua = string([]rune(ua)[:userAgentMaximo])
+	}
+	return ComTransacao(ctx, db, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO logins (usuario_id, metodo, sucesso, motivo, ip, user_agent)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`, l.UsuarioID, l.Metodo, l.Sucesso, l.Motivo, l.IP, ua); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, `
+			DELETE FROM logins
+			 WHERE usuario_id = $1
+			   AND id < (SELECT id FROM logins WHERE usuario_id = $1 ORDER BY id DESC OFFSET $2 LIMIT 1)
+		`, l.UsuarioID, historicoLoginMaximo-1)
+		return err
+	})
+}
+
+// ListarLogins devolve até limite tentativas da conta com id menor que antes (0 = do início),
+// da mais recente para a mais antiga.
+func ListarLogins(ctx context.Context, db *sql.DB, usuarioID int, antes int64, limite int) (HistoricoLogins, error) {
+	// Busca um item a mais para saber se há próxima página.
+	rows, err := db.QueryContext(ctx, `
+		SELECT id, metodo, sucesso, motivo, ip, user_agent, criado_em
+		  FROM logins
+		 WHERE usuario_id = $1
+		   AND ($2 = 0 OR id < $2)
+		 ORDER BY id DESC
+		 LIMIT $3
+	`, usuarioID, antes, limite+1)
+	if err != nil {
+		return HistoricoLogins{}, err
+	}
+	defer rows.Close()
+
+	out := HistoricoLogins{Itens: []RegistroLogin{}}
+	for rows.Next() {
+		var l RegistroLogin
+		if err := rows.Scan(&l.ID, &l.Metodo, &l.Sucesso, &l.Motivo, &l.IP, &l.UserAgent, &l.CriadoEm); err != nil {
+			return HistoricoLogins{}, err
+		}
+		out.Itens = append(out.Itens, l)
+	}
+	if err := rows.Err(); err != nil {
+		return HistoricoLogins{}, err
+	}
+	if len(out.Itens) > limite {
+		out.Itens = out.Itens[:limite]
+		proximo := out.Itens[limite-1].ID
+		out.Proximo = &proximo
+	}
+	return out, nil
+}
@@ -0,0 +1,154 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/model/importacao_classroom.go
+/// Responsabilidade: Prévia e confirmação da importação de alunos do Google Classroom: comparação com os estudantes existentes (duplicados) e payload da confirmação.
+/// Dependências principais: errors, strings.
+/// Pontos de atenção:
+/// - O Classroom só fornece nome e e-mail; CPF e data de nascimento (obrigatórios no cadastro) vêm do usuário na confirmação.
+/// - Duplicado = mesmo e-mail de um estudante ativo; possível duplicado = nome muito parecido (SimilaridadeNome >= LimiarNomeClassroom).
+/// - A confirmação busca a lista de novo no Classroom: nome/e-mail sempre vêm do Google, nunca do corpo da requisição.
+*/
+
+package model
+
+import (
+	"errors"
+	"strings"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// PreviaClassroom é a resposta da prévia: alunos do curso classificados.
+type PreviaClassroom struct {
+	CursoID            string                `json:"curso_id"`
+	Total              int                   `json:"total"`
+	Novos              int                   `json:"novos"`
+	Duplicados         int                   `json:"duplicados"`
+	PossiveisDuplicado int                   `json:"possiveis_duplicados"`
+	Itens              []ItemPreviaClassroom `json:"itens"`
+}
+
+// ItemPreviaClassroom é um aluno do Classroom comparado com os estudantes existentes.
+type ItemPreviaClassroom struct {
+	GoogleID    string `json:"google_id"`
+	Nome        string `json:"nome"`
+	Email       string `json:"email"`
+	Situacao    string `json:"situacao"`               // novo | duplicado | possivel_duplicado
+	EstudanteID int    `json:"estudante_id,omitempty"` // estudante existente correspondente
+	Motivo      string `json:"motivo,omitempty"`       // email | nome
+}
+
+// ImportarClassroomRequest é o payload da confirmação.
+type ImportarClassroomRequest struct {
+	AccessToken string                  `json:"access_token"`
+	CursoID     string                  `json:"curso_id"`
+	AnoID       int                     `json:"ano_id"`
+	TurmaID     int                     `json:"turma_id"`
+	Estudantes  []ImportarClassroomItem `json:"estudantes"` // alunos escolhidos na prévia
+}
+
+// ImportarClassroomItem completa um aluno do Classroom com os dados obrigatórios do cadastro.
+type ImportarClassroomItem struct {
+	GoogleID       string `json:"google_id"`
+	CPF            string `json:"cpf"`
+	DataNascimento string `json:"data_nascimento"`
+	Telefone       string `json:"telefone"`
+}
+
+// ResultadoImportacaoClassroom resume a confirmação (um item por aluno enviado).
+type ResultadoImportacaoClassroom struct {
+	Importados int                      `json:"importados"`
+	Ignorados  int                      `json:"ignorados"`
+	Itens      []ResultadoItemClassroom `json:"itens"`
+}
+
+// ResultadoItemClassroom é o desfecho de um aluno na confirmação.
+type ResultadoItemClassroom struct {
+	GoogleID    string         `json:"google_id"`
+	Nome        string         `json:"nome,omitempty"`
+	Situacao    string         `json:"situacao"` // criado | duplicado | invalido | fora_do_curso
+	EstudanteID int            `json:"estudante_id,omitempty"`
+	Erros       ErrosValidacao `json:"erros,omitempty"`
+	Mensagem    string         `json:"mensagem,omitempty"`
+}
+
+/// ============ Configurações & Constantes ============
+
+// Situações de um aluno na prévia/confirmação.
+const (
+	ClassroomNovo              = "novo"
+	ClassroomDuplicado         = "duplicado"
+	ClassroomPossivelDuplicado = "possivel_duplicado"
+	ClassroomCriado            = "criado"
+	ClassroomInvalido          = "invalido"
+	ClassroomForaDoCurso       = "fora_do_curso"
+)
+
+// LimiarNomeClassroom é a similaridade de nome a partir da qual o aluno é marcado como possível duplicado.
+const LimiarNomeClassroom = 0.8
+
+// limite de alunos por confirmação
+const classroomMaxEstudantes = 500
+
+var (
+	ErrClassroomToken      = errors.New("access_token é obrigatório")
+	ErrClassroomCurso      = errors.New("curso_id é obrigatório")
+	ErrClassroomEstudantes = errors.New("informe de 1 a 500 estudantes, sem repetir google_id")
+)
+
+/// ============ Funções Públicas ============
+
+// CompararAlunoClassroom classifica o aluno (nome/e-mail do Classroom) contra os estudantes ativos.
+func CompararAlunoClassroom(nome, email string, existentes []Estudante) ItemPreviaClassroom {
+	it := ItemPreviaClassroom{Nome: nome, Email: email, Situacao: ClassroomNovo}
+	melhor := 0.0
+	for _, e := range existentes {
+		if email != "" && strings.EqualFold(e.Email, email) {
+			it.Situacao, it.EstudanteID, it.Motivo = ClassroomDuplicado, e.ID, "email"
+			return it
+		}
+		if s := SimilaridadeNome(nome, e.Nome); s >= LimiarNomeClassroom && s > melhor {
+			melhor = s
+			it.Situacao, it.EstudanteID, it.Motivo = ClassroomPossivelDuplicado, e.ID, "nome"
+		}
+	}
+	return it
+}
+
+// Sanitize normaliza token, curso e os dados complementares de cada aluno.
+func (r *ImportarClassroomRequest) Sanitize() {
+	r.AccessToken = strings.TrimSpace(r.AccessToken)
+	r.CursoID = strings.TrimSpace(r.CursoID)
+	for i := range r.Estudantes {
+		it := &r.Estudantes[i]
+		it.GoogleID = strings.TrimSpace(it.GoogleID)
+		it.CPF = digitsOnly(it.CPF)
+		it.DataNascimento = strings.TrimSpace(it.DataNascimento)
+		it.Telefone = strings.TrimSpace(it.Telefone)
+	}
+}
+
+// Validate confere o envelope da confirmação; os dados de cada aluno são validados
+// individualmente na importação (um aluno inválido não impede os demais).
+func (r ImportarClassroomRequest) Validate() error {
+	var ev ErrosValidacao
+	if r.AccessToken == "" {
+		ev.Add("access_token", RegraObrigatorio, ErrClassroomToken)
+	}
+	if r.CursoID == "" {
+		ev.Add("curso_id", RegraObrigatorio, ErrClassroomCurso)
+	}
+	vistos := make(map[string]bool, len(r.Estudantes))
+	valido := len(r.Estudantes) > 0 && len(r.Estudantes) <= classroomMaxEstudantes
+	for _, it := range r.Estudantes {
+		if it.GoogleID == "" || vistos[it.GoogleID] {
+			valido = false
+			break
+		}
+		vistos[it.GoogleID] = true
+	}
+	if !valido {
+		ev.Add("estudantes", RegraFormato, ErrClassroomEstudantes)
+	}
+	return ev.Err()
+}
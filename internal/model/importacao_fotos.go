@@ -0,0 +1,100 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/model/importacao_fotos.go
+/// Responsabilidade: Importação de fotos em lote (ZIP): identificação do estudante pelo nome de cada arquivo (CPF ou e-mail) e o relatório por arquivo.
+/// Dependências principais: path, strings.
+/// Pontos de atenção:
+/// - O nome do arquivo (sem pastas nem extensão) é o identificador: com "@" é e-mail; senão, CPF com ou sem máscara (11 dígitos).
+/// - A correspondência é só com estudantes não excluídos do tenant; mais de um candidato (ex.: e-mail repetido) não é adivinhado: vira "ambiguo".
+/// - Pastas, arquivos ocultos e metadados do macOS (__MACOSX/) são pulados sem entrar no relatório.
+*/
+
+package model
+
+import (
+	"path"
+	"strings"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// ResultadoImportacaoFotos resume a importação (um item por arquivo considerado).
+type ResultadoImportacaoFotos struct {
+	Total      int                  `json:"total"`
+	Importadas int                  `json:"importadas"`
+	Ignoradas  int                  `json:"ignoradas"`
+	Itens      []ItemImportacaoFoto `json:"itens"`
+}
+
+// ItemImportacaoFoto é o desfecho de um arquivo do ZIP.
+type ItemImportacaoFoto struct {
+	Arquivo     string `json:"arquivo"`
+	Situacao    string `json:"situacao"` // importada | sem_correspondencia | ambiguo | repetido | invalido | infectado | erro
+	EstudanteID int    `json:"estudante_id,omitempty"`
+	Nome        string `json:"nome,omitempty"`
+	FotoURL     string `json:"foto_url,omitempty"`
+	Motivo      string `json:"motivo,omitempty"`
+}
+
+// IdentificadorFoto é o CPF (só dígitos) ou o e-mail (minúsculo) lido do nome do arquivo.
+type IdentificadorFoto struct {
+	CPF   string
+	Email string
+}
+
+/// ============ Configurações & Constantes ============
+
+// Situações de um arquivo na importação de fotos.
+const (
+	FotoImportada          = "importada"
+	FotoSemCorrespondencia = "sem_correspondencia"
+	FotoAmbigua            = "ambiguo"
+	FotoRepetida           = "repetido" // outro arquivo do ZIP já trouxe a foto do mesmo estudante
+	FotoInvalida           = "invalido"
+	FotoInfectada          = "infectado"
+	FotoErro               = "erro"
+)
+
+/// ============ Funções Públicas ============
+
+// IgnorarArquivoFoto indica entradas do ZIP que não são fotos enviadas pelo usuário
+// (pastas, ocultos, __MACOSX/) e ficam fora do relatório.
+func IgnorarArquivoFoto(nome string) bool {
+	if strings.HasSuffix(nome, "/") {
+		return true
+	}
+	for _, parte := range strings.Split(nome, "/") {
+		if parte == "__MACOSX" || strings.HasPrefix(parte, ".") {
+			return true
+		}
+	}
+	return false
+}
+
+// IdentificarFoto lê o CPF ou e-mail do nome do arquivo ("pasta/123.456.789-09.jpg",
+// "ana@escola.com.png"). ok=false quando o nome não é nenhum dos dois.
+func IdentificarFoto(nome string) (IdentificadorFoto, bool) {
+	base := path.Base(nome)
+	base = strings.TrimSpace(strings.TrimSuffix(base, path.Ext(base)))
+	if strings.Contains(base, "@") {
+		return IdentificadorFoto{Email: strings.ToLower(base)}, true
+	}
+	if strings.Trim(base, "0123456789.- ") != "" {
+		return IdentificadorFoto{}, false
+	}
+	if cpf := digitsOnly(base); len(cpf) == 11 {
+		return IdentificadorFoto{CPF: cpf}, true
+	}
+	return IdentificadorFoto{}, false
+}
+
+// CorresponderFoto devolve os estudantes com o CPF ou e-mail do identificador.
+func CorresponderFoto(id IdentificadorFoto, existentes []Estudante) []Estudante {
+	var out []Estudante
+	for _, e := range existentes {
+		if (id.CPF != "" && e.CPF == id.CPF) || (id.Email != "" && strings.EqualFold(e.Email, id.Email)) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
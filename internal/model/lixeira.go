@@ -0,0 +1,52 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/model/lixeira.go
+/// Responsabilidade: Itens da lixeira (estudantes e anos com exclusão lógica) e os tipos aceitos nas rotas de restauração/exclusão definitiva.
+/// Dependências principais: strings, time.
+/// Pontos de atenção:
+/// - Estudantes excluídos junto com o ano (force=true) só voltam restaurando o ano (AnoNaLixeira = true).
+/// - Registros mesclados (POST /api/estudantes/merge) também ficam na lixeira, sem autor.
+/// - A exclusão definitiva exige confirmar o nome do item (ConfirmacaoLixeira).
+*/
+
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// ItemLixeira é um item de GET /api/lixeira.
+type ItemLixeira struct {
+	Tipo         string         `json:"tipo"` // estudante | ano
+	ID           int            `json:"id"`
+	Nome         string         `json:"nome"`
+	ExcluidoEm   time.Time      `json:"excluido_em"`
+	ExcluidoPor  AutorAtividade `json:"excluido_por"`             // ID 0 = desconhecido (mesclagem, conta removida)
+	Estudantes   int            `json:"estudantes,omitempty"`     // anos: estudantes excluídos junto
+	AnoNaLixeira bool           `json:"ano_na_lixeira,omitempty"` // estudantes: restaure o ano primeiro
+}
+
+/// ============ Configurações & Constantes ============
+
+// Tipos de item da lixeira ({tipo} nas rotas).
+const (
+	LixeiraEstudante = "estudante"
+	LixeiraAno       = "ano"
+)
+
+/// ============ Funções Públicas ============
+
+// TipoLixeiraValido informa se t é um tipo de item da lixeira.
+func TipoLixeiraValido(t string) bool {
+	return t == LixeiraEstudante || t == LixeiraAno
+}
+
+// ConfirmacaoLixeira confere a confirmação da exclusão definitiva: o nome do
+// item, sem diferenciar maiúsculas nem espaços nas pontas.
+func ConfirmacaoLixeira(nome, confirmacao string) bool {
+	confirmacao = strings.TrimSpace(confirmacao)
+	return confirmacao != "" && strings.EqualFold(strings.TrimSpace(nome), confirmacao)
+}
@@ -0,0 +1,113 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/model/matricula.go
+/// Responsabilidade: DTOs de movimentação de matrícula (transferência entre anos/turmas e promoção de turma inteira) e linha do histórico.
+/// Dependências principais: errors, strings, time.
+/// Pontos de atenção:
+/// - Data da movimentação é opcional no payload; quando vazia o handler usa a data de hoje.
+/// - Transferir para o mesmo ano+turma atual é rejeitado (não gera histórico vazio).
+/// - Promoção move apenas estudantes com status "ativo"; dry_run devolve a prévia sem gravar.
+/// - TurmaID 0 significa "sem turma" (mesma convenção da tabela estudantes).
+*/
+
+package model
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// TransferenciaRequest é o payload de POST /api/estudantes/{id}/transferir.
+type TransferenciaRequest struct {
+	AnoID   int    `json:"ano_id"`
+	TurmaID int    `json:"turma_id"`
+	Data    string `json:"data"` // YYYY-MM-DD (opcional; padrão hoje)
+	Motivo  string `json:"motivo"`
+}
+
+// PromocaoRequest é o payload de POST /api/anos/{id}/promover.
+type PromocaoRequest struct {
+	ParaAnoID   int    `json:"para_ano_id"`
+	ParaTurmaID int    `json:"para_turma_id"`
+	Data        string `json:"data"`
+	Motivo      string `json:"motivo"`
+	DryRun      bool   `json:"dry_run"`
+}
+
+// MovimentoMatricula é uma linha de `matriculas_historico`.
+type MovimentoMatricula struct {
+	ID          int    `json:"id"`
+	EstudanteID int    `json:"estudante_id"`
+	DeAnoID     int    `json:"de_ano_id"`
+	DeTurmaID   int    `json:"de_turma_id"`
+	ParaAnoID   int    `json:"para_ano_id"`
+	ParaTurmaID int    `json:"para_turma_id"`
+	Data        string `json:"data"`
+	Motivo      string `json:"motivo"`
+}
+
+/// ============ Configurações & Constantes ============
+
+var (
+	ErrDataMovimento           = errors.New("data inválida (esperado YYYY-MM-DD)")
+	ErrTransferenciaMesmaTurma = errors.New("estudante já está neste ano/turma")
+	ErrTurmaInvalida           = errors.New("turma_id inválido")
+)
+
+/// ============ Funções Internas (helpers) ============
+
+// dataOuHoje devolve s ou, se vazio, a data atual no layout ISO.
+func dataOuHoje(s string) string {
+	if s == "" {
+		return time.Now().Format(dateLayoutISO)
+	}
+	return s
+}
+
+/// ============ Funções Públicas ============
+
+// Sanitize faz trim dos textos e preenche a data padrão.
+func (t *TransferenciaRequest) Sanitize() {
+	t.Motivo = strings.TrimSpace(t.Motivo)
+	t.Data = dataOuHoje(strings.TrimSpace(t.Data))
+}
+
+// Validate exige ano de destino, turma não negativa e data ISO.
+func (t TransferenciaRequest) Validate() error {
+	if t.AnoID <= 0 {
+		return ErrAnoObrigatorio
+	}
+	if t.TurmaID < 0 {
+		return ErrTurmaInvalida
+	}
+	if !isValidISODate(t.Data) {
+		return ErrDataMovimento
+	}
+	return nil
+}
+
+// Sanitize faz trim dos textos e preenche data/motivo padrão.
+func (p *PromocaoRequest) Sanitize() {
+	p.Motivo = strings.TrimSpace(p.Motivo)
+	if p.Motivo == "" {
+		p.Motivo = "promoção"
+	}
+	p.Data = dataOuHoje(strings.TrimSpace(p.Data))
+}
+
+// Validate exige ano de destino, turma não negativa e data ISO.
+func (p PromocaoRequest) Validate() error {
+	if p.ParaAnoID <= 0 {
+		return ErrAnoObrigatorio
+	}
+	if p.ParaTurmaID < 0 {
+		return ErrTurmaInvalida
+	}
+	if !isValidISODate(p.Data) {
+		return ErrDataMovimento
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/model/notificacao.go
+/// Responsabilidade: Notificações in-app (sino do frontend): entidade, tipos de evento e gravação a partir dos produtores (jobs/handlers).
+/// Dependências principais: context, database/sql, encoding/json, time.
+/// Pontos de atenção:
+/// - O destinatário é o usuário autenticado (usuario_id), não o tenant da organização.
+/// - Com Chave preenchida, repetir o mesmo evento não duplica a notificação (ON CONFLICT DO NOTHING).
+/// - Dados é serializado em JSONB; mantenha apenas ids/contagens (nada de CPF/telefone).
+*/
+
+package model
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// Notificacao representa um registro da tabela `notificacoes`.
+type Notificacao struct {
+	ID       int64          `json:"id"`
+	Tipo     string         `json:"tipo"`
+	Titulo   string         `json:"titulo"`
+	Mensagem string         `json:"mensagem"`
+	Dados    map[string]any `json:"dados"`
+	Lida     bool           `json:"lida"`
+	LidaEm   *time.Time     `json:"lida_em,omitempty"`
+	CriadoEm time.Time      `json:"criado_em"`
+}
+
+// ListaNotificacoes é a resposta de GET /api/notificacoes (nao_lidas alimenta o contador do sino).
+type ListaNotificacoes struct {
+	NaoLidas int           `json:"nao_lidas"`
+	Itens    []Notificacao `json:"itens"`
+}
+
+// NovaNotificacao é o que um produtor informa para notificar um usuário.
+type NovaNotificacao struct {
+	Tipo     string
+	Titulo   string
+	Mensagem string
+	Dados    map[string]any
+	Chave    string // opcional: deduplica o evento por usuário (ex.: "aniversario:2026-03-14:42")
+}
+
+/// ============ Configurações & Constantes ============
+
+// Tipos de notificação.
+const (
+	NotificacaoImportacaoConcluida = "importacao.concluida"
+	NotificacaoAniversario         = "estudante.aniversario"
+	NotificacaoExportacaoPronta    = "exportacao.pronta"
+)
+
+/// ============ Funções Públicas ============
+
+// CriarNotificacao grava a notificação para o usuário. Com n.Chave repetida para
+// o mesmo usuário nada é gravado (criada=false, sem erro).
+func CriarNotificacao(ctx context.Context, db *sql.DB, usuarioID int, n NovaNotificacao) (criada bool, err error) {
+	dados := n.Dados
+	if dados == nil {
+		dados = map[string]any{}
+	}
+	payload, err := json.Marshal(dados)
+	if err != nil {
+		return false, err
+	}
+	res, err := db.ExecContext(ctx, `
+		INSERT INTO notificacoes (usuario_id, tipo, titulo, mensagem, dados, chave)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''))
+		ON CONFLICT (usuario_id, chave) DO NOTHING
+	`, usuarioID, n.Tipo, n.Titulo, n.Mensagem, string(payload), n.Chave)
+	if err != nil {
+		return false, err
+	}
+	linhas, _ := res.RowsAffected()
+	return linhas > 0, nil
+}
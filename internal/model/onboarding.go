@@ -0,0 +1,168 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/model/onboarding.go
+/// Responsabilidade: Checklist de onboarding da conta (tabela `onboarding_etapas`): catálogo de etapas, detecção automática a partir dos dados e marcação das etapas manuais.
+/// Dependências principais: context, database/sql, errors, time.
+/// Pontos de atenção:
+/// - Etapas automáticas (ano, estudante, foto) são detectadas em CarregarOnboarding e gravadas; concluída, a etapa não volta atrás.
+/// - Ano e estudante contam os dados do tenant (numa organização, os dados da escola); tutorial e foto são da própria conta.
+/// - A etapa "tutorial" espelha tutorial_visto: marcá-la grava o flag (compatibilidade com PUT /api/usuario/{id}/tutorial).
+*/
+
+package model
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// DefinicaoEtapa descreve uma etapa do onboarding no catálogo.
+type DefinicaoEtapa struct {
+	ID         string
+	Titulo     string
+	Automatica bool // detectada a partir dos dados (não pode ser marcada pela API)
+}
+
+// EtapaOnboarding é uma etapa na resposta de GET /api/usuario/onboarding.
+type EtapaOnboarding struct {
+	ID          string     `json:"id"`
+	Titulo      string     `json:"titulo"`
+	Automatica  bool       `json:"automatica"`
+	Concluida   bool       `json:"concluida"`
+	ConcluidaEm *time.Time `json:"concluida_em"`
+}
+
+// ProgressoOnboarding é a resposta de GET /api/usuario/onboarding.
+type ProgressoOnboarding struct {
+	Etapas     []EtapaOnboarding `json:"etapas"`
+	Concluidas int               `json:"concluidas"`
+	Total      int               `json:"total"`
+	Completo   bool              `json:"completo"`
+}
+
+/// ============ Configurações & Constantes ============
+
+// Etapas do onboarding.
+const (
+	EtapaTutorial          = "tutorial"
+	EtapaPrimeiroAno       = "primeiro_ano"
+	EtapaPrimeiroEstudante = "primeiro_estudante"
+	EtapaFotoPerfil        = "foto_perfil"
+)
+
+// EtapasOnboarding é o catálogo, na ordem de exibição.
+var EtapasOnboarding = []DefinicaoEtapa{
+	{ID: EtapaTutorial, Titulo: "Ver o tutorial"},
+	{ID: EtapaPrimeiroAno, Titulo: "Criar o primeiro ano/turma", Automatica: true},
+	{ID: EtapaPrimeiroEstudante, Titulo: "Cadastrar o primeiro estudante", Automatica: true},
+	{ID: EtapaFotoPerfil, Titulo: "Adicionar foto de perfil", Automatica: true},
+}
+
+var (
+	ErrEtapaDesconhecida = errors.New("etapa de onboarding desconhecida")
+	ErrEtapaAutomatica   = errors.New("etapa concluída automaticamente a partir dos dados")
+)
+
+/// ============ Funções Públicas ============
+
+// CarregarOnboarding grava as etapas automáticas já cumpridas e devolve o progresso da conta.
+func CarregarOnboarding(ctx context.Context, db *sql.DB, acesso Acesso) (ProgressoOnboarding, error) {
+	if _, err := db.ExecContext(ctx, `
+		INSERT INTO onboarding_etapas (usuario_id, etapa)
+		SELECT $1, d.etapa
+		  FROM (VALUES
+		        ($3, EXISTS(SELECT 1 FROM anos WHERE usuario_id = $2)),
+		        ($4, EXISTS(SELECT 1 FROM estudantes WHERE usuario_id = $2)),
+		        ($5, EXISTS(SELECT 1 FROM usuarios WHERE id = $1 AND COALESCE(foto_url, '') <> ''))
+		       ) AS d(etapa, cumprida)
+		 WHERE d.cumprida
+		ON CONFLICT DO NOTHING
+	`, acesso.UsuarioID, acesso.TenantID, EtapaPrimeiroAno, EtapaPrimeiroEstudante, EtapaFotoPerfil); err != nil {
+		return ProgressoOnboarding{}, err
+	}
+
+	rows, err := db.QueryContext(ctx,
+		`SELECT etapa, concluida_em FROM onboarding_etapas WHERE usuario_id = $1`, acesso.UsuarioID)
+	if err != nil {
+		return ProgressoOnboarding{}, err
+	}
+	defer rows.Close()
+	concluidas := map[string]time.Time{}
+	for rows.Next() {
+		var (
+			etapa string
+			em    time.Time
+		)
+		if err := rows.Scan(&etapa, &em); err != nil {
+			return ProgressoOnboarding{}, err
+		}
+		concluidas[etapa] = em
+	}
+	if err := rows.Err(); err != nil {
+		return ProgressoOnboarding{}, err
+	}
+
+	out := ProgressoOnboarding{Etapas: make([]EtapaOnboarding, 0, len(EtapasOnboarding)), Total: len(EtapasOnboarding)}
+	for _, d := range EtapasOnboarding {
+		e := EtapaOnboarding{ID: d.ID, Titulo: d.Titulo, Automatica: d.Automatica}
+		if em, ok := concluidas[d.ID]; ok {
+			e.Concluida, e.ConcluidaEm = true, &em
+			out.Concluidas++
+		}
+		out.Etapas = append(out.Etapas, e)
+	}
+	out.Completo = out.Concluidas == out.Total
+	return out, nil
+}
+
+// ConcluirEtapa marca uma etapa manual da conta (idempotente).
+// ErrEtapaDesconhecida ou ErrEtapaAutomatica conforme o catálogo.
+func ConcluirEtapa(ctx context.Context, db *sql.DB, usuarioID int, etapa string) error {
+	def, ok := definicaoEtapa(etapa)
+	if !ok {
+		return ErrEtapaDesconhecida
+	}
+	if def.Automatica {
+		return ErrEtapaAutomatica
+	}
+	return ComTransacao(ctx, db, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO onboarding_etapas (usuario_id, etapa) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+			usuarioID, etapa,
+		); err != nil {
+			return err
+		}
+		if etapa == EtapaTutorial {
+			_, err := tx.ExecContext(ctx, `UPDATE usuarios SET tutorial_visto = TRUE WHERE id = $1`, usuarioID)
+			return err
+		}
+		return nil
+	})
+}
+
+// SincronizarTutorial acompanha PUT /api/usuario/{id}/tutorial: visto=true conclui a
+// etapa "tutorial"; false a reabre (o tutorial volta a ser exibido).
+func SincronizarTutorial(ctx context.Context, db *sql.DB, usuarioID int, visto bool) error {
+	query := `DELETE FROM onboarding_etapas WHERE usuario_id = $1 AND etapa = $2`
+	if visto {
+		query = `INSERT INTO onboarding_etapas (usuario_id, etapa) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+	}
+	_, err := db.ExecContext(ctx, query, usuarioID, EtapaTutorial)
+	return err
+}
+
+/// ============ Funções Internas (helpers) ============
+
+// definicaoEtapa procura a etapa no catálogo.
+func definicaoEtapa(id string) (DefinicaoEtapa, bool) {
+	for _, d := range EtapasOnboarding {
+		if d.ID == id {
+			return d, true
+		}
+	}
+	return DefinicaoEtapa{}, false
+}
@@ -0,0 +1,134 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/model/organizacao.go
+/// Responsabilidade: Organizações (escolas) com membros e papéis; resolve, a partir do e-mail autenticado, de quem são os dados acessados (tenant) e com qual papel.
+/// Dependências principais: context, database/sql, errors, net/mail, strings.
+/// Pontos de atenção:
+/// - Os dados continuam gravados com usuario_id = dono da organização; membros "enxergam" o dono via ResolverAcesso.
+/// - Usuário sem organização é dono dos próprios dados (papel admin implícito).
+/// - Cada usuário participa de no máximo uma organização (UNIQUE em organizacao_membros.usuario_id).
+/// - Ao entrar numa organização, os dados antigos do próprio usuário deixam de ser visíveis (não há migração automática).
+*/
+
+package model
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/mail"
+	"strings"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// Organizacao representa um registro da tabela `organizacoes`.
+type Organizacao struct {
+	ID      int      `json:"id"`
+	Nome    string   `json:"nome"`
+	DonoID  int      `json:"dono_id"`
+	Membros []Membro `json:"membros,omitempty"`
+}
+
+// Membro é um usuário vinculado à organização com um papel.
+type Membro struct {
+	UsuarioID int    `json:"usuario_id"`
+	Nome      string `json:"nome"`
+	Email     string `json:"email"`
+	Papel     string `json:"papel"`
+}
+
+// Acesso descreve quem está chamando a API e sobre quais dados atua.
+type Acesso struct {
+	UsuarioID        int    // usuário autenticado (X-User-Email)
+	Email            string // e-mail normalizado do usuário autenticado
+	TenantID         int    // usuario_id usado para escopo dos dados (dono da organização)
+	OrganizacaoID    int    // 0 quando o usuário não pertence a organização
+	Papel            string // admin | editor | leitor
+	Suporte          bool   // equipe de suporte da plataforma (rotas /api/admin); independe do papel
+	PersonificadoPor int    // suporte atuando como este usuário (X-Impersonation-Token); 0 = acesso direto
+	SessaoID         int64  // sessão (Authorization: Bearer) da requisição; 0 = sem sessão
+}
+
+// OrganizacaoRequest é o payload de POST /api/organizacao.
+type OrganizacaoRequest struct {
+	Nome string `json:"nome"`
+}
+
+// MembroRequest é o payload de POST/PUT /api/organizacao/membros.
+type MembroRequest struct {
+	Email string `json:"email"`
+	Papel string `json:"papel"`
+}
+
+/// ============ Configurações & Constantes ============
+
+const (
+	PapelAdmin  = "admin"  // gerencia membros e dados
+	PapelEditor = "editor" // lê e altera dados
+	PapelLeitor = "leitor" // somente leitura
+)
+
+var papeisValidos = map[string]bool{PapelAdmin: true, PapelEditor: true, PapelLeitor: true}
+
+var (
+	ErrPapelInvalido         = errors.New("papel inválido (admin, editor, leitor)")
+	ErrNomeOrganizacao       = errors.New("nome da organização é obrigatório")
+	ErrSemPermissao          = errors.New("permissão insuficiente para esta operação")
+	ErrJaPertenceOrganizacao = errors.New("usuário já pertence a uma organização")
+)
+
+/// ============ Funções Públicas ============
+
+// PapelValido informa se p é um papel conhecido.
+func PapelValido(p string) bool { return papeisValidos[p] }
+
+// PodeEscrever informa se o papel permite alterar dados (POST/PUT/DELETE).
+func (a Acesso) PodeEscrever() bool { return a.Papel != PapelLeitor }
+
+// Admin informa se o papel permite gerenciar a organização.
+func (a Acesso) Admin() bool { return a.Papel == PapelAdmin }
+
+// Sanitize faz trim do nome.
+func (o *OrganizacaoRequest) Sanitize() { o.Nome = strings.TrimSpace(o.Nome) }
+
+// Validate exige nome.
+func (o OrganizacaoRequest) Validate() error {
+	if o.Nome == "" {
+		return ErrNomeOrganizacao
+	}
+	return nil
+}
+
+// Sanitize normaliza e-mail e papel (minúsculas, trim).
+func (m *MembroRequest) Sanitize() {
+	m.Email = NormalizarEmail(m.Email)
+	m.Papel = strings.ToLower(strings.TrimSpace(m.Papel))
+}
+
+// Validate exige e-mail válido e papel conhecido.
+func (m MembroRequest) Validate() error {
+	if _, err := mail.ParseAddress(m.Email); err != nil {
+		return ErrEmailInvalido
+	}
+	if !PapelValido(m.Papel) {
+		return ErrPapelInvalido
+	}
+	return nil
+}
+
+// ResolverAcesso busca o usuário pelo e-mail (NormalizarEmail; usuarios.email é CITEXT) e, se
+// ele for membro de uma organização, devolve o dono dela como tenant. Retorna sql.ErrNoRows se o
+// e-mail não existir ou a conta estiver bloqueada (bloqueado_em).
+func ResolverAcesso(ctx context.Context, db *sql.DB, email string) (Acesso, error) {
+	var a Acesso
+	err := db.QueryRowContext(ctx, `
+		SELECT u.id, COALESCE(o.dono_id, u.id), COALESCE(o.id, 0), COALESCE(m.papel, $2), u.suporte
+		  FROM usuarios u
+		  LEFT JOIN organizacao_membros m ON m.usuario_id = u.id
+		  LEFT JOIN organizacoes o ON o.id = m.organizacao_id
+		 WHERE u.email = $1 AND u.bloqueado_em IS NULL
+	`, NormalizarEmail(email), PapelAdmin).Scan(&a.UsuarioID, &a.TenantID, &a.OrganizacaoID, &a.Papel, &a.Suporte)
+	a.Email = NormalizarEmail(email)
+	return a, err
+}
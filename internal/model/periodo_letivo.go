@@ -0,0 +1,77 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/model/periodo_letivo.go
+/// Responsabilidade: Entidade Período Letivo (ano escolar, ex.: "2025") que agrupa anos/turmas, frequência e notas, com DTOs de criação e clonagem.
+/// Dependências principais: errors, strings, time.
+/// Pontos de atenção:
+/// - Não confundir com Avaliacao.Periodo (bimestre/trimestre em texto livre).
+/// - Período fechado bloqueia chamada e lançamento de notas nos anos vinculados (409 nos handlers).
+/// - Datas de início/fim são opcionais; quando ambas vierem, fim >= início.
+*/
+
+package model
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// PeriodoLetivo representa um registro da tabela `periodos_letivos`.
+type PeriodoLetivo struct {
+	ID      int    `json:"id"`
+	Nome    string `json:"nome"`
+	Inicio  string `json:"inicio,omitempty"` // YYYY-MM-DD
+	Fim     string `json:"fim,omitempty"`    // YYYY-MM-DD
+	Fechado bool   `json:"fechado"`
+}
+
+// PeriodoLetivoRequest é o payload de criação (POST /api/periodos) e de
+// clonagem (POST /api/periodos/{id}/clonar).
+type PeriodoLetivoRequest struct {
+	Nome   string `json:"nome"`
+	Inicio string `json:"inicio"`
+	Fim    string `json:"fim"`
+}
+
+/// ============ Configurações & Constantes ============
+
+var (
+	ErrPeriodoNomeObrigatorio = errors.New("nome do período letivo é obrigatório")
+	ErrPeriodoDatas           = errors.New("datas do período inválidas (YYYY-MM-DD e fim >= início)")
+	ErrPeriodoFechado         = errors.New("período letivo encerrado")
+)
+
+/// ============ Funções Públicas ============
+
+// Sanitize faz trim dos campos.
+func (p *PeriodoLetivoRequest) Sanitize() {
+	p.Nome = strings.TrimSpace(p.Nome)
+	p.Inicio = strings.TrimSpace(p.Inicio)
+	p.Fim = strings.TrimSpace(p.Fim)
+}
+
+// Validate exige nome e confere as datas (quando enviadas).
+func (p PeriodoLetivoRequest) Validate() error {
+	if p.Nome == "" {
+		return ErrPeriodoNomeObrigatorio
+	}
+	var ini, fim time.Time
+	var err error
+	if p.Inicio != "" {
+		if ini, err = time.Parse(dateLayoutISO, p.Inicio); err != nil {
+			return ErrPeriodoDatas
+		}
+	}
+	if p.Fim != "" {
+		if fim, err = time.Parse(dateLayoutISO, p.Fim); err != nil {
+			return ErrPeriodoDatas
+		}
+	}
+	if !ini.IsZero() && !fim.IsZero() && fim.Before(ini) {
+		return ErrPeriodoDatas
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/model/personificacao.go
+/// Responsabilidade: Sessões de personificação do suporte (tabela `personificacoes`): emissão do token curto (POST /api/admin/impersonate) e sua resolução a cada requisição.
+/// Dependências principais: context, database/sql, errors, strings, time, unicode/utf8.
+/// Pontos de atenção:
+/// - Token no mesmo esquema dos convites (NovoTokenConvite): aleatório, devolvido uma única vez; o banco guarda só o hash.
+/// - Não se personifica conta de suporte nem conta bloqueada; o token deixa de valer se quem o emitiu perder o suporte ou for bloqueado.
+/// - Não há revogação: a sessão vale até expira_em (PersonificacaoTTL). O registro fica como histórico.
+*/
+
+package model
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// PersonificarRequest é o payload de POST /api/admin/impersonate.
+type PersonificarRequest struct {
+	UsuarioID int    `json:"usuario_id"`
+	Motivo    string `json:"motivo"` // ex.: número do chamado; fica no histórico
+}
+
+// Personificacao é a resposta de POST /api/admin/impersonate (token exibido uma única vez).
+type Personificacao struct {
+	ID        int64     `json:"id"`
+	UsuarioID int       `json:"usuario_id"`
+	Email     string    `json:"email"`
+	Token     string    `json:"token"` // enviar em X-Impersonation-Token
+	ExpiraEm  time.Time `json:"expira_em"`
+}
+
+/// ============ Configurações & Constantes ============
+
+// PersonificacaoTTL é a validade de um token de personificação a partir da emissão.
+const PersonificacaoTTL = 30 * time.Minute
+
+// tamanho máximo do motivo (caracteres)
+const motivoPersonificacaoMaximo = 500
+
+var (
+	ErrUsuarioPersonificacao  = errors.New("usuario_id é obrigatório")
+	ErrMotivoPersonificacao   = errors.New("motivo é obrigatório (até 500 caracteres)")
+	ErrPersonificarSuporte    = errors.New("não é possível personificar uma conta da equipe de suporte")
+	ErrPersonificarBloqueado  = errors.New("conta bloqueada não pode ser personificada")
+	ErrPersonificacaoInvalida = errors.New("token de personificação inválido ou expirado")
+)
+
+/// ============ Funções Públicas ============
+
+// Sanitize faz trim do motivo.
+func (p *PersonificarRequest) Sanitize() { p.Motivo = strings.TrimSpace(p.Motivo) }
+
+// Validate exige usuário e motivo.
+func (p PersonificarRequest) Validate() error {
+	if p.UsuarioID <= 0 {
+		return ErrUsuarioPersonificacao
+	}
+	if p.Motivo == "" || utf8.RuneCountInString(p.Motivo) > motivoPersonificacaoMaximo {
+		return ErrMotivoPersonificacao
+	}
+	return nil
+}
+
+// CriarPersonificacao emite um token para suporteID atuar como in.UsuarioID.
+// ErrUsuarioNaoEncontrado, ErrPersonificarSuporte ou ErrPersonificarBloqueado conforme a conta alvo.
+func CriarPersonificacao(ctx context.Context, db *sql.DB, suporteID int, in PersonificarRequest) (Personificacao, error) {
+	token, hash, err := NovoTokenConvite()
+	if err != nil {
+		return Personificacao{}, err
+	}
+	p := Personificacao{UsuarioID: in.UsuarioID, Token: token, ExpiraEm: time.Now().Add(PersonificacaoTTL)}
+
+	err = ComTransacao(ctx, db, func(tx *sql.Tx) error {
+		var suporte, bloqueado bool
+		if err := tx.QueryRowContext(ctx,
+			`SELECT email, suporte, bloqueado_em IS NOT NULL FROM usuarios WHERE id = $1`, in.UsuarioID,
+		).Scan(&p.Email, &suporte, &bloqueado); err != nil {
+			return err
+		}
+		switch {
+		case suporte:
+			return ErrPersonificarSuporte
+		case bloqueado:
+			return ErrPersonificarBloqueado
+		}
+		return tx.QueryRowContext(ctx, `
+			INSERT INTO personificacoes (suporte_id, usuario_id, token_hash, motivo, expira_em)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id
+		`, suporteID, in.UsuarioID, hash, in.Motivo, p.ExpiraEm).Scan(&p.ID)
+	})
+	if err == sql.ErrNoRows {
+		return Personificacao{}, ErrUsuarioNaoEncontrado
+	}
+	if err != nil {
+		return Personificacao{}, err
+	}
+	return p, nil
+}
+
+// ResolverPersonificacao devolve quem emitiu o token e o e-mail da conta personificada.
+// ErrPersonificacaoInvalida se o token não existir, tiver expirado ou o emissor não for mais suporte ativo.
+func ResolverPersonificacao(ctx context.Context, db *sql.DB, token string) (suporteID int, email string, err error) {
+	err = db.QueryRowContext(ctx, `
+		SELECT p.suporte_id, u.email
+		  FROM personificacoes p
+		  JOIN usuarios u ON u.id = p.usuario_id
+		  JOIN usuarios s ON s.id = p.suporte_id
+		 WHERE p.token_hash = $1
+		   AND p.expira_em > NOW()
+		   AND s.suporte AND s.bloqueado_em IS NULL
+	`, HashTokenConvite(token)).Scan(&suporteID, &email)
+	if err == sql.ErrNoRows {
+		return 0, "", ErrPersonificacaoInvalida
+	}
+	return suporteID, email, err
+}
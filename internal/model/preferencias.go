@@ -0,0 +1,164 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/model/preferencias.go
+/// Responsabilidade: Preferências de interface da conta (coluna usuarios.preferencias, JSONB): tema, ano/turma padrão e colunas visíveis das tabelas.
+/// Dependências principais: context, database/sql, encoding/json, errors, regexp, sort.
+/// Pontos de atenção:
+/// - PUT substitui o documento inteiro; campos omitidos voltam ao padrão (Normalizar).
+/// - O formato é validado aqui (Validate); o banco só exige um objeto JSON.
+/// - ano_padrao é conferido contra os anos ativos do tenant na gravação e na leitura (ano removido → null).
+*/
+
+package model
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"sort"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// Preferencias é o payload/resposta de GET/PUT /api/usuario/preferencias.
+type Preferencias struct {
+	Tema      string              `json:"tema"`       // claro | escuro | sistema (padrão)
+	AnoPadrao *int                `json:"ano_padrao"` // ano/turma aberto ao entrar; null = nenhum
+	Colunas   map[string][]string `json:"colunas"`    // tabela (estudantes, anos) → colunas visíveis, na ordem
+}
+
+/// ============ Configurações & Constantes ============
+
+// Temas aceitos.
+const (
+	TemaClaro   = "claro"
+	TemaEscuro  = "escuro"
+	TemaSistema = "sistema"
+)
+
+// tabelas com colunas configuráveis e limite de colunas por tabela
+var tabelasPreferencias = map[string]bool{"estudantes": true, "anos": true}
+
+const colunasPreferenciasMaximo = 30
+
+// nome de coluna: identificador snake_case curto (o frontend decide quais existem)
+var reColunaPreferencia = regexp.MustCompile(`^[a-z][a-z0-9_]{0,39}$`)
+
+var (
+	ErrTemaInvalido         = errors.New("tema inválido (claro, escuro, sistema)")
+	ErrAnoPadraoInvalido    = errors.New("ano_padrao não encontrado")
+	ErrTabelaColunas        = errors.New("tabela desconhecida em colunas (estudantes, anos)")
+	ErrColunasPreferencias  = errors.New("colunas inválidas: nomes snake_case, sem repetição, até 30 por tabela")
+	ErrPreferenciasInvalida = errors.New("preferências gravadas em formato inválido")
+)
+
+/// ============ Funções Públicas ============
+
+// Normalizar preenche os padrões dos campos omitidos.
+func (p *Preferencias) Normalizar() {
+	if p.Tema == "" {
+		p.Tema = TemaSistema
+	}
+	if p.Colunas == nil {
+		p.Colunas = map[string][]string{}
+	}
+}
+
+// Validate confere o formato (a existência de ano_padrao é conferida em SalvarPreferencias).
+func (p Preferencias) Validate() error {
+	var ev ErrosValidacao
+	switch p.Tema {
+	case TemaClaro, TemaEscuro, TemaSistema:
+	default:
+		ev.Add("tema", RegraFormato, ErrTemaInvalido)
+	}
+	if p.AnoPadrao != nil && *p.AnoPadrao <= 0 {
+		ev.Add("ano_padrao", RegraFormato, ErrAnoPadraoInvalido)
+	}
+	tabelas := make([]string, 0, len(p.Colunas))
+	for tabela := range p.Colunas {
+		tabelas = append(tabelas, tabela)
+	}
+	sort.Strings(tabelas) // erros em ordem estável
+	for _, tabela := range tabelas {
+		colunas := p.Colunas[tabela]
+		if !tabelasPreferencias[tabela] {
+			ev.Add("colunas."+tabela, RegraFormato, ErrTabelaColunas)
+			continue
+		}
+		if !colunasValidas(colunas) {
+			ev.Add("colunas."+tabela, RegraFormato, ErrColunasPreferencias)
+		}
+	}
+	return ev.Err()
+}
+
+// CarregarPreferencias lê as preferências de usuarioID com os padrões aplicados;
+// ano_padrao vira null se o ano não estiver mais ativo no tenant.
+func CarregarPreferencias(ctx context.Context, db *sql.DB, usuarioID, tenantID int) (Preferencias, error) {
+	var (
+		bruto    []byte
+		anoAtivo bool
+	)
+	err := db.QueryRowContext(ctx, `
+		SELECT u.preferencias,
+		       EXISTS(SELECT 1 FROM anos a
+		               WHERE a.id = (u.preferencias->>'ano_padrao')::int
+		                 AND a.usuario_id = $2 AND a.excluido_em IS NULL)
+		  FROM usuarios u
+		 WHERE u.id = $1
+	`, usuarioID, tenantID).Scan(&bruto, &anoAtivo)
+	if err != nil {
+		return Preferencias{}, err
+	}
+	var p Preferencias
+	if err := json.Unmarshal(bruto, &p); err != nil {
+		return Preferencias{}, ErrPreferenciasInvalida
+	}
+	if !anoAtivo {
+		p.AnoPadrao = nil
+	}
+	p.Normalizar()
+	return p, nil
+}
+
+// SalvarPreferencias grava p (já validado) como preferências de usuarioID.
+// ErrAnoPadraoInvalido se ano_padrao não for um ano ativo do tenant.
+func SalvarPreferencias(ctx context.Context, db *sql.DB, usuarioID, tenantID int, p Preferencias) error {
+	payload, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	res, err := db.ExecContext(ctx, `
+		UPDATE usuarios SET preferencias = $2
+		 WHERE id = $1
+		   AND ($3::int IS NULL OR EXISTS(
+		        SELECT 1 FROM anos WHERE id = $3 AND usuario_id = $4 AND excluido_em IS NULL))
+	`, usuarioID, string(payload), p.AnoPadrao, tenantID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrAnoPadraoInvalido
+	}
+	return nil
+}
+
+/// ============ Funções Internas (helpers) ============
+
+// colunasValidas exige nomes no formato de reColunaPreferencia, sem repetição e até o limite.
+func colunasValidas(colunas []string) bool {
+	if len(colunas) > colunasPreferenciasMaximo {
+		return false
+	}
+	vistas := make(map[string]bool, len(colunas))
+	for _, c := range colunas {
+		if !reColunaPreferencia.MatchString(c) || vistas[c] {
+			return false
+		}
+		vistas[c] = true
+	}
+	return true
+}
@@ -0,0 +1,72 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/model/preparadas.go
+/// Responsabilidade: Prepared statements reutilizáveis para as consultas mais frequentes dos repositórios (listar/criar estudante, login).
+/// Dependências principais: context, database/sql, fmt.
+/// Pontos de atenção:
+/// - Preparar roda na inicialização do servidor: SQL inválido ou coluna inexistente (migration pendente) falha ali, não na primeira requisição.
+/// - *sql.Stmt é seguro para uso concorrente; o database/sql o prepara de novo em cada conexão do pool sob demanda.
+/// - Repositório não preparado (ex.: subcomandos da CLI) executa a mesma query direto no *sql.DB.
+/// - Atrás de PgBouncer em modo transaction, prepared statements não funcionam: não chame Preparar nesse cenário.
+*/
+
+package model
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// preparadas mapeia o texto da query para o statement preparado.
+type preparadas map[string]*sql.Stmt
+
+/// ============ Funções Internas (helpers) ============
+
+// preparar prepara todas as queries; em erro fecha as já preparadas.
+func preparar(ctx context.Context, db *sql.DB, queries ...string) (preparadas, error) {
+	p := make(preparadas, len(queries))
+	for _, q := range queries {
+		st, err := db.PrepareContext(ctx, q)
+		if err != nil {
+			p.fechar()
+			return nil, fmt.Errorf("preparar %q: %w", resumoQuery(q), err)
+		}
+		p[q] = st
+	}
+	return p, nil
+}
+
+// query executa q pelo statement preparado, se houver, ou direto no banco.
+func (p preparadas) query(ctx context.Context, db *sql.DB, q string, args ...any) (*sql.Rows, error) {
+	if st, ok := p[q]; ok {
+		return st.QueryContext(ctx, args...)
+	}
+	return db.QueryContext(ctx, q, args...)
+}
+
+// queryRow é o equivalente de query para uma linha.
+func (p preparadas) queryRow(ctx context.Context, db *sql.DB, q string, args ...any) *sql.Row {
+	if st, ok := p[q]; ok {
+		return st.QueryRowContext(ctx, args...)
+	}
+	return db.QueryRowContext(ctx, q, args...)
+}
+
+func (p preparadas) fechar() {
+	for _, st := range p {
+		_ = st.Close()
+	}
+}
+
+// resumoQuery encurta a query para mensagens de erro (espaços colapsados, até 60 caracteres).
+func resumoQuery(q string) string {
+	q = strings.Join(strings.Fields(q), " ")
+	if len(q) > 60 {
+		q = q[:60] + "..."
+	}
+	return q
+}
@@ -0,0 +1,102 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/model/presenca.go
+/// Responsabilidade: DTOs e validação do registro de presença (chamada diária por Ano/Turma).
+/// Dependências principais: errors, strings, time.
+/// Pontos de atenção:
+/// - A data da chamada usa o mesmo layout ISO (YYYY-MM-DD) de data_nascimento.
+/// - Datas futuras são rejeitadas (chamada só pode ser lançada para hoje ou dias passados).
+/// - Um mesmo estudante não pode aparecer duas vezes na mesma chamada.
+*/
+
+package model
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// PresencaItem é a marcação de um estudante dentro da chamada.
+type PresencaItem struct {
+	EstudanteID int    `json:"estudante_id"`
+	Presente    bool   `json:"presente"`
+	Observacao  string `json:"observacao,omitempty"`
+}
+
+// ChamadaRequest é o payload de POST /api/turmas/{id}/chamada.
+type ChamadaRequest struct {
+	Data      string         `json:"data"` // YYYY-MM-DD
+	Presencas []PresencaItem `json:"presencas"`
+}
+
+// Presenca é o registro persistido (retorno de GET /api/estudantes/{id}/presencas).
+type Presenca struct {
+	ID          int    `json:"id"`
+	EstudanteID int    `json:"estudante_id"`
+	AnoID       int    `json:"ano_id"`
+	Data        string `json:"data"`
+	Presente    bool   `json:"presente"`
+	Observacao  string `json:"observacao,omitempty"`
+}
+
+// ResumoPresenca agrega totais de um período (ou do histórico completo).
+type ResumoPresenca struct {
+	Periodo    string  `json:"periodo,omitempty"` // ex.: "2025-03" quando agrupado por mês
+	Total      int     `json:"total"`
+	Presentes  int     `json:"presentes"`
+	Faltas     int     `json:"faltas"`
+	Percentual float64 `json:"percentual"` // 0–100, duas casas
+}
+
+/// ============ Configurações & Constantes ============
+
+var (
+	ErrChamadaVazia       = errors.New("a chamada precisa de ao menos um estudante")
+	ErrDataChamada        = errors.New("data inválida (esperado YYYY-MM-DD, sem datas futuras)")
+	ErrEstudanteRepetido  = errors.New("estudante repetido na lista")
+	ErrEstudanteIDChamada = errors.New("estudante_id inválido na chamada")
+)
+
+/// ============ Funções Públicas ============
+
+// Sanitize faz trim da data e das observações.
+func (c *ChamadaRequest) Sanitize() {
+	c.Data = strings.TrimSpace(c.Data)
+	for i := range c.Presencas {
+		c.Presencas[i].Observacao = strings.TrimSpace(c.Presencas[i].Observacao)
+	}
+}
+
+// Validate exige data ISO não futura, lista não vazia e estudantes distintos.
+func (c ChamadaRequest) Validate() error {
+	d, err := time.Parse(dateLayoutISO, c.Data)
+	if err != nil || d.After(time.Now()) {
+		return ErrDataChamada
+	}
+	if len(c.Presencas) == 0 {
+		return ErrChamadaVazia
+	}
+	vistos := make(map[int]bool, len(c.Presencas))
+	for _, p := range c.Presencas {
+		if p.EstudanteID <= 0 {
+			return ErrEstudanteIDChamada
+		}
+		if vistos[p.EstudanteID] {
+			return ErrEstudanteRepetido
+		}
+		vistos[p.EstudanteID] = true
+	}
+	return nil
+}
+
+// NovoResumoPresenca calcula faltas e percentual a partir dos totais.
+func NovoResumoPresenca(periodo string, total, presentes int) ResumoPresenca {
+	r := ResumoPresenca{Periodo: periodo, Total: total, Presentes: presentes, Faltas: total - presentes}
+	if total > 0 {
+		r.Percentual = float64(presentes*10000/total) / 100
+	}
+	return r
+}
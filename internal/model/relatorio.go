@@ -0,0 +1,220 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/model/relatorio.go
+/// Responsabilidade: Especificação declarativa de relatórios de estudantes (agrupamentos, métricas, filtros e intervalos de datas) e sua tradução para SQL parametrizado.
+/// Dependências principais: errors, fmt, strings, Array (driver.go).
+/// Pontos de atenção:
+/// - Agrupamentos e métricas vêm de listas fechadas (nunca do texto do usuário); valores de filtro sempre como parâmetros ($n).
+/// - Estudantes excluídos (soft delete) nunca entram; a idade é calculada na data de hoje do banco.
+/// - Sem agrupamento, o relatório tem uma única linha com os totais.
+*/
+
+package model
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// RelatorioSpec é o payload de POST /api/relatorios.
+type RelatorioSpec struct {
+	AgruparPor []string           `json:"agrupar_por"` // ano | turma | status (na ordem das colunas)
+	Metricas   []string           `json:"metricas"`    // quantidade | idade_media | idade_minima | idade_maxima
+	Filtros    RelatorioFiltros   `json:"filtros"`
+	Periodos   []RelatorioPeriodo `json:"periodos"` // intervalos de datas (todos aplicados)
+}
+
+// RelatorioFiltros restringe os estudantes considerados (listas vazias = sem filtro).
+type RelatorioFiltros struct {
+	Status  []string `json:"status"`
+	AnoID   []int    `json:"ano_id"`
+	TurmaID []int    `json:"turma_id"`
+}
+
+// RelatorioPeriodo é um intervalo fechado (YYYY-MM-DD) sobre uma coluna de data.
+type RelatorioPeriodo struct {
+	Campo string `json:"campo"` // data_nascimento | atualizado_em
+	De    string `json:"de"`    // opcional
+	Ate   string `json:"ate"`   // opcional (inclusive)
+}
+
+// Relatorio é o resultado tabular (mesma ordem em Colunas e em cada linha).
+type Relatorio struct {
+	Colunas []string `json:"colunas"`
+	Linhas  [][]any  `json:"linhas"`
+}
+
+/// ============ Configurações & Constantes ============
+
+// colunas de cada agrupamento (expressões fixas; "ano" traz id e nome)
+var relatorioGrupos = map[string][]colunaRelatorio{
+	"ano":    {{"ano_id", "e.ano_id"}, {"ano", "a.nome"}},
+	"turma":  {{"turma_id", "e.turma_id"}},
+	"status": {{"status", "e.status"}},
+}
+
+// idade completa em anos na data de hoje
+const relatorioIdade = `EXTRACT(YEAR FROM age(CURRENT_DATE, e.data_nascimento))`
+
+var relatorioMetricas = map[string]string{
+	"quantidade":   `COUNT(*)`,
+	"idade_media":  `ROUND(AVG(` + relatorioIdade + `)::numeric, 1)::float8`,
+	"idade_minima": `MIN(` + relatorioIdade + `)::int`,
+	"idade_maxima": `MAX(` + relatorioIdade + `)::int`,
+}
+
+var relatorioCamposData = map[string]string{
+	"data_nascimento": "e.data_nascimento",
+	"atualizado_em":   "e.atualizado_em::date",
+}
+
+// limite de valores por filtro de ids
+const relatorioMaxIDs = 500
+
+var (
+	ErrRelatorioGrupo     = errors.New("agrupar_por aceita ano, turma e status (sem repetir)")
+	ErrRelatorioMetrica   = errors.New("metricas aceita quantidade, idade_media, idade_minima e idade_maxima")
+	ErrRelatorioStatus    = errors.New("filtros.status aceita ativo, transferido e formado")
+	ErrRelatorioIDs       = errors.New("filtros com no máximo 500 ids")
+	ErrRelatorioCampoData = errors.New("periodos[].campo aceita data_nascimento e atualizado_em")
+	ErrRelatorioData      = errors.New("periodos[].de/ate devem estar no formato YYYY-MM-DD, com de <= ate")
+)
+
+type colunaRelatorio struct {
+	nome string
+	expr string
+}
+
+/// ============ Funções Públicas ============
+
+// Sanitize normaliza nomes (minúsculos, sem espaços) e aplica a métrica padrão (quantidade).
+func (s *RelatorioSpec) Sanitize() {
+	s.AgruparPor = normalizarLista(s.AgruparPor)
+	s.Metricas = normalizarLista(s.Metricas)
+	if len(s.Metricas) == 0 {
+		s.Metricas = []string{"quantidade"}
+	}
+	s.Filtros.Status = normalizarLista(s.Filtros.Status)
+	for i := range s.Periodos {
+		p := &s.Periodos[i]
+		p.Campo = strings.ToLower(strings.TrimSpace(p.Campo))
+		p.De = strings.TrimSpace(p.De)
+		p.Ate = strings.TrimSpace(p.Ate)
+	}
+}
+
+// Validate confere agrupamentos, métricas, filtros e períodos contra as listas aceitas.
+func (s RelatorioSpec) Validate() error {
+	var ev ErrosValidacao
+	for i, g := range s.AgruparPor {
+		if _, ok := relatorioGrupos[g]; !ok || slices.Contains(s.AgruparPor[:i], g) {
+			ev.Add("agrupar_por", RegraFormato, ErrRelatorioGrupo)
+			break
+		}
+	}
+	for i, m := range s.Metricas {
+		if _, ok := relatorioMetricas[m]; !ok || slices.Contains(s.Metricas[:i], m) {
+			ev.Add("metricas", RegraFormato, ErrRelatorioMetrica)
+			break
+		}
+	}
+	for _, st := range s.Filtros.Status {
+		if !StatusValido(st) {
+			ev.Add("filtros.status", RegraFormato, ErrRelatorioStatus)
+			break
+		}
+	}
+	if len(s.Filtros.AnoID) > relatorioMaxIDs {
+		ev.Add("filtros.ano_id", RegraFormato, ErrRelatorioIDs)
+	}
+	if len(s.Filtros.TurmaID) > relatorioMaxIDs {
+		ev.Add("filtros.turma_id", RegraFormato, ErrRelatorioIDs)
+	}
+	for i, p := range s.Periodos {
+		campo := fmt.Sprintf("periodos[%d]", i)
+		if _, ok := relatorioCamposData[p.Campo]; !ok {
+			ev.Add(campo+".campo", RegraFormato, ErrRelatorioCampoData)
+		}
+		if (p.De != "" && !isValidISODate(p.De)) || (p.Ate != "" && !isValidISODate(p.Ate)) ||
+			(p.De != "" && p.Ate != "" && p.De > p.Ate) {
+			ev.Add(campo, RegraFormato, ErrRelatorioData)
+		}
+	}
+	return ev.Err()
+}
+
+// Colunas devolve os nomes das colunas do resultado (agrupamentos e depois métricas).
+func (s RelatorioSpec) Colunas() []string {
+	var out []string
+	for _, g := range s.AgruparPor {
+		for _, c := range relatorioGrupos[g] {
+			out = append(out, c.nome)
+		}
+	}
+	return append(out, s.Metricas...)
+}
+
+// SQL monta a consulta do relatório para os estudantes do usuário uid.
+// A spec deve ter passado por Sanitize e Validate.
+func (s RelatorioSpec) SQL(uid int) (string, []any) {
+	var sel, grupo []string
+	for _, g := range s.AgruparPor {
+		for _, c := range relatorioGrupos[g] {
+			sel = append(sel, c.expr+` AS `+c.nome)
+			grupo = append(grupo, c.expr)
+		}
+	}
+	for _, m := range s.Metricas {
+		sel = append(sel, relatorioMetricas[m]+` AS `+m)
+	}
+
+	args := []any{uid}
+	where := []string{`e.usuario_id = $1`, `e.excluido_em IS NULL`}
+	param := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	if len(s.Filtros.Status) > 0 {
+		where = append(where, `e.status = ANY(`+param(Array(s.Filtros.Status))+`)`)
+	}
+	if len(s.Filtros.AnoID) > 0 {
+		where = append(where, `e.ano_id = ANY(`+param(Array(s.Filtros.AnoID))+`::int[])`)
+	}
+	if len(s.Filtros.TurmaID) > 0 {
+		where = append(where, `e.turma_id = ANY(`+param(Array(s.Filtros.TurmaID))+`::int[])`)
+	}
+	for _, p := range s.Periodos {
+		col := relatorioCamposData[p.Campo]
+		if p.De != "" {
+			where = append(where, col+` >= `+param(p.De)+`::date`)
+		}
+		if p.Ate != "" {
+			where = append(where, col+` <= `+param(p.Ate)+`::date`)
+		}
+	}
+
+	q := `SELECT ` + strings.Join(sel, ", ") +
+		` FROM estudantes e LEFT JOIN anos a ON a.id = e.ano_id` +
+		` WHERE ` + strings.Join(where, " AND ")
+	if len(grupo) > 0 {
+		q += ` GROUP BY ` + strings.Join(grupo, ", ") + ` ORDER BY ` + strings.Join(grupo, ", ")
+	}
+	return q, args
+}
+
+/// ============ Funções Internas (helpers) ============
+
+// normalizarLista aplica trim/minúsculas e descarta vazios.
+func normalizarLista(xs []string) []string {
+	out := make([]string, 0, len(xs))
+	for _, x := range xs {
+		if x = strings.ToLower(strings.TrimSpace(x)); x != "" {
+			out = append(out, x)
+		}
+	}
+	return out
+}
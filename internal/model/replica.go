@@ -0,0 +1,115 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/model/replica.go
+/// Responsabilidade: Encaminhar consultas pesadas só de leitura (listagens, buscas, relatórios) para a réplica do Postgres (DATABASE_URL_RO), com volta automática ao primário quando ela falha.
+/// Dependências principais: context, database/sql, database/sql/driver, net, sync/atomic, log/slog.
+/// Pontos de atenção:
+/// - Escritas nunca passam por aqui; quem lê para decidir uma escrita (ex.: deduplicar antes de importar) deve ler do primário.
+/// - A réplica pode estar alguns instantes atrás do primário: uma listagem logo após salvar pode ainda não trazer a alteração.
+/// - Falha de conexão (ou réplica recusando conexões/cancelando por conflito de recuperação) põe a réplica em quarentena por replicaQuarentena; nesse meio tempo tudo vai ao primário.
+/// - O fallback só acontece ao abrir a consulta: erro no meio da leitura das linhas é devolvido ao chamador (linhas já entregues não são repetidas).
+/// - Replica nil ou sem DATABASE_URL_RO = tudo no primário.
+*/
+
+package model
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"sync/atomic"
+	"time"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// Replica decide entre a réplica de leitura e o primário para consultas só de leitura.
+type Replica struct {
+	primario *sql.DB
+	ro       *sql.DB    // nil = sem réplica
+	stmts    preparadas // statements preparados na réplica
+	foraAte  atomic.Int64
+}
+
+/// ============ Configurações & Constantes ============
+
+// tempo em que a réplica fica fora depois de uma falha de conexão
+const replicaQuarentena = 30 * time.Second
+
+/// ============ Inicialização/Bootstrap ============
+
+// NovaReplica usa ro para leituras pesadas; ro nil mantém tudo em primario.
+func NovaReplica(primario, ro *sql.DB) *Replica {
+	return &Replica{primario: primario, ro: ro}
+}
+
+/// ============ Funções Públicas ============
+
+// Ativa informa se há réplica configurada.
+func (r *Replica) Ativa() bool { return r != nil && r.ro != nil }
+
+// Preparar prepara queries também na réplica. Falha (réplica fora na subida) só é
+// registrada: as consultas seguem sem prepared statement.
+func (r *Replica) Preparar(ctx context.Context, queries ...string) {
+	if !r.Ativa() {
+		return
+	}
+	p, err := preparar(ctx, r.ro, queries...)
+	if err != nil {
+		slog.Warn("replica: falha ao preparar consultas; seguindo sem prepared statements", "erro", err)
+		return
+	}
+	r.stmts = p
+}
+
+// Query roda q na réplica (ou no primário, se ela estiver fora ou falhar ao conectar).
+func (r *Replica) Query(ctx context.Context, q string, args ...any) (*sql.Rows, error) {
+	return r.query(ctx, nil, q, args...)
+}
+
+/// ============ Funções Internas (helpers) ============
+
+// query usa primarias (statements do primário, pode ser nil) quando cai para o primário.
+func (r *Replica) query(ctx context.Context, primarias preparadas, q string, args ...any) (*sql.Rows, error) {
+	if r.disponivel() {
+		rows, err := r.stmts.query(ctx, r.ro, q, args...)
+		if err == nil || ctx.Err() != nil || !falhaDeConexao(err) {
+			return rows, err
+		}
+		r.afastar(err)
+	}
+	return primarias.query(ctx, r.primario, q, args...)
+}
+
+func (r *Replica) disponivel() bool {
+	return r.Ativa() && time.Now().UnixNano() >= r.foraAte.Load()
+}
+
+// afastar tira a réplica de uso por replicaQuarentena (loga só na transição).
+func (r *Replica) afastar(err error) {
+	antes := r.foraAte.Swap(time.Now().Add(replicaQuarentena).UnixNano())
+	if time.Now().UnixNano() >= antes {
+		slog.Warn("replica: indisponível; leituras no primário", "quarentena", replicaQuarentena.String(), "erro", err)
+	}
+}
+
+// falhaDeConexao separa erros de infraestrutura da réplica (vale tentar no primário)
+// de erros da própria consulta (o primário devolveria o mesmo).
+func falhaDeConexao(err error) bool {
+	if e, ok := ComoErroBanco(err); ok {
+		switch ClasseSQLState(e.Codigo) {
+		case "08", "53": // connection_exception, insufficient_resources
+			return true
+		case "57": // admin_shutdown, cannot_connect_now... (não query_canceled/statement_timeout)
+			return e.Codigo != SQLStateCancelada
+		}
+		return e.Codigo == SQLStateSerializacao // conflito com a recuperação (hot standby)
+	}
+	var netErr net.Error
+	return errors.Is(err, driver.ErrBadConn) || errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) || errors.As(err, &netErr)
+}
@@ -0,0 +1,100 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/model/responsavel.go
+/// Responsabilidade: Entidade Responsável (pais/tutores) vinculada a estudantes, com DTO de escrita e validação leve.
+/// Dependências principais: errors, net/mail, strings.
+/// Pontos de atenção:
+/// - CPF do responsável é opcional, mas quando enviado precisa ter 11 dígitos (mesma regra do estudante).
+/// - Parentesco aceita apenas os valores de parentescosValidos (comparação em minúsculas).
+/// - Um estudante pode ter vários responsáveis; não há "responsável principal" nesta versão.
+*/
+
+package model
+
+import (
+	"errors"
+	"net/mail"
+	"strings"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// Responsavel representa um registro da tabela `responsaveis`.
+type Responsavel struct {
+	ID          int    `json:"id"`
+	EstudanteID int    `json:"estudante_id"`
+	Nome        string `json:"nome"`
+	CPF         string `json:"cpf"`
+	Telefone    string `json:"telefone"`
+	Email       string `json:"email"`
+	Parentesco  string `json:"parentesco"`
+}
+
+// ResponsavelRequest é o payload de criação/edição (POST/PUT).
+type ResponsavelRequest struct {
+	Nome       string `json:"nome"`
+	CPF        string `json:"cpf"`
+	Telefone   string `json:"telefone"`
+	Email      string `json:"email"`
+	Parentesco string `json:"parentesco"`
+}
+
+// EstudanteDetalhe é a resposta de GET /api/estudantes/{id}.
+type EstudanteDetalhe struct {
+	EstudanteResposta
+	Responsaveis []Responsavel `json:"responsaveis"`
+}
+
+/// ============ Configurações & Constantes ============
+
+var parentescosValidos = map[string]bool{
+	"mae":               true,
+	"pai":               true,
+	"avo":               true,
+	"tio":               true,
+	"irmao":             true,
+	"responsavel_legal": true,
+	"outro":             true,
+}
+
+var (
+	ErrParentescoInvalido = errors.New("parentesco inválido (mae, pai, avo, tio, irmao, responsavel_legal, outro)")
+	ErrContatoObrigatorio = errors.New("informe telefone ou e-mail do responsável")
+)
+
+/// ============ Funções Públicas ============
+
+// Sanitize normaliza os campos (CPF só dígitos, e-mail minúsculo, parentesco minúsculo).
+func (r *ResponsavelRequest) Sanitize() {
+	r.Nome = strings.TrimSpace(r.Nome)
+	r.CPF = digitsOnly(r.CPF)
+	r.Telefone = strings.TrimSpace(r.Telefone)
+	r.Email = strings.ToLower(strings.TrimSpace(r.Email))
+	r.Parentesco = strings.ToLower(strings.TrimSpace(r.Parentesco))
+	if r.Parentesco == "" {
+		r.Parentesco = "outro"
+	}
+}
+
+// Validate exige nome, ao menos um contato, CPF (se houver) com 11 dígitos,
+// e-mail (se houver) válido e parentesco conhecido.
+func (r ResponsavelRequest) Validate() error {
+	if r.Nome == "" {
+		return ErrNomeObrigatorio
+	}
+	if r.Telefone == "" && r.Email == "" {
+		return ErrContatoObrigatorio
+	}
+	if r.CPF != "" && len(r.CPF) != cpfDigitsRequired {
+		return ErrCPFInvalido
+	}
+	if r.Email != "" {
+		if _, err := mail.ParseAddress(r.Email); err != nil {
+			return ErrEmailInvalido
+		}
+	}
+	if !parentescosValidos[r.Parentesco] {
+		return ErrParentescoInvalido
+	}
+	return nil
+}
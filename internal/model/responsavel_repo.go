@@ -0,0 +1,167 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/model/responsavel_repo.go
+/// Responsabilidade: Acesso à tabela `responsaveis` que envolve os campos cifrados (CPF e telefone) e o índice cego cpf_hash, pelo mesmo EstudanteRepo.
+/// Dependências principais: database/sql (Postgres), Array (driver.go), backend/internal/cripto (via EstudanteRepo.pii).
+/// Pontos de atenção:
+/// - Mesmo esquema de estudantes.cpf/telefone (0023_responsaveis_pii.sql): handlers veem texto puro, o banco guarda "enc:v1:..." e o HMAC do CPF.
+/// - Linhas gravadas antes da migração continuam legíveis (Decifrar devolve texto puro sem prefixo) até `cifrar-pii` (CifrarResponsaveisExistentes).
+/// - CPF vazio grava cpf_hash NULL (o CPF do responsável é opcional).
+/// - Remover responsável não toca em PII e continua no handler (handler/responsavel_handler.go).
+*/
+
+package model
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+/// ============ Configurações & Constantes ============
+
+// colunas lidas por ListarResponsaveis (mesma ordem de scanResponsavel)
+const colunasResponsavel = `id, estudante_id, nome, COALESCE(cpf,''), COALESCE(telefone,''), COALESCE(email,''), parentesco`
+
+/// ============ Funções Públicas ============
+
+// ListarResponsaveis devolve os responsáveis dos estudantes do usuário, por estudante e ordem de
+// cadastro, com CPF e telefone decifrados. estudanteIDs vazio = todos os estudantes do usuário.
+func (r *EstudanteRepo) ListarResponsaveis(ctx context.Context, uid int, estudanteIDs ...int) ([]Responsavel, error) {
+	query, args := `SELECT `+colunasResponsavel+` FROM responsaveis WHERE usuario_id=$1`, []any{uid}
+	if len(estudanteIDs) > 0 {
+		query, args = query+` AND estudante_id = ANY($2)`, append(args, Array(estudanteIDs))
+	}
+	rows, err := r.db.QueryContext(ctx, query+` ORDER BY estudante_id ASC, id ASC`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []Responsavel{}
+	for rows.Next() {
+		rsp, err := r.scanResponsavel(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, rsp)
+	}
+	return out, rows.Err()
+}
+
+// CriarResponsavel grava o responsável do estudante (já saneado e validado) e o devolve com o id.
+func (r *EstudanteRepo) CriarResponsavel(ctx context.Context, uid, estID int, in ResponsavelRequest) (Responsavel, error) {
+	out := Responsavel{EstudanteID: estID, Nome: in.Nome, CPF: in.CPF, Telefone: in.Telefone, Email: in.Email, Parentesco: in.Parentesco}
+	cpf, tel, err := r.cifrarCampos(in.CPF, in.Telefone)
+	if err != nil {
+		return out, err
+	}
+	err = r.db.QueryRowContext(ctx, `
+		INSERT INTO responsaveis (estudante_id, usuario_id, nome, cpf, cpf_hash, telefone, email, parentesco)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, $8)
+		RETURNING id
+	`, estID, uid, in.Nome, cpf, r.pii.Indice(in.CPF), tel, in.Email, in.Parentesco).Scan(&out.ID)
+	return out, err
+}
+
+// AtualizarResponsavel regrava o responsável rid do estudante (false se não existir para o usuário).
+func (r *EstudanteRepo) AtualizarResponsavel(ctx context.Context, uid, estID, rid int, in ResponsavelRequest) (bool, error) {
+	cpf, tel, err := r.cifrarCampos(in.CPF, in.Telefone)
+	if err != nil {
+		return false, err
+	}
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE responsaveis
+		   SET nome=$1, cpf=$2, cpf_hash=NULLIF($3, ''), telefone=$4, email=$5, parentesco=$6
+		 WHERE id=$7 AND estudante_id=$8 AND usuario_id=$9
+	`, in.Nome, cpf, r.pii.Indice(in.CPF), tel, in.Email, in.Parentesco, rid, estID, uid)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// CifrarResponsaveisExistentes regrava CPF/telefone dos responsáveis que ainda não estão
+// cifrados com a chave atual e preenche cpf_hash. Devolve quantas linhas mudaram.
+func (r *EstudanteRepo) CifrarResponsaveisExistentes(ctx context.Context) (int, error) {
+	total, ultimo := 0, 0
+	for {
+		rows, err := r.db.QueryContext(ctx, `
+			SELECT id, COALESCE(cpf, ''), COALESCE(telefone, ''), cpf_hash IS NULL AND COALESCE(cpf, '') <> ''
+			  FROM responsaveis
+			 WHERE id > $1
+			 ORDER BY id
+			 LIMIT $2
+		`, ultimo, loteMigracaoPII)
+		if err != nil {
+			return total, err
+		}
+		type linha struct {
+			id            int
+			cpf, telefone string
+			semHash       bool
+		}
+		var lote []linha
+		for rows.Next() {
+			var l linha
+			if err := rows.Scan(&l.id, &l.cpf, &l.telefone, &l.semHash); err != nil {
+				rows.Close()
+				return total, err
+			}
+			lote = append(lote, l)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return total, err
+		}
+		if len(lote) == 0 {
+			return total, nil
+		}
+
+		for _, l := range lote {
+			ultimo = l.id
+			if !l.semHash && r.pii.Atualizado(l.cpf) && r.pii.Atualizado(l.telefone) {
+				continue
+			}
+			cpf, err := r.pii.Decifrar(l.cpf)
+			if err != nil {
+				return total, fmt.Errorf("responsável %d (cpf): %w", l.id, err)
+			}
+			tel, err := r.pii.Decifrar(l.telefone)
+			if err != nil {
+				return total, fmt.Errorf("responsável %d (telefone): %w", l.id, err)
+			}
+			cpfC, telC, err := r.cifrarCampos(cpf, tel)
+			if err != nil {
+				return total, err
+			}
+			if _, err := r.db.ExecContext(ctx,
+				`UPDATE responsaveis SET cpf=$1, cpf_hash=NULLIF($2, ''), telefone=$3 WHERE id=$4`,
+				cpfC, r.pii.Indice(cpf), telC, l.id,
+			); err != nil {
+				return total, fmt.Errorf("responsável %d: %w", l.id, err)
+			}
+			total++
+		}
+	}
+}
+
+/// ============ Funções Internas (helpers) ============
+
+// scanResponsavel lê uma linha de colunasResponsavel decifrando CPF e telefone.
+func (r *EstudanteRepo) scanResponsavel(rows *sql.Rows) (Responsavel, error) {
+	var rsp Responsavel
+	if err := rows.Scan(&rsp.ID, &rsp.EstudanteID, &rsp.Nome, &rsp.CPF, &rsp.Telefone, &rsp.Email, &rsp.Parentesco); err != nil {
+		return rsp, err
+	}
+	var err error
+	if rsp.CPF, err = r.pii.Decifrar(rsp.CPF); err != nil {
+		return rsp, fmt.Errorf("responsável %d (cpf): %w", rsp.ID, err)
+	}
+	if rsp.Telefone, err = r.pii.Decifrar(rsp.Telefone); err != nil {
+		return rsp, fmt.Errorf("responsável %d (telefone): %w", rsp.ID, err)
+	}
+	return rsp, nil
+}
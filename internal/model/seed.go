@@ -0,0 +1,220 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/model/seed.go
+/// Responsabilidade: Gerar estudantes fictícios realistas (nomes, CPFs com dígitos verificadores válidos, datas de nascimento em idade escolar, telefones) para desenvolvimento e demonstrações.
+/// Dependências principais: context, database/sql, math/rand/v2, EstudanteRepo (CPF/telefone cifrados como em qualquer cadastro).
+/// Pontos de atenção:
+/// - Usado por `backend seed --estudantes N` (cmd/server/cli.go) e por POST /api/dev/seed (fora de produção); nunca roda sozinho.
+/// - Os estudantes são distribuídos entre os anos ativos do tenant; sem nenhum, cria 1º, 2º e 3º Ano.
+/// - CPF/e-mail repetidos (já existentes no tenant) são pulados, não abortam o lote: o total criado pode ficar abaixo do pedido.
+/// - E-mails usam o domínio reservado dominioSeed, fácil de identificar e apagar depois.
+/// - Sem webhooks nem feed de atividades: são dados de teste.
+*/
+
+package model
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// SeedRequest é o payload de POST /api/dev/seed.
+type SeedRequest struct {
+	Estudantes int `json:"estudantes"` // 0 = seedEstudantesPadrao
+}
+
+/// ============ Configurações & Constantes ============
+
+// limites de um lote de geração
+const (
+	MaxEstudantesSeed    = 5000
+	seedEstudantesPadrao = 50
+)
+
+var ErrSeedQuantidade = fmt.Errorf("estudantes deve estar entre 1 e %d", MaxEstudantesSeed)
+
+// domínio dos e-mails gerados
+const dominioSeed = "seed.tecmise.local"
+
+// anos criados quando o tenant ainda não tem nenhum
+var anosSeed = []string{"1º Ano", "2º Ano", "3º Ano"}
+
+var (
+	nomesSeed = []string{
+		"Ana", "Beatriz", "Bruna", "Camila", "Carolina", "Clara", "Fernanda", "Gabriela", "Isabela", "Júlia",
+		"Larissa", "Laura", "Letícia", "Manuela", "Mariana", "Sofia", "Valentina", "Yasmin", "Alice", "Helena",
+		"Arthur", "Bernardo", "Bruno", "Caio", "Daniel", "Davi", "Eduardo", "Enzo", "Felipe", "Gabriel",
+		"Gustavo", "Heitor", "João", "Lucas", "Matheus", "Miguel", "Pedro", "Rafael", "Samuel", "Thiago",
+	}
+	sobrenomesSeed = []string{
+		"Silva", "Santos", "Oliveira", "Souza", "Rodrigues", "Ferreira", "Alves", "Pereira", "Lima", "Gomes",
+		"Costa", "Ribeiro", "Martins", "Carvalho", "Almeida", "Lopes", "Soares", "Fernandes", "Vieira", "Barbosa",
+		"Rocha", "Dias", "Nascimento", "Andrade", "Moreira", "Nunes", "Marques", "Machado", "Mendes", "Freitas",
+	}
+	dddsSeed = []string{"11", "21", "31", "41", "47", "51", "61", "62", "71", "81", "85", "91"}
+)
+
+// semAcento troca as letras acentuadas usadas em nomesSeed/sobrenomesSeed.
+var semAcento = strings.NewReplacer("á", "a", "â", "a", "ã", "a", "é", "e", "ê", "e", "í", "i", "ó", "o", "ô", "o", "ú", "u", "ç", "c").Replace
+
+/// ============ Funções Públicas ============
+
+// Sanitize aplica a quantidade padrão quando omitida.
+func (s *SeedRequest) Sanitize() {
+	if s.Estudantes == 0 {
+		s.Estudantes = seedEstudantesPadrao
+	}
+}
+
+// Validate limita a quantidade a 1..MaxEstudantesSeed.
+func (s SeedRequest) Validate() error {
+	if s.Estudantes < 1 || s.Estudantes > MaxEstudantesSeed {
+		return ErrSeedQuantidade
+	}
+	return nil
+}
+
+// SemearEstudantes cria até n estudantes fictícios para uid e devolve quantos foram
+// criados. db cria os anos quando o tenant não tem nenhum; repo grava os estudantes.
+func SemearEstudantes(ctx context.Context, db *sql.DB, repo *EstudanteRepo, uid, n int) (int, error) {
+	if err := (SeedRequest{Estudantes: n}).Validate(); err != nil {
+		return 0, err
+	}
+	anos, err := anosParaSeed(ctx, db, uid)
+	if err != nil {
+		return 0, err
+	}
+	criados := 0
+	for _, in := range gerarEstudantes(rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())), n, anos, time.Now()) {
+		if _, err := repo.Criar(ctx, uid, in); err != nil {
+			if e, ok := ComoErroBanco(err); ok && e.Codigo == SQLStateUnicidade {
+				continue // CPF/e-mail já usado no tenant
+			}
+			return criados, fmt.Errorf("criar estudante %q: %w", in.Nome, err)
+		}
+		criados++
+	}
+	return criados, nil
+}
+
+/// ============ Funções Internas (helpers) ============
+
+// gerarEstudantes monta n estudantes fictícios (sem CPF/e-mail repetidos entre si),
+// distribuídos entre anos, com idades de 6 a 17 anos em relação a hoje.
+func gerarEstudantes(rng *rand.Rand, n int, anos []int, hoje time.Time) []EstudanteCreateRequest {
+	out := make([]EstudanteCreateRequest, 0, n)
+	cpfs := make(map[string]bool, n)
+	emails := make(map[string]bool, n)
+	for len(out) < n {
+		cpf := gerarCPF(rng)
+		nome := nomesSeed[rng.IntN(len(nomesSeed))]
+		sobrenome := sobrenomesSeed[rng.IntN(len(sobrenomesSeed))] + " " + sobrenomesSeed[rng.IntN(len(sobrenomesSeed))]
+		email := semAcento(strings.ToLower(nome+"."+strings.ReplaceAll(sobrenome, " ", "."))) +
+			"." + strconv.Itoa(rng.IntN(10000)) + "@" + dominioSeed
+		if cpfs[cpf] || emails[email] {
+			continue
+		}
+		cpfs[cpf], emails[email] = true, true
+
+		idade := 6 + rng.IntN(12)
+		nascimento := hoje.AddDate(-idade, 0, -rng.IntN(365))
+		in := EstudanteCreateRequest{
+			Nome:           nome + " " + sobrenome,
+			CPF:            cpf,
+			Email:          email,
+			DataNascimento: nascimento.Format(dateLayoutISO),
+			Telefone:       dddsSeed[rng.IntN(len(dddsSeed))] + "9" + fmt.Sprintf("%08d", rng.IntN(100000000)),
+		}
+		if len(anos) > 0 {
+			in.AnoID = anos[rng.IntN(len(anos))]
+		}
+		out = append(out, in)
+	}
+	return out
+}
+
+// gerarCPF devolve um CPF aleatório (11 dígitos, sem máscara) com dígitos verificadores válidos.
+func gerarCPF(rng *rand.Rand) string {
+	d := make([]int, 11)
+	for {
+		for i := 0; i < 9; i++ {
+			d[i] = rng.IntN(10)
+		}
+		if !digitosIguais(d[:9]) { // 000.000.000-00, 111... são inválidos
+			break
+		}
+	}
+	d[9] = digitoCPF(d[:9])
+	d[10] = digitoCPF(d[:10])
+	var b strings.Builder
+	for _, v := range d {
+		b.WriteByte(byte('0' + v))
+	}
+	return b.String()
+}
+
+// anosParaSeed devolve os anos ativos de uid, criando os de anosSeed se não houver nenhum.
+func anosParaSeed(ctx context.Context, db *sql.DB, uid int) ([]int, error) {
+	var ids []int
+	err := ComTransacao(ctx, db, func(tx *sql.Tx) error {
+		ids = ids[:0]
+		rows, err := tx.QueryContext(ctx,
+			`SELECT id FROM anos WHERE usuario_id = $1 AND excluido_em IS NULL AND NOT arquivado ORDER BY ordem, id`, uid)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var id int
+			if err := rows.Scan(&id); err != nil {
+				return err
+			}
+			ids = append(ids, id)
+		}
+		if err := rows.Err(); err != nil || len(ids) > 0 {
+			return err
+		}
+		for i, nome := range anosSeed {
+			var id int
+			if err := tx.QueryRowContext(ctx,
+				`INSERT INTO anos (nome, usuario_id, ordem) VALUES ($1, $2, $3) RETURNING id`, nome, uid, i,
+			).Scan(&id); err != nil {
+				return fmt.Errorf("criar ano %q: %w", nome, err)
+			}
+			ids = append(ids, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("preparar anos para o seed: %w", err)
+	}
+	return ids, nil
+}
+
+// digitoCPF calcula o dígito verificador seguinte de d (pesos decrescentes até 2).
+func digitoCPF(d []int) int {
+	soma := 0
+	for i, v := range d {
+		soma += v * (len(d) + 1 - i)
+	}
+	if r := soma % 11; r >= 2 {
+		return 11 - r
+	}
+	return 0
+}
+
+func digitosIguais(d []int) bool {
+	for _, v := range d[1:] {
+		if v != d[0] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,138 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/model/sessao.go
+/// Responsabilidade: Sessões por dispositivo (tabela `sessoes`): emissão do token no login, resolução a cada requisição (Authorization: Bearer), listagem e revogação pelo próprio usuário.
+/// Dependências principais: context, database/sql, errors, time, unicode/utf8.
+/// Pontos de atenção:
+/// - Token no mesmo esquema dos convites e da personificação (NovoTokenConvite): devolvido uma única vez no login; o banco guarda só o hash.
+/// - ResolverSessao recusa token revogado, expirado ou de conta bloqueada (ErrSessaoInvalida) e atualiza ultimo_uso no máximo a cada sessaoUltimoUsoIntervalo.
+/// - Revogar só preenche revogada_em: o dispositivo perde o acesso na próxima requisição.
+*/
+
+package model
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+	"unicode/utf8"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// NovaSessao é o que um handler de login grava ao emitir a sessão.
+type NovaSessao struct {
+	UsuarioID int
+	Metodo    string // MetodoLoginSenha | MetodoLoginGoogle
+	IP        string
+	UserAgent string
+	TTL       time.Duration
+}
+
+// Sessao é um item de GET /api/usuario/sessoes.
+type Sessao struct {
+	ID        int64     `json:"id"`
+	Metodo    string    `json:"metodo"`
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"user_agent"` // dispositivo/navegador informado no login
+	CriadaEm  time.Time `json:"criada_em"`
+	UltimoUso time.Time `json:"ultimo_uso"`
+	ExpiraEm  time.Time `json:"expira_em"`
+	Atual     bool      `json:"atual"` // sessão usada na própria requisição
+}
+
+/// ============ Configurações & Constantes ============
+
+// intervalo mínimo entre duas atualizações de ultimo_uso da mesma sessão
+const sessaoUltimoUsoIntervalo = 5 * time.Minute
+
+// ErrSessaoInvalida indica token de sessão desconhecido, revogado, expirado ou de conta bloqueada.
+var ErrSessaoInvalida = errors.New("sessão inválida ou expirada; faça login novamente")
+
+/// ============ Funções Públicas ============
+
+// CriarSessao grava a sessão e devolve o token (exibido uma única vez).
+func CriarSessao(ctx context.Context, db *sql.DB, s NovaSessao) (string, error) {
+	token, hash, err := NovoTokenConvite()
+	if err != nil {
+		return "", err
+	}
+	ua := s.UserAgent
+	if utf8.RuneCountInString(ua) > userAgentMaximo {
+		ua = string([]rune(ua)[:userAgentMaximo])
+	}
+	if _, err := db.ExecContext(ctx, `
+		INSERT INTO sessoes (usuario_id, token_hash, metodo, ip, user_agent, expira_em)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, s.UsuarioID, hash, s.Metodo, s.IP, ua, time.Now().Add(s.TTL)); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// ResolverSessao devolve o id da sessão e o e-mail da conta do token.
+// ErrSessaoInvalida se o token não existir, tiver sido revogado, expirado ou a conta estiver bloqueada.
+func ResolverSessao(ctx context.Context, db *sql.DB, token string) (id int64, email string, err error) {
+	var ultimoUso time.Time
+	err = db.QueryRowContext(ctx, `
+		SELECT s.id, u.email, s.ultimo_uso
+		  FROM sessoes s
+		  JOIN usuarios u ON u.id = s.usuario_id
+		 WHERE s.token_hash = $1
+		   AND s.revogada_em IS NULL
+		   AND s.expira_em > NOW()
+		   AND u.bloqueado_em IS NULL
+	`, HashTokenConvite(token)).Scan(&id, &email, &ultimoUso)
+	if err == sql.ErrNoRows {
+		return 0, "", ErrSessaoInvalida
+	}
+	if err != nil {
+		return 0, "", err
+	}
+	if time.Since(ultimoUso) >= sessaoUltimoUsoIntervalo {
+		if _, err := db.ExecContext(ctx, `UPDATE sessoes SET ultimo_uso = NOW() WHERE id = $1`, id); err != nil {
+			return 0, "", err
+		}
+	}
+	return id, email, nil
+}
+
+// ListarSessoes devolve as sessões ativas da conta, da usada mais recentemente para a mais antiga;
+// atual marca a sessão da requisição (0 = nenhuma).
+func ListarSessoes(ctx context.Context, db *sql.DB, usuarioID int, atual int64) ([]Sessao, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT id, metodo, ip, user_agent, criada_em, ultimo_uso, expira_em
+		  FROM sessoes
+		 WHERE usuario_id = $1 AND revogada_em IS NULL AND expira_em > NOW()
+		 ORDER BY ultimo_uso DESC, id DESC
+	`, usuarioID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []Sessao{}
+	for rows.Next() {
+		var s Sessao
+		if err := rows.Scan(&s.ID, &s.Metodo, &s.IP, &s.UserAgent, &s.CriadaEm, &s.UltimoUso, &s.ExpiraEm); err != nil {
+			return nil, err
+		}
+		s.Atual = s.ID == atual
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+// RevogarSessao encerra a sessão id da conta (false se não existir, for de outra conta ou já estiver encerrada).
+func RevogarSessao(ctx context.Context, db *sql.DB, usuarioID int, id int64) (bool, error) {
+	res, err := db.ExecContext(ctx, `
+		UPDATE sessoes SET revogada_em = NOW()
+		 WHERE id = $1 AND usuario_id = $2 AND revogada_em IS NULL AND expira_em > NOW()
+	`, id, usuarioID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
@@ -0,0 +1,69 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/model/transacao.go
+/// Responsabilidade: Executar um bloco em transação (BeginTx/Commit/Rollback) repetindo-o quando o Postgres aborta por falha de serialização ou deadlock.
+/// Dependências principais: context, database/sql, math/rand/v2, time.
+/// Pontos de atenção:
+/// - fn pode rodar mais de uma vez: não deve ter efeitos fora da transação (webhooks, e-mails, cache) nem acumular estado de uma tentativa para outra.
+/// - Só 40001 (serialization_failure) e 40P01 (deadlock_detected) são repetidos; qualquer outro erro de fn é devolvido como está, após o rollback.
+/// - A espera entre tentativas respeita ctx: prazo vencido devolve o último erro do banco.
+*/
+
+package model
+
+import (
+	"context"
+	"database/sql"
+	"math/rand/v2"
+	"time"
+)
+
+/// ============ Configurações & Constantes ============
+
+const (
+	txTentativas   = 4                     // execuções no total (1 + 3 repetições)
+	txEsperaInicio = 20 * time.Millisecond // dobra a cada repetição, com jitter
+)
+
+/// ============ Funções Públicas ============
+
+// ComTransacao roda fn em uma transação e faz commit se fn não devolver erro. Em
+// conflito de concorrência (40001/40P01) desfaz e repete fn com backoff.
+func ComTransacao(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	espera := txEsperaInicio
+	for tentativa := 1; ; tentativa++ {
+		err := umaTransacao(ctx, db, fn)
+		if err == nil || tentativa == txTentativas || !ConflitoConcorrencia(err) {
+			return err
+		}
+		t := time.NewTimer(espera/2 + rand.N(espera))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return err
+		case <-t.C:
+		}
+		espera *= 2
+	}
+}
+
+// ConflitoConcorrencia informa se err é uma falha de serialização ou deadlock do
+// Postgres (a operação pode ser repetida).
+func ConflitoConcorrencia(err error) bool {
+	e, ok := ComoErroBanco(err)
+	return ok && (e.Codigo == SQLStateSerializacao || e.Codigo == SQLStateDeadlock)
+}
+
+/// ============ Funções Internas (helpers) ============
+
+func umaTransacao(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -1,6 +1,6 @@
 /*
 /// Projeto: Tecmise
-/// Arquivo: backend/model/user.go
+/// Arquivo: backend/internal/model/user.go
 /// Responsabilidade: DTOs e entidade de Usuário (registro, login, atualização de perfil, flags de tutorial).
 /// Dependências principais: errors, net/mail (validação básica de e-mail), strings.
 /// Pontos de atenção:
@@ -10,7 +10,7 @@
 /// - Sanitize/Validate são leves; regras específicas de negócio devem ficar no handler/camada de serviço.
 */
 
-// backend/model/user.go
+// backend/internal/model/user.go
 package model
 
 import (
@@ -34,6 +34,8 @@ type RegisterRequest struct {
 	Nome  string `json:"nome"`  // Nome do usuário a ser cadastrado
 	Email string `json:"email"` // E-mail único usado no login
 	Senha string `json:"senha"` // Senha em texto puro no payload
+
+	Captcha string `json:"captcha,omitempty"` // token do hCaptcha/reCAPTCHA (exigido com CAPTCHA_DRIVER ligado)
 }
 
 /// ============ Configurações & Constantes ============
@@ -41,34 +43,47 @@ type RegisterRequest struct {
 // Regras básicas (podem ser ajustadas via handler, se preferir)
 const MinPasswordLen = 6
 
+// Regras do cadastro (/register): o projeto vinha exigindo 8 caracteres na senha.
+const (
+	MinNomeLen             = 2
+	MinPasswordLenCadastro = 8
+)
+
 var (
 	ErrNomeObrigatorio = errors.New("nome é obrigatório")
+	ErrNomeCurto       = errors.New("nome muito curto")
 	ErrEmailInvalido   = errors.New("email inválido")
 	ErrSenhaCurta      = errors.New("senha muito curta")
+	ErrSenhaEspacos    = errors.New("senha não pode conter espaços")
 )
 
 /// ============ Funções Públicas ============
 
-// Sanitize normaliza campos de entrada (trim e e-mail minúsculo).
+// Sanitize normaliza campos de entrada (trim e e-mail via NormalizarEmail).
 // Efeitos colaterais: muta o próprio receiver.
 func (r *RegisterRequest) Sanitize() {
 	r.Nome = strings.TrimSpace(r.Nome)
-	r.Email = strings.TrimSpace(strings.ToLower(r.Email))
+	r.Email = NormalizarEmail(r.Email)
 }
 
-// Validate aplica validações simples para cadastro.
-// Regras: nome não vazio, e-mail válido por mail.ParseAddress e senha com tamanho mínimo.
+// Validate aplica validações simples para cadastro, acumulando as violações.
+// Regras: nome com MinNomeLen, e-mail válido por mail.ParseAddress e fora de domínio
+// bloqueado (ValidarDominioEmail) e senha com MinPasswordLenCadastro caracteres e sem espaços.
 func (r RegisterRequest) Validate() error {
-	if strings.TrimSpace(r.Nome) == "" {
-		return ErrNomeObrigatorio
+	var ev ErrosValidacao
+	switch nome := strings.TrimSpace(r.Nome); {
+	case nome == "":
+		ev.Add("nome", RegraObrigatorio, ErrNomeObrigatorio)
+	case len(nome) < MinNomeLen:
+		ev.Add("nome", RegraTamanhoMinimo, ErrNomeCurto)
 	}
 	if _, err := mail.ParseAddress(r.Email); err != nil {
-		return ErrEmailInvalido
-	}
-	if len(r.Senha) < MinPasswordLen {
-		return ErrSenhaCurta
+		ev.Add("email", RegraFormato, ErrEmailInvalido)
+	} else if err := ValidarDominioEmail(r.Email); err != nil {
+		ev.Add("email", RegraBloqueado, err)
 	}
-	return nil
+	validarSenha(&ev, r.Senha, MinPasswordLenCadastro)
+	return ev.Err()
 }
 
 /*
@@ -87,9 +102,9 @@ type LoginRequest struct {
 	Senha string `json:"senha"`
 }
 
-// Sanitize para LoginRequest: trim + lowercase no e-mail.
+// Sanitize para LoginRequest: e-mail normalizado (NormalizarEmail).
 func (l *LoginRequest) Sanitize() {
-	l.Email = strings.TrimSpace(strings.ToLower(l.Email))
+	l.Email = NormalizarEmail(l.Email)
 }
 
 /*
@@ -111,6 +126,19 @@ type UpdatePerfilRequest struct {
 	Senha   *string `json:"senha,omitempty"` // opcional
 }
 
+// Validate aplica as regras de PUT /api/perfil sobre os campos enviados:
+// nome com MinNomeLen e, se houver senha, MinPasswordLen sem espaços.
+func (p UpdatePerfilRequest) Validate() error {
+	var ev ErrosValidacao
+	if p.Nome != nil && len(strings.TrimSpace(*p.Nome)) < MinNomeLen {
+		ev.Add("nome", RegraTamanhoMinimo, ErrNomeCurto)
+	}
+	if p.Senha != nil && strings.TrimSpace(*p.Senha) != "" {
+		validarSenha(&ev, strings.TrimSpace(*p.Senha), MinPasswordLen)
+	}
+	return ev.Err()
+}
+
 /*
 ===========================================
 📌 Estrutura TutorialUpdateRequest (opcional)
@@ -142,6 +170,8 @@ type User struct {
 	Senha         string `json:"senha,omitempty"` // Senha omitida no retorno
 	FotoURL       string `json:"fotoUrl"`         // URL da foto de perfil do usuário
 	TutorialVisto bool   `json:"tutorial_visto"`  // Flag: indica se o tutorial já foi visto
+	Novo          bool   `json:"-"`               // conta criada agora por UpsertFromGoogle (e-mail de boas-vindas)
+	Bloqueado     bool   `json:"-"`               // conta bloqueada pela equipe de suporte (login recusado)
 }
 
 /*
@@ -163,6 +193,13 @@ type UserPublic struct {
 	TutorialVisto bool   `json:"tutorial_visto"`
 }
 
+// RespostaLogin é o corpo de sucesso de /login e /login/google: o usuário e o
+// token da sessão emitida (enviar em Authorization: Bearer).
+type RespostaLogin struct {
+	UserPublic
+	Token string `json:"token"`
+}
+
 // Public projeta um User para UserPublic (sem senha).
 // Não altera o receiver; apenas retorna uma cópia convertida.
 func (u User) Public() UserPublic {
@@ -175,5 +212,17 @@ func (u User) Public() UserPublic {
 	}
 }
 
+/// ============ Funções Internas (helpers) ============
+
+// validarSenha registra em ev as violações de tamanho mínimo e espaços da senha.
+func validarSenha(ev *ErrosValidacao, senha string, minimo int) {
+	if len(senha) < minimo {
+		ev.Add("senha", RegraTamanhoMinimo, ErrSenhaCurta)
+	}
+	if strings.Contains(senha, " ") {
+		ev.Add("senha", RegraSemEspacos, ErrSenhaEspacos)
+	}
+}
+
 // TODO: avaliar alinhamento de MinPasswordLen com validações do frontend (ex.: 8+ chars no login/register UI)
 // TODO: padronizar convenção JSON (camelCase vs snake_case) quando possível, mantendo compatibilidade retroativa
@@ -0,0 +1,150 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/model/user_repo.go
+/// Responsabilidade: Repositório de usuários (PostgreSQL) com fluxo de UPSERT para autenticação via Google (GIS).
+/// Dependências principais: database/sql (Postgres), pacote local model.User.
+/// Pontos de atenção:
+/// - Schema: colunas google_sub/foto_url são garantidas pelas migrations (backend/internal/migrations); não há mais detecção em runtime.
+/// - Concorrência: UpsertFromGoogle é um único INSERT ... ON CONFLICT (email) (índice usuarios_email_unique, migrations/0013_email_citext.sql), então logins Google simultâneos não duplicam a conta.
+/// - email é CITEXT: buscas por `email = $1` ignoram a caixa e usam o índice usuarios_email_unique (não converta o parâmetro com ::text).
+/// - O repositório não guarda estado além dos prepared statements: seguro para uso concorrente.
+/// - BuscarCredenciais (login por senha) usa prepared statement depois de Preparar (preparadas.go).
+*/
+
+package model
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// -----------------------------------------------------------------------------
+// UserRepository para login Google (tabela: usuarios)
+// -----------------------------------------------------------------------------
+//
+// Observação importante sobre senha_hash:
+// Sua tabela `usuarios` exige `senha_hash` NOT NULL. Como contas Google não
+// usam senha local, gravamos `senha_hash` como string vazia (`''`) apenas para
+// satisfazer a restrição. Isso impede login por e-mail/senha para esses
+// usuários (bcrypt vai falhar), o que é desejado nesse fluxo.
+//
+// Tabela esperada (migrations/0001_baseline.sql):
+//   usuarios(id, nome, email, senha_hash, google_sub, foto_url)
+//
+
+/// ============ Tipos & Interfaces ============
+
+// UserRepository define o contrato de persistência para o fluxo de autenticação Google.
+type UserRepository interface {
+	// UpsertFromGoogle:
+	// 1) Se existir usuarios.google_sub = sub -> retorna usuário.
+	// 2) Senão, se existir usuarios.email = email -> (se possível) vincula google_sub e retorna.
+	// 3) Senão, cria usuário com google_sub/foto_url.
+	UpsertFromGoogle(ctx context.Context, nome, email, sub, picture string) (*User, error)
+	// RegistrarLogin grava a tentativa no histórico de logins da conta.
+	RegistrarLogin(ctx context.Context, l NovoLogin) error
+	// CriarSessao grava a sessão do login e devolve o token (Authorization: Bearer).
+	CriarSessao(ctx context.Context, s NovaSessao) (string, error)
+}
+
+// SQLUserRepo implementação baseada em database/sql para PostgreSQL.
+type SQLUserRepo struct {
+	db    *sql.DB
+	stmts preparadas // preenchido por Preparar
+}
+
+// CredenciaisLogin é o que o login por e-mail/senha lê do usuário.
+type CredenciaisLogin struct {
+	ID            int
+	Nome          string
+	Email         string // como gravado (o login compara sem diferenciar caixa)
+	SenhaHash     string
+	FotoURL       string
+	TutorialVisto bool
+	Bloqueado     bool // bloqueado_em preenchido (login recusado)
+}
+
+// Usuario projeta as credenciais no usuário devolvido pelo login (sem o hash).
+func (c CredenciaisLogin) Usuario() User {
+	return User{ID: c.ID, Nome: c.Nome, Email: c.Email, FotoURL: c.FotoURL, TutorialVisto: c.TutorialVisto, Bloqueado: c.Bloqueado}
+}
+
+// consulta do login por senha (preparada por Preparar)
+const sqlCredenciaisLogin = `SELECT id, nome, email, senha_hash, COALESCE(foto_url,''), COALESCE(tutorial_visto,false), bloqueado_em IS NOT NULL FROM usuarios WHERE email = $1`
+
+// upsert do login Google ($1 nome, $2 email, $3 sub, $4 foto). xmax = 0 só na linha
+// recém-inserida (no ON CONFLICT DO UPDATE ela já existia).
+const sqlUpsertGoogle = `
+	WITH por_sub AS (
+		SELECT id, nome, email, foto_url, tutorial_visto, FALSE AS novo, bloqueado_em IS NOT NULL AS bloqueado
+		  FROM usuarios
+		 WHERE google_sub = NULLIF($3, '')
+	), gravado AS (
+		INSERT INTO usuarios (nome, email, senha_hash, google_sub, foto_url)
+		SELECT $1, $2, '', NULLIF($3, ''), NULLIF($4, '')
+		 WHERE NOT EXISTS (SELECT 1 FROM por_sub)
+		ON CONFLICT (email) DO UPDATE
+		   SET google_sub = COALESCE(EXCLUDED.google_sub, usuarios.google_sub),
+		       foto_url   = COALESCE(EXCLUDED.foto_url, usuarios.foto_url)
+		RETURNING id, nome, email, foto_url, tutorial_visto, xmax = 0 AS novo, bloqueado_em IS NOT NULL AS bloqueado
+	)
+	SELECT id, nome, email, COALESCE(foto_url, ''), COALESCE(tutorial_visto, false), novo, bloqueado FROM por_sub
+	UNION ALL
+	SELECT id, nome, email, COALESCE(foto_url, ''), COALESCE(tutorial_visto, false), novo, bloqueado FROM gravado`
+
+/// ============ Inicialização/Bootstrap ============
+
+// NewUserRepo cria uma instância de SQLUserRepo com o pool *sql.DB informado.
+// Exemplo de uso:
+//
+//	repo := model.NewUserRepo(db)
+//	user, err := repo.UpsertFromGoogle(ctx, "Nome", "email@dominio.com", sub, picture)
+func NewUserRepo(db *sql.DB) *SQLUserRepo { return &SQLUserRepo{db: db} }
+
+/// ============ Funções Públicas ============
+
+// Preparar cria o prepared statement do login por senha. Chamar uma vez, na inicialização.
+func (r *SQLUserRepo) Preparar(ctx context.Context) error {
+	p, err := preparar(ctx, r.db, sqlCredenciaisLogin)
+	if err != nil {
+		return fmt.Errorf("usuarios: %w", err)
+	}
+	r.stmts = p
+	return nil
+}
+
+// BuscarCredenciais devolve id, nome, e-mail, hash da senha, foto e flags da conta do e-mail (sql.ErrNoRows se não existir).
+func (r *SQLUserRepo) BuscarCredenciais(ctx context.Context, email string) (CredenciaisLogin, error) {
+	var c CredenciaisLogin
+	err := r.stmts.queryRow(ctx, r.db, sqlCredenciaisLogin, email).Scan(&c.ID, &c.Nome, &c.Email, &c.SenhaHash, &c.FotoURL, &c.TutorialVisto, &c.Bloqueado)
+	return c, err
+}
+
+// UpsertFromGoogle realiza o "upsert" do usuário com os dados do Google em um único
+// comando (atômico, sem janela entre consulta e inserção):
+//  1. Se google_sub existir e corresponder, retorna a conta como está.
+//  2. Senão, insere a conta (senha_hash = ” para satisfazer NOT NULL); se o e-mail já
+//     existir (sem diferenciar caixa: CITEXT, índice usuarios_email_unique), vincula
+//     google_sub e atualiza foto_url (quando vier) na conta existente.
+//
+// User.Novo indica que a conta foi criada agora; User.Bloqueado, que o login deve ser recusado. Erros: encapsulados via fmt.Errorf.
+func (r *SQLUserRepo) UpsertFromGoogle(ctx context.Context, nome, email, sub, picture string) (*User, error) {
+	u := &User{}
+	err := r.db.QueryRowContext(ctx, sqlUpsertGoogle, nome, NormalizarEmail(email), sub, picture).
+		Scan(&u.ID, &u.Nome, &u.Email, &u.FotoURL, &u.TutorialVisto, &u.Novo, &u.Bloqueado)
+	if err != nil {
+		return nil, fmt.Errorf("upsert de usuário Google: %w", err)
+	}
+	return u, nil
+}
+
+// RegistrarLogin grava a tentativa no histórico de logins (ver RegistrarLogin em historico_login.go).
+func (r *SQLUserRepo) RegistrarLogin(ctx context.Context, l NovoLogin) error {
+	return RegistrarLogin(ctx, r.db, l)
+}
+
+// CriarSessao grava a sessão do login (ver model/sessao.go).
+func (r *SQLUserRepo) CriarSessao(ctx context.Context, s NovaSessao) (string, error) {
+	return CriarSessao(ctx, r.db, s)
+}
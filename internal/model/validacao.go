@@ -0,0 +1,178 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/model/validacao.go
+/// Responsabilidade: Erros de validação agregados por campo ({field, rule, message}) para que um único 422 aponte todos os campos inválidos.
+/// Dependências principais: errors, strings, backend/internal/i18n.
+/// Pontos de atenção:
+/// - ErrosValidacao implementa Unwrap() []error: errors.Is(err, ErrCPFInvalido) continua funcionando nos handlers.
+/// - field usa o nome do campo no JSON (ex.: "data_nascimento"), não o nome do struct Go.
+/// - rule é estável (contrato com o frontend); message é texto para exibição, traduzida por Traduzir (sentinela em
+///   mensagensEN, senão o texto genérico da regra).
+*/
+
+package model
+
+import (
+	"errors"
+	"strings"
+
+	"backend/internal/i18n"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// ErroCampo descreve uma regra violada em um campo do payload.
+type ErroCampo struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+	err     error  // sentinela de origem (para errors.Is)
+}
+
+// ErrosValidacao acumula os erros de campo de um payload.
+type ErrosValidacao []ErroCampo
+
+/// ============ Configurações & Constantes ============
+
+// Regras de validação (valor de ErroCampo.Rule).
+const (
+	RegraObrigatorio   = "obrigatorio"
+	RegraFormato       = "formato"
+	RegraTamanhoMinimo = "tamanho_minimo"
+	RegraSemEspacos    = "sem_espacos"
+	RegraBloqueado     = "bloqueado" // valor recusado por política (ex.: domínio de e-mail descartável)
+)
+
+// textos genéricos de cada regra (campos sem sentinela traduzida)
+var mensagensRegra = map[string]i18n.Texto{
+	RegraObrigatorio:   {PtBR: "campo obrigatório", EN: "this field is required"},
+	RegraFormato:       {PtBR: "formato inválido", EN: "invalid format"},
+	RegraTamanhoMinimo: {PtBR: "valor muito curto", EN: "value is too short"},
+	RegraSemEspacos:    {PtBR: "não pode conter espaços", EN: "must not contain spaces"},
+	RegraBloqueado:     {PtBR: "valor não permitido", EN: "value not allowed"},
+}
+
+// mensagensEN traduz as sentinelas usadas em ErrosValidacao.Add.
+var mensagensEN = map[error]string{
+	ErrNomeObrigatorio:        "name is required",
+	ErrNomeCurto:              "name is too short",
+	ErrEmailInvalido:          "invalid e-mail",
+	ErrSenhaCurta:             "password is too short",
+	ErrSenhaEspacos:           "password must not contain spaces",
+	ErrSenhaObrigatoria:       "password is required",
+	ErrIDTokenObrigatorio:     "idToken is required",
+	ErrCPFInvalido:            "invalid cpf (must have 11 digits)",
+	ErrDataNascimentoInvalida: "invalid data_nascimento (expected YYYY-MM-DD)",
+	ErrCompartilharValidade:   "invalid expira_em_horas",
+	ErrInepEscola:             "inep_escola must have 8 digits (school INEP code)",
+	ErrEducacensoNome:         "name must contain only letters and spaces (no digits or symbols)",
+	ErrEducacensoData:         "data_nascimento missing, invalid or in the future",
+	ErrEducacensoCPF:          "cpf has invalid check digits",
+	ErrEducacensoTurma:        "student has no year/class",
+	ErrEducacensoFiliacao:     "guardian name (parentage) contains digits or symbols",
+	ErrFiltroNomeObrigatorio:  "filter name is required",
+	ErrFiltroNomeLongo:        "filter name must have at most 80 characters",
+	ErrFiltroStatus:           "filtro.status accepts ativo, transferido and formado",
+	ErrFiltroIDs:              "filter with at most 500 ids",
+	ErrFiltroBusca:            "filtro.busca must have at most 100 characters",
+	ErrFiltroSem:              "filtro.sem accepts telefone, email, cpf, foto_url, data_nascimento and turma_id",
+	ErrFiltroNascimento:       "filtro.nascimento_de/nascimento_ate must be YYYY-MM-DD, with de <= ate",
+	ErrClassroomToken:         "access_token is required",
+	ErrClassroomCurso:         "curso_id is required",
+	ErrClassroomEstudantes:    "send 1 to 500 students, without repeating google_id",
+	ErrTemaInvalido:           "invalid tema (claro, escuro, sistema)",
+	ErrAnoPadraoInvalido:      "ano_padrao not found",
+	ErrTabelaColunas:          "unknown table in colunas (estudantes, anos)",
+	ErrColunasPreferencias:    "invalid colunas: snake_case names, no repeats, up to 30 per table",
+	ErrRelatorioGrupo:         "agrupar_por accepts ano, turma and status (no repeats)",
+	ErrRelatorioMetrica:       "metricas accepts quantidade, idade_media, idade_minima and idade_maxima",
+	ErrRelatorioStatus:        "filtros.status accepts ativo, transferido and formado",
+	ErrRelatorioIDs:           "filters with at most 500 ids",
+	ErrRelatorioCampoData:     "periodos[].campo accepts data_nascimento and atualizado_em",
+	ErrRelatorioData:          "periodos[].de/ate must be YYYY-MM-DD, with de <= ate",
+	ErrWebhookURLInvalida:     "invalid url (http or https with host)",
+	ErrWebhookURLInterna:      "url points to an internal address (localhost, private network or link-local)",
+	ErrWebhookEventosVazio:    "send at least one event",
+	ErrWebhookEventoInvalido:  "invalid event (estudante.created, estudante.deleted, ano.removed)",
+	ErrWebhookSegredoInvalido: "segredo must have at least 16 characters",
+	ErrComunicadoAssunto:      "assunto is required, with at most 200 characters",
+	ErrComunicadoCorpo:        "corpo is required, with at most 20000 characters",
+	ErrComunicadoCampo:        "unknown field (use {{nome}}, {{estudante}}, {{ano}}, {{turma}} or {{escola}})",
+	ErrComunicadoSelecao:      "send estudante_ids and/or turma_ids",
+	ErrComunicadoIDs:          "at most 500 ids per list",
+	ErrAvisoCanal:             "invalid canal (sms or whatsapp)",
+	ErrAvisoTexto:             "texto is required, with at most 320 characters",
+	ErrProvedorMensagem:       "invalid provedor (twilio or zenvia)",
+	ErrProvedorContaSID:       "conta_sid is required for Twilio",
+	ErrProvedorToken:          "token is required",
+	ErrProvedorRemetente:      "remetente is required",
+	ErrFinalidadeInvalida:     "invalid finalidade (email, sms, whatsapp or foto)",
+	ErrConsentimentoContato:   "responsavel_id is required for email, sms and whatsapp",
+	ErrObservacaoLonga:        "observacao must have at most 500 characters",
+}
+
+/// ============ Funções Públicas ============
+
+// Add registra uma violação; err é a sentinela correspondente (pode ser nil).
+func (e *ErrosValidacao) Add(field, rule string, err error) {
+	msg := ""
+	if err != nil {
+		msg = err.Error()
+	}
+	*e = append(*e, ErroCampo{Field: field, Rule: rule, Message: msg, err: err})
+}
+
+// Err devolve nil quando não há violações (use como retorno de Validate).
+func (e ErrosValidacao) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Error junta as mensagens na ordem em que foram registradas.
+func (e ErrosValidacao) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, c := range e {
+		msgs = append(msgs, c.Field+": "+c.Message)
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap expõe as sentinelas para errors.Is/errors.As.
+func (e ErrosValidacao) Unwrap() []error {
+	out := make([]error, 0, len(e))
+	for _, c := range e {
+		if c.err != nil {
+			out = append(out, c.err)
+		}
+	}
+	return out
+}
+
+// Traduzir devolve uma cópia com as mensagens no idioma (apierr.Traduzivel);
+// em pt-BR devolve e sem alterações.
+func (e ErrosValidacao) Traduzir(idioma string) any {
+	if idioma == i18n.PtBR || len(e) == 0 {
+		return e
+	}
+	out := make(ErrosValidacao, len(e))
+	for i, c := range e {
+		if msg, ok := mensagensEN[c.err]; ok && idioma == i18n.EN {
+			c.Message = msg
+		} else if t, ok := mensagensRegra[c.Rule]; ok {
+			c.Message = t.Em(idioma)
+		}
+		out[i] = c
+	}
+	return out
+}
+
+// ComoErrosValidacao extrai a lista de erros de campo de err (false se não for um).
+func ComoErrosValidacao(err error) (ErrosValidacao, bool) {
+	var ev ErrosValidacao
+	if errors.As(err, &ev) {
+		return ev, true
+	}
+	return nil, false
+}
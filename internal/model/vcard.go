@@ -0,0 +1,112 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/model/vcard.go
+/// Responsabilidade: Contatos de estudantes e responsáveis no formato vCard 3.0 (.vcf) para importar na agenda do celular.
+/// Dependências principais: fmt, io, strings.
+/// Pontos de atenção:
+/// - Um cartão por estudante e um por responsável ("Maria Souza (mãe de João Souza)"), com a turma em ORG.
+/// - Valores são escapados (\\, ",", ";", quebras de linha) e as linhas dobradas em 75 bytes (RFC 2426/6350).
+/// - Contatos sem telefone nem e-mail não geram cartão (não teriam utilidade na agenda).
+*/
+
+package model
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// ContatoVCard é um cartão do arquivo .vcf.
+type ContatoVCard struct {
+	Nome     string
+	Telefone string
+	Email    string
+	Org      string // turma/ano
+	Nota     string
+}
+
+/// ============ Configurações & Constantes ============
+
+// limite de bytes por linha antes da dobra (CRLF + espaço)
+const vcardLinhaMax = 75
+
+var rotulosParentesco = map[string]string{
+	"mae":               "mãe",
+	"pai":               "pai",
+	"avo":               "avó/avô",
+	"tio":               "tia/tio",
+	"irmao":             "irmã/irmão",
+	"responsavel_legal": "responsável legal",
+	"outro":             "responsável",
+}
+
+var escapeVCard = strings.NewReplacer(`\`, `\\`, ",", `\,`, ";", `\;`, "\r\n", `\n`, "\n", `\n`)
+
+/// ============ Funções Públicas ============
+
+// ContatoResponsavel monta o cartão do responsável, identificado pelo estudante.
+func ContatoResponsavel(r Responsavel, estudante, turma string) ContatoVCard {
+	rotulo, ok := rotulosParentesco[r.Parentesco]
+	if !ok {
+		rotulo = rotulosParentesco["outro"]
+	}
+	return ContatoVCard{
+		Nome:     fmt.Sprintf("%s (%s de %s)", r.Nome, rotulo, estudante),
+		Telefone: r.Telefone,
+		Email:    r.Email,
+		Org:      turma,
+		Nota:     "Responsável de " + estudante,
+	}
+}
+
+// EscreverVCards grava os cartões no formato vCard 3.0 (linhas terminadas em CRLF).
+func EscreverVCards(w io.Writer, contatos []ContatoVCard) error {
+	var b strings.Builder
+	for _, c := range contatos {
+		if strings.TrimSpace(c.Telefone) == "" && strings.TrimSpace(c.Email) == "" {
+			continue
+		}
+		linhaVCard(&b, "BEGIN:VCARD")
+		linhaVCard(&b, "VERSION:3.0")
+		linhaVCard(&b, "FN:"+escapeVCard.Replace(c.Nome))
+		linhaVCard(&b, "N:"+escapeVCard.Replace(c.Nome)+";;;;")
+		if c.Telefone != "" {
+			linhaVCard(&b, "TEL;TYPE=CELL:"+escapeVCard.Replace(c.Telefone))
+		}
+		if c.Email != "" {
+			linhaVCard(&b, "EMAIL;TYPE=INTERNET:"+escapeVCard.Replace(c.Email))
+		}
+		if c.Org != "" {
+			linhaVCard(&b, "ORG:"+escapeVCard.Replace(c.Org))
+		}
+		if c.Nota != "" {
+			linhaVCard(&b, "NOTE:"+escapeVCard.Replace(c.Nota))
+		}
+		linhaVCard(&b, "END:VCARD")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+/// ============ Funções Internas (helpers) ============
+
+// linhaVCard escreve a linha dobrando-a em vcardLinhaMax bytes sem partir caracteres UTF-8.
+func linhaVCard(b *strings.Builder, linha string) {
+	limite := vcardLinhaMax
+	for len(linha) > limite {
+		corte := limite
+		for corte > 0 && !utf8.RuneStart(linha[corte]) {
+			corte--
+		}
+		b.WriteString(linha[:corte])
+		b.WriteString("\r\n ")
+		linha = linha[corte:]
+		limite = vcardLinhaMax - 1 // o espaço da continuação conta
+	}
+	b.WriteString(linha)
+	b.WriteString("\r\n")
+}
@@ -0,0 +1,131 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/model/vinculo_conta.go
+/// Responsabilidade: Vinculação de métodos de login da conta: definir senha numa conta só-Google e vincular o Google a uma conta com senha.
+/// Dependências principais: context, database/sql, errors, strings.
+/// Pontos de atenção:
+/// - Conta criada pelo login Google tem senha_hash = '' (ver user_repo.go); DefinirSenha só vale para ela, trocar senha continua em PUT /api/perfil.
+/// - As duas operações exigem prova além do X-User-Email: um ID Token Google novo (e, para vincular, a senha atual). A conferência do token e da senha fica no handler.
+/// - google_sub é único: vincular uma conta Google já usada por outro usuário devolve o erro de unicidade do banco (o handler responde 409 via mapPQError).
+*/
+
+package model
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// DefinirSenhaRequest é o payload de POST /api/usuario/definir-senha.
+type DefinirSenhaRequest struct {
+	Senha   string `json:"senha"`
+	IDToken string `json:"idToken"` // ID Token Google da conta vinculada (prova de posse)
+}
+
+// VincularGoogleRequest é o payload de POST /api/usuario/vincular-google.
+type VincularGoogleRequest struct {
+	Senha   string `json:"senha"`   // senha atual da conta
+	IDToken string `json:"idToken"` // ID Token da conta Google a vincular
+}
+
+/// ============ Configurações & Constantes ============
+
+var (
+	ErrSenhaObrigatoria   = errors.New("senha é obrigatória")
+	ErrIDTokenObrigatorio = errors.New("idToken é obrigatório")
+	ErrSenhaJaDefinida    = errors.New("a conta já tem senha; para trocá-la, use PUT /api/perfil")
+	ErrContaSemSenha      = errors.New("a conta não tem senha; entre com o Google ou defina uma senha")
+	ErrGoogleOutraConta   = errors.New("o token é de uma conta Google diferente da vinculada")
+	ErrGoogleJaVinculado  = errors.New("a conta já está vinculada a outra conta Google")
+	ErrGoogleNaoVinculado = errors.New("a conta não está vinculada ao Google")
+)
+
+/// ============ Funções Públicas ============
+
+// Sanitize remove espaços das pontas do token.
+func (r *DefinirSenhaRequest) Sanitize() { r.IDToken = strings.TrimSpace(r.IDToken) }
+
+// Validate exige o token e aplica à senha as regras do cadastro (MinPasswordLenCadastro, sem espaços).
+func (r DefinirSenhaRequest) Validate() error {
+	var ev ErrosValidacao
+	validarSenha(&ev, r.Senha, MinPasswordLenCadastro)
+	if r.IDToken == "" {
+		ev.Add("idToken", RegraObrigatorio, ErrIDTokenObrigatorio)
+	}
+	return ev.Err()
+}
+
+// Sanitize remove espaços das pontas do token.
+func (r *VincularGoogleRequest) Sanitize() { r.IDToken = strings.TrimSpace(r.IDToken) }
+
+// Validate exige senha e token (a senha é conferida contra a gravada no handler).
+func (r VincularGoogleRequest) Validate() error {
+	var ev ErrosValidacao
+	if r.Senha == "" {
+		ev.Add("senha", RegraObrigatorio, ErrSenhaObrigatoria)
+	}
+	if r.IDToken == "" {
+		ev.Add("idToken", RegraObrigatorio, ErrIDTokenObrigatorio)
+	}
+	return ev.Err()
+}
+
+// DefinirSenha grava senhaHash na conta usuarioID, que precisa estar sem senha e
+// vinculada ao Google com googleSub. ErrUsuarioNaoEncontrado, ErrSenhaJaDefinida,
+// ErrGoogleNaoVinculado ou ErrGoogleOutraConta conforme o estado da conta.
+func DefinirSenha(ctx context.Context, db *sql.DB, usuarioID int, googleSub, senhaHash string) error {
+	return ComTransacao(ctx, db, func(tx *sql.Tx) error {
+		var atual, sub string
+		err := tx.QueryRowContext(ctx,
+			`SELECT senha_hash, COALESCE(google_sub, '') FROM usuarios WHERE id = $1 FOR UPDATE`, usuarioID,
+		).Scan(&atual, &sub)
+		switch {
+		case err == sql.ErrNoRows:
+			return ErrUsuarioNaoEncontrado
+		case err != nil:
+			return err
+		case atual != "":
+			return ErrSenhaJaDefinida
+		case sub == "":
+			return ErrGoogleNaoVinculado
+		case sub != googleSub:
+			return ErrGoogleOutraConta
+		}
+		_, err = tx.ExecContext(ctx, `UPDATE usuarios SET senha_hash = $2 WHERE id = $1`, usuarioID, senhaHash)
+		return err
+	})
+}
+
+// VincularGoogle grava googleSub na conta usuarioID (e a foto do Google, se a conta
+// não tiver uma). Vincular de novo a mesma conta Google não faz nada.
+// ErrUsuarioNaoEncontrado ou ErrGoogleJaVinculado; conta Google já usada por outro
+// usuário devolve o erro de unicidade do banco (usuarios_google_sub_*).
+func VincularGoogle(ctx context.Context, db *sql.DB, usuarioID int, googleSub, foto string) error {
+	return ComTransacao(ctx, db, func(tx *sql.Tx) error {
+		var sub string
+		err := tx.QueryRowContext(ctx,
+			`SELECT COALESCE(google_sub, '') FROM usuarios WHERE id = $1 FOR UPDATE`, usuarioID,
+		).Scan(&sub)
+		switch {
+		case err == sql.ErrNoRows:
+			return ErrUsuarioNaoEncontrado
+		case err != nil:
+			return err
+		case sub == googleSub:
+			return nil
+		case sub != "":
+			return ErrGoogleJaVinculado
+		}
+		_, err = tx.ExecContext(ctx, `
+			UPDATE usuarios
+			   SET google_sub = $2,
+			       foto_url   = COALESCE(NULLIF(foto_url, ''), NULLIF($3, ''))
+			 WHERE id = $1
+		`, usuarioID, googleSub, foto)
+		return err
+	})
+}
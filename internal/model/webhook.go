@@ -0,0 +1,153 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/model/webhook.go
+/// Responsabilidade: Entidades de webhooks (URL cadastrada e entrega), eventos suportados e validação do payload de cadastro.
+/// Dependências principais: errors, net/url, strings, time.
+/// Pontos de atenção:
+/// - Só http/https são aceitos; em produção prefira https (o segredo assina, mas não cifra, o corpo).
+/// - Destinos internos (loopback, redes privadas, link-local como 169.254.169.254, localhost) são recusados no cadastro
+///   quando o host é literal; nomes que resolvem para eles são barrados na conexão (jobs.Webhooks, EnderecoInterno).
+/// - Segredo vazio no cadastro é gerado pelo handler e devolvido apenas na criação.
+/// - Eventos desconhecidos são rejeitados; a lista vazia é inválida.
+*/
+
+package model
+
+import (
+	"errors"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// Webhook representa um registro da tabela `webhooks`.
+type Webhook struct {
+	ID       int       `json:"id"`
+	URL      string    `json:"url"`
+	Eventos  []string  `json:"eventos"`
+	Ativo    bool      `json:"ativo"`
+	CriadoEm time.Time `json:"criado_em"`
+	Segredo  string    `json:"segredo,omitempty"` // só na resposta de criação
+}
+
+// WebhookRequest é o payload de POST /api/webhooks.
+type WebhookRequest struct {
+	URL     string   `json:"url"`
+	Eventos []string `json:"eventos"`
+	Segredo string   `json:"segredo"` // opcional; vazio = gerado
+}
+
+// WebhookEntrega é uma linha do log de entregas (webhook_entregas).
+type WebhookEntrega struct {
+	ID                 int64      `json:"id"`
+	Evento             string     `json:"evento"`
+	Status             string     `json:"status"` // pendente | entregue | falhou
+	Tentativas         int        `json:"tentativas"`
+	UltimoStatusHTTP   int        `json:"ultimo_status_http,omitempty"`
+	UltimoErro         string     `json:"ultimo_erro,omitempty"`
+	CriadoEm           time.Time  `json:"criado_em"`
+	ProximaTentativaEm *time.Time `json:"proxima_tentativa_em,omitempty"` // só quando pendente
+	EntregueEm         *time.Time `json:"entregue_em,omitempty"`
+}
+
+/// ============ Configurações & Constantes ============
+
+// Eventos publicados para webhooks.
+const (
+	EventoEstudanteCriado   = "estudante.created"
+	EventoEstudanteRemovido = "estudante.deleted"
+	EventoAnoRemovido       = "ano.removed"
+)
+
+// EventosWebhook lista os eventos aceitos no cadastro.
+var EventosWebhook = []string{EventoEstudanteCriado, EventoEstudanteRemovido, EventoAnoRemovido}
+
+// Status de uma entrega.
+const (
+	EntregaPendente = "pendente"
+	EntregaEntregue = "entregue"
+	EntregaFalhou   = "falhou"
+)
+
+// tamanho mínimo de um segredo informado pelo usuário
+const webhookSegredoMinimo = 16
+
+var (
+	ErrWebhookURLInvalida     = errors.New("url inválida (http ou https com host)")
+	ErrWebhookURLInterna      = errors.New("url aponta para um endereço interno (localhost, rede privada ou link-local)")
+	ErrWebhookEventosVazio    = errors.New("informe ao menos um evento")
+	ErrWebhookEventoInvalido  = errors.New("evento inválido (estudante.created, estudante.deleted, ano.removed)")
+	ErrWebhookSegredoInvalido = errors.New("segredo deve ter ao menos 16 caracteres")
+)
+
+/// ============ Funções Públicas ============
+
+// Sanitize normaliza URL, eventos (minúsculos, sem repetição) e segredo.
+func (r *WebhookRequest) Sanitize() {
+	r.URL = strings.TrimSpace(r.URL)
+	r.Segredo = strings.TrimSpace(r.Segredo)
+	eventos := make([]string, 0, len(r.Eventos))
+	for _, e := range r.Eventos {
+		e = strings.ToLower(strings.TrimSpace(e))
+		if e != "" && !slices.Contains(eventos, e) {
+			eventos = append(eventos, e)
+		}
+	}
+	r.Eventos = eventos
+}
+
+// Validate exige URL http(s) absoluta e não interna, ao menos um evento conhecido
+// e segredo (se informado) com tamanho mínimo.
+func (r WebhookRequest) Validate() error {
+	var ev ErrosValidacao
+	u, err := url.Parse(r.URL)
+	switch {
+	case err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "":
+		ev.Add("url", RegraFormato, ErrWebhookURLInvalida)
+	case hostInterno(u.Hostname()):
+		ev.Add("url", RegraFormato, ErrWebhookURLInterna)
+	}
+	if len(r.Eventos) == 0 {
+		ev.Add("eventos", RegraObrigatorio, ErrWebhookEventosVazio)
+	}
+	for _, e := range r.Eventos {
+		if !slices.Contains(EventosWebhook, e) {
+			ev.Add("eventos", RegraFormato, ErrWebhookEventoInvalido)
+			break
+		}
+	}
+	if r.Segredo != "" && len(r.Segredo) < webhookSegredoMinimo {
+		ev.Add("segredo", RegraTamanhoMinimo, ErrWebhookSegredoInvalido)
+	}
+	return ev.Err()
+}
+
+// EnderecoInterno informa se ip não pode receber webhooks: loopback, redes privadas
+// (10/8, 172.16/12, 192.168/16, fc00::/7), link-local (169.254/16, fe80::/10, onde
+// ficam os metadados de nuvem), CGNAT (100.64/10), não especificado ou multicast.
+func EnderecoInterno(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	return !ip.IsValid() || ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() ||
+		ip.IsUnspecified() || faixaCGNAT.Contains(ip)
+}
+
+/// ============ Funções Internas (helpers) ============
+
+// faixa compartilhada de CGNAT (RFC 6598), fora de IsPrivate
+var faixaCGNAT = netip.MustParsePrefix("100.64.0.0/10")
+
+// hostInterno recusa localhost e IPs literais internos; os demais nomes só são
+// conferidos na conexão (o DNS pode mudar depois do cadastro).
+func hostInterno(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+	ip, err := netip.ParseAddr(host)
+	return err == nil && EnderecoInterno(ip)
+}
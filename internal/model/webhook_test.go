@@ -0,0 +1,56 @@
+package model
+
+import (
+	"errors"
+	"net/netip"
+	"testing"
+)
+
+func TestEnderecoInterno(t *testing.T) {
+	casos := map[string]bool{
+		"127.0.0.1":       true,
+		"10.1.2.3":        true,
+		"172.16.0.1":      true,
+		"192.168.0.10":    true,
+		"169.254.169.254": true, // metadados de nuvem
+		"100.64.0.1":      true, // CGNAT
+		"0.0.0.0":         true,
+		"::1":             true,
+		"fe80::1":         true,
+		"fd00::1":         true,
+		"::ffff:10.0.0.1": true, // IPv4 mapeado em IPv6
+		"224.0.0.1":       true,
+		"8.8.8.8":         false,
+		"172.32.0.1":      false,
+		"2001:4860::8888": false,
+	}
+	for ip, interno := range casos {
+		if got := EnderecoInterno(netip.MustParseAddr(ip)); got != interno {
+			t.Errorf("EnderecoInterno(%s) = %v, esperado %v", ip, got, interno)
+		}
+	}
+}
+
+func TestWebhookRequestURLInterna(t *testing.T) {
+	casos := map[string]error{
+		"https://hooks.escola.com/tecmise":         nil,
+		"http://203.0.113.7:8080/x":                nil,
+		"http://localhost:8080/x":                  ErrWebhookURLInterna,
+		"http://api.localhost/x":                   ErrWebhookURLInterna,
+		"http://127.0.0.1/x":                       ErrWebhookURLInterna,
+		"http://169.254.169.254/latest/meta-data/": ErrWebhookURLInterna,
+		"http://[::1]:9000/x":                      ErrWebhookURLInterna,
+		"http://10.0.0.5/x":                        ErrWebhookURLInterna,
+		"ftp://hooks.escola.com/x":                 ErrWebhookURLInvalida,
+	}
+	for u, esperado := range casos {
+		r := WebhookRequest{URL: u, Eventos: []string{EventoEstudanteCriado}}
+		err := r.Validate()
+		switch {
+		case esperado == nil && err != nil:
+			t.Errorf("%s: erro inesperado %v", u, err)
+		case esperado != nil && !errors.Is(err, esperado):
+			t.Errorf("%s: erro = %v, esperado %v", u, err, esperado)
+		}
+	}
+}
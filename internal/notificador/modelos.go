@@ -0,0 +1,215 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/notificador/modelos.go
+/// Responsabilidade: Modelos (assunto + corpo em text/template) das mensagens enviadas pelo sistema e os dados que cada um espera.
+/// Dependências principais: text/template, strings.
+/// Pontos de atenção:
+/// - Os modelos são compilados na inicialização do pacote: erro de sintaxe derruba o processo na subida, não no envio.
+/// - Cada modelo aceita apenas o seu tipo de dados; outro tipo é erro de programação (renderizar devolve erro).
+/// - Links (convite, redefinição de senha) chegam prontos do chamador, que conhece APP_URL.
+/// - Comunicado recebe assunto e texto escritos pelo usuário já mesclados: entram como dados, nunca como template.
+*/
+
+package notificador
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"text/template"
+	"time"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// Modelo identifica uma mensagem do sistema.
+type Modelo string
+
+// DadosBoasVindas alimenta o modelo BoasVindas.
+type DadosBoasVindas struct {
+	Nome   string
+	AppURL string
+}
+
+// DadosRedefinicaoSenha alimenta o modelo RedefinicaoSenha.
+type DadosRedefinicaoSenha struct {
+	Nome     string
+	Link     string
+	ExpiraEm time.Time
+}
+
+// DadosConvite alimenta o modelo Convite.
+type DadosConvite struct {
+	Organizacao string
+	Papel       string
+	Link        string
+	ExpiraEm    time.Time
+}
+
+// DadosImportacao alimenta o modelo ImportacaoConcluida.
+type DadosImportacao struct {
+	Nome       string
+	Origem     string // ex.: "Google Classroom", "planilha CSV"
+	Importados int
+	Ignorados  int
+	Erros      []string // mensagens por linha/registro (as primeiras são listadas)
+}
+
+// DadosResumoAniversarios alimenta o modelo ResumoAniversarios.
+type DadosResumoAniversarios struct {
+	Nome            string
+	Inicio          time.Time // primeiro dia da semana coberta
+	Fim             time.Time // último dia (inclusive)
+	Aniversariantes []AniversarianteResumo
+	AppURL          string
+}
+
+// DadosComunicado alimenta o modelo Comunicado (texto já mesclado pelo chamador).
+type DadosComunicado struct {
+	Escola  string // quem envia (organização ou nome da conta)
+	Assunto string
+	Texto   string
+}
+
+// AniversarianteResumo é uma linha do resumo de aniversários.
+type AniversarianteResumo struct {
+	Nome  string
+	Data  time.Time
+	Idade int
+}
+
+// modelo é o par de templates de um Modelo e o tipo de dados aceito.
+type modelo struct {
+	assunto *template.Template
+	corpo   *template.Template
+	dados   reflect.Type
+}
+
+/// ============ Configurações & Constantes ============
+
+// Modelos disponíveis.
+const (
+	BoasVindas          Modelo = "boas_vindas"
+	RedefinicaoSenha    Modelo = "redefinicao_senha"
+	Convite             Modelo = "convite"
+	ImportacaoConcluida Modelo = "importacao_concluida"
+	ResumoAniversarios  Modelo = "resumo_aniversarios"
+	Comunicado          Modelo = "comunicado"
+)
+
+// quantidade máxima de erros listados no e-mail de importação
+const importacaoMaxErros = 10
+
+var funcoes = template.FuncMap{
+	"data": func(t time.Time) string { return t.Local().Format("02/01/2006 15:04") },
+	"primeiros": func(n int, xs []string) []string {
+		if len(xs) > n {
+			return xs[:n]
+		}
+		return xs
+	},
+	"maxErros": func() int { return importacaoMaxErros },
+	"diaMes":   func(t time.Time) string { return t.Format("02/01") },
+	"dia":      func(t time.Time) string { return t.Format("02/01") + " (" + diasSemana[t.Weekday()] + ")" },
+}
+
+var diasSemana = [...]string{"dom", "seg", "ter", "qua", "qui", "sex", "sáb"}
+
+var modelos = map[Modelo]modelo{
+	BoasVindas: compilar(DadosBoasVindas{},
+		`Bem-vindo(a) ao Tecmise`,
+		`Olá, {{.Nome}}!
+
+Sua conta no Tecmise foi criada. Acesse {{.AppURL}} para cadastrar seus anos,
+turmas e estudantes.
+
+Se não foi você quem criou esta conta, ignore este e-mail.
+`),
+	RedefinicaoSenha: compilar(DadosRedefinicaoSenha{},
+		`Redefinição de senha do Tecmise`,
+		`Olá, {{.Nome}}!
+
+Recebemos um pedido para redefinir a senha da sua conta. Para escolher uma
+nova senha, acesse:
+{{.Link}}
+
+O link expira em {{data .ExpiraEm}}. Se você não pediu a redefinição, ignore
+este e-mail: sua senha continua a mesma.
+`),
+	Convite: compilar(DadosConvite{},
+		`Convite para {{.Organizacao}} no Tecmise`,
+		`Você foi convidado(a) para a organização "{{.Organizacao}}" no Tecmise como {{.Papel}}.
+
+Para aceitar, entre com este e-mail e acesse:
+{{.Link}}
+
+O convite expira em {{data .ExpiraEm}}.
+`),
+	ImportacaoConcluida: compilar(DadosImportacao{},
+		`Importação concluída: {{.Importados}} estudante(s)`,
+		`Olá, {{.Nome}}!
+
+A importação de {{.Origem}} terminou.
+
+Importados: {{.Importados}}
+Ignorados: {{.Ignorados}}
+{{- if .Erros}}
+
+Problemas encontrados:
+{{- range primeiros maxErros .Erros}}
+- {{.}}
+{{- end}}
+{{- if gt (len .Erros) maxErros}}
+({{len .Erros}} problemas no total)
+{{- end}}
+{{- end}}
+`),
+	ResumoAniversarios: compilar(DadosResumoAniversarios{},
+		`Aniversariantes da semana ({{diaMes .Inicio}} a {{diaMes .Fim}})`,
+		`Olá, {{.Nome}}!
+
+Estudantes que fazem aniversário entre {{diaMes .Inicio}} e {{diaMes .Fim}}:
+{{range .Aniversariantes}}
+- {{dia .Data}}: {{.Nome}} ({{.Idade}} anos)
+{{- end}}
+
+A lista completa está em {{.AppURL}}.
+`),
+	Comunicado: compilar(DadosComunicado{},
+		`{{.Assunto}}`,
+		`{{.Texto}}
+
+--
+Mensagem enviada por {{.Escola}} pelo Tecmise.
+`),
+}
+
+/// ============ Funções Internas (helpers) ============
+
+// compilar monta um modelo; panic em template inválido (detectado na subida).
+func compilar(dados any, assunto, corpo string) modelo {
+	return modelo{
+		assunto: template.Must(template.New("assunto").Funcs(funcoes).Parse(assunto)),
+		corpo:   template.Must(template.New("corpo").Funcs(funcoes).Parse(corpo)),
+		dados:   reflect.TypeOf(dados),
+	}
+}
+
+// renderizar aplica o modelo aos dados e devolve assunto (em uma linha) e corpo.
+func renderizar(m Modelo, dados any) (assunto, corpo string, err error) {
+	md, ok := modelos[m]
+	if !ok {
+		return "", "", fmt.Errorf("modelo de e-mail desconhecido: %q", m)
+	}
+	if reflect.TypeOf(dados) != md.dados {
+		return "", "", fmt.Errorf("modelo %q espera %s (recebido %T)", m, md.dados, dados)
+	}
+	var a, c strings.Builder
+	if err := md.assunto.Execute(&a, dados); err != nil {
+		return "", "", fmt.Errorf("modelo %q (assunto): %w", m, err)
+	}
+	if err := md.corpo.Execute(&c, dados); err != nil {
+		return "", "", fmt.Errorf("modelo %q (corpo): %w", m, err)
+	}
+	return strings.Join(strings.Fields(a.String()), " "), c.String(), nil
+}
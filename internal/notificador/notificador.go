@@ -0,0 +1,126 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/notificador/notificador.go
+/// Responsabilidade: Abstração de envio de e-mails com provedores intercambiáveis (log, SMTP, SendGrid, Amazon SES) e mensagens a partir dos modelos de modelos.go.
+/// Dependências principais: context, backend/internal/config, backend/internal/logging; implementações em smtp.go, sendgrid.go e ses.go.
+/// Pontos de atenção:
+/// - O provedor é escolhido por EMAIL_DRIVER (lido em config.Carregar); "log" apenas registra a mensagem (desenvolvimento).
+/// - Enviar é síncrono (o chamador decide o que fazer com o erro); EnviarEmSegundoPlano serve aos e-mails que não podem atrasar nem falhar a requisição (boas-vindas, fim de importação).
+/// - Aguardar (desligamento do servidor) espera os envios em segundo plano ainda em curso.
+/// - Mensagens são texto puro (UTF-8); o assunto é codificado por cada provedor.
+*/
+
+package notificador
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"backend/internal/config"
+	"backend/internal/logging"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// Mensagem é um e-mail pronto para envio.
+type Mensagem struct {
+	De      string
+	Para    string
+	Assunto string
+	Texto   string
+}
+
+// Provedor entrega uma mensagem pronta (um por driver).
+type Provedor interface {
+	Enviar(ctx context.Context, m Mensagem) error
+}
+
+// Notificador monta mensagens a partir dos modelos e as entrega pelo provedor configurado.
+type Notificador struct {
+	provedor  Provedor
+	driver    string
+	remetente string
+	pendentes sync.WaitGroup // envios em segundo plano
+}
+
+// provedorLog apenas registra a mensagem (EMAIL_DRIVER=log).
+type provedorLog struct{}
+
+/// ============ Configurações & Constantes ============
+
+// tempo máximo de um envio em segundo plano
+const envioSegundoPlanoTimeout = 30 * time.Second
+
+/// ============ Inicialização/Bootstrap ============
+
+// Novo instancia o provedor escolhido em cfg.Driver ("log", "smtp", "sendgrid" ou "ses").
+// Os valores vêm de config.Carregar (EMAIL_*, SMTP_*, SENDGRID_*, SES_*).
+func Novo(cfg config.Email) (*Notificador, error) {
+	var p Provedor
+	switch cfg.Driver {
+	case "log":
+		p = provedorLog{}
+	case "smtp":
+		p = NovoSMTP(cfg.SMTP)
+	case "sendgrid":
+		p = NovoSendGrid(cfg.SendGrid)
+	case "ses":
+		p = NovoSES(cfg.SES)
+	default:
+		return nil, fmt.Errorf("EMAIL_DRIVER desconhecido: %q", cfg.Driver)
+	}
+	return &Notificador{provedor: p, driver: cfg.Driver, remetente: cfg.From}, nil
+}
+
+/// ============ Funções Públicas ============
+
+// Driver informa o provedor em uso (para logs de inicialização).
+func (n *Notificador) Driver() string { return n.driver }
+
+// Enviar monta a mensagem do modelo com dados e a entrega para o endereço para.
+// dados deve ser o tipo do modelo (DadosBoasVindas, DadosConvite...).
+func (n *Notificador) Enviar(ctx context.Context, para string, modelo Modelo, dados any) error {
+	assunto, texto, err := renderizar(modelo, dados)
+	if err != nil {
+		return err
+	}
+	return n.provedor.Enviar(ctx, Mensagem{De: n.remetente, Para: para, Assunto: assunto, Texto: texto})
+}
+
+// EnviarEmSegundoPlano envia sem bloquear o chamador; falhas vão apenas para o log.
+// O contexto da requisição é usado só para os valores (request_id), não para o cancelamento.
+func (n *Notificador) EnviarEmSegundoPlano(ctx context.Context, para string, modelo Modelo, dados any) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), envioSegundoPlanoTimeout)
+	n.pendentes.Add(1)
+	go func() {
+		defer n.pendentes.Done()
+		defer cancel()
+		if err := n.Enviar(ctx, para, modelo, dados); err != nil {
+			logging.De(ctx).Error("email: falha no envio", "modelo", string(modelo), "driver", n.driver, "erro", err)
+		}
+	}()
+}
+
+// Aguardar espera os envios em segundo plano terminarem ou ctx vencer.
+func (n *Notificador) Aguardar(ctx context.Context) error {
+	feito := make(chan struct{})
+	go func() {
+		n.pendentes.Wait()
+		close(feito)
+	}()
+	select {
+	case <-feito:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Enviar registra a mensagem no log em vez de enviá-la.
+func (provedorLog) Enviar(ctx context.Context, m Mensagem) error {
+	logging.De(ctx).Info("email: EMAIL_DRIVER=log; mensagem apenas registrada",
+		"para", m.Para, "assunto", m.Assunto, "corpo", m.Texto)
+	return nil
+}
@@ -0,0 +1,89 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/notificador/sendgrid.go
+/// Responsabilidade: Provedor de e-mail via API v3 do SendGrid (POST /v3/mail/send).
+/// Dependências principais: net/http, encoding/json.
+/// Pontos de atenção:
+/// - Sucesso = 202 Accepted; o SendGrid entrega de forma assíncrona (falhas posteriores só aparecem no painel dele).
+/// - O remetente (EMAIL_FROM) precisa estar verificado na conta SendGrid.
+*/
+
+package notificador
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"backend/internal/config"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// SendGrid envia mensagens pela API HTTP do SendGrid.
+type SendGrid struct {
+	apiKey   string
+	endpoint string
+	client   *http.Client
+}
+
+/// ============ Configurações & Constantes ============
+
+const sendGridEndpoint = "https://api.sendgrid.com/v3/mail/send"
+
+// timeout das chamadas HTTP aos provedores de API
+const provedorHTTPTimeout = 15 * time.Second
+
+/// ============ Inicialização/Bootstrap ============
+
+// NovoSendGrid cria o provedor SendGrid (SENDGRID_API_KEY).
+func NovoSendGrid(cfg config.SendGrid) *SendGrid {
+	return &SendGrid{
+		apiKey:   cfg.APIKey,
+		endpoint: sendGridEndpoint,
+		client:   &http.Client{Timeout: provedorHTTPTimeout},
+	}
+}
+
+/// ============ Funções Públicas ============
+
+// Enviar chama /v3/mail/send com a mensagem em texto puro.
+func (s *SendGrid) Enviar(ctx context.Context, m Mensagem) error {
+	corpo, err := json.Marshal(map[string]any{
+		"personalizations": []any{map[string]any{"to": []any{map[string]string{"email": m.Para}}}},
+		"from":             map[string]string{"email": m.De},
+		"subject":          m.Assunto,
+		"content":          []any{map[string]string{"type": "text/plain", "value": m.Texto}},
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(corpo))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+	return executarHTTP(s.client, req, "sendgrid")
+}
+
+/// ============ Funções Internas (helpers) ============
+
+// executarHTTP faz a chamada e transforma respostas fora de 2xx em erro (com o trecho inicial do corpo).
+func executarHTTP(client *http.Client, req *http.Request, provedor string) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", provedor, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	trecho, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return fmt.Errorf("%s: HTTP %d: %s", provedor, resp.StatusCode, bytes.TrimSpace(trecho))
+}
@@ -0,0 +1,80 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/notificador/ses.go
+/// Responsabilidade: Provedor de e-mail via Amazon SES v2 (POST /v2/email/outbound-emails), assinado com SigV4.
+/// Dependências principais: net/http, encoding/json, backend/internal/awsv4.
+/// Pontos de atenção:
+/// - Em sandbox, o SES só entrega para endereços verificados; o remetente (EMAIL_FROM) também precisa estar verificado.
+/// - SES_ENDPOINT permite apontar para emuladores (ex.: LocalStack).
+*/
+
+package notificador
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"backend/internal/awsv4"
+	"backend/internal/config"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// SES envia mensagens pela API v2 do Amazon SES.
+type SES struct {
+	creds    awsv4.Credentials
+	endpoint string
+	client   *http.Client
+}
+
+/// ============ Inicialização/Bootstrap ============
+
+// NovoSES cria o provedor SES (SES_REGION, SES_ACCESS_KEY, SES_SECRET_KEY, SES_ENDPOINT).
+func NovoSES(cfg config.SES) *SES {
+	endpoint := strings.TrimRight(cfg.Endpoint, "/")
+	if endpoint == "" {
+		endpoint = "https://email." + cfg.Region + ".amazonaws.com"
+	}
+	return &SES{
+		creds: awsv4.Credentials{
+			AccessKey: cfg.AccessKey,
+			SecretKey: cfg.SecretKey,
+			Region:    cfg.Region,
+			Service:   "ses",
+		},
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: provedorHTTPTimeout},
+	}
+}
+
+/// ============ Funções Públicas ============
+
+// Enviar chama SendEmail (conteúdo "Simple") com assunto e corpo em UTF-8.
+func (s *SES) Enviar(ctx context.Context, m Mensagem) error {
+	corpo, err := json.Marshal(map[string]any{
+		"FromEmailAddress": m.De,
+		"Destination":      map[string]any{"ToAddresses": []string{m.Para}},
+		"Content": map[string]any{
+			"Simple": map[string]any{
+				"Subject": map[string]string{"Data": m.Assunto, "Charset": "UTF-8"},
+				"Body": map[string]any{
+					"Text": map[string]string{"Data": m.Texto, "Charset": "UTF-8"},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint+"/v2/email/outbound-emails", bytes.NewReader(corpo))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	awsv4.SignRequest(req, awsv4.HashHex(corpo), s.creds, time.Now())
+	return executarHTTP(s.client, req, "ses")
+}
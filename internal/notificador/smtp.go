@@ -0,0 +1,53 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/notificador/smtp.go
+/// Responsabilidade: Provedor de e-mail via SMTP (net/smtp) com autenticação PLAIN opcional.
+/// Dependências principais: net/smtp, mime.
+/// Pontos de atenção:
+/// - net/smtp usa STARTTLS quando o servidor anuncia; PLAIN só é aceito em conexão cifrada ou localhost.
+/// - smtp.SendMail não aceita context: o cancelamento só é percebido antes de conectar.
+*/
+
+package notificador
+
+import (
+	"context"
+	"mime"
+	"net/smtp"
+	"time"
+
+	"backend/internal/config"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// SMTP envia mensagens por um servidor SMTP.
+type SMTP struct {
+	cfg config.SMTP
+}
+
+/// ============ Inicialização/Bootstrap ============
+
+// NovoSMTP cria o provedor SMTP (SMTP_HOST, SMTP_PORT, SMTP_USER, SMTP_PASS).
+func NovoSMTP(cfg config.SMTP) *SMTP { return &SMTP{cfg: cfg} }
+
+/// ============ Funções Públicas ============
+
+// Enviar monta a mensagem MIME (texto UTF-8) e a entrega ao servidor.
+func (s *SMTP) Enviar(ctx context.Context, m Mensagem) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	var auth smtp.Auth
+	if s.cfg.User != "" {
+		auth = smtp.PlainAuth("", s.cfg.User, s.cfg.Pass, s.cfg.Host)
+	}
+	msg := "From: " + m.De + "\r\n" +
+		"To: " + m.Para + "\r\n" +
+		"Subject: " + mime.QEncoding.Encode("utf-8", m.Assunto) + "\r\n" +
+		"Date: " + time.Now().Format(time.RFC1123Z) + "\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: text/plain; charset=UTF-8\r\n\r\n" +
+		m.Texto
+	return smtp.SendMail(s.cfg.Host+":"+s.cfg.Port, auth, m.De, []string{m.Para}, []byte(msg))
+}
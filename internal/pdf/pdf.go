@@ -0,0 +1,264 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/pdf/pdf.go
+/// Responsabilidade: Escrita de PDFs simples (texto, retângulos e fotos JPEG) usada pela carteirinha e pela lista de turma impressa.
+/// Dependências principais: image, image/draw, image/jpeg, bytes, fmt.
+/// Pontos de atenção:
+/// - PDF 1.4 escrito à mão, sem compressão de conteúdo; fontes padrão Helvetica/Helvetica-Bold em WinAnsiEncoding: caracteres fora do Latin-1 viram "?".
+/// - Todas as páginas compartilham um único dicionário de recursos (fontes e imagens): uma foto registrada uma vez pode ser desenhada em qualquer página.
+/// - Fotos entram como JPEG (DCTDecode) já recortado/reduzido por FotoJPEG; o PDF não reamostra nada.
+/// - A medida de texto usa as larguras da Helvetica-Bold (teto para a regular); letras acentuadas contam como a largura média.
+*/
+
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"strings"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// Documento acumula páginas do mesmo tamanho e as imagens usadas por elas.
+type Documento struct {
+	largura, altura float64
+	paginas         []*Pagina
+	imagens         []imagemJPEG
+}
+
+// Pagina recebe os operadores de desenho de uma página.
+type Pagina struct {
+	conteudo strings.Builder
+}
+
+type imagemJPEG struct {
+	dados           []byte
+	largura, altura int
+}
+
+/// ============ Configurações & Constantes ============
+
+// Fontes disponíveis em toda página.
+const (
+	FonteRegular = "F1" // Helvetica
+	FonteNegrito = "F2" // Helvetica-Bold
+)
+
+// Tamanho A4 em pontos (1/72").
+const (
+	A4Largura = 595.28
+	A4Altura  = 841.89
+)
+
+// qualidade das fotos embutidas
+const qualidadeJPEG = 85
+
+// larguras da Helvetica-Bold (AFM, milésimos do corpo) para ASCII 32–126
+var larguraBold = [95]int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+}
+
+/// ============ Inicialização/Bootstrap ============
+
+// Novo cria um documento vazio com páginas de largura × altura pontos.
+func Novo(largura, altura float64) *Documento {
+	return &Documento{largura: largura, altura: altura}
+}
+
+/// ============ Funções Públicas ============
+
+// NovaPagina acrescenta uma página em branco ao fim do documento.
+func (d *Documento) NovaPagina() *Pagina {
+	p := &Pagina{}
+	d.paginas = append(d.paginas, p)
+	return p
+}
+
+// ImagemJPEG registra uma foto (JPEG RGB de largura × altura px, como o de FotoJPEG)
+// e devolve o nome a usar em Pagina.Imagem.
+func (d *Documento) ImagemJPEG(dados []byte, largura, altura int) string {
+	d.imagens = append(d.imagens, imagemJPEG{dados: dados, largura: largura, altura: altura})
+	return fmt.Sprintf("Im%d", len(d.imagens))
+}
+
+// Comando escreve operadores PDF crus na página (ex.: "0.6 G 0.5 w x y w h re S").
+func (p *Pagina) Comando(formato string, args ...any) {
+	fmt.Fprintf(&p.conteudo, formato, args...)
+	p.conteudo.WriteByte('\n')
+}
+
+// Texto escreve uma linha na fonte/tamanho/cor (ex.: "0 g") indicados, com a base em (x, y).
+func (p *Pagina) Texto(fonte string, tam, x, y float64, cor, s string) {
+	p.Comando("BT %s /%s %.1f Tf %.2f %.2f Td (%s) Tj ET", cor, fonte, tam, x, y, escapar(s))
+}
+
+// Imagem desenha a imagem registrada nome no retângulo (x, y, w, h).
+func (p *Pagina) Imagem(nome string, x, y, w, h float64) {
+	p.Comando("q %.2f 0 0 %.2f %.2f %.2f cm /%s Do Q", w, h, x, y, nome)
+}
+
+// Bytes escreve os objetos, a tabela xref e o trailer. Documento sem páginas sai com uma em branco.
+func (d *Documento) Bytes() []byte {
+	if len(d.paginas) == 0 {
+		d.NovaPagina()
+	}
+	var b bytes.Buffer
+	var offsets []int
+	obj := func(corpo string, stream []byte) {
+		offsets = append(offsets, b.Len())
+		fmt.Fprintf(&b, "%d 0 obj\n%s", len(offsets), corpo)
+		if stream != nil {
+			b.WriteString("\nstream\n")
+			b.Write(stream)
+			b.WriteString("\nendstream")
+		}
+		b.WriteString("\nendobj\n")
+	}
+
+	// 1 catálogo, 2 árvore de páginas, 3–4 fontes, 5 recursos, imagens, e então (página, conteúdo) por página
+	const primeiraImagem = 6
+	primeiraPagina := primeiraImagem + len(d.imagens)
+	kids := make([]string, len(d.paginas))
+	for i := range d.paginas {
+		kids[i] = fmt.Sprintf("%d 0 R", primeiraPagina+2*i)
+	}
+	var xobjects strings.Builder
+	for i := range d.imagens {
+		fmt.Fprintf(&xobjects, " /Im%d %d 0 R", i+1, primeiraImagem+i)
+	}
+
+	b.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	obj("<< /Type /Catalog /Pages 2 0 R >>", nil)
+	obj(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.paginas)), nil)
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>", nil)
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>", nil)
+	obj(fmt.Sprintf("<< /Font << /F1 3 0 R /F2 4 0 R >> /XObject <<%s >> >>", xobjects.String()), nil)
+	for _, img := range d.imagens {
+		obj(fmt.Sprintf("<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB "+
+			"/BitsPerComponent 8 /Filter /DCTDecode /Length %d >>", img.largura, img.altura, len(img.dados)), img.dados)
+	}
+	for i, p := range d.paginas {
+		obj(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Contents %d 0 R /Resources 5 0 R >>",
+			d.largura, d.altura, primeiraPagina+2*i+1), nil)
+		conteudo := p.conteudo.String()
+		obj(fmt.Sprintf("<< /Length %d >>", len(conteudo)), []byte(conteudo))
+	}
+
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, o := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", o)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return b.Bytes()
+}
+
+// FotoJPEG corta img no centro na proporção largura:altura, reduz para largura × altura
+// px (vizinho mais próximo) e codifica em JPEG RGB, pronto para ImagemJPEG.
+func FotoJPEG(img image.Image, largura, altura int) ([]byte, error) {
+	r := img.Bounds()
+	w, h := r.Dx(), r.Dy()
+	if w*altura > h*largura { // larga demais: corta as laterais
+		nw := h * largura / altura
+		r.Min.X += (w - nw) / 2
+		r.Max.X = r.Min.X + nw
+	} else {
+		nh := w * altura / largura
+		r.Min.Y += (h - nh) / 2
+		r.Max.Y = r.Min.Y + nh
+	}
+	out := image.NewRGBA(image.Rect(0, 0, largura, altura))
+	draw.Draw(out, out.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	if r.Dx() > 0 && r.Dy() > 0 {
+		for y := 0; y < altura; y++ {
+			sy := r.Min.Y + y*r.Dy()/altura
+			for x := 0; x < largura; x++ {
+				out.Set(x, y, img.At(r.Min.X+x*r.Dx()/largura, sy))
+			}
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, out, &jpeg.Options{Quality: qualidadeJPEG}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// LarguraTexto mede s em pontos no corpo tam (Helvetica-Bold; serve de teto para a regular).
+func LarguraTexto(s string, tam float64) float64 {
+	total := 0
+	for _, r := range s {
+		switch {
+		case r >= 32 && r <= 126:
+			total += larguraBold[r-32]
+		default:
+			total += 611 // letras acentuadas ≈ largura média das minúsculas
+		}
+	}
+	return float64(total) * tam / 1000
+}
+
+// Truncar corta s (com "...") para caber em max pontos.
+func Truncar(s string, tam, max float64) string {
+	if LarguraTexto(s, tam) <= max {
+		return s
+	}
+	rs := []rune(s)
+	for len(rs) > 0 && LarguraTexto(string(rs)+"...", tam) > max {
+		rs = rs[:len(rs)-1]
+	}
+	return strings.TrimSpace(string(rs)) + "..."
+}
+
+// QuebrarLinhas distribui as palavras em até n linhas de max pontos (a última é truncada).
+func QuebrarLinhas(s string, tam, max float64, n int) []string {
+	var linhas []string
+	atual := ""
+	palavras := strings.Fields(s)
+	for i, p := range palavras {
+		cand := strings.TrimSpace(atual + " " + p)
+		if atual == "" || LarguraTexto(cand, tam) <= max {
+			atual = cand
+			continue
+		}
+		if len(linhas) == n-1 {
+			atual = strings.Join(append([]string{atual}, palavras[i:]...), " ")
+			break
+		}
+		linhas = append(linhas, atual)
+		atual = p
+	}
+	if atual != "" {
+		linhas = append(linhas, Truncar(atual, tam, max))
+	}
+	return linhas
+}
+
+/// ============ Funções Internas (helpers) ============
+
+// escapar converte para WinAnsi (Latin-1; demais caracteres viram "?") e escapa \, ( e ).
+func escapar(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteByte(byte(r))
+		case r >= 32 && r <= 126, r >= 0xA0 && r <= 0xFF:
+			b.WriteByte(byte(r))
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
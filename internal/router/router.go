@@ -0,0 +1,281 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/router/router.go
+/// Responsabilidade: Roteador HTTP sobre o http.ServeMux (padrões do Go 1.22: "GET /api/estudantes/{id}") com middlewares por rota e 404/405 consistentes.
+/// Dependências principais: net/http, sort, strings, backend/internal/apierr (corpo do 404/405).
+/// Pontos de atenção:
+/// - Parâmetros de caminho são lidos nos handlers com r.PathValue("id"); nada de TrimPrefix/Split manual.
+/// - Cada caminho é registrado uma única vez no ServeMux; o método é despachado aqui. Assim um método não
+///   registrado responde 405 (com Allow) em vez de cair na rota coringa "/".
+/// - OPTIONS (preflight CORS) e o 405 passam pelos middlewares do primeiro registro do caminho, para que o
+///   CORS responda antes. OPTIONS sem handler próprio responde 204 com Allow (o CORS, quando há, responde antes).
+/// - Todo 405 sai com Allow, inclusive o escrito pelo próprio handler (respostaAllow completa o cabeçalho).
+/// - Caminhos que se sobrepõem sem que um seja mais específico (ex.: /usuario/{id}/tutorial e /usuario/sessoes/{id},
+///   ambos casam /usuario/sessoes/tutorial) fazem o ServeMux entrar em panic. O segundo vai para um ServeMux à parte,
+///   consultado antes do principal: continua com 405/OPTIONS/HEAD próprios e, no caminho ambíguo, tem a preferência.
+///   Caminhos equivalentes (mesma forma, só muda o nome do parâmetro) continuam sendo erro de programação (panic).
+/// - Caminho que nenhum padrão casa responde 404 JSON (ENDPOINT_NAO_ENCONTRADO); main registra ainda a rota
+///   coringa "/" com os middlewares de API, e /healthz continua texto puro.
+/// - Padrão sem método ("/caminho") aceita qualquer método; HEAD usa o handler de GET quando não houver um próprio
+///   (o handler vê r.Method = GET, os middlewares veem HEAD; o net/http descarta o corpo e mantém os cabeçalhos).
+/// - Group declara prefixo e middlewares uma vez (ex.: rt.Group("/api", authMW...).Handle("GET /estudantes", h));
+///   With/Group derivam grupos com middlewares extras, aplicados depois dos herdados.
+*/
+
+package router
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"backend/internal/apierr"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// Middleware envolve um handler (mesma assinatura usada em cmd/server/main.go).
+type Middleware = func(http.Handler) http.Handler
+
+// Router agrupa as rotas por caminho e delega o casamento ao http.ServeMux.
+type Router struct {
+	mux         *http.ServeMux
+	sobrepostos *http.ServeMux // caminhos que conflitam com algum do mux (consultado primeiro)
+	rotas       map[string]*rota
+}
+
+// Group registra rotas no Router com um prefixo de caminho e middlewares em comum.
+type Group struct {
+	rt      *Router
+	prefixo string
+	mws     []Middleware
+}
+
+// rota guarda os handlers de um caminho, por método ("" = qualquer).
+type rota struct {
+	metodos map[string]http.Handler
+	head    http.Handler // handler de GET servindo HEAD (comoGET), quando há GET
+	mws     []Middleware
+}
+
+/// ============ Inicialização/Bootstrap ============
+
+// New cria um Router vazio.
+func New() *Router {
+	return &Router{mux: http.NewServeMux(), sobrepostos: http.NewServeMux(), rotas: make(map[string]*rota)}
+}
+
+/// ============ Configurações & Constantes ============
+
+// parâmetro de caminho ({id}, {resto...}), sem o nome, para comparar a forma dos caminhos
+var reParametro = regexp.MustCompile(`\{\w*(\.\.\.)?\}`)
+
+/// ============ Funções Públicas ============
+
+// Handle registra h em padrao ("MÉTODO /caminho/{param}" ou "/caminho") aplicando mws.
+// Registrar o mesmo método e caminho duas vezes é erro de programação (panic).
+func (rt *Router) Handle(padrao string, h http.Handler, mws ...Middleware) {
+	metodo, caminho, ok := strings.Cut(strings.TrimSpace(padrao), " ")
+	if !ok {
+		metodo, caminho = "", metodo
+	}
+	caminho = strings.TrimSpace(caminho)
+
+	ro, existe := rt.rotas[caminho]
+	if !existe {
+		ro = &rota{metodos: make(map[string]http.Handler), mws: mws}
+		rt.registrarCaminho(caminho, ro)
+		rt.rotas[caminho] = ro
+	}
+	if _, dup := ro.metodos[metodo]; dup {
+		panic(fmt.Sprintf("router: rota duplicada %q", padrao))
+	}
+	ro.metodos[metodo] = Apply(h, mws...)
+	if metodo == http.MethodGet {
+		ro.head = Apply(comoGET(h), mws...)
+	}
+}
+
+// HandleFunc é o atalho de Handle para funções.
+func (rt *Router) HandleFunc(padrao string, h http.HandlerFunc, mws ...Middleware) {
+	rt.Handle(padrao, h, mws...)
+}
+
+// Padroes lista as rotas registradas ("MÉTODO /caminho"; sem método, só "/caminho"), ordenadas.
+func (rt *Router) Padroes() []string {
+	out := []string{}
+	for caminho, ro := range rt.rotas {
+		for metodo := range ro.metodos {
+			out = append(out, strings.TrimSpace(metodo+" "+caminho))
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Group cria um grupo de rotas com prefixo (ex.: "/api"; "" = sem prefixo) e mws.
+func (rt *Router) Group(prefixo string, mws ...Middleware) *Group {
+	return &Group{rt: rt, prefixo: prefixo, mws: mws}
+}
+
+// Group deriva um subgrupo: prefixo acrescentado ao do grupo e mws depois dos herdados.
+func (g *Group) Group(prefixo string, mws ...Middleware) *Group {
+	return &Group{rt: g.rt, prefixo: g.prefixo + prefixo, mws: juntar(g.mws, mws)}
+}
+
+// With deriva um grupo com o mesmo prefixo e mws extras (aplicados depois dos herdados).
+func (g *Group) With(mws ...Middleware) *Group {
+	return g.Group("", mws...)
+}
+
+// Handle registra h em padrao ("MÉTODO /caminho", relativo ao prefixo) com os
+// middlewares do grupo seguidos de mws.
+func (g *Group) Handle(padrao string, h http.Handler, mws ...Middleware) {
+	metodo, caminho, ok := strings.Cut(strings.TrimSpace(padrao), " ")
+	if !ok {
+		metodo, caminho = "", metodo
+	}
+	g.rt.Handle(strings.TrimSpace(metodo+" "+g.prefixo+strings.TrimSpace(caminho)), h, juntar(g.mws, mws)...)
+}
+
+// HandleFunc é o atalho de Handle para funções.
+func (g *Group) HandleFunc(padrao string, h http.HandlerFunc, mws ...Middleware) {
+	g.Handle(padrao, h, mws...)
+}
+
+// ServeHTTP implementa http.Handler. O 404 do próprio ServeMux (nenhum padrão
+// casou, nem a rota coringa "/") sai em JSON como os demais erros.
+func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if _, padrao := rt.sobrepostos.Handler(r); padrao != "" {
+		rt.sobrepostos.ServeHTTP(w, r)
+		return
+	}
+	if _, padrao := rt.mux.Handler(r); padrao == "" {
+		apierr.Escrever(w, http.StatusNotFound, apierr.EndpointNaoEncontrado, "Endpoint não encontrado", nil)
+		return
+	}
+	rt.mux.ServeHTTP(w, r)
+}
+
+// Apply encadeia middlewares do último para o primeiro sobre h.
+func Apply(h http.Handler, mws ...Middleware) http.Handler {
+	for i := len(mws) - 1; i >= 0; i-- {
+		h = mws[i](h)
+	}
+	return h
+}
+
+/// ============ Funções Internas (helpers) ============
+
+// registrarCaminho põe o caminho no ServeMux principal ou, se ele se sobrepõe a um já
+// registrado sem que um seja mais específico, no ServeMux dos sobrepostos.
+func (rt *Router) registrarCaminho(caminho string, ro *rota) {
+	forma := reParametro.ReplaceAllString(caminho, "{$1}")
+	for existente := range rt.rotas {
+		if reParametro.ReplaceAllString(existente, "{$1}") == forma {
+			panic(fmt.Sprintf("router: caminho %q equivalente a %q", caminho, existente))
+		}
+	}
+	if !registrou(rt.mux, caminho, ro) {
+		rt.sobrepostos.Handle(caminho, ro)
+	}
+}
+
+// registrou tenta mux.Handle e devolve false se o padrão conflitar com outro já registrado.
+func registrou(mux *http.ServeMux, caminho string, h http.Handler) (ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			ok = false
+		}
+	}()
+	mux.Handle(caminho, h)
+	return true
+}
+
+// ServeHTTP despacha pelo método; sem handler, responde 405 (ou 204 em OPTIONS)
+// pelos middlewares da rota.
+func (ro *rota) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w = &respostaAllow{ResponseWriter: w, ro: ro}
+	if h, ok := ro.metodos[r.Method]; ok {
+		h.ServeHTTP(w, r)
+		return
+	}
+	if r.Method == http.MethodHead && ro.head != nil {
+		ro.head.ServeHTTP(w, r)
+		return
+	}
+	if h, ok := ro.metodos[""]; ok {
+		h.ServeHTTP(w, r)
+		return
+	}
+	if r.Method == http.MethodOptions {
+		w.Header().Set("Allow", ro.permitidos())
+		Apply(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}), ro.mws...).ServeHTTP(w, r)
+		return
+	}
+	Apply(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apierr.Escrever(w, http.StatusMethodNotAllowed, apierr.MetodoNaoPermitido, "Método não permitido", nil)
+	}), ro.mws...).ServeHTTP(w, r)
+}
+
+// comoGET entrega HEAD a h como GET: handlers que conferem r.Method continuam
+// respondendo, e o servidor descarta o corpo escrito (a requisição original é HEAD).
+func comoGET(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			r2 := *r
+			r2.Method = http.MethodGet
+			r = &r2
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// juntar devolve a concatenação de a e b sem compartilhar o array de a (grupos
+// derivados do mesmo pai não sobrescrevem os middlewares um do outro).
+func juntar(a, b []Middleware) []Middleware {
+	out := make([]Middleware, 0, len(a)+len(b))
+	return append(append(out, a...), b...)
+}
+
+// permitidos lista os métodos registrados (valor do cabeçalho Allow), com HEAD
+// quando há GET e sempre OPTIONS.
+func (ro *rota) permitidos() string {
+	ms := make([]string, 0, len(ro.metodos)+2)
+	for m := range ro.metodos {
+		if m != "" {
+			ms = append(ms, m)
+		}
+	}
+	if _, ok := ro.metodos[http.MethodGet]; ok {
+		if _, ok := ro.metodos[http.MethodHead]; !ok {
+			ms = append(ms, http.MethodHead)
+		}
+	}
+	if _, ok := ro.metodos[http.MethodOptions]; !ok {
+		ms = append(ms, http.MethodOptions)
+	}
+	sort.Strings(ms)
+	return strings.Join(ms, ", ")
+}
+
+// respostaAllow completa o cabeçalho Allow de qualquer 405 da rota que saia sem ele
+// (handlers que ainda validam o método por conta própria).
+type respostaAllow struct {
+	http.ResponseWriter
+	ro *rota
+}
+
+func (rw *respostaAllow) WriteHeader(status int) {
+	if status == http.StatusMethodNotAllowed && rw.Header().Get("Allow") == "" {
+		rw.Header().Set("Allow", rw.ro.permitidos())
+	}
+	rw.ResponseWriter.WriteHeader(status)
+}
+
+// Unwrap permite que http.ResponseController alcance o writer original (Flush, deadlines).
+func (rw *respostaAllow) Unwrap() http.ResponseWriter { return rw.ResponseWriter }
@@ -0,0 +1,65 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// responde escreve o nome da rota, para conferir qual handler atendeu.
+func responde(nome string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(nome + ":" + r.PathValue("id")))
+	})
+}
+
+// /usuario/{id}/tutorial e /usuario/sessoes/{id} se sobrepõem em /usuario/sessoes/tutorial
+// sem que um seja mais específico: os dois caminhos continuam atendendo.
+func TestCaminhosSobrepostos(t *testing.T) {
+	rt := New()
+	api := rt.Group("/api")
+	api.Handle("PUT /usuario/{id}/tutorial", responde("tutorial"))
+	api.Handle("GET /usuario/sessoes", responde("sessoes"))
+	api.Handle("DELETE /usuario/sessoes/{id}", responde("sessao"))
+
+	casos := []struct {
+		metodo, caminho string
+		status          int
+		corpo, allow    string
+	}{
+		{http.MethodPut, "/api/usuario/5/tutorial", http.StatusOK, "tutorial:5", ""},
+		{http.MethodDelete, "/api/usuario/sessoes/3", http.StatusOK, "sessao:3", ""},
+		{http.MethodGet, "/api/usuario/sessoes", http.StatusOK, "sessoes:", ""},
+		{http.MethodGet, "/api/usuario/5/tutorial", http.StatusMethodNotAllowed, "", "OPTIONS, PUT"},
+		{http.MethodPut, "/api/usuario/sessoes/3", http.StatusMethodNotAllowed, "", "DELETE, OPTIONS"},
+		{http.MethodOptions, "/api/usuario/sessoes/3", http.StatusNoContent, "", "DELETE, OPTIONS"},
+		{http.MethodGet, "/api/usuario/5/outra", http.StatusNotFound, "", ""},
+	}
+	for _, c := range casos {
+		t.Run(c.metodo+" "+c.caminho, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			rt.ServeHTTP(rec, httptest.NewRequest(c.metodo, c.caminho, nil))
+			if rec.Code != c.status {
+				t.Fatalf("status = %d, esperado %d (corpo = %s)", rec.Code, c.status, rec.Body)
+			}
+			if c.corpo != "" && rec.Body.String() != c.corpo {
+				t.Errorf("corpo = %q, esperado %q", rec.Body, c.corpo)
+			}
+			if got := rec.Header().Get("Allow"); got != c.allow {
+				t.Errorf("Allow = %q, esperado %q", got, c.allow)
+			}
+		})
+	}
+}
+
+// Mesma forma com outro nome de parâmetro não é sobreposição: é rota duplicada.
+func TestCaminhoEquivalentePanic(t *testing.T) {
+	rt := New()
+	rt.Handle("GET /estudantes/{id}", responde("a"))
+	defer func() {
+		if recover() == nil {
+			t.Fatal("caminho equivalente registrado sem panic")
+		}
+	}()
+	rt.Handle("PUT /estudantes/{estudanteID}", responde("b"))
+}
@@ -0,0 +1,204 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/storage/local.go
+/// Responsabilidade: Backend de armazenamento em disco local (diretório de uploads).
+/// Dependências principais: os, path/filepath, crypto/hmac, crypto/rand.
+/// Pontos de atenção:
+/// - Em containers, o diretório precisa estar em volume persistente para sobreviver a restarts.
+/// - Escrita atômica: grava em arquivo temporário e renomeia ao final.
+/// - Sem UPLOADS_SIGNING_KEY, uma chave aleatória é gerada no boot: as URLs assinadas continuam
+///   valendo, mas só até o próximo restart e só na instância que as gerou.
+*/
+
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// Local armazena arquivos em um diretório do sistema de arquivos.
+type Local struct {
+	dir        string // diretório raiz (ex.: ./uploads)
+	publicPath string // prefixo HTTP de leitura (ex.: /uploads/)
+	signingKey []byte // segredo HMAC para URLs assinadas (nunca vazio)
+}
+
+/// ============ Inicialização/Bootstrap ============
+
+// NewLocal cria o backend local garantindo a existência do diretório raiz.
+// signingKey vazio gera uma chave aleatória para este processo.
+func NewLocal(dir, publicPath, signingKey string) (*Local, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(publicPath, "/") {
+		publicPath += "/"
+	}
+	chave := []byte(signingKey)
+	if len(chave) == 0 {
+		chave = make([]byte, 32)
+		if _, err := rand.Read(chave); err != nil {
+			return nil, err
+		}
+		slog.Warn("UPLOADS_SIGNING_KEY não definida: URLs assinadas de /uploads valem só até o próximo restart")
+	}
+	return &Local{dir: dir, publicPath: publicPath, signingKey: chave}, nil
+}
+
+/// ============ Funções Públicas ============
+
+// Put grava o conteúdo de forma atômica (arquivo temporário + rename).
+func (l *Local) Put(_ context.Context, key string, r io.Reader, _ string) error {
+	p, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), p)
+}
+
+// Get abre o arquivo da chave; ErrNotFound se ausente.
+func (l *Local) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	p, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+// Delete remove o arquivo; ausência não é considerada erro.
+func (l *Local) Delete(_ context.Context, key string) error {
+	p, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// SignedURL devolve publicPath+key com exp/sig (HMAC-SHA256).
+func (l *Local) SignedURL(_ context.Context, key string, ttl time.Duration) (string, error) {
+	key, err := CleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	u := l.publicPath + key
+	exp := strconv.FormatInt(time.Now().Add(ttl).Unix(), 10)
+	q := url.Values{"exp": {exp}, "sig": {l.sign(key, exp)}}
+	return u + "?" + q.Encode(), nil
+}
+
+// VerifySignature confere exp/sig de uma URL gerada por SignedURL.
+// Retorna false quando expirada ou adulterada.
+func (l *Local) VerifySignature(key, exp, sig string) bool {
+	if exp == "" || sig == "" {
+		return false
+	}
+	ts, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil || time.Now().Unix() > ts {
+		return false
+	}
+	return hmac.Equal([]byte(sig), []byte(l.sign(key, exp)))
+}
+
+// List percorre o diretório raiz (ignorando temporários ".upload-*").
+func (l *Local) List(ctx context.Context, prefix string) ([]Object, error) {
+	var out []Object
+	err := filepath.WalkDir(l.dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".upload-") {
+			return nil
+		}
+		rel, err := filepath.Rel(l.dir, p)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		out = append(out, Object{Key: key, Size: info.Size(), ModTime: info.ModTime()})
+		return nil
+	})
+	return out, err
+}
+
+// Check confirma que o diretório de uploads aceita escrita (cria e remove um arquivo temporário).
+func (l *Local) Check(_ context.Context) error {
+	f, err := os.CreateTemp(l.dir, ".readyz-*")
+	if err != nil {
+		return err
+	}
+	name := f.Name()
+	_, err = f.Write([]byte("ok"))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if rerr := os.Remove(name); err == nil {
+		err = rerr
+	}
+	return err
+}
+
+/// ============ Funções Internas (helpers) ============
+
+// path converte a chave lógica em caminho no disco (sempre dentro de l.dir).
+func (l *Local) path(key string) (string, error) {
+	key, err := CleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(l.dir, filepath.FromSlash(key)), nil
+}
+
+func (l *Local) sign(key, exp string) string {
+	m := hmac.New(sha256.New, l.signingKey)
+	m.Write([]byte(key + "\n" + exp))
+	return hex.EncodeToString(m.Sum(nil))
+}
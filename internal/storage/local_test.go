@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Sem UPLOADS_SIGNING_KEY a URL continua assinada e aceita por /uploads (ServirUploadsHandler).
+func TestLocalSemChaveAssinaComChaveAleatoria(t *testing.T) {
+	l, err := NewLocal(t.TempDir(), "/uploads", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := l.SignedURL(context.Background(), "12/foto.jpg", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caminho, consulta, ok := strings.Cut(u, "?")
+	if !ok || caminho != "/uploads/12/foto.jpg" {
+		t.Fatalf("URL = %q, esperada assinada em /uploads/12/foto.jpg", u)
+	}
+	q, _ := url.ParseQuery(consulta)
+	if !l.VerifySignature("12/foto.jpg", q.Get("exp"), q.Get("sig")) {
+		t.Errorf("assinatura recusada: %q", u)
+	}
+	if l.VerifySignature("12/outra.jpg", q.Get("exp"), q.Get("sig")) {
+		t.Error("assinatura aceita para outra chave")
+	}
+
+	outro, err := NewLocal(t.TempDir(), "/uploads", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if outro.VerifySignature("12/foto.jpg", q.Get("exp"), q.Get("sig")) {
+		t.Error("chaves aleatórias de dois processos coincidem")
+	}
+}
+
+func TestLocalAssinaturaExpirada(t *testing.T) {
+	l, err := NewLocal(t.TempDir(), "/uploads/", "segredo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := l.SignedURL(context.Background(), "12/foto.jpg", -time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, consulta, _ := strings.Cut(u, "?")
+	q, _ := url.ParseQuery(consulta)
+	if l.VerifySignature("12/foto.jpg", q.Get("exp"), q.Get("sig")) {
+		t.Errorf("URL expirada aceita: %q", u)
+	}
+}
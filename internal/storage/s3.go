@@ -0,0 +1,254 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/storage/s3.go
+/// Responsabilidade: Backend de armazenamento S3-compatível (AWS S3, MinIO, R2...) via API REST assinada com SigV4.
+/// Dependências principais: net/http, backend/internal/awsv4.
+/// Pontos de atenção:
+/// - O corpo do Put é lido em memória (uploads são limitados no handler) para enviar Content-Length e hash real.
+/// - S3_FORCE_PATH_STYLE=true é necessário para MinIO e a maioria dos provedores self-hosted.
+/// - Sem S3_ENDPOINT, usa https://s3.<região>.amazonaws.com.
+*/
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"backend/internal/awsv4"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// S3Config agrupa os parâmetros de conexão com o bucket.
+type S3Config struct {
+	Endpoint       string
+	Region         string
+	Bucket         string
+	AccessKey      string
+	SecretKey      string
+	ForcePathStyle bool
+}
+
+// S3 implementa Storage sobre um bucket S3-compatível.
+type S3 struct {
+	cfg      S3Config
+	endpoint *url.URL
+	creds    awsv4.Credentials
+	client   *http.Client
+}
+
+/// ============ Inicialização/Bootstrap ============
+
+// NewS3 valida a configuração mínima e prepara o cliente HTTP.
+func NewS3(cfg S3Config) (*S3, error) {
+	if cfg.Bucket == "" || cfg.AccessKey == "" || cfg.SecretKey == "" {
+		return nil, errors.New("S3_BUCKET, S3_ACCESS_KEY e S3_SECRET_KEY são obrigatórios")
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = "https://s3." + cfg.Region + ".amazonaws.com"
+	}
+	ep, err := url.Parse(strings.TrimRight(cfg.Endpoint, "/"))
+	if err != nil || ep.Host == "" {
+		return nil, fmt.Errorf("S3_ENDPOINT inválido: %q", cfg.Endpoint)
+	}
+	return &S3{
+		cfg:      cfg,
+		endpoint: ep,
+		creds: awsv4.Credentials{
+			AccessKey: cfg.AccessKey,
+			SecretKey: cfg.SecretKey,
+			Region:    cfg.Region,
+			Service:   "s3",
+		},
+		client: &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+/// ============ Funções Públicas ============
+
+// Put envia o objeto com PUT Object.
+func (s *S3) Put(ctx context.Context, key string, r io.Reader, contentType string) error {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	req, err := s.newRequest(ctx, http.MethodPut, key, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = int64(len(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	awsv4.SignRequest(req, awsv4.HashHex(body), s.creds, time.Now())
+	return s.do(req, nil)
+}
+
+// Get baixa o objeto com GET Object; ErrNotFound em 404.
+func (s *S3) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := s.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	awsv4.SignRequest(req, awsv4.UnsignedPayload, s.creds, time.Now())
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+		return nil, s3Error(resp)
+	}
+	return resp.Body, nil
+}
+
+// Delete remove o objeto (S3 responde 204 mesmo se não existir).
+func (s *S3) Delete(ctx context.Context, key string) error {
+	req, err := s.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+	awsv4.SignRequest(req, awsv4.UnsignedPayload, s.creds, time.Now())
+	return s.do(req, nil)
+}
+
+// SignedURL gera uma URL GET pré-assinada direto no provedor.
+func (s *S3) SignedURL(_ context.Context, key string, ttl time.Duration) (string, error) {
+	u, err := s.objectURL(key)
+	if err != nil {
+		return "", err
+	}
+	return awsv4.PresignURL(http.MethodGet, u, s.creds, time.Now(), ttl), nil
+}
+
+// List usa ListObjectsV2 paginando por continuation-token.
+func (s *S3) List(ctx context.Context, prefix string) ([]Object, error) {
+	var (
+		out   []Object
+		token string
+	)
+	for {
+		u := *s.endpoint
+		if s.cfg.ForcePathStyle {
+			u.Path = "/" + s.cfg.Bucket + "/"
+		} else {
+			u.Host = s.cfg.Bucket + "." + u.Host
+			u.Path = "/"
+		}
+		q := url.Values{"list-type": {"2"}}
+		if prefix != "" {
+			q.Set("prefix", prefix)
+		}
+		if token != "" {
+			q.Set("continuation-token", token)
+		}
+		u.RawQuery = q.Encode()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		awsv4.SignRequest(req, awsv4.UnsignedPayload, s.creds, time.Now())
+
+		var buf bytes.Buffer
+		if err := s.do(req, &buf); err != nil {
+			return nil, err
+		}
+		var page struct {
+			Contents []struct {
+				Key          string    `xml:"Key"`
+				Size         int64     `xml:"Size"`
+				LastModified time.Time `xml:"LastModified"`
+			} `xml:"Contents"`
+			IsTruncated           bool   `xml:"IsTruncated"`
+			NextContinuationToken string `xml:"NextContinuationToken"`
+		}
+		if err := xml.Unmarshal(buf.Bytes(), &page); err != nil {
+			return nil, fmt.Errorf("s3: resposta de listagem inválida: %w", err)
+		}
+		for _, c := range page.Contents {
+			out = append(out, Object{Key: c.Key, Size: c.Size, ModTime: c.LastModified})
+		}
+		if !page.IsTruncated || page.NextContinuationToken == "" {
+			return out, nil
+		}
+		token = page.NextContinuationToken
+	}
+}
+
+// Check confirma que o bucket responde com as credenciais configuradas
+// (listagem de um prefixo inexistente, sem custo de transferência).
+func (s *S3) Check(ctx context.Context) error {
+	_, err := s.List(ctx, ".readyz/")
+	return err
+}
+
+/// ============ Funções Internas (helpers) ============
+
+// objectURL monta a URL do objeto (path-style ou virtual-hosted).
+func (s *S3) objectURL(key string) (*url.URL, error) {
+	key, err := CleanKey(key)
+	if err != nil {
+		return nil, err
+	}
+	segs := strings.Split(key, "/")
+	for i := range segs {
+		segs[i] = url.PathEscape(segs[i])
+	}
+	escaped := strings.Join(segs, "/")
+
+	u := *s.endpoint
+	if s.cfg.ForcePathStyle {
+		u.RawPath = "/" + url.PathEscape(s.cfg.Bucket) + "/" + escaped
+	} else {
+		u.Host = s.cfg.Bucket + "." + u.Host
+		u.RawPath = "/" + escaped
+	}
+	u.Path, _ = url.PathUnescape(u.RawPath)
+	return &u, nil
+}
+
+func (s *S3) newRequest(ctx context.Context, method, key string, body io.Reader) (*http.Request, error) {
+	u, err := s.objectURL(key)
+	if err != nil {
+		return nil, err
+	}
+	return http.NewRequestWithContext(ctx, method, u.String(), body)
+}
+
+// do executa a requisição e converte status não-2xx em erro.
+func (s *S3) do(req *http.Request, out io.Writer) error {
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return s3Error(resp)
+	}
+	if out != nil {
+		_, err = io.Copy(out, resp.Body)
+		return err
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// s3Error resume a resposta de erro do provedor (status + trecho do XML).
+func s3Error(resp *http.Response) error {
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return fmt.Errorf("s3: status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+}
@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"backend/internal/awsv4"
+)
+
+func TestS3SignedURL(t *testing.T) {
+	casos := []struct {
+		nome      string
+		cfg       S3Config
+		host, uri string
+	}{
+		{"virtual-hosted (AWS)", S3Config{Region: "sa-east-1", Bucket: "tecmise"},
+			"tecmise.s3.sa-east-1.amazonaws.com", "/12/foto%20nova.jpg"},
+		{"path-style (MinIO)", S3Config{Endpoint: "http://localhost:9000/", Region: "us-east-1", Bucket: "tecmise", ForcePathStyle: true},
+			"localhost:9000", "/tecmise/12/foto%20nova.jpg"},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			c.cfg.AccessKey, c.cfg.SecretKey = "AKIAEXEMPLO", "segredo"
+			s, err := NewS3(c.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			bruta, err := s.SignedURL(context.Background(), "/12/foto nova.jpg", 15*time.Minute)
+			if err != nil {
+				t.Fatal(err)
+			}
+			u, err := url.Parse(bruta)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if u.Host != c.host || u.EscapedPath() != c.uri {
+				t.Errorf("URL = %s, esperado host %s e caminho %s", bruta, c.host, c.uri)
+			}
+			q := u.Query()
+			if q.Get("X-Amz-Expires") != "900" || q.Get("X-Amz-SignedHeaders") != "host" {
+				t.Errorf("parâmetros = %v", q)
+			}
+			if want := "AKIAEXEMPLO/" + q.Get("X-Amz-Date")[:8] + "/" + c.cfg.Region + "/s3/aws4_request"; q.Get("X-Amz-Credential") != want {
+				t.Errorf("X-Amz-Credential = %q, esperado %q", q.Get("X-Amz-Credential"), want)
+			}
+
+			// mesma assinatura que o S3 calcularia para o instante de X-Amz-Date
+			quando, err := time.Parse("20060102T150405Z", q.Get("X-Amz-Date"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			objeto := *u
+			objeto.RawQuery = ""
+			creds := awsv4.Credentials{AccessKey: "AKIAEXEMPLO", SecretKey: "segredo", Region: c.cfg.Region, Service: "s3"}
+			if want := awsv4.PresignURL(http.MethodGet, &objeto, creds, quando, 15*time.Minute); bruta != want {
+				t.Errorf("SignedURL =\n  %s\nesperado\n  %s", bruta, want)
+			}
+		})
+	}
+}
+
+func TestS3SignedURLChaveInvalida(t *testing.T) {
+	s, err := NewS3(S3Config{Region: "us-east-1", Bucket: "b", AccessKey: "a", SecretKey: "s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.SignedURL(context.Background(), "../fora.jpg", time.Minute); err != ErrInvalidKey {
+		t.Errorf("erro = %v, esperado %v", err, ErrInvalidKey)
+	}
+}
+
+// Put assina o corpo (x-amz-content-sha256) e o Content-Type.
+func TestS3PutAssinaCorpo(t *testing.T) {
+	var recebido *http.Request
+	var corpo string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		recebido, corpo = r, string(b)
+	}))
+	defer srv.Close()
+
+	s, err := NewS3(S3Config{Endpoint: srv.URL, Region: "us-east-1", Bucket: "tecmise", AccessKey: "a", SecretKey: "s", ForcePathStyle: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put(context.Background(), "12/a.txt", strings.NewReader("olá"), "text/plain"); err != nil {
+		t.Fatal(err)
+	}
+	if recebido.URL.Path != "/tecmise/12/a.txt" || corpo != "olá" {
+		t.Fatalf("recebido %s %q", recebido.URL.Path, corpo)
+	}
+	if got := recebido.Header.Get("X-Amz-Content-Sha256"); got != awsv4.HashHex([]byte("olá")) {
+		t.Errorf("X-Amz-Content-Sha256 = %q", got)
+	}
+	auth := recebido.Header.Get("Authorization")
+	if !strings.Contains(auth, "SignedHeaders=content-type;host;x-amz-content-sha256;x-amz-date,") {
+		t.Errorf("Authorization = %q", auth)
+	}
+}
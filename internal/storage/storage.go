@@ -0,0 +1,116 @@
+/*
+/// Projeto: Tecmise
+/// Arquivo: backend/internal/storage/storage.go
+/// Responsabilidade: Abstração de armazenamento de arquivos enviados (fotos/uploads) com backends intercambiáveis (disco local ou S3-compatível).
+/// Dependências principais: context, io, backend/internal/config; implementações em local.go e s3.go.
+/// Pontos de atenção:
+/// - As chaves (keys) são caminhos relativos com "/" (ex.: "12/ab34cd.jpg"); ".." e caminhos absolutos são rejeitados.
+/// - O backend é escolhido por STORAGE_DRIVER ("local" padrão, ou "s3"), lido em config.Carregar.
+/// - SignedURL devolve uma URL temporária; no backend local ela aponta para /uploads com assinatura HMAC.
+/// - Fotos enviadas por /api/uploads têm uma miniatura na chave irmã ChaveMiniatura(key); só a chave principal vai para foto_url.
+*/
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"backend/internal/config"
+)
+
+/// ============ Tipos & Interfaces ============
+
+// Storage define o contrato mínimo para persistir e recuperar arquivos.
+type Storage interface {
+	// Put grava (ou sobrescreve) o conteúdo de r na chave informada.
+	Put(ctx context.Context, key string, r io.Reader, contentType string) error
+	// Get abre o conteúdo da chave; o chamador deve fechar o ReadCloser.
+	// Retorna ErrNotFound quando a chave não existe.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete remove a chave; remover chave inexistente não é erro.
+	Delete(ctx context.Context, key string) error
+	// SignedURL gera uma URL de acesso temporário (ttl) à chave.
+	SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error)
+	// List enumera os objetos cujo nome começa com prefix ("" = todos).
+	List(ctx context.Context, prefix string) ([]Object, error)
+}
+
+// Checker é implementado pelos backends que sabem verificar a própria saúde
+// (usado por /readyz): diretório gravável no local, bucket acessível no S3.
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// Object descreve um arquivo armazenado (retorno de List).
+type Object struct {
+	Key     string    // chave lógica ("12/ab34cd.jpg")
+	Size    int64     // tamanho em bytes
+	ModTime time.Time // última modificação
+}
+
+/// ============ Configurações & Constantes ============
+
+// sufixo do nome da miniatura (ver ChaveMiniatura)
+const sufixoMiniatura = "_mini"
+
+var (
+	ErrNotFound   = errors.New("arquivo não encontrado")
+	ErrInvalidKey = errors.New("chave de arquivo inválida")
+)
+
+/// ============ Inicialização/Bootstrap ============
+
+// New instancia o backend escolhido em cfg.Driver ("local" ou "s3").
+// Os valores vêm de config.Carregar (STORAGE_DRIVER, UPLOADS_*, S3_*).
+func New(cfg config.Storage) (Storage, error) {
+	switch cfg.Driver {
+	case "local":
+		return NewLocal(cfg.UploadsDir, cfg.UploadsPublicPath, cfg.SigningKey)
+	case "s3":
+		return NewS3(S3Config{
+			Endpoint:       cfg.S3.Endpoint,
+			Region:         cfg.S3.Region,
+			Bucket:         cfg.S3.Bucket,
+			AccessKey:      cfg.S3.AccessKey,
+			SecretKey:      cfg.S3.SecretKey,
+			ForcePathStyle: cfg.S3.ForcePathStyle,
+		})
+	default:
+		return nil, fmt.Errorf("STORAGE_DRIVER desconhecido: %q", cfg.Driver)
+	}
+}
+
+/// ============ Funções Públicas ============
+
+// CleanKey normaliza e valida uma chave de arquivo.
+// Regras: remove "/" das bordas, rejeita vazio, ".." e caracteres de controle.
+func CleanKey(key string) (string, error) {
+	key = strings.Trim(strings.TrimSpace(key), "/")
+	if key == "" {
+		return "", ErrInvalidKey
+	}
+	for _, seg := range strings.Split(key, "/") {
+		if seg == "" || seg == "." || seg == ".." {
+			return "", ErrInvalidKey
+		}
+	}
+	for _, r := range key {
+		if r < 0x20 || r == '\\' {
+			return "", ErrInvalidKey
+		}
+	}
+	return path.Clean(key), nil
+}
+
+// ChaveMiniatura devolve a chave da miniatura gravada ao lado de key
+// ("12/ab34cd.jpg" → "12/ab34cd_mini.jpg"), convenção do POST /api/uploads.
+func ChaveMiniatura(key string) string {
+	ext := path.Ext(key)
+	return strings.TrimSuffix(key, ext) + sufixoMiniatura + ext
+}